@@ -515,6 +515,47 @@ func TestE2E_HelpAndVersion(t *testing.T) {
 	})
 }
 
+// TestE2E_GlobalLogFlags tests the global flags that control logging.
+func TestE2E_GlobalLogFlags(t *testing.T) {
+	t.Run("log-file with verbose", func(t *testing.T) {
+		h := testutil.NewE2EHarness(t)
+		defer h.Cleanup()
+
+		logFile := filepath.Join(t.TempDir(), "custom.log")
+		result := h.Run("--log-file", logFile, "--verbose", "list")
+		result.AssertSuccess(t)
+		result.AssertStderrContains(t, "[INFO]")
+
+		data, err := os.ReadFile(logFile)
+		if err != nil {
+			t.Fatalf("log file not written: %v", err)
+		}
+		if !strings.Contains(string(data), "gren started") {
+			t.Errorf("log file = %q, want startup line", data)
+		}
+	})
+
+	t.Run("quiet only logs errors", func(t *testing.T) {
+		h := testutil.NewE2EHarness(t)
+		defer h.Cleanup()
+
+		logFile := filepath.Join(t.TempDir(), "quiet.log")
+		h.Run("--quiet", "--log-file", logFile, "list").AssertSuccess(t)
+
+		data, _ := os.ReadFile(logFile)
+		if strings.Contains(string(data), "[INFO]") || strings.Contains(string(data), "[DEBUG]") {
+			t.Errorf("--quiet log = %q, want errors only", data)
+		}
+	})
+
+	t.Run("quiet and verbose conflict", func(t *testing.T) {
+		h := testutil.NewE2EHarness(t)
+		defer h.Cleanup()
+
+		h.Run("--quiet", "--verbose", "list").AssertFailed(t)
+	})
+}
+
 // TestE2E_ShellInit tests shell initialization scripts.
 func TestE2E_ShellInit(t *testing.T) {
 	shells := []string{"bash", "zsh", "fish"}
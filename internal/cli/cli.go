@@ -1,22 +1,31 @@
 package cli
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"errors"
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 	"sync"
+	"text/tabwriter"
+	"text/template"
 	"time"
 
 	"github.com/langtind/gren/internal/config"
 	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/directive"
 	"github.com/langtind/gren/internal/git"
+	"github.com/langtind/gren/internal/launcher"
 	"github.com/langtind/gren/internal/logging"
 	"github.com/langtind/gren/internal/output"
 	"golang.org/x/term"
@@ -90,6 +99,23 @@ func NewCLI(gitRepo git.Repository, configManager *config.Manager) *CLI {
 	}
 }
 
+// warnConfigProblems prints the warnings from loading the project config,
+// such as misspelled keys, to stderr so they never mix with a command's
+// output. Load errors are left to the command itself.
+func (c *CLI) warnConfigProblems() {
+	if c.configManager == nil {
+		return
+	}
+	cfg, err := c.configManager.Load()
+	if err != nil || len(cfg.Warnings) == 0 {
+		return
+	}
+	defer output.SetStdout(os.Stderr)()
+	for _, warning := range cfg.Warnings {
+		output.Warning(warning)
+	}
+}
+
 // ParseAndExecute parses command line arguments and executes the appropriate command
 func (c *CLI) ParseAndExecute(args []string) error {
 	if len(args) < 2 {
@@ -100,6 +126,13 @@ func (c *CLI) ParseAndExecute(args []string) error {
 	command := args[1]
 	logging.Info("CLI command: %s, args: %v", command, args[2:])
 
+	switch command {
+	case "__complete", "__directive", "completion", "shell-init", "statusline", "help":
+		// Run from shell startup, prompts and tab completion: keep quiet
+	default:
+		c.warnConfigProblems()
+	}
+
 	switch command {
 	case "create":
 		return c.handleCreate(args[2:])
@@ -109,6 +142,10 @@ func (c *CLI) ParseAndExecute(args []string) error {
 		return c.handleDelete(args[2:])
 	case "cleanup":
 		return c.handleCleanup(args[2:])
+	case "prune":
+		return c.handlePrune(args[2:])
+	case "repair":
+		return c.handleRepair(args[2:])
 	case "init":
 		return c.handleInit(args[2:])
 	case "navigate", "nav", "cd", "switch":
@@ -127,14 +164,32 @@ func (c *CLI) ParseAndExecute(args []string) error {
 		return c.handleMerge(args[2:])
 	case "for-each":
 		return c.handleForEach(args[2:])
+	case "exec":
+		return c.handleExec(args[2:])
 	case "diff":
 		return c.handleDiff(args[2:])
+	case "set-upstream":
+		return c.handleSetUpstream(args[2:])
+	case "push":
+		return c.handlePush(args[2:])
+	case "open":
+		return c.handleOpen(args[2:])
+	case "reattach":
+		return c.handleReattach(args[2:])
+	case "relocate":
+		return c.handleRelocate(args[2:])
+	case "info":
+		return c.handleInfo(args[2:])
+	case "doctor":
+		return c.handleDoctor(args[2:])
 	case "step":
 		return c.handleStep(args[2:])
 	case "completion":
 		return c.handleCompletion(args[2:])
 	case "__complete":
 		return c.handleCompletionQuery(args[2:])
+	case "__directive":
+		return c.handleDirectiveShell(args[2:])
 	case "config":
 		return c.handleConfig(args[2:])
 	case "hook-run":
@@ -164,13 +219,22 @@ func (c *CLI) handleCreate(args []string) error {
 	baseBranch := fs.String("b", "", "Base branch to create from (defaults to recommended base branch)")
 	existing := fs.Bool("existing", false, "Use existing branch instead of creating new one")
 	worktreeDir := fs.String("dir", "", "Directory to create worktrees in")
+	explicitPath := fs.String("path", "", "Exact path for the worktree, bypassing --dir and worktree_dir")
 	execute := fs.String("x", "", "Command to run after creating worktree (e.g., -x claude)")
+	open := fs.Bool("open", false, "Go to the new worktree without asking (a new terminal when shell integration is off)")
 	autoYes := fs.Bool("y", false, "Auto-approve hooks without prompting")
 	format := fs.String("format", "", "Output format: json (machine-readable, suppresses prompts)")
 	noHooks := fs.Bool("no-hooks", false, "Create the worktree without running pre/post-create hooks")
+	trackRemote := fs.Bool("track-remote", false, "Always create from origin/<branch>, even if the local branch is ahead (local unpushed commits are left out)")
+	remote := fs.String("remote", "", "Create from <remote>/<branch> and track it, e.g. upstream in a fork (default: origin)")
+	allMatching := fs.String("all-matching", "", "Create a worktree for every remote branch matching a glob (e.g. 'feature/*')")
+	dryRun := fs.Bool("dry-run", false, "With --all-matching: list the worktrees that would be created")
+	preset := fs.String("preset", "", "Apply a preset from the project config: its base branch, copy_files, symlink_files and post-create hook")
+	baseFromPR := fs.Int("base-from-pr", 0, "Base the new branch on the head branch of this PR/MR, for stacked PRs")
 
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), "Usage: gren create -n <name> [options]\n")
+		fmt.Fprintf(fs.Output(), "       gren create --all-matching <glob> [--dry-run] [-y]\n")
 		fmt.Fprintf(fs.Output(), "       gren create pr:<number>              # Check out a GitHub PR\n")
 		fmt.Fprintf(fs.Output(), "       gren create mr:<number>              # Check out a GitLab MR\n")
 		fmt.Fprintf(fs.Output(), "\nCreate a new git worktree\n\n")
@@ -182,11 +246,18 @@ func (c *CLI) handleCreate(args []string) error {
 		fmt.Fprintf(fs.Output(), "  gren create -n existing-feature --existing --branch feature-branch\n")
 		fmt.Fprintf(fs.Output(), "  gren create pr:42                         # Check out PR #42 branch\n")
 		fmt.Fprintf(fs.Output(), "  gren create mr:101                        # Check out MR !101 branch\n")
+		fmt.Fprintf(fs.Output(), "  gren create -n feat-auth --open           # Create and go there\n")
 		fmt.Fprintf(fs.Output(), "  gren create -n feat-auth -x claude        # Create and start Claude\n")
 		fmt.Fprintf(fs.Output(), "  gren create -n feat-ui -x \"npm run dev\"   # Create and start dev server\n")
 		fmt.Fprintf(fs.Output(), "  gren create -n feat-api -y                # Auto-approve hooks\n")
 		fmt.Fprintf(fs.Output(), "  gren create -n feat-x --format=json -y    # Machine-readable, no prompts\n")
 		fmt.Fprintf(fs.Output(), "  gren create -n feat-x --no-hooks -y       # Create, skip hooks (run setup yourself)\n")
+		fmt.Fprintf(fs.Output(), "  gren create -n feat-x --track-remote      # Start from origin/feat-x (e.g. after a force-push)\n")
+		fmt.Fprintf(fs.Output(), "  gren create -n x --remote upstream --branch feature  # Track upstream/feature\n")
+		fmt.Fprintf(fs.Output(), "  gren create -n feat --path ../custom/location  # One-off placement\n")
+		fmt.Fprintf(fs.Output(), "  gren create -n feat-ui --preset frontend  # Apply the frontend preset\n")
+		fmt.Fprintf(fs.Output(), "  gren create -n followup --base-from-pr 123  # Branch off PR #123's branch\n")
+		fmt.Fprintf(fs.Output(), "  gren create --all-matching 'feature/*' --dry-run  # Preview bulk creation\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
@@ -205,6 +276,22 @@ func (c *CLI) handleCreate(args []string) error {
 	if jsonMode && *execute != "" {
 		return fmt.Errorf("--format=json and -x are mutually exclusive: -x writes a shell directive (interactive only)")
 	}
+	if *open && (jsonMode || *execute != "") {
+		return fmt.Errorf("--open cannot be combined with --format=json or -x (-x already goes to the worktree)")
+	}
+
+	if *allMatching != "" {
+		if *name != "" || *branch != "" || *execute != "" || *open || *remote != "" || *explicitPath != "" || *preset != "" || *baseFromPR != 0 || jsonMode {
+			return fmt.Errorf("--all-matching cannot be combined with -n, --branch, --remote, --path, --preset, --base-from-pr, -x, --open or --format")
+		}
+		return c.createAllMatching(*allMatching, *worktreeDir, *dryRun, *noHooks, *trackRemote, *autoYes)
+	}
+	if *dryRun {
+		return fmt.Errorf("--dry-run is only supported with --all-matching")
+	}
+	if *explicitPath != "" && *worktreeDir != "" {
+		return fmt.Errorf("--path and --dir are mutually exclusive: --path is the full worktree path")
+	}
 
 	// Support positional pr:/mr: syntax: gren create pr:42
 	if *name == "" && len(fs.Args()) == 1 && git.IsPRRef(fs.Args()[0]) {
@@ -236,8 +323,39 @@ func (c *CLI) handleCreate(args []string) error {
 		logging.Info("CLI create: resolved %s → branch=%s name=%s", prRef, *branch, *name)
 	}
 
-	// If no base branch specified for CLI, default to current branch
+	// --base-from-pr stands in for -b with the PR's head branch, so a
+	// follow-up branch stacks on top of it
 	effectiveBaseBranch := *baseBranch
+	if *baseFromPR != 0 {
+		switch {
+		case *baseFromPR < 0:
+			return fmt.Errorf("invalid --base-from-pr %d: must be a positive PR number", *baseFromPR)
+		case *baseBranch != "":
+			return fmt.Errorf("--base-from-pr and -b are mutually exclusive")
+		case *existing:
+			return fmt.Errorf("--base-from-pr only applies to a new branch; it cannot be combined with --existing or pr:/mr:")
+		}
+		prBranch, err := c.resolvePRBranch(*baseFromPR, fmt.Sprintf("PR #%d", *baseFromPR))
+		if err != nil {
+			return err
+		}
+		effectiveBaseBranch = prBranch
+		logging.Info("CLI create: --base-from-pr %d → base=%s", *baseFromPR, prBranch)
+	}
+
+	// A preset's base branch stands in for -b; an unknown preset fails
+	// before anything runs, listing the configured ones
+	if *preset != "" {
+		p, err := c.configManager.ResolvePreset(*preset)
+		if err != nil {
+			return err
+		}
+		if effectiveBaseBranch == "" {
+			effectiveBaseBranch = p.BaseBranch
+		}
+	}
+
+	// If no base branch specified for CLI, default to current branch
 	if effectiveBaseBranch == "" && !*existing {
 		currentBranch, err := c.gitRepo.GetCurrentBranch(context.Background())
 		if err != nil {
@@ -248,15 +366,19 @@ func (c *CLI) handleCreate(args []string) error {
 		}
 	}
 
-	logging.Info("CLI create: name=%s, branch=%s, base=%s, existing=%v, dir=%s, execute=%s",
-		*name, *branch, effectiveBaseBranch, *existing, *worktreeDir, *execute)
+	logging.Info("CLI create: name=%s, branch=%s, base=%s, existing=%v, dir=%s, path=%s, execute=%s, track-remote=%v, remote=%s, preset=%s",
+		*name, *branch, effectiveBaseBranch, *existing, *worktreeDir, *explicitPath, *execute, *trackRemote, *remote, *preset)
 
 	req := core.CreateWorktreeRequest{
-		Name:        *name,
-		Branch:      *branch,
-		BaseBranch:  effectiveBaseBranch,
-		IsNewBranch: !*existing,
-		WorktreeDir: *worktreeDir,
+		Name:         *name,
+		Branch:       *branch,
+		BaseBranch:   effectiveBaseBranch,
+		IsNewBranch:  !*existing,
+		WorktreeDir:  *worktreeDir,
+		PreferRemote: *trackRemote,
+		Remote:       *remote,
+		ExplicitPath: *explicitPath,
+		Preset:       *preset,
 	}
 
 	ctx := context.Background()
@@ -271,6 +393,7 @@ func (c *CLI) handleCreate(args []string) error {
 	}
 	var preCreateResults []core.HookResult
 	if !*noHooks {
+		c.offerHookChmod(config.HookPostCreate, *autoYes)
 		c.worktreeManager.SetEventObserver(streamEventsTo(os.Stderr))
 		preCreateResults = c.worktreeManager.RunPreCreateHookWithApproval(preBranchName, effectiveBaseBranch, *autoYes)
 		c.worktreeManager.SetEventObserver(nil)
@@ -292,7 +415,42 @@ func (c *CLI) handleCreate(args []string) error {
 		}
 	}
 
+	// Run post-create hook with approval checking, as part of the create so
+	// a failure lands in its warning. Stream events live to stderr so
+	// long-running hooks show phase progress instead of going silent until
+	// the batch summary at the end.
+	branchName := *branch
+	if branchName == "" {
+		branchName = *name
+	}
+	var postCreateResults []core.HookResult
+	if !*noHooks {
+		req.PostCreate = func(path string) []core.HookResult {
+			if abs, absErr := filepath.Abs(path); absErr == nil {
+				path = abs
+			}
+			c.worktreeManager.SetEventObserver(streamEventsTo(os.Stderr))
+			postCreateResults = c.worktreeManager.RunPostCreateHookWithPreset(path, branchName, effectiveBaseBranch, *preset, *autoYes)
+			c.worktreeManager.SetEventObserver(nil)
+			// In JSON mode the human-readable phase summary would corrupt
+			// stdout — hook results land in the JSON payload instead. Live
+			// stderr streaming above is still useful as a progress signal
+			// for log consumers.
+			if !jsonMode {
+				printHookEvents(postCreateResults)
+			}
+			return postCreateResults
+		}
+	}
+
+	// A failed post-create hook leaves the worktree only partly set up; the
+	// warning says so with the end of the hook's output, and hooks_required
+	// fails the create, keeping the worktree
 	worktreePath, warning, err := c.worktreeManager.CreateWorktree(ctx, req)
+	var hookErr error
+	if errors.Is(err, core.ErrHooksRequired) {
+		hookErr, err = err, nil
+	}
 	if err != nil {
 		logging.Error("CLI create failed: %v", err)
 		return err
@@ -323,26 +481,6 @@ func (c *CLI) handleCreate(args []string) error {
 	}
 	logging.Info("CLI create succeeded: %s at %s", *name, worktreePath)
 
-	// Run post-create hook with approval checking.
-	// Stream events live to stderr so long-running hooks show phase progress
-	// instead of going silent until the batch summary at the end.
-	branchName := *branch
-	if branchName == "" {
-		branchName = *name
-	}
-	var postCreateResults []core.HookResult
-	if !*noHooks {
-		c.worktreeManager.SetEventObserver(streamEventsTo(os.Stderr))
-		postCreateResults = c.worktreeManager.RunPostCreateHookWithApproval(worktreePath, branchName, effectiveBaseBranch, *autoYes)
-		c.worktreeManager.SetEventObserver(nil)
-		// In JSON mode the human-readable phase summary would corrupt stdout —
-		// hook results land in the JSON payload instead. Live stderr streaming
-		// above is still useful as a progress signal for log consumers.
-		if !jsonMode {
-			printHookEvents(postCreateResults)
-		}
-	}
-
 	// JSON mode: emit one machine-readable object on stdout and return.
 	// Suppresses both the human "Worktree created" banner and the navigate
 	// prompt — callers (CI, AI agents) get a parseable result they can
@@ -359,8 +497,16 @@ func (c *CLI) handleCreate(args []string) error {
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(out)
+		if err := enc.Encode(out); err != nil {
+			return err
+		}
+		return hookErr
 	}
+	if hookErr != nil {
+		return hookErr
+	}
+
+	summary := output.Summary{Verb: "created", Done: 1, Warnings: createWarningCount(warning, postCreateResults)}
 
 	// Handle execute flag (-x)
 	if *execute != "" {
@@ -375,10 +521,17 @@ func (c *CLI) handleCreate(args []string) error {
 		postStartResults := c.worktreeManager.RunPostStartHookWithApproval(worktreePath, branchName, *execute, *autoYes)
 		c.worktreeManager.SetEventObserver(nil)
 		printHookEvents(postStartResults)
-		// Don't print anything - shell wrapper will execute the command
+		// No banner, just the summary - shell wrapper will execute the command
+		summary.Warnings += failedHookCount(postStartResults)
+		output.PrintSummary(summary)
 	} else {
 		// Print success output when not executing a command
 		output.WorktreeCreated(*name, branchName, worktreePath)
+		output.PrintSummary(summary)
+
+		if *open {
+			return c.openCreatedWorktree(*name, worktreePath)
+		}
 
 		// Ask user if they want to navigate to the worktree, but only when
 		// stdin is a terminal. Non-interactive callers (CI, AI agents,
@@ -403,6 +556,182 @@ func (c *CLI) handleCreate(args []string) error {
 	return nil
 }
 
+// openCreatedWorktree takes the user to a worktree `gren create --open` just
+// made. With shell integration active that is a cd directive, as when
+// answering yes to "Navigate to worktree?"; without it nothing would read the
+// directive, so the configured terminal is opened there instead, as the TUI
+// does.
+func (c *CLI) openCreatedWorktree(name, worktreePath string) error {
+	if directive.IsShellIntegrationActive() {
+		logging.Info("CLI create: --open, writing navigation directive for %s", worktreePath)
+		if err := directive.WriteCD(worktreePath); err != nil {
+			return fmt.Errorf("worktree created but failed to set up navigation: %w", err)
+		}
+		return nil
+	}
+
+	cfg, _ := c.configManager.Load()
+	terminal, _ := launcher.Settings(cfg)
+	termName, termArgs := launcher.Terminal(terminal, worktreePath)
+	logging.Info("CLI create: --open without shell integration, opening a terminal in %s", worktreePath)
+	if err := exec.Command(termName, termArgs...).Start(); err != nil {
+		output.Hint("Shell integration not detected. Run:")
+		fmt.Printf("   eval \"$(gren shell-init zsh)\"  # or bash/fish\n")
+		return fmt.Errorf("worktree created but failed to open a terminal in it: %w", err)
+	}
+	output.Successf("Opened %s in a new terminal", output.Bold(name))
+	output.Hint("Set up shell integration (gren shell-init) to switch in place instead")
+	return nil
+}
+
+// createAllMatching creates a worktree for every origin branch matching
+// pattern that doesn't have one yet. Each branch goes through the same
+// pre-create hook, CreateWorktree and post-create hook sequence as a single
+// create; a failure is recorded and the rest still run.
+func (c *CLI) createAllMatching(pattern, worktreeDir string, dryRun, noHooks, trackRemote, autoYes bool) error {
+	ctx := context.Background()
+
+	// Fetch first so branches pushed since the last fetch are matched too.
+	c.worktreeManager.FetchOrigin()
+
+	branches, skipped, err := c.worktreeManager.MatchingRemoteBranches(ctx, pattern)
+	if err != nil {
+		return err
+	}
+	logging.Info("CLI create --all-matching: pattern=%s branches=%v skipped=%v dry-run=%v", pattern, branches, skipped, dryRun)
+
+	for _, b := range skipped {
+		fmt.Printf("  %s %s %s\n", output.Dim("-"), b, output.Dim("(already has a worktree)"))
+	}
+	if len(branches) == 0 {
+		fmt.Printf("No remote branches matching '%s' without a worktree\n", pattern)
+		return nil
+	}
+
+	if dryRun {
+		for _, b := range branches {
+			fmt.Printf("  %s %s\n", output.Dim("+"), b)
+		}
+		fmt.Printf("\nWould create %d worktree(s). Run without --dry-run to create them.\n", len(branches))
+		return nil
+	}
+
+	if !noHooks {
+		c.offerHookChmod(config.HookPostCreate, autoYes)
+	}
+
+	summary := output.Summary{Verb: "created", Skipped: len(skipped)}
+	for _, b := range branches {
+		_, warnings, err := c.createBranchWorktree(ctx, core.CreateWorktreeRequest{
+			Name:         b,
+			Branch:       b,
+			IsNewBranch:  false,
+			WorktreeDir:  worktreeDir,
+			PreferRemote: trackRemote,
+		}, noHooks, autoYes)
+		summary.Warnings += warnings
+		if err != nil {
+			logging.Error("CLI create --all-matching: %s failed: %v", b, err)
+			fmt.Printf("  ✗ Failed to create %s: %v\n", b, err)
+			summary.Failed++
+			continue
+		}
+		fmt.Printf("  ✓ Created %s\n", b)
+		summary.Done++
+	}
+
+	fmt.Println()
+	output.PrintSummary(summary)
+	if summary.Failed > 0 {
+		return fmt.Errorf("created %d of %d worktree(s); %d failed", summary.Done, len(branches), summary.Failed)
+	}
+	return nil
+}
+
+// offerHookChmod looks for hook scripts of hookType that lack the execute bit,
+// which is easily lost after a checkout or an edit, and offers to chmod +x
+// them before they fail. autoYes fixes them without asking; without a
+// terminal to ask on, it only warns. Windows has no execute bit, so there is
+// nothing to offer there.
+func (c *CLI) offerHookChmod(hookType config.HookType, autoYes bool) {
+	for _, script := range c.worktreeManager.NonExecutableHookScripts(hookType) {
+		if !autoYes {
+			if !term.IsTerminal(int(os.Stdin.Fd())) {
+				output.Warningf("%s hook %s is not executable and will fail; run: chmod +x %s", hookType, script, script)
+				continue
+			}
+			fmt.Fprintf(humanOut(), "%s hook %s is not executable. Make it executable (chmod +x)? [Y/n]: ", hookType, script)
+			var response string
+			fmt.Scanln(&response)
+			if r := strings.ToLower(strings.TrimSpace(response)); r != "" && r != "y" && r != "yes" {
+				logging.Info("CLI: user declined chmod +x of %s", script)
+				continue
+			}
+		}
+		if err := c.worktreeManager.MakeHookExecutable(script); err != nil {
+			output.Warningf("Could not make %s executable: %v", script, err)
+			continue
+		}
+		output.Successf("Made %s executable", script)
+	}
+}
+
+// createBranchWorktree runs the pre-create hook, CreateWorktree and the
+// post-create hook for req without the interactive extras of `gren create`
+// (no banner, no navigate prompt), for commands that create worktrees as
+// part of something else: --all-matching and `switch --create`. It returns
+// the absolute worktree path and how many warnings (a create warning, failed
+// post-create hooks) it printed.
+func (c *CLI) createBranchWorktree(ctx context.Context, req core.CreateWorktreeRequest, noHooks, autoYes bool) (string, int, error) {
+	branch := req.Branch
+	if !noHooks {
+		c.worktreeManager.SetEventObserver(streamEventsTo(os.Stderr))
+		results := c.worktreeManager.RunPreCreateHookWithApproval(branch, req.BaseBranch, autoYes)
+		c.worktreeManager.SetEventObserver(nil)
+		printHookEvents(results)
+		if core.HooksFailed(results) {
+			return "", 0, fmt.Errorf("pre-create hook failed; worktree not created")
+		}
+	}
+
+	var postCreateResults []core.HookResult
+	if !noHooks {
+		req.PostCreate = func(path string) []core.HookResult {
+			if abs, absErr := filepath.Abs(path); absErr == nil {
+				path = abs
+			}
+			c.worktreeManager.SetEventObserver(streamEventsTo(os.Stderr))
+			postCreateResults = c.worktreeManager.RunPostCreateHookWithApproval(path, branch, req.BaseBranch, autoYes)
+			c.worktreeManager.SetEventObserver(nil)
+			printHookEvents(postCreateResults)
+			return postCreateResults
+		}
+	}
+
+	worktreePath, warning, err := c.worktreeManager.CreateWorktree(ctx, req)
+	if err != nil && !errors.Is(err, core.ErrHooksRequired) {
+		return "", 0, err
+	}
+	if abs, absErr := filepath.Abs(worktreePath); absErr == nil {
+		worktreePath = abs
+	}
+	if warning != "" {
+		output.Warning(warning)
+	}
+	return worktreePath, createWarningCount(warning, postCreateResults), err
+}
+
+// createWarningCount counts the warnings of a create for its summary line:
+// each failed post-create hook in results, and warning when it says more
+// than the hook failures CreateWorktree added to it.
+func createWarningCount(warning string, results []core.HookResult) int {
+	count := failedHookCount(results)
+	if hookWarning, _ := core.PostCreateHookFailure(nil, results, ""); warning != "" && warning != hookWarning {
+		count++
+	}
+	return count
+}
+
 // CreateJSON is the machine-readable shape returned by `gren create --format=json`.
 // Hooks slice captures whether configured hooks ran, succeeded, and any error
 // detail — so callers don't have to parse stderr to know if setup worked.
@@ -462,25 +791,137 @@ type WorktreeJSON struct {
 	IsCurrent      bool   `json:"is_current"`
 	IsPrevious     bool   `json:"is_previous"`
 	IsMain         bool   `json:"is_main"`
+	IsBare         bool   `json:"is_bare"`
 	Status         string `json:"status"`
+	StatusError    string `json:"status_error,omitempty"` // Why git status failed, with Status "unknown"
 	LastCommit     string `json:"last_commit"`
 	StagedCount    int    `json:"staged_count"`
 	ModifiedCount  int    `json:"modified_count"`
 	UnpushedCount  int    `json:"unpushed_count"`
 	UntrackedCount int    `json:"untracked_count"`
+	Upstream       string `json:"upstream,omitempty"` // e.g. "origin/feat-x", omitted when none is configured
 	BranchStatus   string `json:"branch_status,omitempty"`
 	PRNumber       int    `json:"pr_number,omitempty"`
 	PRState        string `json:"pr_state,omitempty"`
 	PRURL          string `json:"pr_url,omitempty"`
 	CIStatus       string `json:"ci_status,omitempty"`
 	StaleReason    string `json:"stale_reason,omitempty"`
+	BranchMismatch bool   `json:"branch_mismatch,omitempty"`
+	BrokenLink     bool   `json:"broken_link,omitempty"`
+	NoRemote       bool   `json:"no_remote,omitempty"`  // The repository has no remote; unpushed_count is always 0
+	SizeBytes      *int64 `json:"size_bytes,omitempty"` // Only with --size
+	Created        string `json:"created,omitempty"`    // RFC 3339; the directory's mtime for worktrees gren didn't create
+}
+
+// listField is a column `gren list --fields` can show.
+type listField struct {
+	name  string
+	value func(wt core.WorktreeInfo) string
+}
+
+// listFields are the fields accepted by `gren list --fields`, in the order
+// `gren list --help` shows them.
+var listFields = []listField{
+	{"name", func(wt core.WorktreeInfo) string { return wt.Name }},
+	{"branch", func(wt core.WorktreeInfo) string { return wt.Branch }},
+	{"path", func(wt core.WorktreeInfo) string { return wt.Path }},
+	{"status", func(wt core.WorktreeInfo) string { return wt.Status }},
+	{"current", func(wt core.WorktreeInfo) string { return strconv.FormatBool(wt.IsCurrent) }},
+	{"main", func(wt core.WorktreeInfo) string { return strconv.FormatBool(wt.IsMain) }},
+	{"last_commit", func(wt core.WorktreeInfo) string { return wt.LastCommit }},
+	{"created", func(wt core.WorktreeInfo) string { return formatCreated(wt) }},
+	{"staged", func(wt core.WorktreeInfo) string { return strconv.Itoa(wt.StagedCount) }},
+	{"modified", func(wt core.WorktreeInfo) string { return strconv.Itoa(wt.ModifiedCount) }},
+	{"untracked", func(wt core.WorktreeInfo) string { return strconv.Itoa(wt.UntrackedCount) }},
+	{"unpushed", func(wt core.WorktreeInfo) string { return strconv.Itoa(wt.UnpushedCount) }},
+	{"upstream", func(wt core.WorktreeInfo) string { return wt.UpstreamName }},
+	{"stale", func(wt core.WorktreeInfo) string {
+		if wt.BranchStatus != "stale" {
+			return ""
+		}
+		return wt.StaleReason
+	}},
+	{"pr", func(wt core.WorktreeInfo) string {
+		if wt.PRNumber == 0 {
+			return ""
+		}
+		return fmt.Sprintf("#%d %s", wt.PRNumber, wt.PRState)
+	}},
+	{"ci", func(wt core.WorktreeInfo) string { return wt.CIStatus }},
+}
+
+// formatCreated renders when wt was created as a date, or "" when unknown
+// (the main worktree).
+func formatCreated(wt core.WorktreeInfo) string {
+	if wt.Created.IsZero() {
+		return ""
+	}
+	return wt.Created.Local().Format("2006-01-02")
+}
+
+// listFieldNames returns the names of listFields.
+func listFieldNames() []string {
+	names := make([]string, len(listFields))
+	for i, f := range listFields {
+		names[i] = f.name
+	}
+	return names
+}
+
+// parseListFields resolves a comma-separated --fields value against
+// listFields, rejecting unknown names.
+func parseListFields(spec string) ([]listField, error) {
+	var fields []listField
+	for _, name := range strings.Split(spec, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		idx := slices.IndexFunc(listFields, func(f listField) bool { return f.name == name })
+		if idx < 0 {
+			return nil, fmt.Errorf("unknown field %q; valid fields: %s", name, strings.Join(listFieldNames(), ", "))
+		}
+		fields = append(fields, listFields[idx])
+	}
+	if len(fields) == 0 {
+		return nil, fmt.Errorf("--fields needs at least one field; valid fields: %s", strings.Join(listFieldNames(), ", "))
+	}
+	return fields, nil
+}
+
+// printListFields prints one line per worktree with the selected fields in
+// aligned columns, without colors or a header so the output can be piped.
+// Empty values print as "-" to keep the columns countable.
+func printListFields(worktrees []core.WorktreeInfo, fields []listField) {
+	w := tabwriter.NewWriter(humanOut(), 0, 0, 2, ' ', 0)
+	for _, wt := range worktrees {
+		values := make([]string, len(fields))
+		for i, f := range fields {
+			if values[i] = f.value(wt); values[i] == "" {
+				values[i] = "-"
+			}
+		}
+		fmt.Fprintln(w, strings.Join(values, "\t"))
+	}
+	w.Flush()
 }
 
 // handleList handles the list command
 func (c *CLI) handleList(args []string) error {
 	fs := flag.NewFlagSet("list", flag.ExitOnError)
 	verbose := fs.Bool("v", false, "Show verbose output")
-	format := fs.String("format", "", "Output format: json")
+	format := fs.String("format", "", "Output format: json, or a Go template run for each worktree (e.g. '{{.Branch}}\\t{{.Status}}')")
+	fetch := fs.Bool("fetch", false, "Fetch from origin (with prune) first for up-to-date stale status")
+	fieldSpec := fs.String("fields", "", "Comma-separated fields to show: "+strings.Join(listFieldNames(), ","))
+	noCI := fs.Bool("no-ci", false, "Skip CI status lookups (one GitHub API call per PR)")
+	size := fs.Bool("size", false, "Show each worktree's disk usage, largest first (walks every worktree)")
+	sortSpec := fs.String("sort", "", "Sort order: recent, name, branch, status or stale (default: git's order)")
+	pinCurrent := fs.Bool("pin-current", false, "List the current worktree first")
+	filterSpec := fs.String("filter-status", "", "Only list worktrees with these comma-separated statuses: "+strings.Join(append(slices.Clone(core.WorktreeStatuses), core.BranchStatuses...), ","))
+	staleOnly := fs.Bool("stale", false, fmt.Sprintf("Only list stale worktrees, and exit with code %d if there are any", ExitCodeStale))
+	showCreated := fs.Bool("created", false, "Show when each worktree was created (its directory's mtime if gren didn't create it)")
+	pathsOnly := fs.Bool("paths-only", false, "Print only worktree paths, one per line, for piping into other tools")
+	nullSep := fs.Bool("0", false, "With --paths-only: end each path with a NUL byte instead of a newline, for xargs -0")
 
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), "Usage: gren list [options]\n")
@@ -490,8 +931,30 @@ func (c *CLI) handleList(args []string) error {
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
 		fmt.Fprintf(fs.Output(), "  gren list\n")
 		fmt.Fprintf(fs.Output(), "  gren list -v\n")
+		fmt.Fprintf(fs.Output(), "  gren list --fetch                # Refresh remote refs before checking stale status\n")
+		fmt.Fprintf(fs.Output(), "  gren list --fields=branch,status,pr,path\n")
+		fmt.Fprintf(fs.Output(), "  gren list --fields=path          # One path per line, for scripts\n")
+		fmt.Fprintf(fs.Output(), "  gren list --paths-only | fzf     # Just the paths, nothing else\n")
+		fmt.Fprintf(fs.Output(), "  gren list --paths-only -0 | xargs -0 du -sh\n")
+		fmt.Fprintf(fs.Output(), "  gren list -v --no-ci             # PR status without CI checks\n")
+		fmt.Fprintf(fs.Output(), "  gren list --size                 # Find the worktrees taking up the most disk\n")
+		fmt.Fprintf(fs.Output(), "  gren list --created              # When each worktree was created\n")
+		fmt.Fprintf(fs.Output(), "  gren list --sort=stale           # Stale branches first, ready for cleanup\n")
+		fmt.Fprintf(fs.Output(), "  gren list --sort=recent --pin-current\n")
+		fmt.Fprintf(fs.Output(), "  gren list --filter-status=modified,mixed\n")
+		fmt.Fprintf(fs.Output(), "  gren list --filter-status=stale --fields=path\n")
+		fmt.Fprintf(fs.Output(), "  gren list --stale --format=json  # In CI: fail when stale worktrees are left\n")
 		fmt.Fprintf(fs.Output(), "  gren list --format=json\n")
 		fmt.Fprintf(fs.Output(), "  gren list --format=json | jq '.[].branch'\n")
+		fmt.Fprintf(fs.Output(), "  gren list --format '{{.Branch}}\\t{{.Status}}\\t{{.PRState}}'\n")
+		fmt.Fprintf(fs.Output(), "  gren list --format '{{.Branch | sanitize}} {{.Path | shortpath}}'\n")
+		fmt.Fprintf(fs.Output(), "\nA --format template sees every field of a worktree: .Name, .Branch, .Path,\n")
+		fmt.Fprintf(fs.Output(), ".Status, .IsCurrent, .IsMain, .LastCommit, .StagedCount, .ModifiedCount,\n")
+		fmt.Fprintf(fs.Output(), ".UntrackedCount, .UnpushedCount, .BranchStatus, .StaleReason, .PRNumber,\n")
+		fmt.Fprintf(fs.Output(), ".PRState, .PRURL, .CIStatus and more. Besides Go's built-in functions it\n")
+		fmt.Fprintf(fs.Output(), "can call sanitize (/, :, # and spaces become -) and shortpath (home directory as ~).\n")
+		fmt.Fprintf(fs.Output(), "\nWith --stale, gren list exits 0 when no worktree is stale, %d when some\n", ExitCodeStale)
+		fmt.Fprintf(fs.Output(), "are (after listing them), and 1 when listing fails.\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
@@ -499,23 +962,82 @@ func (c *CLI) handleList(args []string) error {
 	}
 
 	var jsonMode bool
-	switch *format {
-	case "":
+	var tmpl *template.Template
+	switch {
+	case *format == "":
 		jsonMode = false
-	case "json":
+	case *format == "json":
 		jsonMode = true
+	case isListTemplate(*format):
+		if *fieldSpec != "" {
+			return fmt.Errorf("--fields and a --format template cannot be combined; name the fields in the template")
+		}
+		var err error
+		if tmpl, err = parseListTemplate(*format); err != nil {
+			return err
+		}
 	default:
-		return fmt.Errorf("unsupported format %q; supported formats: json", *format)
+		return fmt.Errorf("unsupported format %q; supported formats: json, or a Go template such as '{{.Branch}}'", *format)
+	}
+	var fields []listField
+	if *fieldSpec != "" {
+		var err error
+		if fields, err = parseListFields(*fieldSpec); err != nil {
+			return err
+		}
+	}
+	if *nullSep && !*pathsOnly {
+		return fmt.Errorf("-0 only applies to --paths-only")
+	}
+	if *pathsOnly && (*format != "" || *fieldSpec != "") {
+		return fmt.Errorf("--paths-only cannot be combined with --format or --fields")
+	}
+	order := listOrder{pinCurrent: *pinCurrent}
+	if *sortSpec != "" {
+		var err error
+		if order.mode, err = core.ParseSortMode(*sortSpec); err != nil {
+			return err
+		}
+	}
+	if *staleOnly {
+		if *filterSpec != "" {
+			return fmt.Errorf("--stale and --filter-status cannot be combined; --stale is --filter-status=stale with an exit code")
+		}
+		*filterSpec = "stale"
+	}
+	var filter core.StatusFilter
+	if *filterSpec != "" {
+		var err error
+		if filter, err = core.ParseStatusFilter(*filterSpec); err != nil {
+			return err
+		}
+	}
+	logging.Debug("CLI list: verbose=%v json=%v fetch=%v fields=%q noCI=%v size=%v sort=%q pin=%v filter=%q stale=%v", *verbose, jsonMode, *fetch, *fieldSpec, *noCI, *size, *sortSpec, *pinCurrent, *filterSpec, *staleOnly)
+
+	// staleExit is what listing n worktrees returns: with --stale, listing
+	// any fails with ExitCodeStale
+	staleExit := func(n int) error {
+		if *staleOnly && n > 0 {
+			logging.Info("CLI list --stale: %d stale worktree(s)", n)
+			return &ExitCodeError{Code: ExitCodeStale}
+		}
+		return nil
 	}
-	logging.Debug("CLI list: verbose=%v json=%v", *verbose, jsonMode)
 
 	ctx := context.Background()
 
+	if *fetch {
+		c.fetchForStaleStatus(jsonMode)
+	}
+
 	// In JSON mode: no spinner, no GitHub enrichment (keep output clean)
 	if jsonMode {
 		if *verbose {
 			fmt.Fprintln(os.Stderr, "warning: -v is ignored when --format=json is set")
 		}
+		if fields != nil {
+			fmt.Fprintln(os.Stderr, "warning: --fields is ignored when --format=json is set; JSON always includes every field")
+		}
 		worktrees, err := c.worktreeManager.ListWorktrees(ctx)
 		if err != nil {
 			logging.Error("CLI list (json) failed: %v", err)
@@ -524,6 +1046,16 @@ func (c *CLI) handleList(args []string) error {
 			_ = errEnc.Encode(map[string]string{"error": err.Error()})
 			return err
 		}
+		// Stale detection is only complete with PR state
+		if filter.NeedsBranchStatus() && c.worktreeManager.CheckGitHubAvailability() == core.GitHubAvailable {
+			c.worktreeManager.EnrichWithGitHubStatus(worktrees)
+		}
+		worktrees = filter.Filter(worktrees)
+		var sizes map[string]int64
+		if *size {
+			sizes = sortBySize(worktrees)
+		}
+		order.apply(worktrees)
 		items := make([]WorktreeJSON, len(worktrees))
 		for i, wt := range worktrees {
 			items[i] = WorktreeJSON{
@@ -533,23 +1065,85 @@ func (c *CLI) handleList(args []string) error {
 				IsCurrent:      wt.IsCurrent,
 				IsPrevious:     wt.IsPrevious,
 				IsMain:         wt.IsMain,
+				IsBare:         wt.IsBare,
 				Status:         wt.Status,
+				StatusError:    wt.StatusError,
 				LastCommit:     wt.LastCommit,
 				StagedCount:    wt.StagedCount,
 				ModifiedCount:  wt.ModifiedCount,
 				UnpushedCount:  wt.UnpushedCount,
 				UntrackedCount: wt.UntrackedCount,
+				Upstream:       wt.UpstreamName,
 				BranchStatus:   wt.BranchStatus,
 				PRNumber:       wt.PRNumber,
 				PRState:        wt.PRState,
 				PRURL:          wt.PRURL,
 				CIStatus:       wt.CIStatus,
 				StaleReason:    wt.StaleReason,
+				BranchMismatch: wt.BranchMismatch,
+				BrokenLink:     wt.BrokenLink,
+				NoRemote:       wt.NoRemote,
+			}
+			if !wt.Created.IsZero() {
+				items[i].Created = wt.Created.Format(time.RFC3339)
+			}
+			if bytes, ok := sizes[wt.Path]; ok {
+				items[i].SizeBytes = &bytes
 			}
 		}
 		enc := json.NewEncoder(os.Stdout)
 		enc.SetIndent("", "  ")
-		return enc.Encode(items)
+		if err := enc.Encode(items); err != nil {
+			return err
+		}
+		return staleExit(len(items))
+	}
+
+	// --paths-only prints nothing but the paths: no spinner, header or
+	// color, so the output can go straight into fzf or xargs
+	if *pathsOnly {
+		if *verbose || *size || *showCreated {
+			fmt.Fprintln(os.Stderr, "warning: -v, --size and --created are ignored with --paths-only")
+		}
+		worktrees, err := c.listForScript(ctx, false, false, order, filter)
+		if err != nil {
+			return err
+		}
+		sep := "\n"
+		if *nullSep {
+			sep = "\x00"
+		}
+		for _, wt := range worktrees {
+			fmt.Fprint(os.Stdout, wt.Path+sep)
+		}
+		return staleExit(len(worktrees))
+	}
+
+	if fields != nil {
+		if *size {
+			fmt.Fprintln(os.Stderr, "warning: --size is ignored when --fields is set")
+		}
+		if *showCreated {
+			fmt.Fprintln(os.Stderr, "warning: --created is ignored when --fields is set; add the created field instead")
+		}
+		n, err := c.listFields(ctx, fields, !*noCI, order, filter)
+		if err != nil {
+			return err
+		}
+		return staleExit(n)
+	}
+	if tmpl != nil {
+		if *verbose || *size {
+			fmt.Fprintln(os.Stderr, "warning: -v and --size are ignored with a --format template")
+		}
+		worktrees, err := c.listForScript(ctx, listTemplateNeedsForge(*format), !*noCI, order, filter)
+		if err != nil {
+			return err
+		}
+		if err := printListTemplate(humanOut(), worktrees, tmpl); err != nil {
+			return err
+		}
+		return staleExit(len(worktrees))
 	}
 
 	// Show spinner while fetching data (when GitHub is available)
@@ -572,7 +1166,9 @@ func (c *CLI) handleList(args []string) error {
 	if c.worktreeManager.CheckGitHubAvailability() == core.GitHubAvailable {
 		logging.Debug("CLI list: enriching with GitHub status")
 		c.worktreeManager.EnrichWithGitHubStatus(worktrees)
-		c.worktreeManager.EnrichWithCIStatus(worktrees)
+		if !*noCI {
+			c.worktreeManager.EnrichWithCIStatus(ctx, worktrees)
+		}
 	}
 
 	if sp != nil {
@@ -585,6 +1181,34 @@ func (c *CLI) handleList(args []string) error {
 		output.Info("No worktrees found")
 		return nil
 	}
+	if worktrees = filter.Filter(worktrees); len(worktrees) == 0 {
+		output.Infof("No worktrees with status %s", filter)
+		return nil
+	}
+
+	var sizes map[string]int64
+	if *size {
+		sp := newSpinner("Measuring disk usage...")
+		sp.Start()
+		sizes = sortBySize(worktrees)
+		sp.Stop()
+	}
+	order.apply(worktrees)
+	sizeOf := func(wt core.WorktreeInfo) string {
+		if sizes == nil {
+			return ""
+		}
+		if bytes, ok := sizes[wt.Path]; ok {
+			return output.FormatSize(bytes)
+		}
+		return "?"
+	}
+	createdOf := func(wt core.WorktreeInfo) string {
+		if !*showCreated {
+			return ""
+		}
+		return formatCreated(wt)
+	}
 
 	// Get repo name for header
 	repoInfo, _ := c.gitRepo.GetRepoInfo(ctx)
@@ -611,10 +1235,14 @@ func (c *CLI) handleList(args []string) error {
 				Path:      wt.Path,
 				IsCurrent: wt.IsCurrent,
 				IsMain:    wt.IsMain,
+				IsBare:    wt.IsBare,
 				StaleInfo: staleInfo,
 				PRInfo:    prInfo,
 				CIStatus:  wt.CIStatus,
 				Status:    wt.Status,
+				Size:      sizeOf(wt),
+				Created:   createdOf(wt),
+				NoRemote:  wt.NoRemote,
 			})
 		}
 		output.PrintWorktreeList(items, repoName)
@@ -626,37 +1254,123 @@ func (c *CLI) handleList(args []string) error {
 			if wt.BranchStatus == "stale" {
 				staleInfo = wt.StaleReason
 			}
+			prInfo := ""
+			if wt.PRNumber > 0 {
+				prInfo = fmt.Sprintf("#%d", wt.PRNumber)
+			}
 			items = append(items, output.WorktreeListItem{
 				Name:      wt.Name,
 				Branch:    wt.Branch,
 				IsCurrent: wt.IsCurrent,
 				StaleInfo: staleInfo,
+				PRInfo:    prInfo,
+				PRState:   wt.PRState,
 				CIStatus:  wt.CIStatus,
+				Status:    wt.Status,
+				Size:      sizeOf(wt),
+				Created:   createdOf(wt),
 			})
 		}
 		output.PrintSimpleWorktreeList(items)
 	}
 
-	return nil
+	return staleExit(len(worktrees))
 }
 
-// handleDelete handles the delete command
-// worktreeBlockingContent returns the modified / untracked / ignored entries in
-// a worktree that make a plain `git worktree remove` fail (git output lines like
-// "!! node_modules/", "?? file", " M file"). Empty means a clean removal will
-// succeed.
-func worktreeBlockingContent(path string) []string {
-	out, err := exec.Command("git", "-C", path, "status", "--porcelain", "--ignored").Output()
-	if err != nil {
-		return nil
-	}
-	var lines []string
-	for _, l := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
-		if strings.TrimSpace(l) != "" {
-			lines = append(lines, l)
+// sortBySize measures the disk usage of worktrees and sorts them largest
+// first, with any that could not be measured last. It returns the sizes by
+// path.
+func sortBySize(worktrees []core.WorktreeInfo) map[string]int64 {
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+	}
+	sizes := core.WorktreeDiskUsages(paths)
+	sort.SliceStable(worktrees, func(i, j int) bool {
+		si, iok := sizes[worktrees[i].Path]
+		sj, jok := sizes[worktrees[j].Path]
+		if iok != jok {
+			return iok
 		}
+		return si > sj
+	})
+	return sizes
+}
+
+// listOrder is the order `gren list --sort` and `--pin-current` ask for.
+// The zero value keeps git's order.
+type listOrder struct {
+	mode       core.SortMode
+	pinCurrent bool
+}
+
+// apply sorts worktrees into the order. It runs after sortBySize, so an
+// explicit --sort takes precedence over --size's largest-first order.
+func (o listOrder) apply(worktrees []core.WorktreeInfo) {
+	if o.mode == "" && !o.pinCurrent {
+		return
 	}
-	return lines
+	core.SortWorktrees(worktrees, o.mode, o.pinCurrent, core.WorktreeInfo.SortKey)
+}
+
+// listFields prints `gren list --fields` output. PR and CI status, which
+// also feed stale detection, are only looked up when a requested field needs
+// them, so plain fields stay fast; withCI false skips CI regardless. There is
+// no spinner: it would end up in piped output. It returns how many
+// worktrees it printed.
+func (c *CLI) listFields(ctx context.Context, fields []listField, withCI bool, order listOrder, filter core.StatusFilter) (int, error) {
+	needsForge := slices.ContainsFunc(fields, func(f listField) bool {
+		return f.name == "pr" || f.name == "ci" || f.name == "stale"
+	})
+	worktrees, err := c.listForScript(ctx, needsForge, withCI, order, filter)
+	if err != nil {
+		return 0, err
+	}
+	printListFields(worktrees, fields)
+	return len(worktrees), nil
+}
+
+// listForScript lists the worktrees for --fields and --format templates,
+// filtered and ordered, without a spinner. PR and CI state are only looked
+// up when the output shows them (needsForge) or the order or filter depends
+// on them.
+func (c *CLI) listForScript(ctx context.Context, needsForge, withCI bool, order listOrder, filter core.StatusFilter) ([]core.WorktreeInfo, error) {
+	worktrees, err := c.worktreeManager.ListWorktrees(ctx)
+	if err != nil {
+		logging.Error("CLI list failed: %v", err)
+		return nil, err
+	}
+
+	needsForge = needsForge || order.mode == core.SortStale || filter.NeedsBranchStatus()
+	if needsForge && c.worktreeManager.CheckGitHubAvailability() == core.GitHubAvailable {
+		c.worktreeManager.EnrichWithGitHubStatus(worktrees)
+		if withCI {
+			c.worktreeManager.EnrichWithCIStatus(ctx, worktrees)
+		}
+	}
+
+	worktrees = filter.Filter(worktrees)
+	order.apply(worktrees)
+	return worktrees, nil
+}
+
+// handleDelete handles the delete command
+// worktreeBlockingContent returns the modified / untracked / ignored entries in
+// a worktree that make a plain `git worktree remove` fail (git output lines like
+// "!! node_modules/", "?? file", " M file"). Empty means a clean removal will
+// succeed.
+func worktreeBlockingContent(path string) []string {
+	out, err := exec.Command("git", "-C", path, "status", "--porcelain", "--ignored").Output()
+	if err != nil {
+		return nil
+	}
+	var lines []string
+	for _, l := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		if strings.TrimSpace(l) != "" {
+			lines = append(lines, l)
+		}
+	}
+	return lines
 }
 
 // splitBlockingContent separates `git status --porcelain --ignored` lines into
@@ -687,8 +1401,9 @@ func capList(items []string, n int) []string {
 
 func (c *CLI) handleDelete(args []string) error {
 	fs := flag.NewFlagSet("delete", flag.ExitOnError)
-	force := fs.Bool("f", false, "Force deletion without confirmation")
+	force := fs.Bool("f", false, "Force deletion without confirmation, even with commits that are not pushed anywhere")
 	dryRun := fs.Bool("dry-run", false, "Show what would be deleted without actually deleting")
+	withBranch := fs.Bool("with-branch", false, "Also delete the local branch (git branch -d; -D with -f)")
 	format := addFormatFlag(fs)
 
 	fs.Usage = func() {
@@ -699,6 +1414,7 @@ func (c *CLI) handleDelete(args []string) error {
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
 		fmt.Fprintf(fs.Output(), "  gren delete feature-branch\n")
 		fmt.Fprintf(fs.Output(), "  gren delete -f old-feature\n")
+		fmt.Fprintf(fs.Output(), "  gren delete --with-branch merged-feature             # Remove the branch too\n")
 		fmt.Fprintf(fs.Output(), "  gren delete --dry-run feature-branch\n")
 		fmt.Fprintf(fs.Output(), "  gren delete --dry-run --format=json feature-branch   # What blocks it?\n")
 		fmt.Fprintf(fs.Output(), "  gren delete -f --format=json old-feature             # Machine-readable\n")
@@ -723,7 +1439,7 @@ func (c *CLI) handleDelete(args []string) error {
 	}
 
 	worktreeName := fs.Arg(0)
-	logging.Info("CLI delete: worktree=%s, force=%v, dry-run=%v, json=%v", worktreeName, *force, *dryRun, jsonMode)
+	logging.Info("CLI delete: worktree=%s, force=%v, dry-run=%v, with-branch=%v, json=%v", worktreeName, *force, *dryRun, *withBranch, jsonMode)
 
 	// Dry run mode - just show what would happen. In JSON mode this is the
 	// inspection call: it answers "is this worktree safe to remove, and if not,
@@ -733,28 +1449,6 @@ func (c *CLI) handleDelete(args []string) error {
 		return nil
 	}
 
-	// Confirmation unless force is specified. JSON mode never prompts: its
-	// callers are plugins, agents, and CI, none of which can answer. Without -f
-	// it reports what it would have asked about and exits non-zero, which is
-	// strictly more useful than the bare refusal a non-TTY got before.
-	if !*force && !*dryRun && !jsonMode {
-		// Check if we're running in an interactive terminal
-		if !term.IsTerminal(int(os.Stdin.Fd())) {
-			return fmt.Errorf("cannot delete worktree without confirmation in non-interactive mode; use -f to force")
-		}
-
-		fmt.Fprintf(humanOut(), "Delete worktree '%s'? (y/N): ", worktreeName)
-		var response string
-		fmt.Scanln(&response)
-		response = strings.ToLower(strings.TrimSpace(response))
-		if response != "y" && response != "yes" {
-			logging.Info("CLI delete: user cancelled deletion of %s", worktreeName)
-			fmt.Fprintln(humanOut(), "Cancelled")
-			return nil
-		}
-		logging.Info("CLI delete: user confirmed deletion of %s", worktreeName)
-	}
-
 	ctx := context.Background()
 
 	// Get worktree info for hook context
@@ -778,23 +1472,104 @@ func (c *CLI) handleDelete(args []string) error {
 		}
 		return fmt.Errorf("worktree '%s' not found", worktreeName)
 	}
+	// The bare repository, the current and the main worktree can't be
+	// deleted at all; the issues are what the user should see before any
+	// delete of the others
+	issues, risk, err := c.worktreeManager.PrecheckDelete(*targetWorktree)
+	if err != nil {
+		if jsonMode {
+			_ = emitJSON(DeleteJSON{Name: worktreeName, Reason: DeleteReasonError, Error: err.Error()})
+		}
+		return err
+	}
+
+	// A rebase or merge stopped on a conflict keeps its state in the
+	// worktree's git dir; deleting the worktree throws that away along with
+	// any conflicts resolved so far, so it takes -f.
+	if targetWorktree.Operation != "" && !*force && !jsonMode {
+		return core.OperationError(targetWorktree.Name, targetWorktree.Path, targetWorktree.Operation)
+	}
+
+	// Commits no remote has, with no open or merged PR holding the work,
+	// exist only in this repository: deleting them takes -f, and -f says so.
+	// So does a worktree git status couldn't read, which may hold anything.
+	risk = c.withDeletePR(*targetWorktree, risk)
+	if risk.StatusError != "" && !*force && !jsonMode {
+		printDeleteIssues(targetWorktree, issues, risk)
+		return fmt.Errorf("could not read the status of worktree '%s' (%s); retry once other git processes finish, or re-run with -f to delete anyway", targetWorktree.Name, risk.StatusError)
+	}
+	if risk.NeedsForce() && !jsonMode {
+		if !*force {
+			printDeleteIssues(targetWorktree, issues, risk)
+			return fmt.Errorf("worktree '%s' has %d commit(s) that are not pushed anywhere and no open or merged PR; push them, or re-run with -f to delete anyway (branch '%s' is kept)", targetWorktree.Name, risk.Unpushed, targetWorktree.Branch)
+		}
+		fmt.Fprintf(humanOut(), "⚠️  Deleting '%s' with %d commit(s) that are not pushed anywhere\n", targetWorktree.Name, risk.Unpushed)
+	}
+
+	// --with-branch refuses an unmerged or current branch before anything is
+	// removed, not after the worktree is already gone. A dry run reports it
+	// through WouldForce instead.
+	if *withBranch {
+		if err := c.worktreeManager.CheckDeleteBranch(targetWorktree.Branch, *force || *dryRun); err != nil {
+			if errors.Is(err, core.ErrBranchNotMerged) {
+				err = fmt.Errorf("%w; re-run with -f to delete it anyway", err)
+			}
+			if jsonMode {
+				_ = emitJSON(DeleteJSON{Name: targetWorktree.Name, Branch: targetWorktree.Branch, Path: targetWorktree.Path, BranchKept: true, Reason: DeleteReasonError, Error: err.Error()})
+			}
+			return err
+		}
+	}
+
+	// Confirmation unless force is specified. JSON mode never prompts: its
+	// callers are plugins, agents, and CI, none of which can answer. Without -f
+	// it reports what it would have asked about and exits non-zero, which is
+	// strictly more useful than the bare refusal a non-TTY got before.
+	if !*force && !*dryRun && !jsonMode {
+		// Check if we're running in an interactive terminal
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("cannot delete worktree without confirmation in non-interactive mode; use -f to force")
+		}
+
+		printDeleteIssues(targetWorktree, issues, risk)
+		fmt.Fprintf(humanOut(), "Delete worktree '%s'? (y/N): ", worktreeName)
+		var response string
+		fmt.Scanln(&response)
+		response = strings.ToLower(strings.TrimSpace(response))
+		if response != "y" && response != "yes" {
+			logging.Info("CLI delete: user cancelled deletion of %s", worktreeName)
+			fmt.Fprintln(humanOut(), "Cancelled")
+			return nil
+		}
+		logging.Info("CLI delete: user confirmed deletion of %s", worktreeName)
+	}
+
+	// Symlinks left by post-create hooks whose targets have since moved show
+	// up as untracked files. They hold nothing and the removal clears them,
+	// so they never count as blocking content.
+	dangling, _ := c.worktreeManager.FindDanglingSymlinks(targetWorktree.Path)
 
 	// JSON mode resolves the whole decision up front — blocking content, then
 	// dry-run or missing -f — so the caller gets one object describing why
 	// nothing happened, instead of an error string it has to pattern-match.
 	if jsonMode {
-		blocking := blockingJSON(targetWorktree.Path)
+		blocking := blockingJSON(targetWorktree.Path, danglingSymlinkPaths(dangling))
 		base := DeleteJSON{
-			Name:       targetWorktree.Name,
-			Branch:     targetWorktree.Branch,
-			Path:       targetWorktree.Path,
-			BranchKept: true,
-			Blocking:   blocking,
+			Name:             targetWorktree.Name,
+			Branch:           targetWorktree.Branch,
+			Path:             targetWorktree.Path,
+			BranchKept:       true,
+			Blocking:         blocking,
+			DanglingSymlinks: danglingSymlinkPaths(dangling),
+			Operation:        targetWorktree.Operation,
+			UnpushedCommits:  risk.Unpushed,
+			Unmerged:         risk.Unmerged,
+			StatusError:      risk.StatusError,
 		}
 		switch {
 		case *dryRun:
 			base.Reason = DeleteReasonDryRun
-			base.WouldForce = blocking != nil
+			base.WouldForce = blocking != nil || targetWorktree.Operation != "" || risk.StatusError != "" || risk.NeedsForce() || (*withBranch && risk.Unmerged)
 			return emitJSON(base)
 		case !*force:
 			base.Reason = DeleteReasonConfirmationRequired
@@ -834,7 +1609,8 @@ func (c *CLI) handleDelete(args []string) error {
 	// failing with a raw "Directory not empty".
 	effectiveForce := *force
 	if !effectiveForce {
-		if leftovers := worktreeBlockingContent(worktreePath); len(leftovers) > 0 {
+		leftovers := withoutDanglingSymlinks(worktreeBlockingContent(worktreePath), danglingSymlinkPaths(dangling))
+		if len(leftovers) > 0 {
 			real, ignored := splitBlockingContent(leftovers)
 			if len(real) == 0 {
 				// Only gitignored content blocks the removal — nothing worth a
@@ -866,7 +1642,7 @@ func (c *CLI) handleDelete(args []string) error {
 		}
 	}
 
-	err = c.worktreeManager.DeleteWorktree(ctx, worktreeName, effectiveForce)
+	removeResult, err := c.worktreeManager.RemoveWorktree(ctx, *targetWorktree, effectiveForce)
 	if err != nil {
 		logging.Error("CLI delete failed: %v", err)
 		if jsonMode {
@@ -884,6 +1660,28 @@ func (c *CLI) handleDelete(args []string) error {
 	}
 
 	logging.Info("CLI delete succeeded: %s", worktreeName)
+	if removed := removeResult.DanglingSymlinks; len(removed) > 0 && !jsonMode {
+		fmt.Fprintf(humanOut(), "Removed %d dangling symlink(s) (targets no longer exist):\n", len(removed))
+		for _, l := range capList(formatDanglingSymlinks(removed), 10) {
+			fmt.Fprintf(humanOut(), "  %s\n", l)
+		}
+	}
+	warnings := 0
+	branchKept := true
+	if *withBranch {
+		if err := c.worktreeManager.DeleteBranch(worktreeBranch, *force); err != nil {
+			logging.Error("CLI delete: failed to delete branch %s: %v", worktreeBranch, err)
+			if errors.Is(err, core.ErrBranchNotMerged) {
+				err = fmt.Errorf("%w; delete it with: git branch -D %s", err, worktreeBranch)
+			}
+			fmt.Fprintf(humanOut(), "⚠️  Kept branch '%s': %v\n", worktreeBranch, err)
+			warnings++
+		} else {
+			fmt.Fprintf(humanOut(), "Deleted branch '%s'\n", worktreeBranch)
+			branchKept = false
+		}
+	}
+
 	// Run post-remove hooks (best-effort: failures are logged but don't affect outcome)
 	c.worktreeManager.SetEventObserver(streamEventsTo(os.Stderr))
 	postResults := c.worktreeManager.RunPostRemoveHookWithApproval(worktreePath, worktreeBranch, false)
@@ -894,18 +1692,89 @@ func (c *CLI) handleDelete(args []string) error {
 		allHooks := append([]core.HookResult{}, results...)
 		allHooks = append(allHooks, postResults...)
 		return emitJSON(DeleteJSON{
-			Name:       targetWorktree.Name,
-			Branch:     worktreeBranch,
-			Path:       worktreePath,
-			Deleted:    true,
-			Forced:     effectiveForce,
-			BranchKept: true,
-			Hooks:      hookResultsToJSON(allHooks),
+			Name:             targetWorktree.Name,
+			Branch:           worktreeBranch,
+			Path:             worktreePath,
+			Deleted:          true,
+			Forced:           effectiveForce,
+			BranchKept:       branchKept,
+			DanglingSymlinks: danglingSymlinkPaths(removeResult.DanglingSymlinks),
+			Hooks:            hookResultsToJSON(allHooks),
 		})
 	}
+	output.PrintSummary(output.Summary{Verb: "deleted", Done: 1, Warnings: warnings + failedHookCount(postResults)})
+	if dir := c.worktreeManager.LeftoverWorktreeDir(worktreePath); dir != "" {
+		fmt.Fprintf(humanOut(), "💡 %s is now empty; set prune_empty_worktree_dir = true to have gren remove it\n", dir)
+	}
 	return nil
 }
 
+// cleanupBranch deletes the branch of wt, a stale worktree cleanup just
+// removed, and returns the number of warnings (0 or 1). Branches whose stale
+// reason doesn't show they were merged (remote_gone, pr_closed) are kept
+// unless force, and deleting one anyway is warned about.
+func (c *CLI) cleanupBranch(wt core.WorktreeInfo, force bool) int {
+	if wt.Branch == "" || wt.Branch == "(detached)" {
+		return 0
+	}
+	if wt.StaleReason == "branch_deleted" {
+		fmt.Printf("    ✓ Branch %s was already deleted\n", wt.Branch)
+		return 0
+	}
+	if err := c.worktreeManager.DeleteStaleBranch(wt, force); err != nil {
+		logging.Error("CLI cleanup: failed to delete branch %s: %v", wt.Branch, err)
+		hint := ""
+		if errors.Is(err, core.ErrBranchNotMerged) || errors.Is(err, core.ErrStaleBranchUnconfirmed) {
+			hint = " (use --force-delete to delete it anyway)"
+		}
+		fmt.Printf("    ⚠ Kept branch %s: %v%s\n", wt.Branch, err, hint)
+		return 1
+	}
+	logging.Info("CLI cleanup: deleted branch %s", wt.Branch)
+	if !core.StaleBranchMerged(wt.StaleReason) {
+		fmt.Printf("    ⚠ Deleted branch %s, which may hold unmerged work (%s)\n", wt.Branch, wt.StaleReason)
+		return 1
+	}
+	fmt.Printf("    ✓ Deleted branch %s\n", wt.Branch)
+	return 0
+}
+
+// withDeletePR fills in the PR of wt's branch on risk, what PrecheckDelete
+// found deleting wt would lose. The PR is only looked up when wt has commits
+// no remote has, the one case where it matters.
+func (c *CLI) withDeletePR(wt core.WorktreeInfo, risk core.DeleteRisk) core.DeleteRisk {
+	if risk.Unpushed == 0 || wt.Branch == "" || wt.Branch == "(detached)" {
+		return risk
+	}
+	provider := c.prProvider
+	if provider == nil {
+		provider = git.DetectProvider()
+	}
+	if provider.IsAvailable() {
+		if pr, err := provider.GetPRInfo(wt.Branch); err == nil && pr != nil {
+			risk.PRNumber, risk.PRState = pr.Number, pr.State
+		}
+	}
+	return risk
+}
+
+// printDeleteIssues lists the issues PrecheckDelete found for wt, with the
+// PR withDeletePR looked up for its unpushed commits, or says deleting it loses
+// nothing.
+func printDeleteIssues(wt *core.WorktreeInfo, issues []string, risk core.DeleteRisk) {
+	if len(issues) == 0 {
+		fmt.Fprintf(humanOut(), "Worktree '%s' (%s) has no uncommitted changes or unpushed commits.\n", wt.Name, wt.Branch)
+		return
+	}
+	fmt.Fprintf(humanOut(), "Worktree '%s' (%s) has:\n", wt.Name, wt.Branch)
+	for _, line := range issues {
+		fmt.Fprintf(humanOut(), "  - %s\n", line)
+	}
+	if risk.PRState != "" {
+		fmt.Fprintf(humanOut(), "  (PR #%d is %s)\n", risk.PRNumber, strings.ToLower(risk.PRState))
+	}
+}
+
 // Reasons reported by `gren delete --format=json` when Deleted is false. They
 // are a closed set so a caller can switch on them instead of matching error
 // prose, which is free to change.
@@ -933,13 +1802,28 @@ type DeleteJSON struct {
 	Forced  bool   `json:"forced,omitempty"`
 	// WouldForce reports, for a dry run, whether the real delete would need -f.
 	WouldForce bool `json:"would_force,omitempty"`
-	// BranchKept is always true: gren preserves the branch by design. It is
-	// stated rather than implied so callers don't guess.
+	// BranchKept is true unless --with-branch deleted the branch: gren
+	// preserves it by default. It is stated rather than implied so callers
+	// don't guess.
 	BranchKept bool          `json:"branch_kept"`
 	Reason     string        `json:"reason,omitempty"`
 	Blocking   *BlockingJSON `json:"blocking,omitempty"`
-	Hooks      []HookJSON    `json:"hooks,omitempty"`
-	Error      string        `json:"error,omitempty"`
+	// DanglingSymlinks lists symlinks whose targets are gone, relative to the
+	// worktree: found, for a dry run or refusal, or removed, when deleted.
+	DanglingSymlinks []string `json:"dangling_symlinks,omitempty"`
+	// Operation is the git operation ("rebase", "merge", ...) stopped halfway
+	// in the worktree. Deleting discards it, so it takes -f.
+	Operation string `json:"operation,omitempty"`
+	// UnpushedCommits counts commits no remote has. With no open or merged
+	// PR holding them, deleting takes -f. Unmerged reports that the branch
+	// has commits its base branch lacks.
+	UnpushedCommits int  `json:"unpushed_commits,omitempty"`
+	Unmerged        bool `json:"unmerged,omitempty"`
+	// StatusError is why git status failed in the worktree, whose changes
+	// are then unknown. Deleting it takes -f.
+	StatusError string     `json:"status_error,omitempty"`
+	Hooks       []HookJSON `json:"hooks,omitempty"`
+	Error       string     `json:"error,omitempty"`
 }
 
 // BlockingJSON describes content that stops a plain `git worktree remove`.
@@ -966,10 +1850,29 @@ type BlockingEntry struct {
 	Path   string `json:"path"`
 }
 
+// danglingSymlinkPaths returns the worktree-relative paths of links.
+func danglingSymlinkPaths(links []core.DanglingSymlink) []string {
+	var paths []string
+	for _, l := range links {
+		paths = append(paths, l.Path)
+	}
+	return paths
+}
+
+// formatDanglingSymlinks renders links as "path -> target" lines.
+func formatDanglingSymlinks(links []core.DanglingSymlink) []string {
+	lines := make([]string, len(links))
+	for i, l := range links {
+		lines[i] = l.Path + " -> " + l.Target
+	}
+	return lines
+}
+
 // blockingJSON inspects a worktree path and reports what would block removal,
-// or nil when a plain remove would succeed.
-func blockingJSON(worktreePath string) *BlockingJSON {
-	leftovers := worktreeBlockingContent(worktreePath)
+// or nil when a plain remove would succeed. Dangling symlinks are left out:
+// the removal clears them itself, so they never block it.
+func blockingJSON(worktreePath string, dangling []string) *BlockingJSON {
+	leftovers := withoutDanglingSymlinks(worktreeBlockingContent(worktreePath), dangling)
 	if len(leftovers) == 0 {
 		return nil
 	}
@@ -981,6 +1884,19 @@ func blockingJSON(worktreePath string) *BlockingJSON {
 	return &BlockingJSON{Tracked: entries, IgnoredCount: len(ignored)}
 }
 
+// withoutDanglingSymlinks drops the untracked entries for the dangling
+// symlink paths from porcelain lines.
+func withoutDanglingSymlinks(lines []string, dangling []string) []string {
+	var kept []string
+	for _, l := range lines {
+		if entry := parseBlockingEntry(l); entry.Status == "??" && slices.Contains(dangling, entry.Path) {
+			continue
+		}
+		kept = append(kept, l)
+	}
+	return kept
+}
+
 // parseBlockingEntry splits a porcelain line into its status code and path.
 // A line too short for the fixed "XY path" shape is passed through as the path
 // with an empty status rather than dropped: an entry the caller cannot classify
@@ -995,31 +1911,116 @@ func parseBlockingEntry(line string) BlockingEntry {
 	}
 }
 
+// cleanupShortcuts are cleanup flags that select a category of stale
+// reasons, as shorthand for --reason.
+var cleanupShortcuts = []struct {
+	flag    string
+	usage   string
+	reasons []string
+}{
+	{"merged-only", "Only clean up merged worktrees (merged_locally, pr_merged)", []string{"merged_locally", "pr_merged"}},
+	{"remote-gone-only", "Only clean up worktrees whose remote branch was deleted (remote_gone)", []string{"remote_gone"}},
+	{"closed-only", "Only clean up worktrees whose PR was closed without merging (pr_closed)", []string{"pr_closed"}},
+}
+
+// cleanupReasonList merges the --reason value with the reasons of the
+// enabled cleanupShortcuts (enabled[i] for cleanupShortcuts[i]) into one
+// comma-separated list. It returns "" when neither is given.
+func cleanupReasonList(reason string, enabled []bool) string {
+	var list []string
+	if reason != "" {
+		list = append(list, reason)
+	}
+	for i, shortcut := range cleanupShortcuts {
+		if enabled[i] {
+			list = append(list, shortcut.reasons...)
+		}
+	}
+	return strings.Join(list, ",")
+}
+
 // handleCleanup handles the cleanup command (delete all stale worktrees)
 func (c *CLI) handleCleanup(args []string) error {
 	fs := flag.NewFlagSet("cleanup", flag.ExitOnError)
 	skipConfirmation := fs.Bool("f", false, "Skip confirmation prompt")
 	forceDelete := fs.Bool("force-delete", false, "Force delete even with uncommitted changes")
 	dryRun := fs.Bool("dry-run", false, "Show what would be deleted without actually deleting")
+	fetch := fs.Bool("fetch", false, "Fetch from origin (with prune) first so deleted remote branches are detected")
+	reasonFlag := fs.String("reason", "", "Only clean up worktrees with these stale reasons (comma-separated: "+strings.Join(core.StaleReasons, ", ")+")")
+	keep := fs.Int("keep", 0, "Keep the N stale worktrees with the most recent commits")
+	createdBeforeFlag := fs.String("created-before", "", "Only clean up worktrees created before this date (YYYY-MM-DD) or longer ago than an age (30d, 2w)")
+	withBranch := fs.Bool("with-branch", false, "Also delete the local branches of deleted worktrees (remote_gone and pr_closed ones only with --force-delete)")
+	interactive := fs.Bool("interactive", false, "Pick the stale worktrees to delete from a numbered list")
+	fs.BoolVar(interactive, "i", false, "Shorthand for --interactive")
+	shortcuts := make([]*bool, len(cleanupShortcuts))
+	for i, shortcut := range cleanupShortcuts {
+		shortcuts[i] = fs.Bool(shortcut.flag, false, shortcut.usage)
+	}
 
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), "Usage: gren cleanup [options]\n")
 		fmt.Fprintf(fs.Output(), "\nDelete all stale worktrees (merged PRs, gone remotes)\n\n")
+		fmt.Fprintf(fs.Output(), "Branches are kept, as with gren delete. With --with-branch the branches of\n")
+		fmt.Fprintf(fs.Output(), "merged worktrees (pr_merged, merged_locally, no_unique_commits) are deleted;\n")
+		fmt.Fprintf(fs.Output(), "remote_gone and pr_closed ones may hold unmerged work and need --force-delete.\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
 		fmt.Fprintf(fs.Output(), "  gren cleanup --dry-run           # See what would be deleted\n")
 		fmt.Fprintf(fs.Output(), "  gren cleanup                     # Delete with confirmation\n")
 		fmt.Fprintf(fs.Output(), "  gren cleanup -f                  # Delete without confirmation\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup -i                  # Pick which ones to delete (e.g. 1-3,5)\n")
 		fmt.Fprintf(fs.Output(), "  gren cleanup --force-delete      # Force delete (ignore uncommitted changes)\n")
 		fmt.Fprintf(fs.Output(), "  gren cleanup -f --force-delete   # Skip confirmation and force delete\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup --fetch --dry-run   # Refresh remote refs, then preview\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup --reason pr_merged  # Only worktrees whose PR was merged\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup --merged-only -f    # Delete merged worktrees, keep closed-PR ones\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup --keep 3            # Leave the 3 most recently active stale worktrees\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup --created-before 30d   # Only stale worktrees older than a month\n")
+		fmt.Fprintf(fs.Output(), "  gren cleanup --merged-only --with-branch   # Remove merged worktrees and their branches\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
-	logging.Info("CLI cleanup: skip-confirmation=%v, force-delete=%v, dry-run=%v", *skipConfirmation, *forceDelete, *dryRun)
+	enabled := make([]bool, len(shortcuts))
+	for i, set := range shortcuts {
+		enabled[i] = *set
+	}
+	reasonFilter := cleanupReasonList(*reasonFlag, enabled)
+	if *keep < 0 {
+		return fmt.Errorf("--keep must be 0 or more, got %d", *keep)
+	}
+	if *interactive {
+		if *skipConfirmation || *dryRun {
+			return fmt.Errorf("--interactive asks which worktrees to delete; it cannot be combined with -f or --dry-run")
+		}
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("--interactive needs a terminal")
+		}
+	}
+
+	var reasons map[string]bool
+	if reasonFilter != "" {
+		var err error
+		if reasons, err = core.ParseStaleReasons(reasonFilter); err != nil {
+			return err
+		}
+	}
+	var createdBefore time.Time
+	if *createdBeforeFlag != "" {
+		var err error
+		if createdBefore, err = core.ParseCreatedBefore(*createdBeforeFlag, time.Now()); err != nil {
+			return err
+		}
+	}
+
+	logging.Info("CLI cleanup: skip-confirmation=%v, force-delete=%v, dry-run=%v, fetch=%v, reason=%q, keep=%d, created-before=%q, with-branch=%v", *skipConfirmation, *forceDelete, *dryRun, *fetch, reasonFilter, *keep, *createdBeforeFlag, *withBranch)
+
+	if *fetch {
+		c.fetchForStaleStatus(false)
+	}
 
 	// Show spinner while fetching data
 	sp := newSpinner("Fetching worktree status...")
@@ -1041,46 +2042,166 @@ func (c *CLI) handleCleanup(args []string) error {
 
 	sp.Stop()
 
-	// Find stale worktrees
-	var staleWorktrees []core.WorktreeInfo
+	// Find stale worktrees, restricted to the requested reasons if any.
+	// Worktrees mid-rebase/merge are held back unless --force-delete: removing
+	// them would discard the operation. So are worktrees whose status git
+	// couldn't read, which may hold uncommitted work.
+	var staleWorktrees, inProgress, unknownStatus []core.WorktreeInfo
+	skipped, newer := 0, 0
 	for _, wt := range worktrees {
-		if wt.BranchStatus == "stale" {
-			staleWorktrees = append(staleWorktrees, wt)
+		if wt.BranchStatus != "stale" {
+			continue
+		}
+		if reasons != nil && !reasons[wt.StaleReason] {
+			skipped++
+			continue
+		}
+		if !createdBefore.IsZero() && (wt.Created.IsZero() || !wt.Created.Before(createdBefore)) {
+			newer++
+			continue
+		}
+		if wt.Operation != "" && !*forceDelete {
+			inProgress = append(inProgress, wt)
+			continue
+		}
+		if wt.StatusError != "" && !*forceDelete {
+			unknownStatus = append(unknownStatus, wt)
+			continue
+		}
+		staleWorktrees = append(staleWorktrees, wt)
+	}
+
+	if len(inProgress) > 0 {
+		fmt.Printf("Skipping %d stale worktree(s) with a git operation in progress:\n", len(inProgress))
+		for _, wt := range inProgress {
+			fmt.Printf("  - %s [%s in progress]\n", wt.Branch, wt.Operation)
+		}
+		fmt.Println("  Finish or abort it (e.g. git rebase --abort), or re-run with --force-delete to discard it.")
+		fmt.Println()
+	}
+	if len(unknownStatus) > 0 {
+		fmt.Printf("Skipping %d stale worktree(s) whose status could not be read:\n", len(unknownStatus))
+		for _, wt := range unknownStatus {
+			fmt.Printf("  - %s [%s]\n", wt.Branch, wt.StatusError)
 		}
+		fmt.Println("  Retry once other git processes finish, or re-run with --force-delete to delete them anyway.")
+		fmt.Println()
+	}
+
+	var kept []core.WorktreeInfo
+	kept, staleWorktrees = keepMostRecent(staleWorktrees, *keep)
+	if len(kept) > 0 {
+		fmt.Printf("Keeping the %d most recent stale worktree(s):\n", len(kept))
+		for _, wt := range kept {
+			fmt.Printf("  - %s [last commit %s]\n", wt.Branch, wt.LastCommit)
+		}
+		fmt.Println()
 	}
 
 	if len(staleWorktrees) == 0 {
+		if len(kept) > 0 {
+			fmt.Println("No other stale worktrees to delete")
+			return nil
+		}
+		if skipped > 0 {
+			fmt.Printf("No stale worktrees with reason %s (%d with other reasons)\n", reasonFilter, skipped)
+			return nil
+		}
+		if newer > 0 {
+			fmt.Printf("No stale worktrees created before %s (%d newer)\n", createdBefore.Format("2006-01-02"), newer)
+			return nil
+		}
 		fmt.Println("No stale worktrees found")
 		return nil
 	}
 
-	// Show what will be deleted
+	// Measure disk usage in the background while the list is printed
+	sizesCh := make(chan map[string]int64, 1)
+	go func() {
+		paths := make([]string, len(staleWorktrees))
+		for i, wt := range staleWorktrees {
+			paths[i] = wt.Path
+		}
+		sizesCh <- core.WorktreeDiskUsages(paths)
+	}()
+
+	// Show what will be deleted. With --interactive the list is numbered for
+	// picking, and * marks the worktrees that are safe to delete.
 	fmt.Printf("Found %d stale worktree(s):\n", len(staleWorktrees))
 	hasAnySubmodules := false
-	for _, wt := range staleWorktrees {
+	for i, wt := range staleWorktrees {
 		reason := wt.StaleReason
 		if wt.PRNumber > 0 {
 			reason = fmt.Sprintf("%s (PR #%d %s)", reason, wt.PRNumber, wt.PRState)
 		}
+		if wt.Operation != "" {
+			reason = fmt.Sprintf("%s, %s in progress", reason, wt.Operation)
+		}
 		submoduleIndicator := ""
 		if wt.HasSubmodules {
 			submoduleIndicator = " 📦"
 			hasAnySubmodules = true
 		}
+		if *interactive {
+			if wt.HasUncommittedChanges() {
+				reason += ", uncommitted changes"
+			}
+			safe := " "
+			if wt.SafeToCleanUp() {
+				safe = "*"
+			}
+			fmt.Printf(" %s%2d. %s [%s]%s\n", safe, i+1, wt.Branch, reason, submoduleIndicator)
+			continue
+		}
 		fmt.Printf("  - %s [%s]%s\n", wt.Branch, reason, submoduleIndicator)
 	}
 	if hasAnySubmodules {
 		fmt.Println("\n  📦 = has submodules (will use force delete automatically)")
 	}
+	if skipped > 0 {
+		fmt.Printf("\n  %d stale worktree(s) with other reasons left alone\n", skipped)
+	}
+
+	if *dryRun || !*skipConfirmation {
+		sp := newSpinner("Measuring disk usage...")
+		sp.Start()
+		sizes := <-sizesCh
+		sp.Stop()
+		fmt.Printf("\n%s\n", cleanupSummary(staleWorktrees, sizes))
+	}
 
 	// Dry run mode - just show what would happen
 	if *dryRun {
+		if *withBranch {
+			printCleanupBranchPlan(staleWorktrees, *forceDelete)
+		}
 		fmt.Println("\n[dry-run] No worktrees were deleted")
 		return nil
 	}
 
-	// Confirmation unless skip confirmation is specified
-	if !*skipConfirmation {
+	// Delete stale worktrees. Those held back (an operation in progress, a
+	// status git couldn't read, or uncommitted changes in a picked one)
+	// count as skipped.
+	summary := output.Summary{Verb: "deleted", Skipped: len(inProgress) + len(unknownStatus)}
+
+	if *interactive {
+		picked, held := pickStaleWorktrees(os.Stdin, staleWorktrees, *forceDelete)
+		for _, wt := range held {
+			fmt.Printf("  Skipping %s: uncommitted changes (re-run with --force-delete to delete it)\n", wt.Branch)
+		}
+		if picked == nil && held == nil {
+			logging.Info("CLI cleanup: user cancelled")
+			fmt.Println("Cancelled")
+			return nil
+		}
+		if len(picked) == 0 {
+			fmt.Println("Nothing to delete")
+			return nil
+		}
+		staleWorktrees = picked
+		summary.Skipped += len(held)
+	} else if !*skipConfirmation {
+		// Confirmation unless skip confirmation is specified
 		fmt.Printf("\nDelete these %d worktrees? (y/N): ", len(staleWorktrees))
 		var response string
 		fmt.Scanln(&response)
@@ -1092,47 +2213,304 @@ func (c *CLI) handleCleanup(args []string) error {
 		}
 	}
 
-	// Delete stale worktrees
-	var deleted, failed int
 	for _, wt := range staleWorktrees {
 		err := c.worktreeManager.DeleteWorktree(ctx, wt.Name, *forceDelete)
 		if err != nil {
 			logging.Error("CLI cleanup: failed to delete %s: %v", wt.Name, err)
 			fmt.Printf("  ✗ Failed to delete %s: %v\n", wt.Branch, err)
-			failed++
+			summary.Failed++
 		} else {
 			logging.Info("CLI cleanup: deleted %s", wt.Name)
 			fmt.Printf("  ✓ Deleted %s\n", wt.Branch)
-			deleted++
+			summary.Done++
+			if *withBranch {
+				summary.Warnings += c.cleanupBranch(wt, *forceDelete)
+			}
 		}
 	}
 
-	fmt.Printf("\nDeleted %d worktree(s)", deleted)
-	if failed > 0 {
-		fmt.Printf(", %d failed", failed)
-	}
 	fmt.Println()
+	output.PrintSummary(summary)
 
 	return nil
 }
 
-// handleInit handles the init command (non-interactive)
-func (c *CLI) handleInit(args []string) error {
-	fs := flag.NewFlagSet("init", flag.ExitOnError)
-	project := fs.String("project", "", "Project name (defaults to repository name)")
+// printCleanupBranchPlan says, for `cleanup --with-branch --dry-run`, what
+// would happen to the branches of the stale worktrees: merged ones are
+// deleted, the others (remote_gone, pr_closed) only with force.
+func printCleanupBranchPlan(worktrees []core.WorktreeInfo, force bool) {
+	var unconfirmed []string
+	for _, wt := range worktrees {
+		if wt.Branch != "" && wt.Branch != "(detached)" && wt.StaleReason != "branch_deleted" && !core.StaleBranchMerged(wt.StaleReason) {
+			unconfirmed = append(unconfirmed, fmt.Sprintf("%s [%s]", wt.Branch, wt.StaleReason))
+		}
+	}
+	fmt.Println("\n[dry-run] Their local branches would be deleted too (unmerged ones need --force-delete)")
+	if len(unconfirmed) == 0 {
+		return
+	}
+	if force {
+		fmt.Println("[dry-run] These may hold unmerged work and would be force-deleted:")
+	} else {
+		fmt.Println("[dry-run] These may hold unmerged work and would be kept without --force-delete:")
+	}
+	for _, b := range unconfirmed {
+		fmt.Printf("  - %s\n", b)
+	}
+}
+
+// pickStaleWorktrees asks which of the numbered stale worktrees to delete,
+// reading a selection such as "1-3,5" or "all" from in. Enter picks the ones
+// SafeToCleanUp marked with *, and "n", end of input or Enter with none
+// marked cancels, returning nil for both. Picked worktrees with uncommitted
+// changes are held back unless force, since deleting them would lose the
+// changes.
+func pickStaleWorktrees(in io.Reader, worktrees []core.WorktreeInfo, force bool) (picked, held []core.WorktreeInfo) {
+	var safe []int
+	for i, wt := range worktrees {
+		if wt.SafeToCleanUp() {
+			safe = append(safe, i)
+		}
+	}
+
+	reader := bufio.NewReader(in)
+	for {
+		if len(safe) > 0 {
+			fmt.Printf("\nWorktrees to delete (e.g. 1-3,5 or all; Enter picks the %d marked *; n cancels): ", len(safe))
+		} else {
+			fmt.Print("\nWorktrees to delete (e.g. 1-3,5 or all; Enter cancels): ")
+		}
+		answer, err := reader.ReadString('\n')
+		answer = strings.TrimSpace(answer)
+
+		var indices []int
+		switch {
+		case strings.EqualFold(answer, "n"), answer == "" && (len(safe) == 0 || err != nil):
+			// End of input (Ctrl-D) cancels too, rather than taking the default
+			return nil, nil
+		case answer == "":
+			indices = safe
+		default:
+			var parseErr error
+			if indices, parseErr = parseSelection(answer, len(worktrees)); parseErr != nil {
+				fmt.Printf("  %v\n", parseErr)
+				if err != nil {
+					return nil, nil
+				}
+				continue
+			}
+		}
+
+		for _, i := range indices {
+			if worktrees[i].HasUncommittedChanges() && !force {
+				held = append(held, worktrees[i])
+				continue
+			}
+			picked = append(picked, worktrees[i])
+		}
+		if picked == nil {
+			picked = []core.WorktreeInfo{}
+		}
+		return picked, held
+	}
+}
+
+// keepMostRecent splits stale worktrees for `cleanup --keep n` into the n
+// with the most recent commits, which are kept, and the rest, newest first.
+func keepMostRecent(worktrees []core.WorktreeInfo, n int) (kept, rest []core.WorktreeInfo) {
+	if n <= 0 {
+		return nil, worktrees
+	}
+	sorted := slices.Clone(worktrees)
+	core.SortWorktrees(sorted, core.SortRecent, false, core.WorktreeInfo.SortKey)
+	n = min(n, len(sorted))
+	return sorted[:n], sorted[n:]
+}
+
+// handlePrune handles the prune command: drop git's records of worktrees
+// whose directories were deleted without gren or git.
+func (c *CLI) handlePrune(args []string) error {
+	fs := flag.NewFlagSet("prune", flag.ExitOnError)
+	expire := fs.String("expire", "", "Only prune worktrees whose administrative files are older than this (git date, e.g. 1.week.ago)")
+	dryRun := fs.Bool("dry-run", false, "Show what would be pruned without pruning")
 
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: gren init [options]\n")
-		fmt.Fprintf(fs.Output(), "\nInitialize gren in the current repository\n\n")
+		fmt.Fprintf(fs.Output(), "Usage: gren prune [options]\n")
+		fmt.Fprintf(fs.Output(), "\nRemove git's records of worktrees whose directories no longer exist\n(git worktree prune)\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren prune --dry-run             # See what would be pruned\n")
+		fmt.Fprintf(fs.Output(), "  gren prune\n")
+		fmt.Fprintf(fs.Output(), "  gren prune --expire 1.week.ago   # Only records untouched for a week\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+	logging.Info("CLI prune: expire=%q dry-run=%v", *expire, *dryRun)
 
-	projectName := *project
+	pruned, err := c.worktreeManager.PruneWorktrees(context.Background(), core.PruneOptions{Expire: *expire, DryRun: *dryRun})
+	if err != nil {
+		return err
+	}
+	if len(pruned) == 0 {
+		fmt.Println("Nothing to prune")
+		return nil
+	}
+
+	for _, p := range pruned {
+		fmt.Printf("  - %s (%s)\n", p.Name, p.Reason)
+	}
+	if *dryRun {
+		fmt.Printf("\n[dry-run] %d worktree(s) would be pruned\n", len(pruned))
+		return nil
+	}
+	output.PrintSummary(output.Summary{Verb: "pruned", Done: len(pruned)})
+	return nil
+}
+
+func (c *CLI) handleRepair(args []string) error {
+	fs := flag.NewFlagSet("repair", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren repair [<path>...]\n")
+		fmt.Fprintf(fs.Output(), "\nReconnect worktrees and the repository after either was moved by hand\n(git worktree repair)\n\n")
+		fmt.Fprintf(fs.Output(), "A moved main worktree needs no arguments. Pass the new location of a\nmoved worktree; without paths, gren looks for moved worktrees next to\nthe others and in the worktree directory.\n")
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren repair                       # After moving the repository\n")
+		fmt.Fprintf(fs.Output(), "  gren repair ../elsewhere/feature  # After moving a worktree\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	logging.Info("CLI repair: paths=%v", fs.Args())
+
+	repaired, err := c.worktreeManager.RepairWorktrees(context.Background(), fs.Args())
+	if err != nil {
+		return err
+	}
+	if len(repaired) == 0 {
+		fmt.Println("Nothing to repair")
+		return nil
+	}
+
+	for _, r := range repaired {
+		fmt.Printf("  - %s (%s)\n", r.Path, r.Problem)
+	}
+	output.PrintSummary(output.Summary{Verb: "repaired", Done: len(repaired)})
+	return nil
+}
+
+// cleanupSummary describes what deleting worktrees frees, e.g.
+// "3 worktree(s), ~1.2 GB on disk". Worktrees that could not be measured are
+// left out of the total and called out.
+func cleanupSummary(worktrees []core.WorktreeInfo, sizes map[string]int64) string {
+	var total int64
+	unmeasured := 0
+	for _, wt := range worktrees {
+		size, ok := sizes[wt.Path]
+		if !ok {
+			unmeasured++
+			continue
+		}
+		total += size
+	}
+	summary := fmt.Sprintf("%d worktree(s), ~%s on disk", len(worktrees), output.FormatSize(total))
+	if unmeasured > 0 {
+		summary += fmt.Sprintf(" (%d could not be measured)", unmeasured)
+	}
+	return summary
+}
+
+// fetchForStaleStatus runs `git fetch --prune origin` for --fetch. A failed
+// fetch (offline, no origin) only warns: stale detection still runs against
+// the remote refs already on disk.
+func (c *CLI) fetchForStaleStatus(jsonMode bool) {
+	// Without a remote there is nothing to fetch, nor any remote status to
+	// be out of date
+	if !c.worktreeManager.HasRemote() {
+		logging.Debug("CLI fetch: no remote configured, skipping")
+		return
+	}
+	var sp *spinner
+	if !jsonMode {
+		sp = newSpinner("Fetching from origin...")
+		sp.Start()
+	}
+	err := c.worktreeManager.FetchOriginPrune()
+	if sp != nil {
+		sp.Stop()
+	}
+	if err == nil {
+		return
+	}
+	if jsonMode {
+		fmt.Fprintf(os.Stderr, "warning: %v; stale status may be out of date\n", err)
+		return
+	}
+	output.Warningf("%v; stale status may be out of date", err)
+}
+
+// handleInit handles the init command (non-interactive)
+func (c *CLI) handleInit(args []string) error {
+	fs := flag.NewFlagSet("init", flag.ExitOnError)
+	project := fs.String("project", "", "Project name (defaults to repository name)")
+	bare := fs.Bool("bare", false, "Set up the bare-clone layout: clone <url> into it, or convert this repository")
+	dryRun := fs.Bool("dry-run", false, "With --bare: show the steps without running them")
+	autoYes := fs.Bool("y", false, "With --bare: skip the confirmation")
+	hook := fs.String("hook", "", "Post-create hook template: "+strings.Join(config.HookTemplates, ", "))
+	noHook := fs.Bool("no-hook", false, "Don't create a post-create hook (same as --hook none)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren init [options]\n")
+		fmt.Fprintf(fs.Output(), "       gren init --bare [--dry-run] [-y] [<url> [dir]]\n")
+		fmt.Fprintf(fs.Output(), "\nInitialize gren in the current repository\n\n")
+		fmt.Fprintf(fs.Output(), "With --bare, set up the bare-clone layout instead: the git data in\n")
+		fmt.Fprintf(fs.Output(), "<project>/.bare, a .git file pointing at it, and every worktree in\n")
+		fmt.Fprintf(fs.Output(), "<project>. Given a URL it clones into a new directory; without one it\n")
+		fmt.Fprintf(fs.Output(), "converts the current repository in place, which needs a clean working\n")
+		fmt.Fprintf(fs.Output(), "tree and no linked worktrees.\n\n")
+		fmt.Fprintf(fs.Output(), "--hook writes a post-create hook that installs dependencies for the\n")
+		fmt.Fprintf(fs.Output(), "toolchain (auto picks it from package.json, go.mod, Cargo.toml or the\n")
+		fmt.Fprintf(fs.Output(), "Python manifests); without it the install step is left commented out.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren init                                     # Initialize gren config\n")
+		fmt.Fprintf(fs.Output(), "  gren init --hook auto                         # Hook that installs this project's dependencies\n")
+		fmt.Fprintf(fs.Output(), "  gren init --no-hook                           # Config only, no post-create hook\n")
+		fmt.Fprintf(fs.Output(), "  gren init --bare git@github.com:org/app.git  # Clone into ./app as a bare layout\n")
+		fmt.Fprintf(fs.Output(), "  gren init --bare --dry-run                    # Show how this repo would be converted\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *bare {
+		return c.initBare(fs.Args(), *dryRun, *autoYes)
+	}
+	if *dryRun || *autoYes {
+		return fmt.Errorf("--dry-run and -y are only supported with --bare")
+	}
+	hookTemplate := *hook
+	if *noHook {
+		if hookTemplate != "" && hookTemplate != config.HookTemplateNone {
+			return fmt.Errorf("--no-hook cannot be combined with --hook %s", hookTemplate)
+		}
+		hookTemplate = config.HookTemplateNone
+	}
+	if hookTemplate != "" && !slices.Contains(config.HookTemplates, hookTemplate) {
+		return fmt.Errorf("unknown hook template %q (valid: %s)", hookTemplate, strings.Join(config.HookTemplates, ", "))
+	}
+
+	projectName := *project
 	if projectName == "" {
 		// Get repository name
 		ctx := context.Background()
@@ -1144,11 +2522,11 @@ func (c *CLI) handleInit(args []string) error {
 		projectName = repoInfo.Name
 	}
 
-	logging.Info("CLI init: project=%s", projectName)
+	logging.Info("CLI init: project=%s, hook=%s", projectName, hookTemplate)
 
 	// CLI defaults to tracking .gren in git (TUI has interactive prompt)
 	trackGrenInGit := true
-	result := config.Initialize(projectName, trackGrenInGit)
+	result := config.InitializeWithHook(projectName, trackGrenInGit, hookTemplate)
 	if result.Error != nil {
 		logging.Error("CLI init failed: %v", result.Error)
 		return fmt.Errorf("initialization failed: %w", result.Error)
@@ -1161,17 +2539,88 @@ func (c *CLI) handleInit(args []string) error {
 		fmt.Println("📝 Configuration file created")
 	}
 	if result.HookCreated {
-		fmt.Println("🪝 Post-create hook script created")
+		if result.HookTemplate != "" {
+			fmt.Printf("🪝 Post-create hook script created (%s)\n", result.HookTemplate)
+		} else {
+			fmt.Println("🪝 Post-create hook script created")
+		}
+	} else if hookTemplate != "" && hookTemplate != config.HookTemplateNone {
+		fmt.Println("🪝 Post-create hook already exists; left unchanged")
+	}
+
+	return nil
+}
+
+// initBare sets up the bare-clone layout: a clone of args[0] into args[1]
+// (default: the repository name), or, without arguments, the current
+// repository converted in place. It shows the plan and asks before touching
+// anything unless autoYes is set.
+func (c *CLI) initBare(args []string, dryRun, autoYes bool) error {
+	var plan *config.BareLayoutPlan
+	var err error
+	switch len(args) {
+	case 0:
+		plan, err = config.PlanBareConversion()
+	case 1, 2:
+		dir := ""
+		if len(args) == 2 {
+			dir = args[1]
+		}
+		plan, err = config.PlanBareClone(args[0], dir)
+	default:
+		return fmt.Errorf("usage: gren init --bare [<url> [dir]]")
+	}
+	if err != nil {
+		logging.Error("CLI init --bare: %v", err)
+		return err
+	}
+	logging.Info("CLI init --bare: root=%s url=%s branch=%s dry-run=%v", plan.Root, plan.URL, plan.Branch, dryRun)
+
+	if plan.URL != "" {
+		fmt.Printf("Set up %s in the bare-clone layout:\n", plan.Root)
+	} else {
+		fmt.Printf("Convert %s to the bare-clone layout:\n", plan.Root)
+	}
+	for i, step := range plan.Steps {
+		fmt.Printf("  %d. %s\n", i+1, step)
+	}
+
+	if dryRun {
+		fmt.Println("\nRun without --dry-run to apply.")
+		return nil
+	}
+	if !autoYes {
+		if !term.IsTerminal(int(os.Stdin.Fd())) {
+			return fmt.Errorf("refusing to change the repository layout without confirmation; re-run with -y")
+		}
+		fmt.Print("\nContinue? [y/N]: ")
+		var resp string
+		fmt.Scanln(&resp)
+		if r := strings.ToLower(strings.TrimSpace(resp)); r != "y" && r != "yes" {
+			fmt.Println("Cancelled")
+			return nil
+		}
 	}
 
+	if err := plan.Apply(); err != nil {
+		logging.Error("CLI init --bare failed: %v", err)
+		return fmt.Errorf("bare layout setup failed: %w", err)
+	}
+	output.Successf("Bare-clone layout ready; %s is checked out in %s", plan.Branch, plan.Worktree)
+	if plan.URL == "" {
+		fmt.Printf("Your files moved to %s; cd there to keep working.\n", plan.Worktree)
+	}
 	return nil
 }
 
 func (c *CLI) handleNavigate(args []string) error {
 	fs := flag.NewFlagSet("navigate", flag.ExitOnError)
+	create := fs.Bool("create", false, "Create the worktree (and the branch, if it doesn't exist) when none matches exactly")
+	autoYes := fs.Bool("y", false, "With --create: auto-approve hooks without prompting")
+	subshell := fs.Bool("subshell", false, "Without shell integration: start $SHELL in the worktree instead of printing a hint")
 
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: gren switch <branch-or-name>\n")
+		fmt.Fprintf(fs.Output(), "Usage: gren switch [--create] [--subshell] <branch-or-name>\n")
 		fmt.Fprintf(fs.Output(), "\nNavigate to a worktree by branch name or worktree name\n\n")
 		fmt.Fprintf(fs.Output(), "Special identifiers:\n")
 		fmt.Fprintf(fs.Output(), "  -   Switch to previous worktree (like cd -)\n")
@@ -1180,11 +2629,20 @@ func (c *CLI) handleNavigate(args []string) error {
 		fmt.Fprintf(fs.Output(), "  1. Exact worktree name match\n")
 		fmt.Fprintf(fs.Output(), "  2. Exact branch name match\n")
 		fmt.Fprintf(fs.Output(), "  3. Partial branch name match (e.g., 'auth' matches 'feature/auth')\n\n")
-		fmt.Fprintf(fs.Output(), "Examples:\n")
+		fmt.Fprintf(fs.Output(), "With --create, only exact matches count; otherwise a worktree is created for\n")
+		fmt.Fprintf(fs.Output(), "the branch (a new branch from the recommended base if it doesn't exist).\n\n")
+		fmt.Fprintf(fs.Output(), "Without shell integration gren can't change your shell's directory. With\n")
+		fmt.Fprintf(fs.Output(), "--subshell it starts a new $SHELL in the worktree instead; type exit to\n")
+		fmt.Fprintf(fs.Output(), "return to where you were. With shell integration --subshell does nothing.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
 		fmt.Fprintf(fs.Output(), "  gren switch feat-auth           # Switch by worktree name\n")
 		fmt.Fprintf(fs.Output(), "  gren switch feature/auth        # Switch by branch name\n")
 		fmt.Fprintf(fs.Output(), "  gren switch auth                # Partial match\n")
 		fmt.Fprintf(fs.Output(), "  gren switch -                   # Previous worktree\n")
+		fmt.Fprintf(fs.Output(), "  gren switch --create feature/x  # Switch, creating the worktree if missing\n")
+		fmt.Fprintf(fs.Output(), "  gren switch --subshell feat     # No shell integration: open a shell there\n")
 		fmt.Fprintf(fs.Output(), "  gren navigate feature-branch    # Alias\n")
 		fmt.Fprintf(fs.Output(), "  gren cd feature-branch          # Alias\n")
 	}
@@ -1200,7 +2658,7 @@ func (c *CLI) handleNavigate(args []string) error {
 	}
 
 	query := fs.Arg(0)
-	logging.Info("CLI navigate: query=%s", query)
+	logging.Info("CLI navigate: query=%s, create=%v", query, *create)
 
 	ctx := context.Background()
 	worktrees, err := c.worktreeManager.ListWorktrees(ctx)
@@ -1248,6 +2706,17 @@ func (c *CLI) handleNavigate(args []string) error {
 		}
 	default:
 		targetWorktree = findWorktreeByQuery(worktrees, query)
+		// --create names a branch: a partial match is some other branch
+		if *create && targetWorktree != nil && !strings.EqualFold(targetWorktree.Name, query) && !strings.EqualFold(targetWorktree.Branch, query) {
+			targetWorktree = nil
+		}
+		if *create {
+			if targetWorktree != nil {
+				output.Infof("Worktree for %s already exists", output.Bold(query))
+			} else if targetWorktree, err = c.createForSwitch(ctx, query, *autoYes); err != nil {
+				return err
+			}
+		}
 	}
 
 	if targetWorktree == nil {
@@ -1265,9 +2734,13 @@ func (c *CLI) handleNavigate(args []string) error {
 		_ = c.worktreeManager.SetPreviousWorktreePath(currentPath)
 	}
 
-	if err := directive.WriteCD(targetWorktree.Path); err != nil {
-		logging.Error("CLI navigate: failed to write navigation directive: %v", err)
-		return fmt.Errorf("failed to write navigation command: %w", err)
+	// Only the wrapper reads the directive; the subshell fallback needs none
+	useSubshell := *subshell && !directive.IsShellIntegrationActive()
+	if !useSubshell {
+		if err := directive.WriteCD(targetWorktree.Path); err != nil {
+			logging.Error("CLI navigate: failed to write navigation directive: %v", err)
+			return fmt.Errorf("failed to write navigation command: %w", err)
+		}
 	}
 
 	// Run post-switch hook with approval; stream phases live to stderr.
@@ -1276,6 +2749,10 @@ func (c *CLI) handleNavigate(args []string) error {
 	c.worktreeManager.SetEventObserver(nil)
 	printHookEvents(switchResults)
 
+	if useSubshell {
+		return runSubshell(targetWorktree)
+	}
+
 	logging.Info("CLI navigate: wrote navigation directive for path %s", targetWorktree.Path)
 
 	// Print styled output
@@ -1287,11 +2764,92 @@ func (c *CLI) handleNavigate(args []string) error {
 		output.Blank()
 		output.Hint("Shell integration not detected. Run:")
 		fmt.Printf("   eval \"$(gren shell-init zsh)\"  # or bash/fish\n")
+		output.Hint("Or use --subshell to open a shell in the worktree")
+	}
+	return nil
+}
+
+// EnvSubshell is set, to the worktree path, in the shell `gren switch
+// --subshell` starts, so prompts can show it and nested switches can warn.
+const EnvSubshell = "GREN_SUBSHELL"
+
+// runSubshell is the `gren switch --subshell` fallback for shells without
+// the wrapper: gren can't change its parent shell's directory, so it runs
+// $SHELL in the worktree and waits. Exiting that shell returns the user to
+// where they were, with its exit code.
+func runSubshell(wt *core.WorktreeInfo) error {
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		return fmt.Errorf("--subshell needs $SHELL to name your shell; set it or use shell integration (gren shell-init)")
+	}
+	logging.Info("CLI navigate: starting subshell %s in %s", shell, wt.Path)
+
+	if parent := os.Getenv(EnvSubshell); parent != "" {
+		output.Warningf("Already in a gren subshell for %s; exit it first to avoid nesting shells", parent)
+	}
+	output.Successf("Opening %s in %s", filepath.Base(shell), output.Bold(wt.Name))
+	fmt.Println("📂 " + output.Path(wt.Path))
+	output.Hint("This is a new shell; type exit to return. Shell integration changes directory in place instead.")
+
+	cmd := exec.Command(shell)
+	cmd.Dir = wt.Path
+	cmd.Env = append(os.Environ(), EnvSubshell+"="+wt.Path, "PWD="+wt.Path)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Ctrl-C belongs to the subshell, not gren waiting for it
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			code := exitErr.ExitCode()
+			if code < 0 {
+				code = 1 // killed by a signal
+			}
+			return &ExitCodeError{Code: code}
+		}
+		return fmt.Errorf("failed to start %s: %w", shell, err)
 	}
 	return nil
 }
 
+// createForSwitch creates the worktree `gren switch --create` asked for: on
+// branch if it exists locally or on origin, else on a new branch from the
+// recommended base. It says which before the caller navigates.
+func (c *CLI) createForSwitch(ctx context.Context, branch string, autoYes bool) (*core.WorktreeInfo, error) {
+	req := core.CreateWorktreeRequest{Name: branch, Branch: branch}
+	if status := c.worktreeManager.GetBranchSyncStatus(branch); !status.LocalExists && !status.RemoteExists {
+		base, err := c.worktreeManager.RecommendedBaseBranch(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine base branch: %w", err)
+		}
+		req.BaseBranch, req.IsNewBranch = base, true
+	}
+	logging.Info("CLI navigate: creating worktree for %s (new=%v, base=%s)", branch, req.IsNewBranch, req.BaseBranch)
+
+	worktreePath, _, err := c.createBranchWorktree(ctx, req, false, autoYes)
+	if err != nil {
+		return nil, err
+	}
+	if req.IsNewBranch {
+		output.Successf("Created worktree for new branch %s from %s", output.Bold(branch), req.BaseBranch)
+	} else {
+		output.Successf("Created worktree for existing branch %s", output.Bold(branch))
+	}
+	return &core.WorktreeInfo{Name: filepath.Base(worktreePath), Branch: branch, Path: worktreePath}, nil
+}
+
+// findWorktreeByQuery finds the worktree a navigation query names: by exact
+// name, exact branch, branch suffix (after / or -), partial branch, then by
+// path, either the worktree's own path (absolute, relative to the current
+// directory, or starting with ~) or its trailing components, such as
+// repo-worktrees/feat.
 func findWorktreeByQuery(worktrees []core.WorktreeInfo, query string) *core.WorktreeInfo {
+	pathQuery := query
 	query = strings.ToLower(query)
 
 	for i, wt := range worktrees {
@@ -1320,9 +2878,45 @@ func findWorktreeByQuery(worktrees []core.WorktreeInfo, query string) *core.Work
 		}
 	}
 
+	return findWorktreeByPath(worktrees, pathQuery)
+}
+
+// findWorktreeByPath finds the worktree at the path query, compared with
+// symlinks resolved, else the one whose path ends in query's components.
+func findWorktreeByPath(worktrees []core.WorktreeInfo, query string) *core.WorktreeInfo {
+	if query == "" {
+		return nil
+	}
+	if abs, err := filepath.Abs(config.ExpandHome(query)); err == nil {
+		target := resolvePath(abs)
+		for i, wt := range worktrees {
+			if resolvePath(wt.Path) == target {
+				return &worktrees[i]
+			}
+		}
+	}
+
+	suffix := filepath.Clean(query)
+	if filepath.IsAbs(suffix) || suffix == "." || strings.HasPrefix(suffix, "..") || strings.HasPrefix(suffix, "~") {
+		return nil
+	}
+	for i, wt := range worktrees {
+		if strings.HasSuffix(filepath.Clean(wt.Path), string(filepath.Separator)+suffix) {
+			return &worktrees[i]
+		}
+	}
 	return nil
 }
 
+// resolvePath returns path with symlinks resolved, or cleaned when it
+// doesn't exist.
+func resolvePath(path string) string {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		return resolved
+	}
+	return filepath.Clean(path)
+}
+
 func getCurrentWorktreePath(worktrees []core.WorktreeInfo) string {
 	for _, wt := range worktrees {
 		if wt.IsCurrent {
@@ -1398,7 +2992,12 @@ if command -v gren >/dev/null 2>&1 || [[ -n "${GREN_BIN:-}" ]]; then
         GREN_DIRECTIVE_FILE="$directive_file" command "${GREN_BIN:-gren}" "$@" || exit_code=$?
 
         if [[ -s "$directive_file" ]]; then
-            source "$directive_file"
+            if [[ "${GREN_DIRECTIVE_FORMAT:-}" == json ]]; then
+                # Structured directives (for other tools): have gren turn them into shell
+                eval "$(command "${GREN_BIN:-gren}" __directive "$directive_file")"
+            else
+                source "$directive_file"
+            fi
             # Show new directory if we changed
             if [[ "$PWD" != "$OLDPWD" ]]; then
                 echo "📂 Now in: $(pwd)"
@@ -1442,7 +3041,12 @@ if command -v gren >/dev/null 2>&1; or set -q GREN_BIN
         or set exit_code $status
 
         if test -s $directive_file
-            source $directive_file
+            if test "$GREN_DIRECTIVE_FORMAT" = json
+                # Structured directives (for other tools): have gren turn them into shell
+                command $gren_bin __directive $directive_file | source
+            else
+                source $directive_file
+            end
             # Show new directory if we changed
             if test "$PWD" != "$old_pwd"
                 echo "📂 Now in: "(pwd)
@@ -1459,39 +3063,89 @@ if command -v gren >/dev/null 2>&1; or set -q GREN_BIN
 end
 `
 
+// handleDirectiveShell prints the shell commands for a directive file
+// written with GREN_DIRECTIVE_FORMAT=json, so the shell wrappers can carry
+// out the structured form too. Internal: `gren __directive <file>`.
+func (c *CLI) handleDirectiveShell(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: gren __directive <file>")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	directives, err := directive.ParseJSON(f)
+	if err != nil {
+		return err
+	}
+	for _, d := range directives {
+		fmt.Println(d.Shell())
+	}
+	return nil
+}
+
+// parseInterspersed parses fs allowing flags after positional arguments
+// (`gren compare feat --diff`), which the flag package stops at otherwise.
+// It returns the positional arguments in order.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			return positional, nil
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
 // handleCompare handles the compare command
 func (c *CLI) handleCompare(args []string) error {
 	fs := flag.NewFlagSet("compare", flag.ExitOnError)
 	diff := fs.Bool("diff", false, "Show unified diff output for all files")
-	apply := fs.Bool("apply", false, "Apply all changes from source to current worktree")
+	apply := fs.Bool("apply", false, "Apply all changes from source to target worktree")
+	reverse := fs.Bool("reverse", false, "Swap source and target")
 
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: gren compare <worktree-name> [options]\n")
-		fmt.Fprintf(fs.Output(), "\nCompare changes between worktrees\n\n")
+		fmt.Fprintf(fs.Output(), "Usage: gren compare <source> [target] [options]\n")
+		fmt.Fprintf(fs.Output(), "\nCompare changes between worktrees. Target defaults to the current worktree.\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
 		fmt.Fprintf(fs.Output(), "  gren compare feature-branch           # List changed files\n")
 		fmt.Fprintf(fs.Output(), "  gren compare feature-branch --diff    # Show diff output\n")
 		fmt.Fprintf(fs.Output(), "  gren compare feature-branch --apply   # Apply all changes\n")
+		fmt.Fprintf(fs.Output(), "  gren compare feat-a feat-b --diff     # Diff two worktrees from anywhere\n")
+		fmt.Fprintf(fs.Output(), "  gren compare feature-branch --reverse # What current has that feature-branch lacks\n")
 	}
 
-	if err := fs.Parse(args); err != nil {
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
 		return err
 	}
 
-	if fs.NArg() == 0 {
+	if len(positional) == 0 || len(positional) > 2 {
 		fs.Usage()
 		return fmt.Errorf("worktree name is required")
 	}
 
-	sourceWorktree := fs.Arg(0)
+	// An empty name means the current worktree on either side.
+	sourceWorktree, targetWorktree := positional[0], ""
+	if len(positional) == 2 {
+		targetWorktree = positional[1]
+	}
+	if *reverse {
+		sourceWorktree, targetWorktree = targetWorktree, sourceWorktree
+	}
 	ctx := context.Background()
 
-	logging.Info("CLI compare: comparing %s to current worktree", sourceWorktree)
+	logging.Info("CLI compare: comparing %q to %q (empty = current)", sourceWorktree, targetWorktree)
 
 	// Get the comparison result
-	result, err := c.worktreeManager.CompareWorktrees(ctx, sourceWorktree)
+	result, err := c.worktreeManager.CompareWorktreePair(ctx, sourceWorktree, targetWorktree)
 	if err != nil {
 		return fmt.Errorf("compare failed: %w", err)
 	}
@@ -1503,8 +3157,8 @@ func (c *CLI) handleCompare(args []string) error {
 
 	// Handle apply mode
 	if *apply {
-		fmt.Printf("Applying %d file(s) from %s...\n", len(result.Files), sourceWorktree)
-		if err := c.worktreeManager.ApplyChanges(ctx, sourceWorktree, result.Files); err != nil {
+		fmt.Printf("Applying %d file(s) from %s to %s...\n", len(result.Files), result.SourceWorktree, result.TargetWorktree)
+		if err := c.worktreeManager.ApplyChangesTo(ctx, result.SourcePath, result.TargetPath, result.Files); err != nil {
 			return fmt.Errorf("apply failed: %w", err)
 		}
 		fmt.Printf("Successfully applied %d file(s)\n", len(result.Files))
@@ -1513,7 +3167,7 @@ func (c *CLI) handleCompare(args []string) error {
 
 	// Handle diff mode
 	if *diff {
-		return c.showCompareWithDiff(sourceWorktree, result)
+		return c.showCompareWithDiff(result)
 	}
 
 	// Default: show file list
@@ -1541,36 +3195,15 @@ func (c *CLI) handleCompare(args []string) error {
 }
 
 // showCompareWithDiff shows the comparison with unified diff output
-func (c *CLI) showCompareWithDiff(sourceWorktree string, result *core.CompareResult) error {
-	ctx := context.Background()
-
-	// Get worktree paths
-	worktrees, err := c.worktreeManager.ListWorktrees(ctx)
-	if err != nil {
-		return err
-	}
-
-	var sourcePath, currentPath string
-	for _, wt := range worktrees {
-		if wt.Name == sourceWorktree || wt.Path == sourceWorktree {
-			sourcePath = wt.Path
-		}
-		if wt.IsCurrent {
-			currentPath = wt.Path
-		}
-	}
-
-	// Validate paths were found
-	if sourcePath == "" {
-		return fmt.Errorf("source worktree '%s' not found", sourceWorktree)
-	}
-	if currentPath == "" {
-		return fmt.Errorf("current worktree not found")
-	}
+func (c *CLI) showCompareWithDiff(result *core.CompareResult) error {
+	sourcePath, currentPath := result.SourcePath, result.TargetPath
 
 	fmt.Printf("Changes from %s → %s:\n", result.SourceWorktree, result.TargetWorktree)
 	fmt.Println(strings.Repeat("=", 60))
 
+	// Only color when a human is looking; piped output stays plain for patch/grep.
+	color := isTerminal()
+
 	for _, file := range result.Files {
 		fmt.Printf("\n--- %s ---\n", file.Path)
 
@@ -1588,11 +3221,14 @@ func (c *CLI) showCompareWithDiff(sourceWorktree string, result *core.CompareRes
 			content, err := os.ReadFile(srcFile)
 			if err != nil {
 				fmt.Printf("[Error reading file: %v]\n", err)
+			} else if output.IsBinary(content) {
+				printDiffLines([]string{output.BinaryDiffMarker}, color)
 			} else {
-				lines := strings.Split(string(content), "\n")
-				for _, line := range lines {
-					fmt.Printf("+ %s\n", line)
+				lines := strings.Split(strings.TrimSuffix(string(content), "\n"), "\n")
+				for i, line := range lines {
+					lines[i] = "+ " + line
 				}
+				printDiffLines(lines, color)
 			}
 		case core.FileDeleted:
 			fmt.Println("[DELETED]")
@@ -1601,9 +3237,9 @@ func (c *CLI) showCompareWithDiff(sourceWorktree string, result *core.CompareRes
 			currentFile := filepath.Join(currentPath, file.Path)
 			sourceFile := filepath.Join(sourcePath, file.Path)
 			cmd := exec.Command("git", "diff", "--no-index", "--", currentFile, sourceFile)
-			output, _ := cmd.CombinedOutput()
-			if len(output) > 0 {
-				fmt.Println(string(output))
+			diffOut, _ := cmd.CombinedOutput()
+			if len(diffOut) > 0 {
+				printDiffLines(output.PrepareDiff(string(diffOut), output.MaxDiffLines), color)
 			} else {
 				fmt.Println("[Binary or no diff available]")
 			}
@@ -1615,6 +3251,16 @@ func (c *CLI) showCompareWithDiff(sourceWorktree string, result *core.CompareRes
 	return nil
 }
 
+// printDiffLines writes diff lines to stdout, coloring them when color is set.
+func printDiffLines(lines []string, color bool) {
+	for _, line := range lines {
+		if color {
+			line = output.DiffLine(line)
+		}
+		fmt.Println(line)
+	}
+}
+
 func validateFilePath(path string) error {
 	if strings.Contains(path, "..") {
 		return fmt.Errorf("invalid path (contains '..'): %s", path)
@@ -1644,6 +3290,8 @@ func (c *CLI) handleMarker(args []string) error {
 		fmt.Println("  gren marker set waiting -branch feat # Set on specific branch")
 		fmt.Println("  gren marker clear                    # Clear current branch marker")
 		fmt.Println("  gren marker clear --all              # Clear all markers")
+		fmt.Println("  gren marker clear --expired          # Clear markers older than marker_ttl")
+		fmt.Println("\nWorking and waiting markers older than marker_ttl (default 2h) read as idle.")
 		fmt.Println("\nUse 'gren marker <subcommand> --help' for more information.")
 	}
 
@@ -1715,7 +3363,7 @@ func (c *CLI) handleMarkerSet(args []string) error {
 	}
 
 	ctx := context.Background()
-	mm := core.NewMarkerManager()
+	mm := c.worktreeManager.MarkerManager()
 	if err := mm.SetMarker(ctx, targetBranch, markerType); err != nil {
 		return err
 	}
@@ -1729,9 +3377,10 @@ func (c *CLI) handleMarkerClear(args []string) error {
 	fs := flag.NewFlagSet("marker clear", flag.ExitOnError)
 	branch := fs.String("branch", "", "Branch name (defaults to current branch)")
 	all := fs.Bool("all", false, "Clear all markers in the repository")
+	expired := fs.Bool("expired", false, "Clear all markers older than marker_ttl (default 2h)")
 
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: gren marker clear [-branch <name>] [-all]\n")
+		fmt.Fprintf(fs.Output(), "Usage: gren marker clear [-branch <name>] [-all | -expired]\n")
 		fmt.Fprintf(fs.Output(), "\nClear Claude activity marker for a branch\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
@@ -1742,7 +3391,27 @@ func (c *CLI) handleMarkerClear(args []string) error {
 	}
 
 	ctx := context.Background()
-	mm := core.NewMarkerManager()
+	mm := c.worktreeManager.MarkerManager()
+
+	if *all && *expired {
+		return fmt.Errorf("--all and --expired cannot be used together")
+	}
+
+	if *expired {
+		cleared, err := mm.ClearExpiredMarkers(ctx)
+		if err != nil {
+			return err
+		}
+		logging.Info("CLI marker clear: cleared %d expired markers", len(cleared))
+		if len(cleared) == 0 {
+			fmt.Println("No expired markers")
+			return nil
+		}
+		for _, b := range cleared {
+			fmt.Printf("Cleared marker for %s\n", b)
+		}
+		return nil
+	}
 
 	if *all {
 		if err := mm.ClearAllMarkers(ctx); err != nil {
@@ -1796,7 +3465,7 @@ func (c *CLI) handleMarkerGet(args []string) error {
 		targetBranch = currentBranch
 	}
 
-	mm := core.NewMarkerManager()
+	mm := c.worktreeManager.MarkerManager()
 	marker, err := mm.GetMarker(ctx, targetBranch)
 	if err != nil {
 		return err
@@ -1812,7 +3481,7 @@ func (c *CLI) handleMarkerGet(args []string) error {
 
 func (c *CLI) handleMarkerList() error {
 	ctx := context.Background()
-	mm := core.NewMarkerManager()
+	mm := c.worktreeManager.MarkerManager()
 
 	markers, err := mm.ListMarkers(ctx)
 	if err != nil {
@@ -1824,13 +3493,43 @@ func (c *CLI) handleMarkerList() error {
 		return nil
 	}
 
+	// Markers carries the timestamps; ListMarkers the types to show
+	stored, err := mm.Markers(ctx)
+	if err != nil {
+		return err
+	}
+	branches := make([]string, 0, len(markers))
+	for branch := range markers {
+		branches = append(branches, branch)
+	}
+	sort.Strings(branches)
+
 	fmt.Println("Branch markers:")
-	for branch, marker := range markers {
-		fmt.Printf("  %s %s\n", marker, branch)
+	for _, branch := range branches {
+		if ts := stored[branch].Timestamp; !ts.IsZero() {
+			fmt.Printf("  %s %s (%s)\n", markers[branch], branch, markerAge(time.Since(ts)))
+		} else {
+			fmt.Printf("  %s %s\n", markers[branch], branch)
+		}
 	}
 	return nil
 }
 
+// markerAge shortens how long ago a marker was set to the style of the
+// worktree list's commit times: "45s ago", "12m ago", "3h ago", "2d ago".
+func markerAge(d time.Duration) string {
+	switch {
+	case d < time.Minute:
+		return fmt.Sprintf("%ds ago", int(d.Seconds()))
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+	}
+}
+
 func (c *CLI) handleSetupClaudePlugin(args []string) error {
 	fs := flag.NewFlagSet("setup-claude-plugin", flag.ExitOnError)
 	force := fs.Bool("f", false, "Overwrite existing files")
@@ -1893,6 +3592,9 @@ func (c *CLI) handleStatusline(args []string) error {
 	var parts []string
 	parts = append(parts, current.Branch)
 
+	if current.StatusError != "" {
+		parts = append(parts, "status?")
+	}
 	if current.StagedCount > 0 {
 		parts = append(parts, fmt.Sprintf("+%d", current.StagedCount))
 	}
@@ -1918,6 +3620,9 @@ func (c *CLI) handleMerge(args []string) error {
 	noRebase := fs.Bool("no-rebase", false, "Skip rebase (fail if not already rebased)")
 	yes := fs.Bool("y", false, "Skip confirmation prompts")
 	force := fs.Bool("f", false, "Force merge even with uncommitted changes")
+	intoCurrent := fs.Bool("into-current", false, "Merge target into the current branch instead (git merge, no squash, rebase or hooks)")
+	abortOnConflict := fs.Bool("abort-on-conflict", false, "With --into-current, abort the merge if it hits conflicts")
+	dryRun := fs.Bool("dry-run", false, "Show what the merge would do without doing it")
 
 	fs.Usage = func() {
 		fmt.Fprintf(fs.Output(), "Usage: gren merge [target] [options]\n")
@@ -1930,6 +3635,9 @@ func (c *CLI) handleMerge(args []string) error {
 		fmt.Fprintf(fs.Output(), "  5. Fast-forward merge to target\n")
 		fmt.Fprintf(fs.Output(), "  6. Remove worktree and branch (unless --no-remove)\n")
 		fmt.Fprintf(fs.Output(), "  7. Run post-merge hooks\n\n")
+		fmt.Fprintf(fs.Output(), "A rebase that hits conflicts is aborted and the conflicted files listed.\n")
+		fmt.Fprintf(fs.Output(), "With --into-current, target is merged into the current branch instead;\n")
+		fmt.Fprintf(fs.Output(), "conflicts are left to resolve unless --abort-on-conflict is given.\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
@@ -1937,6 +3645,9 @@ func (c *CLI) handleMerge(args []string) error {
 		fmt.Fprintf(fs.Output(), "  gren merge main             # Merge to main\n")
 		fmt.Fprintf(fs.Output(), "  gren merge --no-remove      # Keep worktree after merge\n")
 		fmt.Fprintf(fs.Output(), "  gren merge --no-squash      # Preserve commit history\n")
+		fmt.Fprintf(fs.Output(), "  gren merge --dry-run        # Preview the squash, merge and removal\n")
+		fmt.Fprintf(fs.Output(), "  gren merge --into-current   # Bring the default branch into this one\n")
+		fmt.Fprintf(fs.Output(), "  gren merge --into-current --abort-on-conflict main\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
@@ -1948,16 +3659,23 @@ func (c *CLI) handleMerge(args []string) error {
 		target = fs.Arg(0)
 	}
 
+	if *abortOnConflict && !*intoCurrent {
+		return fmt.Errorf("--abort-on-conflict requires --into-current; a conflicted rebase is always aborted")
+	}
+
 	ctx := context.Background()
 
 	opts := core.MergeOptions{
-		Target: target,
-		Squash: !*noSquash,
-		Remove: !*noRemove,
-		Verify: !*noVerify,
-		Rebase: !*noRebase,
-		Yes:    *yes,
-		Force:  *force,
+		Target:          target,
+		Squash:          !*noSquash,
+		Remove:          !*noRemove,
+		Verify:          !*noVerify,
+		Rebase:          !*noRebase,
+		Yes:             *yes,
+		Force:           *force,
+		IntoCurrent:     *intoCurrent,
+		AbortOnConflict: *abortOnConflict,
+		DryRun:          *dryRun,
 	}
 
 	result, err := c.worktreeManager.Merge(ctx, opts)
@@ -1967,16 +3685,38 @@ func (c *CLI) handleMerge(args []string) error {
 
 	if result.Skipped {
 		fmt.Printf("⏭️  Merge skipped: %s\n", result.SkipReason)
+		output.PrintSummary(output.Summary{Verb: "merged", Skipped: 1})
+		return nil
+	}
+
+	if result.DryRun {
+		if *intoCurrent {
+			fmt.Printf("Merge %s into %s:\n", result.TargetBranch, result.SourceBranch)
+		} else {
+			fmt.Printf("Merge %s into %s:\n", result.SourceBranch, result.TargetBranch)
+		}
+		for i, step := range result.Steps {
+			fmt.Printf("  %d. %s\n", i+1, step)
+		}
+		fmt.Println("\nRun without --dry-run to merge.")
 		return nil
 	}
 
-	fmt.Printf("✅ Merged %s into %s\n", result.SourceBranch, result.TargetBranch)
+	if *intoCurrent {
+		fmt.Printf("✅ Merged %s into %s\n", result.TargetBranch, result.SourceBranch)
+	} else {
+		fmt.Printf("✅ Merged %s into %s\n", result.SourceBranch, result.TargetBranch)
+	}
 	if result.CommitsSquashed > 0 {
 		fmt.Printf("   Squashed %d commits\n", result.CommitsSquashed)
 	}
 	if result.WorktreeRemoved {
 		fmt.Printf("   Removed worktree: %s\n", result.WorktreePath)
 	}
+	for _, warning := range result.Warnings {
+		output.Warning(warning)
+	}
+	output.PrintSummary(output.Summary{Verb: "merged", Done: 1, Warnings: len(result.Warnings)})
 
 	return nil
 }
@@ -1994,7 +3734,7 @@ func (c *CLI) handleForEach(args []string) error {
 		fs.PrintDefaults()
 		fmt.Fprintf(fs.Output(), "\nTemplate variables:\n")
 		fmt.Fprintf(fs.Output(), "  {{ branch }}           Branch name\n")
-		fmt.Fprintf(fs.Output(), "  {{ branch | sanitize }} Branch with / replaced by -\n")
+		fmt.Fprintf(fs.Output(), "  {{ branch | sanitize }} Branch as a directory name (/, :, # → -)\n")
 		fmt.Fprintf(fs.Output(), "  {{ worktree }}         Absolute path to worktree\n")
 		fmt.Fprintf(fs.Output(), "  {{ worktree_name }}    Worktree directory name\n")
 		fmt.Fprintf(fs.Output(), "  {{ repo }}             Repository name\n")
@@ -2010,163 +3750,815 @@ func (c *CLI) handleForEach(args []string) error {
 		fmt.Fprintf(fs.Output(), "  gren for-each --fail-fast -- npm test\n")
 	}
 
-	// Check for help flag before looking for -- separator
-	for _, arg := range args {
-		if arg == "--help" || arg == "-h" || arg == "help" {
-			fs.Usage()
-			return nil
-		}
+	// Check for help flag before looking for -- separator
+	for _, arg := range args {
+		if arg == "--help" || arg == "-h" || arg == "help" {
+			fs.Usage()
+			return nil
+		}
+	}
+
+	dashIndex := -1
+	for i, arg := range args {
+		if arg == "--" {
+			dashIndex = i
+			break
+		}
+	}
+
+	if dashIndex == -1 {
+		fs.Usage()
+		return fmt.Errorf("missing -- separator before command")
+	}
+
+	if err := fs.Parse(args[:dashIndex]); err != nil {
+		return err
+	}
+
+	command := args[dashIndex+1:]
+	if len(command) == 0 {
+		fs.Usage()
+		return fmt.Errorf("no command provided")
+	}
+
+	ctx := context.Background()
+
+	opts := core.ForEachOptions{
+		Command:     command,
+		SkipCurrent: *skipCurrent,
+		SkipMain:    *skipMain,
+		FailFast:    *failFast,
+	}
+
+	results, err := c.worktreeManager.ForEach(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	successCount := 0
+	failCount := 0
+
+	for _, r := range results {
+		fmt.Printf("\n\033[1m%s\033[0m (%s)\n", r.Worktree.Branch, r.Worktree.Path)
+		fmt.Print(r.Output)
+
+		if r.Error != nil {
+			fmt.Printf("\033[31m✗ Exit code: %d\033[0m\n", r.ExitCode)
+			failCount++
+		} else {
+			successCount++
+		}
+	}
+
+	fmt.Printf("\n---\n")
+	fmt.Printf("✅ %d succeeded", successCount)
+	if failCount > 0 {
+		fmt.Printf(", \033[31m✗ %d failed\033[0m", failCount)
+	}
+	fmt.Println()
+
+	if failCount > 0 {
+		return fmt.Errorf("%d worktree(s) failed", failCount)
+	}
+
+	return nil
+}
+
+// handleExec runs a command in one worktree, found like `gren switch` finds
+// it, with the command's output and exit code passed straight through.
+func (c *CLI) handleExec(args []string) error {
+	fs := flag.NewFlagSet("exec", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren exec <name> -- <command>\n")
+		fmt.Fprintf(fs.Output(), "\nRun a command in one worktree without cd-ing there. gren exits with the\n")
+		fmt.Fprintf(fs.Output(), "command's exit code. A single quoted argument runs through sh, and the\n")
+		fmt.Fprintf(fs.Output(), "for-each template variables ({{ branch }}, {{ worktree }}, ...) are expanded.\n")
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren exec feat-auth -- npm test\n")
+		fmt.Fprintf(fs.Output(), "  gren exec auth -- git log --oneline -5\n")
+		fmt.Fprintf(fs.Output(), "  gren exec feat-auth -- \"npm run build && npm test\"\n")
+	}
+
+	dashIndex := slices.Index(args, "--")
+	if dashIndex == -1 {
+		if slices.ContainsFunc(args, func(arg string) bool { return arg == "--help" || arg == "-h" || arg == "help" }) {
+			fs.Usage()
+			return nil
+		}
+		fs.Usage()
+		return fmt.Errorf("missing -- separator before command")
+	}
+
+	positional, err := parseInterspersed(fs, args[:dashIndex])
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("exactly one worktree name is required")
+	}
+	command := args[dashIndex+1:]
+	if len(command) == 0 {
+		fs.Usage()
+		return fmt.Errorf("no command provided")
+	}
+
+	ctx := context.Background()
+	worktrees, err := c.worktreeManager.ListWorktreesBasic(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	wt := findWorktreeByQuery(worktrees, positional[0])
+	if wt == nil {
+		return fmt.Errorf("worktree '%s' not found", positional[0])
+	}
+	if wt.IsBare {
+		return fmt.Errorf("'%s' is the bare repository, which has no working tree to run in", wt.Name)
+	}
+	logging.Info("CLI exec: worktree=%s, command=%v", wt.Name, command)
+
+	cmd := c.worktreeManager.WorktreeCommand(ctx, wt, command)
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	// Ctrl-C reaches the command through the terminal; gren stays to report
+	// how it exited
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt)
+	defer signal.Stop(signals)
+
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			logging.Info("CLI exec: %s exited with %d", wt.Name, exitErr.ExitCode())
+			code := exitErr.ExitCode()
+			if code < 0 {
+				code = 1 // killed by a signal
+			}
+			return &ExitCodeError{Code: code}
+		}
+		return fmt.Errorf("failed to run %s in %s: %w", command[0], wt.Name, err)
+	}
+	return nil
+}
+
+// handleDiff shows all changes on the current branch since it diverged from the
+// default (or specified) base branch: committed, staged, unstaged, and untracked.
+func (c *CLI) handleDiff(args []string) error {
+	fs := flag.NewFlagSet("diff", flag.ExitOnError)
+	base := fs.String("base", "", "Base branch to diff against (default: auto-detect default branch)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren diff [options]\n")
+		fmt.Fprintf(fs.Output(), "\nShow all changes since branching from the base branch\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren diff\n")
+		fmt.Fprintf(fs.Output(), "  gren diff --base main\n")
+		fmt.Fprintf(fs.Output(), "  gren diff | delta\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Determine base branch
+	baseBranch := *base
+	if baseBranch == "" {
+		ctx := context.Background()
+		recommended, err := c.worktreeManager.RecommendedBaseBranch(ctx)
+		if err != nil || recommended == "" {
+			return fmt.Errorf("could not determine default branch; use --base to specify one")
+		}
+		baseBranch = recommended
+	}
+
+	// Verify base branch exists
+	if err := exec.Command("git", "rev-parse", "--verify", baseBranch).Run(); err != nil {
+		return fmt.Errorf("base branch %q not found", baseBranch)
+	}
+
+	// Find merge-base (divergence point)
+	mergeBaseOut, err := exec.Command("git", "merge-base", "HEAD", baseBranch).Output()
+	if err != nil {
+		return fmt.Errorf("failed to find merge-base with %q: %w", baseBranch, err)
+	}
+	mergeBase := strings.TrimSpace(string(mergeBaseOut))
+
+	// 1. Committed changes since merge-base
+	committedCmd := exec.Command("git", "diff", mergeBase, "HEAD")
+	committedCmd.Stdout = os.Stdout
+	committedCmd.Stderr = os.Stderr
+	if err := committedCmd.Run(); err != nil {
+		return fmt.Errorf("git diff (committed) failed: %w", err)
+	}
+
+	// 2. Staged changes (index vs HEAD)
+	stagedCmd := exec.Command("git", "diff", "--cached")
+	stagedCmd.Stdout = os.Stdout
+	stagedCmd.Stderr = os.Stderr
+	if err := stagedCmd.Run(); err != nil {
+		return fmt.Errorf("git diff --cached (staged) failed: %w", err)
+	}
+
+	// 3. Unstaged changes (working tree vs index)
+	unstagedCmd := exec.Command("git", "diff")
+	unstagedCmd.Stdout = os.Stdout
+	unstagedCmd.Stderr = os.Stderr
+	if err := unstagedCmd.Run(); err != nil {
+		return fmt.Errorf("git diff (unstaged) failed: %w", err)
+	}
+
+	// 4. Untracked files (shown as new-file diffs)
+	untrackedOut, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return fmt.Errorf("git ls-files failed: %w", err)
+	}
+	for _, file := range strings.Split(strings.TrimSpace(string(untrackedOut)), "\n") {
+		if file == "" {
+			continue
+		}
+		untrackedCmd := exec.Command("git", "diff", "--no-index", os.DevNull, file)
+		untrackedCmd.Stdout = os.Stdout
+		// git diff --no-index exits 1 when files differ (always for new files), ignore
+		_ = untrackedCmd.Run()
+	}
+
+	return nil
+}
+
+// handleSetUpstream configures the tracking branch for a worktree's branch.
+func (c *CLI) handleSetUpstream(args []string) error {
+	fs := flag.NewFlagSet("set-upstream", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren set-upstream <name> [remote]\n")
+		fmt.Fprintf(fs.Output(), "\nSet the upstream of a worktree's branch to <remote>/<branch> (default remote: origin).\n")
+		fmt.Fprintf(fs.Output(), "Use after pushing a branch that was created local-only, so ahead/behind and push work.\n")
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren set-upstream feat-auth            # track origin/feat-auth\n")
+		fmt.Fprintf(fs.Output(), "  gren set-upstream feat-auth upstream   # track upstream/feat-auth\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() == 0 || fs.NArg() > 2 {
+		fs.Usage()
+		return fmt.Errorf("worktree name is required")
+	}
+
+	remote := ""
+	if fs.NArg() == 2 {
+		remote = fs.Arg(1)
+	}
+
+	logging.Info("CLI set-upstream: worktree=%s, remote=%s", fs.Arg(0), remote)
+
+	result, err := c.worktreeManager.SetUpstream(context.Background(), fs.Arg(0), remote)
+	if err != nil {
+		return err
+	}
+
+	switch result.Previous {
+	case "":
+		output.Successf("%s now tracks %s", result.Branch, result.Upstream)
+	case result.Upstream:
+		output.Successf("%s already tracks %s", result.Branch, result.Upstream)
+	default:
+		output.Successf("%s now tracks %s (was %s)", result.Branch, result.Upstream, result.Previous)
+	}
+	return nil
+}
+
+func (c *CLI) handlePush(args []string) error {
+	fs := flag.NewFlagSet("push", flag.ExitOnError)
+	setUpstream := fs.Bool("set-upstream", false, "Track <remote>/<branch> after pushing, even if the branch tracks something else")
+	fs.BoolVar(setUpstream, "u", false, "Shorthand for --set-upstream")
+	forceWithLease := fs.Bool("force-with-lease", false, "Overwrite the remote branch if nobody else pushed to it (after a rebase)")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren push <name> [remote] [--set-upstream] [--force-with-lease]\n")
+		fmt.Fprintf(fs.Output(), "\nPush a worktree's branch without changing into it. A branch without an\n")
+		fmt.Fprintf(fs.Output(), "upstream is pushed to <remote>/<branch> (default remote: origin) and set to\n")
+		fmt.Fprintf(fs.Output(), "track it; otherwise it is pushed to the branch it tracks.\n\n")
+		fmt.Fprintf(fs.Output(), "The worktree is matched like gren switch (name, branch, partial branch, path).\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren push feat-auth                     # push, tracking origin/feat-auth on first push\n")
+		fmt.Fprintf(fs.Output(), "  gren push feat-auth --force-with-lease  # after a rebase\n")
+		fmt.Fprintf(fs.Output(), "  gren push feat-auth fork -u             # push to the fork remote and track it\n")
+	}
+
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) == 0 || len(positional) > 2 {
+		fs.Usage()
+		return fmt.Errorf("worktree name is required")
+	}
+
+	opts := core.PushOptions{SetUpstream: *setUpstream, ForceWithLease: *forceWithLease}
+	if len(positional) == 2 {
+		opts.Remote = positional[1]
+	}
+
+	ctx := context.Background()
+	worktrees, err := c.worktreeManager.ListWorktreesBasic(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	wt := findWorktreeByQuery(worktrees, positional[0])
+	if wt == nil {
+		return fmt.Errorf("worktree '%s' not found", positional[0])
+	}
+	logging.Info("CLI push: worktree=%s, remote=%s, set-upstream=%v, force-with-lease=%v", wt.Name, opts.Remote, opts.SetUpstream, opts.ForceWithLease)
+
+	result, err := c.worktreeManager.PushWorktree(ctx, wt, opts)
+	if err != nil {
+		return err
+	}
+
+	pushed := "nothing new"
+	switch result.Commits {
+	case 0:
+	case 1:
+		pushed = "1 commit"
+	default:
+		pushed = fmt.Sprintf("%d commits", result.Commits)
+	}
+	verb := "Pushed"
+	if result.Forced {
+		verb = "Force-pushed"
+	}
+	output.Successf("%s %s to %s (%s)", verb, output.Bold(result.Branch), result.Target, pushed)
+	if result.NewUpstream {
+		output.Successf("%s now tracks %s", result.Branch, result.Target)
+	}
+	return nil
+}
+
+func (c *CLI) handleReattach(args []string) error {
+	fs := flag.NewFlagSet("reattach", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren reattach <name> <branch>\n")
+		fmt.Fprintf(fs.Output(), "\nCreate <branch> at a detached worktree's HEAD and check it out in place (git switch -c).\n")
+		fmt.Fprintf(fs.Output(), "Uncommitted changes are kept. The branch must not already exist.\n")
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren reattach review-123 fix/review-123   # put the detached worktree on a new branch\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return fmt.Errorf("worktree name and branch are required")
+	}
+
+	logging.Info("CLI reattach: worktree=%s, branch=%s", fs.Arg(0), fs.Arg(1))
+
+	result, err := c.worktreeManager.Reattach(context.Background(), fs.Arg(0), fs.Arg(1))
+	if err != nil {
+		return err
+	}
+
+	output.Successf("%s is now on branch %s (at %s)", result.Worktree, output.Bold(result.Branch), result.Commit)
+	return nil
+}
+
+func (c *CLI) handleRelocate(args []string) error {
+	fs := flag.NewFlagSet("relocate", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "Show what would be moved without moving anything")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren relocate [name...] [--dry-run]\n")
+		fmt.Fprintf(fs.Output(), "\nMove worktrees to where worktree_dir puts them now (git worktree move),\n")
+		fmt.Fprintf(fs.Output(), "e.g. after changing worktree_dir or its template. Worktrees already in\n")
+		fmt.Fprintf(fs.Output(), "place are left alone. A worktree with another branch checked out than the\n")
+		fmt.Fprintf(fs.Output(), "one it was created on is renamed after its branch. Without names, every\n")
+		fmt.Fprintf(fs.Output(), "worktree is checked.\n")
+		fmt.Fprintf(fs.Output(), "\nOptions:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren relocate --dry-run    # See which worktrees would move where\n")
+		fmt.Fprintf(fs.Output(), "  gren relocate              # Move them\n")
+		fmt.Fprintf(fs.Output(), "  gren relocate feature-x    # Move just one\n")
+	}
+
+	names, err := parseInterspersed(fs, args)
+	if err != nil {
+		return err
+	}
+	logging.Info("CLI relocate: names=%v dryRun=%v", names, *dryRun)
+
+	ctx := context.Background()
+	plan, err := c.worktreeManager.PlanRelocations(ctx, names...)
+	if err != nil {
+		return err
+	}
+	if len(plan) == 0 {
+		fmt.Println("All worktrees are where worktree_dir puts them")
+		return nil
+	}
+
+	if *dryRun {
+		fmt.Printf("Would move %d worktree(s):\n", len(plan))
+		for _, r := range plan {
+			fmt.Printf("  - %s: %s → %s\n", r.Worktree, output.Path(r.From), output.Path(r.To))
+		}
+		return nil
+	}
+
+	summary := output.Summary{Verb: "moved"}
+	for _, r := range plan {
+		result, err := c.worktreeManager.MoveWorktree(ctx, r.From, r.To)
+		if err != nil {
+			output.Errorf("%s: %v", r.Worktree, err)
+			summary.Failed++
+			continue
+		}
+		fmt.Printf("  - %s: %s → %s\n", r.Worktree, output.Path(result.From), output.Path(result.To))
+		if len(result.Relinked) > 0 {
+			fmt.Printf("    re-pointed %s\n", strings.Join(result.Relinked, ", "))
+		}
+		summary.Done++
+		if result.Current {
+			// The shell is still in the old, now deleted, directory
+			if err := directive.WriteCD(result.To); err != nil {
+				logging.Error("CLI relocate: failed to write navigation directive: %v", err)
+			}
+			if !directive.IsShellIntegrationActive() {
+				output.Hintf("You are in the worktree that moved: cd %s", result.To)
+			}
+		}
+	}
+	output.PrintSummary(summary)
+	if summary.Failed > 0 {
+		return fmt.Errorf("%d worktree(s) could not be moved", summary.Failed)
+	}
+	return nil
+}
+
+// InfoJSON is the machine-readable shape returned by `gren info --format=json`:
+// everything an editor plugin or script needs to know about gren's view of
+// the repository, in one call.
+type InfoJSON struct {
+	Name             string    `json:"name"`
+	Root             string    `json:"root"`
+	Bare             bool      `json:"bare"`
+	CurrentBranch    string    `json:"current_branch,omitempty"`
+	DefaultBranch    string    `json:"default_branch,omitempty"`
+	WorktreeDir      string    `json:"worktree_dir"`
+	WorktreeCount    int       `json:"worktree_count"`
+	Initialized      bool      `json:"initialized"`
+	Forge            ForgeJSON `json:"forge"`
+	ShellIntegration bool      `json:"shell_integration"`
+}
+
+// ForgeJSON reports the detected forge and whether its CLI (gh, glab) is
+// installed and authenticated, which PR/MR features depend on.
+type ForgeJSON struct {
+	Provider  string `json:"provider"`
+	Available bool   `json:"available"`
+}
+
+func (c *CLI) handleInfo(args []string) error {
+	fs := flag.NewFlagSet("info", flag.ExitOnError)
+	format := addFormatFlag(fs)
+	jsonFlag := fs.Bool("json", false, "Shorthand for --format=json")
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren info [options]\n")
+		fmt.Fprintf(fs.Output(), "\nShow repository metadata: name, root, default branch, worktree directory,\n")
+		fmt.Fprintf(fs.Output(), "worktree count, gren config, forge CLI and shell integration.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren info\n")
+		fmt.Fprintf(fs.Output(), "  gren info --json | jq -r .worktree_dir\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+	if *jsonFlag {
+		if *format != "" && *format != "json" {
+			return fmt.Errorf("--json conflicts with --format=%s", *format)
+		}
+		*format = "json"
+	}
+	jsonMode, err := parseFormat(*format)
+	if err != nil {
+		return err
+	}
+	if jsonMode {
+		defer enterJSONMode()()
+	}
+	logging.Info("CLI info: json=%v", jsonMode)
+
+	summary, err := c.worktreeManager.Summary(context.Background())
+	if err != nil {
+		return err
 	}
 
-	dashIndex := -1
-	for i, arg := range args {
-		if arg == "--" {
-			dashIndex = i
-			break
-		}
+	provider := c.prProvider
+	if provider == nil {
+		provider = git.DetectProvider()
 	}
 
-	if dashIndex == -1 {
-		fs.Usage()
-		return fmt.Errorf("missing -- separator before command")
+	info := InfoJSON{
+		Name:          summary.Name,
+		Root:          summary.Root,
+		Bare:          summary.Bare,
+		CurrentBranch: summary.CurrentBranch,
+		DefaultBranch: summary.DefaultBranch,
+		WorktreeDir:   summary.WorktreeDir,
+		WorktreeCount: summary.WorktreeCount,
+		Initialized:   summary.Initialized,
+		Forge: ForgeJSON{
+			Provider:  provider.Name(),
+			Available: provider.IsAvailable(),
+		},
+		ShellIntegration: directive.IsShellIntegrationActive(),
 	}
 
-	if err := fs.Parse(args[:dashIndex]); err != nil {
-		return err
+	if jsonMode {
+		return emitJSON(info)
 	}
 
-	command := args[dashIndex+1:]
-	if len(command) == 0 {
-		fs.Usage()
-		return fmt.Errorf("no command provided")
+	yesNo := func(b bool) string {
+		if b {
+			return "yes"
+		}
+		return "no"
 	}
+	orUnknown := func(s string) string {
+		if s == "" {
+			return "(unknown)"
+		}
+		return s
+	}
+	root := info.Root
+	if info.Bare {
+		root += " (bare)"
+	}
+	currentBranch := info.CurrentBranch
+	if currentBranch == "" {
+		currentBranch = "(detached)"
+	}
+	out := humanOut()
+	fmt.Fprintf(out, "Repository:        %s\n", info.Name)
+	fmt.Fprintf(out, "Root:              %s\n", root)
+	fmt.Fprintf(out, "Current branch:    %s\n", currentBranch)
+	fmt.Fprintf(out, "Default branch:    %s\n", orUnknown(info.DefaultBranch))
+	fmt.Fprintf(out, "Worktree dir:      %s\n", info.WorktreeDir)
+	fmt.Fprintf(out, "Worktrees:         %d\n", info.WorktreeCount)
+	fmt.Fprintf(out, "Initialized:       %s\n", yesNo(info.Initialized))
+	fmt.Fprintf(out, "Forge:             %s (CLI available: %s)\n", info.Forge.Provider, yesNo(info.Forge.Available))
+	fmt.Fprintf(out, "Shell integration: %s\n", yesNo(info.ShellIntegration))
+	return nil
+}
 
-	ctx := context.Background()
+// DoctorJSON is the machine-readable shape returned by
+// `gren doctor --format=json`.
+type DoctorJSON struct {
+	OK     bool              `json:"ok"`
+	Checks []DoctorCheckJSON `json:"checks"`
+}
 
-	opts := core.ForEachOptions{
-		Command:     command,
-		SkipCurrent: *skipCurrent,
-		SkipMain:    *skipMain,
-		FailFast:    *failFast,
+// DoctorCheckJSON is one check in DoctorJSON. Status is "ok", "warn" or
+// "fail"; only "fail" makes OK false.
+type DoctorCheckJSON struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+func (c *CLI) handleDoctor(args []string) error {
+	fs := flag.NewFlagSet("doctor", flag.ExitOnError)
+	format := addFormatFlag(fs)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren doctor [options]\n")
+		fmt.Fprintf(fs.Output(), "\nCheck gren's setup: git, the home directory, the forge CLI (gh/glab), shell\n")
+		fmt.Fprintf(fs.Output(), "integration, the project config, the post-create hook, the worktree directory,\n")
+		fmt.Fprintf(fs.Output(), "and worktrees that are detached or not tracking their pushed branch. Exits\n")
+		fmt.Fprintf(fs.Output(), "non-zero if a check fails; warnings do not. In a terminal, it offers to\n")
+		fmt.Fprintf(fs.Output(), "chmod +x a post-create hook script that is not executable.\n\n")
+		fmt.Fprintf(fs.Output(), "Options:\n")
+		fs.PrintDefaults()
+		fmt.Fprintf(fs.Output(), "\nExamples:\n")
+		fmt.Fprintf(fs.Output(), "  gren doctor\n")
+		fmt.Fprintf(fs.Output(), "  gren doctor --format=json | jq '.checks[] | select(.status != \"ok\")'\n")
 	}
 
-	results, err := c.worktreeManager.ForEach(ctx, opts)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		fs.Usage()
+		return fmt.Errorf("unexpected argument: %s", fs.Arg(0))
+	}
+	jsonMode, err := parseFormat(*format)
 	if err != nil {
 		return err
 	}
+	if jsonMode {
+		defer enterJSONMode()()
+	}
+	logging.Info("CLI doctor: json=%v", jsonMode)
 
-	successCount := 0
-	failCount := 0
+	// A hook script missing its execute bit is the one failure doctor can
+	// fix itself; ask first so the report shows the result
+	if !jsonMode && term.IsTerminal(int(os.Stdin.Fd())) && len(c.worktreeManager.NonExecutableHookScripts(config.HookPostCreate)) > 0 {
+		c.offerHookChmod(config.HookPostCreate, false)
+		fmt.Fprintln(humanOut())
+	}
 
-	for _, r := range results {
-		fmt.Printf("\n\033[1m%s\033[0m (%s)\n", r.Worktree.Branch, r.Worktree.Path)
-		fmt.Print(r.Output)
+	checks := c.worktreeManager.Diagnose(context.Background())
 
-		if r.Error != nil {
-			fmt.Printf("\033[31m✗ Exit code: %d\033[0m\n", r.ExitCode)
-			failCount++
-		} else {
-			successCount++
-		}
+	// The environment checks go right after git, before the repo ones
+	provider := c.prProvider
+	if provider == nil {
+		provider = git.DetectProvider()
+	}
+	forge := core.DoctorCheck{Name: "forge CLI", Status: core.CheckOK, Detail: provider.Name() + " CLI installed and authenticated"}
+	if !provider.IsAvailable() {
+		forge.Status = core.CheckWarn
+		forge.Detail = provider.Name() + " CLI missing or not authenticated, PR status and cleanup of merged PRs are off"
+		forge.Hint = "install it and run 'gh auth login' (GitHub) or 'glab auth login' (GitLab)"
+	}
+	shell := core.DoctorCheck{Name: "shell integration", Status: core.CheckOK, Detail: "active"}
+	if !directive.IsShellIntegrationActive() {
+		shell.Status = core.CheckWarn
+		shell.Detail = "not active, so 'gren switch' cannot change directory"
+		shell.Hint = `add 'eval "$(gren shell-init zsh)"' (or bash/fish) to your shell config`
+	}
+	env := []core.DoctorCheck{homeDoctorCheck(), forge, shell}
+	if len(checks) > 0 && checks[0].Status != core.CheckFail {
+		checks = append(checks[:1], append(env, checks[1:]...)...)
+	} else {
+		checks = append(checks, env...)
 	}
 
-	fmt.Printf("\n---\n")
-	fmt.Printf("✅ %d succeeded", successCount)
-	if failCount > 0 {
-		fmt.Printf(", \033[31m✗ %d failed\033[0m", failCount)
+	result := DoctorJSON{OK: true, Checks: make([]DoctorCheckJSON, 0, len(checks))}
+	failed := 0
+	for _, check := range checks {
+		if check.Status == core.CheckFail {
+			result.OK = false
+			failed++
+		}
+		result.Checks = append(result.Checks, DoctorCheckJSON{
+			Name:   check.Name,
+			Status: string(check.Status),
+			Detail: check.Detail,
+			Hint:   check.Hint,
+		})
 	}
-	fmt.Println()
 
-	if failCount > 0 {
-		return fmt.Errorf("%d worktree(s) failed", failCount)
+	if jsonMode {
+		if err := emitJSON(result); err != nil {
+			return err
+		}
+	} else {
+		out := humanOut()
+		for _, check := range checks {
+			var symbol string
+			switch check.Status {
+			case core.CheckOK:
+				symbol = output.Green("✓")
+			case core.CheckWarn:
+				symbol = output.Yellow("!")
+			default:
+				symbol = output.Red("✗")
+			}
+			fmt.Fprintf(out, "%s %s: %s\n", symbol, output.Bold(check.Name), check.Detail)
+			if check.Hint != "" {
+				fmt.Fprintf(out, "  %s\n", output.Dim("→ "+check.Hint))
+			}
+		}
 	}
 
+	if failed > 0 {
+		return fmt.Errorf("%d check(s) failed", failed)
+	}
 	return nil
 }
 
-// handleDiff shows all changes on the current branch since it diverged from the
-// default (or specified) base branch: committed, staged, unstaged, and untracked.
-func (c *CLI) handleDiff(args []string) error {
-	fs := flag.NewFlagSet("diff", flag.ExitOnError)
-	base := fs.String("base", "", "Base branch to diff against (default: auto-detect default branch)")
+// homeDoctorCheck reports whether $HOME is set. Containers and CI runners
+// often leave it unset; gren then falls back to the user database for the
+// user config and approvals, and without either those are unavailable.
+func homeDoctorCheck() core.DoctorCheck {
+	check := core.DoctorCheck{Name: "home directory"}
+	if home, err := os.UserHomeDir(); err == nil {
+		check.Status = core.CheckOK
+		check.Detail = home
+		return check
+	}
+	check.Status = core.CheckWarn
+	if home, err := config.HomeDir(); err == nil {
+		check.Detail = "HOME is not set, using " + home + " from the user database"
+		check.Hint = "set HOME in the environment gren runs in"
+		return check
+	}
+	check.Detail = "HOME is not set and no home directory is known, so the user config and command approvals are unavailable"
+	check.Hint = "set HOME (or XDG_CONFIG_HOME)"
+	return check
+}
+
+// handleOpen opens a worktree in an editor, a new terminal, or claude,
+// mirroring the TUI's "Open in..." menu.
+func (c *CLI) handleOpen(args []string) error {
+	fs := flag.NewFlagSet("open", flag.ExitOnError)
+	with := fs.String("with", "editor", "What to open the worktree with: editor, terminal, claude, or an editor command (e.g. code, cursor, zed)")
 
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: gren diff [options]\n")
-		fmt.Fprintf(fs.Output(), "\nShow all changes since branching from the base branch\n\n")
+		fmt.Fprintf(fs.Output(), "Usage: gren open <name> [options]\n")
+		fmt.Fprintf(fs.Output(), "\nOpen a worktree in an editor, a new terminal, or claude.\n")
+		fmt.Fprintf(fs.Output(), "The editor and terminal come from the editor/terminal_command config, then $EDITOR and auto-detection.\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
-		fmt.Fprintf(fs.Output(), "  gren diff\n")
-		fmt.Fprintf(fs.Output(), "  gren diff --base main\n")
-		fmt.Fprintf(fs.Output(), "  gren diff | delta\n")
+		fmt.Fprintf(fs.Output(), "  gren open feat-auth                   # Open in the configured editor\n")
+		fmt.Fprintf(fs.Output(), "  gren open auth --with code            # Open in VS Code\n")
+		fmt.Fprintf(fs.Output(), "  gren open feat-auth --with terminal   # Open a new terminal there\n")
+		fmt.Fprintf(fs.Output(), "  gren open feat-auth --with claude     # cd there and start claude\n")
 	}
 
-	if err := fs.Parse(args); err != nil {
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
 		return err
 	}
-
-	// Determine base branch
-	baseBranch := *base
-	if baseBranch == "" {
-		ctx := context.Background()
-		recommended, err := c.gitRepo.GetRecommendedBaseBranch(ctx)
-		if err != nil || recommended == "" {
-			return fmt.Errorf("could not determine default branch; use --base to specify one")
-		}
-		baseBranch = recommended
+	if len(positional) != 1 {
+		fs.Usage()
+		return fmt.Errorf("worktree name is required")
 	}
 
-	// Verify base branch exists
-	if err := exec.Command("git", "rev-parse", "--verify", baseBranch).Run(); err != nil {
-		return fmt.Errorf("base branch %q not found", baseBranch)
-	}
+	logging.Info("CLI open: query=%s, with=%s", positional[0], *with)
 
-	// Find merge-base (divergence point)
-	mergeBaseOut, err := exec.Command("git", "merge-base", "HEAD", baseBranch).Output()
+	worktrees, err := c.worktreeManager.ListWorktrees(context.Background())
 	if err != nil {
-		return fmt.Errorf("failed to find merge-base with %q: %w", baseBranch, err)
+		return fmt.Errorf("failed to list worktrees: %w", err)
 	}
-	mergeBase := strings.TrimSpace(string(mergeBaseOut))
-
-	// 1. Committed changes since merge-base
-	committedCmd := exec.Command("git", "diff", mergeBase, "HEAD")
-	committedCmd.Stdout = os.Stdout
-	committedCmd.Stderr = os.Stderr
-	if err := committedCmd.Run(); err != nil {
-		return fmt.Errorf("git diff (committed) failed: %w", err)
+	wt := findWorktreeByQuery(worktrees, positional[0])
+	if wt == nil {
+		return fmt.Errorf("worktree '%s' not found", positional[0])
 	}
 
-	// 2. Staged changes (index vs HEAD)
-	stagedCmd := exec.Command("git", "diff", "--cached")
-	stagedCmd.Stdout = os.Stdout
-	stagedCmd.Stderr = os.Stderr
-	if err := stagedCmd.Run(); err != nil {
-		return fmt.Errorf("git diff --cached (staged) failed: %w", err)
-	}
+	cfg, _ := c.configManager.Load()
+	terminal, editor := launcher.Settings(cfg)
 
-	// 3. Unstaged changes (working tree vs index)
-	unstagedCmd := exec.Command("git", "diff")
-	unstagedCmd.Stdout = os.Stdout
-	unstagedCmd.Stderr = os.Stderr
-	if err := unstagedCmd.Run(); err != nil {
-		return fmt.Errorf("git diff (unstaged) failed: %w", err)
+	switch *with {
+	case "claude":
+		if err := directive.WriteCDAndRun(wt.Path, "claude"); err != nil {
+			return fmt.Errorf("failed to write claude command: %w", err)
+		}
+		if !directive.IsShellIntegrationActive() {
+			output.Hint("Shell integration not detected, so claude will not start. Run:")
+			fmt.Printf("   eval \"$(gren shell-init zsh)\"  # or bash/fish\n")
+			return nil
+		}
+		output.Successf("Starting claude in %s", output.Bold(wt.Name))
+		return nil
+	case "terminal":
+		name, termArgs := launcher.Terminal(terminal, wt.Path)
+		if err := exec.Command(name, termArgs...).Start(); err != nil {
+			return fmt.Errorf("failed to open terminal: %w", err)
+		}
+		output.Successf("Opened %s in a new terminal", output.Bold(wt.Name))
+		return nil
 	}
 
-	// 4. Untracked files (shown as new-file diffs)
-	untrackedOut, err := exec.Command("git", "ls-files", "--others", "--exclude-standard").Output()
-	if err != nil {
-		return fmt.Errorf("git ls-files failed: %w", err)
-	}
-	for _, file := range strings.Split(strings.TrimSpace(string(untrackedOut)), "\n") {
-		if file == "" {
-			continue
+	editorCmd := strings.Fields(*with)
+	if *with == "editor" {
+		if editorCmd = launcher.Editor(editor); editorCmd == nil {
+			return fmt.Errorf("no editor found. Set editor in config or the EDITOR environment variable")
 		}
-		untrackedCmd := exec.Command("git", "diff", "--no-index", os.DevNull, file)
-		untrackedCmd.Stdout = os.Stdout
-		// git diff --no-index exits 1 when files differ (always for new files), ignore
-		_ = untrackedCmd.Run()
 	}
-
+	cmd := exec.Command(editorCmd[0], append(editorCmd[1:], wt.Path)...)
+	if launcher.IsTerminalEditor(editorCmd[0]) {
+		// Terminal editors take over this terminal until they exit
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		return cmd.Run()
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to open %s: %w", editorCmd[0], err)
+	}
+	output.Successf("Opened %s in %s", output.Bold(wt.Name), editorCmd[0])
 	return nil
 }
 
@@ -2221,25 +4613,46 @@ func (c *CLI) handleStepCommit(args []string) error {
 	fs := flag.NewFlagSet("step commit", flag.ExitOnError)
 	message := fs.String("m", "", "Commit message")
 	useLLM := fs.Bool("llm", false, "Generate commit message using configured LLM")
+	interactive := fs.Bool("interactive", false, "Pick the files to commit from a list of changed files")
+	fs.BoolVar(interactive, "i", false, "Shorthand for --interactive")
 
 	fs.Usage = func() {
-		fmt.Fprintf(fs.Output(), "Usage: gren step commit [options]\n")
-		fmt.Fprintf(fs.Output(), "\nStage and commit all changes\n\n")
+		fmt.Fprintf(fs.Output(), "Usage: gren step commit [options] [path...]\n")
+		fmt.Fprintf(fs.Output(), "\nStage and commit all changes, or only the given paths\n\n")
 		fmt.Fprintf(fs.Output(), "Options:\n")
 		fs.PrintDefaults()
 		fmt.Fprintf(fs.Output(), "\nExamples:\n")
 		fmt.Fprintf(fs.Output(), "  gren step commit                     # Commit with default message\n")
 		fmt.Fprintf(fs.Output(), "  gren step commit -m \"feat: feature\"  # Commit with custom message\n")
 		fmt.Fprintf(fs.Output(), "  gren step commit --llm               # Use LLM to generate message\n")
+		fmt.Fprintf(fs.Output(), "  gren step commit -m \"fix\" src/a.go   # Commit only src/a.go\n")
+		fmt.Fprintf(fs.Output(), "  gren step commit -i                  # Choose files from a list\n")
 	}
 
 	if err := fs.Parse(args); err != nil {
 		return err
 	}
 
+	paths := fs.Args()
+	if *interactive {
+		if len(paths) > 0 {
+			return fmt.Errorf("--interactive picks the files itself; don't pass paths with it")
+		}
+		picked, err := c.pickChangedFiles()
+		if err != nil {
+			return err
+		}
+		if picked == nil {
+			fmt.Println("Cancelled")
+			return nil
+		}
+		paths = picked
+	}
+
 	opts := core.StepCommitOptions{
 		Message: *message,
 		UseLLM:  *useLLM,
+		Paths:   paths,
 	}
 
 	if err := c.worktreeManager.StepCommit(opts); err != nil {
@@ -2250,6 +4663,83 @@ func (c *CLI) handleStepCommit(args []string) error {
 	return nil
 }
 
+// pickChangedFiles lists the changed files and asks which to commit. It
+// returns nil when the answer is empty.
+func (c *CLI) pickChangedFiles() ([]string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return nil, fmt.Errorf("--interactive needs a terminal; pass the paths to commit instead")
+	}
+	files, err := c.worktreeManager.ChangedFiles()
+	if err != nil {
+		return nil, err
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("nothing to commit")
+	}
+
+	fmt.Println("Changed files:")
+	for i, f := range files {
+		fmt.Printf("  %2d. %s %s\n", i+1, f.Status, f.Path)
+	}
+	fmt.Print("\nFiles to commit (e.g. 1,3-4 or all; empty cancels): ")
+	reader := bufio.NewReader(os.Stdin)
+	answer, _ := reader.ReadString('\n')
+	answer = strings.TrimSpace(answer)
+	if answer == "" {
+		return nil, nil
+	}
+
+	indices, err := parseSelection(answer, len(files))
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, len(indices))
+	for i, idx := range indices {
+		paths[i] = files[idx].Path
+	}
+	return paths, nil
+}
+
+// parseSelection parses a list of 1-based numbers and ranges such as
+// "1,3-4 6", or "all", into sorted 0-based indices below n.
+func parseSelection(input string, n int) ([]int, error) {
+	if strings.EqualFold(strings.TrimSpace(input), "all") {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices, nil
+	}
+
+	seen := make(map[int]bool)
+	for _, field := range strings.FieldsFunc(input, func(r rune) bool { return r == ',' || r == ' ' }) {
+		lo, hi, isRange := strings.Cut(field, "-")
+		first, err := strconv.Atoi(lo)
+		if err != nil {
+			return nil, fmt.Errorf("invalid selection %q", field)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(hi); err != nil || last < first {
+				return nil, fmt.Errorf("invalid range %q", field)
+			}
+		}
+		if first < 1 || last > n {
+			return nil, fmt.Errorf("%q is out of range (1-%d)", field, n)
+		}
+		for i := first; i <= last; i++ {
+			seen[i-1] = true
+		}
+	}
+
+	indices := make([]int, 0, len(seen))
+	for i := range seen {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	return indices, nil
+}
+
 func (c *CLI) handleStepSquash(args []string) error {
 	fs := flag.NewFlagSet("step squash", flag.ExitOnError)
 	message := fs.String("m", "", "Squash commit message")
@@ -2363,7 +4853,7 @@ func (c *CLI) handleStepEval(args []string) error {
 		fmt.Fprintf(fs.Output(), "\nExpand template variables against the current worktree and print the result.\n\n")
 		fmt.Fprintf(fs.Output(), "Template variables:\n")
 		fmt.Fprintf(fs.Output(), "  {{ branch }}              Branch name\n")
-		fmt.Fprintf(fs.Output(), "  {{ branch | sanitize }}   Branch with / replaced by -\n")
+		fmt.Fprintf(fs.Output(), "  {{ branch | sanitize }}   Branch as a directory name (/, :, # → -)\n")
 		fmt.Fprintf(fs.Output(), "  {{ branch | hash_port }}  Deterministic port (10000-19999) from the branch\n")
 		fmt.Fprintf(fs.Output(), "  {{ branch | sanitize_db }} Branch as a safe database identifier\n")
 		fmt.Fprintf(fs.Output(), "  {{ worktree }}            Absolute path to worktree\n")
@@ -2403,9 +4893,11 @@ const userConfigExample = `# gren global user configuration
 
 # Default settings for all projects
 [defaults]
-# Template for worktree directory path
+# Template for worktree directory path, used by projects that don't set
+# worktree_dir and written by gren init. A leading ~ is your home directory.
 # Available variables: {{ repo }}, {{ branch }}, {{ branch | sanitize }}
 worktree-dir = "../{{ repo }}-worktrees"
+# worktree-dir = "~/worktrees/{{ repo }}"
 
 # Merge behavior defaults
 remove-after-merge = true
@@ -2449,6 +4941,29 @@ worktree_dir = "../{{ repo }}-worktrees"
 # Package manager: auto, npm, yarn, pnpm, bun
 package_manager = "auto"
 
+# Default branch, if main/master/origin HEAD detection picks the wrong one
+default_branch = "main"
+
+# Render a template into each new worktree's .env ({{ port }} is a free port)
+env_template = ".gren/env.template"
+# env_file = ".env"
+
+# Copy (rather than symlink) these globs into each new worktree
+copy_files = ["*.sqlite3"]
+
+# Fail gren create when a post-create hook fails (default: warn and continue)
+# hooks_required = true
+
+# Remove worktree_dir once its last worktree is deleted, if gren created it
+# prune_empty_worktree_dir = true
+
+# Presets, applied with: gren create -n <name> --preset frontend
+# [presets.frontend]
+# base_branch = "develop"
+# copy_files = ["apps/web/.env.local"]
+# symlink_files = ["apps/web/node_modules"]
+# post-create = "pnpm install --filter web"
+
 # Lifecycle hooks
 [hooks]
 # Run after creating a worktree
@@ -2483,13 +4998,15 @@ func (c *CLI) handleConfig(args []string) error {
 		return c.handleConfigCreate(subargs)
 	case "show":
 		return c.handleConfigShow(subargs)
+	case "list":
+		return c.handleConfigList(subargs)
 	case "approvals":
 		return c.handleConfigApprovals(subargs)
 	case "--help", "-h", "help":
 		c.showConfigHelp()
 		return nil
 	default:
-		return fmt.Errorf("unknown config subcommand: %s (use: create, show, approvals)", subcommand)
+		return fmt.Errorf("unknown config subcommand: %s (use: create, show, list, approvals)", subcommand)
 	}
 }
 
@@ -2501,11 +5018,13 @@ func (c *CLI) showConfigHelp() {
 	fmt.Println("Subcommands:")
 	fmt.Println("  create     Create a configuration file with example values")
 	fmt.Println("  show       Show current configuration status (default)")
+	fmt.Println("  list       List effective settings and where each comes from")
 	fmt.Println("  approvals  View or revoke approved hook commands")
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  gren config                    # Show current config")
 	fmt.Println("  gren config show               # Same as above")
+	fmt.Println("  gren config list               # Effective settings with their source")
 	fmt.Println("  gren config create             # Create user config")
 	fmt.Println("  gren config create --project   # Create project config")
 	fmt.Println("  gren config approvals          # List approved hooks")
@@ -2546,6 +5065,9 @@ func (c *CLI) handleConfigCreate(args []string) error {
 func (c *CLI) createUserConfig() error {
 	ucm := config.NewUserConfigManager()
 	configPath := ucm.ConfigPath()
+	if configPath == "" {
+		return config.ErrNoUserConfigDir
+	}
 
 	// Check if file already exists
 	if ucm.Exists() {
@@ -2562,7 +5084,7 @@ func (c *CLI) createUserConfig() error {
 
 	// Write the example config with values commented out
 	commentedConfig := commentOutConfig(userConfigExample)
-	if err := os.WriteFile(configPath, []byte(commentedConfig), 0644); err != nil {
+	if err := config.WriteFileAtomic(configPath, []byte(commentedConfig), 0600); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -2572,7 +5094,8 @@ func (c *CLI) createUserConfig() error {
 }
 
 func (c *CLI) createProjectConfig() error {
-	configPath := filepath.Join(config.ConfigDir, config.ConfigFileTOML)
+	configDir := config.NewManager().Dir()
+	configPath := filepath.Join(configDir, config.ConfigFileTOML)
 
 	// Check if file already exists
 	if _, err := os.Stat(configPath); err == nil {
@@ -2582,13 +5105,13 @@ func (c *CLI) createProjectConfig() error {
 	}
 
 	// Create .gren directory
-	if err := os.MkdirAll(config.ConfigDir, 0755); err != nil {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
 	// Write the example config with values commented out
 	commentedConfig := commentOutConfig(projectConfigExample)
-	if err := os.WriteFile(configPath, []byte(commentedConfig), 0644); err != nil {
+	if err := config.WriteFileAtomic(configPath, []byte(commentedConfig), 0644); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -2640,6 +5163,36 @@ func (c *CLI) handleConfigShow(args []string) error {
 	return nil
 }
 
+func (c *CLI) handleConfigList(args []string) error {
+	fs := flag.NewFlagSet("config list", flag.ExitOnError)
+
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gren config list\n")
+		fmt.Fprintf(fs.Output(), "\nList the effective settings, merged from every layer, and the layer each\n")
+		fmt.Fprintf(fs.Output(), "comes from: local (.gren/config.local.toml), project (.gren/config.toml),\n")
+		fmt.Fprintf(fs.Output(), "user (the user config) or default. For each key the first of those that\n")
+		fmt.Fprintf(fs.Output(), "sets it wins.\n")
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := c.configManager.Load()
+	if err != nil {
+		return err
+	}
+	for _, warning := range cfg.Warnings {
+		fmt.Fprintf(os.Stderr, "⚠️  %s\n", warning)
+	}
+
+	w := tabwriter.NewWriter(humanOut(), 0, 0, 2, ' ', 0)
+	for _, setting := range cfg.Settings() {
+		fmt.Fprintf(w, "%s\t%s\t(%s)\n", setting.Key, setting.Value, setting.Source)
+	}
+	return w.Flush()
+}
+
 func (c *CLI) handleConfigApprovals(args []string) error {
 	fs := flag.NewFlagSet("config approvals", flag.ExitOnError)
 	revoke := fs.Bool("revoke", false, "Revoke all approved commands for this project")
@@ -2776,8 +5329,8 @@ func (c *CLI) showUserConfig() {
 	}
 
 	// Show LLM status
-	if userConfig.CommitGenerator.Command != "" {
-		fmt.Printf("✅ LLM configured: %s\n", userConfig.CommitGenerator.Command)
+	if userConfig.CommitGenerator.Configured() {
+		fmt.Printf("✅ LLM configured: %s\n", userConfig.CommitGenerator.Describe())
 	}
 }
 
@@ -2792,8 +5345,9 @@ func (c *CLI) showProjectConfig() {
 		return
 	}
 
-	configPath := filepath.Join(config.ConfigDir, config.ConfigFileTOML)
-	jsonPath := filepath.Join(config.ConfigDir, config.ConfigFileJSON)
+	configDir := config.NewManager().Dir()
+	configPath := filepath.Join(configDir, config.ConfigFileTOML)
+	jsonPath := filepath.Join(configDir, config.ConfigFileJSON)
 
 	// Check which config exists
 	var usedPath string
@@ -2866,13 +5420,26 @@ func (c *CLI) resolvePRRef(ref string) (branch, name string, err error) {
 		return "", "", parseErr
 	}
 
+	branchName, err := c.resolvePRBranch(number, ref)
+	if err != nil {
+		return "", "", err
+	}
+
+	// Derive a safe worktree name: "pr-42" or "mr-101"
+	wtName := fmt.Sprintf("%s-%d", prefix, number)
+	return branchName, wtName, nil
+}
+
+// resolvePRBranch returns the head branch of PR/MR number, looked up with
+// the provider's CLI. ref names the PR in errors.
+func (c *CLI) resolvePRBranch(number int, ref string) (string, error) {
 	provider := c.prProvider
 	if provider == nil {
 		provider = git.DetectProvider()
 	}
 
 	if !provider.IsAvailable() {
-		return "", "", fmt.Errorf(
+		return "", fmt.Errorf(
 			"provider CLI (%s) is not installed or not authenticated; cannot resolve %s",
 			provider.Name(), ref,
 		)
@@ -2880,12 +5447,9 @@ func (c *CLI) resolvePRRef(ref string) (branch, name string, err error) {
 
 	branchName, err := provider.GetBranchForPRNumber(number)
 	if err != nil {
-		return "", "", fmt.Errorf("failed to resolve %s: %w", ref, err)
+		return "", fmt.Errorf("failed to resolve %s: %w", ref, err)
 	}
-
-	// Derive a safe worktree name: "pr-42" or "mr-101"
-	wtName := fmt.Sprintf("%s-%d", prefix, number)
-	return branchName, wtName, nil
+	return branchName, nil
 }
 
 // handleHookRun runs hooks with terminal access (used by TUI for interactive hooks).
@@ -2896,6 +5460,7 @@ func (c *CLI) handleHookRun(args []string) error {
 	worktreePath := fs.String("path", "", "Path to the worktree")
 	branchName := fs.String("branch", "", "Branch name")
 	baseBranch := fs.String("base", "", "Base branch")
+	preset := fs.String("preset", "", "With --type post-create: also run this preset's post-create hook")
 	interactive := fs.Bool("interactive", false, "Force all hooks to run with terminal access (inherited stdio) and prompt for approval")
 	tty := fs.Bool("tty", false, "Alias for --interactive")
 	format := addFormatFlag(fs)
@@ -2945,7 +5510,7 @@ func (c *CLI) handleHookRun(args []string) error {
 
 	switch ht {
 	case config.HookPostCreate:
-		results = c.worktreeManager.RunPostCreateHookWithApproval(*worktreePath, *branchName, *baseBranch, autoApprove)
+		results = c.worktreeManager.RunPostCreateHookWithPreset(*worktreePath, *branchName, *baseBranch, *preset, autoApprove)
 	case config.HookPreRemove:
 		results = c.worktreeManager.RunPreRemoveHookWithApproval(*worktreePath, *branchName, autoApprove)
 	case config.HookPostRemove:
@@ -3002,6 +5567,26 @@ func (c *CLI) handleHookRun(args []string) error {
 	return nil
 }
 
+// ExitCodeStale is the exit code of `gren list --stale` when it lists any
+// stale worktrees.
+const ExitCodeStale = 3
+
+// ExitCodeError makes gren exit with Code rather than 1. main prints Err,
+// when there is one, like any other error.
+type ExitCodeError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitCodeError) Error() string {
+	if e.Err == nil {
+		return ""
+	}
+	return e.Err.Error()
+}
+
+func (e *ExitCodeError) Unwrap() error { return e.Err }
+
 // errHookFailedSilently marks a failure whose detail has already been written
 // to stdout as JSON. main prints errors to stderr; this one carries no message
 // because repeating it would just be noise beside the payload.
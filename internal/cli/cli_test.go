@@ -5,14 +5,18 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"flag"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/git"
 )
 
@@ -439,6 +443,42 @@ func TestHandleInitWithProject(t *testing.T) {
 	}
 }
 
+func TestHandleInitHook(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+	os.WriteFile("Cargo.toml", []byte("[package]\nname = \"app\"\n"), 0644)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	if err := c.ParseAndExecute([]string{"gren", "init", "--no-hook", "--hook", "go"}); err == nil {
+		t.Error("--no-hook with --hook go should fail")
+	}
+	if err := c.ParseAndExecute([]string{"gren", "init", "--hook", "java"}); err == nil || !strings.Contains(err.Error(), "unknown hook template") {
+		t.Errorf("--hook java = %v, want unknown hook template", err)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "init", "--hook", "auto"})
+	})
+	if err != nil || !strings.Contains(out, "Post-create hook script created (rust)") {
+		t.Fatalf("init --hook auto = %q, %v; want a rust hook", out, err)
+	}
+	if hook, err := os.ReadFile(filepath.Join(".gren", "post-create.sh")); err != nil || !strings.Contains(string(hook), "cargo fetch") {
+		t.Errorf("hook = %q (%v), want cargo fetch", hook, err)
+	}
+
+	out = captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "init", "--hook", "go"})
+	})
+	if err != nil || !strings.Contains(out, "already exists; left unchanged") {
+		t.Errorf("second init --hook go = %q, %v; want the existing hook kept", out, err)
+	}
+}
+
 func TestHandleInitRepoInfoError(t *testing.T) {
 	mockRepo := &MockRepository{
 		RepoInfoErr: errors.New("failed to get repo info"),
@@ -536,6 +576,38 @@ func TestHandleCreateSuccess(t *testing.T) {
 	_ = dir // worktree directory is based on config, success of create is sufficient
 }
 
+// TestHandleCreateOpen verifies that `gren create --open` writes the cd
+// directive without asking, and refuses to combine with -x.
+func TestHandleCreateOpen(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	config.Initialize(filepath.Base(dir), true)
+	directiveFile := filepath.Join(t.TempDir(), "directive")
+	t.Setenv("GREN_DIRECTIVE_FILE", directiveFile)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	if err := cli.ParseAndExecute([]string{"gren", "create", "-n", "open-test", "--open", "-y"}); err != nil {
+		t.Fatalf("create --open failed: %v", err)
+	}
+	data, err := os.ReadFile(directiveFile)
+	if err != nil {
+		t.Fatalf("no directive written: %v", err)
+	}
+	if !strings.Contains(string(data), "cd ") || !strings.Contains(string(data), "open-test") {
+		t.Errorf("directive = %q, want a cd into the new worktree", data)
+	}
+
+	err = cli.ParseAndExecute([]string{"gren", "create", "-n", "open-x", "--open", "-x", "true"})
+	if err == nil || !strings.Contains(err.Error(), "--open") {
+		t.Errorf("create --open -x error = %v, want it refused", err)
+	}
+}
+
 // TestHandleCreateNoHooksSkipsPostCreate verifies that `gren create --no-hooks`
 // creates the worktree but does not run the post-create hook — so a caller can
 // run setup itself (e.g. a herdr plugin running it in a pane with a TTY).
@@ -1564,6 +1636,144 @@ func TestHandleNavigateStoresPreviousPath(t *testing.T) {
 	}
 }
 
+func TestHandleCreateUnknownPreset(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+	config.Initialize(filepath.Base(dir), true)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	err := cli.ParseAndExecute([]string{"gren", "create", "-n", "preset-wt", "--preset", "frontend"})
+	if err == nil || !strings.Contains(err.Error(), "no presets are configured") {
+		t.Errorf("create --preset with no presets = %v, want an unknown preset error", err)
+	}
+	worktrees, _ := cli.worktreeManager.ListWorktrees(context.Background())
+	if findWorktreeByQuery(worktrees, "preset-wt") != nil {
+		t.Error("worktree created despite the unknown preset")
+	}
+}
+
+func TestHandleNavigateCreate(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+	config.Initialize(filepath.Base(dir), true)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	if err := cli.ParseAndExecute([]string{"gren", "create", "-y", "-n", "feature-auth"}); err != nil {
+		t.Fatalf("create worktree failed: %v", err)
+	}
+
+	directiveFile, err := os.CreateTemp("", "gren-directive-*")
+	if err != nil {
+		t.Fatalf("failed to create directive file: %v", err)
+	}
+	directiveFile.Close()
+	defer os.Remove(directiveFile.Name())
+	os.Setenv("GREN_DIRECTIVE_FILE", directiveFile.Name())
+	defer os.Unsetenv("GREN_DIRECTIVE_FILE")
+
+	// "auth" partially matches feature-auth, which a plain switch would pick;
+	// --create wants the branch named auth
+	stdout := captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "switch", "--create", "-y", "auth"})
+	})
+	if err != nil {
+		t.Fatalf("switch --create failed: %v", err)
+	}
+	if !strings.Contains(stdout, "Created worktree for new branch") {
+		t.Errorf("switch --create output = %q, want it to say it created the worktree", stdout)
+	}
+	worktrees, _ := cli.worktreeManager.ListWorktrees(context.Background())
+	created := findWorktreeByQuery(worktrees, "auth")
+	if created == nil || created.Branch != "auth" {
+		t.Fatalf("worktree for branch auth not created: %+v", created)
+	}
+	content, _ := os.ReadFile(directiveFile.Name())
+	if !strings.Contains(string(content), created.Path) {
+		t.Errorf("directive = %q, want a cd to %s", content, created.Path)
+	}
+
+	stdout = captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "switch", "--create", "auth"})
+	})
+	if err != nil || !strings.Contains(stdout, "already exists") {
+		t.Errorf("second switch --create = %v, output %q; want the existing worktree", err, stdout)
+	}
+}
+
+func TestHandleNavigateSubshell(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+	config.Initialize(filepath.Base(dir), true)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	if err := cli.ParseAndExecute([]string{"gren", "create", "-y", "-n", "sub-target"}); err != nil {
+		t.Fatalf("create worktree failed: %v", err)
+	}
+	worktrees, _ := cli.worktreeManager.ListWorktrees(context.Background())
+	target := findWorktreeByQuery(worktrees, "sub-target")
+
+	// A stand-in shell that records where it started and exits non-zero
+	record := filepath.Join(t.TempDir(), "record")
+	shell := filepath.Join(t.TempDir(), "fake-shell")
+	script := "#!/bin/sh\necho \"$(pwd -P) $GREN_SUBSHELL\" > " + record + "\nexit 3\n"
+	if err := os.WriteFile(shell, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SHELL", shell)
+	t.Setenv("GREN_DIRECTIVE_FILE", "")
+
+	var err error
+	captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "switch", "--subshell", "sub-target"})
+	})
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 {
+		t.Fatalf("switch --subshell error = %v, want the shell's exit code 3", err)
+	}
+	resolved, _ := filepath.EvalSymlinks(target.Path)
+	if got, _ := os.ReadFile(record); strings.TrimSpace(string(got)) != resolved+" "+target.Path {
+		t.Errorf("subshell recorded %q, want it started in %s with %s set", got, resolved, EnvSubshell)
+	}
+
+	// With shell integration the wrapper changes directory; no subshell
+	os.Remove(record)
+	directiveFile := filepath.Join(t.TempDir(), "directive")
+	t.Setenv("GREN_DIRECTIVE_FILE", directiveFile)
+	captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "switch", "--subshell", "sub-target"})
+	})
+	if err != nil {
+		t.Fatalf("switch --subshell with shell integration failed: %v", err)
+	}
+	if _, err := os.Stat(record); err == nil {
+		t.Error("subshell started although shell integration is active")
+	}
+	if content, _ := os.ReadFile(directiveFile); !strings.Contains(string(content), target.Path) {
+		t.Errorf("directive = %q, want a cd to %s", content, target.Path)
+	}
+
+	t.Setenv("GREN_DIRECTIVE_FILE", "")
+	t.Setenv("SHELL", "")
+	captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "switch", "--subshell", "sub-target"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "$SHELL") {
+		t.Errorf("switch --subshell without $SHELL error = %v, want it to ask for $SHELL", err)
+	}
+}
+
 // --- pr:/mr: shorthand tests ---
 
 // mockCIProvider is a controllable CIProvider for testing pr: resolution.
@@ -1571,11 +1781,12 @@ type mockCIProvider struct {
 	available   bool
 	branchByNum map[int]string // number → branch name
 	branchErr   error
+	pr          *git.PRInfo // returned for every branch
 }
 
 func (m *mockCIProvider) Name() string                                   { return "mock" }
 func (m *mockCIProvider) IsAvailable() bool                              { return m.available }
-func (m *mockCIProvider) GetPRInfo(branch string) (*git.PRInfo, error)   { return nil, nil }
+func (m *mockCIProvider) GetPRInfo(branch string) (*git.PRInfo, error)   { return m.pr, nil }
 func (m *mockCIProvider) GetCIStatus(branch string) (*git.CIInfo, error) { return nil, nil }
 func (m *mockCIProvider) OpenPR(branch string) error                     { return nil }
 func (m *mockCIProvider) GetBranchForPRNumber(number int) (string, error) {
@@ -1750,6 +1961,58 @@ func TestHandleCreate_PRShorthand_MRPrefix(t *testing.T) {
 	}
 }
 
+func TestHandleCreate_BaseFromPR(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	config.Initialize(filepath.Base(dir), true)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	c.prProvider = &mockCIProvider{
+		available:   true,
+		branchByNum: map[int]string{123: "feature/api"},
+	}
+
+	// The PR's branch has a commit main lacks
+	exec.Command("git", "-C", dir, "checkout", "-q", "-b", "feature/api").Run()
+	os.WriteFile(filepath.Join(dir, "api.txt"), []byte("api\n"), 0644)
+	exec.Command("git", "-C", dir, "add", "api.txt").Run()
+	exec.Command("git", "-C", dir, "commit", "-q", "-m", "api").Run()
+	exec.Command("git", "-C", dir, "checkout", "-q", "main").Run()
+
+	if err := c.ParseAndExecute([]string{"gren", "create", "-y", "--no-hooks", "-n", "followup", "--base-from-pr", "123"}); err != nil {
+		t.Fatalf("create --base-from-pr 123 failed: %v", err)
+	}
+	worktree := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-worktrees", "followup")
+	if _, err := os.Stat(filepath.Join(worktree, "api.txt")); err != nil {
+		t.Errorf("followup worktree lacks the PR branch's api.txt: %v", err)
+	}
+
+	for _, tt := range []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-n", "other", "--base-from-pr", "999"}, "failed to resolve PR #999"},
+		{[]string{"-n", "other", "--base-from-pr", "123", "-b", "main"}, "mutually exclusive"},
+		{[]string{"-n", "other", "--base-from-pr", "123", "--existing"}, "new branch"},
+	} {
+		err := c.ParseAndExecute(append([]string{"gren", "create", "-y", "--no-hooks"}, tt.args...))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("create %v = %v, want an error containing %q", tt.args, err, tt.want)
+		}
+	}
+
+	c.prProvider = &mockCIProvider{available: false}
+	err := c.ParseAndExecute([]string{"gren", "create", "-y", "--no-hooks", "-n", "other", "--base-from-pr", "123"})
+	if err == nil || !strings.Contains(err.Error(), "not installed") {
+		t.Errorf("create --base-from-pr without a provider = %v, want a not-installed error", err)
+	}
+}
+
 // --- JSON output tests ---
 
 func captureStdout(t *testing.T, fn func()) string {
@@ -1908,6 +2171,300 @@ func TestHandleListUnknownFormatReturnsError(t *testing.T) {
 	}
 }
 
+func TestHandleListFields(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--fields=branch, current,pr,path"})
+	})
+	if err != nil {
+		t.Fatalf("list --fields error: %v", err)
+	}
+
+	resolvedDir, _ := filepath.EvalSymlinks(dir)
+	cols := strings.Fields(strings.TrimSpace(out))
+	if len(cols) != 4 || cols[0] != "main" || cols[1] != "true" || cols[2] != "-" {
+		t.Fatalf("list --fields output = %q, want branch, current, '-' for no PR, path", out)
+	}
+	if resolved, _ := filepath.EvalSymlinks(cols[3]); resolved != resolvedDir {
+		t.Errorf("path column = %q, want %q", cols[3], dir)
+	}
+	if strings.Contains(out, "\x1b[") {
+		t.Errorf("list --fields output must be plain text, got %q", out)
+	}
+}
+
+func TestHandleListCreated(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	featPath := filepath.Join(t.TempDir(), "feat")
+	if output, err := exec.Command("git", "worktree", "add", "-b", "feat", featPath).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, output)
+	}
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--fields=branch,created"})
+	})
+	if err != nil {
+		t.Fatalf("list --fields error: %v", err)
+	}
+	today := time.Now().Format("2006-01-02")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || strings.Fields(lines[0])[1] != "-" || strings.Fields(lines[1])[1] != today {
+		t.Errorf("list --fields=branch,created = %q, want no date for main and %s for feat", out, today)
+	}
+
+	if err := c.ParseAndExecute([]string{"gren", "cleanup", "--created-before", "last week"}); err == nil || !strings.Contains(err.Error(), "invalid date") {
+		t.Errorf("cleanup --created-before 'last week' = %v, want invalid date", err)
+	}
+}
+
+func TestHandleListPathsOnly(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	featPath := filepath.Join(t.TempDir(), "feat")
+	if output, err := exec.Command("git", "worktree", "add", "-b", "feat", featPath).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, output)
+	}
+	os.WriteFile(filepath.Join(featPath, "scratch.txt"), []byte("wip\n"), 0644)
+	mainPath, _ := filepath.EvalSymlinks(dir)
+	featPath, _ = filepath.EvalSymlinks(featPath)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	list := func(args ...string) string {
+		t.Helper()
+		var err error
+		out := captureStdout(t, func() {
+			err = c.ParseAndExecute(append([]string{"gren", "list"}, args...))
+		})
+		if err != nil {
+			t.Fatalf("list %v error: %v", args, err)
+		}
+		return out
+	}
+
+	if out, want := list("--paths-only"), mainPath+"\n"+featPath+"\n"; out != want {
+		t.Errorf("list --paths-only = %q, want %q", out, want)
+	}
+	if out, want := list("--paths-only", "-0"), mainPath+"\x00"+featPath+"\x00"; out != want {
+		t.Errorf("list --paths-only -0 = %q, want %q", out, want)
+	}
+	if out, want := list("--paths-only", "--filter-status=untracked"), featPath+"\n"; out != want {
+		t.Errorf("list --paths-only --filter-status=untracked = %q, want %q", out, want)
+	}
+
+	for _, args := range [][]string{{"-0"}, {"--paths-only", "--fields=path"}, {"--paths-only", "--format=json"}} {
+		if err := c.ParseAndExecute(append([]string{"gren", "list"}, args...)); err == nil {
+			t.Errorf("list %v succeeded, want an error", args)
+		}
+	}
+}
+
+func TestHandleListFieldsRejectsUnknownField(t *testing.T) {
+	c := NewCLI(newMockRepository(), config.NewManager())
+
+	for _, spec := range []string{"branch,colour", ","} {
+		err := c.ParseAndExecute([]string{"gren", "list", "--fields=" + spec})
+		if err == nil || !strings.Contains(err.Error(), "valid fields: name, branch, path") {
+			t.Errorf("list --fields=%s error = %v, want the valid fields listed", spec, err)
+		}
+	}
+}
+
+func TestHandleListFormatTemplate(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--format", `{{.Branch}}\t{{.IsCurrent}}\t{{"feat/x" | sanitize}}`})
+	})
+	if err != nil {
+		t.Fatalf("list --format template error: %v", err)
+	}
+	if out != "main\ttrue\tfeat-x\n" {
+		t.Errorf("list --format template output = %q, want one tab-separated line", out)
+	}
+	// Valid for real worktrees, though it fails on an empty one
+	out = captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--format", `{{slice .Branch 0 3}}`})
+	})
+	if err != nil || out != "mai\n" {
+		t.Errorf("list --format with slice = %q, %v; want \"mai\"", out, err)
+	}
+}
+
+func TestHandleListFormatTemplateInvalid(t *testing.T) {
+	c := NewCLI(newMockRepository(), config.NewManager())
+
+	for format, want := range map[string]string{
+		"{{.Branch":          "unclosed action",
+		"{{.Colour}}":        "can't evaluate field Colour",
+		"{{.Branch | nope}}": `function "nope" not defined`,
+		"table":              "unsupported format",
+	} {
+		err := c.ParseAndExecute([]string{"gren", "list", "--format", format})
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("list --format %q error = %v, want %q", format, err, want)
+		}
+	}
+	err := c.ParseAndExecute([]string{"gren", "list", "--fields=branch", "--format", "{{.Branch}}"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("list --fields with a --format template error = %v, want a conflict", err)
+	}
+}
+
+func TestShortPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	for path, want := range map[string]string{
+		home:                           "~",
+		filepath.Join(home, "src/app"): "~/src/app",
+		home + "-other/app":            home + "-other/app",
+		"/opt/app":                     "/opt/app",
+	} {
+		if got := shortPath(path); got != want {
+			t.Errorf("shortPath(%q) = %q, want %q", path, got, want)
+		}
+	}
+}
+
+func TestHandleListFilterStatus(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+	os.WriteFile(filepath.Join(dir, "scratch.txt"), []byte("x"), 0644)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	listJSON := func(filter string) []map[string]interface{} {
+		t.Helper()
+		var err error
+		out := captureStdout(t, func() {
+			err = c.ParseAndExecute([]string{"gren", "list", "--format=json", "--filter-status=" + filter})
+		})
+		if err != nil {
+			t.Fatalf("list --filter-status=%s error: %v", filter, err)
+		}
+		var worktrees []map[string]interface{}
+		if jsonErr := json.Unmarshal([]byte(out), &worktrees); jsonErr != nil {
+			t.Fatalf("invalid JSON for --filter-status=%s: %v\noutput: %s", filter, jsonErr, out)
+		}
+		return worktrees
+	}
+
+	if got := listJSON("modified, untracked"); len(got) != 1 || got[0]["status"] != "untracked" {
+		t.Errorf("--filter-status=modified,untracked = %v, want the untracked main worktree", got)
+	}
+	if got := listJSON("clean,mixed"); len(got) != 0 {
+		t.Errorf("--filter-status=clean,mixed = %v, want an empty list", got)
+	}
+
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--fields=branch,status", "--filter-status=untracked"})
+	})
+	if err != nil || strings.TrimSpace(out) != "main  untracked" {
+		t.Errorf("list --fields --filter-status = %q, %v; want the main worktree", out, err)
+	}
+
+	err = c.ParseAndExecute([]string{"gren", "list", "--filter-status=dirty"})
+	if err == nil || !strings.Contains(err.Error(), "unknown status 'dirty'") {
+		t.Errorf("--filter-status=dirty error = %v, want unknown status", err)
+	}
+}
+
+func TestHandleListStale(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--stale", "--format=json"})
+	})
+	if err != nil || strings.TrimSpace(out) != "[]" {
+		t.Fatalf("list --stale with nothing stale = %q, %v; want [] and no error", out, err)
+	}
+
+	// A branch without commits of its own is stale
+	wtPath := filepath.Join(t.TempDir(), "feat")
+	if output, err := exec.Command("git", "worktree", "add", "-b", "feat", wtPath).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, output)
+	}
+	out = captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "list", "--stale", "--fields=branch"})
+	})
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != ExitCodeStale {
+		t.Errorf("list --stale with a stale worktree error = %v, want exit code %d", err, ExitCodeStale)
+	}
+	if strings.TrimSpace(out) != "feat" {
+		t.Errorf("list --stale --fields=branch = %q, want feat", out)
+	}
+
+	err = c.ParseAndExecute([]string{"gren", "list", "--stale", "--filter-status=clean"})
+	if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+		t.Errorf("--stale with --filter-status error = %v, want a conflict", err)
+	}
+}
+
+func TestHandleListSort(t *testing.T) {
+	c := NewCLI(newMockRepository(), config.NewManager())
+
+	err := c.ParseAndExecute([]string{"gren", "list", "--sort=size"})
+	if err == nil || !strings.Contains(err.Error(), "valid modes: recent, name, branch, status, stale") {
+		t.Errorf("list --sort=size error = %v, want the valid modes listed", err)
+	}
+
+	worktrees := []core.WorktreeInfo{
+		{Name: "web", LastCommit: "2d ago"},
+		{Name: "main", LastCommit: "3d ago", IsCurrent: true},
+		{Name: "api", LastCommit: "1h ago"},
+	}
+	listOrder{}.apply(worktrees)
+	if worktrees[0].Name != "web" {
+		t.Errorf("zero listOrder should keep git's order, got %s first", worktrees[0].Name)
+	}
+	listOrder{mode: core.SortRecent, pinCurrent: true}.apply(worktrees)
+	if got := worktrees[0].Name + "," + worktrees[1].Name + "," + worktrees[2].Name; got != "main,api,web" {
+		t.Errorf("--sort=recent --pin-current order = %s, want main,api,web", got)
+	}
+}
+
 // --- for-each tests ---
 
 // setupForEachRepo creates a real git repo with two worktrees for for-each testing.
@@ -2312,3 +2869,485 @@ func TestProjectConfigExampleUsesCurrentVersion(t *testing.T) {
 		t.Error("projectConfigExample still contains stale hardcoded version \"1.0.0\"")
 	}
 }
+
+func TestParseInterspersed(t *testing.T) {
+	fs := flag.NewFlagSet("compare", flag.ContinueOnError)
+	diff := fs.Bool("diff", false, "")
+	reverse := fs.Bool("reverse", false, "")
+
+	positional, err := parseInterspersed(fs, []string{"feat-a", "--diff", "feat-b", "--reverse"})
+	if err != nil {
+		t.Fatalf("parseInterspersed() error: %v", err)
+	}
+	if len(positional) != 2 || positional[0] != "feat-a" || positional[1] != "feat-b" {
+		t.Errorf("positional = %v, want [feat-a feat-b]", positional)
+	}
+	if !*diff || !*reverse {
+		t.Errorf("diff = %v, reverse = %v; want both true", *diff, *reverse)
+	}
+}
+
+func TestCleanupSummary(t *testing.T) {
+	worktrees := []core.WorktreeInfo{
+		{Name: "a", Path: "/wt/a"},
+		{Name: "b", Path: "/wt/b"},
+		{Name: "c", Path: "/wt/c"},
+	}
+
+	got := cleanupSummary(worktrees, map[string]int64{"/wt/a": 1024, "/wt/b": 2048, "/wt/c": 1024})
+	if want := "3 worktree(s), ~4.0 KB on disk"; got != want {
+		t.Errorf("cleanupSummary() = %q, want %q", got, want)
+	}
+
+	got = cleanupSummary(worktrees, map[string]int64{"/wt/a": 512})
+	if want := "3 worktree(s), ~512 B on disk (2 could not be measured)"; got != want {
+		t.Errorf("cleanupSummary() = %q, want %q", got, want)
+	}
+}
+
+func TestHandleOpen(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	directiveFile := filepath.Join(t.TempDir(), "directive")
+	t.Setenv("GREN_DIRECTIVE_FILE", directiveFile)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+
+	t.Run("claude writes cd and run directive", func(t *testing.T) {
+		captureStdout(t, func() {
+			if err := cli.ParseAndExecute([]string{"gren", "open", "main", "--with", "claude"}); err != nil {
+				t.Fatalf("open --with claude failed: %v", err)
+			}
+		})
+		data, err := os.ReadFile(directiveFile)
+		if err != nil {
+			t.Fatalf("directive not written: %v", err)
+		}
+		resolved, _ := filepath.EvalSymlinks(dir)
+		got := string(data)
+		if !strings.Contains(got, "claude") || (!strings.Contains(got, dir) && !strings.Contains(got, resolved)) {
+			t.Errorf("directive = %q, want cd to %s then claude", got, dir)
+		}
+	})
+
+	t.Run("editor command runs with worktree path", func(t *testing.T) {
+		captureStdout(t, func() {
+			if err := cli.ParseAndExecute([]string{"gren", "open", "--with", "true", "main"}); err != nil {
+				t.Fatalf("open --with true failed: %v", err)
+			}
+		})
+	})
+
+	t.Run("unknown worktree", func(t *testing.T) {
+		err := cli.ParseAndExecute([]string{"gren", "open", "does-not-exist"})
+		if err == nil || !strings.Contains(err.Error(), "not found") {
+			t.Errorf("expected not found error, got %v", err)
+		}
+	})
+}
+
+func TestHandleCreateAllMatching(t *testing.T) {
+	dir, cleanup := setupTempGitRepoWithCleanWorktrees(t)
+	defer cleanup()
+
+	remoteDir := t.TempDir()
+	exec.Command("git", "-C", remoteDir, "init", "--bare").Run()
+	exec.Command("git", "-C", dir, "remote", "add", "origin", remoteDir).Run()
+	for _, branch := range []string{"feature/a", "feature/b", "fix/c"} {
+		exec.Command("git", "-C", dir, "push", "origin", "HEAD:refs/heads/"+branch).Run()
+	}
+	exec.Command("git", "-C", dir, "fetch", "origin").Run()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+
+	t.Run("dry run lists matches without creating", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			if err := cli.ParseAndExecute([]string{"gren", "create", "--all-matching", "feature/*", "--dry-run"}); err != nil {
+				t.Fatalf("create --all-matching --dry-run failed: %v", err)
+			}
+		})
+		if !strings.Contains(out, "feature/a") || !strings.Contains(out, "feature/b") || strings.Contains(out, "fix/c") {
+			t.Errorf("dry-run output = %q, want feature/a and feature/b only", out)
+		}
+		if !strings.Contains(out, "Would create 2 worktree(s)") {
+			t.Errorf("dry-run output = %q, want count", out)
+		}
+	})
+
+	t.Run("creates worktrees and skips existing", func(t *testing.T) {
+		out := captureStdout(t, func() {
+			if err := cli.ParseAndExecute([]string{"gren", "create", "--all-matching", "feature/*", "-y"}); err != nil {
+				t.Fatalf("create --all-matching failed: %v", err)
+			}
+		})
+		if !strings.Contains(out, "2 created, 0 failed, 0 warnings") {
+			t.Errorf("create --all-matching output = %q, want a summary line", out)
+		}
+		listOut, _ := exec.Command("git", "worktree", "list").Output()
+		for _, branch := range []string{"[feature/a]", "[feature/b]"} {
+			if !strings.Contains(string(listOut), branch) {
+				t.Errorf("worktree list = %q, missing %s", listOut, branch)
+			}
+		}
+
+		out = captureStdout(t, func() {
+			if err := cli.ParseAndExecute([]string{"gren", "create", "--all-matching", "feature/*"}); err != nil {
+				t.Fatalf("second create --all-matching failed: %v", err)
+			}
+		})
+		if !strings.Contains(out, "already has a worktree") || !strings.Contains(out, "No remote branches matching") {
+			t.Errorf("second run output = %q, want everything skipped", out)
+		}
+	})
+
+	t.Run("dry run requires all-matching", func(t *testing.T) {
+		if err := cli.ParseAndExecute([]string{"gren", "create", "-n", "x", "--dry-run"}); err == nil {
+			t.Error("create -n x --dry-run = nil error, want error")
+		}
+	})
+}
+
+func TestHomeDoctorCheck(t *testing.T) {
+	if check := homeDoctorCheck(); check.Status != core.CheckOK {
+		t.Errorf("home directory = %+v with HOME set, want ok", check)
+	}
+
+	t.Setenv("HOME", "")
+	check := homeDoctorCheck()
+	if check.Status != core.CheckWarn || !strings.HasPrefix(check.Detail, "HOME is not set") || check.Hint == "" {
+		t.Errorf("home directory = %+v with HOME unset, want a warning with a hint", check)
+	}
+}
+
+func TestCleanupReasonList(t *testing.T) {
+	tests := []struct {
+		reason  string
+		enabled []bool
+		want    string
+	}{
+		{"", []bool{false, false, false}, ""},
+		{"pr_closed", []bool{false, false, false}, "pr_closed"},
+		{"", []bool{true, false, false}, "merged_locally,pr_merged"},
+		{"", []bool{true, true, false}, "merged_locally,pr_merged,remote_gone"},
+		{"no_unique_commits", []bool{false, false, true}, "no_unique_commits,pr_closed"},
+	}
+	for _, tt := range tests {
+		if got := cleanupReasonList(tt.reason, tt.enabled); got != tt.want {
+			t.Errorf("cleanupReasonList(%q, %v) = %q, want %q", tt.reason, tt.enabled, got, tt.want)
+		}
+	}
+}
+
+func TestKeepMostRecent(t *testing.T) {
+	worktrees := []core.WorktreeInfo{
+		{Name: "old", LastCommit: "3mo ago"},
+		{Name: "new", LastCommit: "2h ago"},
+		{Name: "mid", LastCommit: "4d ago"},
+	}
+	names := func(wts []core.WorktreeInfo) string {
+		var out []string
+		for _, wt := range wts {
+			out = append(out, wt.Name)
+		}
+		return strings.Join(out, ",")
+	}
+
+	tests := []struct {
+		n          int
+		kept, rest string
+	}{
+		{0, "", "old,new,mid"},
+		{1, "new", "mid,old"},
+		{2, "new,mid", "old"},
+		{5, "new,mid,old", ""},
+	}
+	for _, tt := range tests {
+		kept, rest := keepMostRecent(worktrees, tt.n)
+		if names(kept) != tt.kept || names(rest) != tt.rest {
+			t.Errorf("keepMostRecent(%d) = %s / %s, want %s / %s", tt.n, names(kept), names(rest), tt.kept, tt.rest)
+		}
+	}
+	if names(worktrees) != "old,new,mid" {
+		t.Errorf("keepMostRecent reordered its input: %s", names(worktrees))
+	}
+
+	c := NewCLI(newMockRepository(), config.NewManager())
+	if err := c.ParseAndExecute([]string{"gren", "cleanup", "--keep", "-1"}); err == nil || !strings.Contains(err.Error(), "--keep must be 0 or more") {
+		t.Errorf("cleanup --keep -1 error = %v, want it rejected", err)
+	}
+}
+
+func TestSortBySize(t *testing.T) {
+	small, big := t.TempDir(), t.TempDir()
+	os.WriteFile(filepath.Join(small, "a"), make([]byte, 10), 0644)
+	os.WriteFile(filepath.Join(big, "a"), make([]byte, 1000), 0644)
+	missing := filepath.Join(small, "missing")
+
+	worktrees := []core.WorktreeInfo{{Name: "missing", Path: missing}, {Name: "small", Path: small}, {Name: "big", Path: big}}
+	sizes := sortBySize(worktrees)
+
+	var order []string
+	for _, wt := range worktrees {
+		order = append(order, wt.Name)
+	}
+	if got := strings.Join(order, ","); got != "big,small,missing" {
+		t.Errorf("order = %s, want big,small,missing", got)
+	}
+	if sizes[big] != 1000 || sizes[small] != 10 {
+		t.Errorf("sizes = %v", sizes)
+	}
+	if _, ok := sizes[missing]; ok {
+		t.Error("a worktree that can't be measured should have no size")
+	}
+}
+
+func TestPickStaleWorktrees(t *testing.T) {
+	worktrees := []core.WorktreeInfo{
+		{Branch: "merged", StaleReason: "pr_merged"},
+		{Branch: "dirty", StaleReason: "pr_merged", ModifiedCount: 1},
+		{Branch: "gone", StaleReason: "remote_gone"},
+		{Branch: "merged-too", StaleReason: "pr_merged"},
+	}
+	branches := func(wts []core.WorktreeInfo) []string {
+		var names []string
+		for _, wt := range wts {
+			names = append(names, wt.Branch)
+		}
+		return names
+	}
+
+	tests := []struct {
+		name       string
+		input      string
+		force      bool
+		wantPicked []string
+		wantHeld   []string
+		cancelled  bool
+	}{
+		{name: "enter picks the safe ones", input: "\n", wantPicked: []string{"merged", "merged-too"}},
+		{name: "ranges", input: "2-3\n", wantPicked: []string{"gone"}, wantHeld: []string{"dirty"}},
+		{name: "force deletes dirty ones", input: "1,2\n", force: true, wantPicked: []string{"merged", "dirty"}},
+		{name: "invalid selection asks again", input: "9\n3\n", wantPicked: []string{"gone"}},
+		{name: "n cancels", input: "n\n", cancelled: true},
+		{name: "end of input cancels", input: "", cancelled: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var picked, held []core.WorktreeInfo
+			captureStdout(t, func() {
+				picked, held = pickStaleWorktrees(strings.NewReader(tt.input), worktrees, tt.force)
+			})
+			if tt.cancelled {
+				if picked != nil || held != nil {
+					t.Errorf("picked %v, held %v; want cancelled", branches(picked), branches(held))
+				}
+				return
+			}
+			if !slices.Equal(branches(picked), tt.wantPicked) || !slices.Equal(branches(held), tt.wantHeld) {
+				t.Errorf("picked %v, held %v; want %v, %v", branches(picked), branches(held), tt.wantPicked, tt.wantHeld)
+			}
+		})
+	}
+
+	// With nothing safe, Enter cancels
+	var picked []core.WorktreeInfo
+	captureStdout(t, func() {
+		picked, _ = pickStaleWorktrees(strings.NewReader("\n"), worktrees[1:3], false)
+	})
+	if picked != nil {
+		t.Errorf("Enter with nothing marked picked %v, want cancelled", branches(picked))
+	}
+}
+
+func TestHandleCleanupInteractiveConflicts(t *testing.T) {
+	c := NewCLI(newMockRepository(), config.NewManager())
+	for _, args := range [][]string{{"-i", "-f"}, {"--interactive", "--dry-run"}} {
+		err := c.ParseAndExecute(append([]string{"gren", "cleanup"}, args...))
+		if err == nil || !strings.Contains(err.Error(), "cannot be combined") {
+			t.Errorf("cleanup %v error = %v, want a conflict", args, err)
+		}
+	}
+}
+
+func TestParseSelection(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    []int
+		wantErr bool
+	}{
+		{input: "1", want: []int{0}},
+		{input: "3,1", want: []int{0, 2}},
+		{input: "2-4 1", want: []int{0, 1, 2, 3}},
+		{input: "1,1-2", want: []int{0, 1}},
+		{input: "all", want: []int{0, 1, 2, 3, 4}},
+		{input: "0", wantErr: true},
+		{input: "6", wantErr: true},
+		{input: "4-2", wantErr: true},
+		{input: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseSelection(tt.input, 5)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSelection(%q) = %v, want an error", tt.input, got)
+			}
+			continue
+		}
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("parseSelection(%q) = %v, %v; want %v", tt.input, got, err, tt.want)
+		}
+	}
+}
+
+func TestHandleRelocate(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+	worktreeDir := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-worktrees")
+	defer os.RemoveAll(worktreeDir)
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	// Somewhere other than the default ../<repo>-worktrees
+	oldPath := filepath.Join(t.TempDir(), "feat")
+	if output, err := exec.Command("git", "worktree", "add", "-b", "feat", oldPath).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, output)
+	}
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "relocate", "--dry-run"})
+	})
+	if err != nil || !strings.Contains(out, "Would move 1 worktree(s)") || !strings.Contains(out, "feat") {
+		t.Fatalf("relocate --dry-run = %q, %v; want feat listed", out, err)
+	}
+	if _, err := os.Stat(oldPath); err != nil {
+		t.Fatalf("--dry-run moved the worktree: %v", err)
+	}
+
+	out = captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "relocate", "feat"})
+	})
+	if err != nil {
+		t.Fatalf("relocate error: %v\n%s", err, out)
+	}
+	if _, err := os.Stat(filepath.Join(worktreeDir, "feat", "README.md")); err != nil {
+		t.Errorf("feat not moved into %s: %v\n%s", worktreeDir, err, out)
+	}
+
+	out = captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "relocate"})
+	})
+	if err != nil || !strings.Contains(out, "All worktrees are where worktree_dir puts them") {
+		t.Errorf("second relocate = %q, %v; want nothing to move", out, err)
+	}
+}
+
+func TestHandleExec(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	featPath := filepath.Join(t.TempDir(), "feat")
+	if output, err := exec.Command("git", "worktree", "add", "-b", "feat", featPath).CombinedOutput(); err != nil {
+		t.Fatalf("git worktree add: %v\n%s", err, output)
+	}
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "exec", "feat", "--", "sh", "-c", "pwd; exit 3"})
+	})
+	var exitErr *ExitCodeError
+	if !errors.As(err, &exitErr) || exitErr.Code != 3 || exitErr.Error() != "" {
+		t.Errorf("exec error = %v, want a silent exit code 3", err)
+	}
+	if resolved, _ := filepath.EvalSymlinks(featPath); !strings.Contains(out, resolved) {
+		t.Errorf("exec ran in %q, want %s", strings.TrimSpace(out), resolved)
+	}
+
+	out = captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "exec", "feat", "--", "echo {{ branch }}"})
+	})
+	if err != nil || strings.TrimSpace(out) != "feat" {
+		t.Errorf("exec with template = %q, %v; want feat", out, err)
+	}
+
+	if err := c.ParseAndExecute([]string{"gren", "exec", "nope", "--", "true"}); err == nil || !strings.Contains(err.Error(), "not found") {
+		t.Errorf("exec on unknown worktree = %v, want not found", err)
+	}
+	if err := c.ParseAndExecute([]string{"gren", "exec", "feat", "true"}); err == nil {
+		t.Error("exec without -- should fail")
+	}
+}
+
+func TestFindWorktreeByQueryPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	base := filepath.Join(home, "code", "app-worktrees")
+	for _, name := range []string{"feat", "fix"} {
+		os.MkdirAll(filepath.Join(base, name), 0755)
+	}
+	worktrees := []core.WorktreeInfo{
+		{Name: "app", Branch: "main", Path: filepath.Join(home, "code", "app")},
+		{Name: "feat", Branch: "feature/login", Path: filepath.Join(base, "feat")},
+		{Name: "fix", Branch: "bugfix/crash", Path: filepath.Join(base, "fix")},
+	}
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(filepath.Join(base, "feat"))
+
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{filepath.Join(base, "fix"), "fix"},
+		{filepath.Join(base, "fix") + string(filepath.Separator), "fix"},
+		{filepath.Join("..", "fix"), "fix"},
+		{".", "feat"},
+		{"~/code/app-worktrees/fix", "fix"},
+		{filepath.Join("app-worktrees", "fix"), "fix"},
+		{filepath.Join("code", "app"), "app"},
+		{"login", "feat"}, // branch matches still come first
+	}
+	for _, tt := range tests {
+		got := findWorktreeByQuery(worktrees, tt.query)
+		if got == nil || got.Name != tt.want {
+			t.Errorf("findWorktreeByQuery(%q) = %v, want %s", tt.query, got, tt.want)
+		}
+	}
+	for _, query := range []string{filepath.Join(base, "gone"), filepath.Join("..", "gone"), "worktrees/fix"} {
+		if got := findWorktreeByQuery(worktrees, query); got != nil {
+			t.Errorf("findWorktreeByQuery(%q) = %s, want no match", query, got.Name)
+		}
+	}
+}
+
+func TestHandleDirectiveShell(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "directive")
+	os.WriteFile(file, []byte(`{"action":"cd","path":"/tmp/feat"}`+"\n"+`{"action":"exec","command":"vim ."}`+"\n"), 0644)
+
+	c := NewCLI(newMockRepository(), config.NewManager())
+	var err error
+	out := captureStdout(t, func() {
+		err = c.ParseAndExecute([]string{"gren", "__directive", file})
+	})
+	if err != nil || out != "cd \"/tmp/feat\"\nexec vim .\n" {
+		t.Errorf("__directive = %q, %v; want the shell commands", out, err)
+	}
+}
@@ -51,10 +51,23 @@ func (c *CLI) handleCompletion(args []string) error {
 	return nil
 }
 
-// getWorktreeNames returns a list of worktree names for completion
+// completionCommands are the commands tab completion offers, in the order
+// the scripts list them. Internal commands (__complete, hook-run) are left
+// out. The scripts spell the list out too; a test keeps them in step.
+var completionCommands = []string{
+	"create", "list", "delete", "cleanup", "prune", "repair", "relocate", "init",
+	"navigate", "switch", "cd", "nav",
+	"compare", "diff", "merge", "for-each", "exec", "step", "push", "set-upstream", "open", "reattach",
+	"info", "doctor", "config", "marker", "statusline", "shell-init", "completion",
+	"logs", "help", "install-skill", "setup-claude-plugin",
+}
+
+// getWorktreeNames returns a list of worktree names for completion. It runs
+// on every tab press, so it skips the per-worktree git status and stale
+// checks a full listing does.
 func (c *CLI) getWorktreeNames() []string {
 	ctx := context.Background()
-	worktrees, err := c.worktreeManager.ListWorktrees(ctx)
+	worktrees, err := c.worktreeManager.ListWorktreesBasic(ctx)
 	if err != nil {
 		return nil
 	}
@@ -69,7 +82,7 @@ func (c *CLI) getWorktreeNames() []string {
 // getBranchNames returns a list of branch names for completion
 func (c *CLI) getBranchNames() []string {
 	ctx := context.Background()
-	worktrees, err := c.worktreeManager.ListWorktrees(ctx)
+	worktrees, err := c.worktreeManager.ListWorktreesBasic(ctx)
 	if err != nil {
 		return nil
 	}
@@ -83,35 +96,23 @@ func (c *CLI) getBranchNames() []string {
 	return names
 }
 
-// handleCompletionQuery handles dynamic completion queries from shells
+// handleCompletionQuery handles dynamic completion queries from shells:
+// `gren __complete <worktrees|branches|commands> <word>` prints the
+// candidates starting with word (case-insensitively), one per line.
 func (c *CLI) handleCompletionQuery(args []string) error {
 	if len(args) < 2 {
 		return nil
 	}
 
-	queryType := args[0]
-	// word := args[1] // Current word being completed
+	queryType, word := args[0], args[1]
 
 	switch queryType {
 	case "worktrees":
-		for _, name := range c.getWorktreeNames() {
-			fmt.Println(name)
-		}
+		formatCompletions(c.getWorktreeNames(), word)
 	case "branches":
-		for _, name := range c.getBranchNames() {
-			fmt.Println(name)
-		}
+		formatCompletions(c.getBranchNames(), word)
 	case "commands":
-		commands := []string{
-			"create", "list", "delete", "cleanup", "init",
-			"navigate", "switch", "cd", "nav",
-			"compare", "merge", "for-each", "step",
-			"marker", "statusline", "shell-init", "completion",
-			"logs", "setup-claude-plugin",
-		}
-		for _, cmd := range commands {
-			fmt.Println(cmd)
-		}
+		formatCompletions(completionCommands, word)
 	}
 
 	return nil
@@ -124,7 +125,7 @@ _gren_completions() {
     local cur prev words cword
     _init_completion || return
 
-    local commands="create list delete cleanup init navigate switch cd nav compare merge for-each step marker statusline shell-init completion logs setup-claude-plugin"
+    local commands="create list delete cleanup prune repair relocate init navigate switch cd nav compare diff merge for-each exec step push set-upstream open reattach info doctor config marker statusline shell-init completion logs help install-skill setup-claude-plugin"
 
     case $cword in
         1)
@@ -134,7 +135,21 @@ _gren_completions() {
     esac
 
     case ${words[1]} in
-        delete|compare|navigate|switch|cd|nav)
+        relocate)
+            if [[ $cur == -* ]]; then
+                COMPREPLY=($(compgen -W "--dry-run" -- "$cur"))
+            else
+                COMPREPLY=($(compgen -W "$(COMPLETE=1 gren __complete worktrees "$cur" 2>/dev/null)" -- "$cur"))
+            fi
+            return 0
+            ;;
+        exec)
+            if [[ $cword -eq 2 ]]; then
+                COMPREPLY=($(compgen -W "$(COMPLETE=1 gren __complete worktrees "$cur" 2>/dev/null)" -- "$cur"))
+            fi
+            return 0
+            ;;
+        delete|compare|push|set-upstream|open|reattach|navigate|switch|cd|nav)
             # Complete with worktree names
             local worktrees
             worktrees=$(COMPLETE=1 gren __complete worktrees "$cur" 2>/dev/null)
@@ -150,8 +165,12 @@ _gren_completions() {
                     COMPREPLY=($(compgen -W "$branches" -- "$cur"))
                     return 0
                     ;;
+                --remote)
+                    COMPREPLY=($(compgen -W "$(git remote 2>/dev/null)" -- "$cur"))
+                    return 0
+                    ;;
                 *)
-                    COMPREPLY=($(compgen -W "-n -b --branch --existing --dir -x" -- "$cur"))
+                    COMPREPLY=($(compgen -W "-n -b --branch --existing --track-remote --remote --dir --path -x --open --all-matching --dry-run --preset --base-from-pr" -- "$cur"))
                     return 0
                     ;;
             esac
@@ -166,31 +185,75 @@ _gren_completions() {
                     return 0
                     ;;
                 *)
-                    COMPREPLY=($(compgen -W "--no-squash --no-remove --no-verify --no-rebase -y -f" -- "$cur"))
+                    COMPREPLY=($(compgen -W "--no-squash --no-remove --no-verify --no-rebase -y -f --into-current --abort-on-conflict --dry-run" -- "$cur"))
                     return 0
                     ;;
             esac
             ;;
         list)
-            COMPREPLY=($(compgen -W "-v" -- "$cur"))
+            COMPREPLY=($(compgen -W "-v --fetch --fields --no-ci --size --created --sort --pin-current --filter-status --stale --format --paths-only -0" -- "$cur"))
+            return 0
+            ;;
+        info)
+            COMPREPLY=($(compgen -W "--json --format" -- "$cur"))
+            return 0
+            ;;
+        doctor)
+            COMPREPLY=($(compgen -W "--format" -- "$cur"))
+            return 0
+            ;;
+        init)
+            if [[ $prev == --hook ]]; then
+                COMPREPLY=($(compgen -W "node go rust python auto none" -- "$cur"))
+            else
+                COMPREPLY=($(compgen -W "-project --bare --dry-run -y --hook --no-hook" -- "$cur"))
+            fi
+            return 0
+            ;;
+        prune)
+            COMPREPLY=($(compgen -W "--expire --dry-run" -- "$cur"))
+            return 0
+            ;;
+        repair)
+            COMPREPLY=($(compgen -d -- "$cur"))
             return 0
             ;;
         cleanup)
-            COMPREPLY=($(compgen -W "-f --force-delete --dry-run" -- "$cur"))
+            COMPREPLY=($(compgen -W "-f -i --interactive --force-delete --dry-run --fetch --reason --merged-only --remote-gone-only --closed-only --keep --created-before --with-branch" -- "$cur"))
             return 0
             ;;
         shell-init|completion)
             COMPREPLY=($(compgen -W "bash zsh fish" -- "$cur"))
             return 0
             ;;
+        diff)
+            COMPREPLY=($(compgen -W "--base" -- "$cur"))
+            return 0
+            ;;
+        config)
+            COMPREPLY=($(compgen -W "create show list approvals" -- "$cur"))
+            return 0
+            ;;
+        help)
+            COMPREPLY=($(compgen -W "create merge for-each step hooks" -- "$cur"))
+            return 0
+            ;;
+        install-skill)
+            COMPREPLY=($(compgen -W "-p --path -f --force" -- "$cur"))
+            return 0
+            ;;
         marker)
             case ${words[2]} in
                 set)
                     COMPREPLY=($(compgen -W "working waiting idle -branch" -- "$cur"))
                     return 0
                     ;;
-                clear|get)
-                    COMPREPLY=($(compgen -W "-branch -all" -- "$cur"))
+                clear)
+                    COMPREPLY=($(compgen -W "-branch -all -expired" -- "$cur"))
+                    return 0
+                    ;;
+                get)
+                    COMPREPLY=($(compgen -W "-branch" -- "$cur"))
                     return 0
                     ;;
                 *)
@@ -201,7 +264,11 @@ _gren_completions() {
             ;;
         step)
             case ${words[2]} in
-                commit|squash)
+                commit)
+                    COMPREPLY=($(compgen -W "-m --llm -i --interactive" -- "$cur") $(compgen -f -- "$cur"))
+                    return 0
+                    ;;
+                squash)
                     COMPREPLY=($(compgen -W "-m --llm" -- "$cur"))
                     return 0
                     ;;
@@ -229,19 +296,33 @@ _gren() {
         'list:List all worktrees'
         'delete:Delete a worktree'
         'cleanup:Delete all stale worktrees'
+        'prune:Forget worktrees whose directories are gone'
+        'repair:Reconnect worktrees after moving them or the repo'
+        'relocate:Move worktrees to where worktree_dir puts them'
         'init:Initialize gren in repository'
         'navigate:Navigate to a worktree'
         'switch:Navigate to a worktree'
         'cd:Navigate to a worktree'
         'compare:Compare changes between worktrees'
+        'diff:Show all changes since branching from the base branch'
         'merge:Merge current worktree into target'
         'for-each:Run command in all worktrees'
+        'exec:Run a command in one worktree'
         'step:Commit/squash operations'
+        'push:Push a worktree branch'
+        'set-upstream:Set tracking branch for a worktree'
+        'open:Open a worktree in an editor or terminal'
+        'reattach:Put a detached worktree on a new branch'
+        'info:Show repository metadata'
+        'doctor:Diagnose setup problems'
+        'config:Manage gren configuration'
         'marker:Manage Claude activity markers'
         'statusline:Output status for shell prompts'
         'shell-init:Generate shell integration'
         'completion:Generate completion scripts'
         'logs:Show gren log (--path, -f, --last, --hooks)'
+        'help:Show detailed help for a topic'
+        'install-skill:Install the gren skill for Claude Code'
         'setup-claude-plugin:Create Claude plugin hooks'
     )
 
@@ -255,7 +336,7 @@ _gren() {
             ;;
         args)
             case $words[2] in
-                delete|compare|navigate|switch|cd|nav)
+                delete|compare|push|set-upstream|open|reattach|navigate|switch|cd|nav)
                     local -a worktrees
                     worktrees=(${(f)"$(COMPLETE=1 gren __complete worktrees "" 2>/dev/null)"})
                     _describe -t worktrees 'worktrees' worktrees
@@ -266,8 +347,16 @@ _gren() {
                         '-b[Base branch]:branch:' \
                         '--branch[Branch name]:branch:' \
                         '--existing[Use existing branch]' \
+                        '--track-remote[Always create from origin/<branch>]' \
+                        '--remote[Create from <remote>/<branch>]:remote:($(git remote 2>/dev/null))' \
+                        '--all-matching[Create worktrees for matching remote branches]:glob:' \
+                        '--dry-run[List what --all-matching would create]' \
+                        '--preset[Apply a preset from the project config]:preset:' \
+                        '--base-from-pr[Base the new branch on a PR head branch]:PR number:' \
                         '--dir[Worktree directory]:directory:_files -/' \
-                        '-x[Execute command]:command:'
+                        '--path[Exact worktree path]:path:_files -/' \
+                        '-x[Execute command]:command:' \
+                        '--open[Go to the new worktree]'
                     ;;
                 merge)
                     local -a branches
@@ -279,20 +368,93 @@ _gren() {
                         '--no-verify[Skip hooks]' \
                         '--no-rebase[Skip rebase]' \
                         '-y[Skip confirmation]' \
-                        '-f[Force merge]'
+                        '-f[Force merge]' \
+                        '--into-current[Merge target into the current branch]' \
+                        '--abort-on-conflict[Abort an --into-current merge on conflicts]' \
+                        '--dry-run[Show what the merge would do]'
                     ;;
                 list)
-                    _arguments '-v[Verbose output]'
+                    _arguments \
+                        '-v[Verbose output]' \
+                        '--fetch[Fetch from origin first]' \
+                        '--fields[Comma-separated fields to show]:fields:_values -s , field name branch path status current main last_commit created staged modified untracked unpushed upstream stale pr ci' \
+                        '--no-ci[Skip CI status lookups]' \
+                        '--size[Show disk usage, largest first]' \
+                        '--created[Show when each worktree was created]' \
+                        '--sort[Sort order]:mode:(recent name branch status stale)' \
+                        '--pin-current[List the current worktree first]' \
+                        '--filter-status[Only worktrees with these statuses]:status:_values -s , status clean modified untracked mixed unpushed missing unknown active stale' \
+                        '--stale[Only stale worktrees; exit 3 if any]' \
+                        '--format[Output format: json or a Go template]:format:(json)' \
+                        '--paths-only[Print only worktree paths]' \
+                        '-0[NUL-separate --paths-only output]'
+                    ;;
+                info)
+                    _arguments \
+                        '--json[Machine-readable output]' \
+                        '--format[Output format]:format:(json)'
+                    ;;
+                doctor)
+                    _arguments \
+                        '--format[Output format]:format:(json)'
+                    ;;
+                init)
+                    _arguments \
+                        '-project[Project name]:name:' \
+                        '--bare[Set up the bare-clone layout]' \
+                        '--dry-run[Show the --bare steps without running them]' \
+                        '-y[Skip the confirmation]' \
+                        '--hook[Post-create hook template]:template:(node go rust python auto none)' \
+                        '--no-hook[Create no post-create hook]'
+                    ;;
+                prune)
+                    _arguments \
+                        '--expire[Only records older than this]:time:' \
+                        '--dry-run[Show what would be pruned]'
+                    ;;
+                repair)
+                    _arguments \
+                        '*:path:_directories'
+                    ;;
+                relocate)
+                    _arguments \
+                        '--dry-run[Show what would be moved]' \
+                        '*:worktree:($(COMPLETE=1 gren __complete worktrees "" 2>/dev/null))'
                     ;;
                 cleanup)
                     _arguments \
                         '-f[Skip confirmation]' \
+                        '(-i --interactive)'{-i,--interactive}'[Pick worktrees to delete by number]' \
                         '--force-delete[Force delete]' \
-                        '--dry-run[Show what would be deleted]'
+                        '--dry-run[Show what would be deleted]' \
+                        '--fetch[Fetch from origin first]' \
+                        '--reason[Only these stale reasons]:reason:(merged_locally no_unique_commits remote_gone pr_merged pr_closed branch_deleted)' \
+                        '--merged-only[Only merged worktrees]' \
+                        '--remote-gone-only[Only worktrees whose remote branch is gone]' \
+                        '--closed-only[Only worktrees whose PR was closed]' \
+                        '--keep[Keep the N most recent stale worktrees]:count:' \
+                        '--created-before[Only worktrees created before a date or age]:date:' \
+                        '--with-branch[Also delete the local branches]'
                     ;;
                 shell-init|completion)
                     _arguments '1:shell:(bash zsh fish)'
                     ;;
+                diff)
+                    local -a branches
+                    branches=(${(f)"$(COMPLETE=1 gren __complete branches "" 2>/dev/null)"})
+                    _arguments '--base[Base branch to diff against]:branch:($branches)'
+                    ;;
+                config)
+                    _arguments '1:subcommand:(create show list approvals)'
+                    ;;
+                help)
+                    _arguments '1:topic:(create merge for-each step hooks)'
+                    ;;
+                install-skill)
+                    _arguments \
+                        {-p,--path}'[Parent directory]:directory:_files -/' \
+                        {-f,--force}'[Overwrite existing files]'
+                    ;;
                 marker)
                     local -a subcommands
                     subcommands=(
@@ -340,7 +502,9 @@ _gren() {
                         subargs)
                             _arguments \
                                 '-m[Commit message]:message:' \
-                                '--llm[Use LLM for message]'
+                                '--llm[Use LLM for message]' \
+                                '(-i --interactive)'{-i,--interactive}'[Pick the files to commit]' \
+                                '*:file:_files'
                             ;;
                     esac
                     ;;
@@ -350,6 +514,11 @@ _gren() {
                         '--skip-main[Skip main worktree]' \
                         '--[Command separator]:command:_command_names'
                     ;;
+                exec)
+                    _arguments \
+                        '1:worktree:($(COMPLETE=1 gren __complete worktrees "" 2>/dev/null))' \
+                        '--[Command separator]:command:_command_names'
+                    ;;
             esac
             ;;
     esac
@@ -369,19 +538,33 @@ complete -c gren -n '__fish_use_subcommand' -a create -d 'Create a new worktree'
 complete -c gren -n '__fish_use_subcommand' -a list -d 'List all worktrees'
 complete -c gren -n '__fish_use_subcommand' -a delete -d 'Delete a worktree'
 complete -c gren -n '__fish_use_subcommand' -a cleanup -d 'Delete all stale worktrees'
+complete -c gren -n '__fish_use_subcommand' -a prune -d 'Forget worktrees whose directories are gone'
+complete -c gren -n '__fish_use_subcommand' -a repair -d 'Reconnect worktrees after moving them or the repo'
+complete -c gren -n '__fish_use_subcommand' -a relocate -d 'Move worktrees to where worktree_dir puts them'
 complete -c gren -n '__fish_use_subcommand' -a init -d 'Initialize gren in repository'
 complete -c gren -n '__fish_use_subcommand' -a navigate -d 'Navigate to a worktree'
 complete -c gren -n '__fish_use_subcommand' -a switch -d 'Navigate to a worktree'
 complete -c gren -n '__fish_use_subcommand' -a cd -d 'Navigate to a worktree'
 complete -c gren -n '__fish_use_subcommand' -a compare -d 'Compare changes between worktrees'
+complete -c gren -n '__fish_use_subcommand' -a diff -d 'Show all changes since branching from the base branch'
 complete -c gren -n '__fish_use_subcommand' -a merge -d 'Merge current worktree into target'
 complete -c gren -n '__fish_use_subcommand' -a for-each -d 'Run command in all worktrees'
+complete -c gren -n '__fish_use_subcommand' -a exec -d 'Run a command in one worktree'
 complete -c gren -n '__fish_use_subcommand' -a step -d 'Commit/squash operations'
+complete -c gren -n '__fish_use_subcommand' -a push -d 'Push a worktree branch'
+complete -c gren -n '__fish_use_subcommand' -a set-upstream -d 'Set tracking branch for a worktree'
+complete -c gren -n '__fish_use_subcommand' -a open -d 'Open a worktree in an editor or terminal'
+complete -c gren -n '__fish_use_subcommand' -a reattach -d 'Put a detached worktree on a new branch'
+complete -c gren -n '__fish_use_subcommand' -a info -d 'Show repository metadata'
+complete -c gren -n '__fish_use_subcommand' -a doctor -d 'Diagnose setup problems'
+complete -c gren -n '__fish_use_subcommand' -a config -d 'Manage gren configuration'
 complete -c gren -n '__fish_use_subcommand' -a marker -d 'Manage Claude activity markers'
 complete -c gren -n '__fish_use_subcommand' -a statusline -d 'Output status for shell prompts'
 complete -c gren -n '__fish_use_subcommand' -a shell-init -d 'Generate shell integration'
 complete -c gren -n '__fish_use_subcommand' -a completion -d 'Generate completion scripts'
 complete -c gren -n '__fish_use_subcommand' -a logs -d 'Show gren log'
+complete -c gren -n '__fish_use_subcommand' -a help -d 'Show detailed help for a topic'
+complete -c gren -n '__fish_use_subcommand' -a install-skill -d 'Install the gren skill for Claude Code'
 complete -c gren -n '__fish_use_subcommand' -a setup-claude-plugin -d 'Create Claude plugin hooks'
 
 # Worktree completions for relevant commands
@@ -397,22 +580,69 @@ end
 complete -c gren -n '__fish_seen_subcommand_from delete' -a '(__fish_gren_worktrees)' -d 'Worktree'
 complete -c gren -n '__fish_seen_subcommand_from delete' -s f -d 'Force deletion'
 complete -c gren -n '__fish_seen_subcommand_from delete' -l dry-run -d 'Show what would be deleted'
+complete -c gren -n '__fish_seen_subcommand_from delete' -l with-branch -d 'Also delete the local branch'
 
 # navigate/switch/cd commands
 complete -c gren -n '__fish_seen_subcommand_from navigate switch cd nav' -a '(__fish_gren_worktrees)' -d 'Worktree'
+complete -c gren -n '__fish_seen_subcommand_from navigate switch cd nav' -l create -d 'Create the worktree if missing'
+complete -c gren -n '__fish_seen_subcommand_from navigate switch cd nav' -l subshell -d 'Without shell integration, open a shell in the worktree'
 
 # compare command
 complete -c gren -n '__fish_seen_subcommand_from compare' -a '(__fish_gren_worktrees)' -d 'Worktree'
 complete -c gren -n '__fish_seen_subcommand_from compare' -l diff -d 'Show unified diff'
 complete -c gren -n '__fish_seen_subcommand_from compare' -l apply -d 'Apply all changes'
+complete -c gren -n '__fish_seen_subcommand_from compare' -l reverse -d 'Swap source and target'
+
+# push command
+complete -c gren -n '__fish_seen_subcommand_from push' -a '(__fish_gren_worktrees)' -d 'Worktree'
+complete -c gren -n '__fish_seen_subcommand_from push' -s u -l set-upstream -d 'Track the pushed branch'
+complete -c gren -n '__fish_seen_subcommand_from push' -l force-with-lease -d 'Overwrite the remote branch safely'
+
+# set-upstream command
+complete -c gren -n '__fish_seen_subcommand_from set-upstream' -a '(__fish_gren_worktrees)' -d 'Worktree'
+
+# open command
+complete -c gren -n '__fish_seen_subcommand_from open' -a '(__fish_gren_worktrees)' -d 'Worktree'
+complete -c gren -n '__fish_seen_subcommand_from open' -l with -r -a 'editor terminal claude code cursor zed' -d 'Open with'
+
+# reattach command
+complete -c gren -n '__fish_seen_subcommand_from reattach' -a '(__fish_gren_worktrees)' -d 'Worktree'
+
+# diff command
+complete -c gren -n '__fish_seen_subcommand_from diff' -l base -x -a '(__fish_gren_branches)' -d 'Base branch to diff against'
+
+# config subcommands
+complete -c gren -n '__fish_seen_subcommand_from config; and not __fish_seen_subcommand_from create show list approvals' -a 'create show list approvals'
+
+# help topics
+complete -c gren -n '__fish_seen_subcommand_from help' -a 'create merge for-each step hooks'
+
+# install-skill command
+complete -c gren -n '__fish_seen_subcommand_from install-skill' -s p -l path -r -d 'Parent directory'
+complete -c gren -n '__fish_seen_subcommand_from install-skill' -s f -l force -d 'Overwrite existing files'
+
+# info command
+complete -c gren -n '__fish_seen_subcommand_from info' -l json -d 'Machine-readable output'
+complete -c gren -n '__fish_seen_subcommand_from info' -l format -ra 'json' -d 'Output format'
+
+# doctor command
+complete -c gren -n '__fish_seen_subcommand_from doctor' -l format -ra 'json' -d 'Output format'
 
 # create command
 complete -c gren -n '__fish_seen_subcommand_from create' -s n -d 'Worktree name' -r
 complete -c gren -n '__fish_seen_subcommand_from create' -l branch -d 'Branch name' -r
 complete -c gren -n '__fish_seen_subcommand_from create' -s b -d 'Base branch' -ra '(__fish_gren_branches)'
 complete -c gren -n '__fish_seen_subcommand_from create' -l existing -d 'Use existing branch'
+complete -c gren -n '__fish_seen_subcommand_from create' -l track-remote -d 'Always create from origin/<branch>'
+complete -c gren -n '__fish_seen_subcommand_from create' -l remote -d 'Create from <remote>/<branch>' -ra '(git remote 2>/dev/null)'
+complete -c gren -n '__fish_seen_subcommand_from create' -l all-matching -d 'Create worktrees for matching remote branches' -r
+complete -c gren -n '__fish_seen_subcommand_from create' -l dry-run -d 'List what --all-matching would create'
+complete -c gren -n '__fish_seen_subcommand_from create' -l preset -d 'Apply a preset from the project config' -r
+complete -c gren -n '__fish_seen_subcommand_from create' -l base-from-pr -d 'Base the new branch on a PR head branch' -r
 complete -c gren -n '__fish_seen_subcommand_from create' -l dir -d 'Worktree directory' -ra '(__fish_complete_directories)'
+complete -c gren -n '__fish_seen_subcommand_from create' -l path -d 'Exact worktree path' -ra '(__fish_complete_directories)'
 complete -c gren -n '__fish_seen_subcommand_from create' -s x -d 'Execute command' -r
+complete -c gren -n '__fish_seen_subcommand_from create' -l open -d 'Go to the new worktree'
 
 # merge command
 complete -c gren -n '__fish_seen_subcommand_from merge' -a '(__fish_gren_branches)' -d 'Target branch'
@@ -422,14 +652,55 @@ complete -c gren -n '__fish_seen_subcommand_from merge' -l no-verify -d 'Skip ho
 complete -c gren -n '__fish_seen_subcommand_from merge' -l no-rebase -d 'Skip rebase'
 complete -c gren -n '__fish_seen_subcommand_from merge' -s y -d 'Skip confirmation'
 complete -c gren -n '__fish_seen_subcommand_from merge' -s f -d 'Force merge'
+complete -c gren -n '__fish_seen_subcommand_from merge' -l into-current -d 'Merge target into the current branch'
+complete -c gren -n '__fish_seen_subcommand_from merge' -l abort-on-conflict -d 'Abort an --into-current merge on conflicts'
+complete -c gren -n '__fish_seen_subcommand_from merge' -l dry-run -d 'Show what the merge would do'
 
 # list command
 complete -c gren -n '__fish_seen_subcommand_from list' -s v -d 'Verbose output'
+complete -c gren -n '__fish_seen_subcommand_from list' -l fetch -d 'Fetch from origin first'
+complete -c gren -n '__fish_seen_subcommand_from list' -l fields -r -d 'Comma-separated fields to show'
+complete -c gren -n '__fish_seen_subcommand_from list' -l no-ci -d 'Skip CI status lookups'
+complete -c gren -n '__fish_seen_subcommand_from list' -l size -d 'Show disk usage, largest first'
+complete -c gren -n '__fish_seen_subcommand_from list' -l sort -x -a 'recent name branch status stale' -d 'Sort order'
+complete -c gren -n '__fish_seen_subcommand_from list' -l pin-current -d 'List the current worktree first'
+complete -c gren -n '__fish_seen_subcommand_from list' -l created -d 'Show when each worktree was created'
+complete -c gren -n '__fish_seen_subcommand_from list' -l filter-status -x -a 'clean modified untracked mixed unpushed missing unknown active stale' -d 'Only worktrees with these statuses'
+complete -c gren -n '__fish_seen_subcommand_from list' -l stale -d 'Only stale worktrees; exit 3 if any'
+complete -c gren -n '__fish_seen_subcommand_from list' -l format -ra 'json' -d 'Output format: json or a Go template'
+complete -c gren -n '__fish_seen_subcommand_from list' -l paths-only -d 'Print only worktree paths'
+complete -c gren -n '__fish_seen_subcommand_from list' -s 0 -d 'NUL-separate --paths-only output'
+
+# prune command
+complete -c gren -n '__fish_seen_subcommand_from init' -o project -r -d 'Project name'
+complete -c gren -n '__fish_seen_subcommand_from init' -l bare -d 'Set up the bare-clone layout'
+complete -c gren -n '__fish_seen_subcommand_from init' -l dry-run -d 'Show the --bare steps without running them'
+complete -c gren -n '__fish_seen_subcommand_from init' -s y -d 'Skip the confirmation'
+complete -c gren -n '__fish_seen_subcommand_from init' -l hook -x -a 'node go rust python auto none' -d 'Post-create hook template'
+complete -c gren -n '__fish_seen_subcommand_from init' -l no-hook -d 'Create no post-create hook'
+complete -c gren -n '__fish_seen_subcommand_from prune' -l expire -r -d 'Only records older than this'
+complete -c gren -n '__fish_seen_subcommand_from prune' -l dry-run -d 'Show what would be pruned'
+
+# repair command
+complete -c gren -n '__fish_seen_subcommand_from repair' -a '(__fish_complete_directories)'
+
+# relocate command
+complete -c gren -n '__fish_seen_subcommand_from relocate' -a '(__fish_gren_worktrees)' -d 'Worktree'
+complete -c gren -n '__fish_seen_subcommand_from relocate' -l dry-run -d 'Show what would be moved'
 
 # cleanup command
 complete -c gren -n '__fish_seen_subcommand_from cleanup' -s f -d 'Skip confirmation'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -s i -l interactive -d 'Pick worktrees to delete by number'
 complete -c gren -n '__fish_seen_subcommand_from cleanup' -l force-delete -d 'Force delete'
 complete -c gren -n '__fish_seen_subcommand_from cleanup' -l dry-run -d 'Show what would be deleted'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l fetch -d 'Fetch from origin first'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l reason -r -a 'merged_locally no_unique_commits remote_gone pr_merged pr_closed branch_deleted' -d 'Only these stale reasons'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l merged-only -d 'Only merged worktrees'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l remote-gone-only -d 'Only worktrees whose remote branch is gone'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l closed-only -d 'Only worktrees whose PR was closed'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l keep -x -d 'Keep the N most recent stale worktrees'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l created-before -x -d 'Only worktrees created before a date or age'
+complete -c gren -n '__fish_seen_subcommand_from cleanup' -l with-branch -d 'Also delete the local branches'
 
 # shell-init and completion commands
 complete -c gren -n '__fish_seen_subcommand_from shell-init completion' -a 'bash zsh fish' -d 'Shell type'
@@ -446,6 +717,7 @@ complete -c gren -n '__fish_seen_subcommand_from marker; and __fish_seen_subcomm
 
 # marker clear
 complete -c gren -n '__fish_seen_subcommand_from marker; and __fish_seen_subcommand_from clear' -l all -d 'Clear all markers'
+complete -c gren -n '__fish_seen_subcommand_from marker; and __fish_seen_subcommand_from clear' -l expired -d 'Clear markers older than marker_ttl'
 
 # step command subcommands
 complete -c gren -n '__fish_seen_subcommand_from step; and not __fish_seen_subcommand_from commit squash' -a commit -d 'Stage and commit changes'
@@ -454,10 +726,14 @@ complete -c gren -n '__fish_seen_subcommand_from step; and not __fish_seen_subco
 # step commit/squash
 complete -c gren -n '__fish_seen_subcommand_from step; and __fish_seen_subcommand_from commit squash' -s m -d 'Commit message' -r
 complete -c gren -n '__fish_seen_subcommand_from step; and __fish_seen_subcommand_from commit squash' -l llm -d 'Use LLM for message'
+complete -c gren -n '__fish_seen_subcommand_from step; and __fish_seen_subcommand_from commit' -s i -l interactive -d 'Pick the files to commit'
 
 # for-each command
 complete -c gren -n '__fish_seen_subcommand_from for-each' -l skip-current -d 'Skip current worktree'
 complete -c gren -n '__fish_seen_subcommand_from for-each' -l skip-main -d 'Skip main worktree'
+
+# exec command
+complete -c gren -n '__fish_seen_subcommand_from exec; and __fish_is_nth_token 2' -a '(__fish_gren_worktrees)' -d 'Worktree'
 `
 
 // parseCompletionEnv checks if we're in completion mode and returns the word being completed
@@ -1,8 +1,14 @@
 package cli
 
 import (
+	"os"
+	"regexp"
+	"slices"
 	"strings"
 	"testing"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/git"
 )
 
 func TestBashCompletionScript(t *testing.T) {
@@ -287,3 +293,59 @@ func TestMarkerSubcommands(t *testing.T) {
 		})
 	}
 }
+
+func TestCompletionScriptsListEveryCommand(t *testing.T) {
+	bashCommands := regexp.MustCompile(`local commands="([^"]*)"`).FindStringSubmatch(bashCompletionScript)
+	if bashCommands == nil {
+		t.Fatal("bash completion has no command list")
+	}
+	for _, cmd := range completionCommands {
+		if !slices.Contains(strings.Fields(bashCommands[1]), cmd) {
+			t.Errorf("bash completion missing command %q", cmd)
+		}
+		// zsh and fish list the aliases nav/cd under their own entries
+		if cmd == "nav" {
+			continue
+		}
+		if !strings.Contains(zshCompletionScript, "'"+cmd+":") {
+			t.Errorf("zsh completion missing command %q", cmd)
+		}
+		if !strings.Contains(fishCompletionScript, "__fish_use_subcommand' -a "+cmd+" ") {
+			t.Errorf("fish completion missing command %q", cmd)
+		}
+	}
+}
+
+func TestHandleCompletionQueryFiltersByWord(t *testing.T) {
+	c := NewCLI(newMockRepository(), config.NewManager())
+
+	out := captureStdout(t, func() {
+		c.handleCompletionQuery([]string{"commands", "co"})
+	})
+	if out != "compare\nconfig\ncompletion\n" {
+		t.Errorf("__complete commands co = %q, want the commands starting with co", out)
+	}
+}
+
+func TestHandleCompletionQueryWorktrees(t *testing.T) {
+	dir, cleanup := setupTempGitRepo(t)
+	defer cleanup()
+
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(dir)
+
+	c := NewCLI(git.NewLocalRepository(), config.NewManager())
+	out := captureStdout(t, func() {
+		c.ParseAndExecute([]string{"gren", "__complete", "branches", "ma"})
+	})
+	if out != "main\n" {
+		t.Errorf("__complete branches ma = %q, want main", out)
+	}
+	out = captureStdout(t, func() {
+		c.ParseAndExecute([]string{"gren", "__complete", "branches", "zz"})
+	})
+	if out != "" {
+		t.Errorf("__complete branches zz = %q, want nothing", out)
+	}
+}
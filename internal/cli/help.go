@@ -79,21 +79,29 @@ func (c *CLI) ShowColoredHelp() {
 	// Worktree Management
 	fmt.Println("  " + bold("Worktree Management"))
 	printCommand("create", "-n <name>", "Create a new worktree")
-	printCommand("list", "[-v]", "List all worktrees")
+	printCommand("list", "[-v] [--fields] [--sort] [--filter-status]", "List all worktrees")
 	printCommand("delete", "<name>", "Delete a worktree")
 	printCommand("cleanup", "", "Delete all stale worktrees")
+	printCommand("prune", "[--expire <time>]", "Forget worktrees whose directories are gone")
+	printCommand("repair", "[<path>...]", "Reconnect worktrees after moving them or the repo")
+	printCommand("relocate", "[name...]", "Move worktrees to where worktree_dir puts them")
 	fmt.Println()
 
 	// Navigation
 	fmt.Println("  " + bold("Navigation"))
 	printCommand("switch", "<name>", "Navigate to a worktree")
-	printCommand("compare", "<name>", "Compare changes between worktrees")
+	printCommand("compare", "<src> [target]", "Compare changes between worktrees")
+	printCommand("open", "<name>", "Open a worktree in an editor, terminal or claude")
 	fmt.Println()
 
 	// Git Operations
 	fmt.Println("  " + bold("Git Operations"))
 	printCommand("merge", "[target]", "Merge current worktree into target")
 	printCommand("for-each", "-- <cmd>", "Run command in all worktrees")
+	printCommand("exec", "<name> -- <cmd>", "Run a command in one worktree")
+	printCommand("push", "<name> [remote]", "Push a worktree's branch from anywhere")
+	printCommand("set-upstream", "<name> [remote]", "Set tracking branch for a worktree")
+	printCommand("reattach", "<name> <branch>", "Put a detached worktree on a new branch")
 	printCommand("step commit", "", "Stage and commit all changes")
 	printCommand("step squash", "[target]", "Squash commits since target")
 	fmt.Println()
@@ -101,6 +109,9 @@ func (c *CLI) ShowColoredHelp() {
 	// Configuration
 	fmt.Println("  " + bold("Configuration"))
 	printCommand("init", "", "Initialize gren in repository")
+	printCommand("init", "--bare [url]", "Clone into, or convert to, the bare-clone layout")
+	printCommand("info", "[--json]", "Show repository metadata")
+	printCommand("doctor", "", "Diagnose setup problems")
 	printCommand("shell-init", "<shell>", "Generate shell integration")
 	printCommand("completion", "<shell>", "Generate shell completions")
 	printCommand("logs", "[--path|-f|--last]", "Show gren's log")
@@ -116,8 +127,12 @@ func (c *CLI) ShowColoredHelp() {
 	fmt.Println()
 
 	fmt.Println(bold("FLAGS"))
-	fmt.Println("  " + yellow("--help") + "      " + dim("Show help for gren or a command"))
-	fmt.Println("  " + yellow("--version") + "   " + dim("Show version information"))
+	fmt.Println("  " + yellow("--help") + "            " + dim("Show help for gren or a command"))
+	fmt.Println("  " + yellow("--version") + "         " + dim("Show version information"))
+	fmt.Println("  " + yellow("-v, --verbose") + "     " + dim("Log debug output and mirror it to stderr"))
+	fmt.Println("  " + yellow("--quiet") + "           " + dim("Only log errors"))
+	fmt.Println("  " + yellow("--log-file <path>") + " " + dim("Write the log to path instead of the default"))
+	fmt.Println("  " + dim("Global flags go before the command: gren --verbose create -n feat-x"))
 	fmt.Println()
 
 	fmt.Println(bold("EXAMPLES"))
@@ -190,20 +205,25 @@ func showCreateHelp() {
 	fmt.Println()
 	fmt.Println(bold("SYNOPSIS"))
 	fmt.Println("  gren create " + yellow("-n <name>") + " [options]")
+	fmt.Println("  gren create " + yellow("--all-matching <glob>") + " [--dry-run]")
 	fmt.Println()
 	fmt.Println(bold("OPTIONS"))
 	fmt.Println("  " + yellow("-n <name>") + "          " + dim("Worktree name (required)"))
 	fmt.Println("  " + yellow("-b <branch>") + "        " + dim("Base branch to create from"))
 	fmt.Println("  " + yellow("--branch <name>") + "    " + dim("Branch name (defaults to worktree name)"))
 	fmt.Println("  " + yellow("--existing") + "         " + dim("Use existing branch instead of creating new"))
+	fmt.Println("  " + yellow("--track-remote") + "     " + dim("Always create from origin/<branch> (drops local unpushed commits)"))
 	fmt.Println("  " + yellow("--dir <path>") + "       " + dim("Directory for worktrees"))
 	fmt.Println("  " + yellow("-x <command>") + "       " + dim("Command to run after creation"))
+	fmt.Println("  " + yellow("--all-matching <glob>") + " " + dim("Create worktrees for all matching remote branches"))
+	fmt.Println("  " + yellow("--dry-run") + "          " + dim("With --all-matching, only list what would be created"))
 	fmt.Println()
 	fmt.Println(bold("EXAMPLES"))
 	fmt.Println("  $ gren create -n feat-auth")
 	fmt.Println("  $ gren create -n hotfix -b main")
 	fmt.Println("  $ gren create -n feat-ui -x claude")
 	fmt.Println("  $ gren create -n existing-feature --existing --branch feature/old")
+	fmt.Println("  $ gren create --all-matching 'feature/*' --dry-run")
 	fmt.Println()
 }
 
@@ -231,12 +251,21 @@ func showMergeHelp() {
 	fmt.Println("  " + yellow("--no-rebase") + "    " + dim("Skip rebase (fail if not already rebased)"))
 	fmt.Println("  " + yellow("-y") + "             " + dim("Skip confirmation prompts"))
 	fmt.Println("  " + yellow("-f") + "             " + dim("Force merge even with uncommitted changes"))
+	fmt.Println("  " + yellow("--into-current") + " " + dim("Merge target into the current branch instead"))
+	fmt.Println("  " + yellow("--abort-on-conflict") + " " + dim("With --into-current, abort on conflicts"))
+	fmt.Println("  " + yellow("--dry-run") + "      " + dim("Show what the merge would do without doing it"))
+	fmt.Println()
+	fmt.Println(bold("CONFLICTS"))
+	fmt.Println("  A rebase that hits conflicts is aborted and the conflicted files listed.")
+	fmt.Println("  An --into-current merge leaves them to resolve, unless --abort-on-conflict.")
 	fmt.Println()
 	fmt.Println(bold("EXAMPLES"))
 	fmt.Println("  $ gren merge                  " + dim("# Merge to default branch"))
 	fmt.Println("  $ gren merge main             " + dim("# Merge to main"))
 	fmt.Println("  $ gren merge --no-remove      " + dim("# Keep worktree"))
 	fmt.Println("  $ gren merge --no-squash      " + dim("# Preserve history"))
+	fmt.Println("  $ gren merge --into-current   " + dim("# Bring the default branch in"))
+	fmt.Println("  $ gren merge --dry-run        " + dim("# Preview before merging"))
 	fmt.Println()
 }
 
@@ -254,7 +283,7 @@ func showForEachHelp() {
 	fmt.Println()
 	fmt.Println(bold("TEMPLATE VARIABLES"))
 	fmt.Println("  " + cyan("{{ branch }}") + "           " + dim("Branch name"))
-	fmt.Println("  " + cyan("{{ branch | sanitize }}") + " " + dim("Branch with / replaced by -"))
+	fmt.Println("  " + cyan("{{ branch | sanitize }}") + " " + dim("Branch as a directory name (/, :, # → -)"))
 	fmt.Println("  " + cyan("{{ branch | hash_port }}") + " " + dim("Deterministic port 10000-19999 from branch"))
 	fmt.Println("  " + cyan("{{ branch | sanitize_db }}") + " " + dim("Branch as a safe database identifier"))
 	fmt.Println("  " + cyan("{{ worktree }}") + "         " + dim("Absolute path to worktree"))
@@ -280,11 +309,12 @@ func showStepHelp() {
 	fmt.Println()
 	fmt.Println(bold("SUBCOMMANDS"))
 	fmt.Println()
-	fmt.Println("  " + cyan("gren step commit") + " [options]")
-	fmt.Println("    Stage and commit all changes")
+	fmt.Println("  " + cyan("gren step commit") + " [options] [path...]")
+	fmt.Println("    Stage and commit all changes, or only the given paths")
 	fmt.Println()
 	fmt.Println("    " + yellow("-m <message>") + "   " + dim("Commit message"))
 	fmt.Println("    " + yellow("--llm") + "          " + dim("Generate message using configured LLM"))
+	fmt.Println("    " + yellow("-i") + "             " + dim("Pick the files to commit from a list"))
 	fmt.Println()
 	fmt.Println("  " + cyan("gren step squash") + " [target] [options]")
 	fmt.Println("    Squash commits since target branch into one")
@@ -304,6 +334,8 @@ func showStepHelp() {
 	fmt.Println("  $ gren step commit")
 	fmt.Println("  $ gren step commit -m \"feat: add feature\"")
 	fmt.Println("  $ gren step commit --llm")
+	fmt.Println("  $ gren step commit -m \"fix: typo\" docs/guide.md")
+	fmt.Println("  $ gren step commit -i")
 	fmt.Println("  $ gren step squash main")
 	fmt.Println("  $ gren step squash --llm")
 	fmt.Println("  $ PORT=$(gren step eval '{{ branch | hash_port }}') npm run dev")
@@ -84,3 +84,15 @@ func printHookEvents(results []core.HookResult) {
 		}
 	}
 }
+
+// failedHookCount returns how many hooks in results failed, for the warning
+// count of a command's summary line when a failing hook doesn't undo it.
+func failedHookCount(results []core.HookResult) int {
+	count := 0
+	for _, r := range results {
+		if r.Err != nil {
+			count++
+		}
+	}
+	return count
+}
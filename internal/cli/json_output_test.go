@@ -3,7 +3,9 @@ package cli
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -158,6 +160,34 @@ func TestDeleteJSONDryRunReportsBlockingContent(t *testing.T) {
 	}
 }
 
+// TestDeleteJSONDanglingSymlinksDoNotBlock covers a post-create .env link whose
+// target has moved: git lists it as untracked, but it holds nothing, so it is
+// reported separately and the delete removes it instead of needing -f for it.
+func TestDeleteJSONDanglingSymlinksDoNotBlock(t *testing.T) {
+	_, worktreePath := deleteJSONRepo(t, "dangling")
+
+	if err := os.Symlink("/nonexistent/gren-test/.env", filepath.Join(worktreePath, ".env")); err != nil {
+		t.Fatal(err)
+	}
+
+	result, _ := runDeleteJSON(t, "--dry-run", "--format=json", "dangling")
+
+	if len(result.DanglingSymlinks) != 1 || result.DanglingSymlinks[0] != ".env" {
+		t.Errorf("dangling_symlinks = %v, want [.env]", result.DanglingSymlinks)
+	}
+	if result.Blocking != nil || result.WouldForce {
+		t.Errorf("a dangling symlink alone must not block: blocking=%+v would_force=%v", result.Blocking, result.WouldForce)
+	}
+
+	result, errored := runDeleteJSON(t, "-f", "--format=json", "dangling")
+	if errored || !result.Deleted {
+		t.Fatalf("delete -f failed: %+v", result)
+	}
+	if len(result.DanglingSymlinks) != 1 {
+		t.Errorf("dangling_symlinks after delete = %v, want the removed .env", result.DanglingSymlinks)
+	}
+}
+
 // TestDeleteJSONWithoutForceRefuses locks in that JSON mode never prompts.
 // Its callers — plugins, agents, CI — cannot answer a y/N, and a prompt that
 // nobody answers is a hang, not a safety feature.
@@ -233,6 +263,52 @@ func hookRunJSONRepo(t *testing.T, name, hookCmd string) (repoDir, worktreePath
 	return dir, worktreePath
 }
 
+// runCreateJSON runs `gren create -y --format=json` and parses its stdout.
+func runCreateJSON(t *testing.T, name string) (CreateJSON, error) {
+	t.Helper()
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+
+	var cmdErr error
+	stdout := captureStdout(t, func() {
+		captureStderr(t, func() {
+			cmdErr = cli.ParseAndExecute([]string{"gren", "create", "-n", name, "-y", "--format=json"})
+		})
+	})
+
+	var result CreateJSON
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("create --format=json stdout must be pure JSON, got parse error %v\nstdout: %q", err, stdout)
+	}
+	return result, cmdErr
+}
+
+// TestCreatePostCreateHookFailure covers a setup script that breaks: the
+// create still succeeds, but its warning carries the end of the hook's
+// output, and hooks_required turns it into a failure.
+func TestCreatePostCreateHookFailure(t *testing.T) {
+	dir, _ := hookRunJSONRepo(t, "base", "echo missing-dependency >&2; exit 3")
+
+	result, err := runCreateJSON(t, "hook-warn")
+	if err != nil {
+		t.Fatalf("a failing post-create hook must not fail the create: %v", err)
+	}
+	if !strings.Contains(result.Warning, "post-create hook failed") || !strings.Contains(result.Warning, "missing-dependency") {
+		t.Errorf("warning = %q, want the hook failure and its output", result.Warning)
+	}
+
+	configPath := filepath.Join(dir, ".gren", "config.toml")
+	data, _ := os.ReadFile(configPath)
+	os.WriteFile(configPath, append([]byte("hooks_required = true\n"), data...), 0644)
+
+	result, err = runCreateJSON(t, "hook-required")
+	if err == nil || !strings.Contains(err.Error(), "hooks_required") {
+		t.Errorf("create with hooks_required = %v, want a failure", err)
+	}
+	if result.Path == "" || !strings.Contains(result.Warning, "missing-dependency") {
+		t.Errorf("hooks_required result = %+v, want the kept worktree and the hook output", result)
+	}
+}
+
 // runHookRunJSON runs `gren hook-run --format=json` and asserts stdout parses.
 func runHookRunJSON(t *testing.T, args ...string) (HookRunJSON, bool) {
 	t.Helper()
@@ -314,3 +390,268 @@ func TestHookRunJSONUnknownTypeIsStructured(t *testing.T) {
 		t.Errorf("error is empty for an unknown hook type")
 	}
 }
+
+// TestDeleteOperationInProgress: a worktree stopped mid-merge is refused
+// without -f, and a dry run reports the operation and that -f is needed.
+func TestDeleteOperationInProgress(t *testing.T) {
+	repoDir, worktreePath := deleteJSONRepo(t, "mid-merge")
+
+	os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("# Branch\n"), 0644)
+	exec.Command("git", "-C", worktreePath, "commit", "-am", "branch change").Run()
+	os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("# Main\n"), 0644)
+	exec.Command("git", "-C", repoDir, "commit", "-am", "main change").Run()
+	exec.Command("git", "-C", worktreePath, "merge", "main").Run()
+
+	result, errored := runDeleteJSON(t, "--dry-run", "--format=json", "mid-merge")
+	if errored {
+		t.Errorf("a dry run must not fail")
+	}
+	if result.Operation != "merge" || !result.WouldForce {
+		t.Errorf("dry run = operation %q, would_force %v; want merge, true", result.Operation, result.WouldForce)
+	}
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	err := cli.ParseAndExecute([]string{"gren", "delete", "mid-merge"})
+	if err == nil || !strings.Contains(err.Error(), "merge in progress") {
+		t.Errorf("delete without -f = %v, want merge in progress error", err)
+	}
+	if _, statErr := os.Stat(worktreePath); statErr != nil {
+		t.Errorf("worktree removed despite refusal: %v", statErr)
+	}
+}
+
+// TestDeleteUnpushedCommits: commits no remote has, with no open or merged
+// PR, are listed and refused without -f; an open PR lifts the refusal.
+func TestDeleteUnpushedCommits(t *testing.T) {
+	_, worktreePath := deleteJSONRepo(t, "local-work")
+	os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("feature\n"), 0644)
+	exec.Command("git", "-C", worktreePath, "add", ".").Run()
+	exec.Command("git", "-C", worktreePath, "commit", "-m", "local work").Run()
+
+	result, _ := runDeleteJSON(t, "--dry-run", "--format=json", "local-work")
+	if result.UnpushedCommits != 1 || !result.Unmerged || !result.WouldForce {
+		t.Errorf("dry run = unpushed %d, unmerged %v, would_force %v; want 1, true, true", result.UnpushedCommits, result.Unmerged, result.WouldForce)
+	}
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	cli.prProvider = &mockCIProvider{}
+	var err error
+	stdout := captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "delete", "local-work"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "not pushed anywhere") {
+		t.Errorf("delete without -f = %v, want a refusal naming the unpushed commits", err)
+	}
+	if !strings.Contains(stdout, "1 commit(s) not pushed to any remote") || !strings.Contains(stdout, "branch not merged") {
+		t.Errorf("delete output = %q, want the risk summary", stdout)
+	}
+	if _, statErr := os.Stat(worktreePath); statErr != nil {
+		t.Fatalf("worktree removed despite refusal: %v", statErr)
+	}
+
+	// An open PR holds the work: only the usual confirmation is left, which
+	// a test (no terminal) cannot give
+	cli.prProvider = &mockCIProvider{available: true, pr: &git.PRInfo{Number: 7, State: "OPEN"}}
+	captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "delete", "local-work"})
+	})
+	if err == nil || !strings.Contains(err.Error(), "non-interactive mode") {
+		t.Errorf("delete with an open PR = %v, want only the confirmation to stop it", err)
+	}
+
+	cli.prProvider = &mockCIProvider{}
+	stdout = captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "delete", "-f", "local-work"})
+	})
+	if err != nil {
+		t.Fatalf("delete -f failed: %v", err)
+	}
+	if !strings.Contains(stdout, "with 1 commit(s) that are not pushed anywhere") {
+		t.Errorf("delete -f output = %q, want an explicit warning", stdout)
+	}
+}
+
+func TestDeleteWithBranch(t *testing.T) {
+	repoDir, worktreePath := deleteJSONRepo(t, "merged-work")
+	branchExists := func() bool {
+		return exec.Command("git", "-C", repoDir, "rev-parse", "--verify", "--quiet", "refs/heads/merged-work").Run() == nil
+	}
+
+	result, failed := runDeleteJSON(t, "-f", "--with-branch", "--format=json", "merged-work")
+	if failed {
+		t.Fatalf("delete --with-branch failed: %+v", result)
+	}
+	if !result.Deleted || result.BranchKept || branchExists() {
+		t.Errorf("delete --with-branch = deleted %v, branch_kept %v, branch exists %v; want the branch gone too", result.Deleted, result.BranchKept, branchExists())
+	}
+	if _, statErr := os.Stat(worktreePath); !os.IsNotExist(statErr) {
+		t.Errorf("worktree still exists: %v", statErr)
+	}
+}
+
+func TestDeleteWithBranchRefusesUnmerged(t *testing.T) {
+	repoDir, worktreePath := deleteJSONRepo(t, "unmerged-work")
+	os.WriteFile(filepath.Join(worktreePath, "feature.txt"), []byte("feature\n"), 0644)
+	exec.Command("git", "-C", worktreePath, "add", ".").Run()
+	exec.Command("git", "-C", worktreePath, "commit", "-m", "unmerged work").Run()
+	exec.Command("git", "-C", worktreePath, "update-ref", "refs/remotes/origin/unmerged-work", "HEAD").Run()
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	cli.prProvider = &mockCIProvider{}
+	var err error
+	captureStdout(t, func() {
+		err = cli.ParseAndExecute([]string{"gren", "delete", "--with-branch", "unmerged-work"})
+	})
+	if !errors.Is(err, core.ErrBranchNotMerged) {
+		t.Errorf("delete --with-branch of an unmerged branch = %v, want ErrBranchNotMerged", err)
+	}
+	if _, statErr := os.Stat(worktreePath); statErr != nil {
+		t.Fatalf("worktree removed despite refusal: %v", statErr)
+	}
+
+	result, failed := runDeleteJSON(t, "-f", "--with-branch", "--format=json", "unmerged-work")
+	if failed || result.BranchKept {
+		t.Errorf("delete -f --with-branch = %+v; want the branch force-deleted", result)
+	}
+	if exec.Command("git", "-C", repoDir, "rev-parse", "--verify", "--quiet", "refs/heads/unmerged-work").Run() == nil {
+		t.Error("unmerged branch survived delete -f --with-branch")
+	}
+}
+
+// TestInfoJSON runs `gren info --json` from a linked worktree: the payload
+// must describe the repository, not the worktree it was run from.
+func TestInfoJSON(t *testing.T) {
+	dir, worktreePath := deleteJSONRepo(t, "info-wt")
+	os.Chdir(worktreePath)
+	t.Setenv("GREN_DIRECTIVE_FILE", filepath.Join(t.TempDir(), "directive"))
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	cli.prProvider = &mockCIProvider{available: true}
+
+	var cmdErr error
+	stdout := captureStdout(t, func() {
+		cmdErr = cli.ParseAndExecute([]string{"gren", "info", "--json"})
+	})
+	if cmdErr != nil {
+		t.Fatalf("info --json failed: %v", cmdErr)
+	}
+
+	var info InfoJSON
+	if err := json.Unmarshal([]byte(stdout), &info); err != nil {
+		t.Fatalf("info --json stdout must be pure JSON, got parse error %v\nstdout: %q", err, stdout)
+	}
+
+	resolvedDir, _ := filepath.EvalSymlinks(dir)
+	resolvedRoot, _ := filepath.EvalSymlinks(info.Root)
+	if resolvedRoot != resolvedDir {
+		t.Errorf("root = %q, want main worktree %q", info.Root, dir)
+	}
+	if info.Name != filepath.Base(dir) {
+		t.Errorf("name = %q, want %q", info.Name, filepath.Base(dir))
+	}
+	if info.CurrentBranch != "info-wt" {
+		t.Errorf("current_branch = %q, want info-wt", info.CurrentBranch)
+	}
+	if info.DefaultBranch != "main" {
+		t.Errorf("default_branch = %q, want main", info.DefaultBranch)
+	}
+	resolvedWorktreeDir, _ := filepath.EvalSymlinks(info.WorktreeDir)
+	if want, _ := filepath.EvalSymlinks(filepath.Dir(worktreePath)); resolvedWorktreeDir != want {
+		t.Errorf("worktree_dir = %q, want %q", info.WorktreeDir, filepath.Dir(worktreePath))
+	}
+	if info.WorktreeCount != 2 {
+		t.Errorf("worktree_count = %d, want 2", info.WorktreeCount)
+	}
+	if !info.Initialized {
+		t.Error("initialized = false after gren init")
+	}
+	if info.Forge != (ForgeJSON{Provider: "mock", Available: true}) {
+		t.Errorf("forge = %+v, want mock provider available", info.Forge)
+	}
+	if !info.ShellIntegration {
+		t.Error("shell_integration = false with GREN_DIRECTIVE_FILE set")
+	}
+}
+
+func TestInfoRejectsConflictingFormat(t *testing.T) {
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	if err := cli.ParseAndExecute([]string{"gren", "info", "--json", "--format=yaml"}); err == nil {
+		t.Error("info --json --format=yaml = nil error, want conflict")
+	}
+}
+
+func TestDoctorJSON(t *testing.T) {
+	dir, _ := deleteJSONRepo(t, "doctor-wt")
+	t.Setenv("GREN_DIRECTIVE_FILE", "")
+
+	runDoctor := func() (DoctorJSON, error) {
+		cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+		cli.prProvider = &mockCIProvider{available: false}
+		var cmdErr error
+		stdout := captureStdout(t, func() {
+			captureStderr(t, func() {
+				cmdErr = cli.ParseAndExecute([]string{"gren", "doctor", "--format=json"})
+			})
+		})
+		var result DoctorJSON
+		if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+			t.Fatalf("doctor --format=json stdout must be pure JSON, got parse error %v\nstdout: %q", err, stdout)
+		}
+		return result, cmdErr
+	}
+
+	result, err := runDoctor()
+	if err != nil || !result.OK {
+		t.Fatalf("doctor = %+v, %v; want ok with only warnings", result, err)
+	}
+	var names []string
+	statuses := make(map[string]string)
+	for _, check := range result.Checks {
+		names = append(names, check.Name)
+		statuses[check.Name] = check.Status
+	}
+	want := "git,home directory,forge CLI,shell integration,config,post-create hook,worktree dir,worktree links,detached worktrees,branch names,upstreams"
+	if got := strings.Join(names, ","); got != want {
+		t.Errorf("checks = %s, want %s", got, want)
+	}
+	if statuses["forge CLI"] != "warn" || statuses["shell integration"] != "warn" {
+		t.Errorf("forge CLI = %s, shell integration = %s, want warn for both", statuses["forge CLI"], statuses["shell integration"])
+	}
+	if statuses["post-create hook"] != "ok" {
+		t.Errorf("post-create hook = %s after gren init, want ok", statuses["post-create hook"])
+	}
+
+	os.Chmod(filepath.Join(dir, ".gren", "post-create.sh"), 0644)
+	result, err = runDoctor()
+	if err == nil || result.OK {
+		t.Errorf("doctor = %+v, %v with a non-executable hook; want ok=false and an error", result.OK, err)
+	}
+}
+
+func TestConfigKeyWarningsGoToStderr(t *testing.T) {
+	dir, _ := deleteJSONRepo(t, "warn-wt")
+	configPath := filepath.Join(dir, ".gren", "config.toml")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(configPath, append(data, []byte("\nworktre_dir = \"typo\"\n")...), 0644)
+
+	cli := NewCLI(git.NewLocalRepository(), config.NewManager())
+	var cmdErr error
+	var stderr string
+	stdout := captureStdout(t, func() {
+		stderr = captureStderr(t, func() {
+			cmdErr = cli.ParseAndExecute([]string{"gren", "list", "--format=json"})
+		})
+	})
+	if cmdErr != nil {
+		t.Fatalf("list failed: %v", cmdErr)
+	}
+	if !json.Valid([]byte(stdout)) {
+		t.Errorf("list --format=json stdout must stay pure JSON, got %q", stdout)
+	}
+	if !strings.Contains(stderr, `unknown config key "worktre_dir"`) || !strings.Contains(stderr, `did you mean "worktree_dir"`) {
+		t.Errorf("stderr = %q, want the misspelled key reported", stderr)
+	}
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/core"
+)
+
+// listTemplateFuncs are the functions a `gren list --format` template can
+// call besides Go's built-ins.
+var listTemplateFuncs = template.FuncMap{
+	"sanitize":  core.SanitizeBranch,
+	"shortpath": shortPath,
+}
+
+// isListTemplate reports whether a --format value is a Go template rather
+// than the name of a format, as docker and gh tell them apart.
+func isListTemplate(format string) bool {
+	return strings.Contains(format, "{{")
+}
+
+// parseListTemplate parses a --format template. \t and \n typed as escapes
+// become a tab and a newline, since shells pass them through unchanged in
+// single quotes. The template is run once against an empty worktree, so an
+// unknown field is reported before anything is listed rather than halfway
+// through. Other errors from that run are left for the real worktrees:
+// {{slice .Branch 0 3}} fails on an empty branch but not on theirs.
+func parseListTemplate(text string) (*template.Template, error) {
+	text = strings.NewReplacer(`\t`, "\t", `\n`, "\n").Replace(text)
+	tmpl, err := template.New("format").Funcs(listTemplateFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	if err := tmpl.Execute(io.Discard, core.WorktreeInfo{}); err != nil && strings.Contains(err.Error(), "can't evaluate field") {
+		return nil, fmt.Errorf("invalid --format template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// listTemplateNeedsForge reports whether a --format template reads fields
+// that only the forge fills in: PR and CI state, and stale status (a merged
+// PR makes a branch stale).
+func listTemplateNeedsForge(text string) bool {
+	for _, field := range []string{".PR", ".CI", ".BranchStatus", ".StaleReason"} {
+		if strings.Contains(text, field) {
+			return true
+		}
+	}
+	return false
+}
+
+// printListTemplate writes tmpl's output for each worktree on its own line.
+// A worktree's output is written only once the template has run without
+// error, so a failure never leaves half a line behind.
+func printListTemplate(w io.Writer, worktrees []core.WorktreeInfo, tmpl *template.Template) error {
+	var buf bytes.Buffer
+	for _, wt := range worktrees {
+		buf.Reset()
+		if err := tmpl.Execute(&buf, wt); err != nil {
+			return fmt.Errorf("--format template failed for %s: %w", wt.Name, err)
+		}
+		fmt.Fprintln(w, strings.TrimSuffix(buf.String(), "\n"))
+	}
+	return nil
+}
+
+// shortPath replaces the home directory at the start of path with ~.
+func shortPath(path string) string {
+	home, err := config.HomeDir()
+	if err != nil || home == "/" {
+		return path
+	}
+	if path == home || strings.HasPrefix(path, home+string(filepath.Separator)) {
+		return "~" + strings.TrimPrefix(path, home)
+	}
+	return path
+}
@@ -81,8 +81,12 @@ func lastFailedHookLogPath(s string) string {
 	const marker = "full output → "
 	lines := strings.Split(s, "\n")
 	for i := len(lines) - 1; i >= 0; i-- {
-		if idx := strings.Index(lines[i], marker); idx != -1 {
-			return strings.TrimSpace(lines[i][idx+len(marker):])
+		line := lines[i]
+		if _, msg, ok := logging.ParseJSONLine(line); ok {
+			line = msg
+		}
+		if idx := strings.Index(line, marker); idx != -1 {
+			return strings.TrimSpace(line[idx+len(marker):])
 		}
 	}
 	return ""
@@ -90,11 +94,19 @@ func lastFailedHookLogPath(s string) string {
 
 // lastErrorBlock returns the last "[ERROR]" line plus any non-timestamped
 // continuation lines (captured stdout/stderr) that follow it, or a friendly
-// message if there are none. Timestamped lines start with "[20".
+// message if there are none. Timestamped lines start with "[20". In a
+// GREN_LOG_FORMAT=json log the block is the message of the last error line,
+// whose output is already part of it.
 func lastErrorBlock(s string) string {
 	lines := strings.Split(strings.TrimRight(s, "\n"), "\n")
 	idx := -1
 	for i := len(lines) - 1; i >= 0; i-- {
+		if level, msg, ok := logging.ParseJSONLine(lines[i]); ok {
+			if level == "error" {
+				return msg
+			}
+			continue
+		}
 		if strings.Contains(lines[i], "[ERROR]") {
 			idx = i
 			break
@@ -104,7 +116,7 @@ func lastErrorBlock(s string) string {
 		return "no [ERROR] entries in log"
 	}
 	end := idx + 1
-	for end < len(lines) && !strings.HasPrefix(lines[end], "[20") {
+	for end < len(lines) && !strings.HasPrefix(lines[end], "[20") && !strings.HasPrefix(lines[end], "{") {
 		end++
 	}
 	return strings.Join(lines[idx:end], "\n")
@@ -46,6 +46,21 @@ func TestLastFailedHookLogPath(t *testing.T) {
 	}
 }
 
+func TestLogsJSONFormat(t *testing.T) {
+	logText := strings.Join([]string{
+		`{"time":"2026-07-07T10:00:00.000Z","level":"info","component":"cli","msg":"started"}`,
+		`{"time":"2026-07-07T10:00:01.000Z","level":"error","component":"core","msg":"post-create hook failed: exit status 1\nstdout: boom"}`,
+		`{"time":"2026-07-07T10:00:01.000Z","level":"error","component":"core","msg":"post-create hook full output → /tmp/logs/hooks/post-create-x-123.log"}`,
+		`{"time":"2026-07-07T10:00:02.000Z","level":"info","component":"cli","msg":"done"}`,
+	}, "\n")
+	if got := lastErrorBlock(logText); got != "post-create hook full output → /tmp/logs/hooks/post-create-x-123.log" {
+		t.Errorf("lastErrorBlock = %q, want the last error's message", got)
+	}
+	if got := lastFailedHookLogPath(logText); got != "/tmp/logs/hooks/post-create-x-123.log" {
+		t.Errorf("lastFailedHookLogPath = %q, want the pointer path", got)
+	}
+}
+
 func TestLastErrorBlockNoErrors(t *testing.T) {
 	if got := lastErrorBlock("[2026-07-07 10:00:00.000] [INFO] fine"); !strings.Contains(got, "no [ERROR]") {
 		t.Errorf("lastErrorBlock = %q, want no-error message", got)
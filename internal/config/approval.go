@@ -6,7 +6,6 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 )
 
@@ -34,22 +33,12 @@ func NewApprovalManager() *ApprovalManager {
 	return am
 }
 
-// getApprovalConfigPath returns the path to the approval config file.
+// getApprovalConfigPath returns the path to the approval config file, or ""
+// when there is no user config directory.
 func getApprovalConfigPath() string {
-	var configDir string
-	switch runtime.GOOS {
-	case "darwin":
-		home, _ := os.UserHomeDir()
-		configDir = filepath.Join(home, "Library", "Application Support", "gren")
-	case "windows":
-		configDir = filepath.Join(os.Getenv("APPDATA"), "gren")
-	default: // linux and others
-		if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
-			configDir = filepath.Join(xdgConfig, "gren")
-		} else {
-			home, _ := os.UserHomeDir()
-			configDir = filepath.Join(home, ".config", "gren")
-		}
+	configDir := userConfigDir()
+	if configDir == "" {
+		return ""
 	}
 	return filepath.Join(configDir, "approved-commands.json")
 }
@@ -73,6 +62,9 @@ func (am *ApprovalManager) load() {
 
 // save writes the approval data to disk.
 func (am *ApprovalManager) save() error {
+	if am.configPath == "" {
+		return ErrNoUserConfigDir
+	}
 	// Ensure directory exists
 	dir := filepath.Dir(am.configPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -89,7 +81,7 @@ func (am *ApprovalManager) save() error {
 		return fmt.Errorf("failed to marshal approval data: %w", err)
 	}
 
-	return os.WriteFile(am.configPath, jsonData, 0644)
+	return WriteFileAtomic(am.configPath, jsonData, privateFileMode)
 }
 
 // IsApproved checks if a command is approved for a project.
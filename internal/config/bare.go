@@ -0,0 +1,305 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// BareDir is the directory holding the git data in the bare-clone layout:
+// <project>/.bare, with a <project>/.git file pointing at it and every
+// worktree checked out next to them in <project>.
+const BareDir = ".bare"
+
+// BareLayoutPlan describes how to set up the bare-clone layout, either by
+// cloning into a new project directory or by converting the repository in
+// place. Steps lists what Apply will do, for a dry run or a confirmation.
+type BareLayoutPlan struct {
+	Root     string   // Project directory: holds .bare, the .git file and the worktrees
+	URL      string   // Clone source; empty when converting an existing repository
+	Branch   string   // Branch checked out in the first worktree
+	Worktree string   // Path of the first worktree
+	Steps    []string // What Apply does, in order
+}
+
+// PlanBareClone plans cloning url into dir in the bare-clone layout. dir
+// defaults to the repository name from url and must not exist yet, or be
+// empty. The default branch is read from the remote so the plan can name the
+// first worktree; when the remote does not say, Apply uses the clone's HEAD.
+func PlanBareClone(url, dir string) (*BareLayoutPlan, error) {
+	if strings.TrimSpace(url) == "" {
+		return nil, fmt.Errorf("a repository URL is required")
+	}
+	if dir == "" {
+		dir = repoNameFromURL(url)
+		if dir == "" {
+			return nil, fmt.Errorf("cannot derive a directory name from %q; pass one", url)
+		}
+	}
+	root, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve %s: %w", dir, err)
+	}
+	if entries, err := os.ReadDir(root); err == nil && len(entries) > 0 {
+		return nil, fmt.Errorf("%s already exists and is not empty", root)
+	}
+
+	plan := &BareLayoutPlan{Root: root, URL: url, Branch: remoteDefaultBranch(url)}
+	worktree := "<default branch>"
+	if plan.Branch != "" {
+		plan.Worktree = filepath.Join(root, worktreeDirName(plan.Branch))
+		worktree = plan.Worktree
+	}
+	plan.Steps = []string{
+		fmt.Sprintf("clone %s as a bare repository into %s", url, filepath.Join(root, BareDir)),
+		fmt.Sprintf("write %s pointing at %s", filepath.Join(root, ".git"), BareDir),
+		"configure origin to fetch every branch, then fetch",
+		fmt.Sprintf("check out the default branch in %s", worktree),
+		fmt.Sprintf("new worktrees go in %s", root),
+	}
+	return plan, nil
+}
+
+// PlanBareConversion plans converting the repository gren runs in to the
+// bare-clone layout, in place: .git becomes .bare and the checked-out files
+// move, with any ignored files, into a worktree for the current branch. It
+// refuses a repository that is already bare, has linked worktrees, has
+// uncommitted or untracked changes, or is not on a branch, since any of
+// those would be lost or left behind by the move.
+func PlanBareConversion() (*BareLayoutPlan, error) {
+	root := MainWorktreeRoot()
+	if root == "" {
+		commonDir := gitPath("--path-format=absolute", "--git-common-dir")
+		if commonDir == "" {
+			return nil, fmt.Errorf("not a git repository")
+		}
+		if out, err := exec.Command("git", "--git-dir", commonDir, "rev-parse", "--is-bare-repository").Output(); err == nil && strings.TrimSpace(string(out)) == "true" {
+			return nil, fmt.Errorf("this repository is already bare")
+		}
+		return nil, fmt.Errorf("cannot find the main worktree of this repository")
+	}
+	if toplevel := gitPath("--show-toplevel"); toplevel != root {
+		return nil, fmt.Errorf("run gren init --bare from the main worktree (%s)", root)
+	}
+	if !dirExists(filepath.Join(root, ".git")) {
+		return nil, fmt.Errorf("%s keeps its git data elsewhere (a submodule or a GIT_DIR setup); only a repository with a .git directory can be converted", root)
+	}
+
+	out, err := exec.Command("git", "-C", root, "worktree", "list", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if n := strings.Count(string(out), "worktree "); n > 1 {
+		return nil, fmt.Errorf("the repository has %d linked worktree(s); delete or move them before converting", n-1)
+	}
+
+	out, err = exec.Command("git", "-C", root, "status", "--porcelain").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if len(strings.TrimSpace(string(out))) > 0 {
+		return nil, fmt.Errorf("the repository has uncommitted or untracked changes; commit or stash them before converting")
+	}
+
+	out, err = exec.Command("git", "-C", root, "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return nil, fmt.Errorf("HEAD is detached; check out a branch before converting")
+	}
+	branch := strings.TrimSpace(string(out))
+
+	plan := &BareLayoutPlan{
+		Root:     root,
+		Branch:   branch,
+		Worktree: filepath.Join(root, worktreeDirName(branch)),
+	}
+	if fileExists(filepath.Join(root, BareDir)) {
+		return nil, fmt.Errorf("%s already exists", filepath.Join(root, BareDir))
+	}
+	if fileExists(plan.Worktree) {
+		return nil, fmt.Errorf("%s already exists, so the %s worktree cannot go there", plan.Worktree, branch)
+	}
+
+	plan.Steps = []string{
+		fmt.Sprintf("move %s to %s and mark it bare", filepath.Join(root, ".git"), filepath.Join(root, BareDir)),
+		fmt.Sprintf("write %s pointing at %s", filepath.Join(root, ".git"), BareDir),
+		fmt.Sprintf("add a worktree for %s at %s", branch, plan.Worktree),
+		fmt.Sprintf("move the files in %s, ignored ones included, into that worktree", root),
+		fmt.Sprintf("new worktrees go in %s", root),
+	}
+	return plan, nil
+}
+
+// Apply carries out the plan. A clone that fails part-way leaves the project
+// directory for inspection; a failed conversion reports the step it stopped
+// at, as the repository may need finishing by hand.
+func (p *BareLayoutPlan) Apply() error {
+	bareDir := filepath.Join(p.Root, BareDir)
+	if p.URL != "" {
+		if err := os.MkdirAll(p.Root, 0755); err != nil {
+			return fmt.Errorf("failed to create %s: %w", p.Root, err)
+		}
+		if out, err := exec.Command("git", "clone", "--bare", p.URL, bareDir).CombinedOutput(); err != nil {
+			return fmt.Errorf("git clone --bare failed: %s", strings.TrimSpace(string(out)))
+		}
+	} else {
+		if err := os.Rename(filepath.Join(p.Root, ".git"), bareDir); err != nil {
+			return fmt.Errorf("failed to move .git to %s: %w", BareDir, err)
+		}
+		if err := runGitIn(bareDir, "config", "core.bare", "true"); err != nil {
+			return err
+		}
+	}
+	if err := os.WriteFile(filepath.Join(p.Root, ".git"), []byte("gitdir: ./"+BareDir+"\n"), 0644); err != nil {
+		return fmt.Errorf("failed to write .git file: %w", err)
+	}
+
+	if p.URL != "" {
+		// A bare clone maps remote branches straight onto local ones and
+		// fetches nothing else; worktrees need origin/* refs to track.
+		if err := runGitIn(bareDir, "config", "remote.origin.fetch", "+refs/heads/*:refs/remotes/origin/*"); err != nil {
+			return err
+		}
+		if err := runGitIn(bareDir, "fetch", "origin"); err != nil {
+			return err
+		}
+		if p.Branch == "" {
+			out, err := exec.Command("git", "-C", bareDir, "symbolic-ref", "--short", "HEAD").Output()
+			if err != nil {
+				return fmt.Errorf("failed to read the default branch: %w", err)
+			}
+			p.Branch = strings.TrimSpace(string(out))
+			p.Worktree = filepath.Join(p.Root, worktreeDirName(p.Branch))
+		}
+		if err := runGitIn(bareDir, "worktree", "add", p.Worktree, p.Branch); err != nil {
+			return err
+		}
+		// Best effort: without an upstream, ahead/behind counts are missing
+		_ = runGitIn(p.Worktree, "branch", "--set-upstream-to=origin/"+p.Branch, p.Branch)
+	} else {
+		if err := runGitIn(bareDir, "worktree", "add", "--no-checkout", p.Worktree, p.Branch); err != nil {
+			return err
+		}
+		if err := moveCheckout(p.Root, p.Worktree); err != nil {
+			return fmt.Errorf("failed to move files into %s: %w", p.Worktree, err)
+		}
+		// The files already match HEAD; fill the new worktree's index from it
+		if err := runGitIn(p.Worktree, "reset", "--quiet"); err != nil {
+			return err
+		}
+	}
+
+	return setBareWorktreeDir(filepath.Join(p.Worktree, ConfigDir))
+}
+
+// moveCheckout moves every entry of root except the git data and the new
+// worktree itself into worktree.
+func moveCheckout(root, worktree string) error {
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if name == BareDir || name == ".git" || filepath.Join(root, name) == worktree {
+			continue
+		}
+		if err := os.Rename(filepath.Join(root, name), filepath.Join(worktree, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setBareWorktreeDir points worktree_dir at the project directory when the
+// project config in configDir sets it elsewhere. The layout is a per-clone
+// choice, so the override goes in the local config rather than the shared
+// file; without a project config, the bare layout's default already fits.
+func setBareWorktreeDir(configDir string) error {
+	manager := &Manager{configDir: configDir}
+	if !manager.Exists() {
+		return nil
+	}
+	shared, err := manager.loadShared()
+	if err != nil {
+		return err
+	}
+	// worktree_dir resolves against the bare git dir, so ".." is the project
+	const worktreeDir = ".."
+	if shared.WorktreeDir == worktreeDir {
+		return nil
+	}
+
+	values := map[string]any{}
+	localJSON := filepath.Join(configDir, ConfigFileLocalJSON)
+	if data, err := os.ReadFile(localJSON); err == nil {
+		if err := json.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", localJSON, err)
+		}
+		values["worktree_dir"] = worktreeDir
+		data, err := json.MarshalIndent(values, "", "  ")
+		if err != nil {
+			return err
+		}
+		return WriteFileAtomic(localJSON, append(data, '\n'), privateFileMode)
+	}
+
+	localTOML := filepath.Join(configDir, ConfigFileLocalTOML)
+	if data, err := os.ReadFile(localTOML); err == nil {
+		if err := toml.Unmarshal(data, &values); err != nil {
+			return fmt.Errorf("failed to parse %s: %w", localTOML, err)
+		}
+	}
+	values["worktree_dir"] = worktreeDir
+	data, err := toml.Marshal(values)
+	if err != nil {
+		return err
+	}
+	return WriteFileAtomic(localTOML, data, privateFileMode)
+}
+
+// remoteDefaultBranch asks url which branch its HEAD points at, or returns
+// "" when it cannot tell.
+func remoteDefaultBranch(url string) string {
+	out, err := exec.Command("git", "ls-remote", "--symref", url, "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	for _, line := range strings.Split(string(out), "\n") {
+		if rest, ok := strings.CutPrefix(line, "ref: refs/heads/"); ok {
+			if branch, _, ok := strings.Cut(rest, "\t"); ok {
+				return branch
+			}
+		}
+	}
+	return ""
+}
+
+// repoNameFromURL returns the last path element of a clone URL without its
+// .git suffix: "git@github.com:org/app.git" → "app".
+func repoNameFromURL(url string) string {
+	url = strings.TrimRight(url, "/")
+	if i := strings.LastIndexAny(url, "/:"); i >= 0 {
+		url = url[i+1:]
+	}
+	return strings.TrimSuffix(url, ".git")
+}
+
+// worktreeDirName is the directory name of a branch's worktree in the
+// project directory: "feature/auth" → "feature-auth".
+func worktreeDirName(branch string) string {
+	return strings.ReplaceAll(branch, "/", "-")
+}
+
+// runGitIn runs git in dir, folding its output into the error.
+func runGitIn(dir string, args ...string) error {
+	cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("git %s failed: %s", strings.Join(args, " "), strings.TrimSpace(string(out)))
+	}
+	return nil
+}
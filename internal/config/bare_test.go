@@ -0,0 +1,145 @@
+package config
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBareLayout(t *testing.T) {
+	git := func(dir string, args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-C", dir}, args...)...)
+		cmd.Env = append(os.Environ(), "GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t", "GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t")
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+		return strings.TrimSpace(string(out))
+	}
+	// newRepo returns a repository on main with one commit, an ignored .env
+	// and a shared config whose worktree_dir points at a sibling directory.
+	newRepo := func(t *testing.T) string {
+		dir := filepath.Join(t.TempDir(), "app")
+		os.MkdirAll(filepath.Join(dir, ConfigDir), 0755)
+		git(dir, "init", "-b", "main")
+		os.WriteFile(filepath.Join(dir, "README.md"), []byte("app\n"), 0644)
+		os.WriteFile(filepath.Join(dir, ".gitignore"), []byte(".env\n"+ConfigDir+"/config.local.*\n"), 0644)
+		os.WriteFile(filepath.Join(dir, ConfigDir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../app-worktrees\"\n"), 0644)
+		git(dir, "add", ".")
+		git(dir, "-c", "commit.gpgsign=false", "commit", "-m", "init")
+		os.WriteFile(filepath.Join(dir, ".env"), []byte("PORT=1\n"), 0644)
+		return dir
+	}
+	chdir := func(t *testing.T, dir string) {
+		original, _ := os.Getwd()
+		t.Cleanup(func() { os.Chdir(original) })
+		if err := os.Chdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkLayout := func(t *testing.T, plan *BareLayoutPlan) {
+		t.Helper()
+		if data, err := os.ReadFile(filepath.Join(plan.Root, ".git")); err != nil || string(data) != "gitdir: ./.bare\n" {
+			t.Errorf(".git file = %q, %v", data, err)
+		}
+		if got := git(filepath.Join(plan.Root, BareDir), "rev-parse", "--is-bare-repository"); got != "true" {
+			t.Errorf("--is-bare-repository = %s, want true", got)
+		}
+		if got := git(plan.Worktree, "branch", "--show-current"); got != "main" {
+			t.Errorf("worktree branch = %s, want main", got)
+		}
+		if got := git(plan.Worktree, "status", "--porcelain"); got != "" {
+			t.Errorf("worktree status = %q, want clean", got)
+		}
+		local, err := os.ReadFile(filepath.Join(plan.Worktree, ConfigDir, ConfigFileLocalTOML))
+		if err != nil || !strings.Contains(string(local), `worktree_dir = '..'`) {
+			t.Errorf("config.local.toml = %q, %v; want worktree_dir = '..'", local, err)
+		}
+	}
+
+	t.Run("convert in place", func(t *testing.T) {
+		repo := newRepo(t)
+		chdir(t, repo)
+
+		plan, err := PlanBareConversion()
+		if err != nil {
+			t.Fatalf("PlanBareConversion() error = %v", err)
+		}
+		if plan.Branch != "main" || plan.Worktree != filepath.Join(repo, "main") {
+			t.Errorf("plan = %+v, want main at %s", plan, filepath.Join(repo, "main"))
+		}
+		if err := plan.Apply(); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		checkLayout(t, plan)
+		if _, err := os.Stat(filepath.Join(plan.Worktree, ".env")); err != nil {
+			t.Errorf("ignored .env did not move into the worktree: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(repo, "README.md")); !os.IsNotExist(err) {
+			t.Errorf("README.md left in the project directory: %v", err)
+		}
+
+		if _, err := PlanBareConversion(); err == nil {
+			t.Error("PlanBareConversion() on a bare layout: want error")
+		}
+	})
+
+	t.Run("clone", func(t *testing.T) {
+		origin := newRepo(t)
+		dest := filepath.Join(t.TempDir(), "clone")
+
+		plan, err := PlanBareClone(origin, dest)
+		if err != nil {
+			t.Fatalf("PlanBareClone() error = %v", err)
+		}
+		if plan.Branch != "main" {
+			t.Errorf("plan.Branch = %q, want main", plan.Branch)
+		}
+		if err := plan.Apply(); err != nil {
+			t.Fatalf("Apply() error = %v", err)
+		}
+		checkLayout(t, plan)
+		if got := git(plan.Worktree, "rev-parse", "--abbrev-ref", "main@{upstream}"); got != "origin/main" {
+			t.Errorf("upstream = %s, want origin/main", got)
+		}
+
+		if _, err := PlanBareClone(origin, dest); err == nil {
+			t.Error("PlanBareClone() into a non-empty directory: want error")
+		}
+	})
+
+	t.Run("refuses uncommitted changes", func(t *testing.T) {
+		repo := newRepo(t)
+		chdir(t, repo)
+		os.WriteFile(filepath.Join(repo, "README.md"), []byte("changed\n"), 0644)
+		if _, err := PlanBareConversion(); err == nil || !strings.Contains(err.Error(), "uncommitted") {
+			t.Errorf("PlanBareConversion() error = %v, want uncommitted changes", err)
+		}
+	})
+
+	t.Run("refuses linked worktrees", func(t *testing.T) {
+		repo := newRepo(t)
+		chdir(t, repo)
+		git(repo, "worktree", "add", filepath.Join(filepath.Dir(repo), "feature"), "-b", "feature")
+		if _, err := PlanBareConversion(); err == nil || !strings.Contains(err.Error(), "linked worktree") {
+			t.Errorf("PlanBareConversion() error = %v, want linked worktrees", err)
+		}
+	})
+}
+
+func TestRepoNameFromURL(t *testing.T) {
+	for url, want := range map[string]string{
+		"git@github.com:org/app.git":  "app",
+		"https://github.com/org/app":  "app",
+		"https://github.com/org/app/": "app",
+		"/srv/git/app.git":            "app",
+		"git@host:app.git":            "app",
+	} {
+		if got := repoNameFromURL(url); got != want {
+			t.Errorf("repoNameFromURL(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
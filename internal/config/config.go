@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -17,6 +20,11 @@ const (
 	ConfigFileTOML = "config.toml"
 	// ConfigFileJSON is the legacy JSON configuration file name.
 	ConfigFileJSON = "config.json"
+	// ConfigFileLocalTOML holds personal overrides of the project config. It
+	// is meant to stay out of git, unlike config.toml.
+	ConfigFileLocalTOML = "config.local.toml"
+	// ConfigFileLocalJSON is the JSON form of ConfigFileLocalTOML.
+	ConfigFileLocalJSON = "config.local.json"
 	// ConfigFile is kept for backward compatibility, points to JSON.
 	ConfigFile = ConfigFileJSON
 	// DefaultVersion is the version stamped into configs created by `gren init`.
@@ -77,10 +85,64 @@ type ProjectNamedHooks struct {
 	PostStart  []NamedHook `toml:"post-start,omitempty"`
 }
 
-// CommitGenerator configures external LLM command for commit message generation.
+// CommitGenerator configures the LLM behind commit message generation and,
+// when Claude Code isn't installed, the AI setup script.
 type CommitGenerator struct {
-	Command string   `json:"command,omitempty" toml:"command,omitempty"`
-	Args    []string `json:"args,omitempty" toml:"args,omitempty"`
+	// Provider picks the backend: "command" runs Command with the prompt on
+	// stdin, "claude" runs the Claude Code CLI, and "openai" calls an
+	// OpenAI-compatible chat completions API. Empty means "command".
+	Provider string   `json:"provider,omitempty" toml:"provider,omitempty"`
+	Command  string   `json:"command,omitempty" toml:"command,omitempty"`
+	Args     []string `json:"args,omitempty" toml:"args,omitempty"`
+	// Model is required for "openai" and optional for "claude".
+	Model string `json:"model,omitempty" toml:"model,omitempty"`
+	// BaseURL is the "openai" API root; empty means https://api.openai.com/v1.
+	BaseURL string `json:"base_url,omitempty" toml:"base_url,omitempty"`
+	// APIKeyEnv names the environment variable holding the "openai" API key;
+	// empty means OPENAI_API_KEY. Local servers may need no key at all.
+	APIKeyEnv string `json:"api_key_env,omitempty" toml:"api_key_env,omitempty"`
+}
+
+// LLM providers for CommitGenerator.Provider
+const (
+	LLMProviderCommand = "command"
+	LLMProviderClaude  = "claude"
+	LLMProviderOpenAI  = "openai"
+)
+
+// Configured reports whether an LLM is set up.
+func (g CommitGenerator) Configured() bool {
+	return g.Command != "" || (g.Provider != "" && g.Provider != LLMProviderCommand)
+}
+
+// Describe names the configured LLM for status output, e.g. "llm" or
+// "openai (gpt-4o-mini)".
+func (g CommitGenerator) Describe() string {
+	if g.Provider == "" || g.Provider == LLMProviderCommand {
+		return g.Command
+	}
+	if g.Model != "" {
+		return fmt.Sprintf("%s (%s)", g.Provider, g.Model)
+	}
+	return g.Provider
+}
+
+// validate checks that the provider is known and has what it needs.
+func (g CommitGenerator) validate() error {
+	switch g.Provider {
+	case "", LLMProviderCommand:
+		if g.Provider != "" && g.Command == "" {
+			return fmt.Errorf("commit generation provider %q needs a command", g.Provider)
+		}
+	case LLMProviderClaude:
+	case LLMProviderOpenAI:
+		if g.Model == "" {
+			return fmt.Errorf("commit generation provider %q needs a model", g.Provider)
+		}
+	default:
+		return fmt.Errorf("unknown commit generation provider %q (use command, claude or openai)", g.Provider)
+	}
+	return nil
 }
 
 // Get returns the hook command for the given hook type.
@@ -145,6 +207,77 @@ type Config struct {
 	Hooks           Hooks             `json:"hooks,omitempty" toml:"hooks,omitempty"`
 	NamedHooks      ProjectNamedHooks `json:"-" toml:"named-hooks,omitempty"`
 	CommitGenerator CommitGenerator   `json:"commit_generator,omitempty" toml:"commit-generation,omitempty"`
+	// TerminalCommand opens a terminal in a worktree. It is a shell command
+	// with {{path}} replaced by the worktree path, or "tmux" / "tmux-split" to
+	// open a tmux window or pane. Empty auto-detects the terminal.
+	TerminalCommand string `json:"terminal_command,omitempty" toml:"terminal_command,omitempty"`
+	// Editor opens config and hook files. It takes precedence over $EDITOR.
+	Editor string `json:"editor,omitempty" toml:"editor,omitempty"`
+	// DefaultBranch overrides main/master/origin-HEAD detection wherever gren
+	// needs the repository's default branch: the base for new worktrees, stale
+	// detection, and the merge target. It must exist locally or on origin.
+	DefaultBranch string `json:"default_branch,omitempty" toml:"default_branch,omitempty"`
+	// EnvTemplate is a template, relative to the main worktree (typically
+	// .gren/env.template), rendered into each new worktree's EnvFile. Empty
+	// disables rendering.
+	EnvTemplate string `json:"env_template,omitempty" toml:"env_template,omitempty"`
+	// EnvFile is the file EnvTemplate renders to, relative to the worktree.
+	// Empty means .env.
+	EnvFile string `json:"env_file,omitempty" toml:"env_file,omitempty"`
+	// CopyFiles lists globs, relative to the main worktree, of files and
+	// directories copied into each new worktree rather than symlinked, for
+	// state that must not be shared (SQLite databases, caches). Files that
+	// already exist in the worktree are left alone.
+	CopyFiles []string `json:"copy_files,omitempty" toml:"copy_files,omitempty"`
+	// HooksRequired makes a failing post-create hook fail `gren create`
+	// instead of leaving a warning; the worktree is kept for inspection.
+	HooksRequired bool `json:"hooks_required,omitempty" toml:"hooks_required,omitempty"`
+	// PruneEmptyWorktreeDir removes the directory holding worktrees once the
+	// last worktree in it is deleted, if gren created that directory.
+	PruneEmptyWorktreeDir bool `json:"prune_empty_worktree_dir,omitempty" toml:"prune_empty_worktree_dir,omitempty"`
+	// Sanitize sets which characters in a worktree or branch name become
+	// "-" in its directory name and {{ branch | sanitize }}.
+	Sanitize Sanitize `json:"sanitize,omitempty" toml:"sanitize,omitempty"`
+	// Presets are named bundles of create settings, applied with
+	// `gren create --preset <name>`.
+	Presets map[string]Preset `json:"presets,omitempty" toml:"presets,omitempty"`
+	// GitHubConcurrency caps how many per-branch gh calls (CI checks) run at
+	// once. Zero means the default of 8.
+	GitHubConcurrency int `json:"github_concurrency,omitempty" toml:"github_concurrency,omitempty"`
+	// GitHub turns GitHub integration (PR and CI status through gh) on or
+	// off. Unset means on; false skips every gh call, including the check
+	// for whether gh is installed and logged in.
+	GitHub *bool `json:"github,omitempty" toml:"github,omitempty"`
+	// MarkerTTL is how long a working or waiting Claude marker holds, as a
+	// Go duration ("2h", "45m"), before it reads as idle. Empty means the
+	// default of 2h; "0" keeps markers until they are cleared.
+	MarkerTTL string `json:"marker_ttl,omitempty" toml:"marker_ttl,omitempty"`
+	// HookTimeout bounds how long a non-interactive hook may run, as a Go
+	// duration ("10m", "90s"), before it is killed and fails. Empty means
+	// the default of 10m; "0" lets hooks run as long as they like.
+	HookTimeout string `json:"hook_timeout,omitempty" toml:"hook_timeout,omitempty"`
+	// NetworkAttempts is how many times `git fetch` and gh calls are tried
+	// when they fail for a transient network reason. Zero means the default
+	// of 3; 1 turns retrying off.
+	NetworkAttempts int `json:"network_attempts,omitempty" toml:"network_attempts,omitempty"`
+	// NetworkBackoff is the wait before the first retry, as a Go duration
+	// ("500ms", "2s"), doubling after each further attempt. Empty means the
+	// default of 500ms.
+	NetworkBackoff string `json:"network_backoff,omitempty" toml:"network_backoff,omitempty"`
+
+	// Warnings lists problems found while loading that did not stop it,
+	// such as unknown (likely misspelled) keys. It is never saved.
+	Warnings []string `json:"-" toml:"-"`
+
+	// layers holds what each file Load read contributed, lowest precedence
+	// first, for Settings to tell where a setting came from.
+	layers []sourceLayer
+}
+
+// GitHubEnabled reports whether GitHub integration is on, which it is
+// unless the config sets `github = false`.
+func (c *Config) GitHubEnabled() bool {
+	return c.GitHub == nil || *c.GitHub
 }
 
 // GetAllHooks returns all hooks (simple + named) for a given hook type.
@@ -168,14 +301,62 @@ func (c *Config) GetAllHooks(hookType HookType) []NamedHook {
 
 // Manager handles configuration operations.
 type Manager struct {
-	configDir string
+	configDir  string
+	userConfig *UserConfigManager // Supplies [defaults] the project leaves unset
+
+	mu          sync.Mutex
+	dir         string // Dir as resolved from dirCwd, the working directory
+	dirCwd      string
+	user        *UserConfig // userConfig as last read, see loadUserConfig
+	userModTime time.Time
 }
 
 // NewManager creates a new configuration manager.
 func NewManager() *Manager {
 	return &Manager{
-		configDir: ConfigDir,
+		configDir:  ConfigDir,
+		userConfig: NewUserConfigManager(),
+	}
+}
+
+// Dir returns the project config directory. The default, relative .gren is
+// resolved against the repository rather than the working directory, so the
+// config is found from a subdirectory or a linked worktree: the current
+// worktree's .gren when it has one (a committed config, possibly changed on
+// its branch), else the main worktree's, where a gitignored config lives.
+// Outside a git repository the relative path is used as is. Finding the
+// repository takes git calls, so the result is kept and only resolved again
+// once the working directory changes.
+func (m *Manager) Dir() string {
+	if filepath.IsAbs(m.configDir) {
+		return m.configDir
+	}
+	cwd, _ := os.Getwd()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.dir == "" || m.dirCwd != cwd {
+		m.dir, m.dirCwd = m.resolveDir(), cwd
+	}
+	return m.dir
+}
+
+// resolveDir looks up Dir from the current working directory.
+func (m *Manager) resolveDir() string {
+	toplevel := gitPath("--show-toplevel")
+	mainRoot := MainWorktreeRoot()
+	for _, root := range []string{toplevel, mainRoot} {
+		if root == "" {
+			continue
+		}
+		dir := filepath.Join(root, m.configDir)
+		if info, err := os.Stat(dir); err == nil && info.IsDir() {
+			return dir
+		}
 	}
+	if toplevel != "" {
+		return filepath.Join(toplevel, m.configDir)
+	}
+	return m.configDir
 }
 
 // NewDefaultConfig returns a default configuration for the given project.
@@ -220,23 +401,105 @@ func DefaultRuntimeConfig() *Config {
 
 // Load reads the configuration from the config file.
 // Tries TOML first (config.toml), then falls back to JSON (config.json).
+// Personal overrides from config.local.toml (or config.local.json) are then
+// applied on top, and the user config's [defaults] fill in what is still
+// unset, so the precedence is local > project > user defaults > built-in
+// defaults.
 func (m *Manager) Load() (*Config, error) {
+	config, found, err := m.readShared()
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		project := *config
+		config.addLayer(&project, SourceProject)
+	}
+
+	localPath, err := m.applyLocalOverrides(config)
+	if err != nil {
+		return nil, err
+	}
+	// Runtime defaults are not validated (they leave worktree_dir empty on
+	// purpose), so only a real project config is checked again.
+	if localPath != "" && found {
+		if err := m.validateConfig(config); err != nil {
+			return nil, fmt.Errorf("invalid configuration after applying %s: %w", localPath, err)
+		}
+	}
+	m.applyUserDefaults(config)
+	return config, nil
+}
+
+// applyUserDefaults fills in what the project and local files leave unset
+// from the user config, as MergeConfigs does. worktree_dir is only ever
+// unset in an uninitialized repository, since a project config must set it.
+// An unreadable user config is reported in Warnings rather than failing
+// every command.
+func (m *Manager) applyUserDefaults(config *Config) {
+	if m.userConfig == nil {
+		return
+	}
+	user, err := m.loadUserConfig()
+	if err != nil {
+		config.Warnings = append(config.Warnings, fmt.Sprintf("ignoring user config: %v", err))
+		return
+	}
+	MergeConfigs(user, config)
+	// Below the project and local layers, the user's values only show as
+	// the source of settings neither of them sets
+	config.layers = append([]sourceLayer{{MergeConfigs(user, nil), SourceUser}}, config.layers...)
+}
+
+// loadUserConfig returns the user config, parsed once and read again only
+// when the file changes, so a Load costs a stat of it rather than a parse.
+func (m *Manager) loadUserConfig() (*UserConfig, error) {
+	var modTime time.Time
+	if info, err := os.Stat(m.userConfig.ConfigPath()); err == nil {
+		modTime = info.ModTime()
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.user != nil && m.userModTime.Equal(modTime) {
+		return m.user, nil
+	}
+	user, err := m.userConfig.Load()
+	if err != nil {
+		return nil, err
+	}
+	m.user, m.userModTime = user, modTime
+	return user, nil
+}
+
+// loadShared reads the project config without local overrides. Anything
+// that writes the config back must start from this, or it would copy
+// personal settings into the shared file.
+func (m *Manager) loadShared() (*Config, error) {
+	config, _, err := m.readShared()
+	return config, err
+}
+
+// readShared is loadShared, also reporting whether a project config file
+// was found rather than the runtime defaults returned.
+func (m *Manager) readShared() (*Config, bool, error) {
+	configDir := m.Dir()
 	var config Config
 	var data []byte
 	var err error
 	var usedPath string
 
 	// Try TOML first (preferred format)
-	tomlPath := filepath.Join(m.configDir, ConfigFileTOML)
+	tomlPath := filepath.Join(configDir, ConfigFileTOML)
 	data, err = os.ReadFile(tomlPath)
 	if err == nil {
 		usedPath = tomlPath
 		if err := toml.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse %s: %w", tomlPath, err)
+			return nil, false, fmt.Errorf("failed to parse %s: %w", tomlPath, err)
 		}
+		config.Warnings = keyWarnings(data, "toml", tomlPath)
 	} else if os.IsNotExist(err) {
 		// Fall back to JSON
-		jsonPath := filepath.Join(m.configDir, ConfigFileJSON)
+		jsonPath := filepath.Join(configDir, ConfigFileJSON)
 		data, err = os.ReadFile(jsonPath)
 		if err != nil {
 			if os.IsNotExist(err) {
@@ -244,16 +507,17 @@ func (m *Manager) Load() (*Config, error) {
 				// instead of erroring, so gren works on any git repo (like
 				// `git worktree`). `gren init` remains available to persist
 				// hooks and custom settings.
-				return DefaultRuntimeConfig(), nil
+				return DefaultRuntimeConfig(), false, nil
 			}
-			return nil, fmt.Errorf("failed to read %s: %w", jsonPath, err)
+			return nil, false, fmt.Errorf("failed to read %s: %w", jsonPath, err)
 		}
 		usedPath = jsonPath
 		if err := json.Unmarshal(data, &config); err != nil {
-			return nil, fmt.Errorf("failed to parse %s: %w", jsonPath, err)
+			return nil, false, fmt.Errorf("failed to parse %s: %w", jsonPath, err)
 		}
+		config.Warnings = keyWarnings(data, "json", jsonPath)
 	} else {
-		return nil, fmt.Errorf("failed to read %s: %w", tomlPath, err)
+		return nil, false, fmt.Errorf("failed to read %s: %w", tomlPath, err)
 	}
 
 	// Note: MainWorktree from old configs is ignored - now detected dynamically
@@ -263,10 +527,52 @@ func (m *Manager) Load() (*Config, error) {
 	}
 
 	if err := m.validateConfig(&config); err != nil {
-		return nil, fmt.Errorf("invalid configuration in %s: %w", usedPath, err)
+		// A misspelled key is the likely cause, e.g. an empty worktree_dir
+		if len(config.Warnings) > 0 {
+			return nil, false, fmt.Errorf("invalid configuration in %s: %w; %s", usedPath, err, strings.Join(config.Warnings, "; "))
+		}
+		return nil, false, fmt.Errorf("invalid configuration in %s: %w", usedPath, err)
 	}
 
-	return &config, nil
+	return &config, true, nil
+}
+
+// applyLocalOverrides decodes the local override file, if any, onto config.
+// Only keys present in the file change; nested tables such as [hooks] are
+// merged key by key, while lists replace the project's. It returns the path
+// applied, or "" when there is no local file.
+func (m *Manager) applyLocalOverrides(config *Config) (string, error) {
+	configDir := m.Dir()
+
+	tomlPath := filepath.Join(configDir, ConfigFileLocalTOML)
+	if data, err := os.ReadFile(tomlPath); err == nil {
+		if err := toml.Unmarshal(data, config); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", tomlPath, err)
+		}
+		var layer Config
+		toml.Unmarshal(data, &layer)
+		config.addLayer(&layer, SourceLocal)
+		config.Warnings = append(config.Warnings, keyWarnings(data, "toml", tomlPath)...)
+		return tomlPath, nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", tomlPath, err)
+	}
+
+	jsonPath := filepath.Join(configDir, ConfigFileLocalJSON)
+	if data, err := os.ReadFile(jsonPath); err == nil {
+		if err := json.Unmarshal(data, config); err != nil {
+			return "", fmt.Errorf("failed to parse %s: %w", jsonPath, err)
+		}
+		var layer Config
+		json.Unmarshal(data, &layer)
+		config.addLayer(&layer, SourceLocal)
+		config.Warnings = append(config.Warnings, keyWarnings(data, "json", jsonPath)...)
+		return jsonPath, nil
+	} else if !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", jsonPath, err)
+	}
+
+	return "", nil
 }
 
 // Save writes the configuration to the config file in TOML format.
@@ -280,13 +586,14 @@ func (m *Manager) Save(config *Config) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	configDir := m.Dir()
 	// Ensure config directory exists
-	if err := os.MkdirAll(m.configDir, 0755); err != nil {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
 	// Save as TOML (preferred format)
-	configPath := filepath.Join(m.configDir, ConfigFileTOML)
+	configPath := filepath.Join(configDir, ConfigFileTOML)
 
 	data, err := toml.Marshal(config)
 	if err != nil {
@@ -312,12 +619,12 @@ func (m *Manager) Save(config *Config) error {
 `
 	data = append([]byte(header), data...)
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := WriteFileAtomic(configPath, data, sharedFileMode); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
 	// Remove legacy JSON config if it exists
-	jsonPath := filepath.Join(m.configDir, ConfigFileJSON)
+	jsonPath := filepath.Join(configDir, ConfigFileJSON)
 	if _, err := os.Stat(jsonPath); err == nil {
 		os.Remove(jsonPath) // Ignore errors - not critical
 	}
@@ -335,19 +642,20 @@ func (m *Manager) SaveJSON(config *Config) error {
 		return fmt.Errorf("invalid configuration: %w", err)
 	}
 
+	configDir := m.Dir()
 	// Ensure config directory exists
-	if err := os.MkdirAll(m.configDir, 0755); err != nil {
+	if err := os.MkdirAll(configDir, 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configPath := filepath.Join(m.configDir, ConfigFileJSON)
+	configPath := filepath.Join(configDir, ConfigFileJSON)
 
 	data, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
 
-	if err := os.WriteFile(configPath, data, 0644); err != nil {
+	if err := WriteFileAtomic(configPath, data, sharedFileMode); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 
@@ -356,27 +664,30 @@ func (m *Manager) SaveJSON(config *Config) error {
 
 // Exists checks if any configuration file exists (TOML or JSON).
 func (m *Manager) Exists() bool {
+	configDir := m.Dir()
 	// Check TOML first (preferred)
-	tomlPath := filepath.Join(m.configDir, ConfigFileTOML)
+	tomlPath := filepath.Join(configDir, ConfigFileTOML)
 	if _, err := os.Stat(tomlPath); err == nil {
 		return true
 	}
 	// Fall back to JSON
-	jsonPath := filepath.Join(m.configDir, ConfigFileJSON)
+	jsonPath := filepath.Join(configDir, ConfigFileJSON)
 	_, err := os.Stat(jsonPath)
 	return err == nil
 }
 
 // ExistsTOML checks if TOML configuration exists.
 func (m *Manager) ExistsTOML() bool {
-	tomlPath := filepath.Join(m.configDir, ConfigFileTOML)
+	configDir := m.Dir()
+	tomlPath := filepath.Join(configDir, ConfigFileTOML)
 	_, err := os.Stat(tomlPath)
 	return err == nil
 }
 
 // ExistsJSON checks if legacy JSON configuration exists.
 func (m *Manager) ExistsJSON() bool {
-	jsonPath := filepath.Join(m.configDir, ConfigFileJSON)
+	configDir := m.Dir()
+	jsonPath := filepath.Join(configDir, ConfigFileJSON)
 	_, err := os.Stat(jsonPath)
 	return err == nil
 }
@@ -393,6 +704,48 @@ func (m *Manager) validateConfig(config *Config) error {
 		return fmt.Errorf("version cannot be empty")
 	}
 
+	if config.GitHubConcurrency < 0 {
+		return fmt.Errorf("github_concurrency must be 0 (default) or more, got %d", config.GitHubConcurrency)
+	}
+
+	if err := config.CommitGenerator.validate(); err != nil {
+		return err
+	}
+
+	if config.MarkerTTL != "" {
+		if ttl, err := time.ParseDuration(config.MarkerTTL); err != nil || ttl < 0 {
+			return fmt.Errorf("marker_ttl must be a duration such as \"2h\" or \"0\", got %q", config.MarkerTTL)
+		}
+	}
+
+	if config.HookTimeout != "" {
+		if timeout, err := time.ParseDuration(config.HookTimeout); err != nil || timeout < 0 {
+			return fmt.Errorf("hook_timeout must be a duration such as \"10m\" or \"0\", got %q", config.HookTimeout)
+		}
+	}
+
+	if config.NetworkAttempts < 0 {
+		return fmt.Errorf("network_attempts must be 0 (default) or more, got %d", config.NetworkAttempts)
+	}
+
+	if config.NetworkBackoff != "" {
+		if backoff, err := time.ParseDuration(config.NetworkBackoff); err != nil || backoff < 0 {
+			return fmt.Errorf("network_backoff must be a duration such as \"500ms\" or \"2s\", got %q", config.NetworkBackoff)
+		}
+	}
+
+	for _, pattern := range config.CopyFiles {
+		if err := validateCopyPattern(pattern); err != nil {
+			return err
+		}
+	}
+
+	for name, preset := range config.Presets {
+		if err := preset.validate(name); err != nil {
+			return err
+		}
+	}
+
 	// Validate package manager if specified
 	if config.PackageManager != "" && config.PackageManager != "auto" {
 		validManagers := []string{"npm", "yarn", "pnpm", "bun"}
@@ -409,5 +762,50 @@ func (m *Manager) validateConfig(config *Config) error {
 		}
 	}
 
+	if config.DefaultBranch != "" && !branchExists(config.DefaultBranch) {
+		return fmt.Errorf("default_branch %q does not exist locally or on origin", config.DefaultBranch)
+	}
+
+	// The rendered env file must land inside the worktree it belongs to.
+	if config.EnvFile != "" && (filepath.IsAbs(config.EnvFile) || !filepath.IsLocal(config.EnvFile)) {
+		return fmt.Errorf("invalid env_file: %s (must be a path inside the worktree)", config.EnvFile)
+	}
+
+	return nil
+}
+
+// branchExists reports whether branch exists as a local branch or on origin.
+func branchExists(branch string) bool {
+	for _, ref := range []string{"refs/heads/" + branch, "refs/remotes/origin/" + branch} {
+		if exec.Command("git", "show-ref", "--verify", "--quiet", ref).Run() == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// validateCopyPattern checks a copy_files entry: a valid glob that stays
+// inside the repository.
+func validateCopyPattern(pattern string) error {
+	return validateFilePattern("copy_files", pattern)
+}
+
+// validateFilePattern checks an entry of the file list key (copy_files,
+// symlink_files): a valid glob that stays inside the repository.
+func validateFilePattern(key, pattern string) error {
+	if pattern == "" {
+		return fmt.Errorf("%s entries cannot be empty", key)
+	}
+	if filepath.IsAbs(pattern) {
+		return fmt.Errorf("%s entry %q must be relative to the repository root", key, pattern)
+	}
+	for _, part := range strings.Split(filepath.ToSlash(pattern), "/") {
+		if part == ".." {
+			return fmt.Errorf("%s entry %q must not leave the repository", key, pattern)
+		}
+	}
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return fmt.Errorf("%s entry %q is not a valid glob: %w", key, pattern, err)
+	}
 	return nil
 }
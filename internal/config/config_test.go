@@ -2,9 +2,11 @@ package config
 
 import (
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestNewDefaultConfig(t *testing.T) {
@@ -249,6 +251,7 @@ func TestLoadWithoutConfigReturnsDefaults(t *testing.T) {
 	os.Chdir(tempDir)
 
 	manager := NewManager()
+	manager.userConfig = &UserConfigManager{} // no user defaults
 
 	cfg, err := manager.Load()
 	if err != nil {
@@ -314,6 +317,323 @@ func TestLoadErrorNamesConfigFile(t *testing.T) {
 	}
 }
 
+func TestLoadUserDefaults(t *testing.T) {
+	userDir := t.TempDir()
+	userPath := filepath.Join(userDir, "config.toml")
+	os.WriteFile(userPath, []byte("[defaults]\nworktree-dir = \"~/worktrees/{{ repo }}\"\n"), 0644)
+	user := &UserConfigManager{configPath: userPath}
+
+	t.Run("uninitialized repo uses the user default", func(t *testing.T) {
+		config, err := (&Manager{configDir: t.TempDir(), userConfig: user}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.WorktreeDir != "~/worktrees/{{ repo }}" {
+			t.Errorf("WorktreeDir = %q, want the user default", config.WorktreeDir)
+		}
+	})
+
+	t.Run("project config wins", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../project-worktrees\"\n"), 0644)
+		config, err := (&Manager{configDir: dir, userConfig: user}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.WorktreeDir != "../project-worktrees" {
+			t.Errorf("WorktreeDir = %q, want the project's", config.WorktreeDir)
+		}
+	})
+
+	t.Run("local override wins", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../project-worktrees\"\n"), 0644)
+		os.WriteFile(filepath.Join(dir, ConfigFileLocalTOML), []byte("worktree_dir = \"/fast-disk/worktrees\"\n"), 0644)
+		config, err := (&Manager{configDir: dir, userConfig: user}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.WorktreeDir != "/fast-disk/worktrees" {
+			t.Errorf("WorktreeDir = %q, want the local override", config.WorktreeDir)
+		}
+	})
+
+	t.Run("user hooks fill in unset hooks", func(t *testing.T) {
+		hooksPath := filepath.Join(t.TempDir(), "config.toml")
+		os.WriteFile(hooksPath, []byte("[hooks]\npost-create = \"direnv allow\"\npre-merge = \"make lint\"\n"), 0644)
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../w\"\n\n[hooks]\npost-create = \"npm install\"\n"), 0644)
+		config, err := (&Manager{configDir: dir, userConfig: &UserConfigManager{configPath: hooksPath}}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.Hooks.PostCreate != "npm install" || config.Hooks.PreMerge != "make lint" {
+			t.Errorf("post-create = %q, pre-merge = %q; want the project's and the user's", config.Hooks.PostCreate, config.Hooks.PreMerge)
+		}
+	})
+
+	t.Run("user config is reread when it changes", func(t *testing.T) {
+		changingPath := filepath.Join(t.TempDir(), "config.toml")
+		os.WriteFile(changingPath, []byte("[defaults]\neditor = \"vim\"\n"), 0644)
+		manager := &Manager{configDir: t.TempDir(), userConfig: &UserConfigManager{configPath: changingPath}}
+		if config, err := manager.Load(); err != nil || config.Editor != "vim" {
+			t.Fatalf("Load() = %v, %v; want editor vim", config, err)
+		}
+
+		os.WriteFile(changingPath, []byte("[defaults]\neditor = \"hx\"\n"), 0644)
+		later := time.Now().Add(time.Minute)
+		os.Chtimes(changingPath, later, later)
+		config, err := manager.Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.Editor != "hx" {
+			t.Errorf("Editor after the change = %q, want hx", config.Editor)
+		}
+	})
+
+	t.Run("broken user config is a warning", func(t *testing.T) {
+		brokenPath := filepath.Join(t.TempDir(), "config.toml")
+		os.WriteFile(brokenPath, []byte("[defaults\n"), 0644)
+		config, err := (&Manager{configDir: t.TempDir(), userConfig: &UserConfigManager{configPath: brokenPath}}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.WorktreeDir != "" {
+			t.Errorf("WorktreeDir = %q, want the built-in default (empty)", config.WorktreeDir)
+		}
+		if len(config.Warnings) != 1 || !strings.Contains(config.Warnings[0], "user config") {
+			t.Errorf("Warnings = %q, want one about the user config", config.Warnings)
+		}
+	})
+}
+
+func TestLoadSources(t *testing.T) {
+	userPath := filepath.Join(t.TempDir(), "config.toml")
+	os.WriteFile(userPath, []byte("[defaults]\nworktree-dir = \"~/worktrees\"\neditor = \"vim\"\nterminal-command = \"tmux\"\n\n[commit-generation]\ncommand = \"llm\"\n"), 0644)
+
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../project-worktrees\"\neditor = \"code\"\n\n[hooks]\npost-create = \"npm install\"\n"), 0644)
+	os.WriteFile(filepath.Join(dir, ConfigFileLocalTOML), []byte("editor = \"nvim\"\ngithub = false\n"), 0644)
+
+	config, err := (&Manager{configDir: dir, userConfig: &UserConfigManager{configPath: userPath}}).Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+
+	got := make(map[string]Setting)
+	for _, setting := range config.Settings() {
+		got[setting.Key] = setting
+	}
+	want := map[string]Setting{
+		"version":                   {Value: "1.0.0", Source: SourceProject},
+		"worktree_dir":              {Value: "../project-worktrees", Source: SourceProject},
+		"editor":                    {Value: "nvim", Source: SourceLocal},
+		"github":                    {Value: "false", Source: SourceLocal},
+		"terminal_command":          {Value: "tmux", Source: SourceUser},
+		"commit-generation.command": {Value: "llm", Source: SourceUser},
+		"hooks.post-create":         {Value: "npm install", Source: SourceProject},
+	}
+	for key, w := range want {
+		if g := got[key]; g.Value != w.Value || g.Source != w.Source {
+			t.Errorf("%s = %q (%s), want %q (%s)", key, g.Value, g.Source, w.Value, w.Source)
+		}
+	}
+	if len(got) != len(want) {
+		t.Errorf("Settings() = %v, want only %d settings", config.Settings(), len(want))
+	}
+	if config.GitHubEnabled() {
+		t.Error("GitHubEnabled() = true, want false with github = false")
+	}
+}
+
+func TestSettingsDefaults(t *testing.T) {
+	config := DefaultRuntimeConfig()
+	settings := config.Settings()
+	if len(settings) != 2 || settings[0].Key != "package_manager" || settings[1].Key != "version" {
+		t.Fatalf("Settings() = %v, want package_manager and version", settings)
+	}
+	for _, setting := range settings {
+		if setting.Source != SourceDefault {
+			t.Errorf("%s source = %s, want default", setting.Key, setting.Source)
+		}
+	}
+}
+
+func TestLoadLocalOverrides(t *testing.T) {
+	project := `version = "1.0.0"
+worktree_dir = "../shared-worktrees"
+editor = "code"
+
+[hooks]
+post-create = "npm install"
+pre-merge = "npm test"
+`
+
+	t.Run("toml", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte(project), 0644)
+		os.WriteFile(filepath.Join(dir, ConfigFileLocalTOML), []byte("editor = \"nvim\"\n\n[hooks]\npost-create = \"pnpm install\"\n"), 0644)
+
+		config, err := (&Manager{configDir: dir}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.Editor != "nvim" || config.Hooks.PostCreate != "pnpm install" {
+			t.Errorf("Editor = %q, post-create = %q; want local nvim, pnpm install", config.Editor, config.Hooks.PostCreate)
+		}
+		if config.WorktreeDir != "../shared-worktrees" || config.Hooks.PreMerge != "npm test" {
+			t.Errorf("WorktreeDir = %q, pre-merge = %q; want the project values kept", config.WorktreeDir, config.Hooks.PreMerge)
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte(project), 0644)
+		os.WriteFile(filepath.Join(dir, ConfigFileLocalJSON), []byte(`{"worktree_dir": "/scratch/worktrees"}`), 0644)
+
+		config, err := (&Manager{configDir: dir}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.WorktreeDir != "/scratch/worktrees" || config.Editor != "code" {
+			t.Errorf("WorktreeDir = %q, Editor = %q; want local worktree_dir and project editor", config.WorktreeDir, config.Editor)
+		}
+	})
+
+	t.Run("without project config", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileLocalTOML), []byte("editor = \"nvim\"\n"), 0644)
+
+		config, err := (&Manager{configDir: dir}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.Editor != "nvim" || config.WorktreeDir != "" {
+			t.Errorf("Editor = %q, WorktreeDir = %q; want local editor over runtime defaults", config.Editor, config.WorktreeDir)
+		}
+	})
+
+	t.Run("invalid override", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte(project), 0644)
+		localPath := filepath.Join(dir, ConfigFileLocalTOML)
+		os.WriteFile(localPath, []byte("package_manager = \"pip\"\n"), 0644)
+
+		_, err := (&Manager{configDir: dir}).Load()
+		if err == nil || !strings.Contains(err.Error(), localPath) {
+			t.Errorf("Load() error = %v, want an error naming %s", err, localPath)
+		}
+	})
+
+	t.Run("migration keeps overrides out of the shared config", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileJSON), []byte(`{"worktree_dir": "../shared-worktrees", "version": "1.0.0"}`), 0644)
+		os.WriteFile(filepath.Join(dir, ConfigFileLocalTOML), []byte("editor = \"nvim\"\n"), 0644)
+
+		m := &Manager{configDir: dir}
+		if _, err := m.Migrate(); err != nil {
+			t.Fatalf("Migrate() error: %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, ConfigFileTOML))
+		if err != nil {
+			t.Fatalf("Migrate() did not write config.toml: %v", err)
+		}
+		if strings.Contains(string(data), "nvim") {
+			t.Errorf("migrated config.toml contains the local editor override:\n%s", data)
+		}
+	})
+}
+
+func TestLoadWarnsAboutUnknownKeys(t *testing.T) {
+	t.Run("toml", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, ConfigFileTOML)
+		os.WriteFile(path, []byte("version = \"1.0.0\"\nworktree_dir = \"../wt\"\nEditor = \"nvim\"\nterminal-command = \"kitty\"\nfoo = 1\n"), 0644)
+
+		config, err := (&Manager{configDir: dir}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		want := []string{
+			`unknown config key "foo" in ` + path,
+			`unknown config key "terminal-command" in ` + path + ` (did you mean "terminal_command"?)`,
+		}
+		if strings.Join(config.Warnings, "\n") != strings.Join(want, "\n") {
+			t.Errorf("Warnings = %q, want %q", config.Warnings, want)
+		}
+		if config.Editor != "nvim" {
+			t.Errorf("Editor = %q, want nvim: keys match case-insensitively, so Editor is not unknown", config.Editor)
+		}
+	})
+
+	t.Run("json and local overrides", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileJSON), []byte(`{"version": "1.0.0", "worktree_dir": "../wt", "package_manger": "npm"}`), 0644)
+		os.WriteFile(filepath.Join(dir, ConfigFileLocalTOML), []byte("edtor = \"nvim\"\n"), 0644)
+
+		config, err := (&Manager{configDir: dir}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if len(config.Warnings) != 2 ||
+			!strings.Contains(config.Warnings[0], `"package_manger"`) || !strings.Contains(config.Warnings[0], `did you mean "package_manager"`) ||
+			!strings.Contains(config.Warnings[1], ConfigFileLocalTOML) || !strings.Contains(config.Warnings[1], `did you mean "editor"`) {
+			t.Errorf("Warnings = %q, want package_manger and edtor flagged", config.Warnings)
+		}
+	})
+
+	t.Run("known keys only", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../wt\"\n\n[hooks]\npost-create = \"npm install\"\n\n[[named-hooks.post-create]]\nname = \"x\"\ncommand = \"true\"\n"), 0644)
+
+		config, err := (&Manager{configDir: dir}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if len(config.Warnings) != 0 {
+			t.Errorf("Warnings = %q, want none", config.Warnings)
+		}
+	})
+
+	t.Run("checked again once the file changes", func(t *testing.T) {
+		dir := t.TempDir()
+		path := filepath.Join(dir, ConfigFileTOML)
+		os.WriteFile(path, []byte("version = \"1.0.0\"\nworktree_dir = \"../wt\"\nfoo = 1\n"), 0644)
+		manager := &Manager{configDir: dir}
+		for i := 0; i < 2; i++ {
+			config, err := manager.Load()
+			if err != nil {
+				t.Fatalf("Load() error: %v", err)
+			}
+			if len(config.Warnings) != 1 {
+				t.Fatalf("Load() #%d Warnings = %q, want foo flagged", i+1, config.Warnings)
+			}
+		}
+
+		os.WriteFile(path, []byte("version = \"1.0.0\"\nworktree_dir = \"../wt\"\n"), 0644)
+		later := time.Now().Add(time.Minute)
+		os.Chtimes(path, later, later)
+		config, err := manager.Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if len(config.Warnings) != 0 {
+			t.Errorf("Warnings after fixing the file = %q, want none", config.Warnings)
+		}
+	})
+
+	t.Run("typo that empties a required key", func(t *testing.T) {
+		dir := t.TempDir()
+		os.WriteFile(filepath.Join(dir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktreedir = \"../wt\"\n"), 0644)
+
+		_, err := (&Manager{configDir: dir}).Load()
+		if err == nil || !strings.Contains(err.Error(), `did you mean "worktree_dir"`) {
+			t.Errorf("Load() error = %v, want it to point at the misspelled worktreedir", err)
+		}
+	})
+}
+
 func TestLoadIgnoresMainWorktree(t *testing.T) {
 	// MainWorktree is now detected dynamically, so old configs with it should still load
 	tempDir, err := os.MkdirTemp("", "gren-load-config-*")
@@ -746,6 +1066,79 @@ func TestValidateConfig(t *testing.T) {
 			},
 			wantErr: false, // MainWorktree is now optional (detected dynamically)
 		},
+		{
+			name: "env file inside the worktree",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				EnvTemplate: ".gren/env.template",
+				EnvFile:     "apps/web/.env.local",
+			},
+			wantErr: false,
+		},
+		{
+			name: "default branch that does not exist",
+			config: &Config{
+				WorktreeDir:   "../worktrees",
+				Version:       "1.0.0",
+				DefaultBranch: "no-such-branch-for-gren-tests",
+			},
+			wantErr: true,
+		},
+		{
+			name: "env file outside the worktree",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				EnvFile:     "../.env",
+			},
+			wantErr: true,
+		},
+		{
+			name: "copy files globs",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				CopyFiles:   []string{"*.sqlite3", "tmp/cache"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "copy files outside the repository",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				CopyFiles:   []string{"../shared.db"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "preset copy files outside the repository",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				Presets:     map[string]Preset{"backend": {CopyFiles: []string{"../shared.db"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "preset symlink files outside the repository",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				Presets:     map[string]Preset{"frontend": {SymlinkFiles: []string{"/etc/hosts"}}},
+			},
+			wantErr: true,
+		},
+		{
+			name: "copy files invalid glob",
+			config: &Config{
+				WorktreeDir: "../worktrees",
+				Version:     "1.0.0",
+				CopyFiles:   []string{"data/[abc"},
+			},
+			wantErr: true,
+		},
 		{
 			name: "empty worktree dir",
 			config: &Config{
@@ -770,6 +1163,32 @@ func TestValidateConfig(t *testing.T) {
 				Version:        "1.0.0",
 			},
 			wantErr: true,
+		}, {
+			name: "openai commit generation with model",
+			config: &Config{
+				WorktreeDir:     "../worktrees",
+				Version:         "1.0.0",
+				CommitGenerator: CommitGenerator{Provider: "openai", Model: "gpt-4o-mini"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "openai commit generation without model",
+			config: &Config{
+				WorktreeDir:     "../worktrees",
+				Version:         "1.0.0",
+				CommitGenerator: CommitGenerator{Provider: "openai"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unknown commit generation provider",
+			config: &Config{
+				WorktreeDir:     "../worktrees",
+				Version:         "1.0.0",
+				CommitGenerator: CommitGenerator{Provider: "gemini"},
+			},
+			wantErr: true,
 		},
 	}
 
@@ -782,3 +1201,61 @@ func TestValidateConfig(t *testing.T) {
 		})
 	}
 }
+
+func TestLoadFromLinkedWorktree(t *testing.T) {
+	tempDir, err := os.MkdirTemp("", "gren-linked-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tempDir)
+	tempDir, _ = filepath.EvalSymlinks(tempDir)
+
+	repo := filepath.Join(tempDir, "repo")
+	linked := filepath.Join(tempDir, "linked")
+	for _, args := range [][]string{
+		{"init", "-b", "main", repo},
+		{"-C", repo, "-c", "user.name=Test", "-c", "user.email=test@test.com", "commit", "--allow-empty", "-m", "init"},
+		{"-C", repo, "worktree", "add", "-b", "feature", linked},
+	} {
+		if out, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	// A gitignored config only exists in the main worktree.
+	os.MkdirAll(filepath.Join(repo, ConfigDir), 0755)
+	os.WriteFile(filepath.Join(repo, ConfigDir, ConfigFileTOML), []byte("version = \"1.0.0\"\nworktree_dir = \"../custom\"\n"), 0644)
+
+	subdir := filepath.Join(linked, "sub")
+	os.MkdirAll(subdir, 0755)
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(subdir)
+
+	manager := NewManager()
+	if got, want := manager.Dir(), filepath.Join(repo, ConfigDir); got != want {
+		t.Errorf("Dir() = %s, want %s", got, want)
+	}
+	cfg, err := manager.Load()
+	if err != nil {
+		t.Fatalf("Load() error: %v", err)
+	}
+	if cfg.WorktreeDir != "../custom" {
+		t.Errorf("WorktreeDir = %q, want %q (main worktree config)", cfg.WorktreeDir, "../custom")
+	}
+
+	// The linked worktree's own config, e.g. a committed one changed on its
+	// branch, takes precedence. Dir is resolved once per manager and working
+	// directory, so a new manager sees it.
+	os.MkdirAll(filepath.Join(linked, ConfigDir), 0755)
+	if got, want := manager.Dir(), filepath.Join(repo, ConfigDir); got != want {
+		t.Errorf("Dir() = %s, want %s kept from before", got, want)
+	}
+	os.Chdir(linked)
+	if got, want := manager.Dir(), filepath.Join(linked, ConfigDir); got != want {
+		t.Errorf("Dir() after a chdir = %s, want %s", got, want)
+	}
+	if got, want := NewManager().Dir(), filepath.Join(linked, ConfigDir); got != want {
+		t.Errorf("Dir() with linked config = %s, want %s", got, want)
+	}
+}
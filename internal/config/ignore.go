@@ -0,0 +1,80 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"io/fs"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// envSearchDepth is how many directories below the repo root FindEnvFiles
+// looks for env files, enough for monorepo layouts like apps/web/.env.
+const envSearchDepth = 3
+
+// envSkipDirs are directories FindEnvFiles never descends into.
+var envSkipDirs = map[string]bool{
+	".git":         true,
+	".gren":        true,
+	"node_modules": true,
+	"vendor":       true,
+	".venv":        true,
+}
+
+// CheckIgnored reports which of paths git ignores, honoring nested
+// .gitignore files, .git/info/exclude and the global excludes file. All paths
+// are checked with a single `git check-ignore --stdin -z` run. Paths are
+// relative to the current directory; outside a repository none are ignored.
+func CheckIgnored(paths []string) map[string]bool {
+	ignored := make(map[string]bool)
+	if len(paths) == 0 {
+		return ignored
+	}
+
+	cmd := exec.Command("git", "check-ignore", "--stdin", "-z")
+	cmd.Stdin = strings.NewReader(strings.Join(paths, "\x00") + "\x00")
+	output, err := cmd.Output()
+	if err != nil {
+		// Exit status 1 means none of the paths are ignored; anything else
+		// (not a repository, git missing) is treated the same way.
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) || exitErr.ExitCode() != 1 {
+			return ignored
+		}
+	}
+
+	for _, path := range bytes.Split(output, []byte{0}) {
+		if len(path) > 0 {
+			ignored[string(path)] = true
+		}
+	}
+	return ignored
+}
+
+// FindEnvFiles returns the files under the current directory whose base name
+// matches any of patterns (filepath.Match syntax, e.g. ".env*"), searching
+// envSearchDepth levels deep and skipping dependency and VCS directories.
+// Paths are relative and sorted.
+func FindEnvFiles(patterns ...string) []string {
+	var found []string
+	filepath.WalkDir(".", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != "." && (envSkipDirs[d.Name()] || strings.Count(path, string(filepath.Separator)) >= envSearchDepth) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, d.Name()); ok {
+				found = append(found, path)
+				break
+			}
+		}
+		return nil
+	})
+	return found
+}
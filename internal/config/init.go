@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strings"
 )
 
@@ -13,14 +14,61 @@ type InitResult struct {
 	Success       bool
 	ConfigCreated bool
 	HookCreated   bool
+	HookTemplate  string // Template the created hook was written from; "" for the generic one
 	Message       string
 	Error         error
 }
 
+// Post-create hook templates for InitializeWithHook. Each installs the
+// toolchain's dependencies on top of the symlinks every hook sets up.
+const (
+	HookTemplateNode   = "node"
+	HookTemplateGo     = "go"
+	HookTemplateRust   = "rust"
+	HookTemplatePython = "python"
+	HookTemplateAuto   = "auto" // Pick one from the project files, see DetectHookTemplate
+	HookTemplateNone   = "none" // Write no hook and leave it out of a new config
+)
+
+// HookTemplates lists the hook template names InitializeWithHook accepts.
+var HookTemplates = []string{HookTemplateNode, HookTemplateGo, HookTemplateRust, HookTemplatePython, HookTemplateAuto, HookTemplateNone}
+
+// DetectHookTemplate picks the hook template for the project in the current
+// directory from its manifest files, or "" (the generic hook) when none
+// matches. package.json wins in a mixed repository, as it is usually the
+// one that needs installing before anything runs.
+func DetectHookTemplate() string {
+	switch {
+	case fileExists("package.json"):
+		return HookTemplateNode
+	case fileExists("go.mod"):
+		return HookTemplateGo
+	case fileExists("Cargo.toml"):
+		return HookTemplateRust
+	case fileExists("pyproject.toml"), fileExists("requirements.txt"), fileExists("setup.py"):
+		return HookTemplatePython
+	default:
+		return ""
+	}
+}
+
 // Initialize sets up gren configuration for the current repository
 func Initialize(projectName string, trackGrenInGit bool) InitResult {
+	return InitializeWithHook(projectName, trackGrenInGit, "")
+}
+
+// InitializeWithHook is Initialize with the post-create hook written from
+// hookTemplate, one of HookTemplates, instead of the generic hook whose
+// install step is commented out. "" means the generic hook. An existing
+// hook script is never overwritten.
+func InitializeWithHook(projectName string, trackGrenInGit bool, hookTemplate string) InitResult {
 	result := InitResult{}
 
+	if hookTemplate != "" && !slices.Contains(HookTemplates, hookTemplate) {
+		result.Error = fmt.Errorf("unknown hook template %q (valid: %s)", hookTemplate, strings.Join(HookTemplates, ", "))
+		return result
+	}
+
 	// Get the repository root (main worktree path)
 	repoRoot, err := getRepoRoot()
 	if err != nil {
@@ -54,6 +102,12 @@ func Initialize(projectName string, trackGrenInGit bool) InitResult {
 			result.Error = fmt.Errorf("failed to add .gren to .gitignore: %w", err)
 			return result
 		}
+	} else {
+		// .gren is shared, but personal overrides must stay out of git
+		if err := addToGitignore(ConfigDir + "/config.local.*"); err != nil {
+			result.Error = fmt.Errorf("failed to add local config to .gitignore: %w", err)
+			return result
+		}
 	}
 
 	manager := NewManager()
@@ -64,7 +118,7 @@ func Initialize(projectName string, trackGrenInGit bool) InitResult {
 	// Check if config already exists (migrate or preserve)
 	if manager.Exists() {
 		wasJSON = manager.ExistsJSON() && !manager.ExistsTOML()
-		existingConfig, err = manager.Load()
+		existingConfig, err = manager.loadShared()
 		if err != nil {
 			// Config exists but failed to load - create new but warn
 			result.Message = fmt.Sprintf("Warning: existing config could not be loaded (%v), creating new", err)
@@ -85,8 +139,18 @@ func Initialize(projectName string, trackGrenInGit bool) InitResult {
 			result.Error = fmt.Errorf("failed to create default config: %w", err)
 			return result
 		}
+		// The user's default location, if any, beats the sibling directory
+		if user, err := manager.userConfig.Load(); err == nil && user.Defaults.WorktreeDir != "" {
+			config.WorktreeDir = user.Defaults.WorktreeDir
+		}
 		// Detect package manager and files to symlink (including .gren if gitignored)
 		config, _ = detectProjectSettings(config, trackGrenInGit)
+		if hookTemplate == HookTemplateNone {
+			config.PostCreateHook = ""
+		}
+	}
+	if hookTemplate == HookTemplateAuto {
+		hookTemplate = DetectHookTemplate()
 	}
 
 	// Only save if new config or migrating from JSON
@@ -105,18 +169,19 @@ func Initialize(projectName string, trackGrenInGit bool) InitResult {
 	if hookPath == "" {
 		hookPath = config.Hooks.PostCreate
 	}
-	if hookPath != "" && !fileExists(hookPath) {
+	if hookPath != "" && !fileExists(hookPath) && hookTemplate != HookTemplateNone {
 		detected := DetectedFiles{} // Empty for existing configs
 		if existingConfig == nil {
 			// Only detect for new configs
 			_, detected = detectProjectSettings(config, trackGrenInGit)
 		}
-		err = createPostCreateHookWithSymlinks(hookPath, config, detected)
+		err = createPostCreateHookWithSymlinks(hookPath, config, detected, hookTemplate)
 		if err != nil {
 			result.Error = fmt.Errorf("failed to create post-create hook: %w", err)
 			return result
 		}
 		result.HookCreated = true
+		result.HookTemplate = hookTemplate
 	}
 
 	// Create README.md in .gren directory
@@ -163,45 +228,57 @@ func detectProjectSettings(config *Config, trackGrenInGit bool) (*Config, Detect
 		config.PackageManager = "npm"
 	}
 
-	// Detect .env files that are gitignored
-	envPatterns := []string{".env.local", ".env.*.local", ".env.llm.local"}
-	for _, pattern := range envPatterns {
-		matches, _ := filepath.Glob(pattern)
-		for _, match := range matches {
-			if isGitIgnored(match) {
-				detected.EnvFiles = append(detected.EnvFiles, match)
-			}
+	// Collect every candidate first so git is asked about all of them at once
+	envFiles := FindEnvFiles(".env.local", ".env.*.local", ".env.llm.local")
+	var configFiles []string
+	for _, file := range []string{".envrc", ".nvmrc", ".node-version"} {
+		if fileExists(file) {
+			configFiles = append(configFiles, file)
+		}
+	}
+	candidates := append(append([]string{}, envFiles...), configFiles...)
+	for _, name := range []string{".claude", "CLAUDE.md", ".gren"} {
+		if fileExists(name) {
+			candidates = append(candidates, name)
+		}
+	}
+	ignored := CheckIgnored(candidates)
+
+	// Detect .env files that are gitignored, including ones in subdirectories
+	for _, file := range envFiles {
+		if ignored[file] {
+			detected.EnvFiles = append(detected.EnvFiles, file)
 		}
 	}
 
 	// Check for common config files that are gitignored
-	checkFiles := []string{".envrc", ".nvmrc", ".node-version"}
-	for _, file := range checkFiles {
-		if fileExists(file) && isGitIgnored(file) {
+	for _, file := range configFiles {
+		if ignored[file] {
 			detected.ConfigFiles = append(detected.ConfigFiles, file)
 		}
 	}
 
 	// Check for .claude directory (if gitignored)
-	if dirExists(".claude") && isGitIgnored(".claude") {
+	if dirExists(".claude") && ignored[".claude"] {
 		detected.ClaudeDir = true
 	}
 
 	// Check for CLAUDE.md (if gitignored)
-	if fileExists("CLAUDE.md") && isGitIgnored("CLAUDE.md") {
+	if fileExists("CLAUDE.md") && ignored["CLAUDE.md"] {
 		detected.ClaudeMd = true
 	}
 
 	// Check if .gren should be symlinked (if user chose to gitignore it)
-	if !trackGrenInGit && dirExists(".gren") && isGitIgnored(".gren") {
+	if !trackGrenInGit && dirExists(".gren") && ignored[".gren"] {
 		detected.GrenDir = true
 	}
 
 	return config, detected
 }
 
-// createPostCreateHookWithSymlinks creates a post-create hook script using symlinks
-func createPostCreateHookWithSymlinks(hookPath string, config *Config, detected DetectedFiles) error {
+// createPostCreateHookWithSymlinks creates a post-create hook script using
+// symlinks, with the install step of hookTemplate ("" for the generic hook)
+func createPostCreateHookWithSymlinks(hookPath string, config *Config, detected DetectedFiles, hookTemplate string) error {
 	// Ensure directory exists
 	dir := filepath.Dir(hookPath)
 	err := os.MkdirAll(dir, 0755)
@@ -210,7 +287,7 @@ func createPostCreateHookWithSymlinks(hookPath string, config *Config, detected
 	}
 
 	// Generate hook content with symlinks
-	content := generateHookContentWithSymlinks(config, detected)
+	content := generateHookContentWithSymlinks(config, detected, hookTemplate)
 
 	// Write hook file
 	err = os.WriteFile(hookPath, []byte(content), 0755)
@@ -218,11 +295,15 @@ func createPostCreateHookWithSymlinks(hookPath string, config *Config, detected
 		return err
 	}
 
-	return nil
+	// WriteFile's mode is filtered by the umask; the hook must end up
+	// executable or it fails with "permission denied" on first use
+	return os.Chmod(hookPath, 0755)
 }
 
-// generateHookContentWithSymlinks creates the content for the post-create hook using symlinks
-func generateHookContentWithSymlinks(config *Config, detected DetectedFiles) string {
+// generateHookContentWithSymlinks creates the content for the post-create hook
+// using symlinks. hookTemplate adds its toolchain's install step; the generic
+// hook ("") only has a commented-out package manager install.
+func generateHookContentWithSymlinks(config *Config, detected DetectedFiles, hookTemplate string) string {
 	var builder strings.Builder
 
 	builder.WriteString("#!/usr/bin/env bash\n")
@@ -250,6 +331,10 @@ func generateHookContentWithSymlinks(config *Config, detected DetectedFiles) str
 		builder.WriteString("# Symlink environment files\n")
 		builder.WriteString("echo \"🔗 Symlinking env files...\"\n")
 		for _, envFile := range detected.EnvFiles {
+			if dir := filepath.Dir(envFile); dir != "." {
+				builder.WriteString(fmt.Sprintf("[ -f \"$REPO_ROOT/%s\" ] && mkdir -p \"$WORKTREE_PATH/%s\" && ln -sf \"$REPO_ROOT/%s\" \"$WORKTREE_PATH/%s\" && echo \"   ✓ %s\"\n", envFile, dir, envFile, envFile, envFile))
+				continue
+			}
 			builder.WriteString(fmt.Sprintf("[ -f \"$REPO_ROOT/%s\" ] && ln -sf \"$REPO_ROOT/%s\" \"$WORKTREE_PATH/%s\" && echo \"   ✓ %s\"\n", envFile, envFile, envFile, envFile))
 		}
 		builder.WriteString("echo \"\"\n\n")
@@ -314,17 +399,59 @@ func generateHookContentWithSymlinks(config *Config, detected DetectedFiles) str
 	builder.WriteString("    echo \"\"\n")
 	builder.WriteString("fi\n\n")
 
-	// Package manager installation (commented out by default - user can enable)
-	builder.WriteString("# Uncomment below to auto-install dependencies\n")
-	builder.WriteString("# if [ -f \"package.json\" ]; then\n")
-	if config.PackageManager != "auto" && config.PackageManager != "" {
-		builder.WriteString(fmt.Sprintf("#     echo \"📦 Installing dependencies with %s...\"\n", config.PackageManager))
-		builder.WriteString(fmt.Sprintf("#     %s install\n", config.PackageManager))
-	} else {
-		builder.WriteString("#     echo \"📦 Installing dependencies...\"\n")
-		builder.WriteString("#     npm install  # or: yarn, pnpm, bun\n")
+	switch hookTemplate {
+	case HookTemplateNode:
+		packageManager := config.PackageManager
+		if packageManager == "auto" || packageManager == "" {
+			packageManager = "npm"
+		}
+		builder.WriteString("# Install dependencies\n")
+		builder.WriteString("if [ -f \"package.json\" ]; then\n")
+		builder.WriteString(fmt.Sprintf("    echo \"📦 Installing dependencies with %s...\"\n", packageManager))
+		builder.WriteString(fmt.Sprintf("    %s install\n", packageManager))
+		builder.WriteString("    echo \"\"\n")
+		builder.WriteString("fi\n\n")
+	case HookTemplateGo:
+		builder.WriteString("# Download Go modules\n")
+		builder.WriteString("if [ -f \"go.mod\" ]; then\n")
+		builder.WriteString("    echo \"📦 Downloading Go modules...\"\n")
+		builder.WriteString("    go mod download\n")
+		builder.WriteString("    echo \"\"\n")
+		builder.WriteString("fi\n\n")
+	case HookTemplateRust:
+		builder.WriteString("# Fetch crates\n")
+		builder.WriteString("if [ -f \"Cargo.toml\" ]; then\n")
+		builder.WriteString("    echo \"📦 Fetching crates...\"\n")
+		builder.WriteString("    cargo fetch\n")
+		builder.WriteString("    echo \"\"\n")
+		builder.WriteString("fi\n\n")
+	case HookTemplatePython:
+		builder.WriteString("# Install dependencies with the tool the project uses, else into a .venv\n")
+		builder.WriteString("if [ -f \"uv.lock\" ] && command -v uv &> /dev/null; then\n")
+		builder.WriteString("    echo \"📦 Installing dependencies with uv...\"\n")
+		builder.WriteString("    uv sync\n")
+		builder.WriteString("elif [ -f \"poetry.lock\" ] && command -v poetry &> /dev/null; then\n")
+		builder.WriteString("    echo \"📦 Installing dependencies with poetry...\"\n")
+		builder.WriteString("    poetry install\n")
+		builder.WriteString("elif [ -f \"requirements.txt\" ]; then\n")
+		builder.WriteString("    echo \"📦 Installing requirements.txt into .venv...\"\n")
+		builder.WriteString("    python3 -m venv .venv\n")
+		builder.WriteString("    .venv/bin/pip install -r requirements.txt\n")
+		builder.WriteString("fi\n")
+		builder.WriteString("echo \"\"\n\n")
+	default:
+		// Package manager installation (commented out by default - user can enable)
+		builder.WriteString("# Uncomment below to auto-install dependencies\n")
+		builder.WriteString("# if [ -f \"package.json\" ]; then\n")
+		if config.PackageManager != "auto" && config.PackageManager != "" {
+			builder.WriteString(fmt.Sprintf("#     echo \"📦 Installing dependencies with %s...\"\n", config.PackageManager))
+			builder.WriteString(fmt.Sprintf("#     %s install\n", config.PackageManager))
+		} else {
+			builder.WriteString("#     echo \"📦 Installing dependencies...\"\n")
+			builder.WriteString("#     npm install  # or: yarn, pnpm, bun\n")
+		}
+		builder.WriteString("# fi\n\n")
 	}
-	builder.WriteString("# fi\n\n")
 
 	builder.WriteString("echo \"✅ Post-create setup complete!\"\n")
 	builder.WriteString("echo \"\"\n")
@@ -343,33 +470,10 @@ func dirExists(path string) bool {
 	return err == nil && info.IsDir()
 }
 
+// isGitIgnored reports whether git ignores path. Use CheckIgnored to check
+// several paths at once.
 func isGitIgnored(path string) bool {
-	if !fileExists(".gitignore") {
-		return false
-	}
-
-	data, err := os.ReadFile(".gitignore")
-	if err != nil {
-		return false
-	}
-
-	gitignoreContent := string(data)
-
-	// Check for various gitignore patterns for the path
-	patterns := []string{
-		path,             // .claude
-		"/" + path,       // /.claude
-		path + "/",       // .claude/
-		"/" + path + "/", // /.claude/
-	}
-
-	for _, pattern := range patterns {
-		if strings.Contains(gitignoreContent, pattern) {
-			return true
-		}
-	}
-
-	return false
+	return CheckIgnored([]string{path})[path]
 }
 
 // createGrenReadme creates a README.md file in the .gren directory
@@ -395,12 +499,16 @@ brew install langtind/tap/gren
 
 - ` + "`config.toml`" + ` - Project configuration (worktree directory, hooks)
 - ` + "`post-create.sh`" + ` - Script that runs after creating new worktrees
+- ` + "`config.local.toml`" + ` - Optional personal overrides of config.toml (keep it out of git)
 `
 	return os.WriteFile(readmePath, []byte(content), 0644)
 }
 
 // getRepoRoot returns the absolute path to the repository root (main worktree)
 func getRepoRoot() (string, error) {
+	if root := MainWorktreeRoot(); root != "" {
+		return root, nil
+	}
 	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
@@ -409,6 +517,54 @@ func getRepoRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// MainWorktreeRoot returns the main worktree's root, even when run from a
+// linked worktree. It does not assume the git dir is <root>/.git, which is
+// wrong for a submodule (its git dir is in the superproject's .git/modules)
+// and for a git dir set apart with --separate-git-dir or GIT_DIR: from the
+// main worktree it is the toplevel, else the parent of a .git common dir,
+// else the common dir's core.worktree. It is "" outside a repository, for
+// bare repositories, which have no main worktree, and when none of those
+// tell.
+func MainWorktreeRoot() string {
+	gitDir := gitPath("--path-format=absolute", "--git-dir")
+	commonDir := gitPath("--path-format=absolute", "--git-common-dir")
+	if commonDir == "" {
+		return ""
+	}
+	if gitDir == commonDir {
+		return gitPath("--show-toplevel")
+	}
+	if filepath.Base(commonDir) == ".git" {
+		return filepath.Dir(commonDir)
+	}
+	output, err := exec.Command("git", "--git-dir", commonDir, "config", "--get", "core.worktree").Output()
+	if err != nil {
+		return ""
+	}
+	worktree := strings.TrimSpace(string(output))
+	if worktree == "" {
+		return ""
+	}
+	if !filepath.IsAbs(worktree) {
+		worktree = filepath.Join(commonDir, worktree)
+	}
+	return filepath.Clean(worktree)
+}
+
+// gitPath runs git rev-parse with args and returns the cleaned path it
+// prints, or "" when git fails.
+func gitPath(args ...string) string {
+	output, err := exec.Command("git", append([]string{"rev-parse"}, args...)...).Output()
+	if err != nil {
+		return ""
+	}
+	path := strings.TrimSpace(string(output))
+	if path == "" {
+		return ""
+	}
+	return filepath.Clean(path)
+}
+
 // addToGitignore adds a pattern to .gitignore if it's not already there
 func addToGitignore(pattern string) error {
 	gitignorePath := ".gitignore"
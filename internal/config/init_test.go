@@ -4,6 +4,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"testing"
 )
 
@@ -98,6 +99,7 @@ func TestDetectEnvFiles(t *testing.T) {
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	os.Chdir(tempDir)
+	exec.Command("git", "init", "-b", "main").Run()
 
 	// Create .gitignore
 	gitignore := ".env.local\n.env.*.local\n"
@@ -133,6 +135,7 @@ func TestDetectClaudeDir(t *testing.T) {
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	os.Chdir(tempDir)
+	exec.Command("git", "init", "-b", "main").Run()
 
 	t.Run("claude dir exists and gitignored", func(t *testing.T) {
 		os.Mkdir(".claude", 0755)
@@ -162,6 +165,33 @@ func TestDetectClaudeDir(t *testing.T) {
 }
 
 func TestInitialize(t *testing.T) {
+	t.Run("writes the user's default worktree dir", func(t *testing.T) {
+		home := t.TempDir()
+		t.Setenv("HOME", home)
+		t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+		t.Setenv("APPDATA", filepath.Join(home, "AppData"))
+		if err := NewUserConfigManager().Save(&UserConfig{Defaults: UserDefaults{WorktreeDir: "~/worktrees/{{ repo }}"}}); err != nil {
+			t.Fatalf("saving user config: %v", err)
+		}
+
+		tempDir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		defer os.Chdir(originalDir)
+		os.Chdir(tempDir)
+		exec.Command("git", "init", "-b", "main").Run()
+
+		if result := Initialize("test-project", true); result.Error != nil {
+			t.Fatalf("Initialize() failed: %v", result.Error)
+		}
+		config, err := (&Manager{configDir: filepath.Join(tempDir, ConfigDir)}).Load()
+		if err != nil {
+			t.Fatalf("Load() error: %v", err)
+		}
+		if config.WorktreeDir != "~/worktrees/{{ repo }}" {
+			t.Errorf("WorktreeDir = %q, want the user default", config.WorktreeDir)
+		}
+	})
+
 	t.Run("successful initialization", func(t *testing.T) {
 		tempDir, err := os.MkdirTemp("", "gren-init-test-*")
 		if err != nil {
@@ -202,9 +232,11 @@ func TestInitialize(t *testing.T) {
 			t.Errorf("config.toml not created: %v", err)
 		}
 
-		// Verify hook file exists
-		if _, err := os.Stat(filepath.Join(".gren", "post-create.sh")); err != nil {
+		// Verify hook file exists and is executable
+		if info, err := os.Stat(filepath.Join(".gren", "post-create.sh")); err != nil {
 			t.Errorf("post-create.sh not created: %v", err)
+		} else if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+			t.Errorf("post-create.sh mode = %v, want it executable", info.Mode())
 		}
 
 		// Verify README.md exists
@@ -423,6 +455,7 @@ func TestDetectConfigFiles(t *testing.T) {
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	os.Chdir(tempDir)
+	exec.Command("git", "init", "-b", "main").Run()
 
 	// Create gitignored config files
 	os.WriteFile(".gitignore", []byte(".envrc\n.nvmrc\n"), 0644)
@@ -447,6 +480,7 @@ func TestDetectClaudeMd(t *testing.T) {
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	os.Chdir(tempDir)
+	exec.Command("git", "init", "-b", "main").Run()
 
 	// Create gitignored CLAUDE.md
 	os.WriteFile(".gitignore", []byte("CLAUDE.md\n"), 0644)
@@ -472,7 +506,7 @@ func TestGenerateHookContent(t *testing.T) {
 			ClaudeDir: true,
 		}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		// The script should check if .claude is already a real directory (not a symlink)
 		// and skip creating a symlink if so. This handles the case where .claude is
@@ -493,7 +527,7 @@ func TestGenerateHookContent(t *testing.T) {
 			GrenDir: true,
 		}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		// Same check for .gren directory
 		if !contains(content, "! -L") || !contains(content, "-d \"$WORKTREE_PATH/.gren\"") {
@@ -510,7 +544,7 @@ func TestGenerateHookContent(t *testing.T) {
 
 		detected := DetectedFiles{}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		// Check for gren branding header
 		if !contains(content, "gren - Git Worktree Manager") {
@@ -536,7 +570,7 @@ func TestGenerateHookContent(t *testing.T) {
 			ClaudeDir: true,
 		}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		// Check for shebang
 		if content[:2] != "#!" {
@@ -568,7 +602,7 @@ func TestGenerateHookContent(t *testing.T) {
 
 		detected := DetectedFiles{}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		// Should have npm as fallback suggestion
 		if !contains(content, "npm install") {
@@ -587,7 +621,7 @@ func TestGenerateHookContent(t *testing.T) {
 			ConfigFiles: []string{".envrc", ".nvmrc"},
 		}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		if !contains(content, ".envrc") {
 			t.Error("Hook should contain .envrc symlink")
@@ -608,7 +642,7 @@ func TestGenerateHookContent(t *testing.T) {
 			ClaudeMd: true,
 		}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		if !contains(content, "CLAUDE.md") {
 			t.Error("Hook should contain CLAUDE.md symlink")
@@ -624,7 +658,7 @@ func TestGenerateHookContent(t *testing.T) {
 
 		detected := DetectedFiles{}
 
-		content := generateHookContentWithSymlinks(config, detected)
+		content := generateHookContentWithSymlinks(config, detected, "")
 
 		// Should still have basic structure
 		if !contains(content, "#!/usr/bin/env bash") {
@@ -692,6 +726,7 @@ func TestIsGitIgnored(t *testing.T) {
 	originalDir, _ := os.Getwd()
 	defer os.Chdir(originalDir)
 	os.Chdir(tempDir)
+	exec.Command("git", "init", "-b", "main").Run()
 
 	t.Run("no gitignore file", func(t *testing.T) {
 		if isGitIgnored(".env") {
@@ -701,6 +736,7 @@ func TestIsGitIgnored(t *testing.T) {
 
 	t.Run("file is gitignored", func(t *testing.T) {
 		os.WriteFile(".gitignore", []byte(".env\n.claude/\n"), 0644)
+		os.Mkdir(".claude", 0755)
 
 		if !isGitIgnored(".env") {
 			t.Error("isGitIgnored() = false for .env which is in .gitignore")
@@ -716,6 +752,161 @@ func TestIsGitIgnored(t *testing.T) {
 			t.Error("isGitIgnored() = true for README.md which is not in .gitignore")
 		}
 	})
+
+	t.Run("file is in info/exclude", func(t *testing.T) {
+		os.WriteFile(filepath.Join(".git", "info", "exclude"), []byte("CLAUDE.md\n"), 0644)
+
+		if !isGitIgnored("CLAUDE.md") {
+			t.Error("isGitIgnored() = false for CLAUDE.md which is in .git/info/exclude")
+		}
+	})
+
+	t.Run("file is in nested gitignore", func(t *testing.T) {
+		os.MkdirAll(filepath.Join("apps", "web"), 0755)
+		os.WriteFile(filepath.Join("apps", "web", ".gitignore"), []byte(".env.local\n"), 0644)
+
+		if !isGitIgnored("apps/web/.env.local") {
+			t.Error("isGitIgnored() = false for apps/web/.env.local which is in apps/web/.gitignore")
+		}
+		if isGitIgnored(".env.local") {
+			t.Error("isGitIgnored() = true for .env.local, which only apps/web/.gitignore ignores")
+		}
+	})
+}
+
+func TestCheckIgnored(t *testing.T) {
+	tempDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tempDir)
+
+	t.Run("outside a repository", func(t *testing.T) {
+		if got := CheckIgnored([]string{".env"}); len(got) != 0 {
+			t.Errorf("CheckIgnored() = %v outside a repository, want none", got)
+		}
+	})
+
+	exec.Command("git", "init", "-b", "main").Run()
+	os.WriteFile(".gitignore", []byte(".env\n*.local\n"), 0644)
+
+	got := CheckIgnored([]string{".env", "README.md", "apps/api/.env.local", "with space.local"})
+	want := map[string]bool{".env": true, "apps/api/.env.local": true, "with space.local": true}
+	if len(got) != len(want) {
+		t.Fatalf("CheckIgnored() = %v, want %v", got, want)
+	}
+	for path := range want {
+		if !got[path] {
+			t.Errorf("CheckIgnored() missing %q, got %v", path, got)
+		}
+	}
+
+	if got := CheckIgnored([]string{"README.md"}); len(got) != 0 {
+		t.Errorf("CheckIgnored() = %v when nothing is ignored, want none", got)
+	}
+}
+
+func TestDetectNestedEnvFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	originalDir, _ := os.Getwd()
+	defer os.Chdir(originalDir)
+	os.Chdir(tempDir)
+	exec.Command("git", "init", "-b", "main").Run()
+
+	os.MkdirAll(filepath.Join("apps", "web"), 0755)
+	os.MkdirAll(filepath.Join("node_modules", "pkg"), 0755)
+	os.WriteFile(filepath.Join("apps", "web", ".gitignore"), []byte(".env.local\n"), 0644)
+	os.WriteFile(filepath.Join("apps", "web", ".env.local"), []byte("SECRET=value"), 0644)
+	os.WriteFile(filepath.Join("node_modules", "pkg", ".env.local"), []byte("SECRET=value"), 0644)
+	os.WriteFile(filepath.Join(".git", "info", "exclude"), []byte(".env.local\n"), 0644)
+	os.WriteFile(".env.local", []byte("SECRET=value"), 0644)
+
+	config, _ := NewDefaultConfig("test", tempDir)
+	_, detected := detectProjectSettings(config, true)
+
+	want := []string{".env.local", filepath.Join("apps", "web", ".env.local")}
+	if len(detected.EnvFiles) != len(want) {
+		t.Fatalf("EnvFiles = %v, want %v", detected.EnvFiles, want)
+	}
+	for i := range want {
+		if detected.EnvFiles[i] != want[i] {
+			t.Errorf("EnvFiles[%d] = %q, want %q", i, detected.EnvFiles[i], want[i])
+		}
+	}
+
+	hook := generateHookContentWithSymlinks(config, detected, "")
+	if !contains(hook, `mkdir -p "$WORKTREE_PATH/apps/web"`) {
+		t.Errorf("hook does not create apps/web before symlinking:\n%s", hook)
+	}
+}
+
+func TestInitializeWithHook(t *testing.T) {
+	initRepo := func(t *testing.T) string {
+		dir := t.TempDir()
+		originalDir, _ := os.Getwd()
+		t.Cleanup(func() { os.Chdir(originalDir) })
+		os.Chdir(dir)
+		exec.Command("git", "init", "-b", "main").Run()
+		return dir
+	}
+
+	t.Run("auto picks the template from the project files", func(t *testing.T) {
+		dir := initRepo(t)
+		os.WriteFile(filepath.Join(dir, "go.mod"), []byte("module example.com/app\n"), 0644)
+
+		result := InitializeWithHook("app", true, HookTemplateAuto)
+		if result.Error != nil || !result.HookCreated || result.HookTemplate != HookTemplateGo {
+			t.Fatalf("result = %+v, want a go hook", result)
+		}
+		hook, err := os.ReadFile(filepath.Join(dir, ConfigDir, DefaultHookFile))
+		if err != nil || !contains(string(hook), "go mod download") {
+			t.Errorf("hook = %q (%v), want go mod download", hook, err)
+		}
+	})
+
+	t.Run("none writes no hook and leaves it out of the config", func(t *testing.T) {
+		dir := initRepo(t)
+
+		result := InitializeWithHook("app", true, HookTemplateNone)
+		if result.Error != nil || result.HookCreated {
+			t.Fatalf("result = %+v, want no hook", result)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ConfigDir, DefaultHookFile)); !os.IsNotExist(err) {
+			t.Errorf("hook script exists: %v", err)
+		}
+		config, err := (&Manager{configDir: filepath.Join(dir, ConfigDir)}).Load()
+		if err != nil || config.PostCreateHook != "" {
+			t.Errorf("PostCreateHook = %q (%v), want none", config.PostCreateHook, err)
+		}
+	})
+
+	t.Run("unknown template", func(t *testing.T) {
+		dir := initRepo(t)
+		if result := InitializeWithHook("app", true, "java"); result.Error == nil {
+			t.Error("expected an error for an unknown template")
+		}
+		if _, err := os.Stat(filepath.Join(dir, ConfigDir)); !os.IsNotExist(err) {
+			t.Errorf(".gren created despite the error: %v", err)
+		}
+	})
+}
+
+func TestGenerateHookContentTemplates(t *testing.T) {
+	config := &Config{PackageManager: "pnpm"}
+	tests := []struct {
+		template string
+		want     string
+	}{
+		{HookTemplateNode, "    pnpm install"},
+		{HookTemplateGo, "    go mod download"},
+		{HookTemplateRust, "    cargo fetch"},
+		{HookTemplatePython, "    uv sync"},
+		{"", "#     pnpm install"},
+	}
+	for _, tt := range tests {
+		if hook := generateHookContentWithSymlinks(config, DetectedFiles{}, tt.template); !contains(hook, tt.want) {
+			t.Errorf("template %q: hook lacks %q:\n%s", tt.template, tt.want, hook)
+		}
+	}
 }
 
 // Helper function
@@ -35,8 +35,8 @@ func (m *Manager) NeedsMigration() (bool, *MigrationResult, error) {
 		return false, nil, nil
 	}
 
-	// Load current config
-	config, err := m.Load()
+	// Load current config, without local overrides that must not be saved
+	config, err := m.loadShared()
 	if err != nil {
 		return false, nil, fmt.Errorf("failed to load config for migration check: %w", err)
 	}
@@ -69,8 +69,8 @@ func (m *Manager) Migrate() (*MigrationResult, error) {
 		return nil, nil // Nothing to do
 	}
 
-	// Load current config
-	config, err := m.Load()
+	// Load current config, without local overrides that must not be saved
+	config, err := m.loadShared()
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config for migration: %w", err)
 	}
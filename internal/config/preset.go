@@ -0,0 +1,76 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Preset is a named bundle of settings for role-specific worktrees (a
+// frontend worktree that needs node_modules, a backend one that needs a
+// database copy), so `gren create --preset <name>` sets them all up in one
+// command. Configured under [presets.<name>] in .gren/config.toml.
+type Preset struct {
+	// BaseBranch is the base for new branches when -b isn't given.
+	BaseBranch string `json:"base_branch,omitempty" toml:"base_branch,omitempty"`
+	// PostCreate runs after the configured post-create hooks, with the same
+	// approval and context.
+	PostCreate string `json:"post_create,omitempty" toml:"post-create,omitempty"`
+	// CopyFiles is added to the project's copy_files.
+	CopyFiles []string `json:"copy_files,omitempty" toml:"copy_files,omitempty"`
+	// SymlinkFiles lists globs, relative to the main worktree, of files and
+	// directories linked into the new worktree, shared rather than copied.
+	// Like copy_files, paths that already exist in the worktree are left
+	// alone.
+	SymlinkFiles []string `json:"symlink_files,omitempty" toml:"symlink_files,omitempty"`
+}
+
+// Preset returns the preset called name, or an error listing the presets
+// that are configured.
+func (c *Config) Preset(name string) (Preset, error) {
+	if preset, ok := c.Presets[name]; ok {
+		return preset, nil
+	}
+	names := c.PresetNames()
+	if len(names) == 0 {
+		return Preset{}, fmt.Errorf("unknown preset %q: no presets are configured (define them under [presets.<name>] in .gren/config.toml)", name)
+	}
+	return Preset{}, fmt.Errorf("unknown preset %q (available: %s)", name, strings.Join(names, ", "))
+}
+
+// PresetNames returns the configured preset names, sorted.
+func (c *Config) PresetNames() []string {
+	names := make([]string, 0, len(c.Presets))
+	for name := range c.Presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ResolvePreset loads the configuration and returns the preset called name.
+func (m *Manager) ResolvePreset(name string) (Preset, error) {
+	cfg, err := m.Load()
+	if err != nil {
+		return Preset{}, err
+	}
+	return cfg.Preset(name)
+}
+
+// validate checks the preset called name.
+func (p Preset) validate(name string) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("preset names cannot be empty")
+	}
+	for _, pattern := range p.CopyFiles {
+		if err := validateCopyPattern(pattern); err != nil {
+			return fmt.Errorf("preset %q: %w", name, err)
+		}
+	}
+	for _, pattern := range p.SymlinkFiles {
+		if err := validateFilePattern("symlink_files", pattern); err != nil {
+			return fmt.Errorf("preset %q: %w", name, err)
+		}
+	}
+	return nil
+}
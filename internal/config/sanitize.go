@@ -0,0 +1,65 @@
+package config
+
+import (
+	"strings"
+	"unicode"
+)
+
+// DefaultSanitizeChars are the characters, besides whitespace, that
+// Sanitize.Apply replaces with "-" when Chars is unset: path separators and
+// the characters Windows, shells or URLs trip over.
+const DefaultSanitizeChars = `/\:*?"<>|#`
+
+// Sanitize configures how a branch or worktree name becomes a directory
+// name, which is also the value of {{ branch | sanitize }}.
+type Sanitize struct {
+	// Chars lists the characters replaced with "-". Empty means
+	// DefaultSanitizeChars. / and \ are always replaced, and whitespace
+	// always is too.
+	Chars string `json:"chars,omitempty" toml:"chars,omitempty"`
+	// Lowercase folds names to lower case, so on a case-insensitive
+	// filesystem Feature/X and feature/x map to the same, visibly
+	// colliding, directory instead of depending on the filesystem.
+	Lowercase bool `json:"lowercase,omitempty" toml:"lowercase,omitempty"`
+}
+
+// Apply turns name into a directory name following s. Worktree paths,
+// worktree_dir templates and {{ branch | sanitize }} in hooks and env
+// templates all go through it, so they agree.
+func (s Sanitize) Apply(name string) string {
+	chars := s.Chars
+	if chars == "" {
+		chars = DefaultSanitizeChars
+	}
+	chars += `/\`
+	if s.Lowercase {
+		name = strings.ToLower(name)
+	}
+	return strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || strings.ContainsRune(chars, r) {
+			return '-'
+		}
+		return r
+	}, name)
+}
+
+// SanitizeDB turns a string (typically a branch name) into a safe SQL/database
+// identifier, the value of {{ branch | sanitize_db }}: lowercased, every
+// non-alphanumeric rune replaced with an underscore, and a leading underscore
+// prepended when the result would start with a digit (many databases reject
+// identifiers that start with a digit).
+func SanitizeDB(s string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(s) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+	result := b.String()
+	if result != "" && result[0] >= '0' && result[0] <= '9' {
+		result = "_" + result
+	}
+	return result
+}
@@ -0,0 +1,53 @@
+package config
+
+import "testing"
+
+func TestSanitizeApply(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules Sanitize
+		in    string
+		want  string
+	}{
+		{"slash", Sanitize{}, "feature/auth", "feature-auth"},
+		{"nested slashes", Sanitize{}, "user/feat/x", "user-feat-x"},
+		{"backslash", Sanitize{}, `feat\x`, "feat-x"},
+		{"colon and hash", Sanitize{}, "fix:issue#42", "fix-issue-42"},
+		{"whitespace", Sanitize{}, "my feature\tbranch", "my-feature-branch"},
+		{"windows reserved", Sanitize{}, `a*b?c"d<e>f|g`, "a-b-c-d-e-f-g"},
+		{"unicode kept", Sanitize{}, "feat/æøå", "feat-æøå"},
+		{"case kept by default", Sanitize{}, "Feature/Auth", "Feature-Auth"},
+		{"lowercase", Sanitize{Lowercase: true}, "Feature/Auth", "feature-auth"},
+		{"custom chars", Sanitize{Chars: "."}, "release/1.2:x", "release-1-2:x"},
+		{"custom chars keep slash", Sanitize{Chars: "#"}, "a/b#c", "a-b-c"},
+		{"empty", Sanitize{}, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.rules.Apply(tt.in); got != tt.want {
+				t.Errorf("%+v.Apply(%q) = %q, want %q", tt.rules, tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSanitizeDB(t *testing.T) {
+	tests := []struct {
+		branch   string
+		expected string
+	}{
+		{"main", "main"},
+		{"feat/Foo-Bar", "feat_foo_bar"},
+		{"feature/JIRA-42", "feature_jira_42"},
+		{"123-start", "_123_start"},
+		{"UPPER", "upper"},
+		{"", ""},
+		{"a.b.c", "a_b_c"},
+	}
+	for _, tt := range tests {
+		got := SanitizeDB(tt.branch)
+		if got != tt.expected {
+			t.Errorf("SanitizeDB(%q) = %q, want %q", tt.branch, got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+)
+
+// ConfigSource names the layer a setting came from.
+type ConfigSource string
+
+const (
+	SourceDefault ConfigSource = "default" // gren's built-in default
+	SourceUser    ConfigSource = "user"    // the user config's [defaults], [commit-generation] or [hooks]
+	SourceProject ConfigSource = "project" // .gren/config.toml (or config.json)
+	SourceLocal   ConfigSource = "local"   // .gren/config.local.toml (or config.local.json)
+)
+
+// Setting is one effective configuration value, keyed as in config.toml
+// with tables dotted ("hooks.post-create", "commit-generation.model").
+type Setting struct {
+	Key    string
+	Value  string
+	Source ConfigSource
+}
+
+// sourceLayer is one layer's contribution to a loaded Config.
+type sourceLayer struct {
+	config *Config
+	source ConfigSource
+}
+
+// Settings returns every setting with a value, sorted by key, each with the
+// layer it came from.
+func (c *Config) Settings() []Setting {
+	sources := make(map[string]ConfigSource)
+	for _, layer := range c.layers {
+		for key := range settingValues(layer.config) {
+			sources[key] = layer.source
+		}
+	}
+
+	values := settingValues(c)
+	settings := make([]Setting, 0, len(values))
+	for key, value := range values {
+		source, ok := sources[key]
+		if !ok {
+			source = SourceDefault
+		}
+		settings = append(settings, Setting{Key: key, Value: value, Source: source})
+	}
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings
+}
+
+// addLayer records that layer was applied on top of the layers before it.
+// Where each setting came from is only worked out when Settings asks.
+func (c *Config) addLayer(layer *Config, source ConfigSource) {
+	c.layers = append(c.layers, sourceLayer{layer, source})
+}
+
+// settingValues flattens c into dotted keys and display values, leaving out
+// unset (zero) values. It goes through the TOML encoding so keys match
+// config.toml whatever format the files are in.
+func settingValues(c *Config) map[string]string {
+	values := make(map[string]string)
+	data, err := toml.Marshal(c)
+	if err != nil {
+		return values
+	}
+	var raw map[string]any
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return values
+	}
+	flattenSettings("", raw, values)
+	return values
+}
+
+func flattenSettings(prefix string, raw map[string]any, values map[string]string) {
+	for key, value := range raw {
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+		switch v := value.(type) {
+		case map[string]any:
+			flattenSettings(key, v, values)
+		case []any:
+			if len(v) > 0 {
+				values[key] = formatList(v)
+			}
+		case string:
+			if v != "" {
+				values[key] = v
+			}
+		case bool:
+			// omitempty drops false bools, so a false here is an optional
+			// setting turned off on purpose (github = false)
+			values[key] = strconv.FormatBool(v)
+		default:
+			if s := fmt.Sprint(v); s != "0" {
+				values[key] = s
+			}
+		}
+	}
+}
+
+// formatList shows a list of strings as a TOML array, and a list of tables
+// (named hooks) by their names.
+func formatList(list []any) string {
+	items := make([]string, 0, len(list))
+	for _, item := range list {
+		switch v := item.(type) {
+		case string:
+			items = append(items, fmt.Sprintf("%q", v))
+		case map[string]any:
+			items = append(items, fmt.Sprint(v["name"]))
+		default:
+			items = append(items, fmt.Sprint(v))
+		}
+	}
+	return "[" + strings.Join(items, ", ") + "]"
+}
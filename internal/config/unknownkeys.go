@@ -0,0 +1,148 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/langtind/gren/internal/logging"
+	"github.com/pelletier/go-toml/v2"
+)
+
+// checkedKeys remembers keyWarnings' result per config file, so a file is
+// only checked, and its warnings logged, once until it changes.
+var checkedKeys = struct {
+	sync.Mutex
+	files map[string]checkedFile
+}{files: make(map[string]checkedFile)}
+
+type checkedFile struct {
+	modTime  time.Time
+	size     int64
+	warnings []string
+}
+
+// keyWarnings decodes the config file at path, holding data in the given
+// format ("toml" or "json"), generically and returns unknownKeyWarnings for
+// it. They are logged the first time the file is seen as it is now.
+func keyWarnings(data []byte, format, path string) []string {
+	info, statErr := os.Stat(path)
+	if statErr == nil {
+		checkedKeys.Lock()
+		checked, ok := checkedKeys.files[path]
+		checkedKeys.Unlock()
+		if ok && checked.modTime.Equal(info.ModTime()) && checked.size == info.Size() {
+			return slices.Clone(checked.warnings)
+		}
+	}
+
+	var raw map[string]any
+	var err error
+	if format == "toml" {
+		err = toml.Unmarshal(data, &raw)
+	} else {
+		err = json.Unmarshal(data, &raw)
+	}
+	if err != nil {
+		return nil
+	}
+	warnings := unknownKeyWarnings(raw, format, path)
+	for _, warning := range warnings {
+		logging.Warn("%s", warning)
+	}
+	if statErr == nil {
+		checkedKeys.Lock()
+		checkedKeys.files[path] = checkedFile{info.ModTime(), info.Size(), warnings}
+		checkedKeys.Unlock()
+	}
+	return warnings
+}
+
+// unknownKeyWarnings returns a warning for each top-level key in raw, a
+// config file decoded into a map, that Config has no field for. Such keys
+// are otherwise dropped silently, so a typo like "worktreedir" leaves the
+// setting without effect and no hint why. tag is the struct tag the file's
+// format uses ("toml" or "json").
+func unknownKeyWarnings(raw map[string]any, tag, path string) []string {
+	known := knownKeys(tag)
+
+	var unknown []string
+	for key := range raw {
+		// Both decoders match field names case-insensitively
+		if !known[strings.ToLower(key)] {
+			unknown = append(unknown, key)
+		}
+	}
+	sort.Strings(unknown)
+
+	warnings := make([]string, 0, len(unknown))
+	for _, key := range unknown {
+		warning := fmt.Sprintf("unknown config key %q in %s", key, path)
+		if suggestion := closestKey(key, known); suggestion != "" {
+			warning += fmt.Sprintf(" (did you mean %q?)", suggestion)
+		}
+		warnings = append(warnings, warning)
+	}
+	return warnings
+}
+
+// knownKeys returns the top-level keys Config accepts under the given
+// struct tag, in lower case.
+func knownKeys(tag string) map[string]bool {
+	known := make(map[string]bool)
+	t := reflect.TypeOf(Config{})
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get(tag), ",")
+		if name != "" && name != "-" {
+			known[strings.ToLower(name)] = true
+		}
+	}
+	return known
+}
+
+// closestKey returns the known key that key most likely misspells: one that
+// only differs in case, '-' versus '_', or by at most two edits. It returns
+// "" when nothing is that close.
+func closestKey(key string, known map[string]bool) string {
+	normalize := func(s string) string {
+		return strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(s))
+	}
+
+	best, bestDistance := "", 3
+	for candidate := range known {
+		if normalize(candidate) == normalize(key) {
+			return candidate
+		}
+		if d := editDistance(key, candidate); d < bestDistance || (d == bestDistance && candidate < best) {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
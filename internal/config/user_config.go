@@ -1,10 +1,13 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"os/user"
 	"path/filepath"
 	"runtime"
+	"strings"
 
 	"github.com/pelletier/go-toml/v2"
 )
@@ -26,8 +29,9 @@ type UserConfig struct {
 
 // UserDefaults contains default settings for worktree operations.
 type UserDefaults struct {
-	// WorktreeDir template for worktree directory names
-	// Supports {{ repo }} template variable
+	// WorktreeDir is the worktree_dir for projects that don't set one, and
+	// the one `gren init` writes. Supports {{ repo }} and a leading ~, e.g.
+	// "~/worktrees/{{ repo }}".
 	WorktreeDir string `toml:"worktree-dir,omitempty"`
 
 	// RemoveAfterMerge controls whether worktrees are removed after merge
@@ -38,6 +42,12 @@ type UserDefaults struct {
 
 	// RebaseOnMerge controls whether to rebase before merge
 	RebaseOnMerge bool `toml:"rebase-on-merge,omitempty"`
+
+	// TerminalCommand is the default terminal launcher (see Config.TerminalCommand)
+	TerminalCommand string `toml:"terminal-command,omitempty"`
+
+	// Editor is the default editor for config and hook files
+	Editor string `toml:"editor,omitempty"`
 }
 
 // NamedHooksConfig holds named hooks organized by lifecycle event.
@@ -75,28 +85,79 @@ func NewUserConfigManager() *UserConfigManager {
 	}
 }
 
-// getUserConfigPath returns the platform-specific path for user config.
-func getUserConfigPath() string {
-	var configDir string
+// ErrNoUserConfigDir is returned when writing user-level files while the
+// user config directory cannot be determined.
+var ErrNoUserConfigDir = errors.New("cannot determine the user config directory: set HOME (or XDG_CONFIG_HOME)")
+
+// HomeDir returns the user's home directory. When $HOME is unset, as in some
+// containers and CI runners, it falls back to the user database.
+func HomeDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err == nil {
+		return home, nil
+	}
+	if u, uerr := user.Current(); uerr == nil && u.HomeDir != "" {
+		return u.HomeDir, nil
+	}
+	return "", err
+}
+
+// ExpandHome replaces a leading ~ in path with the home directory, so
+// worktree-dir = "~/worktrees/{{ repo }}" works. Other paths, and ~ when the
+// home directory is unknown, are returned unchanged.
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") && !strings.HasPrefix(path, "~"+string(filepath.Separator)) {
+		return path
+	}
+	home, err := HomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, path[1:])
+}
+
+// userConfigDir returns the platform-specific directory for gren's user
+// files, or "" when it cannot be determined. Joining a missing home onto a
+// relative path would otherwise read and write files in the current
+// directory.
+func userConfigDir() string {
 	switch runtime.GOOS {
-	case "darwin":
-		home, _ := os.UserHomeDir()
-		configDir = filepath.Join(home, "Library", "Application Support", "gren")
 	case "windows":
-		configDir = filepath.Join(os.Getenv("APPDATA"), "gren")
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gren")
+		}
+		return ""
+	case "darwin":
+		if home, err := HomeDir(); err == nil {
+			return filepath.Join(home, "Library", "Application Support", "gren")
+		}
+		return ""
 	default: // linux and others
 		if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
-			configDir = filepath.Join(xdgConfig, "gren")
-		} else {
-			home, _ := os.UserHomeDir()
-			configDir = filepath.Join(home, ".config", "gren")
+			return filepath.Join(xdgConfig, "gren")
+		}
+		if home, err := HomeDir(); err == nil {
+			return filepath.Join(home, ".config", "gren")
 		}
+		return ""
+	}
+}
+
+// getUserConfigPath returns the platform-specific path for user config, or
+// "" when there is no user config directory.
+func getUserConfigPath() string {
+	configDir := userConfigDir()
+	if configDir == "" {
+		return ""
 	}
 	return filepath.Join(configDir, "config.toml")
 }
 
 // Load reads the user configuration from disk.
 func (ucm *UserConfigManager) Load() (*UserConfig, error) {
+	if ucm.configPath == "" {
+		return &UserConfig{}, nil
+	}
 	data, err := os.ReadFile(ucm.configPath)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -116,6 +177,9 @@ func (ucm *UserConfigManager) Load() (*UserConfig, error) {
 
 // Save writes the user configuration to disk.
 func (ucm *UserConfigManager) Save(config *UserConfig) error {
+	if ucm.configPath == "" {
+		return ErrNoUserConfigDir
+	}
 	// Ensure directory exists
 	dir := filepath.Dir(ucm.configPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -131,16 +195,20 @@ func (ucm *UserConfigManager) Save(config *UserConfig) error {
 	header := []byte("# gren global user configuration\n# See https://github.com/langtind/gren for documentation\n\n")
 	data = append(header, data...)
 
-	return os.WriteFile(ucm.configPath, data, 0644)
+	return WriteFileAtomic(ucm.configPath, data, privateFileMode)
 }
 
 // Exists checks if user config file exists.
 func (ucm *UserConfigManager) Exists() bool {
+	if ucm.configPath == "" {
+		return false
+	}
 	_, err := os.Stat(ucm.configPath)
 	return err == nil
 }
 
-// ConfigPath returns the path to the user config file.
+// ConfigPath returns the path to the user config file, or "" when the user
+// config directory cannot be determined.
 func (ucm *UserConfigManager) ConfigPath() string {
 	return ucm.configPath
 }
@@ -161,8 +229,15 @@ func MergeConfigs(user *UserConfig, project *Config) *Config {
 		project.WorktreeDir = user.Defaults.WorktreeDir
 	}
 
+	if project.TerminalCommand == "" {
+		project.TerminalCommand = user.Defaults.TerminalCommand
+	}
+	if project.Editor == "" {
+		project.Editor = user.Defaults.Editor
+	}
+
 	// Merge commit generator (project takes precedence)
-	if project.CommitGenerator.Command == "" && user.CommitGenerator.Command != "" {
+	if !project.CommitGenerator.Configured() && user.CommitGenerator.Configured() {
 		project.CommitGenerator = user.CommitGenerator
 	}
 
@@ -30,6 +30,28 @@ func TestUserConfigManagerConfigPath(t *testing.T) {
 	}
 }
 
+func TestUserConfigPathWithoutHome(t *testing.T) {
+	t.Setenv("HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	// Falls back to the user database instead of a path relative to the
+	// current directory
+	if path := getUserConfigPath(); path != "" && !filepath.IsAbs(path) {
+		t.Errorf("getUserConfigPath() = %q with HOME unset, want an absolute path or none", path)
+	}
+
+	manager := &UserConfigManager{}
+	if config, err := manager.Load(); err != nil || config == nil {
+		t.Errorf("Load() without a config path = %v, %v; want an empty config", config, err)
+	}
+	if manager.Exists() {
+		t.Error("Exists() without a config path = true")
+	}
+	if err := manager.Save(&UserConfig{}); err != ErrNoUserConfigDir {
+		t.Errorf("Save() without a config path = %v, want ErrNoUserConfigDir", err)
+	}
+}
+
 func TestUserConfigManagerLoad_MissingFile(t *testing.T) {
 	tempDir, err := os.MkdirTemp("", "gren-user-config-test-*")
 	if err != nil {
@@ -192,7 +214,9 @@ func TestUserConfigManagerExists(t *testing.T) {
 func TestMergeConfigs(t *testing.T) {
 	userConfig := &UserConfig{
 		Defaults: UserDefaults{
-			WorktreeDir: "../user-worktrees",
+			WorktreeDir:     "../user-worktrees",
+			TerminalCommand: "kitty --directory {{path}}",
+			Editor:          "vim",
 		},
 		CommitGenerator: CommitGenerator{
 			Command: "llm",
@@ -206,6 +230,7 @@ func TestMergeConfigs(t *testing.T) {
 
 	projectConfig := &Config{
 		WorktreeDir: "", // Empty, should use user default
+		Editor:      "hx",
 		Hooks: Hooks{
 			PostCreate: "project-post-create", // Overrides user
 			PreRemove:  "project-pre-remove",  // Project-only
@@ -238,6 +263,14 @@ func TestMergeConfigs(t *testing.T) {
 	if merged.CommitGenerator.Command != "llm" {
 		t.Errorf("CommitGenerator.Command = %q, want %q", merged.CommitGenerator.Command, "llm")
 	}
+
+	// Launcher defaults apply unless the project sets its own
+	if merged.TerminalCommand != "kitty --directory {{path}}" {
+		t.Errorf("TerminalCommand = %q, want user default", merged.TerminalCommand)
+	}
+	if merged.Editor != "hx" {
+		t.Errorf("Editor = %q, want %q", merged.Editor, "hx")
+	}
 }
 
 func TestMergeConfigs_NilUserConfig(t *testing.T) {
@@ -431,3 +464,24 @@ func TestUserConfigTOMLRoundTrip(t *testing.T) {
 		t.Errorf("CommitGenerator.Command = %q, want %q", loaded.CommitGenerator.Command, original.CommitGenerator.Command)
 	}
 }
+
+func TestExpandHome(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"~", home},
+		{"~/worktrees/{{ repo }}", filepath.Join(home, "worktrees", "{{ repo }}")},
+		{"../{{ repo }}-worktrees", "../{{ repo }}-worktrees"},
+		{"/abs/worktrees", "/abs/worktrees"},
+		{"~other/worktrees", "~other/worktrees"},
+	}
+	for _, tt := range tests {
+		if got := ExpandHome(tt.path); got != tt.want {
+			t.Errorf("ExpandHome(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
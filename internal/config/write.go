@@ -0,0 +1,69 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// Permissions for config files. Files that stay on this machine (the user
+// config, approvals, local overrides) may hold tokens in commands and are
+// private to the user; the project config is meant to be committed.
+const (
+	sharedFileMode  os.FileMode = 0644
+	privateFileMode os.FileMode = 0600
+)
+
+// WriteFileAtomic writes data to path so that readers, and a crash partway
+// through, see either the old file or the new one, never a truncated mix:
+// the data goes to a temporary file in the same directory, which is synced
+// and then renamed over path. If path is a symlink, the file it points to
+// is replaced and the link is kept. The file gets perm regardless of the umask.
+func WriteFileAtomic(path string, data []byte, perm os.FileMode) error {
+	return writeAtomic(path, perm, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
+
+// writeAtomic is WriteFileAtomic with the contents produced by write.
+func writeAtomic(path string, perm os.FileMode, write func(io.Writer) error) error {
+	// Renaming over a symlink would replace the link itself, breaking
+	// dotfiles setups that link the config into a repository
+	target := path
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		target = resolved
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file for %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			tmp.Close()
+			os.Remove(tmpPath)
+		}
+	}()
+
+	if err := write(tmp); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Chmod(perm); err != nil {
+		return fmt.Errorf("failed to set permissions on %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, target); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	committed = true
+	return nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteFileAtomic(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFileTOML)
+	if err := os.WriteFile(path, []byte("worktree_dir = \"../old\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(path, []byte("worktree_dir = \"../new\"\n"), privateFileMode); err != nil {
+		t.Fatalf("WriteFileAtomic() error: %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "worktree_dir = \"../new\"\n" {
+		t.Errorf("contents = %q, want the new config", data)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != privateFileMode {
+		t.Errorf("mode = %v, want %v", info.Mode().Perm(), privateFileMode)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func TestWriteFileAtomicKeepsSymlink(t *testing.T) {
+	dir := t.TempDir()
+	dotfiles := filepath.Join(dir, "dotfiles")
+	if err := os.Mkdir(dotfiles, 0755); err != nil {
+		t.Fatal(err)
+	}
+	target := filepath.Join(dotfiles, "config.toml")
+	if err := os.WriteFile(target, []byte("worktree_dir = \"../old\"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "config.toml")
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := WriteFileAtomic(link, []byte("worktree_dir = \"../new\"\n"), privateFileMode); err != nil {
+		t.Fatalf("WriteFileAtomic() error: %v", err)
+	}
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("config is no longer a symlink after saving (%v)", err)
+	}
+	if data, _ := os.ReadFile(target); string(data) != "worktree_dir = \"../new\"\n" {
+		t.Errorf("link target = %q, want the new config", data)
+	}
+	assertNoTempFiles(t, dir)
+	assertNoTempFiles(t, dotfiles)
+}
+
+func TestWriteAtomicFailureKeepsOriginal(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ConfigFileJSON)
+	original := `{"worktree_dir": "../old", "version": "1.0.0"}`
+	if err := os.WriteFile(path, []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fail halfway through, as a full disk or a crash would
+	err := writeAtomic(path, sharedFileMode, func(w io.Writer) error {
+		w.Write([]byte(`{"worktree_dir": "../ne`))
+		return errors.New("no space left on device")
+	})
+	if err == nil {
+		t.Fatal("writeAtomic() succeeded, want the write error")
+	}
+
+	if data, _ := os.ReadFile(path); string(data) != original {
+		t.Errorf("config = %q after a failed write, want it untouched", data)
+	}
+	assertNoTempFiles(t, dir)
+
+	manager := NewManager()
+	manager.configDir = dir
+	if _, err := manager.Load(); err != nil {
+		t.Errorf("Load() after a failed write error: %v", err)
+	}
+}
+
+func TestSavePermissions(t *testing.T) {
+	dir := t.TempDir()
+	manager := NewManager()
+	manager.configDir = dir
+	cfg, err := NewDefaultConfig("test", dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.Save(cfg); err != nil {
+		t.Fatalf("Save() error: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(dir, ConfigFileTOML)); err != nil || info.Mode().Perm() != sharedFileMode {
+		t.Errorf("project config mode = %v (%v), want %v", info.Mode().Perm(), err, sharedFileMode)
+	}
+
+	ucm := &UserConfigManager{configPath: filepath.Join(dir, "user", "config.toml")}
+	if err := ucm.Save(&UserConfig{}); err != nil {
+		t.Fatalf("UserConfigManager.Save() error: %v", err)
+	}
+	if info, _ := os.Stat(ucm.configPath); info.Mode().Perm() != privateFileMode {
+		t.Errorf("user config mode = %v, want %v", info.Mode().Perm(), privateFileMode)
+	}
+	assertNoTempFiles(t, dir)
+}
+
+func assertNoTempFiles(t *testing.T, dir string) {
+	t.Helper()
+	matches, _ := filepath.Glob(filepath.Join(dir, ".*.tmp-*"))
+	if len(matches) > 0 {
+		t.Errorf("temporary files left behind: %v", matches)
+	}
+}
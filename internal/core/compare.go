@@ -45,56 +45,46 @@ type FileChange struct {
 // CompareResult contains the result of comparing two worktrees
 type CompareResult struct {
 	SourceWorktree string       // Name of the source worktree (with changes)
-	TargetWorktree string       // Name of the target worktree (current)
+	TargetWorktree string       // Name of the target worktree (current unless given)
+	SourcePath     string       // Absolute path of the source worktree
+	TargetPath     string       // Absolute path of the target worktree
 	Files          []FileChange // List of changed files
 }
 
 // CompareWorktrees compares changes between a source worktree and the current worktree
 // Returns files that exist in source but differ from (or don't exist in) the current worktree
 func (wm *WorktreeManager) CompareWorktrees(ctx context.Context, sourceWorktree string) (*CompareResult, error) {
-	logging.Debug("CompareWorktrees: comparing %s to current worktree", sourceWorktree)
-
-	// Get all worktrees
-	worktrees, err := wm.ListWorktrees(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list worktrees: %w", err)
-	}
-
-	// Find source and current worktree
-	var sourcePath, currentPath, currentName string
-	for _, wt := range worktrees {
-		if wt.Name == sourceWorktree || wt.Path == sourceWorktree {
-			sourcePath = wt.Path
-		}
-		if wt.IsCurrent {
-			currentPath = wt.Path
-			currentName = wt.Name
-		}
-	}
+	return wm.CompareWorktreePair(ctx, sourceWorktree, "")
+}
 
-	if sourcePath == "" {
-		return nil, fmt.Errorf("worktree '%s' not found", sourceWorktree)
-	}
+// CompareWorktreePair compares a source worktree against an explicit target
+// worktree (name or path); an empty target means the current worktree. This
+// lets `gren compare a b` diff two feature worktrees from anywhere.
+func (wm *WorktreeManager) CompareWorktreePair(ctx context.Context, sourceWorktree, targetWorktree string) (*CompareResult, error) {
+	logging.Debug("CompareWorktreePair: comparing %s to %q (empty = current)", sourceWorktree, targetWorktree)
 
-	if sourcePath == currentPath {
-		return nil, fmt.Errorf("cannot compare worktree to itself")
+	source, target, err := wm.resolveComparePair(ctx, sourceWorktree, targetWorktree)
+	if err != nil {
+		return nil, err
 	}
 
 	result := &CompareResult{
-		SourceWorktree: sourceWorktree,
-		TargetWorktree: currentName,
+		SourceWorktree: source.Name,
+		TargetWorktree: target.Name,
+		SourcePath:     source.Path,
+		TargetPath:     target.Path,
 		Files:          []FileChange{},
 	}
 
 	// Get uncommitted changes in source worktree
-	uncommittedChanges, err := wm.getUncommittedChanges(sourcePath)
+	uncommittedChanges, err := wm.getUncommittedChanges(source.Path)
 	if err != nil {
 		logging.Warn("failed to get uncommitted changes: %v", err)
 	}
 	result.Files = append(result.Files, uncommittedChanges...)
 
 	// Get committed changes (diff between branches)
-	committedChanges, err := wm.getCommittedChanges(sourcePath, currentPath)
+	committedChanges, err := wm.getCommittedChanges(source.Path, target.Path)
 	if err != nil {
 		logging.Warn("failed to get committed changes: %v", err)
 	}
@@ -103,10 +93,51 @@ func (wm *WorktreeManager) CompareWorktrees(ctx context.Context, sourceWorktree
 	// Deduplicate files (uncommitted changes take precedence)
 	result.Files = deduplicateFiles(result.Files)
 
-	logging.Info("CompareWorktrees: found %d changed files", len(result.Files))
+	logging.Info("CompareWorktreePair: found %d changed files", len(result.Files))
 	return result, nil
 }
 
+// resolveComparePair looks up the source and target worktrees of a compare.
+// An empty name on either side resolves to the current worktree, which is how
+// `compare --reverse` without a target compares current → source.
+func (wm *WorktreeManager) resolveComparePair(ctx context.Context, sourceWorktree, targetWorktree string) (source, target *WorktreeInfo, err error) {
+	worktrees, err := wm.ListWorktrees(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if sourceWorktree == "" && wt.IsCurrent {
+			source = wt
+		} else if sourceWorktree != "" && source == nil && (wt.Name == sourceWorktree || wt.Path == sourceWorktree) {
+			source = wt
+		}
+		if targetWorktree == "" && wt.IsCurrent {
+			target = wt
+		} else if targetWorktree != "" && target == nil && (wt.Name == targetWorktree || wt.Path == targetWorktree) {
+			target = wt
+		}
+	}
+
+	if source == nil {
+		if sourceWorktree == "" {
+			return nil, nil, fmt.Errorf("current worktree not found")
+		}
+		return nil, nil, fmt.Errorf("worktree '%s' not found", sourceWorktree)
+	}
+	if target == nil {
+		if targetWorktree == "" {
+			return nil, nil, fmt.Errorf("current worktree not found")
+		}
+		return nil, nil, fmt.Errorf("worktree '%s' not found", targetWorktree)
+	}
+	if source.Path == target.Path {
+		return nil, nil, fmt.Errorf("cannot compare worktree to itself")
+	}
+	return source, target, nil
+}
+
 // getUncommittedChanges returns uncommitted changes in a worktree
 func (wm *WorktreeManager) getUncommittedChanges(worktreePath string) ([]FileChange, error) {
 	cmd := exec.Command("git", "-C", worktreePath, "status", "--porcelain")
@@ -255,6 +286,12 @@ func validatePath(path string) error {
 
 // ApplyChanges applies selected file changes from source worktree to current worktree
 func (wm *WorktreeManager) ApplyChanges(ctx context.Context, sourceWorktree string, files []FileChange) error {
+	return wm.ApplyChangesTo(ctx, sourceWorktree, "", files)
+}
+
+// ApplyChangesTo applies selected file changes from the source worktree to
+// the target worktree; an empty target means the current worktree.
+func (wm *WorktreeManager) ApplyChangesTo(ctx context.Context, sourceWorktree, targetWorktree string, files []FileChange) error {
 	if len(files) == 0 {
 		return nil
 	}
@@ -268,26 +305,11 @@ func (wm *WorktreeManager) ApplyChanges(ctx context.Context, sourceWorktree stri
 
 	logging.Info("ApplyChanges: applying %d files from %s", len(files), sourceWorktree)
 
-	// Get all worktrees
-	worktrees, err := wm.ListWorktrees(ctx)
+	source, target, err := wm.resolveComparePair(ctx, sourceWorktree, targetWorktree)
 	if err != nil {
-		return fmt.Errorf("failed to list worktrees: %w", err)
-	}
-
-	// Find source and current worktree paths
-	var sourcePath, currentPath string
-	for _, wt := range worktrees {
-		if wt.Name == sourceWorktree || wt.Path == sourceWorktree {
-			sourcePath = wt.Path
-		}
-		if wt.IsCurrent {
-			currentPath = wt.Path
-		}
-	}
-
-	if sourcePath == "" {
-		return fmt.Errorf("worktree '%s' not found", sourceWorktree)
+		return err
 	}
+	sourcePath, currentPath := source.Path, target.Path
 
 	// Apply each file change
 	for _, file := range files {
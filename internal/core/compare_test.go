@@ -896,3 +896,66 @@ func TestCopyFileErrors(t *testing.T) {
 		}
 	})
 }
+
+func TestCompareWorktreePair(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	pathA, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "pair-a", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+	pathB, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "pair-b", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("failed to create worktree: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(pathA, "only-in-a.txt"), []byte("a"), 0644)
+	runGit(t, pathA, "add", "only-in-a.txt")
+	runGit(t, pathA, "commit", "-m", "Add only-in-a")
+
+	t.Run("compares two non-current worktrees", func(t *testing.T) {
+		result, err := manager.CompareWorktreePair(ctx, "pair-a", "pair-b")
+		if err != nil {
+			t.Fatalf("CompareWorktreePair() error: %v", err)
+		}
+		if result.TargetWorktree != "pair-b" || result.TargetPath != pathB || result.SourcePath != pathA {
+			t.Errorf("result target = %q (%s), source path %s; want pair-b (%s), %s",
+				result.TargetWorktree, result.TargetPath, result.SourcePath, pathB, pathA)
+		}
+		if len(result.Files) != 1 || result.Files[0].Path != "only-in-a.txt" {
+			t.Errorf("Files = %+v, want only-in-a.txt", result.Files)
+		}
+	})
+
+	t.Run("empty source means current worktree", func(t *testing.T) {
+		result, err := manager.CompareWorktreePair(ctx, "", "pair-a")
+		if err != nil {
+			t.Fatalf("CompareWorktreePair() error: %v", err)
+		}
+		if result.SourcePath != dir {
+			t.Errorf("SourcePath = %q, want current worktree %q", result.SourcePath, dir)
+		}
+	})
+
+	t.Run("applies into the given target", func(t *testing.T) {
+		err := manager.ApplyChangesTo(ctx, "pair-a", "pair-b", []FileChange{{Path: "only-in-a.txt", Status: FileAdded}})
+		if err != nil {
+			t.Fatalf("ApplyChangesTo() error: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(pathB, "only-in-a.txt")); err != nil {
+			t.Errorf("file not applied to target: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, "only-in-a.txt")); err == nil {
+			t.Error("file was applied to the current worktree instead of the target")
+		}
+	})
+
+	t.Run("rejects identical sides", func(t *testing.T) {
+		if _, err := manager.CompareWorktreePair(ctx, "pair-a", "pair-a"); err == nil {
+			t.Error("CompareWorktreePair(a, a) = nil error, want error")
+		}
+	})
+}
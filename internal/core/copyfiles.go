@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/logging"
+)
+
+// copyConfiguredFiles copies the files and directories matching the
+// configured copy_files globs from the main worktree into the new worktree,
+// giving it an independent copy where the post-create hook would symlink a
+// shared one. Files that already exist in the worktree (tracked files, or
+// ones copied before) are skipped, so copying again is harmless. It returns
+// the worktree-relative paths of the files it copied.
+func (wm *WorktreeManager) copyConfiguredFiles(cfg *config.Config, worktreePath string) ([]string, error) {
+	if len(cfg.CopyFiles) == 0 {
+		return nil, nil
+	}
+
+	repoRoot, err := wm.getRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	var copied []string
+	for _, pattern := range cfg.CopyFiles {
+		matches, err := filepath.Glob(filepath.Join(repoRoot, pattern))
+		if err != nil {
+			return copied, fmt.Errorf("invalid copy_files pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			logging.Debug("copy_files: %q matches nothing in %s", pattern, repoRoot)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if filepath.Base(match) == ".git" {
+				continue
+			}
+			files, err := copyMissing(repoRoot, match, worktreePath)
+			copied = append(copied, files...)
+			if err != nil {
+				return copied, err
+			}
+		}
+	}
+
+	if len(copied) > 0 {
+		logging.Info("copy_files: copied %d file(s) into %s", len(copied), worktreePath)
+	}
+	return copied, nil
+}
+
+// copyMissing copies src, a file or directory under repoRoot, to the same
+// relative path under worktreePath, skipping files that already exist there.
+func copyMissing(repoRoot, src, worktreePath string) ([]string, error) {
+	var copied []string
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			// A worktree nested in the main one is not part of its files
+			if path != src && isGitDir(path) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		relPath, err := filepath.Rel(repoRoot, path)
+		if err != nil {
+			return err
+		}
+		dst := filepath.Join(worktreePath, relPath)
+		if _, err := os.Lstat(dst); err == nil {
+			logging.Debug("copy_files: %s already exists in the worktree, skipping", relPath)
+			return nil
+		}
+		if err := copyFile(path, dst); err != nil {
+			return fmt.Errorf("failed to copy %s: %w", relPath, err)
+		}
+		copied = append(copied, relPath)
+		return nil
+	})
+	return copied, err
+}
+
+// isGitDir reports whether dir is the top of a git checkout (a .git file or
+// directory inside it).
+func isGitDir(dir string) bool {
+	_, err := os.Lstat(filepath.Join(dir, ".git"))
+	return err == nil
+}
+
+// symlinkConfiguredFiles links the files and directories matching a
+// preset's symlink_files globs from the main worktree into the new
+// worktree, at the same relative path, so the worktree shares them.
+// Matches are linked whole; paths that already exist in the worktree are
+// skipped, so linking again is harmless. It returns the worktree-relative
+// paths it linked.
+func (wm *WorktreeManager) symlinkConfiguredFiles(patterns []string, worktreePath string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+
+	repoRoot, err := wm.getRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	var linked []string
+	for _, pattern := range patterns {
+		matches, err := filepath.Glob(filepath.Join(repoRoot, pattern))
+		if err != nil {
+			return linked, fmt.Errorf("invalid symlink_files pattern %q: %w", pattern, err)
+		}
+		if len(matches) == 0 {
+			logging.Debug("symlink_files: %q matches nothing in %s", pattern, repoRoot)
+		}
+		sort.Strings(matches)
+		for _, match := range matches {
+			if filepath.Base(match) == ".git" {
+				continue
+			}
+			relPath, err := filepath.Rel(repoRoot, match)
+			if err != nil {
+				return linked, err
+			}
+			dst := filepath.Join(worktreePath, relPath)
+			if _, err := os.Lstat(dst); err == nil {
+				logging.Debug("symlink_files: %s already exists in the worktree, skipping", relPath)
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return linked, fmt.Errorf("failed to link %s: %w", relPath, err)
+			}
+			if err := os.Symlink(match, dst); err != nil {
+				return linked, fmt.Errorf("failed to link %s: %w", relPath, err)
+			}
+			linked = append(linked, relPath)
+		}
+	}
+
+	if len(linked) > 0 {
+		logging.Info("symlink_files: linked %d path(s) into %s", len(linked), worktreePath)
+	}
+	return linked, nil
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestCreateWorktreeCopiesFiles(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// Untracked state in the main worktree: a database and a cache directory
+	os.WriteFile(filepath.Join(dir, "dev.sqlite3"), []byte("main db"), 0600)
+	os.MkdirAll(filepath.Join(dir, "cache", "nested"), 0755)
+	os.WriteFile(filepath.Join(dir, "cache", "nested", "entry"), []byte("cached"), 0644)
+	cfg := `{
+		"worktree_dir": "` + filepath.Join(filepath.Dir(dir), "test-worktrees") + `",
+		"version": "1.0.0",
+		"copy_files": ["*.sqlite3", "cache", "README.md", "missing/*"]
+	}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	path, warning, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "copy-test", IsNewBranch: true})
+	if err != nil || warning != "" {
+		t.Fatalf("CreateWorktree() = %q, %v, want no error or warning", warning, err)
+	}
+
+	db := filepath.Join(path, "dev.sqlite3")
+	if info, err := os.Lstat(db); err != nil || !info.Mode().IsRegular() {
+		t.Fatalf("dev.sqlite3 in worktree: %v, %v; want a regular file, not a symlink", info, err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(path, "cache", "nested", "entry")); string(data) != "cached" {
+		t.Errorf("cache/nested/entry = %q, want the directory copied", data)
+	}
+
+	// The copy is independent of the main worktree's file
+	os.WriteFile(db, []byte("worktree db"), 0600)
+	if data, _ := os.ReadFile(filepath.Join(dir, "dev.sqlite3")); string(data) != "main db" {
+		t.Errorf("main dev.sqlite3 = %q after writing the copy, want it unchanged", data)
+	}
+
+	// Copying again leaves existing files, including the tracked README, alone
+	loaded, err := manager.configManager.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	copied, err := manager.copyConfiguredFiles(loaded, path)
+	if err != nil || len(copied) != 0 {
+		t.Errorf("second copyConfiguredFiles() = %v, %v, want nothing copied", copied, err)
+	}
+	if data, _ := os.ReadFile(db); string(data) != "worktree db" {
+		t.Errorf("dev.sqlite3 = %q after copying again, want the worktree's own copy kept", data)
+	}
+
+	os.Remove(db)
+	copied, err = manager.copyConfiguredFiles(loaded, path)
+	if err != nil || !reflect.DeepEqual(copied, []string{"dev.sqlite3"}) {
+		t.Errorf("copyConfiguredFiles() = %v, %v, want only the removed dev.sqlite3 copied", copied, err)
+	}
+}
@@ -0,0 +1,108 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// createdFile is the file in a linked worktree's administrative directory
+// (.git/worktrees/<name>) where CreateWorktree records when it created the
+// worktree. git removes the directory with the worktree and keeps it across
+// `git worktree move`, so the record never outlives or loses its worktree.
+const createdFile = "gren-created"
+
+// createdBranchFile is the file next to createdFile holding the branch the
+// worktree's directory is named for: the one it was created on, or, after
+// gren relocate renamed it, the one it was renamed after.
+const createdBranchFile = "gren-branch"
+
+// recordWorktreeCreated notes that the worktree at worktreePath was created
+// on branch at t. Failing only costs the record, so it is logged, not
+// returned.
+func recordWorktreeCreated(worktreePath, branch string, t time.Time) {
+	adminDir := linkedGitDir(worktreePath)
+	if adminDir == "" {
+		logging.Warn("recordWorktreeCreated: %s has no administrative directory", worktreePath)
+		return
+	}
+	if err := os.WriteFile(filepath.Join(adminDir, createdFile), []byte(t.UTC().Format(time.RFC3339)+"\n"), 0644); err != nil {
+		logging.Warn("recordWorktreeCreated: %v", err)
+	}
+	recordWorktreeBranch(worktreePath, branch)
+}
+
+// recordWorktreeBranch notes that the directory of the worktree at
+// worktreePath is named for branch.
+func recordWorktreeBranch(worktreePath, branch string) {
+	adminDir := linkedGitDir(worktreePath)
+	if adminDir == "" || branch == "" {
+		return
+	}
+	if err := os.WriteFile(filepath.Join(adminDir, createdBranchFile), []byte(branch+"\n"), 0644); err != nil {
+		logging.Warn("recordWorktreeBranch: %v", err)
+	}
+}
+
+// worktreeCreatedBranch returns the branch recordWorktreeBranch noted for
+// the worktree at worktreePath, or "" for worktrees made with `git worktree
+// add` or by an older gren.
+func worktreeCreatedBranch(worktreePath string) string {
+	adminDir := linkedGitDir(worktreePath)
+	if adminDir == "" {
+		return ""
+	}
+	data, err := os.ReadFile(filepath.Join(adminDir, createdBranchFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// worktreeCreated returns when the linked worktree at worktreePath was
+// created: the time CreateWorktree recorded, else, for worktrees made with
+// `git worktree add` or by an older gren, the directory's modification
+// time. It is zero for the main worktree and for a missing directory.
+func worktreeCreated(worktreePath string) time.Time {
+	adminDir := linkedGitDir(worktreePath)
+	if adminDir == "" {
+		return time.Time{}
+	}
+	if data, err := os.ReadFile(filepath.Join(adminDir, createdFile)); err == nil {
+		if t, err := time.Parse(time.RFC3339, strings.TrimSpace(string(data))); err == nil {
+			return t
+		}
+	}
+	info, err := os.Stat(worktreePath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+var ageRe = regexp.MustCompile(`^(\d+)([hdw])$`)
+
+// ParseCreatedBefore parses a `--created-before` value relative to now: a
+// date (2006-01-02, local time), an RFC 3339 timestamp, or an age such as
+// 12h, 30d or 2w meaning that long before now.
+func ParseCreatedBefore(value string, now time.Time) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if m := ageRe.FindStringSubmatch(value); m != nil {
+		n, _ := strconv.Atoi(m[1])
+		unit := map[string]time.Duration{"h": time.Hour, "d": 24 * time.Hour, "w": 7 * 24 * time.Hour}[m[2]]
+		return now.Add(-time.Duration(n) * unit), nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", value, now.Location()); err == nil {
+		return t, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD, an RFC 3339 time or an age such as 30d, 2w or 12h", value)
+}
@@ -0,0 +1,79 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestWorktreeCreated(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	before := time.Now().Add(-time.Second)
+	path, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "made-by-gren", Branch: "made-by-gren", BaseBranch: "main", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	// The record, not the directory's mtime, decides
+	old := time.Now().Add(-72 * time.Hour)
+	os.Chtimes(path, old, old)
+	if created := worktreeCreated(path); created.Before(before) || created.After(time.Now()) {
+		t.Errorf("created = %v, want the time CreateWorktree ran", created)
+	}
+
+	// The record moves with the worktree
+	moved := filepath.Join(filepath.Dir(dir), "test-worktrees", "moved")
+	if _, err := manager.MoveWorktree(ctx, "made-by-gren", moved); err != nil {
+		t.Fatalf("MoveWorktree() error: %v", err)
+	}
+	if created := worktreeCreated(moved); created.Before(before) {
+		t.Errorf("created after move = %v, want the recorded time", created)
+	}
+
+	// Worktrees from plain git fall back to the directory's mtime
+	plain := filepath.Join(filepath.Dir(dir), "test-worktrees", "plain")
+	runGit(t, dir, "worktree", "add", "-b", "plain", plain)
+	os.Chtimes(plain, old, old)
+	if created := worktreeCreated(plain); created.Sub(old).Abs() > time.Second {
+		t.Errorf("created = %v, want the directory mtime %v", created, old)
+	}
+
+	worktrees, err := manager.ListWorktreesBasic(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, wt := range worktrees {
+		if wt.IsMain != wt.Created.IsZero() {
+			t.Errorf("%s: IsMain = %v, Created = %v; want a date for every linked worktree only", wt.Name, wt.IsMain, wt.Created)
+		}
+	}
+}
+
+func TestParseCreatedBefore(t *testing.T) {
+	now := time.Date(2026, 3, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Time
+	}{
+		{"30d", now.Add(-30 * 24 * time.Hour)},
+		{"2w", now.Add(-14 * 24 * time.Hour)},
+		{"12h", now.Add(-12 * time.Hour)},
+		{"2026-01-31", time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{"2026-01-31T08:00:00Z", time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseCreatedBefore(tt.value, now)
+		if err != nil || !got.Equal(tt.want) {
+			t.Errorf("ParseCreatedBefore(%q) = %v, %v; want %v", tt.value, got, err, tt.want)
+		}
+	}
+	for _, value := range []string{"", "yesterday", "30", "3m"} {
+		if _, err := ParseCreatedBefore(value, now); err == nil {
+			t.Errorf("ParseCreatedBefore(%q) should fail", value)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// setDefaultBranch rewrites the test environment's config with a
+// default_branch override.
+func setDefaultBranch(t *testing.T, dir, branch string) {
+	t.Helper()
+	cfg := `{
+		"worktree_dir": "` + filepath.Join(filepath.Dir(dir), "test-worktrees") + `",
+		"package_manager": "auto",
+		"version": "1.0.0",
+		"default_branch": "` + branch + `"
+	}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDefaultBranchOverride(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// develop is ahead of main; feature starts at develop.
+	runGit(t, dir, "checkout", "-q", "-b", "develop")
+	runGit(t, dir, "commit", "-q", "--allow-empty", "-m", "develop work")
+	runGit(t, dir, "branch", "feature")
+	runGit(t, dir, "checkout", "-q", "main")
+
+	ctx := context.Background()
+	if _, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "feature", Branch: "feature"}); err != nil {
+		t.Fatalf("CreateWorktree(feature) error: %v", err)
+	}
+
+	staleReason := func() string {
+		worktrees, err := manager.ListWorktrees(ctx)
+		if err != nil {
+			t.Fatalf("ListWorktrees() error: %v", err)
+		}
+		for _, wt := range worktrees {
+			if wt.Branch == "feature" {
+				return wt.StaleReason
+			}
+		}
+		t.Fatal("feature worktree not listed")
+		return ""
+	}
+
+	// Against main, feature carries develop's commit, so it is active.
+	if reason := staleReason(); reason != "" {
+		t.Errorf("without override: StaleReason = %q, want active", reason)
+	}
+
+	// The override is read once per manager; a new one, as each command
+	// builds, sees the change.
+	setDefaultBranch(t, dir, "develop")
+	if branch := manager.configuredDefaultBranch(); branch != "" {
+		t.Errorf("configuredDefaultBranch() = %q, want the value cached before the change", branch)
+	}
+	manager = NewWorktreeManager(manager.gitRepo, manager.configManager)
+
+	if branch, err := manager.getDefaultBranch(); err != nil || branch != "develop" {
+		t.Errorf("getDefaultBranch() = %q, %v, want develop", branch, err)
+	}
+	if branch, err := manager.RecommendedBaseBranch(ctx); err != nil || branch != "develop" {
+		t.Errorf("RecommendedBaseBranch() = %q, %v, want develop", branch, err)
+	}
+	if reason := staleReason(); reason != "no_unique_commits" {
+		t.Errorf("with default_branch = develop: StaleReason = %q, want no_unique_commits", reason)
+	}
+
+	// A new branch without an explicit base starts from develop.
+	path, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "from-default", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree(from-default) error: %v", err)
+	}
+	head, _ := exec.Command("git", "-C", path, "rev-parse", "HEAD").Output()
+	want, _ := exec.Command("git", "-C", dir, "rev-parse", "develop").Output()
+	if string(head) != string(want) {
+		t.Errorf("new worktree HEAD = %s, want develop %s", strings.TrimSpace(string(head)), strings.TrimSpace(string(want)))
+	}
+}
+
+func TestDefaultBranchOverrideMustExist(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	setDefaultBranch(t, dir, "trunk")
+	if _, err := manager.configManager.Load(); err == nil {
+		t.Error("Load() with a missing default_branch = nil error, want validation error")
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// ErrBranchNotMerged is returned, wrapped, when DeleteBranch refuses a branch
+// with commits that are not merged; force deletes it anyway.
+var ErrBranchNotMerged = errors.New("not merged into the base branch")
+
+// ErrStaleBranchUnconfirmed is returned, wrapped, when DeleteStaleBranch
+// refuses a branch whose stale reason doesn't show its work was merged;
+// force deletes it anyway.
+var ErrStaleBranchUnconfirmed = errors.New("stale reason doesn't show its work was merged")
+
+// StaleBranchMerged reports whether a worktree stale for reason has a branch
+// whose work is known to be in the base branch: its PR was merged, git sees
+// it merged, or it never had commits of its own. A remote branch that is
+// gone or a PR closed without merging says nothing of the kind.
+func StaleBranchMerged(reason string) bool {
+	switch reason {
+	case "pr_merged", "merged_locally", "no_unique_commits":
+		return true
+	}
+	return false
+}
+
+// CheckDeleteBranch reports why DeleteBranch would refuse branch, so callers
+// can refuse before removing its worktree instead of after: the current
+// branch is never deleted, and without force neither is a branch with
+// commits its base branch lacks.
+func (wm *WorktreeManager) CheckDeleteBranch(branch string, force bool) error {
+	if branch == "" || branch == "(detached)" {
+		return fmt.Errorf("no branch to delete (detached HEAD)")
+	}
+	if current, err := wm.getCurrentBranch(); err == nil && current == branch {
+		return fmt.Errorf("cannot delete branch '%s': it is the current branch", branch)
+	}
+	if !force {
+		if merged, hasUniqueCommits := wm.isBranchMerged(branch); hasUniqueCommits && !merged {
+			return fmt.Errorf("branch '%s' is %w", branch, ErrBranchNotMerged)
+		}
+	}
+	return nil
+}
+
+// DeleteBranch deletes the local branch, normally after its worktree is
+// gone. It runs `git branch -d`, which also refuses a branch git considers
+// unmerged or one still checked out in a worktree, or `git branch -D` when
+// force is set. Remote branches are never touched.
+func (wm *WorktreeManager) DeleteBranch(branch string, force bool) error {
+	if err := wm.CheckDeleteBranch(branch, force); err != nil {
+		return err
+	}
+
+	flag := "-d"
+	if force {
+		flag = "-D"
+	}
+	cmd := exec.Command("git", "branch", flag, branch)
+	if repoRoot, err := wm.getRepoRoot(); err == nil {
+		cmd.Dir = repoRoot
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		msg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(output)), "error:"))
+		logging.Error("DeleteBranch: git branch %s %s failed: %v, output: %s", flag, branch, err, msg)
+		if strings.Contains(msg, "not fully merged") {
+			return fmt.Errorf("branch '%s' is %w", branch, ErrBranchNotMerged)
+		}
+		if msg != "" {
+			return fmt.Errorf("failed to delete branch '%s': %s", branch, msg)
+		}
+		return fmt.Errorf("failed to delete branch '%s': %w", branch, err)
+	}
+
+	logging.Info("Deleted branch '%s'", branch)
+	return nil
+}
+
+// DeleteStaleBranch deletes the branch of wt, a stale worktree that was just
+// removed, as `gren cleanup --with-branch` does. A branch StaleBranchMerged
+// vouches for goes through DeleteBranch, with a merged PR counting as merged
+// even when git can't see it (squash or rebase merges). Any other branch
+// needs force, and is then deleted with `git branch -D`. A branch_deleted
+// worktree's branch is already gone, so there is nothing to do.
+func (wm *WorktreeManager) DeleteStaleBranch(wt WorktreeInfo, force bool) error {
+	if wt.StaleReason == "branch_deleted" {
+		return nil
+	}
+	if !force && !StaleBranchMerged(wt.StaleReason) {
+		return fmt.Errorf("branch '%s' is %s: %w", wt.Branch, wt.StaleReason, ErrStaleBranchUnconfirmed)
+	}
+	return wm.DeleteBranch(wt.Branch, force || wt.StaleReason == "pr_merged")
+}
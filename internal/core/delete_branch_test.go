@@ -0,0 +1,90 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeleteBranch(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	branchExists := func(branch string) bool {
+		return exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+	}
+
+	runGit(t, dir, "branch", "merged-branch")
+	if err := manager.DeleteBranch("merged-branch", false); err != nil || branchExists("merged-branch") {
+		t.Errorf("DeleteBranch(merged) = %v, exists %v; want it deleted", err, branchExists("merged-branch"))
+	}
+
+	current, err := manager.getCurrentBranch()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteBranch(current, true); err == nil || !branchExists(current) {
+		t.Errorf("DeleteBranch(current, force) = %v, want a refusal", err)
+	}
+
+	path := filepath.Join(filepath.Dir(dir), "test-worktrees", "unmerged-wt")
+	runGit(t, dir, "worktree", "add", "-b", "unmerged-branch", path)
+	os.WriteFile(filepath.Join(path, "work.txt"), []byte("work\n"), 0644)
+	runGit(t, path, "add", ".")
+	runGit(t, path, "commit", "-m", "work")
+
+	// git refuses a branch still checked out in a worktree, even with force
+	if err := manager.DeleteBranch("unmerged-branch", true); err == nil || !branchExists("unmerged-branch") {
+		t.Errorf("DeleteBranch(checked out) = %v, want a refusal", err)
+	}
+
+	runGit(t, dir, "worktree", "remove", path)
+	if err := manager.DeleteBranch("unmerged-branch", false); !errors.Is(err, ErrBranchNotMerged) || !branchExists("unmerged-branch") {
+		t.Errorf("DeleteBranch(unmerged) = %v, want ErrBranchNotMerged", err)
+	}
+	if err := manager.DeleteBranch("unmerged-branch", true); err != nil || branchExists("unmerged-branch") {
+		t.Errorf("DeleteBranch(unmerged, force) = %v, want it deleted", err)
+	}
+}
+
+func TestDeleteStaleBranch(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	branchExists := func(branch string) bool {
+		return exec.Command("git", "-C", dir, "rev-parse", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil
+	}
+	// An unmerged branch, like one whose PR was squash-merged or closed
+	unmerged := func(branch string) {
+		t.Helper()
+		runGit(t, dir, "checkout", "-q", "-b", branch)
+		os.WriteFile(filepath.Join(dir, branch+".txt"), []byte("work\n"), 0644)
+		runGit(t, dir, "add", ".")
+		runGit(t, dir, "commit", "-q", "-m", "work on "+branch)
+		runGit(t, dir, "checkout", "-q", "-")
+	}
+
+	runGit(t, dir, "branch", "merged-locally")
+	if err := manager.DeleteStaleBranch(WorktreeInfo{Branch: "merged-locally", StaleReason: "merged_locally"}, false); err != nil || branchExists("merged-locally") {
+		t.Errorf("DeleteStaleBranch(merged_locally) = %v, want it deleted", err)
+	}
+
+	unmerged("squash-merged")
+	if err := manager.DeleteStaleBranch(WorktreeInfo{Branch: "squash-merged", StaleReason: "pr_merged"}, false); err != nil || branchExists("squash-merged") {
+		t.Errorf("DeleteStaleBranch(pr_merged) = %v, want it deleted though git can't see the merge", err)
+	}
+
+	for _, reason := range []string{"remote_gone", "pr_closed"} {
+		branch := "stale-" + reason
+		unmerged(branch)
+		wt := WorktreeInfo{Branch: branch, StaleReason: reason}
+		if err := manager.DeleteStaleBranch(wt, false); !errors.Is(err, ErrStaleBranchUnconfirmed) || !branchExists(branch) {
+			t.Errorf("DeleteStaleBranch(%s) = %v, want ErrStaleBranchUnconfirmed and the branch kept", reason, err)
+		}
+		if err := manager.DeleteStaleBranch(wt, true); err != nil || branchExists(branch) {
+			t.Errorf("DeleteStaleBranch(%s, force) = %v, want it deleted", reason, err)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// ErrDeleteCurrent is returned when asked to delete the worktree gren runs
+// in, which git can't remove from under itself.
+var ErrDeleteCurrent = errors.New("cannot delete current worktree")
+
+// ErrDeleteMain is returned when asked to delete the main worktree, whose
+// .git directory holds the git data of every worktree.
+var ErrDeleteMain = errors.New("cannot delete the main worktree: its .git directory holds the git data for every worktree")
+
+// checkDeletable returns why wt can't be deleted at all, force or not, or
+// nil.
+func checkDeletable(wt WorktreeInfo) error {
+	switch {
+	case wt.IsBare:
+		return ErrDeleteBare
+	case wt.IsCurrent:
+		return ErrDeleteCurrent
+	case wt.IsMain:
+		return ErrDeleteMain
+	}
+	return nil
+}
+
+// PrecheckDelete reports, before anything is removed, what deleting wt runs
+// into, for the CLI and the TUI to present alike. err says why wt can't be
+// deleted at all (the bare repository, the current or the main worktree).
+// issues has one short phrase per problem the user should see first: a git
+// operation in progress, a lock, what AssessDeleteRisk finds (uncommitted
+// files, unpushed commits, an unmerged branch, an unreadable status) and
+// submodules. risk is the DeleteRisk behind those issues, for callers that
+// decide on force from it. Whether an issue needs force is the caller's
+// call. wt should carry its status (see EnrichStatus); one never loaded is
+// loaded here.
+func (wm *WorktreeManager) PrecheckDelete(wt WorktreeInfo) (issues []string, risk DeleteRisk, err error) {
+	if err := checkDeletable(wt); err != nil {
+		return nil, DeleteRisk{}, err
+	}
+	// A missing worktree has nothing on disk left to lose
+	if wt.Status == "missing" {
+		return nil, DeleteRisk{}, nil
+	}
+	if wt.Status == "" {
+		wm.EnrichStatus(&wt)
+	}
+
+	if wt.Operation != "" {
+		issues = append(issues, wt.Operation+" in progress")
+	}
+	if locked, reason := worktreeLocked(wt.Path); locked {
+		if reason != "" {
+			issues = append(issues, fmt.Sprintf("locked (%s)", reason))
+		} else {
+			issues = append(issues, "locked")
+		}
+	}
+	risk = wm.AssessDeleteRisk(wt)
+	issues = append(issues, risk.Summary()...)
+	if wt.HasSubmodules {
+		line := "submodules, deinitialized before removal"
+		if wt.SubmoduleState != "" {
+			line += fmt.Sprintf(" (%s)", wt.SubmoduleState)
+		}
+		issues = append(issues, line)
+	}
+	return issues, risk, nil
+}
+
+// worktreeLocked reports whether the worktree at worktreePath was locked
+// with `git worktree lock`, and the reason given, if any. git refuses to
+// remove a locked worktree unless --force is given twice.
+func worktreeLocked(worktreePath string) (locked bool, reason string) {
+	gitDir := worktreeGitDir(worktreePath)
+	if gitDir == "" {
+		return false, ""
+	}
+	data, err := os.ReadFile(filepath.Join(gitDir, "locked"))
+	if err != nil {
+		return false, ""
+	}
+	return true, strings.TrimSpace(string(data))
+}
+
+// DeleteRisk is what deleting a worktree would put at risk, shown by
+// `gren delete` before it asks for confirmation.
+type DeleteRisk struct {
+	Uncommitted int  // Staged and modified files, lost with the worktree
+	Untracked   int  // Untracked files, lost with the worktree
+	Unpushed    int  // Commits no remote has (for a detached HEAD: that no branch has either)
+	Unmerged    bool // The branch has commits its base branch lacks
+	// StatusError is why git status failed (see WorktreeInfo.StatusError);
+	// Uncommitted and Untracked are then unknown, not zero.
+	StatusError string
+	// PRNumber and PRState describe the branch's PR/MR ("OPEN", "MERGED",
+	// "CLOSED"). AssessDeleteRisk leaves them empty; the caller fills them
+	// in from the forge, and PRState stays "" when there is no PR.
+	PRNumber int
+	PRState  string
+}
+
+// AssessDeleteRisk gathers wt's DeleteRisk from git. wt must carry its file
+// counts (see EnrichStatus).
+func (wm *WorktreeManager) AssessDeleteRisk(wt WorktreeInfo) DeleteRisk {
+	risk := DeleteRisk{
+		Uncommitted: wt.StagedCount + wt.ModifiedCount,
+		Untracked:   wt.UntrackedCount,
+		StatusError: wt.StatusError,
+	}
+	if wt.Status == "missing" || wt.IsBare {
+		return risk
+	}
+
+	// A branch survives the delete, so only its own commits that no remote
+	// has are at risk, not ones it shares with the base branch; a detached
+	// HEAD's commits are also lost unless a branch has them
+	args := []string{"-C", wt.Path, "rev-list", "--count", "HEAD", "--not", "--remotes"}
+	for _, ref := range wm.baseBranchRefs() {
+		if exec.Command("git", "-C", wt.Path, "rev-parse", "--verify", "--quiet", ref).Run() == nil {
+			args = append(args, ref)
+		}
+	}
+	detached := wt.Branch == "" || wt.Branch == "(detached)"
+	if detached {
+		args = append(args, "--branches", "--tags")
+	}
+	if output, err := exec.Command("git", args...).Output(); err == nil {
+		risk.Unpushed, _ = strconv.Atoi(strings.TrimSpace(string(output)))
+	}
+
+	if !detached {
+		merged, hasUniqueCommits := wm.isBranchMerged(wt.Branch)
+		risk.Unmerged = hasUniqueCommits && !merged
+	}
+	return risk
+}
+
+// NeedsForce reports whether deleting takes -f even after confirmation:
+// there are commits no remote has and no open or merged PR holds the work.
+func (r DeleteRisk) NeedsForce() bool {
+	return r.Unpushed > 0 && r.PRState != "OPEN" && r.PRState != "MERGED"
+}
+
+// Summary describes the risk in short phrases, one per problem, or returns
+// nil when deleting loses nothing.
+func (r DeleteRisk) Summary() []string {
+	var lines []string
+	if r.StatusError != "" {
+		lines = append(lines, fmt.Sprintf("uncommitted changes unknown (%s)", r.StatusError))
+	}
+	if r.Uncommitted > 0 {
+		lines = append(lines, fmt.Sprintf("%d uncommitted file(s)", r.Uncommitted))
+	}
+	if r.Untracked > 0 {
+		lines = append(lines, fmt.Sprintf("%d untracked file(s)", r.Untracked))
+	}
+	if r.Unpushed > 0 {
+		line := fmt.Sprintf("%d commit(s) not pushed to any remote", r.Unpushed)
+		if r.PRState != "" {
+			line += fmt.Sprintf(" (PR #%d is %s)", r.PRNumber, strings.ToLower(r.PRState))
+		}
+		lines = append(lines, line)
+	}
+	if r.Unmerged {
+		lines = append(lines, "branch not merged into the base branch")
+	}
+	return lines
+}
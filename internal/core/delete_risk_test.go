@@ -0,0 +1,129 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAssessDeleteRisk(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	path := filepath.Join(filepath.Dir(dir), "test-worktrees", "risk-wt")
+	runGit(t, dir, "worktree", "add", "-b", "risk-branch", path)
+
+	wt := WorktreeInfo{Name: "risk-wt", Path: path, Branch: "risk-branch"}
+	if risk := manager.AssessDeleteRisk(wt); risk.Summary() != nil || risk.NeedsForce() {
+		t.Errorf("fresh worktree risk = %+v, want nothing at risk", risk)
+	}
+
+	os.WriteFile(filepath.Join(path, "work.txt"), []byte("work\n"), 0644)
+	runGit(t, path, "add", ".")
+	runGit(t, path, "commit", "-m", "work")
+	wt.ModifiedCount, wt.UntrackedCount = 2, 1
+
+	risk := manager.AssessDeleteRisk(wt)
+	want := []string{"2 uncommitted file(s)", "1 untracked file(s)", "1 commit(s) not pushed to any remote", "branch not merged into the base branch"}
+	if !reflect.DeepEqual(risk.Summary(), want) || !risk.NeedsForce() {
+		t.Errorf("risk = %+v (%q), want %q and -f required", risk, risk.Summary(), want)
+	}
+	risk.PRNumber, risk.PRState = 3, "OPEN"
+	if risk.NeedsForce() {
+		t.Error("NeedsForce() with an open PR = true, want false")
+	}
+
+	// Once a remote has the commit it is no longer at risk
+	origin := t.TempDir()
+	runGit(t, origin, "init", "--bare", "-q")
+	runGit(t, path, "remote", "add", "origin", origin)
+	runGit(t, path, "push", "-q", "origin", "risk-branch")
+	if risk := manager.AssessDeleteRisk(wt); risk.Unpushed != 0 || !risk.Unmerged || risk.NeedsForce() {
+		t.Errorf("risk after push = %+v, want no unpushed commits but still unmerged", risk)
+	}
+
+	// A detached HEAD's commits are lost unless a branch has them
+	runGit(t, path, "checkout", "-q", "--detach")
+	os.WriteFile(filepath.Join(path, "detached.txt"), []byte("x\n"), 0644)
+	runGit(t, path, "add", ".")
+	runGit(t, path, "commit", "-m", "detached work")
+	detached := WorktreeInfo{Name: "risk-wt", Path: path, Branch: "(detached)"}
+	if risk := manager.AssessDeleteRisk(detached); risk.Unpushed != 1 || risk.Unmerged {
+		t.Errorf("detached risk = %+v, want the one commit no branch has", risk)
+	}
+}
+
+func TestPrecheckDelete(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	path := filepath.Join(filepath.Dir(dir), "test-worktrees", "precheck-wt")
+	runGit(t, dir, "worktree", "add", "-b", "precheck-branch", path)
+
+	for _, tt := range []struct {
+		wt   WorktreeInfo
+		want error
+	}{
+		{WorktreeInfo{Name: "repo.git", IsBare: true}, ErrDeleteBare},
+		{WorktreeInfo{Name: "here", Path: path, IsCurrent: true}, ErrDeleteCurrent},
+		{WorktreeInfo{Name: "main", Path: dir, IsMain: true}, ErrDeleteMain},
+	} {
+		if _, _, err := manager.PrecheckDelete(tt.wt); !errors.Is(err, tt.want) {
+			t.Errorf("PrecheckDelete(%s) error = %v, want %v", tt.wt.Name, err, tt.want)
+		}
+	}
+
+	// Status not loaded yet: PrecheckDelete loads it
+	wt := WorktreeInfo{Name: "precheck-wt", Path: path, Branch: "precheck-branch"}
+	if issues, _, err := manager.PrecheckDelete(wt); err != nil || issues != nil {
+		t.Errorf("fresh worktree: issues = %q, err = %v; want none", issues, err)
+	}
+
+	os.WriteFile(filepath.Join(path, "work.txt"), []byte("work\n"), 0644)
+	runGit(t, path, "add", ".")
+	runGit(t, path, "commit", "-m", "work")
+	os.WriteFile(filepath.Join(path, "scratch.txt"), []byte("x\n"), 0644)
+	os.WriteFile(filepath.Join(path, ".gitmodules"), []byte(""), 0644)
+	runGit(t, dir, "worktree", "lock", "--reason", "on a USB disk", path)
+
+	issues, risk, err := manager.PrecheckDelete(wt)
+	if err != nil {
+		t.Fatalf("PrecheckDelete() error: %v", err)
+	}
+	if risk.Unpushed != 1 || risk.Untracked != 2 || !risk.Unmerged {
+		t.Errorf("risk = %+v, want the unpushed commit, untracked files and unmerged branch", risk)
+	}
+	want := []string{"locked (on a USB disk)", "2 untracked file(s)", "1 commit(s) not pushed to any remote", "branch not merged into the base branch", "submodules, deinitialized before removal"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Errorf("issues = %q, want %q", issues, want)
+	}
+
+	if missing, _, err := manager.PrecheckDelete(WorktreeInfo{Name: "gone", Path: filepath.Join(dir, "gone"), Status: "missing"}); err != nil || missing != nil {
+		t.Errorf("missing worktree: issues = %q, err = %v; want none", missing, err)
+	}
+}
+
+func TestDeleteWorktreeLocked(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	path := filepath.Join(filepath.Dir(dir), "test-worktrees", "locked-wt")
+	runGit(t, dir, "worktree", "add", "-b", "locked-branch", path)
+	runGit(t, dir, "worktree", "lock", path)
+
+	ctx := context.Background()
+	if err := manager.DeleteWorktree(ctx, "locked-wt", false); err == nil || !strings.Contains(err.Error(), "git worktree unlock") {
+		t.Errorf("DeleteWorktree() of a locked worktree = %v, want it refused with the unlock command", err)
+	}
+	if err := manager.DeleteWorktree(ctx, "locked-wt", true); err != nil {
+		t.Fatalf("DeleteWorktree(force) of a locked worktree error: %v", err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("locked worktree still exists after a forced delete: %v", err)
+	}
+	if out, _ := exec.Command("git", "-C", dir, "worktree", "list").Output(); strings.Contains(string(out), "locked-wt") {
+		t.Errorf("forced delete left the worktree registered:\n%s", out)
+	}
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FileStat is one file with uncommitted changes in a worktree, as listed
+// by DiffStat.
+type FileStat struct {
+	Path      string
+	Added     int  // Lines added; 0 for binary files
+	Deleted   int  // Lines deleted; 0 for binary files
+	Binary    bool // git reports no line counts for it
+	Untracked bool // New file git doesn't track yet; Added counts its lines
+}
+
+// DiffStat lists the uncommitted changes in the worktree at path, like
+// `git diff --stat HEAD` plus untracked files: staged and unstaged edits
+// together, largest change first. Untracked files count every line as
+// added.
+func DiffStat(path string) ([]FileStat, error) {
+	cmd := exec.Command("git", "diff", "HEAD", "--numstat", "-z")
+	cmd.Dir = path
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git diff failed in %s: %w", path, err)
+	}
+	changes := parseNumstat(output)
+
+	cmd = exec.Command("git", "ls-files", "--others", "--exclude-standard", "-z")
+	cmd.Dir = path
+	output, err = cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git ls-files failed in %s: %w", path, err)
+	}
+	for _, file := range strings.Split(string(output), "\x00") {
+		if file == "" {
+			continue
+		}
+		change := FileStat{Path: file, Untracked: true}
+		change.Added, change.Binary = countLines(filepath.Join(path, file))
+		changes = append(changes, change)
+	}
+
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].Added+changes[i].Deleted > changes[j].Added+changes[j].Deleted
+	})
+	return changes, nil
+}
+
+// countLines counts the lines in the file at path, or reports it as binary
+// when its first 8000 bytes hold a NUL byte, as git decides. An unreadable
+// file counts as empty.
+func countLines(path string) (lines int, binary bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return 0, false
+	}
+	defer f.Close()
+
+	buf := make([]byte, 32*1024)
+	first := true
+	var last byte
+	for {
+		n, err := f.Read(buf)
+		if n > 0 {
+			if first && bytes.IndexByte(buf[:min(n, 8000)], 0) >= 0 {
+				return 0, true
+			}
+			first = false
+			lines += bytes.Count(buf[:n], []byte{'\n'})
+			last = buf[n-1]
+		}
+		if err != nil {
+			break
+		}
+	}
+	// A last line without a newline still counts
+	if !first && last != '\n' {
+		lines++
+	}
+	return lines, false
+}
+
+// parseNumstat parses `git diff --numstat -z` output. A rename is recorded
+// under its new path.
+func parseNumstat(output []byte) []FileStat {
+	var changes []FileStat
+	fields := bytes.Split(output, []byte{0})
+	for i := 0; i < len(fields); i++ {
+		record := string(fields[i])
+		if record == "" {
+			continue
+		}
+		parts := strings.SplitN(record, "\t", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		change := FileStat{Path: parts[2]}
+		if parts[0] == "-" && parts[1] == "-" {
+			change.Binary = true
+		} else {
+			change.Added, _ = strconv.Atoi(parts[0])
+			change.Deleted, _ = strconv.Atoi(parts[1])
+		}
+		// Renames leave the path empty and follow with the old and new path
+		if change.Path == "" && i+2 < len(fields) {
+			change.Path = string(fields[i+2])
+			i += 2
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
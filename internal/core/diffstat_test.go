@@ -0,0 +1,44 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestDiffStat(t *testing.T) {
+	dir, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	os.WriteFile(filepath.Join(dir, "old.txt"), []byte("a\nb\nc\n"), 0644)
+	runGit(t, dir, "add", "old.txt")
+	runGit(t, dir, "commit", "-q", "-m", "old")
+
+	// Unstaged edit, staged rename, untracked text and binary files
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Title\n\nMore\nLines\n"), 0644)
+	runGit(t, dir, "mv", "old.txt", "new.txt")
+	os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("one\ntwo\nno newline"), 0644)
+	os.WriteFile(filepath.Join(dir, "image.bin"), []byte{0x89, 'P', 'N', 'G', 0, 1, 2}, 0644)
+
+	got, err := DiffStat(dir)
+	if err != nil {
+		t.Fatalf("DiffStat() error = %v", err)
+	}
+	want := []FileStat{
+		{Path: "README.md", Added: 4, Deleted: 1},
+		{Path: "notes.txt", Added: 3, Untracked: true},
+		{Path: "new.txt"},
+		{Path: "image.bin", Binary: true, Untracked: true},
+	}
+	// .gren from the test setup is untracked too
+	var files []FileStat
+	for _, f := range got {
+		if filepath.Dir(f.Path) != ".gren" {
+			files = append(files, f)
+		}
+	}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("DiffStat() = %+v, want %+v", files, want)
+	}
+}
@@ -0,0 +1,65 @@
+package core
+
+import (
+	"io/fs"
+	"path/filepath"
+	"slices"
+	"sync"
+)
+
+// WorktreeDiskUsage returns the bytes used by regular files under path,
+// roughly what deleting the worktree reclaims. Symlinks are not followed, so
+// env files, caches and a .gren linked from the main worktree are not
+// counted. Directories listed in exclude are skipped, so worktrees nested
+// inside another (e.g. worktree_dir ".worktrees") are only counted once.
+// Unreadable entries are skipped rather than failing the whole walk.
+func WorktreeDiskUsage(path string, exclude ...string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(path, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() && p != path && slices.Contains(exclude, p) {
+			return fs.SkipDir
+		}
+		if err != nil {
+			if d == nil {
+				// The root itself could not be read
+				return err
+			}
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		if info, err := d.Info(); err == nil {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
+
+// WorktreeDiskUsages measures several worktrees concurrently, each without
+// the others nested inside it. The result maps each path to its size; paths
+// that could not be measured are left out.
+func WorktreeDiskUsages(paths []string) map[string]int64 {
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sizes := make(map[string]int64, len(paths))
+	for _, p := range paths {
+		wg.Add(1)
+		go func(p string) {
+			defer wg.Done()
+			size, err := WorktreeDiskUsage(p, paths...)
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			sizes[p] = size
+			mu.Unlock()
+		}(p)
+	}
+	wg.Wait()
+	return sizes
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWorktreeDiskUsage(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a.txt"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "sub", "b.txt"), make([]byte, 50), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A symlink to a large file outside the worktree must not be counted
+	outside := filepath.Join(t.TempDir(), "big")
+	if err := os.WriteFile(outside, make([]byte, 4096), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, ".env")); err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := WorktreeDiskUsage(dir)
+	if err != nil {
+		t.Fatalf("WorktreeDiskUsage() error = %v", err)
+	}
+	if size != 150 {
+		t.Errorf("WorktreeDiskUsage() = %d, want 150", size)
+	}
+
+	sizes := WorktreeDiskUsages([]string{dir, filepath.Join(dir, "missing")})
+	if sizes[dir] != 150 {
+		t.Errorf("WorktreeDiskUsages()[dir] = %d, want 150", sizes[dir])
+	}
+	if _, ok := sizes[filepath.Join(dir, "missing")]; ok {
+		t.Error("missing path should be left out of WorktreeDiskUsages()")
+	}
+}
+
+func TestWorktreeDiskUsageNestedWorktrees(t *testing.T) {
+	main := t.TempDir()
+	if err := os.WriteFile(filepath.Join(main, "a.txt"), make([]byte, 100), 0644); err != nil {
+		t.Fatal(err)
+	}
+	nested := filepath.Join(main, ".worktrees", "feature")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(nested, "b.txt"), make([]byte, 30), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// A worktree elsewhere whose .gren links back into the main worktree
+	linked := t.TempDir()
+	if err := os.WriteFile(filepath.Join(linked, "c.txt"), make([]byte, 10), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(main, filepath.Join(linked, ".gren")); err != nil {
+		t.Fatal(err)
+	}
+
+	sizes := WorktreeDiskUsages([]string{main, nested, linked})
+	if sizes[main] != 100 || sizes[nested] != 30 || sizes[linked] != 10 {
+		t.Errorf("WorktreeDiskUsages() = %v, want main 100, nested 30, linked 10", sizes)
+	}
+}
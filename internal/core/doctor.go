@@ -0,0 +1,291 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/logging"
+)
+
+// CheckStatus is the outcome of a single `gren doctor` check.
+type CheckStatus string
+
+const (
+	CheckOK   CheckStatus = "ok"
+	CheckWarn CheckStatus = "warn"
+	CheckFail CheckStatus = "fail"
+)
+
+// DoctorCheck is one line of `gren doctor` output.
+type DoctorCheck struct {
+	Name   string // Short label, e.g. "post-create hook"
+	Status CheckStatus
+	Detail string // What was found
+	Hint   string // How to fix it, "" when Status is CheckOK
+}
+
+// Diagnose runs gren's repository checks: git itself, the project config,
+// the post-create hook, the worktree directory, and worktrees that are
+// missing or cut off from the repository, detached, named for another
+// branch, or miss an upstream they could track. Checks of the user's
+// environment (forge CLI, shell integration) are left to the caller. A
+// failed check never stops the others from running.
+func (wm *WorktreeManager) Diagnose(ctx context.Context) []DoctorCheck {
+	var checks []DoctorCheck
+
+	if err := wm.CheckPrerequisites(); err != nil {
+		checks = append(checks, DoctorCheck{Name: "git", Status: CheckFail, Detail: "git not found on PATH", Hint: "install git"})
+		return checks
+	}
+	checks = append(checks, DoctorCheck{Name: "git", Status: CheckOK, Detail: "installed"})
+
+	cfg, configCheck := wm.checkConfig()
+	checks = append(checks, configCheck)
+	if cfg == nil {
+		return checks
+	}
+
+	checks = append(checks, wm.checkPostCreateHook(cfg))
+	checks = append(checks, wm.checkWorktreeDir(ctx, cfg))
+
+	worktrees, err := wm.ListWorktrees(ctx)
+	if err != nil {
+		checks = append(checks, DoctorCheck{Name: "worktrees", Status: CheckFail, Detail: err.Error(), Hint: "run gren inside a git repository"})
+		return checks
+	}
+	checks = append(checks, checkLinks(worktrees), checkDetached(worktrees), checkBranchNames(worktrees), checkUpstreams(worktrees))
+
+	logging.Debug("Diagnose: ran %d checks", len(checks))
+	return checks
+}
+
+// checkConfig loads the project config. The config is nil when it fails to
+// load, since the checks that depend on it cannot run.
+func (wm *WorktreeManager) checkConfig() (*config.Config, DoctorCheck) {
+	check := DoctorCheck{Name: "config"}
+	cfg, err := wm.configManager.Load()
+	switch {
+	case err != nil:
+		check.Status = CheckFail
+		check.Detail = err.Error()
+		check.Hint = "fix the file, or run 'gren config show' to see what gren expects"
+		return nil, check
+	case !wm.configManager.Exists():
+		check.Status = CheckWarn
+		check.Detail = "no .gren config, using defaults"
+		check.Hint = "run 'gren init' to set up hooks and settings"
+	default:
+		check.Status = CheckOK
+		check.Detail = "valid"
+	}
+	return cfg, check
+}
+
+// checkPostCreateHook verifies that script-file post-create hooks exist and
+// are executable. A non-executable script would be handed to sh as a
+// command and fail with "permission denied".
+func (wm *WorktreeManager) checkPostCreateHook(cfg *config.Config) DoctorCheck {
+	check := DoctorCheck{Name: "post-create hook"}
+	hooks := cfg.GetAllHooks(config.HookPostCreate)
+	if len(hooks) == 0 {
+		check.Status = CheckWarn
+		check.Detail = "none configured, new worktrees get no setup (deps, .env files)"
+		check.Hint = "run 'gren init' to generate one"
+		return check
+	}
+
+	repoRoot, _ := wm.getRepoRoot()
+	var problems []string
+	for _, hook := range hooks {
+		if hook.Disabled || !isScriptHook(hook.Command) {
+			continue
+		}
+		path := resolveHookPath(hook.Command, repoRoot)
+		info, err := os.Stat(path)
+		if err != nil {
+			if strings.HasSuffix(hook.Command, ".sh") {
+				problems = append(problems, hook.Command+" does not exist")
+			}
+			continue
+		}
+		if lacksExecuteBit(info) {
+			problems = append(problems, hook.Command+" is not executable")
+			check.Hint = "chmod +x " + hook.Command
+		} else if problem := hookInterpreterProblem(path); problem != "" {
+			problems = append(problems, hook.Command+" "+problem)
+			if check.Hint == "" {
+				check.Hint = "install the interpreter or fix the script's #! line"
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		check.Status = CheckFail
+		check.Detail = strings.Join(problems, "; ")
+		if check.Hint == "" {
+			check.Hint = "create the script or update hooks.post-create in the config"
+		}
+		return check
+	}
+	check.Status = CheckOK
+	check.Detail = fmt.Sprintf("%d configured", len(hooks))
+	return check
+}
+
+// checkWorktreeDir verifies that gren can create worktrees in the configured
+// directory, or in its nearest existing parent when it does not exist yet.
+func (wm *WorktreeManager) checkWorktreeDir(ctx context.Context, cfg *config.Config) DoctorCheck {
+	check := DoctorCheck{Name: "worktree dir"}
+	dir, err := wm.resolveWorktreeDir(ctx, cfg, "", "")
+	if err != nil {
+		check.Status = CheckFail
+		check.Detail = err.Error()
+		check.Hint = "set worktree_dir in the config"
+		return check
+	}
+	dir = filepath.Clean(dir)
+
+	existing := dir
+	for {
+		if info, err := os.Stat(existing); err == nil {
+			if !info.IsDir() {
+				check.Status = CheckFail
+				check.Detail = existing + " is not a directory"
+				check.Hint = "point worktree_dir somewhere else"
+				return check
+			}
+			break
+		}
+		parent := filepath.Dir(existing)
+		if parent == existing {
+			break
+		}
+		existing = parent
+	}
+
+	probe, err := os.CreateTemp(existing, ".gren-doctor-*")
+	if err != nil {
+		check.Status = CheckFail
+		check.Detail = fmt.Sprintf("%s is not writable", existing)
+		check.Hint = "fix its permissions or point worktree_dir somewhere writable"
+		return check
+	}
+	probe.Close()
+	os.Remove(probe.Name())
+
+	check.Status = CheckOK
+	check.Detail = dir
+	if existing != dir {
+		check.Detail += " (will be created)"
+	}
+	return check
+}
+
+// checkDetached lists worktrees that have no branch checked out.
+func checkDetached(worktrees []WorktreeInfo) DoctorCheck {
+	check := DoctorCheck{Name: "detached worktrees"}
+	var names []string
+	for _, wt := range worktrees {
+		if wt.Branch == "(detached)" {
+			names = append(names, wt.Name)
+		}
+	}
+	if len(names) == 0 {
+		check.Status = CheckOK
+		check.Detail = "none"
+		return check
+	}
+	check.Status = CheckWarn
+	check.Detail = strings.Join(names, ", ")
+	check.Hint = fmt.Sprintf("put one on a branch with 'gren reattach %s <branch>'", names[0])
+	return check
+}
+
+// checkLinks lists worktrees git can no longer reach: directories that are
+// gone, and worktrees whose link with the repository broke because one of
+// them was moved by hand.
+func checkLinks(worktrees []WorktreeInfo) DoctorCheck {
+	check := DoctorCheck{Name: "worktree links"}
+	var broken, missing []string
+	for _, wt := range worktrees {
+		switch {
+		case wt.Status == "missing":
+			missing = append(missing, wt.Name)
+		case wt.BrokenLink:
+			broken = append(broken, wt.Name)
+		}
+	}
+
+	var details, hints []string
+	if len(broken) > 0 {
+		details = append(details, "broken link: "+strings.Join(broken, ", "))
+		hints = append(hints, "run 'gren repair'")
+	}
+	if len(missing) > 0 {
+		details = append(details, "directory missing: "+strings.Join(missing, ", "))
+		hints = append(hints, "if moved, run 'gren repair <new path>'; if deleted, 'gren prune'")
+	}
+	if len(details) == 0 {
+		check.Status = CheckOK
+		check.Detail = "every worktree is linked to the repository"
+		return check
+	}
+	check.Status = CheckWarn
+	check.Detail = strings.Join(details, "; ")
+	check.Hint = strings.Join(hints, "; ")
+	return check
+}
+
+// checkBranchNames lists worktrees whose directory is named for another
+// branch than the one checked out since (WorktreeInfo.BranchMismatch).
+func checkBranchNames(worktrees []WorktreeInfo) DoctorCheck {
+	check := DoctorCheck{Name: "branch names"}
+	var mismatched []WorktreeInfo
+	var names []string
+	for _, wt := range worktrees {
+		if wt.BranchMismatch {
+			mismatched = append(mismatched, wt)
+			names = append(names, fmt.Sprintf("%s (on %s)", wt.Name, wt.Branch))
+		}
+	}
+	if len(mismatched) == 0 {
+		check.Status = CheckOK
+		check.Detail = "every worktree directory matches its branch"
+		return check
+	}
+	wt := mismatched[0]
+	check.Status = CheckWarn
+	check.Detail = "directory named for another branch: " + strings.Join(names, ", ")
+	check.Hint = fmt.Sprintf("rename it after its branch with 'gren relocate %s', or check the original branch back out", wt.Name)
+	return check
+}
+
+// checkUpstreams lists worktrees whose branch exists on origin but does not
+// track it, typically a local-only branch pushed without -u. Branches that
+// were never pushed are fine: there is nothing to track yet.
+func checkUpstreams(worktrees []WorktreeInfo) DoctorCheck {
+	check := DoctorCheck{Name: "upstreams"}
+	var names []string
+	for _, wt := range worktrees {
+		if wt.Branch == "" || wt.Branch == "(detached)" || wt.Branch == "(bare)" || GetUpstream(wt.Path) != "" {
+			continue
+		}
+		if exec.Command("git", "-C", wt.Path, "show-ref", "--verify", "--quiet", "refs/remotes/origin/"+wt.Branch).Run() == nil {
+			names = append(names, wt.Name)
+		}
+	}
+	if len(names) == 0 {
+		check.Status = CheckOK
+		check.Detail = "all pushed branches track origin"
+		return check
+	}
+	check.Status = CheckWarn
+	check.Detail = "not tracking their origin branch: " + strings.Join(names, ", ")
+	check.Hint = fmt.Sprintf("run 'gren set-upstream %s'", names[0])
+	return check
+}
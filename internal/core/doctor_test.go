@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/langtind/gren/internal/config"
+)
+
+func doctorCheck(t *testing.T, checks []DoctorCheck, name string) DoctorCheck {
+	t.Helper()
+	for _, check := range checks {
+		if check.Name == name {
+			return check
+		}
+	}
+	t.Fatalf("no %q check in %+v", name, checks)
+	return DoctorCheck{}
+}
+
+func TestDiagnose(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	t.Run("fresh repo", func(t *testing.T) {
+		checks := manager.Diagnose(ctx)
+		for _, name := range []string{"git", "config", "worktree dir", "worktree links", "detached worktrees", "branch names", "upstreams"} {
+			if check := doctorCheck(t, checks, name); check.Status != CheckOK {
+				t.Errorf("%s = %s (%s), want ok", name, check.Status, check.Detail)
+			}
+		}
+		if check := doctorCheck(t, checks, "post-create hook"); check.Status != CheckWarn {
+			t.Errorf("post-create hook = %s, want warn when none is configured", check.Status)
+		}
+		if check := doctorCheck(t, checks, "worktree dir"); !strings.HasSuffix(check.Detail, "(will be created)") {
+			t.Errorf("worktree dir detail = %q, want it to note the dir will be created", check.Detail)
+		}
+	})
+
+	t.Run("hook script not executable", func(t *testing.T) {
+		hook := filepath.Join(dir, ".gren", "post-create.sh")
+		if err := os.WriteFile(hook, []byte("#!/bin/sh\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		cfg := `{"worktree_dir": "` + filepath.Join(filepath.Dir(dir), "test-worktrees") + `", "version": "1.0.0", "hooks": {"post_create": ".gren/post-create.sh"}}`
+		if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		check := doctorCheck(t, manager.Diagnose(ctx), "post-create hook")
+		if check.Status != CheckFail || check.Hint != "chmod +x .gren/post-create.sh" {
+			t.Errorf("post-create hook = %+v, want fail with a chmod hint", check)
+		}
+
+		// Running it fails with a clear message rather than sh's "permission denied"
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		results := manager.RunPostCreateHookWithApproval(dir, "main", "", true)
+		if failed := FirstFailedHook(results); failed == nil || !strings.Contains(failed.Err.Error(), "is not executable (run: chmod +x .gren/post-create.sh)") {
+			t.Errorf("running the hook = %+v, want a not-executable error", results)
+		}
+
+		scripts := manager.NonExecutableHookScripts(config.HookPostCreate)
+		if len(scripts) != 1 || scripts[0] != ".gren/post-create.sh" {
+			t.Fatalf("NonExecutableHookScripts() = %v, want the post-create script", scripts)
+		}
+		if err := manager.MakeHookExecutable(scripts[0]); err != nil {
+			t.Fatalf("MakeHookExecutable() error: %v", err)
+		}
+		if info, _ := os.Stat(hook); info.Mode().Perm() != 0755 {
+			t.Errorf("hook mode = %v after MakeHookExecutable, want 0755", info.Mode().Perm())
+		}
+		if check := doctorCheck(t, manager.Diagnose(ctx), "post-create hook"); check.Status != CheckOK {
+			t.Errorf("post-create hook = %+v after chmod, want ok", check)
+		}
+	})
+
+	t.Run("hook interpreter not installed", func(t *testing.T) {
+		hook := filepath.Join(dir, ".gren", "post-create.sh")
+		original, _ := os.ReadFile(hook)
+		defer os.WriteFile(hook, original, 0755)
+		if err := os.WriteFile(hook, []byte("#!/usr/bin/env gren-no-such-interpreter\n"), 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		check := doctorCheck(t, manager.Diagnose(ctx), "post-create hook")
+		if check.Status != CheckFail || !strings.Contains(check.Detail, "needs gren-no-such-interpreter") {
+			t.Errorf("post-create hook = %+v, want fail naming the interpreter", check)
+		}
+
+		t.Setenv("HOME", t.TempDir())
+		t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+		results := manager.RunPostCreateHookWithApproval(dir, "main", "", true)
+		if failed := FirstFailedHook(results); failed == nil || !strings.Contains(failed.Err.Error(), "hook script .gren/post-create.sh needs gren-no-such-interpreter") {
+			t.Errorf("running the hook = %+v, want a missing-interpreter error", results)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		configPath := filepath.Join(dir, ".gren", "config.json")
+		original, _ := os.ReadFile(configPath)
+		defer os.WriteFile(configPath, original, 0644)
+		os.WriteFile(configPath, []byte("{not json"), 0644)
+
+		checks := manager.Diagnose(ctx)
+		if check := doctorCheck(t, checks, "config"); check.Status != CheckFail {
+			t.Errorf("config = %+v, want fail", check)
+		}
+		if len(checks) != 2 {
+			t.Errorf("got %d checks, want only git and config when the config is broken", len(checks))
+		}
+	})
+
+	t.Run("detached and untracked worktrees", func(t *testing.T) {
+		worktrees := filepath.Join(filepath.Dir(dir), "test-worktrees")
+		runGit(t, dir, "worktree", "add", "--detach", filepath.Join(worktrees, "detached-wt"))
+		runGit(t, dir, "worktree", "add", "-b", "pushed", filepath.Join(worktrees, "pushed-wt"))
+		runGit(t, dir, "update-ref", "refs/remotes/origin/pushed", "HEAD")
+		runGit(t, dir, "worktree", "add", "-b", "local-only", filepath.Join(worktrees, "local-wt"))
+
+		checks := manager.Diagnose(ctx)
+		if check := doctorCheck(t, checks, "detached worktrees"); check.Status != CheckWarn || check.Detail != "detached-wt" {
+			t.Errorf("detached worktrees = %+v, want warn naming detached-wt", check)
+		}
+		check := doctorCheck(t, checks, "upstreams")
+		if check.Status != CheckWarn || !strings.HasSuffix(check.Detail, ": pushed-wt") {
+			t.Errorf("upstreams = %+v, want warn naming only pushed-wt", check)
+		}
+		if check.Hint != "run 'gren set-upstream pushed-wt'" {
+			t.Errorf("upstreams hint = %q", check.Hint)
+		}
+	})
+	t.Run("directory named for another branch", func(t *testing.T) {
+		worktrees := filepath.Join(filepath.Dir(dir), "test-worktrees")
+		for _, req := range []CreateWorktreeRequest{
+			{Name: "feature-match", Branch: "feature/match"},
+			{Name: "custom-name", Branch: "feature/custom"},
+			{Name: "feature-old", Branch: "feature/old"},
+		} {
+			req.IsNewBranch, req.WorktreeDir = true, worktrees
+			if _, _, err := manager.CreateWorktree(ctx, req); err != nil {
+				t.Fatalf("CreateWorktree(%s) error: %v", req.Name, err)
+			}
+		}
+		runGit(t, filepath.Join(worktrees, "feature-old"), "checkout", "-b", "feature/new")
+
+		list, err := manager.ListWorktrees(ctx)
+		if err != nil {
+			t.Fatalf("ListWorktrees() error: %v", err)
+		}
+		for _, wt := range list {
+			want := wt.Name == "feature-old"
+			if wt.BranchMismatch != want {
+				t.Errorf("%s (on %s) BranchMismatch = %v, want %v", wt.Name, wt.Branch, wt.BranchMismatch, want)
+			}
+		}
+
+		check := doctorCheck(t, manager.Diagnose(ctx), "branch names")
+		if check.Status != CheckWarn || !strings.Contains(check.Detail, "feature-old (on feature/new)") {
+			t.Errorf("branch names = %+v, want warn naming feature-old", check)
+		}
+		if strings.Contains(check.Detail, "feature-match") || strings.Contains(check.Detail, "detached-wt") {
+			t.Errorf("branch names detail = %q, want matching and detached worktrees left out", check.Detail)
+		}
+		if check.Hint != "rename it after its branch with 'gren relocate feature-old', or check the original branch back out" {
+			t.Errorf("branch names hint = %q", check.Hint)
+		}
+
+		plan, err := manager.PlanRelocations(ctx, "feature-old")
+		if err != nil || len(plan) != 1 || filepath.Base(plan[0].To) != "feature-new" {
+			t.Fatalf("PlanRelocations(feature-old) = %+v, %v; want a move to feature-new", plan, err)
+		}
+		if _, err := manager.MoveWorktree(ctx, "feature-old", plan[0].To); err != nil {
+			t.Fatalf("MoveWorktree() error: %v", err)
+		}
+		if HasBranchMismatch(WorktreeInfo{Path: plan[0].To, Branch: "feature/new"}) {
+			t.Error("worktree renamed after its branch is still flagged")
+		}
+	})
+}
@@ -0,0 +1,163 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/logging"
+)
+
+// DefaultEnvFile is the file env_template renders to when env_file is unset.
+const DefaultEnvFile = ".env"
+
+// portPlaceholder matches {{ port }} in an env template, with or without
+// inner spaces. It is only known to env templates, not to hooks.
+var portPlaceholder = regexp.MustCompile(`\{\{\s*port\s*\}\}`)
+
+// envPortLine matches an env assignment whose key mentions PORT and whose
+// value is a plain number, e.g. "PORT=3001" or "export DB_PORT='5433'".
+var envPortLine = regexp.MustCompile(`^\s*(?:export\s+)?[A-Za-z0-9_]*PORT[A-Za-z0-9_]*\s*=\s*["']?(\d+)["']?\s*$`)
+
+// Port range for {{ port }}, the same one the hash_port filter uses.
+const (
+	envPortMin = 10000
+	envPortMax = 19999
+)
+
+// renderEnvTemplate renders the configured env_template into the new
+// worktree's env file, so each worktree gets its own values (ports, database
+// names) instead of a symlink to the main worktree's file. It returns the
+// path written, or "" when no template is configured. An existing env file,
+// e.g. a tracked one, is left alone.
+func (wm *WorktreeManager) renderEnvTemplate(cfg *config.Config, worktreePath, branch string) (string, error) {
+	if cfg.EnvTemplate == "" {
+		return "", nil
+	}
+
+	repoRoot, err := wm.getRepoRoot()
+	if err != nil {
+		return "", err
+	}
+	templatePath := cfg.EnvTemplate
+	if !filepath.IsAbs(templatePath) {
+		templatePath = filepath.Join(repoRoot, templatePath)
+	}
+	data, err := os.ReadFile(templatePath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read env_template: %w", err)
+	}
+
+	envFile := cfg.EnvFile
+	if envFile == "" {
+		envFile = DefaultEnvFile
+	}
+	envPath := filepath.Join(worktreePath, envFile)
+	if _, err := os.Lstat(envPath); err == nil {
+		return "", fmt.Errorf("%s already exists in the worktree; not rendering env_template over it", envFile)
+	}
+
+	content := string(data)
+	if portPlaceholder.MatchString(content) {
+		port, err := allocateEnvPort(envFile, branch)
+		if err != nil {
+			return "", err
+		}
+		content = portPlaceholder.ReplaceAllLiteralString(content, strconv.Itoa(port))
+	}
+
+	commit := wm.getCommitSHA(worktreePath)
+	shortCommit := commit
+	if len(commit) > 7 {
+		shortCommit = commit[:7]
+	}
+	defaultBranch, _ := wm.getDefaultBranch()
+	content = expandTemplate(content, TemplateContext{
+		Branch:          branch,
+		BranchSanitized: cfg.Sanitize.Apply(branch),
+		Worktree:        worktreePath,
+		WorktreeName:    filepath.Base(worktreePath),
+		Repo:            filepath.Base(repoRoot),
+		RepoRoot:        repoRoot,
+		Commit:          commit,
+		ShortCommit:     shortCommit,
+		DefaultBranch:   defaultBranch,
+	})
+
+	if err := os.MkdirAll(filepath.Dir(envPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create directory for %s: %w", envFile, err)
+	}
+	if err := os.WriteFile(envPath, []byte(content), 0600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", envFile, err)
+	}
+	logging.Info("Rendered %s into %s", cfg.EnvTemplate, envPath)
+	return envPath, nil
+}
+
+// allocateEnvPort picks the port for {{ port }} in a new worktree's envFile.
+// It starts from the branch's hash_port, so a recreated worktree tends to get
+// its old port back, and moves up past ports that other worktrees' env files
+// already claim or that something is listening on.
+func allocateEnvPort(envFile, branch string) (int, error) {
+	used := make(map[int]bool)
+	if output, err := exec.Command("git", "worktree", "list", "--porcelain").Output(); err == nil {
+		for _, line := range strings.Split(string(output), "\n") {
+			path, ok := strings.CutPrefix(line, "worktree ")
+			if !ok {
+				continue
+			}
+			for _, port := range envFilePorts(filepath.Join(path, envFile)) {
+				used[port] = true
+			}
+		}
+	}
+
+	start := hashPort(branch)
+	for i := 0; i <= envPortMax-envPortMin; i++ {
+		port := envPortMin + (start-envPortMin+i)%(envPortMax-envPortMin+1)
+		if used[port] || !portFree(port) {
+			continue
+		}
+		logging.Debug("allocateEnvPort: %s gets port %d (%d claimed by other worktrees)", branch, port, len(used))
+		return port, nil
+	}
+	return 0, fmt.Errorf("no free port between %d and %d", envPortMin, envPortMax)
+}
+
+// envFilePorts returns the numeric values of the *PORT* variables in the env
+// file at path. A missing or unreadable file has none.
+func envFilePorts(path string) []int {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	var ports []int
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if m := envPortLine.FindStringSubmatch(scanner.Text()); m != nil {
+			if port, err := strconv.Atoi(m[1]); err == nil {
+				ports = append(ports, port)
+			}
+		}
+	}
+	return ports
+}
+
+// portFree reports whether nothing is listening on the local TCP port.
+func portFree(port int) bool {
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(port)))
+	if err != nil {
+		return false
+	}
+	ln.Close()
+	return true
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// useEnvTemplate points the test environment's config at an env template
+// with the given content.
+func useEnvTemplate(t *testing.T, dir, content string) {
+	t.Helper()
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "env.template"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg := `{
+		"worktree_dir": "` + filepath.Join(filepath.Dir(dir), "test-worktrees") + `",
+		"package_manager": "auto",
+		"version": "1.0.0",
+		"env_template": ".gren/env.template"
+	}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readEnv(t *testing.T, worktreePath string) map[string]string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(worktreePath, ".env"))
+	if err != nil {
+		t.Fatalf("reading rendered .env: %v", err)
+	}
+	env := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if key, value, ok := strings.Cut(line, "="); ok {
+			env[key] = value
+		}
+	}
+	return env
+}
+
+func TestCreateWorktreeRendersEnvTemplate(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	useEnvTemplate(t, dir, "PORT={{ port }}\nDB_NAME=app_{{ branch | sanitize_db }}\nBRANCH={{ branch }}\n")
+
+	ctx := context.Background()
+	first, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "feature/env-a", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	env := readEnv(t, first)
+	if env["DB_NAME"] != "app_feature_env_a" || env["BRANCH"] != "feature/env-a" {
+		t.Errorf("rendered .env = %v, want branch values filled in", env)
+	}
+	firstPort, err := strconv.Atoi(env["PORT"])
+	if err != nil || firstPort < envPortMin || firstPort > envPortMax {
+		t.Fatalf("PORT = %q, want a port in %d-%d", env["PORT"], envPortMin, envPortMax)
+	}
+
+	second, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "feature/env-b", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	env = readEnv(t, second)
+	if env["DB_NAME"] != "app_feature_env_b" {
+		t.Errorf("DB_NAME = %q, want app_feature_env_b", env["DB_NAME"])
+	}
+	if env["PORT"] == strconv.Itoa(firstPort) {
+		t.Errorf("second worktree got PORT %d too, want a different port", firstPort)
+	}
+}
+
+func TestAllocateEnvPortSkipsClaimedAndBusyPorts(t *testing.T) {
+	dir, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	start := hashPort("feat")
+	// The main worktree's .env claims the start port...
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("export PORT='"+strconv.Itoa(start)+"'\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// ...and something is listening on the next one.
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(start+1)))
+	if err != nil {
+		t.Skipf("cannot listen on port %d: %v", start+1, err)
+	}
+	defer ln.Close()
+
+	port, err := allocateEnvPort(".env", "feat")
+	if err != nil {
+		t.Fatalf("allocateEnvPort() error: %v", err)
+	}
+	if port == start || port == start+1 {
+		t.Errorf("allocateEnvPort() = %d, want a port other than claimed %d and busy %d", port, start, start+1)
+	}
+}
+
+func TestRenderEnvTemplateKeepsExistingFile(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	useEnvTemplate(t, dir, "PORT={{ port }}\n")
+
+	worktreePath := filepath.Join(t.TempDir(), "wt")
+	os.MkdirAll(worktreePath, 0755)
+	os.WriteFile(filepath.Join(worktreePath, ".env"), []byte("KEEP=1\n"), 0644)
+
+	cfg, err := manager.configManager.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := manager.renderEnvTemplate(cfg, worktreePath, "keep"); err == nil {
+		t.Error("renderEnvTemplate() over an existing .env = nil error, want refusal")
+	}
+	if env := readEnv(t, worktreePath); env["KEEP"] != "1" {
+		t.Errorf(".env = %v, want the existing file untouched", env)
+	}
+}
@@ -0,0 +1,43 @@
+//go:build !windows
+
+package core
+
+import (
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// setHookProcessGroup runs cmd in a process group of its own and makes
+// cancelling its context kill the whole group, so a timed-out `sh -c "npm
+// install"` takes npm down with it instead of leaving it running. Call it
+// before cmd starts.
+func setHookProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error {
+		return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL)
+	}
+}
+
+// forwardInterrupts passes Ctrl-C on to the process group pid leads, which
+// no longer gets it from the terminal, until the returned stop is called.
+func forwardInterrupts(pid int) (stop func()) {
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt)
+	done := make(chan struct{})
+	go func() {
+		for {
+			select {
+			case <-sigCh:
+				_ = syscall.Kill(-pid, syscall.SIGINT)
+			case <-done:
+				return
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(sigCh)
+		close(done)
+	}
+}
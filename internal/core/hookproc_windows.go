@@ -0,0 +1,15 @@
+//go:build windows
+
+package core
+
+import "os/exec"
+
+// setHookProcessGroup on Windows leaves cmd as is: cancelling its context
+// kills the hook process itself, but not what it started.
+func setHookProcessGroup(cmd *exec.Cmd) {}
+
+// forwardInterrupts has nothing to do on Windows, where the hook shares the
+// console and gets Ctrl-C directly.
+func forwardInterrupts(pid int) (stop func()) {
+	return func() {}
+}
@@ -2,13 +2,16 @@ package core
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -22,8 +25,15 @@ const (
 	hookTailLimit = 64 * 1024          // bytes of hook output kept in memory for logs/UI
 	hookLogKeep   = 20                 // per-run hook logs retained
 	hookLogMaxAge = 7 * 24 * time.Hour // per-run hook log max age
+	// DefaultHookTimeout bounds a non-interactive hook when hook_timeout is
+	// unset.
+	DefaultHookTimeout = 10 * time.Minute
 )
 
+// ErrHookTimedOut is wrapped by the error of a hook killed for running
+// longer than hook_timeout.
+var ErrHookTimedOut = errors.New("hook timed out")
+
 // cappedWriter keeps only the last `limit` bytes written — a bounded tail so
 // hook output can surface in logs/UI without unbounded memory.
 type cappedWriter struct {
@@ -41,6 +51,43 @@ func (w *cappedWriter) Write(p []byte) (int, error) {
 
 func (w *cappedWriter) String() string { return string(w.buf) }
 
+// lineWriter hands each complete line written to it to emit, holding back a
+// trailing partial line until its newline arrives or Flush is called. Of a
+// line redrawn with carriage returns (progress bars) only the final text is
+// passed on, as a terminal would show it.
+type lineWriter struct {
+	emit    func(string)
+	partial []byte
+}
+
+func (w *lineWriter) Write(p []byte) (int, error) {
+	w.partial = append(w.partial, p...)
+	for {
+		i := bytes.IndexByte(w.partial, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		w.emitLine(w.partial[:i])
+		w.partial = w.partial[i+1:]
+	}
+}
+
+// Flush emits the partial line left when the output did not end in a newline.
+func (w *lineWriter) Flush() {
+	if len(w.partial) > 0 {
+		w.emitLine(w.partial)
+		w.partial = nil
+	}
+}
+
+func (w *lineWriter) emitLine(line []byte) {
+	line = bytes.TrimRight(line, "\r")
+	if i := bytes.LastIndexByte(line, '\r'); i >= 0 {
+		line = line[i+1:]
+	}
+	w.emit(string(line))
+}
+
 // HookContext contains all information passed to hooks.
 type HookContext struct {
 	WorktreePath string
@@ -49,6 +96,7 @@ type HookContext struct {
 	RepoRoot     string
 	TargetBranch string // For merge hooks
 	ExecuteCmd   string // For post-start hook
+	Preset       string // For post-create hook: the preset whose hook also runs
 }
 
 // HookJSONContext is the JSON structure sent to hooks via stdin.
@@ -65,6 +113,7 @@ type HookJSONContext struct {
 	TargetBranch  string `json:"target_branch,omitempty"`
 	BaseBranch    string `json:"base_branch,omitempty"`
 	ExecuteCmd    string `json:"execute_cmd,omitempty"`
+	Preset        string `json:"preset,omitempty"`
 }
 
 // HookResult contains the result of running a hook.
@@ -147,7 +196,7 @@ func (wm *WorktreeManager) RunHooksWithApproval(hookType config.HookType, ctx Ho
 	// hooks. CollectHooks also honors each hook's optional branch globs and
 	// skips disabled hooks — so branch-filtered and global hooks now work.
 	userCfg, _ := config.NewUserConfigManager().Load()
-	hooks := config.CollectHooks(cfg, userCfg, hookType, ctx.BranchName)
+	hooks := append(config.CollectHooks(cfg, userCfg, hookType, ctx.BranchName), presetHooks(cfg, hookType, ctx.Preset)...)
 	if len(hooks) == 0 {
 		logging.Debug("RunHooksWithApproval: no %s hooks configured", hookType)
 		return nil
@@ -199,17 +248,64 @@ func (wm *WorktreeManager) RunHooksWithApproval(hookType config.HookType, ctx Ho
 	return results
 }
 
+// hookTimeout returns how long a non-interactive hook may run, from the
+// hook_timeout config, or DefaultHookTimeout when it is unset or the config
+// cannot be loaded. Zero means no limit.
+func (wm *WorktreeManager) hookTimeout() time.Duration {
+	if wm.configManager != nil {
+		if cfg, err := wm.configManager.Load(); err == nil && cfg.HookTimeout != "" {
+			if timeout, err := time.ParseDuration(cfg.HookTimeout); err == nil {
+				return timeout
+			}
+		}
+	}
+	return DefaultHookTimeout
+}
+
 // executeHook runs a single hook command.
 // If interactive is true, the hook runs with terminal access for user input.
+// Otherwise it is killed once it has run for hookTimeout, and its output is
+// passed line by line to the output observer as it arrives.
 func (wm *WorktreeManager) executeHook(hookType config.HookType, hookCmd string, ctx HookContext, hookName string, interactive bool) HookResult {
 	logging.Info("Running %s hook: %s (interactive: %v)", hookType, hookCmd, interactive)
+	interactive = interactive || wm.forceInteractive.Load()
+
+	// Someone is at the terminal of an interactive hook and may take their
+	// time answering it, so only non-interactive hooks are timed out
+	runCtx := context.Background()
+	var timeout time.Duration
+	if !interactive {
+		if timeout = wm.hookTimeout(); timeout > 0 {
+			var cancel context.CancelFunc
+			runCtx, cancel = context.WithTimeout(runCtx, timeout)
+			defer cancel()
+		}
+	}
 
 	var cmd *exec.Cmd
 	var cmdDesc string
 
+	// A script without its execute bit would otherwise be handed to sh as a
+	// command and fail with a bare "permission denied"
+	if isScriptHook(hookCmd) {
+		if info, err := os.Stat(resolveHookPath(hookCmd, ctx.RepoRoot)); err == nil && info.Mode().IsRegular() && lacksExecuteBit(info) {
+			err := fmt.Errorf("hook script %s is not executable (run: chmod +x %s)", hookCmd, hookCmd)
+			logging.Error("%s hook: %v", hookType, err)
+			return HookResult{Ran: true, Err: err, Command: hookCmd, Name: hookName}
+		}
+	}
+
 	if isExecutableScript(hookCmd, ctx.RepoRoot) {
 		fullPath := resolveHookPath(hookCmd, ctx.RepoRoot)
-		cmd = exec.Command(fullPath, ctx.WorktreePath, ctx.BranchName, ctx.BaseBranch, ctx.RepoRoot)
+		// The script runs through its #! line, so it can be written in any
+		// language; check the interpreter exists rather than fail with a bare
+		// "no such file or directory"
+		if problem := hookInterpreterProblem(fullPath); problem != "" {
+			err := fmt.Errorf("hook script %s %s", hookCmd, problem)
+			logging.Error("%s hook: %v", hookType, err)
+			return HookResult{Ran: true, Err: err, Command: hookCmd, Name: hookName}
+		}
+		cmd = exec.CommandContext(runCtx, fullPath, ctx.WorktreePath, ctx.BranchName, ctx.BaseBranch, ctx.RepoRoot)
 		cmdDesc = fmt.Sprintf("%s %s %s %s %s", fullPath, ctx.WorktreePath, ctx.BranchName, ctx.BaseBranch, ctx.RepoRoot)
 	} else {
 		// Expand template variables (e.g. {{ branch | hash_port }}) in inline
@@ -217,10 +313,14 @@ func (wm *WorktreeManager) executeHook(hookType config.HookType, hookCmd string,
 		// shell-quoted so a branch name with shell metacharacters can't inject
 		// commands. Script-file hooks receive context via args + env, unchanged.
 		expandedCmd := expandTemplateShellQuoted(hookCmd, wm.templateContextFromHook(ctx))
-		cmd = exec.Command("sh", "-c", expandedCmd)
+		cmd = exec.CommandContext(runCtx, "sh", "-c", expandedCmd)
 		cmdDesc = expandedCmd
 	}
 
+	// Don't wait on children that outlive a killed hook and hold its output
+	// open
+	cmd.WaitDelay = 5 * time.Second
+
 	// Use worktree path as working directory, but fall back to repo root if it
 	// no longer exists (e.g. for post-remove hooks that run after deletion).
 	if _, statErr := os.Stat(ctx.WorktreePath); statErr == nil {
@@ -336,7 +436,7 @@ func (wm *WorktreeManager) executeHook(hookType config.HookType, hookCmd string,
 	// *where* it failed, not just that it failed.
 	var stdoutBuf, stderrBuf strings.Builder
 	var hookLogPath string
-	if interactive || wm.forceInteractive.Load() {
+	if interactive {
 		// Interactive: run against a real TTY (op / make seed / read all work),
 		// but tee the combined output to a per-run disk log and a capped tail so a
 		// failure leaves a trace even if the pane closes or gren is killed mid-run.
@@ -358,10 +458,22 @@ func (wm *WorktreeManager) executeHook(hookType config.HookType, hookCmd string,
 			logging.PruneHookLogs(hookLogKeep, hookLogMaxAge)
 		}
 	} else {
+		stdoutLines := &lineWriter{emit: wm.emitOutput}
+		stderrLines := &lineWriter{emit: wm.emitOutput}
 		cmd.Stdin = strings.NewReader(string(jsonData))
-		cmd.Stdout = &stdoutBuf
-		cmd.Stderr = &stderrBuf
-		err = cmd.Run()
+		cmd.Stdout = io.MultiWriter(&stdoutBuf, stdoutLines)
+		cmd.Stderr = io.MultiWriter(&stderrBuf, stderrLines)
+		setHookProcessGroup(cmd)
+		if err = cmd.Start(); err == nil {
+			stop := forwardInterrupts(cmd.Process.Pid)
+			err = cmd.Wait()
+			stop()
+		}
+		stdoutLines.Flush()
+		stderrLines.Flush()
+		if runCtx.Err() == context.DeadlineExceeded {
+			err = fmt.Errorf("%w after %s and was killed", ErrHookTimedOut, timeout)
+		}
 	}
 
 	if eventsPath != "" {
@@ -486,6 +598,7 @@ func buildJSONContext(hookType config.HookType, ctx HookContext, wm *WorktreeMan
 		TargetBranch:  ctx.TargetBranch,
 		BaseBranch:    ctx.BaseBranch,
 		ExecuteCmd:    ctx.ExecuteCmd,
+		Preset:        ctx.Preset,
 	}
 }
 
@@ -510,7 +623,7 @@ func (wm *WorktreeManager) templateContextFromHook(ctx HookContext) TemplateCont
 
 	return TemplateContext{
 		Branch:          ctx.BranchName,
-		BranchSanitized: sanitizeBranch(ctx.BranchName),
+		BranchSanitized: wm.sanitizeBranch(ctx.BranchName),
 		Worktree:        ctx.WorktreePath,
 		WorktreeName:    filepath.Base(ctx.WorktreePath),
 		Repo:            filepath.Base(ctx.RepoRoot),
@@ -640,8 +753,72 @@ func FirstFailedHook(results []HookResult) *HookResult {
 	return nil
 }
 
+// HookFailureWarning describes the failed hooks in results, each followed by
+// the last tailLines lines of its stderr and stdout, for commands that carry
+// on after a failing hook and must still tell the user their setup didn't
+// run. It returns "" when no hook failed.
+func HookFailureWarning(hookType config.HookType, results []HookResult, tailLines int) string {
+	var parts []string
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		part := fmt.Sprintf("%s hook failed: %s: %v", hookType, r.Command, r.Err)
+		for _, stream := range []struct{ label, text string }{{"stderr", r.Stderr}, {"stdout", r.Output}} {
+			lines := strings.Split(strings.TrimRight(stream.text, "\n"), "\n")
+			if len(lines) == 1 && lines[0] == "" {
+				continue
+			}
+			if len(lines) > tailLines {
+				lines = lines[len(lines)-tailLines:]
+			}
+			part += "\n  " + stream.label + ":\n    " + strings.Join(lines, "\n    ")
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ErrHooksRequired is returned (wrapped) when a post-create hook fails and
+// the project config sets hooks_required. The worktree is kept.
+var ErrHooksRequired = errors.New("post-create hook failed and hooks_required is set")
+
+// hookFailureTailLines is how many lines of a failed hook's stderr and of its
+// stdout a create warning repeats; the full output is in the log.
+const hookFailureTailLines = 10
+
+// PostCreateHookFailure describes the failed post-create hooks in results,
+// with the end of their output, for the warning of the create that ran
+// them. When cfg sets hooks_required it also returns ErrHooksRequired for
+// the worktree at worktreePath. Both are empty when no hook failed.
+func PostCreateHookFailure(cfg *config.Config, results []HookResult, worktreePath string) (string, error) {
+	warning := HookFailureWarning(config.HookPostCreate, results, hookFailureTailLines)
+	if warning == "" || cfg == nil || !cfg.HooksRequired {
+		return warning, nil
+	}
+	return warning, fmt.Errorf("%w; the worktree is left at %s", ErrHooksRequired, worktreePath)
+}
+
+// hookWarnings describes the failed hooks in results, for steps where a
+// failing hook is reported but doesn't undo the operation.
+func hookWarnings(results []HookResult) []string {
+	var warnings []string
+	for _, r := range results {
+		if r.Err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s hook failed: %v", r.Command, r.Err))
+		}
+	}
+	return warnings
+}
+
+// isScriptHook reports whether hookCmd names a script file, run directly,
+// rather than an inline shell command.
+func isScriptHook(hookCmd string) bool {
+	return !strings.Contains(hookCmd, " ") || strings.HasSuffix(hookCmd, ".sh")
+}
+
 func isExecutableScript(hookCmd, repoRoot string) bool {
-	if strings.Contains(hookCmd, " ") && !strings.HasSuffix(hookCmd, ".sh") {
+	if !isScriptHook(hookCmd) {
 		return false
 	}
 
@@ -654,6 +831,62 @@ func isExecutableScript(hookCmd, repoRoot string) bool {
 	return info.Mode()&0111 != 0
 }
 
+// lacksExecuteBit reports whether the file described by info has no execute
+// bit. Windows has no execute bit, so nothing lacks one there.
+func lacksExecuteBit(info os.FileInfo) bool {
+	return runtime.GOOS != "windows" && info.Mode()&0111 == 0
+}
+
+// hookInterpreterProblem describes why the executable script at path can't
+// be run through its #! line — no #! line, or an interpreter that isn't
+// installed — or returns "" when it can. Binaries, which need no #! line,
+// pass.
+func hookInterpreterProblem(path string) string {
+	if runtime.GOOS == "windows" {
+		return ""
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	head := make([]byte, 512)
+	n, _ := io.ReadFull(f, head)
+	head = head[:n]
+
+	line, ok := bytes.CutPrefix(head, []byte("#!"))
+	if !ok {
+		if bytes.IndexByte(head, 0) >= 0 {
+			return "" // a compiled binary
+		}
+		return "has no #! line naming its interpreter (e.g. #!/usr/bin/env bash)"
+	}
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	}
+	fields := strings.Fields(string(line))
+	if len(fields) == 0 {
+		return "has an empty #! line"
+	}
+
+	interpreter := fields[0]
+	if filepath.Base(interpreter) == "env" {
+		// #!/usr/bin/env [-S] python3 -u: the first operand is the program
+		for _, field := range fields[1:] {
+			if !strings.HasPrefix(field, "-") && !strings.Contains(field, "=") {
+				if _, err := exec.LookPath(field); err != nil {
+					return fmt.Sprintf("needs %s (from its #! line), which is not installed or not on PATH", field)
+				}
+				return ""
+			}
+		}
+	}
+	if info, err := os.Stat(interpreter); err != nil || info.IsDir() || info.Mode()&0111 == 0 {
+		return fmt.Sprintf("needs %s (from its #! line), which does not exist or is not executable", interpreter)
+	}
+	return ""
+}
+
 func resolveHookPath(hookCmd, repoRoot string) string {
 	if filepath.IsAbs(hookCmd) {
 		return hookCmd
@@ -661,14 +894,58 @@ func resolveHookPath(hookCmd, repoRoot string) string {
 	return filepath.Join(repoRoot, hookCmd)
 }
 
+// NonExecutableHookScripts returns the configured hooks of hookType that name
+// an existing script file lacking its execute bit, as configured. Such a
+// hook fails when it runs; MakeHookExecutable fixes it. It is always empty
+// on Windows.
+func (wm *WorktreeManager) NonExecutableHookScripts(hookType config.HookType) []string {
+	cfg, err := wm.configManager.Load()
+	if err != nil {
+		return nil
+	}
+	repoRoot, _ := wm.getRepoRoot()
+
+	var scripts []string
+	for _, hook := range cfg.GetAllHooks(hookType) {
+		if hook.Disabled || !isScriptHook(hook.Command) {
+			continue
+		}
+		info, err := os.Stat(resolveHookPath(hook.Command, repoRoot))
+		if err == nil && info.Mode().IsRegular() && lacksExecuteBit(info) {
+			scripts = append(scripts, hook.Command)
+		}
+	}
+	return scripts
+}
+
+// MakeHookExecutable sets the execute bit on the hook script hookCmd for
+// everyone who can read it, like chmod +x.
+func (wm *WorktreeManager) MakeHookExecutable(hookCmd string) error {
+	repoRoot, _ := wm.getRepoRoot()
+	path := resolveHookPath(hookCmd, repoRoot)
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	mode := info.Mode().Perm()
+	logging.Info("MakeHookExecutable: chmod +x %s", path)
+	return os.Chmod(path, mode|(mode&0444)>>2)
+}
+
 // GetUnapprovedHooks returns a list of hook commands that need approval for the given hook type.
 func (wm *WorktreeManager) GetUnapprovedHooks(hookType config.HookType) []string {
+	return wm.GetUnapprovedHooksWithPreset(hookType, "")
+}
+
+// GetUnapprovedHooksWithPreset is GetUnapprovedHooks including the
+// post-create hook of preset, if it has one.
+func (wm *WorktreeManager) GetUnapprovedHooksWithPreset(hookType config.HookType, preset string) []string {
 	cfg, err := wm.configManager.Load()
 	if err != nil {
 		return nil
 	}
 
-	hooks := cfg.GetAllHooks(hookType)
+	hooks := append(cfg.GetAllHooks(hookType), presetHooks(cfg, hookType, preset)...)
 	if len(hooks) == 0 {
 		return nil
 	}
@@ -689,6 +966,19 @@ func (wm *WorktreeManager) GetUnapprovedHooks(hookType config.HookType) []string
 	return unapproved
 }
 
+// presetHooks returns the hook preset adds for hookType: its post-create
+// hook, run after the configured ones.
+func presetHooks(cfg *config.Config, hookType config.HookType, preset string) []config.NamedHook {
+	if hookType != config.HookPostCreate || preset == "" {
+		return nil
+	}
+	p, err := cfg.Preset(preset)
+	if err != nil || p.PostCreate == "" {
+		return nil
+	}
+	return []config.NamedHook{{Name: "preset " + preset, Command: p.PostCreate}}
+}
+
 // HasInteractiveHooks returns true if any hook of the given type is marked as interactive.
 func (wm *WorktreeManager) HasInteractiveHooks(hookType config.HookType) bool {
 	cfg, err := wm.configManager.Load()
@@ -731,6 +1021,12 @@ func (wm *WorktreeManager) RunPreCreateHookWithApproval(branchName, baseBranch s
 // RunPostCreateHookWithApproval runs the post-create hook with approval checking.
 // Returns the hook results. If autoYes is true, hooks are auto-approved.
 func (wm *WorktreeManager) RunPostCreateHookWithApproval(worktreePath, branchName, baseBranch string, autoYes bool) []HookResult {
+	return wm.RunPostCreateHookWithPreset(worktreePath, branchName, baseBranch, "", autoYes)
+}
+
+// RunPostCreateHookWithPreset runs the post-create hooks followed by the
+// post-create hook of preset, if it has one, with approval checking.
+func (wm *WorktreeManager) RunPostCreateHookWithPreset(worktreePath, branchName, baseBranch, preset string, autoYes bool) []HookResult {
 	repoRoot, _ := wm.getRepoRoot()
 
 	// Ensure absolute path
@@ -744,6 +1040,7 @@ func (wm *WorktreeManager) RunPostCreateHookWithApproval(worktreePath, branchNam
 		BranchName:   branchName,
 		BaseBranch:   baseBranch,
 		RepoRoot:     repoRoot,
+		Preset:       preset,
 	}
 
 	return wm.RunHooksWithApproval(config.HookPostCreate, ctx, autoYes)
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"os"
 	"path/filepath"
 	"strings"
@@ -117,3 +118,32 @@ echo "${app^^}_DB"
 			result.Output, result.Stderr)
 	}
 }
+
+func TestHookFailureWarning(t *testing.T) {
+	results := []HookResult{
+		{Command: "echo ok", Output: "fine\n"},
+		{Command: "setup.sh", Err: errors.New("exit status 3"), Output: "one\ntwo\nthree\n", Stderr: "boom\n"},
+	}
+	want := "post-create hook failed: setup.sh: exit status 3\n  stderr:\n    boom\n  stdout:\n    two\n    three"
+	if got := HookFailureWarning(config.HookPostCreate, results, 2); got != want {
+		t.Errorf("HookFailureWarning() = %q, want %q", got, want)
+	}
+	if got := HookFailureWarning(config.HookPostCreate, results[:1], 2); got != "" {
+		t.Errorf("HookFailureWarning() without failures = %q, want empty", got)
+	}
+}
+
+func TestPostCreateHookFailure(t *testing.T) {
+	results := []HookResult{{Command: "setup.sh", Err: errors.New("exit status 3"), Stderr: "boom\n"}}
+
+	warning, err := PostCreateHookFailure(&config.Config{}, results, "/wt")
+	if !strings.Contains(warning, "boom") || err != nil {
+		t.Errorf("PostCreateHookFailure() = %q, %v; want the hook output and no error", warning, err)
+	}
+	if _, err := PostCreateHookFailure(&config.Config{HooksRequired: true}, results, "/wt"); !errors.Is(err, ErrHooksRequired) {
+		t.Errorf("PostCreateHookFailure(hooks_required) error = %v, want ErrHooksRequired", err)
+	}
+	if warning, err := PostCreateHookFailure(&config.Config{HooksRequired: true}, results[:0], "/wt"); warning != "" || err != nil {
+		t.Errorf("PostCreateHookFailure() without failures = %q, %v; want nothing", warning, err)
+	}
+}
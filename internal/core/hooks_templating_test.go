@@ -32,7 +32,7 @@ func TestExecuteHook_InlineTemplating(t *testing.T) {
 	}
 
 	wantPort := strconv.Itoa(hashPort("feat/my-thing"))
-	wantDB := sanitizeDB("feat/my-thing")
+	wantDB := config.SanitizeDB("feat/my-thing")
 
 	for _, want := range []string{
 		"port=" + wantPort,
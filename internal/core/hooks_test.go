@@ -3,6 +3,10 @@ package core
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/langtind/gren/internal/config"
@@ -604,3 +608,35 @@ func TestPostRemoveHookIsNotFailFast(t *testing.T) {
 		t.Errorf("HookPostRemove (%s) should not be fail-fast", config.HookPostRemove)
 	}
 }
+
+func TestHookInterpreterProblem(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("#! lines are not used on Windows")
+	}
+	dir := t.TempDir()
+
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"sh", "#!/bin/sh\necho ok\n", ""},
+		{"env", "#!/usr/bin/env sh\necho ok\n", ""},
+		{"env with flags", "#!/usr/bin/env -S sh -e\necho ok\n", ""},
+		{"env missing", "#!/usr/bin/env gren-no-such-interpreter\n", "needs gren-no-such-interpreter"},
+		{"absolute missing", "#!/opt/gren-no-such/python3\n", "needs /opt/gren-no-such/python3"},
+		{"no shebang", "echo ok\n", "has no #! line"},
+		{"empty shebang", "#!\n", "has an empty #! line"},
+		{"binary", "\x7fELF\x02\x01\x01\x00\x00", ""},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(dir, strings.ReplaceAll(tt.name, " ", "-"))
+		if err := os.WriteFile(path, []byte(tt.content), 0755); err != nil {
+			t.Fatal(err)
+		}
+		got := hookInterpreterProblem(path)
+		if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+			t.Errorf("%s: hookInterpreterProblem() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
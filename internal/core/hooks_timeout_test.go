@@ -0,0 +1,66 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/langtind/gren/internal/config"
+)
+
+func TestExecuteHook_KilledAfterHookTimeout(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	configContent := `{"worktree_dir": "../wt", "version": "1.0.0", "hook_timeout": "300ms"}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(configContent), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	start := time.Now()
+	ctx := HookContext{WorktreePath: dir, BranchName: "main", RepoRoot: dir}
+	result := manager.executeHook(config.HookPostCreate, "echo started; sleep 10", ctx, "", false)
+
+	if !errors.Is(result.Err, ErrHookTimedOut) {
+		t.Fatalf("Err = %v, want ErrHookTimedOut", result.Err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("hook ran for %s, want it killed after 300ms", elapsed)
+	}
+	if result.Output != "started\n" {
+		t.Errorf("Output = %q, want the output from before the timeout", result.Output)
+	}
+}
+
+func TestExecuteHook_StreamsOutputLines(t *testing.T) {
+	repo := mkRepo(t)
+	t.Setenv("XDG_STATE_HOME", t.TempDir())
+
+	var mu sync.Mutex
+	var lines []string
+	wm := &WorktreeManager{}
+	wm.SetHookOutputObserver(func(line string) {
+		mu.Lock()
+		lines = append(lines, line)
+		mu.Unlock()
+	})
+
+	ctx := HookContext{WorktreePath: repo, BranchName: "main", RepoRoot: repo}
+	result := wm.executeHook(config.HookPostCreate, `echo one; printf '10%%\r100%%\n'; echo oops >&2; printf 'no newline'`, ctx, "", false)
+	if result.Err != nil {
+		t.Fatalf("hook failed: %v", result.Err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(lines)
+	want := []string{"100%", "no newline", "one", "oops"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("observed lines = %q, want %q", lines, want)
+	}
+}
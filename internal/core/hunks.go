@@ -0,0 +1,143 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// Hunk is one "@@" section of a file's diff from the current worktree to a
+// source worktree. Applying it to the current worktree moves that part of the
+// file to the source's version.
+type Hunk struct {
+	Path   string   // Relative path from worktree root
+	Header string   // "@@ -a,b +c,d @@ ..." line
+	Lines  []string // Body lines: " context", "+added", "-removed", "\ No newline..."
+}
+
+// IsNewFile reports whether the hunk creates its file (old range is "-0,0").
+func (h Hunk) IsNewFile() bool {
+	return strings.HasPrefix(h.Header, "@@ -0,0 ")
+}
+
+// ParseHunks splits a unified diff of a single file into hunks. Everything
+// before the first "@@" line (diff/index/---/+++ headers) is dropped; the
+// patch headers are rebuilt from Path when the hunks are applied.
+func ParseHunks(path, diff string) []Hunk {
+	var hunks []Hunk
+	var current *Hunk
+	for _, line := range strings.Split(strings.TrimRight(diff, "\n"), "\n") {
+		if strings.HasPrefix(line, "@@") {
+			hunks = append(hunks, Hunk{Path: path, Header: line})
+			current = &hunks[len(hunks)-1]
+			continue
+		}
+		if current == nil {
+			continue
+		}
+		if line == "" || strings.ContainsRune(" +-\\", rune(line[0])) {
+			current.Lines = append(current.Lines, line)
+		}
+	}
+	return hunks
+}
+
+// DiffHunks diffs relPath between the current and source worktree checkouts
+// and returns its hunks. It reads the files on disk, so committed and
+// uncommitted changes in the source are both picked up. A file missing from
+// the current worktree yields a single new-file hunk.
+func DiffHunks(currentPath, sourcePath, relPath string) ([]Hunk, error) {
+	if err := validatePath(relPath); err != nil {
+		return nil, fmt.Errorf("security error: %w", err)
+	}
+
+	currentFile := filepath.Join(currentPath, relPath)
+	sourceFile := filepath.Join(sourcePath, relPath)
+	if _, err := os.Stat(sourceFile); err != nil {
+		return nil, fmt.Errorf("%s does not exist in source worktree", relPath)
+	}
+	if _, err := os.Stat(currentFile); os.IsNotExist(err) {
+		currentFile = os.DevNull
+	}
+
+	cmd := exec.Command("git", "diff", "--no-index", "--no-color", "--no-ext-diff", "--", currentFile, sourceFile)
+	output, err := cmd.Output()
+	if err != nil {
+		// git diff --no-index exits 1 when the files differ
+		if exitErr, ok := err.(*exec.ExitError); !ok || exitErr.ExitCode() != 1 {
+			return nil, fmt.Errorf("git diff failed for %s: %w", relPath, err)
+		}
+	}
+	return ParseHunks(relPath, string(output)), nil
+}
+
+// buildPatch assembles hunks into a patch that `git apply` accepts, grouping
+// hunks by file in the order given.
+func buildPatch(hunks []Hunk) string {
+	var b strings.Builder
+	lastPath := ""
+	for _, h := range hunks {
+		if h.Path != lastPath {
+			fmt.Fprintf(&b, "diff --git a/%s b/%s\n", h.Path, h.Path)
+			if h.IsNewFile() {
+				b.WriteString("new file mode 100644\n")
+				b.WriteString("--- /dev/null\n")
+			} else {
+				fmt.Fprintf(&b, "--- a/%s\n", h.Path)
+			}
+			fmt.Fprintf(&b, "+++ b/%s\n", h.Path)
+			lastPath = h.Path
+		}
+		b.WriteString(h.Header)
+		b.WriteString("\n")
+		for _, line := range h.Lines {
+			b.WriteString(line)
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// ApplyHunks applies selected hunks from source worktree to the current
+// worktree with `git apply`. Hunks of the same file must be passed together
+// and in diff order. git apply is all-or-nothing, so a hunk that no longer
+// applies leaves the current worktree untouched.
+func (wm *WorktreeManager) ApplyHunks(ctx context.Context, sourceWorktree string, hunks []Hunk) error {
+	if len(hunks) == 0 {
+		return nil
+	}
+
+	for _, h := range hunks {
+		if err := validatePath(h.Path); err != nil {
+			return fmt.Errorf("security error: %w", err)
+		}
+	}
+
+	_, current, err := wm.resolveComparePair(ctx, sourceWorktree, "")
+	if err != nil {
+		return err
+	}
+	currentPath := current.Path
+
+	logging.Info("ApplyHunks: applying %d hunks from %s", len(hunks), sourceWorktree)
+
+	patch := buildPatch(hunks)
+	// Dropping hunks shifts the new-side line numbers of later ones; git apply
+	// locates each hunk by its context, and --recount stops it trusting the
+	// header counts over the bodies.
+	cmd := exec.Command("git", "apply", "--recount", "--whitespace=nowarn", "-")
+	cmd.Dir = currentPath
+	cmd.Stdin = strings.NewReader(patch)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logging.Error("ApplyHunks: git apply failed: %v, output: %s", err, string(output))
+		return fmt.Errorf("failed to apply hunks: %s", strings.TrimSpace(string(output)))
+	}
+
+	logging.Info("ApplyHunks: successfully applied %d hunks", len(hunks))
+	return nil
+}
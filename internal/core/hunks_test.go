@@ -0,0 +1,139 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseHunks(t *testing.T) {
+	diff := `diff --git a/x b/x
+index 123..456 100644
+--- a/x
++++ b/x
+@@ -1,3 +1,3 @@
+-one
++ONE
+ two
+ three
+@@ -10,2 +10,3 @@ func tail()
+ ten
+ eleven
++twelve
+\ No newline at end of file
+`
+	hunks := ParseHunks("x", diff)
+	if len(hunks) != 2 {
+		t.Fatalf("len(hunks) = %d, want 2", len(hunks))
+	}
+	if hunks[0].Header != "@@ -1,3 +1,3 @@" || len(hunks[0].Lines) != 4 {
+		t.Errorf("hunk 0 = %q with %d lines, want header @@ -1,3 +1,3 @@ and 4 lines", hunks[0].Header, len(hunks[0].Lines))
+	}
+	if last := hunks[1].Lines[len(hunks[1].Lines)-1]; !strings.HasPrefix(last, `\ `) {
+		t.Errorf("hunk 1 last line = %q, want no-newline marker kept", last)
+	}
+	for _, h := range hunks {
+		if h.Path != "x" || h.IsNewFile() {
+			t.Errorf("hunk %q: Path = %q, IsNewFile = %v", h.Header, h.Path, h.IsNewFile())
+		}
+	}
+
+	if !(Hunk{Header: "@@ -0,0 +1,2 @@"}).IsNewFile() {
+		t.Error("IsNewFile() for -0,0 hunk = false, want true")
+	}
+}
+
+func TestApplyHunks(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+
+	// 30 lines so the two edits land in separate hunks.
+	var lines []string
+	for i := 1; i <= 30; i++ {
+		lines = append(lines, "line")
+	}
+	lines[0], lines[29] = "first", "last"
+	original := strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(original), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "add", "file.txt")
+	runGit(t, dir, "commit", "-m", "Add file")
+
+	sourcePath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{
+		Name:        "hunk-source",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	// Committed edit at the top, uncommitted edit at the bottom.
+	edited := strings.Replace(original, "first\n", "FIRST\n", 1)
+	os.WriteFile(filepath.Join(sourcePath, "file.txt"), []byte(edited), 0644)
+	runGit(t, sourcePath, "commit", "-am", "Edit top")
+	edited = strings.Replace(edited, "last\n", "LAST\n", 1)
+	os.WriteFile(filepath.Join(sourcePath, "file.txt"), []byte(edited), 0644)
+	os.WriteFile(filepath.Join(sourcePath, "new.txt"), []byte("brand new\n"), 0644)
+
+	hunks, err := DiffHunks(dir, sourcePath, "file.txt")
+	if err != nil {
+		t.Fatalf("DiffHunks() error: %v", err)
+	}
+	if len(hunks) != 2 {
+		t.Fatalf("len(hunks) = %d, want 2", len(hunks))
+	}
+
+	t.Run("applies only the selected hunk", func(t *testing.T) {
+		if err := manager.ApplyHunks(ctx, "hunk-source", hunks[1:]); err != nil {
+			t.Fatalf("ApplyHunks() error: %v", err)
+		}
+		got, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+		if !strings.Contains(string(got), "LAST") {
+			t.Error("selected hunk (LAST) was not applied")
+		}
+		if strings.Contains(string(got), "FIRST") {
+			t.Error("unselected hunk (FIRST) was applied")
+		}
+	})
+
+	t.Run("creates new files", func(t *testing.T) {
+		newHunks, err := DiffHunks(dir, sourcePath, "new.txt")
+		if err != nil {
+			t.Fatalf("DiffHunks() error: %v", err)
+		}
+		if len(newHunks) != 1 || !newHunks[0].IsNewFile() {
+			t.Fatalf("DiffHunks(new.txt) = %+v, want a single new-file hunk", newHunks)
+		}
+		if err := manager.ApplyHunks(ctx, "hunk-source", newHunks); err != nil {
+			t.Fatalf("ApplyHunks() error: %v", err)
+		}
+		got, _ := os.ReadFile(filepath.Join(dir, "new.txt"))
+		if string(got) != "brand new\n" {
+			t.Errorf("new.txt = %q, want %q", got, "brand new\n")
+		}
+	})
+
+	t.Run("leaves file untouched when a hunk no longer applies", func(t *testing.T) {
+		// hunks[1] is already applied, so re-applying it fails.
+		before, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+		if err := manager.ApplyHunks(ctx, "hunk-source", hunks); err == nil {
+			t.Fatal("ApplyHunks() with stale hunk = nil error, want error")
+		}
+		after, _ := os.ReadFile(filepath.Join(dir, "file.txt"))
+		if string(before) != string(after) {
+			t.Error("failed ApplyHunks() modified the file")
+		}
+	})
+
+	t.Run("rejects path traversal", func(t *testing.T) {
+		err := manager.ApplyHunks(ctx, "hunk-source", []Hunk{{Path: "../escape", Header: "@@ -0,0 +1 @@"}})
+		if err == nil || !strings.Contains(err.Error(), "security") {
+			t.Errorf("ApplyHunks() with ../ path error = %v, want security error", err)
+		}
+	})
+}
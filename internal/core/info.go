@@ -0,0 +1,76 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// RepoSummary is gren's view of the repository as a whole, as reported by
+// `gren info`.
+type RepoSummary struct {
+	Name          string // Repository name, from the main worktree (or bare git dir)
+	Root          string // Main worktree; the git dir itself for a bare repo
+	Bare          bool   // Bare repository without a main worktree
+	CurrentBranch string // Branch checked out where gren runs, "" when detached
+	DefaultBranch string // "" when it cannot be determined
+	WorktreeDir   string // Directory new worktrees are created in
+	WorktreeCount int    // All worktrees, the main one included
+	Initialized   bool   // A .gren config exists
+}
+
+// Summary collects repo-level metadata in one call, so scripts and editor
+// plugins need not piece it together from several commands. Like the rest of
+// the manager it resolves the main worktree, so it reports the same repo from
+// any worktree.
+func (wm *WorktreeManager) Summary(ctx context.Context) (*RepoSummary, error) {
+	repoInfo, err := wm.gitRepo.GetRepoInfo(ctx)
+	bareDir := bareGitDir()
+	if err != nil && bareDir == "" {
+		return nil, fmt.Errorf("failed to get repo info: %w", err)
+	}
+	if repoInfo != nil && !repoInfo.IsGitRepo {
+		return nil, fmt.Errorf("not a git repository")
+	}
+
+	root, err := wm.getRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+
+	summary := &RepoSummary{
+		Name: filepath.Base(root),
+		Root: root,
+		Bare: bareDir != "",
+	}
+	if summary.Bare {
+		summary.Name = bareRepoName(bareDir)
+	}
+	if repoInfo != nil {
+		summary.CurrentBranch = repoInfo.CurrentBranch
+		summary.Initialized = repoInfo.IsInitialized
+	} else {
+		summary.Initialized = wm.configManager.Exists()
+	}
+	summary.DefaultBranch, _ = wm.getDefaultBranch()
+
+	cfg, err := wm.configManager.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if summary.WorktreeDir, err = wm.resolveWorktreeDir(ctx, cfg, "", ""); err != nil {
+		return nil, err
+	}
+	summary.WorktreeDir = filepath.Clean(summary.WorktreeDir)
+
+	worktrees, err := wm.ListWorktrees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	summary.WorktreeCount = len(worktrees)
+
+	logging.Debug("Summary: %+v", *summary)
+	return summary, nil
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/git"
+)
+
+func TestSummary(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	summary, err := manager.Summary(context.Background())
+	if err != nil {
+		t.Fatalf("Summary() error: %v", err)
+	}
+
+	resolvedDir, _ := filepath.EvalSymlinks(dir)
+	resolvedRoot, _ := filepath.EvalSymlinks(summary.Root)
+	if resolvedRoot != resolvedDir {
+		t.Errorf("Root = %q, want %q", summary.Root, dir)
+	}
+	if summary.Name != filepath.Base(dir) || summary.Bare {
+		t.Errorf("Name = %q, Bare = %v, want %q, false", summary.Name, summary.Bare, filepath.Base(dir))
+	}
+	if summary.CurrentBranch != "main" || summary.DefaultBranch != "main" {
+		t.Errorf("CurrentBranch = %q, DefaultBranch = %q, want main, main", summary.CurrentBranch, summary.DefaultBranch)
+	}
+	if want := filepath.Join(filepath.Dir(dir), "test-worktrees"); summary.WorktreeDir != want {
+		t.Errorf("WorktreeDir = %q, want configured %q", summary.WorktreeDir, want)
+	}
+	if summary.WorktreeCount != 1 || !summary.Initialized {
+		t.Errorf("WorktreeCount = %d, Initialized = %v, want 1, true", summary.WorktreeCount, summary.Initialized)
+	}
+}
+
+func TestSummaryInBareRepo(t *testing.T) {
+	dir, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	parent, err := os.MkdirTemp("", "gren-bare-parent-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(parent)
+	parent, _ = filepath.EvalSymlinks(parent)
+
+	bareDir := filepath.Join(parent, "project.git")
+	runGit(t, dir, "clone", "--bare", dir, bareDir)
+	if err := os.Chdir(bareDir); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewWorktreeManager(git.NewLocalRepository(), config.NewManager())
+	summary, err := manager.Summary(context.Background())
+	if err != nil {
+		t.Fatalf("Summary() in bare repo error: %v", err)
+	}
+	if !summary.Bare || summary.Name != "project" || summary.Root != bareDir {
+		t.Errorf("Summary() = %+v, want bare project at %q", *summary, bareDir)
+	}
+	if want := filepath.Join(parent, "project-worktrees"); summary.WorktreeDir != want {
+		t.Errorf("WorktreeDir = %q, want %q", summary.WorktreeDir, want)
+	}
+}
@@ -1,6 +1,7 @@
 package core
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"os/exec"
@@ -35,16 +36,24 @@ type LLMGenerator struct {
 	Command  string
 	Args     []string
 	Template string
+
+	generator CommitMessageGenerator
 }
 
 // NewLLMGenerator creates an LLM generator from config
 func NewLLMGenerator(cfg *config.Config) *LLMGenerator {
-	if cfg == nil || cfg.CommitGenerator.Command == "" {
+	if cfg == nil || !cfg.CommitGenerator.Configured() {
+		return nil
+	}
+	gen, err := NewCommitMessageGenerator(cfg.CommitGenerator)
+	if err != nil {
+		logging.Warn("LLM: %v", err)
 		return nil
 	}
 	return &LLMGenerator{
-		Command: cfg.CommitGenerator.Command,
-		Args:    cfg.CommitGenerator.Args,
+		Command:   cfg.CommitGenerator.Command,
+		Args:      cfg.CommitGenerator.Args,
+		generator: gen,
 	}
 }
 
@@ -66,16 +75,12 @@ func (g *LLMGenerator) GenerateCommitMessage(diff, context string) (string, erro
 	// Build prompt
 	prompt := g.buildPrompt(filteredDiff, context)
 
-	// Execute LLM command
-	cmd := exec.Command(g.Command, g.Args...)
-	cmd.Stdin = strings.NewReader(prompt)
-
-	output, err := cmd.Output()
+	output, err := g.generate(prompt)
 	if err != nil {
-		return "", fmt.Errorf("LLM command failed: %w", err)
+		return "", err
 	}
 
-	return cleanCommitMessage(string(output)), nil
+	return cleanCommitMessage(output), nil
 }
 
 // GenerateSquashMessage generates a squash commit message
@@ -99,16 +104,26 @@ func (g *LLMGenerator) GenerateSquashMessage(diff, commitLog, branch, target str
 	// Build prompt
 	prompt := g.buildPrompt(filteredDiff, context)
 
-	// Execute LLM command
-	cmd := exec.Command(g.Command, g.Args...)
-	cmd.Stdin = strings.NewReader(prompt)
+	output, err := g.generate(prompt)
+	if err != nil {
+		return "", err
+	}
+
+	return cleanCommitMessage(output), nil
+}
 
-	output, err := cmd.Output()
+// generate sends prompt to the configured provider, or runs Command
+// directly for a generator built by hand.
+func (g *LLMGenerator) generate(prompt string) (string, error) {
+	gen := g.generator
+	if gen == nil {
+		gen = &CommandGenerator{Command: g.Command, Args: g.Args}
+	}
+	output, err := gen.Generate(context.Background(), prompt)
 	if err != nil {
 		return "", fmt.Errorf("LLM command failed: %w", err)
 	}
-
-	return cleanCommitMessage(string(output)), nil
+	return output, nil
 }
 
 // buildPrompt creates the prompt for the LLM
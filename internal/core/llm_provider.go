@@ -0,0 +1,250 @@
+package core
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/langtind/gren/internal/config"
+)
+
+// CommitMessageGenerator sends a prompt to an LLM and returns its reply. The
+// commit and squash message generators and the AI setup script all go
+// through it, so any provider works for each.
+type CommitMessageGenerator interface {
+	Generate(ctx context.Context, prompt string) (string, error)
+}
+
+// ErrLLMNotConfigured is returned when no LLM is set up in [commit-generation].
+var ErrLLMNotConfigured = errors.New("LLM not configured; set [commit-generation] command or provider in config")
+
+// DefaultOpenAIBaseURL is the API root used when base_url is not set.
+const DefaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+// llmTimeout bounds a single HTTP generation request.
+const llmTimeout = 2 * time.Minute
+
+// CommandGenerator runs a command with the prompt on stdin and returns its
+// output, as for the `llm` CLI or `claude -p`.
+type CommandGenerator struct {
+	Command string
+	Args    []string
+	Dir     string // Working directory; empty means the current one
+}
+
+// Generate runs the command.
+func (g *CommandGenerator) Generate(ctx context.Context, prompt string) (string, error) {
+	cmd := exec.CommandContext(ctx, g.Command, g.Args...)
+	cmd.Dir = g.Dir
+	cmd.Stdin = strings.NewReader(prompt)
+	output, err := cmd.Output()
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(exitErr.Stderr) > 0 {
+			return "", fmt.Errorf("%s failed: %s", g.Command, strings.TrimSpace(string(exitErr.Stderr)))
+		}
+		return "", fmt.Errorf("%s failed: %w", g.Command, err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// NewClaudeGenerator returns a generator running the Claude Code CLI in
+// print mode, with model when set and any extra arguments.
+func NewClaudeGenerator(model string, extraArgs ...string) (*CommandGenerator, error) {
+	path := FindClaudeCLI()
+	if path == "" {
+		return nil, fmt.Errorf("Claude Code CLI not found; install it from https://claude.ai/code or configure another [commit-generation] provider")
+	}
+	args := []string{"-p"}
+	if model != "" {
+		args = append(args, "--model", model)
+	}
+	return &CommandGenerator{Command: path, Args: append(args, extraArgs...)}, nil
+}
+
+// FindClaudeCLI returns the path of the claude binary, looking in PATH and
+// the usual install locations, or "" when it isn't installed.
+func FindClaudeCLI() string {
+	if path, err := exec.LookPath("claude"); err == nil {
+		return path
+	}
+	for _, p := range []string{
+		"/usr/local/bin/claude",
+		os.ExpandEnv("$HOME/.local/bin/claude"),
+		"/opt/homebrew/bin/claude",
+	} {
+		if _, err := os.Stat(p); err == nil {
+			return p
+		}
+	}
+	return ""
+}
+
+// OpenAIGenerator calls an OpenAI-compatible chat completions endpoint:
+// OpenAI itself, or a local server such as Ollama or LM Studio.
+type OpenAIGenerator struct {
+	BaseURL string // API root, e.g. https://api.openai.com/v1
+	Model   string
+	APIKey  string // Sent as a bearer token when set
+	Client  *http.Client
+}
+
+type openAIMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+type openAIRequest struct {
+	Model    string          `json:"model"`
+	Messages []openAIMessage `json:"messages"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message openAIMessage `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// Generate sends prompt as a single user message and returns the first
+// choice.
+func (g *OpenAIGenerator) Generate(ctx context.Context, prompt string) (string, error) {
+	body, err := json.Marshal(openAIRequest{
+		Model:    g.Model,
+		Messages: []openAIMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		return "", err
+	}
+
+	url := strings.TrimRight(g.BaseURL, "/") + "/chat/completions"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if g.APIKey != "" {
+		req.Header.Set("Authorization", "Bearer "+g.APIKey)
+	}
+
+	client := g.Client
+	if client == nil {
+		client = &http.Client{Timeout: llmTimeout}
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("LLM request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("failed to read LLM response: %w", err)
+	}
+	var parsed openAIResponse
+	jsonErr := json.Unmarshal(data, &parsed)
+	if resp.StatusCode != http.StatusOK {
+		if jsonErr == nil && parsed.Error != nil && parsed.Error.Message != "" {
+			return "", fmt.Errorf("LLM request failed (%s): %s", resp.Status, parsed.Error.Message)
+		}
+		msg := strings.TrimSpace(string(data))
+		if len(msg) > 200 {
+			msg = msg[:200] + "..."
+		}
+		return "", fmt.Errorf("LLM request failed (%s): %s", resp.Status, msg)
+	}
+	if jsonErr != nil {
+		return "", fmt.Errorf("failed to parse LLM response: %w", jsonErr)
+	}
+	if len(parsed.Choices) == 0 {
+		return "", fmt.Errorf("LLM response had no choices")
+	}
+	return strings.TrimSpace(parsed.Choices[0].Message.Content), nil
+}
+
+// NewCommitMessageGenerator returns the generator cfg selects.
+func NewCommitMessageGenerator(cfg config.CommitGenerator) (CommitMessageGenerator, error) {
+	if !cfg.Configured() {
+		return nil, ErrLLMNotConfigured
+	}
+	switch cfg.Provider {
+	case "", config.LLMProviderCommand:
+		return &CommandGenerator{Command: cfg.Command, Args: cfg.Args}, nil
+	case config.LLMProviderClaude:
+		return NewClaudeGenerator(cfg.Model, cfg.Args...)
+	case config.LLMProviderOpenAI:
+		if cfg.Model == "" {
+			return nil, fmt.Errorf("[commit-generation] provider openai needs a model")
+		}
+		baseURL := cfg.BaseURL
+		if baseURL == "" {
+			baseURL = DefaultOpenAIBaseURL
+		}
+		keyEnv := cfg.APIKeyEnv
+		if keyEnv == "" {
+			keyEnv = "OPENAI_API_KEY"
+		}
+		apiKey := os.Getenv(keyEnv)
+		// Local servers take no key, but the hosted API always does
+		if apiKey == "" && baseURL == DefaultOpenAIBaseURL {
+			return nil, fmt.Errorf("%s is not set; export the API key or set api_key_env in [commit-generation]", keyEnv)
+		}
+		return &OpenAIGenerator{BaseURL: baseURL, Model: cfg.Model, APIKey: apiKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown [commit-generation] provider %q", cfg.Provider)
+	}
+}
+
+// LLMConfig returns the [commit-generation] settings in effect. Load has
+// already filled them in from the user config when the project sets up no
+// LLM. cfg may be nil, when the config could not be loaded.
+func LLMConfig(cfg *config.Config) config.CommitGenerator {
+	if cfg == nil {
+		return config.CommitGenerator{}
+	}
+	return cfg.CommitGenerator
+}
+
+// NewSetupScriptGenerator returns the generator for the AI setup script. The
+// Claude Code CLI is preferred whenever it's installed, limited to read-only
+// tools so it can explore dir itself; without it, the configured LLM gets
+// the prompt alone.
+func NewSetupScriptGenerator(cfg *config.Config, dir string) (CommitMessageGenerator, error) {
+	llm := LLMConfig(cfg)
+	if FindClaudeCLI() != "" {
+		model := ""
+		if llm.Provider == config.LLMProviderClaude {
+			model = llm.Model
+		}
+		gen, err := NewClaudeGenerator(model,
+			"--allowedTools", "Read", "Glob", "Grep",
+			"Bash(git check-ignore:*)", "Bash(git ls-files:*)", "Bash(ls:*)", "Bash(cat:*)",
+		)
+		if err != nil {
+			return nil, err
+		}
+		gen.Dir = dir
+		return gen, nil
+	}
+	if !llm.Configured() {
+		return nil, fmt.Errorf("Claude Code CLI not found.\n\nInstall it from https://claude.ai/code, or configure another LLM in [commit-generation] (see the README).\n\nAlternatively, choose 'Customize settings' to manually create a setup script.")
+	}
+	gen, err := NewCommitMessageGenerator(llm)
+	if err != nil {
+		return nil, err
+	}
+	if cg, ok := gen.(*CommandGenerator); ok {
+		cg.Dir = dir
+	}
+	return gen, nil
+}
@@ -0,0 +1,111 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/langtind/gren/internal/config"
+)
+
+func TestOpenAIGenerator(t *testing.T) {
+	var got openAIRequest
+	var auth string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chat/completions" {
+			http.NotFound(w, r)
+			return
+		}
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&got)
+		w.Write([]byte(`{"choices":[{"message":{"role":"assistant","content":"  feat: add thing\n"}}]}`))
+	}))
+	defer server.Close()
+
+	gen := &OpenAIGenerator{BaseURL: server.URL + "/v1/", Model: "test-model", APIKey: "secret"}
+	msg, err := gen.Generate(context.Background(), "the prompt")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if msg != "feat: add thing" {
+		t.Errorf("Generate() = %q, want %q", msg, "feat: add thing")
+	}
+	if got.Model != "test-model" || len(got.Messages) != 1 || got.Messages[0].Content != "the prompt" {
+		t.Errorf("request = %+v", got)
+	}
+	if auth != "Bearer secret" {
+		t.Errorf("Authorization = %q, want %q", auth, "Bearer secret")
+	}
+}
+
+func TestOpenAIGeneratorError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Errorf("Authorization sent without an API key")
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"message":"Incorrect API key provided"}}`))
+	}))
+	defer server.Close()
+
+	gen := &OpenAIGenerator{BaseURL: server.URL, Model: "test-model"}
+	_, err := gen.Generate(context.Background(), "prompt")
+	if err == nil || !strings.Contains(err.Error(), "Incorrect API key provided") {
+		t.Errorf("Generate() error = %v, want the API's message", err)
+	}
+}
+
+func TestCommandGenerator(t *testing.T) {
+	gen := &CommandGenerator{Command: "cat"}
+	msg, err := gen.Generate(context.Background(), "fix: echo\n")
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+	if msg != "fix: echo" {
+		t.Errorf("Generate() = %q, want %q", msg, "fix: echo")
+	}
+
+	gen = &CommandGenerator{Command: "sh", Args: []string{"-c", "echo boom >&2; exit 1"}}
+	if _, err := gen.Generate(context.Background(), ""); err == nil || !strings.Contains(err.Error(), "boom") {
+		t.Errorf("Generate() error = %v, want stderr in it", err)
+	}
+}
+
+func TestNewCommitMessageGenerator(t *testing.T) {
+	t.Setenv("GREN_TEST_LLM_KEY", "k")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	tests := []struct {
+		name    string
+		cfg     config.CommitGenerator
+		want    string
+		wantErr bool
+	}{
+		{name: "not configured", cfg: config.CommitGenerator{}, wantErr: true},
+		{name: "command", cfg: config.CommitGenerator{Command: "llm"}, want: "*core.CommandGenerator"},
+		{name: "explicit command", cfg: config.CommitGenerator{Provider: "command", Command: "llm"}, want: "*core.CommandGenerator"},
+		{name: "openai", cfg: config.CommitGenerator{Provider: "openai", Model: "m", APIKeyEnv: "GREN_TEST_LLM_KEY"}, want: "*core.OpenAIGenerator"},
+		{name: "openai without key", cfg: config.CommitGenerator{Provider: "openai", Model: "m"}, wantErr: true},
+		{name: "local server without key", cfg: config.CommitGenerator{Provider: "openai", Model: "m", BaseURL: "http://localhost:11434/v1"}, want: "*core.OpenAIGenerator"},
+		{name: "openai without model", cfg: config.CommitGenerator{Provider: "openai", APIKeyEnv: "GREN_TEST_LLM_KEY"}, wantErr: true},
+		{name: "unknown provider", cfg: config.CommitGenerator{Provider: "gemini"}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen, err := NewCommitMessageGenerator(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewCommitMessageGenerator() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil {
+				if got := fmt.Sprintf("%T", gen); got != tt.want {
+					t.Errorf("NewCommitMessageGenerator() = %s, want %s", got, tt.want)
+				}
+			}
+		})
+	}
+}
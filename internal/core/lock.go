@@ -0,0 +1,175 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// ErrRepoLocked is returned (wrapped) when another gren process holds the
+// repository lock for longer than RepoLockTimeout.
+var ErrRepoLocked = errors.New("repository is locked by another gren process")
+
+// RepoLockTimeout is how long LockRepo waits for another process to release
+// the lock before giving up.
+var RepoLockTimeout = 10 * time.Second
+
+// repoLockPollInterval is how often LockRepo retries while the lock is held.
+const repoLockPollInterval = 100 * time.Millisecond
+
+// RepoLock is an exclusive lock on a repository, held while gren changes the
+// shared worktree list (create, delete, prune, reattach) so that two gren
+// processes, say the TUI and a CLI call from another terminal, don't race.
+// It is an OS file lock on <git-common-dir>/gren/lock, so it is released
+// even when the holder crashes.
+//
+// Within one process the lock is reentrant: DeleteWorktree called from Merge
+// or from a cleanup loop does not wait on itself.
+type RepoLock struct {
+	path string
+}
+
+// heldLocks counts this process's holds per lock path.
+var (
+	heldLocksMu sync.Mutex
+	heldLocks   = map[string]*heldLock{}
+)
+
+type heldLock struct {
+	file  *os.File
+	count int
+}
+
+// LockRepo acquires the repository lock, waiting up to RepoLockTimeout (or
+// until ctx is done) while another process holds it. Release it with
+// Unlock. Outside a git repository it returns a no-op lock, leaving the
+// error to the git command that follows.
+func (wm *WorktreeManager) LockRepo(ctx context.Context) (*RepoLock, error) {
+	path := repoLockPath()
+	if path == "" {
+		return &RepoLock{}, nil
+	}
+
+	if joinHeldLock(path) {
+		return &RepoLock{path: path}, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+
+	// Poll without heldLocksMu, so waiting here doesn't hold up this
+	// process's other lock calls
+	deadline := time.Now().Add(RepoLockTimeout)
+	for {
+		locked, err := tryLockFile(file)
+		if err != nil {
+			file.Close()
+			return nil, fmt.Errorf("failed to lock %s: %w", path, err)
+		}
+		if locked {
+			break
+		}
+		// Another goroutine of this process may have taken it meanwhile
+		if joinHeldLock(path) {
+			file.Close()
+			return &RepoLock{path: path}, nil
+		}
+		if time.Now().After(deadline) {
+			holder := readLockHolder(file)
+			file.Close()
+			return nil, fmt.Errorf("%w%s; try again once it finishes (lock file: %s)", ErrRepoLocked, holder, path)
+		}
+		select {
+		case <-ctx.Done():
+			file.Close()
+			return nil, ctx.Err()
+		case <-time.After(repoLockPollInterval):
+		}
+	}
+
+	// Record the holder for the error message other processes show
+	if err := file.Truncate(0); err == nil {
+		fmt.Fprintf(file, "%d\n%s\n", os.Getpid(), strings.Join(os.Args, " "))
+	}
+	logging.Debug("LockRepo: acquired %s", path)
+	heldLocksMu.Lock()
+	heldLocks[path] = &heldLock{file: file, count: 1}
+	heldLocksMu.Unlock()
+	return &RepoLock{path: path}, nil
+}
+
+// joinHeldLock adds a hold on the lock at path if this process already
+// holds it, and reports whether it did.
+func joinHeldLock(path string) bool {
+	heldLocksMu.Lock()
+	defer heldLocksMu.Unlock()
+	held, ok := heldLocks[path]
+	if ok {
+		held.count++
+	}
+	return ok
+}
+
+// Unlock releases the lock. Unlocking a nil or no-op lock does nothing.
+func (l *RepoLock) Unlock() {
+	if l == nil || l.path == "" {
+		return
+	}
+	heldLocksMu.Lock()
+	defer heldLocksMu.Unlock()
+	held, ok := heldLocks[l.path]
+	if !ok {
+		return
+	}
+	held.count--
+	if held.count > 0 {
+		return
+	}
+	delete(heldLocks, l.path)
+	unlockFile(held.file)
+	held.file.Close()
+	logging.Debug("LockRepo: released %s", l.path)
+}
+
+// repoLockPath returns <git-common-dir>/gren/lock, shared by all worktrees
+// of the repository, or "" outside a git repository.
+func repoLockPath() string {
+	output, err := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	commonDir := strings.TrimSpace(string(output))
+	if commonDir == "" {
+		return ""
+	}
+	return filepath.Join(filepath.Clean(commonDir), "gren", "lock")
+}
+
+// readLockHolder describes the process recorded in the lock file, e.g.
+// " (pid 4242: gren delete feature)", or "" when it is unknown.
+func readLockHolder(file *os.File) string {
+	data := make([]byte, 512)
+	n, _ := file.ReadAt(data, 0)
+	pid, command, _ := strings.Cut(string(data[:n]), "\n")
+	if _, err := strconv.Atoi(pid); err != nil {
+		return ""
+	}
+	if command = strings.TrimSpace(command); command != "" {
+		return fmt.Sprintf(" (pid %s: %s)", pid, command)
+	}
+	return fmt.Sprintf(" (pid %s)", pid)
+}
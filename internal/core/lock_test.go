@@ -0,0 +1,84 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLockRepo(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	lock, err := manager.LockRepo(ctx)
+	if err != nil {
+		t.Fatalf("LockRepo() error: %v", err)
+	}
+	// Reentrant within one process, so DeleteWorktree doesn't wait on Merge
+	inner, err := manager.LockRepo(ctx)
+	if err != nil {
+		t.Fatalf("nested LockRepo() error: %v", err)
+	}
+	inner.Unlock()
+	lock.Unlock()
+
+	// Another process holding the lock: a separate open file description
+	path := filepath.Join(dir, ".git", "gren", "lock")
+	other, err := os.OpenFile(path, os.O_RDWR, 0644)
+	if err != nil {
+		t.Fatalf("lock file not created at %s: %v", path, err)
+	}
+	defer other.Close()
+	if locked, err := tryLockFile(other); !locked || err != nil {
+		t.Fatalf("tryLockFile() = %v, %v; want the lock released by Unlock", locked, err)
+	}
+	other.Truncate(0)
+	other.WriteString("4242\ngren delete feature\n")
+
+	defer func(timeout time.Duration) { RepoLockTimeout = timeout }(RepoLockTimeout)
+	RepoLockTimeout = 200 * time.Millisecond
+
+	if err := manager.DeleteWorktree(ctx, "feature", false); !errors.Is(err, ErrRepoLocked) || !strings.Contains(err.Error(), "pid 4242: gren delete feature") {
+		t.Errorf("DeleteWorktree() while locked = %v, want ErrRepoLocked naming the holder", err)
+	}
+
+	// Released locks are picked up while waiting
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		unlockFile(other)
+	}()
+	lock, err = manager.LockRepo(ctx)
+	if err != nil {
+		t.Fatalf("LockRepo() after release error: %v", err)
+	}
+	lock.Unlock()
+
+	// Waiters in one process don't queue behind each other: both time out
+	// together rather than one after the other
+	if locked, err := tryLockFile(other); !locked || err != nil {
+		t.Fatalf("tryLockFile() = %v, %v", locked, err)
+	}
+	start := time.Now()
+	errs := make(chan error, 2)
+	for range 2 {
+		go func() {
+			lock, err := manager.LockRepo(ctx)
+			lock.Unlock()
+			errs <- err
+		}()
+	}
+	for range 2 {
+		if err := <-errs; !errors.Is(err, ErrRepoLocked) {
+			t.Errorf("LockRepo() while locked = %v, want ErrRepoLocked", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed >= 2*RepoLockTimeout {
+		t.Errorf("two waiters took %s, want them to wait concurrently (timeout %s)", elapsed, RepoLockTimeout)
+	}
+	unlockFile(other)
+}
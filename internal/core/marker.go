@@ -7,6 +7,8 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,25 +24,55 @@ const (
 	MarkerIdle    MarkerType = "💤" // Claude session is idle
 )
 
+// DefaultMarkerTTL is how long a working or waiting marker is believed
+// before it reads as idle, unless marker_ttl says otherwise.
+const DefaultMarkerTTL = 2 * time.Hour
+
 // Marker represents a Claude activity marker for a worktree
 type Marker struct {
 	Branch    string     // Branch name this marker is for
 	Type      MarkerType // Marker type (emoji)
-	Timestamp time.Time  // When the marker was set
+	Timestamp time.Time  // When the marker was set; zero for markers set before timestamps were stored
+}
+
+// Expired reports whether the marker is older than ttl. A ttl of 0, or a
+// marker without a timestamp, never expires.
+func (m Marker) Expired(ttl time.Duration, now time.Time) bool {
+	return ttl > 0 && !m.Timestamp.IsZero() && now.Sub(m.Timestamp) > ttl
+}
+
+// effectiveType is the marker type to show: a working or waiting marker
+// past ttl is left over from a session that crashed or was closed without
+// clearing it, so it reads as idle.
+func (m Marker) effectiveType(ttl time.Duration, now time.Time) MarkerType {
+	if (m.Type == MarkerWorking || m.Type == MarkerWaiting) && m.Expired(ttl, now) {
+		return MarkerIdle
+	}
+	return m.Type
 }
 
 // MarkerManager handles Claude activity markers via git config
 type MarkerManager struct {
 	timeout time.Duration
+	ttl     time.Duration
+	now     func() time.Time
 }
 
-// NewMarkerManager creates a new MarkerManager
+// NewMarkerManager creates a new MarkerManager with DefaultMarkerTTL
 func NewMarkerManager() *MarkerManager {
 	return &MarkerManager{
 		timeout: 5 * time.Second,
+		ttl:     DefaultMarkerTTL,
+		now:     time.Now,
 	}
 }
 
+// SetTTL sets how long working and waiting markers last; 0 keeps them until
+// they are cleared.
+func (mm *MarkerManager) SetTTL(ttl time.Duration) {
+	mm.ttl = ttl
+}
+
 // SetMarker sets a marker for a branch
 func (mm *MarkerManager) SetMarker(ctx context.Context, branch string, markerType MarkerType) error {
 	if branch == "" {
@@ -51,7 +83,8 @@ func (mm *MarkerManager) SetMarker(ctx context.Context, branch string, markerTyp
 	defer cancel()
 
 	key := fmt.Sprintf("gren.marker.%s", sanitizeBranchForConfig(branch))
-	cmd := exec.CommandContext(ctx, "git", "config", "--local", key, string(markerType))
+	value := fmt.Sprintf("%s %d", markerType, mm.now().Unix())
+	cmd := exec.CommandContext(ctx, "git", "config", "--local", key, value)
 	if err := cmd.Run(); err != nil {
 		if ctx.Err() == context.DeadlineExceeded {
 			return fmt.Errorf("git command timed out")
@@ -83,7 +116,8 @@ func (mm *MarkerManager) ClearMarker(ctx context.Context, branch string) error {
 	return nil
 }
 
-// GetMarker gets the marker for a branch
+// GetMarker gets the marker for a branch, reading an expired working or
+// waiting marker as idle
 func (mm *MarkerManager) GetMarker(ctx context.Context, branch string) (MarkerType, error) {
 	if branch == "" {
 		return "", fmt.Errorf("branch name is required")
@@ -102,11 +136,28 @@ func (mm *MarkerManager) GetMarker(ctx context.Context, branch string) (MarkerTy
 		return "", nil
 	}
 
-	return MarkerType(strings.TrimSpace(string(output))), nil
+	marker := parseMarker(branch, strings.TrimSpace(string(output)))
+	return marker.effectiveType(mm.ttl, mm.now()), nil
 }
 
-// ListMarkers lists all markers in the repository
+// ListMarkers lists all markers in the repository by branch, reading
+// expired working and waiting markers as idle
 func (mm *MarkerManager) ListMarkers(ctx context.Context) (map[string]MarkerType, error) {
+	markers, err := mm.Markers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	now := mm.now()
+	types := make(map[string]MarkerType, len(markers))
+	for branch, marker := range markers {
+		types[branch] = marker.effectiveType(mm.ttl, now)
+	}
+	return types, nil
+}
+
+// Markers lists all markers in the repository by branch as stored, with
+// their timestamps and without expiry applied
+func (mm *MarkerManager) Markers(ctx context.Context) (map[string]Marker, error) {
 	ctx, cancel := context.WithTimeout(ctx, mm.timeout)
 	defer cancel()
 
@@ -116,10 +167,10 @@ func (mm *MarkerManager) ListMarkers(ctx context.Context) (map[string]MarkerType
 		if ctx.Err() == context.DeadlineExceeded {
 			return nil, fmt.Errorf("git command timed out")
 		}
-		return make(map[string]MarkerType), nil
+		return make(map[string]Marker), nil
 	}
 
-	markers := make(map[string]MarkerType)
+	markers := make(map[string]Marker)
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
 	for _, line := range lines {
 		if line == "" {
@@ -133,12 +184,48 @@ func (mm *MarkerManager) ListMarkers(ctx context.Context) (map[string]MarkerType
 		value := parts[1]
 		branch := strings.TrimPrefix(key, "gren.marker.")
 		branch = restoreBranchFromConfig(branch)
-		markers[branch] = MarkerType(value)
+		markers[branch] = parseMarker(branch, value)
 	}
 
 	return markers, nil
 }
 
+// ClearExpiredMarkers clears every marker older than the TTL, whatever its
+// type, and returns the branches it cleared
+func (mm *MarkerManager) ClearExpiredMarkers(ctx context.Context) ([]string, error) {
+	markers, err := mm.Markers(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := mm.now()
+	var cleared []string
+	for branch, marker := range markers {
+		if !marker.Expired(mm.ttl, now) {
+			continue
+		}
+		if err := mm.ClearMarker(ctx, branch); err != nil {
+			return cleared, fmt.Errorf("failed to clear marker for %s: %w", branch, err)
+		}
+		cleared = append(cleared, branch)
+	}
+	sort.Strings(cleared)
+	return cleared, nil
+}
+
+// parseMarker parses a stored marker value, "<type> <unix time>". Markers
+// set before timestamps were stored hold just the type.
+func parseMarker(branch, value string) Marker {
+	marker := Marker{Branch: branch, Type: MarkerType(value)}
+	if i := strings.LastIndexByte(value, ' '); i > 0 {
+		if secs, err := strconv.ParseInt(value[i+1:], 10, 64); err == nil {
+			marker.Type = MarkerType(value[:i])
+			marker.Timestamp = time.Unix(secs, 0)
+		}
+	}
+	return marker
+}
+
 // ClearAllMarkers clears all markers in the repository
 func (mm *MarkerManager) ClearAllMarkers(ctx context.Context) error {
 	markers, err := mm.ListMarkers(ctx)
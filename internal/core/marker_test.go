@@ -1,10 +1,13 @@
 package core
 
 import (
+	"context"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 )
 
 func TestParseMarkerType(t *testing.T) {
@@ -251,3 +254,83 @@ func TestBranchEncodingRoundTrip(t *testing.T) {
 		})
 	}
 }
+
+func TestParseMarker(t *testing.T) {
+	tests := []struct {
+		value    string
+		wantType MarkerType
+		wantUnix int64
+	}{
+		{"🤖 1700000000", MarkerWorking, 1700000000},
+		{"💬 1700000000", MarkerWaiting, 1700000000},
+		{"🤖", MarkerWorking, 0},           // set before timestamps were stored
+		{"🔥 hot", MarkerType("🔥 hot"), 0}, // custom marker, no timestamp
+	}
+	for _, tt := range tests {
+		m := parseMarker("feat", tt.value)
+		if m.Type != tt.wantType {
+			t.Errorf("parseMarker(%q).Type = %q, want %q", tt.value, m.Type, tt.wantType)
+		}
+		if tt.wantUnix == 0 && !m.Timestamp.IsZero() || tt.wantUnix != 0 && m.Timestamp.Unix() != tt.wantUnix {
+			t.Errorf("parseMarker(%q).Timestamp = %v, want unix %d", tt.value, m.Timestamp, tt.wantUnix)
+		}
+	}
+}
+
+func TestMarkerExpiry(t *testing.T) {
+	_, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	mm := NewMarkerManager()
+	start := time.Now()
+	mm.now = func() time.Time { return start }
+
+	for branch, marker := range map[string]MarkerType{"work": MarkerWorking, "wait": MarkerWaiting, "idle": MarkerIdle} {
+		if err := mm.SetMarker(ctx, branch, marker); err != nil {
+			t.Fatalf("SetMarker(%s) failed: %v", branch, err)
+		}
+	}
+	// A marker written before timestamps were stored never expires
+	if out, err := exec.Command("git", "config", "--local", "gren.marker.legacy", string(MarkerWorking)).CombinedOutput(); err != nil {
+		t.Fatalf("git config failed: %v\n%s", err, out)
+	}
+
+	if got, _ := mm.GetMarker(ctx, "work"); got != MarkerWorking {
+		t.Errorf("fresh GetMarker(work) = %q, want %q", got, MarkerWorking)
+	}
+
+	mm.now = func() time.Time { return start.Add(DefaultMarkerTTL + time.Minute) }
+
+	want := map[string]MarkerType{"work": MarkerIdle, "wait": MarkerIdle, "idle": MarkerIdle, "legacy": MarkerWorking}
+	markers, err := mm.ListMarkers(ctx)
+	if err != nil {
+		t.Fatalf("ListMarkers failed: %v", err)
+	}
+	for branch, w := range want {
+		if markers[branch] != w {
+			t.Errorf("expired ListMarkers[%s] = %q, want %q", branch, markers[branch], w)
+		}
+	}
+	if got, _ := mm.GetMarker(ctx, "wait"); got != MarkerIdle {
+		t.Errorf("expired GetMarker(wait) = %q, want %q", got, MarkerIdle)
+	}
+
+	mm.SetTTL(0)
+	if got, _ := mm.GetMarker(ctx, "work"); got != MarkerWorking {
+		t.Errorf("GetMarker(work) with TTL 0 = %q, want %q", got, MarkerWorking)
+	}
+	mm.SetTTL(DefaultMarkerTTL)
+
+	cleared, err := mm.ClearExpiredMarkers(ctx)
+	if err != nil {
+		t.Fatalf("ClearExpiredMarkers failed: %v", err)
+	}
+	if strings.Join(cleared, ",") != "idle,wait,work" {
+		t.Errorf("ClearExpiredMarkers cleared %v, want [idle wait work]", cleared)
+	}
+	markers, _ = mm.ListMarkers(ctx)
+	if len(markers) != 1 || markers["legacy"] != MarkerWorking {
+		t.Errorf("markers after ClearExpiredMarkers = %v, want only legacy", markers)
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// MatchingRemoteBranches returns the origin branches matching the glob
+// pattern (e.g. "feature/*"), without the "origin/" prefix. Branches that are
+// already checked out in a worktree are returned separately in skipped.
+// The pattern follows path.Match, so "*" does not cross a "/".
+func (wm *WorktreeManager) MatchingRemoteBranches(ctx context.Context, pattern string) (matches, skipped []string, err error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, nil, fmt.Errorf("invalid pattern '%s': %w", pattern, err)
+	}
+
+	worktrees, err := wm.ListWorktrees(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	existingWorktreeBranches := make(map[string]bool)
+	for _, wt := range worktrees {
+		existingWorktreeBranches[wt.Branch] = true
+	}
+
+	cmd := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/remotes/origin/")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list remote branches: %w", err)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		remoteBranchName := strings.TrimSpace(line)
+		localPart := strings.TrimPrefix(remoteBranchName, "origin/")
+		// Skip empty output and the origin/HEAD pointer
+		if localPart == "" || localPart == "HEAD" || localPart == "origin" {
+			continue
+		}
+		if ok, _ := path.Match(pattern, localPart); !ok {
+			continue
+		}
+		// Check both with and without origin/ prefix
+		if existingWorktreeBranches[remoteBranchName] || existingWorktreeBranches[localPart] {
+			skipped = append(skipped, localPart)
+			continue
+		}
+		matches = append(matches, localPart)
+	}
+
+	logging.Info("MatchingRemoteBranches: pattern=%s matches=%v skipped=%v", pattern, matches, skipped)
+	return matches, skipped, nil
+}
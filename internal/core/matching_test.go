@@ -0,0 +1,58 @@
+package core
+
+import (
+	"context"
+	"os/exec"
+	"reflect"
+	"testing"
+)
+
+func TestMatchingRemoteBranches(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	for _, branch := range []string{"feature/a", "feature/b", "feature/deep/c", "fix/d", "main"} {
+		exec.Command("git", "-C", dir, "update-ref", "refs/remotes/origin/"+branch, "HEAD").Run()
+	}
+	exec.Command("git", "-C", dir, "symbolic-ref", "refs/remotes/origin/HEAD", "refs/remotes/origin/main").Run()
+	exec.Command("git", "-C", dir, "branch", "feature/b").Run()
+
+	if _, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:   "feature/b",
+		Branch: "feature/b",
+	}); err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	tests := []struct {
+		pattern     string
+		wantMatches []string
+		wantSkipped []string
+	}{
+		{"feature/*", []string{"feature/a"}, []string{"feature/b"}},
+		{"fix/*", []string{"fix/d"}, nil},
+		{"*", nil, []string{"main"}},
+		{"nope/*", nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern, func(t *testing.T) {
+			matches, skipped, err := manager.MatchingRemoteBranches(context.Background(), tt.pattern)
+			if err != nil {
+				t.Fatalf("MatchingRemoteBranches() error: %v", err)
+			}
+			if !reflect.DeepEqual(matches, tt.wantMatches) {
+				t.Errorf("matches = %v, want %v", matches, tt.wantMatches)
+			}
+			if !reflect.DeepEqual(skipped, tt.wantSkipped) {
+				t.Errorf("skipped = %v, want %v", skipped, tt.wantSkipped)
+			}
+		})
+	}
+
+	t.Run("invalid pattern", func(t *testing.T) {
+		if _, _, err := manager.MatchingRemoteBranches(context.Background(), "feature/["); err == nil {
+			t.Error("MatchingRemoteBranches() with bad pattern = nil error, want error")
+		}
+	})
+}
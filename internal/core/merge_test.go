@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// commitReadme commits README.md with content on branch, starting it from
+// main, and returns to main.
+func commitReadme(t *testing.T, dir, branch, content string) {
+	t.Helper()
+	runGit(t, dir, "checkout", "-q", "-b", branch, "main")
+	if err := os.WriteFile(filepath.Join(dir, "README.md"), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	runGit(t, dir, "commit", "-q", "-am", "README on "+branch)
+	runGit(t, dir, "checkout", "-q", "main")
+}
+
+func TestMergeIntoCurrentConflicts(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	commitReadme(t, dir, "other", "# Other\n")
+	commitReadme(t, dir, "feature", "# Feature\n")
+	runGit(t, dir, "checkout", "-q", "feature")
+
+	// Left in place without AbortOnConflict
+	result, err := manager.Merge(ctx, MergeOptions{Target: "other", IntoCurrent: true})
+	var conflict *MergeConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("Merge error = %v, want *MergeConflictError", err)
+	}
+	if conflict.Aborted || len(conflict.Files) != 1 || conflict.Files[0] != "README.md" {
+		t.Errorf("conflict = %+v, want README.md, not aborted", conflict)
+	}
+	if result == nil || len(result.Conflicts) != 1 {
+		t.Errorf("result.Conflicts = %v, want [README.md]", result)
+	}
+	if exec.Command("git", "rev-parse", "-q", "--verify", "MERGE_HEAD").Run() != nil {
+		t.Error("merge should be left in progress without AbortOnConflict")
+	}
+	runGit(t, dir, "merge", "--abort")
+
+	// Aborted with AbortOnConflict
+	_, err = manager.Merge(ctx, MergeOptions{Target: "other", IntoCurrent: true, AbortOnConflict: true})
+	if !errors.As(err, &conflict) || !conflict.Aborted {
+		t.Fatalf("Merge error = %v, want an aborted *MergeConflictError", err)
+	}
+	if exec.Command("git", "rev-parse", "-q", "--verify", "MERGE_HEAD").Run() == nil {
+		t.Error("merge should be aborted with AbortOnConflict")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != "# Feature\n" {
+		t.Errorf("README.md = %q after abort, want the feature version", data)
+	}
+}
+
+func TestMergeIntoCurrentClean(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	commitReadme(t, dir, "other", "# Other\n")
+	runGit(t, dir, "checkout", "-q", "-b", "feature")
+
+	result, err := manager.Merge(context.Background(), MergeOptions{Target: "other", IntoCurrent: true})
+	if err != nil {
+		t.Fatalf("Merge failed: %v", err)
+	}
+	if result.SourceBranch != "feature" || result.TargetBranch != "other" || len(result.Conflicts) != 0 {
+		t.Errorf("result = %+v", result)
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, "README.md")); string(data) != "# Other\n" {
+		t.Errorf("README.md = %q, want other's version merged in", data)
+	}
+}
+
+func TestRebaseConflictReportsFiles(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	commitReadme(t, dir, "other", "# Other\n")
+	commitReadme(t, dir, "feature", "# Feature\n")
+	runGit(t, dir, "checkout", "-q", "feature")
+
+	err := manager.rebaseOnto("feature", "other")
+	var conflict *MergeConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("rebaseOnto error = %v, want *MergeConflictError", err)
+	}
+	if !conflict.Aborted || len(conflict.Files) != 1 || conflict.Files[0] != "README.md" {
+		t.Errorf("conflict = %+v, want README.md, aborted", conflict)
+	}
+}
+
+func TestMergeDryRun(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	wtPath := filepath.Join(t.TempDir(), "feature")
+	runGit(t, dir, "worktree", "add", "-q", "-b", "feature", wtPath)
+	for _, name := range []string{"a.txt", "b.txt"} {
+		if err := os.WriteFile(filepath.Join(wtPath, name), []byte(name), 0644); err != nil {
+			t.Fatal(err)
+		}
+		runGit(t, wtPath, "add", name)
+		runGit(t, wtPath, "commit", "-q", "-m", "add "+name)
+	}
+	if err := os.Chdir(wtPath); err != nil {
+		t.Fatal(err)
+	}
+	mainBefore, _ := exec.Command("git", "rev-parse", "main").Output()
+
+	result, err := manager.Merge(context.Background(), MergeOptions{Target: "main", Squash: true, Remove: true, Rebase: true, DryRun: true})
+	if err != nil {
+		t.Fatalf("Merge dry run failed: %v", err)
+	}
+	if !result.DryRun || result.CommitsSquashed != 2 || !result.WorktreeRemoved {
+		t.Errorf("result = %+v, want a dry run squashing 2 commits and removing the worktree", result)
+	}
+	if len(result.Steps) != 4 {
+		t.Errorf("Steps = %q, want squash, rebase, fast-forward and remove", result.Steps)
+	}
+
+	if mainAfter, _ := exec.Command("git", "rev-parse", "main").Output(); string(mainAfter) != string(mainBefore) {
+		t.Error("dry run moved main")
+	}
+	if count, _ := exec.Command("git", "rev-list", "--count", "main..feature").Output(); string(count) != "2\n" {
+		t.Errorf("feature is %q commits ahead after dry run, want 2 unsquashed", count)
+	}
+	if _, err := os.Stat(wtPath); err != nil {
+		t.Errorf("dry run removed the worktree: %v", err)
+	}
+}
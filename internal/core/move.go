@@ -0,0 +1,223 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// ErrWorktreeHasSubmodules is returned by MoveWorktree for a worktree with
+// checked-out submodules, which `git worktree move` refuses: their git
+// files point at the repository by relative path and would break.
+var ErrWorktreeHasSubmodules = errors.New("worktrees with submodules cannot be moved")
+
+// MoveResult describes a worktree moved by MoveWorktree.
+type MoveResult struct {
+	Worktree string   // Worktree directory name before the move
+	From     string   // Old path
+	To       string   // New path
+	Relinked []string // Relative symlinks at the worktree root that were re-pointed, such as .gren
+	Current  bool     // The worktree gren ran in; the shell is now in a deleted directory
+}
+
+// MoveWorktree moves the worktree identified by identifier (name, path or
+// branch) to newPath with `git worktree move`, creating newPath's parent
+// directories. Symlinks at the worktree root with a relative target outside
+// the worktree (a .gren or .env linked from the main worktree) are
+// re-pointed so they still resolve, and gren.previousWorktree follows the
+// move. It refuses the main worktree, locked worktrees, worktrees with
+// checked-out submodules (ErrWorktreeHasSubmodules) and a newPath that is
+// taken. Moving a worktree to where it already is does nothing.
+func (wm *WorktreeManager) MoveWorktree(ctx context.Context, identifier, newPath string) (*MoveResult, error) {
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	worktrees, err := wm.ListWorktreesBasic(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	wt := findWorktree(worktrees, identifier)
+	if wt == nil {
+		return nil, fmt.Errorf("worktree '%s' not found", identifier)
+	}
+	if wt.IsMain {
+		return nil, fmt.Errorf("cannot move the main worktree")
+	}
+
+	to, err := filepath.Abs(newPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve path %s: %w", newPath, err)
+	}
+	result := &MoveResult{Worktree: wt.Name, From: wt.Path, To: to, Current: wt.IsCurrent}
+	if samePath(wt.Path, to) {
+		return result, nil
+	}
+	if err := clearWorktreePath(to, false); err != nil {
+		return nil, err
+	}
+	if rel, err := filepath.Rel(wt.Path, resolveParentSymlinks(to)); err == nil && !leavesDir(rel) {
+		return nil, fmt.Errorf("cannot move worktree '%s' into itself", wt.Name)
+	}
+
+	// Run git and the config update from the main worktree: the current
+	// directory may be the worktree being moved
+	repoRoot, err := wm.getRepoRoot()
+	if err != nil {
+		return nil, err
+	}
+	previous, _ := wm.GetPreviousWorktreePath()
+
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return nil, fmt.Errorf("failed to create %s: %w", filepath.Dir(to), err)
+	}
+	cmd := exec.CommandContext(ctx, "git", "-C", repoRoot, "worktree", "move", wt.Path, to)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(output))
+		if strings.Contains(msg, "containing submodules") {
+			return nil, fmt.Errorf("%w: '%s' has checked-out submodules; recreate it at %s instead", ErrWorktreeHasSubmodules, wt.Name, to)
+		}
+		return nil, fmt.Errorf("failed to move worktree '%s': %s", wt.Name, msg)
+	}
+	logging.Info("MoveWorktree: moved %s from %s to %s", wt.Name, wt.Path, to)
+	if namedFor(filepath.Base(to), wt.Branch) {
+		recordWorktreeBranch(to, wt.Branch)
+	}
+
+	result.Relinked = relinkRelativeSymlinks(wt.Path, to)
+
+	if previous != "" && samePath(previous, wt.Path) {
+		if err := exec.CommandContext(ctx, "git", "-C", repoRoot, "config", "--local", previousWorktreeConfigKey, to).Run(); err != nil {
+			logging.Warn("MoveWorktree: failed to update %s: %v", previousWorktreeConfigKey, err)
+		}
+	}
+	return result, nil
+}
+
+// relinkRelativeSymlinks re-points the symlinks at the root of a worktree
+// moved from oldPath to newPath whose relative targets lead out of the
+// worktree, and so no longer resolve after a move to a different depth.
+// Links within the worktree move with it and are left alone, as are links
+// that were already dangling. It returns the names of the links it changed;
+// failures are only logged, as the move itself has succeeded.
+func relinkRelativeSymlinks(oldPath, newPath string) []string {
+	entries, err := os.ReadDir(newPath)
+	if err != nil {
+		return nil
+	}
+	var relinked []string
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		link := filepath.Join(newPath, entry.Name())
+		target, err := os.Readlink(link)
+		if err != nil || filepath.IsAbs(target) {
+			continue
+		}
+		resolved := filepath.Join(oldPath, target)
+		if rel, err := filepath.Rel(oldPath, resolved); err != nil || !leavesDir(rel) {
+			continue
+		}
+		if _, err := os.Stat(resolved); err != nil {
+			continue
+		}
+		newTarget, err := filepath.Rel(newPath, resolved)
+		if err != nil || newTarget == target {
+			continue
+		}
+		if err := os.Remove(link); err != nil {
+			logging.Warn("relinkRelativeSymlinks: %v", err)
+			continue
+		}
+		if err := os.Symlink(newTarget, link); err != nil {
+			logging.Warn("relinkRelativeSymlinks: %v", err)
+			continue
+		}
+		relinked = append(relinked, entry.Name())
+	}
+	return relinked
+}
+
+// leavesDir reports whether the relative path rel climbs out of the
+// directory it is relative to.
+func leavesDir(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// samePath reports whether a and b name the same location, comparing them
+// with symlinks in their existing parents resolved.
+func samePath(a, b string) bool {
+	return resolveParentSymlinks(filepath.Clean(a)) == resolveParentSymlinks(filepath.Clean(b))
+}
+
+// Relocation is a worktree whose path differs from where worktree_dir would
+// put it today, as planned by PlanRelocations.
+type Relocation struct {
+	Worktree string // Worktree directory name
+	Branch   string
+	From     string // Current path
+	To       string // Path worktree_dir gives for its name and branch
+	Current  bool   // The worktree gren runs in
+}
+
+// PlanRelocations lists the linked worktrees that are not where worktree_dir
+// (with its template expanded for each branch) would create them now, such
+// as after worktree_dir changed, or that HasBranchMismatch finds named for a
+// branch they no longer have. The main worktree and worktrees already in
+// place are left out, and the current worktree comes last so it can be moved
+// after the others. identifiers, when given, restrict the plan to those
+// worktrees.
+func (wm *WorktreeManager) PlanRelocations(ctx context.Context, identifiers ...string) ([]Relocation, error) {
+	worktrees, err := wm.ListWorktreesBasic(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	if len(identifiers) > 0 {
+		var selected []WorktreeInfo
+		for _, identifier := range identifiers {
+			wt := findWorktree(worktrees, identifier)
+			if wt == nil {
+				return nil, fmt.Errorf("worktree '%s' not found", identifier)
+			}
+			selected = append(selected, *wt)
+		}
+		worktrees = selected
+	}
+
+	var plan []Relocation
+	for _, wt := range worktrees {
+		if wt.IsMain || wt.Status == "missing" {
+			continue
+		}
+		branch := wt.Branch
+		if branch == "(detached)" {
+			branch = ""
+		}
+		// A worktree whose branch changed since it was created is renamed
+		// after the branch it has now
+		name := wt.Name
+		if HasBranchMismatch(wt) {
+			name = branch
+		}
+		to, err := wm.WorktreePath(ctx, name, branch)
+		if err != nil {
+			return nil, err
+		}
+		if samePath(wt.Path, to) {
+			continue
+		}
+		plan = append(plan, Relocation{Worktree: wt.Name, Branch: wt.Branch, From: wt.Path, To: to, Current: wt.IsCurrent})
+	}
+	sort.SliceStable(plan, func(i, j int) bool { return !plan[i].Current && plan[j].Current })
+	return plan, nil
+}
@@ -0,0 +1,97 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMoveWorktreeAndPlanRelocations(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	oldPath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{
+		Name:        "relocated",
+		Branch:      "relocated",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	// A relative link into the main worktree, as a post-create hook may make
+	target, _ := filepath.Rel(oldPath, filepath.Join(dir, ".gren"))
+	if err := os.Symlink(target, filepath.Join(oldPath, ".gren")); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.SetPreviousWorktreePath(oldPath); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err := manager.PlanRelocations(ctx)
+	if err != nil {
+		t.Fatalf("PlanRelocations() error: %v", err)
+	}
+	if len(plan) != 0 {
+		t.Fatalf("PlanRelocations() = %+v before worktree_dir changed, want none", plan)
+	}
+
+	// Move worktree_dir one level deeper
+	newDir := filepath.Join(t.TempDir(), "nested", "worktrees")
+	config := `{"worktree_dir": "` + newDir + `", "version": "1.0.0"}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(config), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	plan, err = manager.PlanRelocations(ctx)
+	if err != nil {
+		t.Fatalf("PlanRelocations() error: %v", err)
+	}
+	want := filepath.Join(newDir, "relocated")
+	if len(plan) != 1 || plan[0].Worktree != "relocated" || plan[0].To != want {
+		t.Fatalf("PlanRelocations() = %+v, want relocated to %s", plan, want)
+	}
+
+	result, err := manager.MoveWorktree(ctx, "relocated", plan[0].To)
+	if err != nil {
+		t.Fatalf("MoveWorktree() error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(want, "README.md")); err != nil {
+		t.Errorf("worktree not at %s: %v", want, err)
+	}
+	if _, err := os.Stat(oldPath); !os.IsNotExist(err) {
+		t.Errorf("old path %s still exists", oldPath)
+	}
+	if len(result.Relinked) != 1 || result.Relinked[0] != ".gren" {
+		t.Errorf("Relinked = %v, want [.gren]", result.Relinked)
+	}
+	if _, err := os.Stat(filepath.Join(want, ".gren", "config.json")); err != nil {
+		t.Errorf(".gren link broken after the move: %v", err)
+	}
+	if prev, _ := manager.GetPreviousWorktreePath(); !samePath(prev, want) {
+		t.Errorf("previous worktree = %q, want %q", prev, want)
+	}
+
+	if plan, _ := manager.PlanRelocations(ctx); len(plan) != 0 {
+		t.Errorf("PlanRelocations() = %+v after the move, want none", plan)
+	}
+
+	t.Run("refuses the main worktree", func(t *testing.T) {
+		_, err := manager.MoveWorktree(ctx, filepath.Base(dir), filepath.Join(newDir, "main"))
+		if err == nil || !strings.Contains(err.Error(), "main worktree") {
+			t.Errorf("MoveWorktree() error = %v, want main worktree refusal", err)
+		}
+	})
+
+	t.Run("refuses a taken path", func(t *testing.T) {
+		taken := filepath.Join(newDir, "taken")
+		os.MkdirAll(filepath.Join(taken, "x"), 0755)
+		_, err := manager.MoveWorktree(ctx, "relocated", taken)
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("MoveWorktree() error = %v, want path exists", err)
+		}
+	})
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// operationMarkers maps the files git leaves in a worktree's git dir while an
+// operation is stopped halfway (typically on a conflict) to the operation.
+// Rebase is checked first: an interactive rebase that stops on a conflicting
+// pick also leaves CHERRY_PICK_HEAD behind.
+var operationMarkers = []struct {
+	file      string
+	operation string
+}{
+	{"rebase-merge", "rebase"},
+	{"rebase-apply", "rebase"},
+	{"MERGE_HEAD", "merge"},
+	{"CHERRY_PICK_HEAD", "cherry-pick"},
+	{"REVERT_HEAD", "revert"},
+}
+
+// OperationInProgress reports the git operation ("rebase", "merge",
+// "cherry-pick" or "revert") that is in progress in the worktree at
+// worktreePath, or "" when there is none. Removing such a worktree throws
+// away the operation's state along with any conflict resolution done so far.
+func OperationInProgress(worktreePath string) string {
+	gitDir := worktreeGitDir(worktreePath)
+	if gitDir == "" {
+		return ""
+	}
+	for _, m := range operationMarkers {
+		if _, err := os.Stat(filepath.Join(gitDir, m.file)); err == nil {
+			return m.operation
+		}
+	}
+	return ""
+}
+
+// OperationError explains that operation blocks removing the worktree named
+// name at path, and how to finish or abort it.
+func OperationError(name, path, operation string) error {
+	return fmt.Errorf("worktree '%s' has a %s in progress; finish it or abort it with 'git -C %s %s --abort' before deleting, or force the delete to discard it", name, operation, path, operation)
+}
+
+// worktreeGitDir returns the git dir of the worktree at worktreePath. Linked
+// worktrees have a .git file pointing at .git/worktrees/<name>; reading it
+// avoids running git once per worktree when listing.
+func worktreeGitDir(worktreePath string) string {
+	dotGit := filepath.Join(worktreePath, ".git")
+	info, err := os.Stat(dotGit)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return dotGit
+	}
+
+	data, err := os.ReadFile(dotGit)
+	if err == nil {
+		if dir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir: "); ok {
+			if !filepath.IsAbs(dir) {
+				dir = filepath.Join(worktreePath, dir)
+			}
+			return dir
+		}
+	}
+
+	output, err := exec.Command("git", "-C", worktreePath, "rev-parse", "--absolute-git-dir").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
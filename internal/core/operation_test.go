@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// conflictingWorktree creates a worktree whose branch and main both change
+// README.md, so merging or rebasing one onto the other stops on a conflict.
+func conflictingWorktree(t *testing.T, dir string, manager *WorktreeManager, name string) string {
+	t.Helper()
+
+	worktreePath, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:        name,
+		Branch:      name,
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	os.WriteFile(filepath.Join(worktreePath, "README.md"), []byte("# Branch\n"), 0644)
+	exec.Command("git", "-C", worktreePath, "commit", "-am", "branch change").Run()
+	os.WriteFile(filepath.Join(dir, "README.md"), []byte("# Main\n"), 0644)
+	exec.Command("git", "-C", dir, "commit", "-am", "main change").Run()
+	return worktreePath
+}
+
+func TestOperationInProgress(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	worktreePath := conflictingWorktree(t, dir, manager, "conflicted")
+
+	if got := OperationInProgress(worktreePath); got != "" {
+		t.Fatalf("OperationInProgress() before merge = %q, want empty", got)
+	}
+	if got := OperationInProgress(dir); got != "" {
+		t.Fatalf("OperationInProgress(main) = %q, want empty", got)
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		exec.Command("git", "-C", worktreePath, "merge", "main").Run()
+		if got := OperationInProgress(worktreePath); got != "merge" {
+			t.Errorf("OperationInProgress() = %q, want merge", got)
+		}
+		exec.Command("git", "-C", worktreePath, "merge", "--abort").Run()
+	})
+
+	t.Run("rebase", func(t *testing.T) {
+		exec.Command("git", "-C", worktreePath, "rebase", "main").Run()
+		if got := OperationInProgress(worktreePath); got != "rebase" {
+			t.Errorf("OperationInProgress() = %q, want rebase", got)
+		}
+	})
+
+	t.Run("missing worktree", func(t *testing.T) {
+		if got := OperationInProgress(filepath.Join(dir, "does-not-exist")); got != "" {
+			t.Errorf("OperationInProgress() = %q, want empty", got)
+		}
+	})
+}
+
+func TestDeleteWorktreeOperationInProgress(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	worktreePath := conflictingWorktree(t, dir, manager, "mid-rebase")
+	exec.Command("git", "-C", worktreePath, "rebase", "main").Run()
+
+	err := manager.DeleteWorktree(context.Background(), "mid-rebase", false)
+	if err == nil || !strings.Contains(err.Error(), "rebase in progress") {
+		t.Fatalf("DeleteWorktree() without force = %v, want rebase in progress error", err)
+	}
+	if _, statErr := os.Stat(worktreePath); statErr != nil {
+		t.Fatalf("worktree removed despite refusal: %v", statErr)
+	}
+
+	if err := manager.DeleteWorktree(context.Background(), "mid-rebase", true); err != nil {
+		t.Fatalf("DeleteWorktree() with force error: %v", err)
+	}
+	if _, statErr := os.Stat(worktreePath); !os.IsNotExist(statErr) {
+		t.Errorf("worktree still exists after forced delete")
+	}
+}
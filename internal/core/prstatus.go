@@ -0,0 +1,128 @@
+package core
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+const (
+	// prListLimit caps how many PRs the bulk lookup asks gh for. Worktrees
+	// whose PR is older than the newest prListLimit show no PR.
+	prListLimit = "200"
+	// prCacheTTL is how long a bulk lookup is reused, so running `gren list`
+	// several times in a row hits the GitHub API once.
+	prCacheTTL = time.Minute
+)
+
+// prListEntry is one PR from `gh pr list --json`.
+type prListEntry struct {
+	PRInfo
+	HeadRefName string `json:"headRefName"`
+}
+
+// FetchPRsByBranch returns the repository's PRs keyed by head branch, from a
+// single `gh pr list` call instead of one `gh pr view` per branch. When a
+// branch has several PRs, an open one wins, then the most recent. Results
+// are cached on disk for prCacheTTL, keyed by repo and HEAD commit. It
+// retries transient network failures and returns nil when gh fails.
+func (wm *WorktreeManager) FetchPRsByBranch() map[string]PRInfo {
+	cachePath := wm.prCachePath()
+	if prs, ok := readPRCache(cachePath); ok {
+		logging.Debug("FetchPRsByBranch: using %d cached PRs from %s", len(prs), cachePath)
+		return prs
+	}
+
+	output, err := retryNetwork(context.Background(), wm.networkRetryPolicy(), "FetchPRsByBranch", func() ([]byte, error) {
+		return exec.Command("gh", "pr", "list", "--state", "all", "--limit", prListLimit, "--json", "number,state,url,isDraft,headRefName").Output()
+	})
+	if err != nil {
+		logging.Debug("FetchPRsByBranch: gh pr list failed: %v", err)
+		return nil
+	}
+
+	var entries []prListEntry
+	if err := json.Unmarshal(output, &entries); err != nil {
+		logging.Debug("FetchPRsByBranch: failed to parse PR list: %v", err)
+		return nil
+	}
+
+	// gh lists newest first, so the first PR seen for a branch is its latest
+	prs := make(map[string]PRInfo, len(entries))
+	for _, entry := range entries {
+		if existing, ok := prs[entry.HeadRefName]; ok && (existing.State == "OPEN" || entry.State != "OPEN") {
+			continue
+		}
+		prs[entry.HeadRefName] = entry.PRInfo
+	}
+	logging.Debug("FetchPRsByBranch: %d PRs for %d branches", len(entries), len(prs))
+
+	writePRCache(cachePath, prs)
+	return prs
+}
+
+// prCachePath returns the cache file for the current repo and HEAD commit,
+// or "" when either cannot be determined.
+func (wm *WorktreeManager) prCachePath() string {
+	repoRoot, err := wm.getRepoRoot()
+	if err != nil {
+		return ""
+	}
+	head, err := exec.Command("git", "rev-parse", "HEAD").Output()
+	if err != nil {
+		return ""
+	}
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		cacheDir = os.TempDir()
+	}
+	sum := sha256.Sum256([]byte(repoRoot + "\x00" + strings.TrimSpace(string(head))))
+	return filepath.Join(cacheDir, "gren", "pr-status", hex.EncodeToString(sum[:8])+".json")
+}
+
+// readPRCache returns the cached PRs at path if they are younger than
+// prCacheTTL.
+func readPRCache(path string) (map[string]PRInfo, bool) {
+	if path == "" {
+		return nil, false
+	}
+	info, err := os.Stat(path)
+	if err != nil || time.Since(info.ModTime()) > prCacheTTL {
+		return nil, false
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, false
+	}
+	var prs map[string]PRInfo
+	if err := json.Unmarshal(data, &prs); err != nil {
+		return nil, false
+	}
+	return prs, true
+}
+
+// writePRCache stores prs at path. The cache is an optimization, so
+// failures are only logged.
+func writePRCache(path string, prs map[string]PRInfo) {
+	if path == "" {
+		return
+	}
+	data, err := json.Marshal(prs)
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(path), 0755)
+	}
+	if err == nil {
+		err = os.WriteFile(path, data, 0644)
+	}
+	if err != nil {
+		logging.Debug("writePRCache: %v", err)
+	}
+}
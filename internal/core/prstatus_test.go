@@ -0,0 +1,266 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeGH puts a gh on PATH that answers `gh pr list` with prList and counts
+// its invocations in the returned file.
+func fakeGH(t *testing.T, prList string) string {
+	t.Helper()
+	return fakeGHScript(t, "cat <<'EOF'\n"+prList+"\nEOF\n")
+}
+
+// fakeGHScript puts a gh on PATH that runs the shell script body, after
+// logging its arguments to the returned file.
+func fakeGHScript(t *testing.T, body string) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gh is a shell script")
+	}
+	bin := t.TempDir()
+	calls := filepath.Join(bin, "calls")
+	script := "#!/bin/sh\necho \"$*\" >> '" + calls + "'\n" + body
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	return calls
+}
+
+func TestEnrichWithGitHubStatusUsesOneBulkLookup(t *testing.T) {
+	_, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	calls := fakeGH(t, `[
+  {"number": 7, "state": "OPEN", "url": "https://example.com/7", "isDraft": true, "headRefName": "feat-a"},
+  {"number": 5, "state": "MERGED", "url": "https://example.com/5", "isDraft": false, "headRefName": "feat-b"},
+  {"number": 3, "state": "CLOSED", "url": "https://example.com/3", "isDraft": false, "headRefName": "feat-a"}
+]`)
+
+	worktrees := []WorktreeInfo{
+		{Name: "main", Branch: "main", IsMain: true},
+		{Name: "a", Branch: "feat-a"},
+		{Name: "b", Branch: "feat-b"},
+		{Name: "c", Branch: "feat-c"},
+	}
+	manager.EnrichWithGitHubStatus(worktrees)
+
+	if wt := worktrees[1]; wt.PRNumber != 7 || wt.PRState != "DRAFT" || wt.BranchStatus != "" {
+		t.Errorf("feat-a = PR #%d %s (%q), want the open draft #7, not the older closed PR", wt.PRNumber, wt.PRState, wt.BranchStatus)
+	}
+	if wt := worktrees[2]; wt.PRNumber != 5 || wt.StaleReason != "pr_merged" {
+		t.Errorf("feat-b = PR #%d, stale reason %q, want #5 pr_merged", wt.PRNumber, wt.StaleReason)
+	}
+	if wt := worktrees[3]; wt.PRNumber != 0 {
+		t.Errorf("feat-c = PR #%d, want none", wt.PRNumber)
+	}
+
+	// A second run within the cache TTL must not call gh again
+	manager.EnrichWithGitHubStatus([]WorktreeInfo{{Name: "a", Branch: "feat-a"}})
+
+	data, _ := os.ReadFile(calls)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 1 || !strings.HasPrefix(lines[0], "pr list") {
+		t.Errorf("gh calls = %q, want a single 'pr list'", lines)
+	}
+}
+
+func TestEnrichWithCIStatus(t *testing.T) {
+	_, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// gh exits 1 when checks fail and 8 while they run, but still prints them
+	fakeGHScript(t, `case "$3" in
+failing)
+  echo '[{"name":"lint","state":"SUCCESS","bucket":"pass","link":"https://ci/lint"},{"name":"test","state":"FAILURE","bucket":"fail","link":"https://ci/test"}]'
+  exit 1 ;;
+running)
+  echo '[{"name":"test","state":"IN_PROGRESS","bucket":"pending","link":"https://ci/run"}]'
+  exit 8 ;;
+passing)
+  echo '[{"name":"test","state":"SUCCESS","bucket":"pass","link":"https://ci/ok"}]' ;;
+*)
+  echo "no checks reported on the '$3' branch" >&2
+  exit 1 ;;
+esac
+`)
+
+	withWorkflows := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(withWorkflows, ".github", "workflows"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(withWorkflows, ".github", "workflows", "ci.yml"), []byte("on: push\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees := []WorktreeInfo{
+		{Name: "failing", Branch: "failing", PRNumber: 1, PRURL: "https://pr/1"},
+		{Name: "running", Branch: "running", PRNumber: 2, PRURL: "https://pr/2"},
+		{Name: "passing", Branch: "passing", PRNumber: 3, PRURL: "https://pr/3"},
+		{Name: "starting", Branch: "starting", PRNumber: 4, PRURL: "https://pr/4", Path: withWorkflows},
+		{Name: "no-ci", Branch: "no-ci", PRNumber: 5, PRURL: "https://pr/5", Path: t.TempDir()},
+		{Name: "no-pr", Branch: "no-pr"},
+	}
+	manager.EnrichWithCIStatus(context.Background(), worktrees)
+
+	tests := []struct {
+		status, conclusion, url string
+	}{
+		{"failure", "1 of 2 checks failed", "https://ci/test"},
+		{"pending", "1 of 1 checks in progress", "https://ci/run"},
+		{"success", "All checks passed", "https://pr/3/checks"},
+		{"pending", "Waiting for checks to start", "https://pr/4/checks"},
+		{"none", "No checks reported", ""},
+		{"", "", ""},
+	}
+	for i, tt := range tests {
+		wt := worktrees[i]
+		if wt.CIStatus != tt.status || wt.CIConclusion != tt.conclusion || wt.CIURL != tt.url {
+			t.Errorf("%s = (%q, %q, %q), want (%q, %q, %q)", wt.Name, wt.CIStatus, wt.CIConclusion, wt.CIURL, tt.status, tt.conclusion, tt.url)
+		}
+	}
+}
+
+// slowChecksGH fakes `gh pr checks` taking delay per branch, and records the
+// highest number of calls seen running at once in the returned file.
+func slowChecksGH(t testing.TB, delay string) string {
+	t.Helper()
+	bin := t.(interface{ TempDir() string }).TempDir()
+	running := filepath.Join(bin, "running")
+	peak := filepath.Join(bin, "peak")
+	os.MkdirAll(running, 0755)
+	script := "#!/bin/sh\n" +
+		"touch '" + running + "/'$$\n" +
+		"n=$(ls '" + running + "' | wc -l)\n" +
+		"echo $n >> '" + peak + "'\n" +
+		"sleep " + delay + "\n" +
+		"rm -f '" + running + "/'$$\n" +
+		"echo '[{\"name\":\"test\",\"state\":\"SUCCESS\",\"bucket\":\"pass\",\"link\":\"\"}]'\n"
+	if err := os.WriteFile(filepath.Join(bin, "gh"), []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("PATH", bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	return peak
+}
+
+func prWorktrees(n int) []WorktreeInfo {
+	worktrees := make([]WorktreeInfo, n)
+	for i := range worktrees {
+		worktrees[i] = WorktreeInfo{Name: fmt.Sprintf("wt-%d", i), Branch: fmt.Sprintf("feat-%d", i), PRNumber: i + 1, PRURL: fmt.Sprintf("https://pr/%d", i+1)}
+	}
+	return worktrees
+}
+
+func TestEnrichWithCIStatusConcurrency(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake gh is a shell script")
+	}
+	_, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	t.Run("bounded", func(t *testing.T) {
+		peak := slowChecksGH(t, "0.2")
+		manager.SetGitHubConcurrency(3)
+		defer manager.SetGitHubConcurrency(0)
+
+		worktrees := prWorktrees(9)
+		manager.EnrichWithCIStatus(context.Background(), worktrees)
+		for _, wt := range worktrees {
+			if wt.CIStatus != "success" {
+				t.Errorf("%s CIStatus = %q, want success", wt.Name, wt.CIStatus)
+			}
+		}
+
+		data, _ := os.ReadFile(peak)
+		max := 0
+		for _, line := range strings.Fields(string(data)) {
+			var n int
+			fmt.Sscan(line, &n)
+			if n > max {
+				max = n
+			}
+		}
+		if max > 3 || max < 2 {
+			t.Errorf("peak concurrent gh calls = %d, want 2-3 with a limit of 3", max)
+		}
+	})
+
+	t.Run("cancelled", func(t *testing.T) {
+		slowChecksGH(t, "5")
+		ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+		defer cancel()
+
+		start := time.Now()
+		worktrees := prWorktrees(4)
+		manager.EnrichWithCIStatus(ctx, worktrees)
+		if elapsed := time.Since(start); elapsed > 3*time.Second {
+			t.Errorf("EnrichWithCIStatus took %v after cancellation, want the gh calls killed", elapsed)
+		}
+		for _, wt := range worktrees {
+			if wt.CIStatus != "" {
+				t.Errorf("%s CIStatus = %q, want none after cancellation", wt.Name, wt.CIStatus)
+			}
+		}
+	})
+}
+
+func BenchmarkEnrichWithCIStatus(b *testing.B) {
+	if runtime.GOOS == "windows" {
+		b.Skip("fake gh is a shell script")
+	}
+	slowChecksGH(b, "0.05")
+	manager := NewWorktreeManager(nil, nil)
+	for _, limit := range []int{1, 4, DefaultGitHubConcurrency} {
+		b.Run(fmt.Sprintf("concurrency=%d", limit), func(b *testing.B) {
+			manager.SetGitHubConcurrency(limit)
+			for i := 0; i < b.N; i++ {
+				manager.EnrichWithCIStatus(context.Background(), prWorktrees(16))
+			}
+		})
+	}
+}
+
+func TestCheckGitHubAvailabilityIsCached(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	runGit(t, dir, "remote", "add", "origin", "https://github.com/example/repo.git")
+
+	calls := fakeGHScript(t, "exit 0\n")
+
+	for i := 0; i < 3; i++ {
+		if got := manager.CheckGitHubAvailability(); got != GitHubAvailable {
+			t.Fatalf("CheckGitHubAvailability() = %v, want GitHubAvailable", got)
+		}
+	}
+	data, _ := os.ReadFile(calls)
+	if n := strings.Count(string(data), "auth status"); n != 1 {
+		t.Errorf("gh auth status ran %d times, want 1", n)
+	}
+}
+
+func TestCheckGitHubAvailabilityDisabledByConfig(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	runGit(t, dir, "remote", "add", "origin", "https://github.com/example/repo.git")
+
+	calls := fakeGHScript(t, "exit 0\n")
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.local.toml"), []byte("github = false\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := manager.CheckGitHubAvailability(); got != GitHubUnavailable {
+		t.Errorf("CheckGitHubAvailability() = %v, want GitHubUnavailable with github = false", got)
+	}
+	if _, err := os.Stat(calls); !os.IsNotExist(err) {
+		t.Errorf("gh ran with github = false")
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// PruneOptions configures PruneWorktrees.
+type PruneOptions struct {
+	// Expire only prunes worktrees whose administrative files are older
+	// than this git date expression, e.g. "1.week.ago" or "2024-01-01".
+	// It is passed to `git worktree prune --expire` as is, so git reports
+	// malformed values. Empty prunes every missing worktree.
+	Expire string
+	// DryRun reports what would be pruned without removing anything.
+	DryRun bool
+}
+
+// PrunedWorktree is a worktree registration removed (or, in a dry run, to
+// be removed) by PruneWorktrees.
+type PrunedWorktree struct {
+	Name   string // Administrative name under .git/worktrees
+	Reason string // Why git considers it prunable, e.g. "gitdir file points to non-existent location"
+}
+
+// PruneWorktrees runs `git worktree prune`, removing the registrations of
+// worktrees whose directories no longer exist.
+func (wm *WorktreeManager) PruneWorktrees(ctx context.Context, opts PruneOptions) ([]PrunedWorktree, error) {
+	args := []string{"worktree", "prune", "--verbose"}
+	if opts.DryRun {
+		args = append(args, "--dry-run")
+	}
+	if opts.Expire != "" {
+		args = append(args, "--expire", opts.Expire)
+	}
+
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if repoRoot, err := wm.getRepoRoot(); err == nil {
+		cmd.Dir = repoRoot
+	}
+	logging.Debug("PruneWorktrees: running git %s", strings.Join(args, " "))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		logging.Error("PruneWorktrees: git worktree prune failed: %v, output: %s", err, string(output))
+		if msg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(output)), "fatal:")); msg != "" {
+			return nil, fmt.Errorf("git worktree prune failed: %s", msg)
+		}
+		return nil, fmt.Errorf("git worktree prune failed: %w", err)
+	}
+
+	pruned := parsePruneOutput(string(output))
+	logging.Info("PruneWorktrees: %d worktree(s) pruned (dry-run=%v, expire=%q)", len(pruned), opts.DryRun, opts.Expire)
+	return pruned, nil
+}
+
+// parsePruneOutput parses `git worktree prune --verbose` lines of the form
+// "Removing worktrees/<name>: <reason>".
+func parsePruneOutput(output string) []PrunedWorktree {
+	var pruned []PrunedWorktree
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "Removing ")
+		if !ok {
+			continue
+		}
+		name, reason, _ := strings.Cut(rest, ":")
+		pruned = append(pruned, PrunedWorktree{
+			Name:   strings.TrimPrefix(strings.TrimSpace(name), "worktrees/"),
+			Reason: strings.TrimSpace(reason),
+		})
+	}
+	return pruned
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPruneWorktrees(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	gone := filepath.Join(filepath.Dir(dir), "test-worktrees", "gone")
+	runGit(t, dir, "worktree", "add", "-b", "gone", gone)
+	if err := os.RemoveAll(gone); err != nil {
+		t.Fatal(err)
+	}
+	admin := filepath.Join(dir, ".git", "worktrees", "gone")
+
+	pruned, err := manager.PruneWorktrees(ctx, PruneOptions{Expire: "1.week.ago"})
+	if err != nil || len(pruned) != 0 {
+		t.Fatalf("PruneWorktrees(expire 1.week.ago) = %v, %v; want nothing pruned for a fresh worktree", pruned, err)
+	}
+
+	pruned, err = manager.PruneWorktrees(ctx, PruneOptions{DryRun: true})
+	if err != nil || len(pruned) != 1 || pruned[0].Name != "gone" || pruned[0].Reason == "" {
+		t.Fatalf("PruneWorktrees(dry run) = %+v, %v; want the gone worktree with a reason", pruned, err)
+	}
+	if _, err := os.Stat(admin); err != nil {
+		t.Errorf("dry run removed %s", admin)
+	}
+
+	if pruned, err = manager.PruneWorktrees(ctx, PruneOptions{}); err != nil || len(pruned) != 1 {
+		t.Fatalf("PruneWorktrees() = %+v, %v; want one pruned", pruned, err)
+	}
+	if _, err := os.Stat(admin); !os.IsNotExist(err) {
+		t.Errorf("%s still exists after prune", admin)
+	}
+
+	if _, err := manager.PruneWorktrees(ctx, PruneOptions{Expire: "bogus"}); err == nil || !strings.Contains(err.Error(), "malformed expiration date") {
+		t.Errorf("PruneWorktrees(expire bogus) error = %v, want git's message", err)
+	}
+}
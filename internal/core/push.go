@@ -0,0 +1,130 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// PushOptions controls PushWorktree.
+type PushOptions struct {
+	// Remote to push to. Empty means the remote the branch tracks, or
+	// "origin" for a branch without an upstream.
+	Remote string
+	// SetUpstream makes <remote>/<branch> the upstream even when the branch
+	// already tracks something else. A branch without an upstream gets one
+	// on its first push regardless.
+	SetUpstream bool
+	// ForceWithLease overwrites the remote branch, but only if it is still
+	// where this clone last saw it (git push --force-with-lease), as after a
+	// rebase.
+	ForceWithLease bool
+}
+
+// PushResult describes what PushWorktree pushed.
+type PushResult struct {
+	Worktree    string // Worktree directory name
+	Branch      string // Local branch pushed
+	Target      string // Remote branch pushed to, e.g. "origin/feat-x"
+	NewUpstream bool   // The push made Target the upstream; it tracked nothing or something else
+	Commits     int    // Commits the remote branch didn't have
+	Forced      bool   // Pushed with --force-with-lease
+}
+
+// PushWorktree pushes the branch checked out in wt, running git in the
+// worktree so the caller doesn't have to be there. A branch without an
+// upstream is pushed to opts.Remote (default origin) and set to track it;
+// otherwise it goes to the branch it tracks.
+func (wm *WorktreeManager) PushWorktree(ctx context.Context, wt *WorktreeInfo, opts PushOptions) (*PushResult, error) {
+	if wt.IsBare {
+		return nil, fmt.Errorf("'%s' is the bare repository, which has no branch to push", wt.Name)
+	}
+	if wt.Branch == "" || wt.Branch == "(detached)" || wt.Branch == "(bare)" {
+		return nil, fmt.Errorf("worktree '%s' has no branch checked out", wt.Name)
+	}
+
+	upstream := GetUpstream(wt.Path)
+	trackedRemote := gitConfigValue(wt.Path, "branch."+wt.Branch+".remote")
+	remote := opts.Remote
+	if remote == "" {
+		remote = trackedRemote
+	}
+	if remote == "" || remote == "." {
+		remote = "origin"
+	}
+	if exec.CommandContext(ctx, "git", "-C", wt.Path, "remote", "get-url", remote).Run() != nil {
+		return nil, fmt.Errorf("no remote named '%s'; add one with 'git remote add %s <url>'", remote, remote)
+	}
+
+	// Pushing to the tracked remote goes to the tracked branch, which may be
+	// named differently, unless the push is to set a new upstream
+	remoteBranch := wt.Branch
+	setUpstream := opts.SetUpstream || upstream == ""
+	if !setUpstream && remote == trackedRemote {
+		if merge := gitConfigValue(wt.Path, "branch."+wt.Branch+".merge"); merge != "" {
+			remoteBranch = strings.TrimPrefix(merge, "refs/heads/")
+		}
+	}
+
+	result := &PushResult{
+		Worktree: wt.Name,
+		Branch:   wt.Branch,
+		Target:   remote + "/" + remoteBranch,
+		Forced:   opts.ForceWithLease,
+	}
+	result.NewUpstream = setUpstream && result.Target != upstream
+	result.Commits = countUnpushed(ctx, wt.Path, remote, remoteBranch)
+
+	args := []string{"-C", wt.Path, "push"}
+	if setUpstream {
+		args = append(args, "--set-upstream")
+	}
+	if opts.ForceWithLease {
+		args = append(args, "--force-with-lease")
+	}
+	args = append(args, remote, "HEAD:refs/heads/"+remoteBranch)
+
+	logging.Info("PushWorktree: %s → %s (set-upstream=%v, force-with-lease=%v)", wt.Branch, result.Target, setUpstream, opts.ForceWithLease)
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(output))
+		if !opts.ForceWithLease && (strings.Contains(msg, "non-fast-forward") || strings.Contains(msg, "fetch first")) {
+			return nil, fmt.Errorf("push of %s rejected: %s has commits this branch lacks; pull them, or use --force-with-lease to overwrite after a rebase", wt.Branch, result.Target)
+		}
+		return nil, fmt.Errorf("failed to push %s to %s: %s", wt.Branch, result.Target, msg)
+	}
+
+	logging.Info("PushWorktree: pushed %d commit(s) of %s", result.Commits, wt.Branch)
+	return result, nil
+}
+
+// countUnpushed counts the commits at HEAD in worktreePath that
+// <remote>/<branch> lacks, or, when this clone has never seen that branch,
+// that no branch of remote has.
+func countUnpushed(ctx context.Context, worktreePath, remote, branch string) int {
+	ref := "refs/remotes/" + remote + "/" + branch
+	args := []string{"-C", worktreePath, "rev-list", "--count", "HEAD", "--not", "--remotes=" + remote}
+	if exec.CommandContext(ctx, "git", "-C", worktreePath, "show-ref", "--verify", "--quiet", ref).Run() == nil {
+		args = []string{"-C", worktreePath, "rev-list", "--count", ref + "..HEAD"}
+	}
+	output, err := exec.CommandContext(ctx, "git", args...).Output()
+	if err != nil {
+		return 0
+	}
+	n, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+	return n
+}
+
+// gitConfigValue returns the git config value of key as seen from dir, or ""
+// when it is unset.
+func gitConfigValue(dir, key string) string {
+	output, err := exec.Command("git", "-C", dir, "config", "--get", key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
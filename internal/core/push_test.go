@@ -0,0 +1,93 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPushWorktree(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	remoteDir := t.TempDir()
+	exec.Command("git", "-C", remoteDir, "init", "--bare").Run()
+	exec.Command("git", "-C", dir, "remote", "add", "origin", remoteDir).Run()
+	exec.Command("git", "-C", dir, "push", "-u", "origin", "HEAD").Run()
+
+	ctx := context.Background()
+	worktreePath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{
+		Name:        "push-me",
+		Branch:      "push-me",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	wt := &WorktreeInfo{Name: "push-me", Branch: "push-me", Path: worktreePath}
+
+	commit := func(file string) {
+		t.Helper()
+		os.WriteFile(filepath.Join(worktreePath, file), []byte(file), 0644)
+		runGit(t, worktreePath, "add", file)
+		runGit(t, worktreePath, "commit", "-m", "add "+file)
+	}
+
+	t.Run("first push sets the upstream", func(t *testing.T) {
+		commit("a.txt")
+		commit("b.txt")
+		result, err := manager.PushWorktree(ctx, wt, PushOptions{})
+		if err != nil {
+			t.Fatalf("PushWorktree() error: %v", err)
+		}
+		if result.Target != "origin/push-me" || !result.NewUpstream || result.Commits != 2 {
+			t.Errorf("PushWorktree() = %+v, want 2 commits to origin/push-me with a new upstream", result)
+		}
+		if got := GetUpstream(worktreePath); got != "origin/push-me" {
+			t.Errorf("GetUpstream() = %q, want origin/push-me", got)
+		}
+	})
+
+	t.Run("later push goes to the upstream", func(t *testing.T) {
+		commit("c.txt")
+		result, err := manager.PushWorktree(ctx, wt, PushOptions{})
+		if err != nil {
+			t.Fatalf("PushWorktree() error: %v", err)
+		}
+		if result.NewUpstream || result.Commits != 1 {
+			t.Errorf("PushWorktree() = %+v, want 1 commit and the upstream kept", result)
+		}
+	})
+
+	t.Run("rewritten history needs force-with-lease", func(t *testing.T) {
+		runGit(t, worktreePath, "commit", "--amend", "-m", "reworded")
+		_, err := manager.PushWorktree(ctx, wt, PushOptions{})
+		if err == nil || !strings.Contains(err.Error(), "--force-with-lease") {
+			t.Fatalf("PushWorktree() error = %v, want a rejection suggesting --force-with-lease", err)
+		}
+		result, err := manager.PushWorktree(ctx, wt, PushOptions{ForceWithLease: true})
+		if err != nil {
+			t.Fatalf("PushWorktree(ForceWithLease) error: %v", err)
+		}
+		if !result.Forced {
+			t.Errorf("result.Forced = false, want true")
+		}
+	})
+
+	t.Run("unknown remote", func(t *testing.T) {
+		if _, err := manager.PushWorktree(ctx, wt, PushOptions{Remote: "nope"}); err == nil || !strings.Contains(err.Error(), "no remote named") {
+			t.Errorf("PushWorktree(Remote: nope) error = %v, want no remote named", err)
+		}
+	})
+
+	t.Run("detached worktree", func(t *testing.T) {
+		detached := &WorktreeInfo{Name: "push-me", Branch: "(detached)", Path: worktreePath}
+		if _, err := manager.PushWorktree(ctx, detached, PushOptions{}); err == nil {
+			t.Error("PushWorktree() on a detached worktree = nil error, want error")
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// ReattachResult describes the branch created by Reattach.
+type ReattachResult struct {
+	Worktree string // Worktree directory name
+	Branch   string // New branch, now checked out in the worktree
+	Commit   string // Short hash of the detached HEAD the branch points at
+}
+
+// Reattach creates branch at the detached HEAD of the worktree identified by
+// identifier (name or path) and checks it out in place, so the worktree tracks
+// a branch again without being recreated. It refuses worktrees that already
+// have a branch, invalid branch names, and branches that already exist.
+func (wm *WorktreeManager) Reattach(ctx context.Context, identifier, branch string) (*ReattachResult, error) {
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	worktrees, err := wm.ListWorktrees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	wt := findWorktree(worktrees, identifier)
+	if wt == nil {
+		return nil, fmt.Errorf("worktree '%s' not found", identifier)
+	}
+	if wt.Branch != "(detached)" {
+		return nil, fmt.Errorf("worktree '%s' is not detached (on branch '%s')", wt.Name, wt.Branch)
+	}
+
+	if err := exec.Command("git", "check-ref-format", "--branch", branch).Run(); err != nil {
+		return nil, fmt.Errorf("invalid branch name '%s'", branch)
+	}
+	if exec.Command("git", "-C", wt.Path, "show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() == nil {
+		return nil, fmt.Errorf("branch '%s' already exists; choose another name or check it out with 'git -C %s switch %s'", branch, wt.Path, branch)
+	}
+
+	headCmd := exec.Command("git", "-C", wt.Path, "rev-parse", "--short", "HEAD")
+	head, err := headCmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve HEAD in %s: %w", wt.Name, err)
+	}
+
+	// git switch -c keeps uncommitted changes, so nothing in the worktree is lost
+	switchCmd := exec.Command("git", "-C", wt.Path, "switch", "-c", branch)
+	if output, err := switchCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to create branch '%s': %s", branch, strings.TrimSpace(string(output)))
+	}
+
+	result := &ReattachResult{
+		Worktree: wt.Name,
+		Branch:   branch,
+		Commit:   strings.TrimSpace(string(head)),
+	}
+	logging.Info("Reattach: %s now on new branch %s at %s", wt.Name, branch, result.Commit)
+	return result, nil
+}
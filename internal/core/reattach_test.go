@@ -0,0 +1,72 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReattach(t *testing.T) {
+	_, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	worktreePath, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:        "detached-wt",
+		Branch:      "detached-wt",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	t.Run("refuses a worktree on a branch", func(t *testing.T) {
+		_, err := manager.Reattach(context.Background(), "detached-wt", "rescued")
+		if err == nil || !strings.Contains(err.Error(), "not detached") {
+			t.Fatalf("Reattach() error = %v, want not detached", err)
+		}
+	})
+
+	if out, err := exec.Command("git", "-C", worktreePath, "switch", "--detach").CombinedOutput(); err != nil {
+		t.Fatalf("git switch --detach: %v: %s", err, out)
+	}
+	// Uncommitted work must survive the reattach
+	if err := os.WriteFile(filepath.Join(worktreePath, "wip.txt"), []byte("wip\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("refuses an existing branch", func(t *testing.T) {
+		_, err := manager.Reattach(context.Background(), "detached-wt", "main")
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Fatalf("Reattach() error = %v, want already exists", err)
+		}
+	})
+
+	t.Run("refuses an invalid branch name", func(t *testing.T) {
+		_, err := manager.Reattach(context.Background(), "detached-wt", "bad..name")
+		if err == nil || !strings.Contains(err.Error(), "invalid branch name") {
+			t.Fatalf("Reattach() error = %v, want invalid branch name", err)
+		}
+	})
+
+	t.Run("creates and checks out the branch", func(t *testing.T) {
+		result, err := manager.Reattach(context.Background(), "detached-wt", "rescued")
+		if err != nil {
+			t.Fatalf("Reattach() error: %v", err)
+		}
+		if result.Branch != "rescued" || result.Commit == "" {
+			t.Errorf("result = %+v, want branch rescued with a commit", result)
+		}
+
+		out, err := exec.Command("git", "-C", worktreePath, "symbolic-ref", "--short", "HEAD").Output()
+		if err != nil || strings.TrimSpace(string(out)) != "rescued" {
+			t.Errorf("HEAD = %q (err %v), want rescued", strings.TrimSpace(string(out)), err)
+		}
+		if _, err := os.Stat(filepath.Join(worktreePath, "wip.txt")); err != nil {
+			t.Errorf("uncommitted file lost: %v", err)
+		}
+	})
+}
@@ -0,0 +1,264 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// RemoveResult describes what RemoveWorktree did on the way to removing a
+// worktree.
+type RemoveResult struct {
+	ExternalSymlinks []string          // Untracked root symlinks to existing targets outside the worktree, removed first
+	DanglingSymlinks []DanglingSymlink // Symlinks whose targets are gone, removed first
+	Submodules       bool              // Submodules were deinitialized first
+	Forced           bool              // git worktree remove ran with --force
+	Fallback         bool              // git refused even with --force; the directory was deleted and the worktree pruned
+}
+
+// RemoveError is returned by RemoveWorktree when the worktree is left in
+// place. Error gives the full message with git's output and a hint; Reason
+// is a short cause for lists such as the TUI cleanup.
+type RemoveError struct {
+	Worktree string
+	Reason   string // e.g. "has uncommitted changes", "locked (git worktree unlock it first)"
+	Output   string // git's output, when git ran and failed
+	Hint     string // What to do about it
+	Err      error
+}
+
+func (e *RemoveError) Error() string {
+	msg := e.Err.Error()
+	if e.Output != "" {
+		msg += "\n\nOutput: " + e.Output
+	}
+	if e.Hint != "" {
+		msg += "\n\n" + e.Hint
+	}
+	return msg
+}
+
+func (e *RemoveError) Unwrap() error { return e.Err }
+
+// RemoveWorktree removes wt from disk and from git, the one removal path
+// behind DeleteWorktree, the TUI delete and the TUI cleanup. It refuses what
+// checkDeletable refuses, and without force a worktree with an operation in
+// progress or a lock. Hooks, confirmation and the branch are the caller's.
+func (wm *WorktreeManager) RemoveWorktree(ctx context.Context, wt WorktreeInfo, force bool) (*RemoveResult, error) {
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	if err := checkDeletable(wt); err != nil {
+		return nil, err
+	}
+	if wt.Operation != "" && !force {
+		return nil, OperationError(wt.Name, wt.Path, wt.Operation)
+	}
+	return wm.removeWorktree(wt, force)
+}
+
+// removeWorktree runs the removal for RemoveWorktree: it clears symlinks
+// that would block a plain remove, deinitializes submodules, runs git
+// worktree remove and, when forced and git still refuses, deletes the
+// directory and prunes the registration. The branch is kept.
+func (wm *WorktreeManager) removeWorktree(wt WorktreeInfo, force bool) (*RemoveResult, error) {
+	result := &RemoveResult{}
+
+	// Check the lock before touching anything, so a refused delete leaves the
+	// worktree as it was
+	locked, lockReason := worktreeLocked(wt.Path)
+	if locked && !force {
+		reason := ""
+		if lockReason != "" {
+			reason = " (" + lockReason + ")"
+		}
+		return nil, &RemoveError{
+			Worktree: wt.Name,
+			Reason:   "locked (git worktree unlock it first)",
+			Err:      fmt.Errorf("worktree '%s' is locked%s; unlock it with 'git worktree unlock %s', or force the delete", wt.Name, reason, wt.Path),
+		}
+	}
+
+	// Symlinks made by post-create hooks (.gren, .env) are untracked files and
+	// would block a plain remove; removing a link leaves its target alone.
+	// Without force they go only when they are all that blocks it, so a
+	// remove git refuses for other changes leaves the worktree as it was.
+	external := findExternalSymlinks(wt.Path)
+	dangling, _ := wm.FindDanglingSymlinks(wt.Path)
+	if (len(external) > 0 || len(dangling) > 0) && (force || onlyLinksUntracked(wt.Path, external, dangling)) {
+		result.ExternalSymlinks = removeSymlinks(wt.Path, external)
+		if len(dangling) > 0 {
+			result.DanglingSymlinks = wm.RemoveDanglingSymlinks(wt.Path, dangling)
+			logging.Info("removeWorktree: removed %d dangling symlink(s) from %s", len(result.DanglingSymlinks), wt.Name)
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(wt.Path, ".gitmodules")); err == nil {
+		result.Submodules = true
+		output, err := exec.Command("git", "-C", wt.Path, "submodule", "deinit", "--all", "--force").CombinedOutput()
+		if err != nil {
+			logging.Error("removeWorktree: failed to deinit submodules in %s: %v (%s)", wt.Name, err, strings.TrimSpace(string(output)))
+			return nil, &RemoveError{
+				Worktree: wt.Name,
+				Reason:   "submodule deinit failed",
+				Output:   string(output),
+				Hint:     "This can happen if submodules have uncommitted changes.\nTry running manually:\n  cd " + wt.Path + "\n  git submodule deinit --all --force",
+				Err:      fmt.Errorf("failed to deinit submodules in worktree '%s': %w", wt.Name, err),
+			}
+		}
+	}
+
+	// --force is required for submodules (even after deinit) or when the
+	// caller forces (to ignore uncommitted/leftover content). A locked
+	// worktree takes it twice.
+	result.Forced = result.Submodules || force
+	args := []string{"worktree", "remove"}
+	if result.Forced {
+		args = append(args, "--force")
+		if locked {
+			args = append(args, "--force")
+		}
+	}
+	args = append(args, wt.Path)
+	logging.Debug("removeWorktree: git %s (submodules=%v, force=%v, locked=%v)", strings.Join(args, " "), result.Submodules, force, locked)
+	output, err := exec.Command("git", args...).CombinedOutput()
+	if err != nil {
+		outputStr := string(output)
+		// When forced, complete the removal even if git still refuses, e.g.
+		// leftover ignored content ("Directory not empty") or a worktree left
+		// half-removed by an earlier attempt: remove the checkout directly and
+		// prune the now-stale registration.
+		if result.Forced {
+			logging.Warn("removeWorktree: 'git worktree remove --force' failed (%s); removing directory and pruning", strings.TrimSpace(outputStr))
+			if rmErr := os.RemoveAll(wt.Path); rmErr != nil {
+				return nil, &RemoveError{
+					Worktree: wt.Name,
+					Reason:   "deletion failed",
+					Err:      fmt.Errorf("failed to remove worktree '%s' directory: %w", wt.Name, rmErr),
+				}
+			}
+			pruneCmd := exec.Command("git", "worktree", "prune")
+			if repoRoot, rrErr := wm.getRepoRoot(); rrErr == nil {
+				pruneCmd.Dir = repoRoot
+			}
+			if pruneOut, pruneErr := pruneCmd.CombinedOutput(); pruneErr != nil {
+				logging.Warn("removeWorktree: 'git worktree prune' failed: %v (%s)", pruneErr, strings.TrimSpace(string(pruneOut)))
+			}
+			result.Fallback = true
+			logging.Info("Removed worktree '%s' via force fallback (branch '%s' is preserved)", wt.Name, wt.Branch)
+			wm.pruneEmptyWorktreeDir(wt.Path)
+			return result, nil
+		}
+		logging.Error("removeWorktree: failed to remove %s: %v (%s)", wt.Name, err, strings.TrimSpace(outputStr))
+		removeErr := &RemoveError{
+			Worktree: wt.Name,
+			Output:   outputStr,
+			Err:      fmt.Errorf("failed to remove worktree '%s': %w", wt.Name, err),
+		}
+		switch {
+		case strings.Contains(outputStr, "submodules"):
+			removeErr.Reason = "has submodules (try force delete)"
+			removeErr.Hint = "The worktree contains submodules. Try running:\n  git -C " + wt.Path + " submodule deinit --all --force\nThen try deleting again with force."
+		case strings.Contains(outputStr, "modified or untracked"):
+			removeErr.Reason = "has uncommitted changes"
+			removeErr.Hint = "The worktree has uncommitted changes. Commit or stash them first, or use force delete."
+		case strings.Contains(outputStr, "is not a working tree"):
+			removeErr.Reason = "not a valid worktree"
+		default:
+			removeErr.Reason = "deletion failed"
+			removeErr.Hint = "Use force delete to remove anyway."
+		}
+		return nil, removeErr
+	}
+
+	logging.Info("Removed worktree '%s' (branch '%s' is preserved)", wt.Name, wt.Branch)
+	wm.pruneEmptyWorktreeDir(wt.Path)
+	return result, nil
+}
+
+// findExternalSymlinks lists the symlinks at the root of worktreePath that
+// point outside it and that git doesn't track. A tracked link is part of the
+// checkout; removing it would only make the worktree look modified. Dangling
+// links are left to FindDanglingSymlinks.
+func findExternalSymlinks(worktreePath string) []string {
+	entries, err := os.ReadDir(worktreePath)
+	if err != nil {
+		return nil
+	}
+	var links []string
+	for _, entry := range entries {
+		if entry.Type()&os.ModeSymlink == 0 {
+			continue
+		}
+		entryPath := filepath.Join(worktreePath, entry.Name())
+		target, err := os.Readlink(entryPath)
+		if err != nil {
+			continue
+		}
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(worktreePath, target)
+		}
+		if samePath(target, worktreePath) || pathWithin(target, worktreePath) {
+			continue
+		}
+		if _, err := os.Stat(entryPath); os.IsNotExist(err) {
+			continue
+		}
+		tracked, err := exec.Command("git", "-C", worktreePath, "ls-files", "--", entry.Name()).Output()
+		if err != nil || strings.TrimSpace(string(tracked)) != "" {
+			continue
+		}
+		links = append(links, entry.Name())
+	}
+	return links
+}
+
+// removeSymlinks removes the named root entries of worktreePath and returns
+// the ones it removed.
+func removeSymlinks(worktreePath string, names []string) []string {
+	var removed []string
+	for _, name := range names {
+		entryPath := filepath.Join(worktreePath, name)
+		logging.Debug("removeSymlinks: removing %s", entryPath)
+		if err := os.Remove(entryPath); err != nil {
+			logging.Warn("removeSymlinks: failed to remove %s: %v", entryPath, err)
+			continue
+		}
+		removed = append(removed, name)
+	}
+	return removed
+}
+
+// onlyLinksUntracked reports whether the untracked symlinks in external and
+// dangling are the only changes git sees in worktreePath, i.e. whether
+// removing them is enough for a plain git worktree remove to succeed.
+func onlyLinksUntracked(worktreePath string, external []string, dangling []DanglingSymlink) bool {
+	output, err := exec.Command("git", "-C", worktreePath, "status", "--porcelain", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return false
+	}
+	links := make(map[string]bool, len(external)+len(dangling))
+	for _, name := range external {
+		links[name] = true
+	}
+	for _, link := range dangling {
+		links[filepath.ToSlash(link.Path)] = true
+	}
+	for _, entry := range strings.Split(string(output), "\x00") {
+		if entry == "" {
+			continue
+		}
+		if !strings.HasPrefix(entry, "?? ") || !links[entry[3:]] {
+			return false
+		}
+	}
+	return true
+}
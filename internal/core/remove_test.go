@@ -0,0 +1,138 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRemoveWorktree(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	addWorktree := func(t *testing.T, name string) WorktreeInfo {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), name)
+		runGit(t, dir, "worktree", "add", "-b", name, path)
+		return WorktreeInfo{Name: name, Path: path, Branch: name}
+	}
+	assertGone := func(t *testing.T, path string) {
+		t.Helper()
+		if _, err := os.Lstat(path); !os.IsNotExist(err) {
+			t.Errorf("worktree %s still exists: %v", path, err)
+		}
+	}
+
+	t.Run("external symlinks are removed, their targets kept", func(t *testing.T) {
+		shared := filepath.Join(t.TempDir(), "shared")
+		os.MkdirAll(shared, 0755)
+		os.WriteFile(filepath.Join(shared, "secret"), []byte("keep\n"), 0644)
+		os.WriteFile(filepath.Join(dir, ".env"), []byte("A=1\n"), 0644)
+
+		wt := addWorktree(t, "symlinked")
+		os.Symlink(filepath.Join(dir, ".env"), filepath.Join(wt.Path, ".env"))
+		os.Symlink(shared, filepath.Join(wt.Path, "shared"))
+		os.Symlink(filepath.Join(dir, "gone"), filepath.Join(wt.Path, "dangling"))
+
+		result, err := manager.RemoveWorktree(ctx, wt, false)
+		if err != nil {
+			t.Fatalf("RemoveWorktree() error: %v", err)
+		}
+		if want := []string{".env", "shared"}; !reflect.DeepEqual(result.ExternalSymlinks, want) {
+			t.Errorf("ExternalSymlinks = %q, want %q", result.ExternalSymlinks, want)
+		}
+		if len(result.DanglingSymlinks) != 1 || result.DanglingSymlinks[0].Path != "dangling" {
+			t.Errorf("DanglingSymlinks = %+v, want the dangling link", result.DanglingSymlinks)
+		}
+		if result.Forced || result.Fallback {
+			t.Errorf("a worktree with only hook symlinks needed force: %+v", result)
+		}
+		assertGone(t, wt.Path)
+		if _, err := os.Stat(filepath.Join(shared, "secret")); err != nil {
+			t.Errorf("symlink target was removed with the worktree: %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".env")); err != nil {
+			t.Errorf("symlink target was removed with the worktree: %v", err)
+		}
+	})
+
+	t.Run("tracked and internal symlinks stay", func(t *testing.T) {
+		wt := addWorktree(t, "tracked-link")
+		os.Symlink("../outside", filepath.Join(wt.Path, "tracked"))
+		runGit(t, wt.Path, "add", "tracked")
+		runGit(t, wt.Path, "commit", "-m", "add link")
+		os.Symlink("README.md", filepath.Join(wt.Path, "readme"))
+
+		if found := findExternalSymlinks(wt.Path); found != nil {
+			t.Errorf("findExternalSymlinks() = %q, want nothing to remove", found)
+		}
+	})
+
+	t.Run("submodules are deinitialized and force removed", func(t *testing.T) {
+		origin := t.TempDir()
+		runGit(t, origin, "init", "-b", "main")
+		runGit(t, origin, "-c", "user.email=t@t", "-c", "user.name=t", "commit", "--allow-empty", "-m", "init")
+
+		wt := addWorktree(t, "with-submodule")
+		runGit(t, wt.Path, "-c", "protocol.file.allow=always", "submodule", "add", origin, "sub")
+		runGit(t, wt.Path, "commit", "-m", "add submodule")
+
+		result, err := manager.RemoveWorktree(ctx, wt, false)
+		if err != nil {
+			t.Fatalf("RemoveWorktree() error: %v", err)
+		}
+		if !result.Submodules || !result.Forced {
+			t.Errorf("result = %+v, want submodules deinitialized and --force", result)
+		}
+		assertGone(t, wt.Path)
+	})
+
+	t.Run("uncommitted changes need force", func(t *testing.T) {
+		wt := addWorktree(t, "dirty")
+		os.WriteFile(filepath.Join(wt.Path, "wip.txt"), []byte("wip\n"), 0644)
+		os.Symlink(filepath.Join(dir, ".env"), filepath.Join(wt.Path, ".env"))
+
+		_, err := manager.RemoveWorktree(ctx, wt, false)
+		var removeErr *RemoveError
+		if !errors.As(err, &removeErr) || removeErr.Reason != "has uncommitted changes" {
+			t.Fatalf("RemoveWorktree() error = %v, want a RemoveError for uncommitted changes", err)
+		}
+		if _, err := os.Stat(filepath.Join(wt.Path, "wip.txt")); err != nil {
+			t.Fatalf("refused remove lost the uncommitted file: %v", err)
+		}
+		if _, err := os.Lstat(filepath.Join(wt.Path, ".env")); err != nil {
+			t.Errorf("refused remove took the hook symlink: %v", err)
+		}
+
+		result, err := manager.RemoveWorktree(ctx, wt, true)
+		if err != nil {
+			t.Fatalf("RemoveWorktree(force) error: %v", err)
+		}
+		if !result.Forced {
+			t.Errorf("result = %+v, want Forced", result)
+		}
+		assertGone(t, wt.Path)
+	})
+
+	t.Run("refusals", func(t *testing.T) {
+		if _, err := manager.RemoveWorktree(ctx, WorktreeInfo{Name: "main", Path: dir, IsMain: true}, true); !errors.Is(err, ErrDeleteMain) {
+			t.Errorf("RemoveWorktree(main) error = %v, want ErrDeleteMain", err)
+		}
+
+		wt := addWorktree(t, "locked")
+		runGit(t, dir, "worktree", "lock", wt.Path)
+		os.Symlink(filepath.Join(dir, ".env"), filepath.Join(wt.Path, ".env"))
+		_, err := manager.RemoveWorktree(ctx, wt, false)
+		var removeErr *RemoveError
+		if !errors.As(err, &removeErr) || removeErr.Reason != "locked (git worktree unlock it first)" {
+			t.Fatalf("RemoveWorktree(locked) error = %v, want a RemoveError for the lock", err)
+		}
+		if _, err := os.Lstat(filepath.Join(wt.Path, ".env")); err != nil {
+			t.Errorf("refused remove touched the worktree: %v", err)
+		}
+	})
+}
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// RepairedLink is a link between a worktree and the repository that
+// `git worktree repair` fixed.
+type RepairedLink struct {
+	Problem string // git's description, e.g. "gitdir incorrect" or ".git file broken"
+	Path    string // The file or worktree git fixed
+}
+
+// RepairWorktrees runs `git worktree repair` from the main worktree, which
+// reconnects linked worktrees and the repository after either was moved by
+// hand. Moving the main worktree needs no paths. A moved linked worktree
+// does: its old path is all git knows, so paths are their new locations.
+// Without paths, gren looks for moved worktrees next to the registered ones
+// and in the worktree directory. It returns what git fixed, which is empty
+// when nothing was broken.
+func (wm *WorktreeManager) RepairWorktrees(ctx context.Context, paths []string) ([]RepairedLink, error) {
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	if len(paths) == 0 {
+		if worktrees, err := wm.ListWorktreesBasic(ctx); err == nil {
+			paths = wm.findMovedWorktrees(ctx, worktrees)
+		}
+	}
+
+	args := []string{"worktree", "repair"}
+	for _, path := range paths {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid path %s: %w", path, err)
+		}
+		args = append(args, abs)
+	}
+
+	cmd := exec.CommandContext(ctx, "git", args...)
+	if repoRoot, err := wm.getRepoRoot(); err == nil {
+		cmd.Dir = repoRoot
+	}
+	logging.Debug("RepairWorktrees: running git %s", strings.Join(args, " "))
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		logging.Error("RepairWorktrees: git worktree repair failed: %v, output: %s", err, string(output))
+		if msg := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(output)), "error:")); msg != "" {
+			return nil, fmt.Errorf("git worktree repair failed: %s", msg)
+		}
+		return nil, fmt.Errorf("git worktree repair failed: %w", err)
+	}
+
+	repaired := parseRepairOutput(string(output))
+	logging.Info("RepairWorktrees: %d link(s) repaired", len(repaired))
+	return repaired, nil
+}
+
+// parseRepairOutput parses `git worktree repair` lines of the form
+// "repair: <problem>: <path>".
+func parseRepairOutput(output string) []RepairedLink {
+	var repaired []RepairedLink
+	for _, line := range strings.Split(output, "\n") {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "repair: ")
+		if !ok {
+			continue
+		}
+		problem, path, _ := strings.Cut(rest, ": ")
+		repaired = append(repaired, RepairedLink{Problem: strings.TrimSpace(problem), Path: strings.TrimSpace(path)})
+	}
+	return repaired
+}
+
+// findMovedWorktrees returns directories, next to the registered worktrees
+// or in the worktree directory, that are linked worktrees of this
+// repository which the repository no longer points at: worktrees moved
+// without `git worktree move`.
+func (wm *WorktreeManager) findMovedWorktrees(ctx context.Context, worktrees []WorktreeInfo) []string {
+	output, err := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return nil
+	}
+	adminDirs := filepath.Join(filepath.Clean(strings.TrimSpace(string(output))), "worktrees") + string(filepath.Separator)
+
+	registered := make(map[string]bool)
+	parents := make(map[string]bool)
+	for _, wt := range worktrees {
+		registered[wt.Path] = true
+		if !wt.IsMain {
+			parents[filepath.Dir(wt.Path)] = true
+		}
+	}
+	if cfg, err := wm.configManager.Load(); err == nil {
+		if dir, err := wm.resolveWorktreeDir(ctx, cfg, "", ""); err == nil {
+			parents[filepath.Clean(dir)] = true
+		}
+	}
+
+	var moved []string
+	for parent := range parents {
+		entries, err := os.ReadDir(parent)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			path := filepath.Join(parent, entry.Name())
+			if !entry.IsDir() || registered[path] {
+				continue
+			}
+			if adminDir := linkedGitDir(path); strings.HasPrefix(adminDir, adminDirs) && HasBrokenLink(path) {
+				logging.Debug("findMovedWorktrees: %s looks like a moved worktree", path)
+				moved = append(moved, path)
+			}
+		}
+	}
+	return moved
+}
+
+// HasBrokenLink reports whether the linked worktree at path and the
+// repository no longer point at each other: its .git file names an
+// administrative directory that is gone (the repository moved), or that
+// directory records another location (the worktree moved). Git commands in
+// such a worktree fail or act on the wrong checkout. Directories that are
+// not linked worktrees are never broken.
+func HasBrokenLink(path string) bool {
+	adminDir := linkedGitDir(path)
+	if adminDir == "" {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(adminDir, "gitdir"))
+	if err != nil {
+		return true
+	}
+	recorded := strings.TrimSpace(string(data))
+	if !filepath.IsAbs(recorded) {
+		recorded = filepath.Join(adminDir, recorded)
+	}
+	recordedInfo, err := os.Stat(recorded)
+	if err != nil {
+		return true
+	}
+	gitFile, err := os.Stat(filepath.Join(path, ".git"))
+	return err != nil || !os.SameFile(recordedInfo, gitFile)
+}
+
+// linkedGitDir returns the administrative directory a linked worktree's
+// .git file points at, or "" when path has no .git file (the main worktree
+// has a .git directory).
+func linkedGitDir(path string) string {
+	gitFile := filepath.Join(path, ".git")
+	info, err := os.Lstat(gitFile)
+	if err != nil || !info.Mode().IsRegular() {
+		return ""
+	}
+	data, err := os.ReadFile(gitFile)
+	if err != nil {
+		return ""
+	}
+	adminDir, ok := strings.CutPrefix(strings.TrimSpace(string(data)), "gitdir:")
+	if !ok {
+		return ""
+	}
+	adminDir = strings.TrimSpace(adminDir)
+	if !filepath.IsAbs(adminDir) {
+		adminDir = filepath.Join(path, adminDir)
+	}
+	return filepath.Clean(adminDir)
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestRepairWorktrees(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+	worktrees := filepath.Join(filepath.Dir(dir), "test-worktrees")
+
+	// findWorktreeByBranch lists the worktrees and returns the one on branch.
+	findWorktreeByBranch := func(t *testing.T, branch string) WorktreeInfo {
+		t.Helper()
+		list, err := manager.ListWorktrees(ctx)
+		if err != nil {
+			t.Fatalf("ListWorktrees() error: %v", err)
+		}
+		for _, wt := range list {
+			if wt.Branch == branch {
+				return wt
+			}
+		}
+		t.Fatalf("no worktree on %s in %+v", branch, list)
+		return WorktreeInfo{}
+	}
+
+	// assertRepaired checks that the worktree on branch is back at path and
+	// that git works in it.
+	assertRepaired := func(t *testing.T, branch, path string) {
+		t.Helper()
+		wt := findWorktreeByBranch(t, branch)
+		if wt.Path != path || wt.Status == "missing" || wt.BrokenLink {
+			t.Errorf("after repair %s = path %s, status %q, broken %v; want it at %s and linked", branch, wt.Path, wt.Status, wt.BrokenLink, path)
+		}
+		if output, err := exec.Command("git", "-C", path, "status", "--short").CombinedOutput(); err != nil {
+			t.Errorf("git status in the repaired worktree: %v: %s", err, output)
+		}
+	}
+
+	t.Run("worktree moved next to the others", func(t *testing.T) {
+		oldPath := filepath.Join(worktrees, "repair-old")
+		newPath := filepath.Join(worktrees, "repair-new")
+		runGit(t, dir, "worktree", "add", "-b", "repair-branch", oldPath)
+		if err := os.Rename(oldPath, newPath); err != nil {
+			t.Fatal(err)
+		}
+
+		wt := findWorktreeByBranch(t, "repair-branch")
+		if wt.Status != "missing" {
+			t.Errorf("moved worktree status = %q, want missing", wt.Status)
+		}
+		if !HasBrokenLink(newPath) {
+			t.Error("HasBrokenLink(new path) = false, want true before repair")
+		}
+		if check := doctorCheck(t, manager.Diagnose(ctx), "worktree links"); check.Status != CheckWarn || check.Detail != "directory missing: repair-old" {
+			t.Errorf("worktree links = %+v, want warn naming repair-old", check)
+		}
+
+		// No paths: gren finds the moved worktree on its own
+		repaired, err := manager.RepairWorktrees(ctx, nil)
+		if err != nil || len(repaired) == 0 {
+			t.Fatalf("RepairWorktrees() = %v, %v, want a repaired link", repaired, err)
+		}
+		assertRepaired(t, "repair-branch", newPath)
+
+		if check := doctorCheck(t, manager.Diagnose(ctx), "worktree links"); check.Status != CheckOK {
+			t.Errorf("worktree links = %+v after repair, want ok", check)
+		}
+		if repaired, err := manager.RepairWorktrees(ctx, nil); err != nil || len(repaired) != 0 {
+			t.Errorf("second RepairWorktrees() = %v, %v, want nothing to repair", repaired, err)
+		}
+	})
+
+	t.Run("worktree moved elsewhere", func(t *testing.T) {
+		oldPath := filepath.Join(worktrees, "repair-far")
+		runGit(t, dir, "worktree", "add", "-b", "repair-far", oldPath)
+		newPath := filepath.Join(t.TempDir(), "repair-far")
+		if err := os.Rename(oldPath, newPath); err != nil {
+			t.Fatal(err)
+		}
+		newPath, _ = filepath.EvalSymlinks(newPath)
+
+		if _, err := manager.RepairWorktrees(ctx, []string{newPath}); err != nil {
+			t.Fatalf("RepairWorktrees(%s) error: %v", newPath, err)
+		}
+		assertRepaired(t, "repair-far", newPath)
+	})
+}
+
+func TestParseRepairOutput(t *testing.T) {
+	output := "repair: gitdir incorrect: /repo/.git/worktrees/feature/gitdir\nrepair: .git file broken: /wt/other\nwarning: something else\n"
+	want := []RepairedLink{
+		{Problem: "gitdir incorrect", Path: "/repo/.git/worktrees/feature/gitdir"},
+		{Problem: ".git file broken", Path: "/wt/other"},
+	}
+	if got := parseRepairOutput(output); !reflect.DeepEqual(got, want) {
+		t.Errorf("parseRepairOutput() = %+v, want %+v", got, want)
+	}
+}
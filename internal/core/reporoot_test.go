@@ -1,10 +1,13 @@
 package core
 
 import (
+	"context"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"testing"
+
+	"github.com/langtind/gren/internal/config"
 )
 
 // getRepoRoot must resolve to the MAIN worktree, not the current linked
@@ -45,3 +48,100 @@ func TestGetRepoRoot_FromLinkedWorktree(t *testing.T) {
 			got, gotReal, main, wantReal)
 	}
 }
+
+// The main worktree is whichever git lists first, not the one whose .git is
+// a directory: a submodule's checkout has a .git file, and with a separate
+// git dir (GIT_DIR, core.worktree) there may be no .git at all.
+func TestMainWorktreeDetection(t *testing.T) {
+	git := func(dir string, args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=t", "GIT_AUTHOR_EMAIL=t@t",
+			"GIT_COMMITTER_NAME=t", "GIT_COMMITTER_EMAIL=t@t",
+		)
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+	chdir := func(t *testing.T, dir string) {
+		origWd, err := os.Getwd()
+		if err != nil {
+			t.Fatal(err)
+		}
+		t.Cleanup(func() { os.Chdir(origWd) })
+		if err := os.Chdir(dir); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// check lists the worktrees from the current directory and expects main
+	// to be the main one, and to be what getRepoRoot resolves to.
+	check := func(t *testing.T, main string, wantCount int) {
+		t.Helper()
+		wm := NewWorktreeManager(nil, config.NewManager())
+		worktrees, err := wm.ListWorktreesBasic(context.Background())
+		if err != nil {
+			t.Fatalf("ListWorktreesBasic: %v", err)
+		}
+		if len(worktrees) != wantCount {
+			t.Fatalf("ListWorktreesBasic returned %d worktrees, want %d: %+v", len(worktrees), wantCount, worktrees)
+		}
+		wantReal, _ := filepath.EvalSymlinks(main)
+		for _, wt := range worktrees {
+			real, _ := filepath.EvalSymlinks(wt.Path)
+			if want := real == wantReal; wt.IsMain != want {
+				t.Errorf("worktree %s IsMain = %v, want %v", wt.Path, wt.IsMain, want)
+			}
+		}
+		got, err := wm.getRepoRoot()
+		if err != nil {
+			t.Fatalf("getRepoRoot: %v", err)
+		}
+		if gotReal, _ := filepath.EvalSymlinks(got); gotReal != wantReal {
+			t.Errorf("getRepoRoot = %q, want %q", got, main)
+		}
+	}
+
+	t.Run("submodule", func(t *testing.T) {
+		origin := mkRepo(t)
+		super := mkRepo(t)
+		git(super, "-c", "protocol.file.allow=always", "submodule", "add", origin, "sub")
+		sub := filepath.Join(super, "sub")
+		if info, err := os.Stat(filepath.Join(sub, ".git")); err != nil || info.IsDir() {
+			t.Fatalf("submodule .git should be a file: %v", err)
+		}
+		feat := filepath.Join(t.TempDir(), "feat")
+		git(sub, "worktree", "add", "-b", "feat", feat)
+
+		chdir(t, sub)
+		check(t, sub, 2)
+		chdir(t, feat)
+		check(t, sub, 2)
+	})
+
+	t.Run("separate git dir", func(t *testing.T) {
+		base := t.TempDir()
+		work := filepath.Join(base, "work")
+		git(base, "init", "-b", "main", "--separate-git-dir", filepath.Join(base, "repo.git"), work)
+		git(work, "-c", "commit.gpgsign=false", "commit", "--allow-empty", "-m", "initial")
+
+		chdir(t, work)
+		check(t, work, 1)
+	})
+
+	t.Run("GIT_DIR with core.worktree", func(t *testing.T) {
+		base := t.TempDir()
+		work := filepath.Join(base, "work")
+		gitDir := filepath.Join(base, "repo.git")
+		git(base, "init", "-b", "main", "--separate-git-dir", gitDir, work)
+		git(work, "config", "core.worktree", work)
+		git(work, "-c", "commit.gpgsign=false", "commit", "--allow-empty", "-m", "initial")
+		// Only GIT_DIR says where the repository is
+		os.Remove(filepath.Join(work, ".git"))
+		t.Setenv("GIT_DIR", gitDir)
+
+		chdir(t, work)
+		check(t, work, 1)
+	})
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+const (
+	// DefaultNetworkAttempts is how many times a network git or gh call is
+	// tried when network_attempts is unset.
+	DefaultNetworkAttempts = 3
+	// DefaultNetworkBackoff is the wait before the first retry when
+	// network_backoff is unset. It doubles after each further attempt.
+	DefaultNetworkBackoff = 500 * time.Millisecond
+)
+
+// retryPolicy says how often, and how patiently, to retry a network call.
+type retryPolicy struct {
+	attempts int
+	backoff  time.Duration
+}
+
+// networkRetryPolicy returns the retry policy from the network_attempts and
+// network_backoff config, with the defaults for what is unset or when the
+// config cannot be loaded.
+func (wm *WorktreeManager) networkRetryPolicy() retryPolicy {
+	policy := retryPolicy{attempts: DefaultNetworkAttempts, backoff: DefaultNetworkBackoff}
+	if wm.configManager == nil {
+		return policy
+	}
+	cfg, err := wm.configManager.Load()
+	if err != nil {
+		return policy
+	}
+	if cfg.NetworkAttempts > 0 {
+		policy.attempts = cfg.NetworkAttempts
+	}
+	if cfg.NetworkBackoff != "" {
+		if backoff, err := time.ParseDuration(cfg.NetworkBackoff); err == nil {
+			policy.backoff = backoff
+		}
+	}
+	return policy
+}
+
+// retryNetwork runs run, which starts a fresh git or gh command and returns
+// its output, until it succeeds, fails for a reason retrying won't fix, or
+// policy.attempts are used up. It waits policy.backoff before the first
+// retry and twice as long before each one after that, and stops early when
+// ctx is done. name labels the call in the log.
+func retryNetwork(ctx context.Context, policy retryPolicy, name string, run func() ([]byte, error)) ([]byte, error) {
+	backoff := policy.backoff
+	for attempt := 1; ; attempt++ {
+		output, err := run()
+		if err == nil || attempt >= policy.attempts || ctx.Err() != nil || !isTransientNetworkError(output, err) {
+			return output, err
+		}
+		logging.Warn("%s: transient failure (attempt %d of %d), retrying in %s: %v", name, attempt, policy.attempts, backoff, err)
+		select {
+		case <-ctx.Done():
+			return output, err
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// permanentNetworkErrors mark failures that fail the same way every time:
+// authentication, missing repositories or PRs, and bad requests. They win
+// over transientNetworkErrors when both match.
+var permanentNetworkErrors = []string{
+	"authentication failed",
+	"permission denied",
+	"could not read username",
+	"could not read password",
+	"gh auth login",
+	"http 401",
+	"http 403",
+	"http 404",
+	"not found",
+	"does not appear to be a git repository",
+	"no pull requests found",
+}
+
+// transientNetworkErrors mark failures of the connection rather than the
+// request, which are worth retrying.
+var transientNetworkErrors = []string{
+	"timed out",
+	"timeout",
+	"connection reset",
+	"connection refused",
+	"connection closed",
+	"broken pipe",
+	"could not resolve host",
+	"temporary failure in name resolution",
+	"network is unreachable",
+	"no route to host",
+	"the remote end hung up unexpectedly",
+	"early eof",
+	"rpc failed",
+	"tls handshake",
+	"unexpected eof",
+	"http 502",
+	"http 503",
+	"http 504",
+	"bad gateway",
+	"service unavailable",
+}
+
+// isTransientNetworkError reports whether a git or gh command that failed
+// with err and printed output failed for a network reason that may pass,
+// judging by its error text. The stderr of an *exec.ExitError counts too,
+// as Output leaves it there. Failures it doesn't recognize are permanent.
+func isTransientNetworkError(output []byte, err error) bool {
+	if err == nil {
+		return false
+	}
+	text := string(output) + "\n" + err.Error()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		text += "\n" + string(exitErr.Stderr)
+	}
+	text = strings.ToLower(text)
+
+	for _, marker := range permanentNetworkErrors {
+		if strings.Contains(text, marker) {
+			return false
+		}
+	}
+	for _, marker := range transientNetworkErrors {
+		if strings.Contains(text, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,113 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestIsTransientNetworkError(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		err    error
+		want   bool
+	}{
+		{"success", "", nil, false},
+		{"connection reset", "fatal: unable to access 'https://github.com/o/r/': Recv failure: Connection reset by peer", errors.New("exit status 128"), true},
+		{"dns", "fatal: unable to access: Could not resolve host: github.com", errors.New("exit status 128"), true},
+		{"remote hung up", "fatal: the remote end hung up unexpectedly", errors.New("exit status 128"), true},
+		{"gh timeout", "", errors.New(`Post "https://api.github.com/graphql": net/http: TLS handshake timeout`), true},
+		{"auth", "fatal: Authentication failed for 'https://github.com/o/r/'", errors.New("exit status 128"), false},
+		{"missing repo", "remote: Repository not found.", errors.New("exit status 128"), false},
+		{"no remote", "fatal: 'origin' does not appear to be a git repository", errors.New("exit status 128"), false},
+		{"auth wins over timeout", "HTTP 401: timeout waiting for login (gh auth login)", errors.New("exit status 1"), false},
+		{"unknown", "error: something else", errors.New("exit status 1"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isTransientNetworkError([]byte(tt.output), tt.err); got != tt.want {
+				t.Errorf("isTransientNetworkError(%q, %v) = %v, want %v", tt.output, tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryNetwork(t *testing.T) {
+	policy := retryPolicy{attempts: 3, backoff: time.Millisecond}
+	transient := errors.New("connection reset by peer")
+
+	t.Run("retries transient failures", func(t *testing.T) {
+		calls := 0
+		output, err := retryNetwork(context.Background(), policy, "test", func() ([]byte, error) {
+			calls++
+			if calls < 3 {
+				return nil, transient
+			}
+			return []byte("ok"), nil
+		})
+		if err != nil || string(output) != "ok" || calls != 3 {
+			t.Errorf("got %q, %v after %d calls, want ok after 3", output, err, calls)
+		}
+	})
+
+	t.Run("gives up after the last attempt", func(t *testing.T) {
+		calls := 0
+		_, err := retryNetwork(context.Background(), policy, "test", func() ([]byte, error) {
+			calls++
+			return nil, transient
+		})
+		if err != transient || calls != 3 {
+			t.Errorf("got %v after %d calls, want the transient error after 3", err, calls)
+		}
+	})
+
+	t.Run("does not retry permanent failures", func(t *testing.T) {
+		calls := 0
+		_, err := retryNetwork(context.Background(), policy, "test", func() ([]byte, error) {
+			calls++
+			return []byte("fatal: Authentication failed"), errors.New("exit status 128")
+		})
+		if err == nil || calls != 1 {
+			t.Errorf("got %v after %d calls, want an error after 1", err, calls)
+		}
+	})
+
+	t.Run("stops when the context is done", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		_, err := retryNetwork(ctx, retryPolicy{attempts: 5, backoff: time.Hour}, "test", func() ([]byte, error) {
+			calls++
+			cancel()
+			return nil, transient
+		})
+		if err != transient || calls != 1 {
+			t.Errorf("got %v after %d calls, want the transient error after 1", err, calls)
+		}
+	})
+}
+
+func TestFetchPRsByBranchRetriesTransientFailure(t *testing.T) {
+	_, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	calls := fakeGHScript(t, `if [ "$(wc -l < "$0.calls" 2>/dev/null || echo 0)" -lt 1 ]; then
+  echo x >> "$0.calls"
+  echo 'Post "https://api.github.com/graphql": read: connection reset by peer' >&2
+  exit 1
+fi
+echo '[{"number": 4, "state": "OPEN", "url": "https://example.com/4", "isDraft": false, "headRefName": "feat"}]'
+`)
+
+	prs := manager.FetchPRsByBranch()
+	if pr, ok := prs["feat"]; !ok || pr.Number != 4 {
+		t.Errorf("FetchPRsByBranch() = %v, want PR #4 for feat after a retry", prs)
+	}
+	data, _ := os.ReadFile(calls)
+	if n := len(strings.Split(strings.TrimSpace(string(data)), "\n")); n != 2 {
+		t.Errorf("gh called %d times, want 2", n)
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SortMode is an order for worktree listings, shared by `gren list --sort`
+// and the TUI dashboard.
+type SortMode string
+
+const (
+	SortRecent SortMode = "recent" // Last commit, newest first
+	SortName   SortMode = "name"   // Worktree name, A-Z
+	SortBranch SortMode = "branch" // Branch name, A-Z
+	SortStatus SortMode = "status" // Uncommitted changes first, then unpushed, then clean
+	SortStale  SortMode = "stale"  // Stale branches first, then by recency
+)
+
+// SortModes lists the sort modes in the order the TUI cycles through them.
+var SortModes = []SortMode{SortRecent, SortName, SortBranch, SortStatus, SortStale}
+
+// ParseSortMode returns the sort mode named s.
+func ParseSortMode(s string) (SortMode, error) {
+	for _, mode := range SortModes {
+		if SortMode(s) == mode {
+			return mode, nil
+		}
+	}
+	names := make([]string, len(SortModes))
+	for i, mode := range SortModes {
+		names[i] = string(mode)
+	}
+	return "", fmt.Errorf("unknown sort mode %q; valid modes: %s", s, strings.Join(names, ", "))
+}
+
+// Next returns the sort mode after m in SortModes, wrapping around.
+func (m SortMode) Next() SortMode {
+	for i, mode := range SortModes {
+		if mode == m {
+			return SortModes[(i+1)%len(SortModes)]
+		}
+	}
+	return SortModes[0]
+}
+
+// SortKey holds the fields of a worktree that SortWorktrees compares, so
+// the CLI's WorktreeInfo and the TUI's own worktree type sort alike.
+type SortKey struct {
+	Name         string
+	Branch       string
+	Status       string // WorktreeInfo.Status
+	BranchStatus string // "stale" sorts first in SortStale
+	LastCommit   string // Relative time as set by ListWorktrees, e.g. "2h ago"
+	IsCurrent    bool
+}
+
+// SortKey returns the sort key of wt.
+func (wt WorktreeInfo) SortKey() SortKey {
+	return SortKey{
+		Name:         wt.Name,
+		Branch:       wt.Branch,
+		Status:       wt.Status,
+		BranchStatus: wt.BranchStatus,
+		LastCommit:   wt.LastCommit,
+		IsCurrent:    wt.IsCurrent,
+	}
+}
+
+// SortWorktrees sorts items by mode, stably, with the current worktree first
+// when pinCurrent is set. An empty mode keeps the existing order apart from
+// pinning.
+func SortWorktrees[T any](items []T, mode SortMode, pinCurrent bool, key func(T) SortKey) {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := key(items[i]), key(items[j])
+		if pinCurrent && a.IsCurrent != b.IsCurrent {
+			return a.IsCurrent
+		}
+		return CompareWorktrees(a, b, mode) < 0
+	})
+}
+
+// CompareWorktrees orders a and b by mode, returning a negative number when
+// a comes first, positive when b does, and zero when mode doesn't tell them
+// apart.
+func CompareWorktrees(a, b SortKey, mode SortMode) int {
+	byRecency := func() int {
+		return compareDurations(commitAge(a.LastCommit), commitAge(b.LastCommit))
+	}
+
+	switch mode {
+	case SortRecent:
+		return byRecency()
+	case SortName:
+		return strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortBranch:
+		return strings.Compare(strings.ToLower(a.Branch), strings.ToLower(b.Branch))
+	case SortStatus:
+		if d := statusRank(a.Status) - statusRank(b.Status); d != 0 {
+			return d
+		}
+		return byRecency()
+	case SortStale:
+		if aStale, bStale := a.BranchStatus == "stale", b.BranchStatus == "stale"; aStale != bStale {
+			if aStale {
+				return -1
+			}
+			return 1
+		}
+		return byRecency()
+	}
+	return 0
+}
+
+// statusRank orders statuses for SortStatus: work that isn't committed or
+// pushed yet first, along with worktrees whose status git couldn't read.
+func statusRank(status string) int {
+	switch status {
+	case "mixed", "modified", "untracked", "unknown":
+		return 0
+	case "unpushed":
+		return 1
+	case "clean":
+		return 2
+	default: // missing, or not loaded yet
+		return 3
+	}
+}
+
+func compareDurations(a, b time.Duration) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// commitAgeUnits maps the unit suffixes getLastCommitTime produces to their
+// approximate length. "mo" must be tried before "m".
+var commitAgeUnits = []struct {
+	suffix string
+	unit   time.Duration
+}{
+	{"mo", 30 * 24 * time.Hour},
+	{"s", time.Second},
+	{"m", time.Minute},
+	{"h", time.Hour},
+	{"d", 24 * time.Hour},
+	{"w", 7 * 24 * time.Hour},
+	{"y", 365 * 24 * time.Hour},
+}
+
+// commitAge parses a shortened relative commit time such as "30m ago" or
+// "2y, 3mo ago" into an approximate age. Unknown or empty times count as
+// the oldest, so worktrees without commits sort last by recency.
+func commitAge(relative string) time.Duration {
+	const unknown = time.Duration(1<<63 - 1)
+
+	// Only the leading, largest unit matters for ordering
+	field, _, _ := strings.Cut(strings.TrimSpace(relative), " ")
+	field = strings.TrimSuffix(field, ",")
+	for _, u := range commitAgeUnits {
+		if number, ok := strings.CutSuffix(field, u.suffix); ok {
+			n, err := strconv.Atoi(number)
+			if err != nil {
+				return unknown
+			}
+			return time.Duration(n) * u.unit
+		}
+	}
+	return unknown
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSortWorktrees(t *testing.T) {
+	worktrees := []WorktreeInfo{
+		{Name: "web", Branch: "feat/web", Status: "clean", LastCommit: "2d ago"},
+		{Name: "main", Branch: "main", Status: "clean", LastCommit: "3h ago", IsCurrent: true},
+		{Name: "Api", Branch: "feat/api", Status: "modified", LastCommit: "1y, 2mo ago"},
+		{Name: "cache", Branch: "fix/cache", Status: "unpushed", LastCommit: "45m ago", BranchStatus: "stale"},
+		{Name: "empty", Branch: "empty", Status: "clean"},
+	}
+
+	tests := []struct {
+		mode       SortMode
+		pinCurrent bool
+		want       string
+	}{
+		{SortRecent, false, "cache,main,web,Api,empty"},
+		{SortRecent, true, "main,cache,web,Api,empty"},
+		{SortName, false, "Api,cache,empty,main,web"},
+		{SortBranch, false, "empty,Api,web,cache,main"},
+		{SortStatus, false, "Api,cache,main,web,empty"},
+		{SortStale, true, "main,cache,web,Api,empty"},
+		{"", true, "main,web,Api,cache,empty"},
+	}
+
+	for _, tt := range tests {
+		items := append([]WorktreeInfo(nil), worktrees...)
+		SortWorktrees(items, tt.mode, tt.pinCurrent, WorktreeInfo.SortKey)
+
+		names := make([]string, len(items))
+		for i, wt := range items {
+			names[i] = wt.Name
+		}
+		if got := strings.Join(names, ","); got != tt.want {
+			t.Errorf("SortWorktrees(%q, pin=%v) = %s, want %s", tt.mode, tt.pinCurrent, got, tt.want)
+		}
+	}
+}
+
+func TestParseSortMode(t *testing.T) {
+	for _, mode := range SortModes {
+		if got, err := ParseSortMode(string(mode)); err != nil || got != mode {
+			t.Errorf("ParseSortMode(%q) = %q, %v", mode, got, err)
+		}
+	}
+
+	if _, err := ParseSortMode("size"); err == nil || !strings.Contains(err.Error(), "recent, name, branch, status, stale") {
+		t.Errorf("ParseSortMode(size) error = %v, want the valid modes listed", err)
+	}
+
+	if got := SortStale.Next(); got != SortRecent {
+		t.Errorf("SortStale.Next() = %q, want it to wrap to %q", got, SortRecent)
+	}
+}
+
+func TestCommitAge(t *testing.T) {
+	ordered := []string{"10s ago", "5m ago", "2h ago", "3d ago", "2w ago", "3mo ago", "1y, 2mo ago", "2y ago", ""}
+	for i := 1; i < len(ordered); i++ {
+		if commitAge(ordered[i-1]) >= commitAge(ordered[i]) {
+			t.Errorf("commitAge(%q) should be less than commitAge(%q)", ordered[i-1], ordered[i])
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// WorktreeStatuses lists every value WorktreeInfo.Status can take, and
+// BranchStatuses every value BranchStatus takes once checked.
+var (
+	WorktreeStatuses = []string{"clean", "modified", "untracked", "mixed", "unpushed", "missing", "unknown"}
+	BranchStatuses   = []string{"active", "stale"}
+)
+
+// StatusFilter selects worktrees by Status or BranchStatus. A worktree
+// matches when either value is in the set; a nil filter matches every one.
+type StatusFilter map[string]bool
+
+// ParseStatusFilter parses a comma-separated list of statuses, e.g.
+// "modified,mixed" or "stale". Unknown statuses are an error.
+func ParseStatusFilter(list string) (StatusFilter, error) {
+	valid := append(slices.Clone(WorktreeStatuses), BranchStatuses...)
+	filter := make(StatusFilter)
+	for _, s := range strings.Split(list, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if !slices.Contains(valid, s) {
+			return nil, fmt.Errorf("unknown status '%s' (must be one of: %s)", s, strings.Join(valid, ", "))
+		}
+		filter[s] = true
+	}
+	if len(filter) == 0 {
+		return nil, fmt.Errorf("no statuses given (must be one of: %s)", strings.Join(valid, ", "))
+	}
+	return filter, nil
+}
+
+// Matches reports whether wt's Status or BranchStatus is in f. Status is
+// the single value shown for the worktree, so "unpushed" only matches a
+// worktree with nothing uncommitted, and "modified" not one that also has
+// untracked files ("mixed").
+func (f StatusFilter) Matches(wt WorktreeInfo) bool {
+	if f == nil {
+		return true
+	}
+	return f[wt.Status] || f[wt.BranchStatus]
+}
+
+// NeedsBranchStatus reports whether f selects on BranchStatus, which needs
+// the forge's PR state to be complete (a merged PR makes a branch stale).
+func (f StatusFilter) NeedsBranchStatus() bool {
+	return slices.ContainsFunc(BranchStatuses, func(s string) bool { return f[s] })
+}
+
+// Filter returns the worktrees f matches, in order.
+func (f StatusFilter) Filter(worktrees []WorktreeInfo) []WorktreeInfo {
+	if f == nil {
+		return worktrees
+	}
+	var matched []WorktreeInfo
+	for _, wt := range worktrees {
+		if f.Matches(wt) {
+			matched = append(matched, wt)
+		}
+	}
+	return matched
+}
+
+// String lists the statuses in f, sorted and comma-separated.
+func (f StatusFilter) String() string {
+	statuses := make([]string, 0, len(f))
+	for s := range f {
+		statuses = append(statuses, s)
+	}
+	slices.Sort(statuses)
+	return strings.Join(statuses, ",")
+}
@@ -0,0 +1,41 @@
+package core
+
+import "testing"
+
+func TestStatusFilter(t *testing.T) {
+	filter, err := ParseStatusFilter("modified, stale,")
+	if err != nil {
+		t.Fatalf("ParseStatusFilter() error = %v", err)
+	}
+	if got := filter.String(); got != "modified,stale" {
+		t.Errorf("String() = %q, want %q", got, "modified,stale")
+	}
+	if !filter.NeedsBranchStatus() {
+		t.Error("NeedsBranchStatus() = false with stale in the filter")
+	}
+
+	worktrees := []WorktreeInfo{
+		{Name: "a", Status: "modified", BranchStatus: "active"},
+		{Name: "b", Status: "mixed", BranchStatus: "active"},
+		{Name: "c", Status: "clean", BranchStatus: "stale"},
+		{Name: "d", Status: "unpushed"},
+	}
+	var names []string
+	for _, wt := range filter.Filter(worktrees) {
+		names = append(names, wt.Name)
+	}
+	if len(names) != 2 || names[0] != "a" || names[1] != "c" {
+		t.Errorf("Filter() = %v, want [a c]", names)
+	}
+
+	var none StatusFilter
+	if len(none.Filter(worktrees)) != len(worktrees) || none.NeedsBranchStatus() {
+		t.Error("a nil filter should match every worktree and need nothing")
+	}
+
+	for _, list := range []string{"dirty", "", " , "} {
+		if _, err := ParseStatusFilter(list); err == nil {
+			t.Errorf("ParseStatusFilter(%q) = nil error, want one", list)
+		}
+	}
+}
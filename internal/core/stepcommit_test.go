@@ -0,0 +1,61 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestStepCommitPaths(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	os.MkdirAll(filepath.Join(dir, "sub"), 0755)
+	for _, name := range []string{"keep.txt", "sub/commit.txt", "README.md"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("changed\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Already staged but not picked: must not end up in the commit
+	runGit(t, dir, "add", "keep.txt")
+
+	if err := os.Chdir(filepath.Join(dir, "sub")); err != nil {
+		t.Fatal(err)
+	}
+	files, err := manager.ChangedFiles()
+	if err != nil {
+		t.Fatalf("ChangedFiles failed: %v", err)
+	}
+	got := map[string]string{}
+	for _, f := range files {
+		got[f.Path] = f.Status
+	}
+	want := map[string]string{"../README.md": " M", "../keep.txt": "A ", "commit.txt": "??"}
+	for path, status := range want {
+		if got[path] != status {
+			t.Errorf("ChangedFiles()[%s] = %q, want %q (all: %v)", path, got[path], status, got)
+		}
+	}
+
+	if err := manager.StepCommit(StepCommitOptions{Message: "partial", Paths: []string{"commit.txt", "../README.md"}}); err != nil {
+		t.Fatalf("StepCommit failed: %v", err)
+	}
+
+	out, err := exec.Command("git", "show", "--name-only", "--format=", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if committed := strings.Fields(string(out)); strings.Join(committed, ",") != "README.md,sub/commit.txt" {
+		t.Errorf("committed %v, want README.md and sub/commit.txt only", committed)
+	}
+	status, _ := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	if strings.TrimSpace(string(status)) != "A  keep.txt" {
+		t.Errorf("status after commit = %q, want keep.txt still staged", status)
+	}
+
+	if err := manager.StepCommit(StepCommitOptions{Message: "again", Paths: []string{"commit.txt"}}); err == nil || !strings.Contains(err.Error(), "nothing to commit") {
+		t.Errorf("StepCommit of a clean path = %v, want nothing to commit", err)
+	}
+}
@@ -0,0 +1,82 @@
+package core
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// danglingSymlinkMaxDepth bounds how deep FindDanglingSymlinks looks. Hook
+// symlinks (.env, .claude, config/local.yml) sit near the worktree root, and
+// the bound keeps the scan cheap in trees with node_modules or build output.
+const danglingSymlinkMaxDepth = 3
+
+// DanglingSymlink is a symlink in a worktree whose target no longer exists,
+// typically left by a post-create hook after the linked file moved.
+type DanglingSymlink struct {
+	Path   string // Relative to the worktree root
+	Target string // Link target as stored in the symlink
+}
+
+// FindDanglingSymlinks lists symlinks under worktreePath whose targets are
+// gone. git reports them as untracked files, so they block a plain
+// `git worktree remove` even though they hold nothing. The .git entry is
+// skipped.
+func (wm *WorktreeManager) FindDanglingSymlinks(worktreePath string) ([]DanglingSymlink, error) {
+	var dangling []DanglingSymlink
+	err := filepath.WalkDir(worktreePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if d == nil {
+				return err
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(worktreePath, path)
+		if rel == ".git" {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() && rel != "." && strings.Count(rel, string(filepath.Separator)) >= danglingSymlinkMaxDepth-1 {
+			return fs.SkipDir
+		}
+		if d.Type()&fs.ModeSymlink == 0 {
+			return nil
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			return nil
+		}
+		target, _ := os.Readlink(path)
+		dangling = append(dangling, DanglingSymlink{Path: rel, Target: target})
+		return nil
+	})
+	return dangling, err
+}
+
+// RemoveDanglingSymlinks removes the given symlinks from worktreePath and
+// returns the ones it removed. Entries that are no longer dangling symlinks
+// are left alone, so a stale list can't delete real files.
+func (wm *WorktreeManager) RemoveDanglingSymlinks(worktreePath string, links []DanglingSymlink) []DanglingSymlink {
+	var removed []DanglingSymlink
+	for _, link := range links {
+		path := filepath.Join(worktreePath, link.Path)
+		info, err := os.Lstat(path)
+		if err != nil || info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		if _, err := os.Stat(path); !os.IsNotExist(err) {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			logging.Warn("RemoveDanglingSymlinks: failed to remove %s: %v", path, err)
+			continue
+		}
+		removed = append(removed, link)
+	}
+	logging.Debug("RemoveDanglingSymlinks: removed %d of %d in %s", len(removed), len(links), worktreePath)
+	return removed
+}
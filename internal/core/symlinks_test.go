@@ -0,0 +1,72 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestFindDanglingSymlinks(t *testing.T) {
+	wm := &WorktreeManager{}
+	dir := t.TempDir()
+
+	mustSymlink := func(target, link string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(link), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	existing := filepath.Join(t.TempDir(), "real.env")
+	if err := os.WriteFile(existing, []byte("A=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	mustSymlink(existing, filepath.Join(dir, ".env.ok"))
+	mustSymlink("/nonexistent/gren/.env", filepath.Join(dir, ".env"))
+	mustSymlink("../moved/.claude", filepath.Join(dir, "config", ".claude"))
+	// Deeper than danglingSymlinkMaxDepth: not scanned
+	mustSymlink("/nonexistent/deep", filepath.Join(dir, "a", "b", "c", "deep"))
+
+	dangling, err := wm.FindDanglingSymlinks(dir)
+	if err != nil {
+		t.Fatalf("FindDanglingSymlinks() error = %v", err)
+	}
+
+	got := map[string]string{}
+	for _, d := range dangling {
+		got[d.Path] = d.Target
+	}
+	want := map[string]string{
+		".env":                             "/nonexistent/gren/.env",
+		filepath.Join("config", ".claude"): "../moved/.claude",
+	}
+	if len(got) != len(want) {
+		t.Fatalf("FindDanglingSymlinks() = %v, want %v", got, want)
+	}
+	for path, target := range want {
+		if got[path] != target {
+			t.Errorf("dangling[%s] = %q, want %q", path, got[path], target)
+		}
+	}
+
+	// A link that was repaired after the scan must survive removal
+	if err := os.Remove(filepath.Join(dir, ".env")); err != nil {
+		t.Fatal(err)
+	}
+	mustSymlink(existing, filepath.Join(dir, ".env"))
+
+	removed := wm.RemoveDanglingSymlinks(dir, dangling)
+	if len(removed) != 1 || removed[0].Path != filepath.Join("config", ".claude") {
+		t.Errorf("RemoveDanglingSymlinks() removed %v, want only config/.claude", removed)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, ".env")); err != nil {
+		t.Errorf("repaired .env link was removed: %v", err)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, "config", ".claude")); !os.IsNotExist(err) {
+		t.Errorf("dangling config/.claude still present: %v", err)
+	}
+}
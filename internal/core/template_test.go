@@ -1,9 +1,13 @@
 package core
 
 import (
+	"context"
 	"os"
+	"path/filepath"
 	"strconv"
 	"testing"
+
+	"github.com/langtind/gren/internal/config"
 )
 
 func TestHashPort(t *testing.T) {
@@ -31,27 +35,6 @@ func TestHashPort(t *testing.T) {
 	}
 }
 
-func TestSanitizeDB(t *testing.T) {
-	tests := []struct {
-		branch   string
-		expected string
-	}{
-		{"main", "main"},
-		{"feat/Foo-Bar", "feat_foo_bar"},
-		{"feature/JIRA-42", "feature_jira_42"},
-		{"123-start", "_123_start"},
-		{"UPPER", "upper"},
-		{"", ""},
-		{"a.b.c", "a_b_c"},
-	}
-	for _, tt := range tests {
-		got := sanitizeDB(tt.branch)
-		if got != tt.expected {
-			t.Errorf("sanitizeDB(%q) = %q, want %q", tt.branch, got, tt.expected)
-		}
-	}
-}
-
 // EvalTemplate exposes the template engine to scripts (via `gren step eval`),
 // resolving variables against the current worktree.
 func TestEvalTemplate(t *testing.T) {
@@ -72,7 +55,7 @@ func TestEvalTemplate(t *testing.T) {
 		t.Fatalf("EvalTemplate: %v", err)
 	}
 
-	want := "b=main port=" + strconv.Itoa(hashPort("main")) + " db=" + sanitizeDB("main")
+	want := "b=main port=" + strconv.Itoa(hashPort("main")) + " db=" + config.SanitizeDB("main")
 	if got != want {
 		t.Errorf("EvalTemplate = %q, want %q", got, want)
 	}
@@ -81,11 +64,11 @@ func TestEvalTemplate(t *testing.T) {
 func TestExpandTemplateFilters(t *testing.T) {
 	ctx := TemplateContext{
 		Branch:          "feat/My-Thing",
-		BranchSanitized: sanitizeBranch("feat/My-Thing"),
+		BranchSanitized: SanitizeBranch("feat/My-Thing"),
 	}
 
 	wantPort := strconv.Itoa(hashPort("feat/My-Thing"))
-	wantDB := sanitizeDB("feat/My-Thing")
+	wantDB := config.SanitizeDB("feat/My-Thing")
 
 	tests := []struct {
 		template string
@@ -104,3 +87,46 @@ func TestExpandTemplateFilters(t *testing.T) {
 		}
 	}
 }
+
+// The project's sanitize rules name worktree directories and fill in
+// {{ branch | sanitize }} alike, and directories named either way match
+// their branch.
+func TestSanitizeRulesSharedByPathAndTemplates(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	cfg := `{
+		"worktree_dir": "` + filepath.Join(filepath.Dir(dir), "test-worktrees") + `",
+		"version": "1.0.0",
+		"sanitize": {"lowercase": true}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := manager.WorktreePath(context.Background(), "Fix:Login#1", "")
+	if err != nil {
+		t.Fatalf("WorktreePath() error = %v", err)
+	}
+	if got := filepath.Base(path); got != "fix-login-1" {
+		t.Errorf("WorktreePath() directory = %q, want %q", got, "fix-login-1")
+	}
+
+	runGit(t, dir, "checkout", "-q", "-b", "Feat/Issue#7")
+	got, err := manager.EvalTemplate("{{ branch | sanitize }}")
+	if err != nil {
+		t.Fatalf("EvalTemplate() error = %v", err)
+	}
+	if got != "feat-issue-7" {
+		t.Errorf("{{ branch | sanitize }} = %q, want %q", got, "feat-issue-7")
+	}
+
+	for _, name := range []string{"feat-issue-7", "Feat-Issue#7", "Feat-Issue-7"} {
+		if !namedFor(name, "Feat/Issue#7") {
+			t.Errorf("namedFor(%s, Feat/Issue#7) = false, want true", name)
+		}
+	}
+	if namedFor("other", "Feat/Issue#7") {
+		t.Error("namedFor(other, Feat/Issue#7) = true, want false")
+	}
+}
@@ -0,0 +1,90 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// SetUpstreamResult describes the tracking change made by SetUpstream.
+type SetUpstreamResult struct {
+	Worktree string // Worktree directory name
+	Branch   string // Local branch whose upstream was set
+	Upstream string // New upstream, e.g. "origin/feat-x"
+	Previous string // Upstream before the change, "" if there was none
+}
+
+// GetUpstream returns the upstream the branch checked out in worktreePath
+// tracks (e.g. "origin/feat-x"), or "" when none is configured.
+func GetUpstream(worktreePath string) string {
+	cmd := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "--symbolic-full-name", "@{u}")
+	output, err := cmd.Output()
+	if err != nil {
+		// No upstream configured (or detached HEAD) — git exits non-zero.
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// SetUpstream points the branch of the worktree identified by identifier
+// (name, path or branch) at <remote>/<branch>. remote defaults to "origin".
+// The remote branch must already exist: a local-only branch has nothing to
+// track until it is pushed.
+func (wm *WorktreeManager) SetUpstream(ctx context.Context, identifier, remote string) (*SetUpstreamResult, error) {
+	if remote == "" {
+		remote = "origin"
+	}
+
+	worktrees, err := wm.ListWorktrees(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worktrees: %w", err)
+	}
+
+	wt := findWorktree(worktrees, identifier)
+	if wt == nil {
+		return nil, fmt.Errorf("worktree '%s' not found", identifier)
+	}
+	if wt.Branch == "" || wt.Branch == "(detached)" || wt.Branch == "(bare)" {
+		return nil, fmt.Errorf("worktree '%s' has no branch checked out", wt.Name)
+	}
+
+	upstream := remote + "/" + wt.Branch
+	checkCmd := exec.Command("git", "-C", wt.Path, "show-ref", "--verify", "--quiet", "refs/remotes/"+upstream)
+	if checkCmd.Run() != nil {
+		return nil, fmt.Errorf("remote branch '%s' not found; push it first with 'git push -u %s %s' or fetch %s", upstream, remote, wt.Branch, remote)
+	}
+
+	result := &SetUpstreamResult{
+		Worktree: wt.Name,
+		Branch:   wt.Branch,
+		Upstream: upstream,
+		Previous: GetUpstream(wt.Path),
+	}
+
+	setCmd := exec.Command("git", "-C", wt.Path, "branch", "--set-upstream-to", upstream, wt.Branch)
+	if output, err := setCmd.CombinedOutput(); err != nil {
+		return nil, fmt.Errorf("failed to set upstream to %s: %s", upstream, strings.TrimSpace(string(output)))
+	}
+
+	logging.Info("SetUpstream: %s now tracks %s (was %q)", wt.Branch, upstream, result.Previous)
+	return result, nil
+}
+
+// findWorktree resolves a user-supplied name, path or branch to a worktree.
+// Name/path matches win over a branch match, as in DeleteWorktree.
+func findWorktree(worktrees []WorktreeInfo, identifier string) *WorktreeInfo {
+	var match *WorktreeInfo
+	for i := range worktrees {
+		wt := &worktrees[i]
+		if wt.Name == identifier || wt.Path == identifier {
+			return wt
+		}
+		if match == nil && wt.Branch == identifier {
+			match = wt
+		}
+	}
+	return match
+}
@@ -0,0 +1,184 @@
+package core
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"testing"
+)
+
+func TestSetUpstream(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	remoteDir, err := os.MkdirTemp("", "gren-remote-upstream-*")
+	if err != nil {
+		t.Fatalf("failed to create remote dir: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	exec.Command("git", "-C", remoteDir, "init", "--bare").Run()
+	exec.Command("git", "-C", dir, "remote", "add", "origin", remoteDir).Run()
+	exec.Command("git", "-C", dir, "push", "-u", "origin", "HEAD").Run()
+
+	worktreePath, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:        "no-upstream",
+		Branch:      "no-upstream",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+
+	if got := GetUpstream(worktreePath); got != "" {
+		t.Fatalf("GetUpstream() on new local branch = %q, want empty", got)
+	}
+
+	t.Run("errors before the branch is pushed", func(t *testing.T) {
+		if _, err := manager.SetUpstream(context.Background(), "no-upstream", ""); err == nil {
+			t.Fatal("SetUpstream() without remote branch = nil error, want error")
+		}
+	})
+
+	t.Run("tracks origin branch after push", func(t *testing.T) {
+		// Push without -u, the case set-upstream exists for.
+		exec.Command("git", "-C", worktreePath, "push", "origin", "no-upstream").Run()
+
+		result, err := manager.SetUpstream(context.Background(), "no-upstream", "")
+		if err != nil {
+			t.Fatalf("SetUpstream() error: %v", err)
+		}
+		if result.Upstream != "origin/no-upstream" {
+			t.Errorf("result.Upstream = %q, want origin/no-upstream", result.Upstream)
+		}
+		if result.Previous != "" {
+			t.Errorf("result.Previous = %q, want empty", result.Previous)
+		}
+		if got := GetUpstream(worktreePath); got != "origin/no-upstream" {
+			t.Errorf("GetUpstream() = %q, want origin/no-upstream", got)
+		}
+	})
+
+	t.Run("unknown worktree", func(t *testing.T) {
+		if _, err := manager.SetUpstream(context.Background(), "does-not-exist", ""); err == nil {
+			t.Fatal("SetUpstream() for unknown worktree = nil error, want error")
+		}
+	})
+}
+
+func TestEnrichStatusUsesUpstream(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	remoteDir, err := os.MkdirTemp("", "gren-remote-upstream-*")
+	if err != nil {
+		t.Fatalf("failed to create remote dir: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	exec.Command("git", "-C", remoteDir, "init", "--bare").Run()
+	exec.Command("git", "-C", dir, "remote", "add", "origin", remoteDir).Run()
+	exec.Command("git", "-C", dir, "push", "-u", "origin", "HEAD").Run()
+
+	create := func(name string) string {
+		t.Helper()
+		path, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+			Name:        name,
+			Branch:      name,
+			BaseBranch:  "main",
+			IsNewBranch: true,
+		})
+		if err != nil {
+			t.Fatalf("CreateWorktree(%s) error: %v", name, err)
+		}
+		return path
+	}
+	commit := func(path, msg string) {
+		t.Helper()
+		if out, err := exec.Command("git", "-C", path, "commit", "--allow-empty", "-m", msg).CombinedOutput(); err != nil {
+			t.Fatalf("commit failed: %v: %s", err, out)
+		}
+	}
+
+	t.Run("differently named upstream", func(t *testing.T) {
+		path := create("local-name")
+		commit(path, "work")
+		exec.Command("git", "-C", path, "push", "-u", "origin", "local-name:remote-name").Run()
+
+		wt := WorktreeInfo{Path: path, Branch: "local-name"}
+		manager.EnrichStatus(&wt)
+		if wt.UpstreamName != "origin/remote-name" || wt.UnpushedCount != 0 || wt.Status != "clean" {
+			t.Errorf("got upstream %q, %d unpushed, status %q; want origin/remote-name, 0, clean", wt.UpstreamName, wt.UnpushedCount, wt.Status)
+		}
+
+		commit(path, "more work")
+		wt = WorktreeInfo{Path: path, Branch: "local-name"}
+		manager.EnrichStatus(&wt)
+		if wt.UnpushedCount != 1 || wt.Status != "unpushed" {
+			t.Errorf("after a new commit got %d unpushed, status %q; want 1, unpushed", wt.UnpushedCount, wt.Status)
+		}
+	})
+
+	t.Run("pushed without -u", func(t *testing.T) {
+		path := create("no-tracking")
+		commit(path, "work")
+		exec.Command("git", "-C", path, "push", "origin", "no-tracking").Run()
+		commit(path, "more work")
+
+		wt := WorktreeInfo{Path: path, Branch: "no-tracking"}
+		manager.EnrichStatus(&wt)
+		if wt.UpstreamName != "" || wt.UnpushedCount != 1 || wt.Status != "unpushed" {
+			t.Errorf("got upstream %q, %d unpushed, status %q; want none, 1, unpushed", wt.UpstreamName, wt.UnpushedCount, wt.Status)
+		}
+	})
+
+	t.Run("never pushed", func(t *testing.T) {
+		path := create("local-only")
+
+		wt := WorktreeInfo{Path: path, Branch: "local-only"}
+		manager.EnrichStatus(&wt)
+		if wt.UpstreamName != "" || wt.Status != "unpushed" {
+			t.Errorf("got upstream %q, status %q; want none, unpushed", wt.UpstreamName, wt.Status)
+		}
+	})
+}
+
+func TestEnrichStatusWithoutRemote(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	path, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:        "local-work",
+		Branch:      "local-work",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	if out, err := exec.Command("git", "-C", path, "commit", "--allow-empty", "-m", "work").CombinedOutput(); err != nil {
+		t.Fatalf("commit failed: %v: %s", err, out)
+	}
+
+	if manager.HasRemote() {
+		t.Fatal("HasRemote() = true in a repository without remotes")
+	}
+	wt := WorktreeInfo{Path: path, Branch: "local-work"}
+	manager.EnrichStatus(&wt)
+	if !wt.NoRemote || wt.UnpushedCount != 0 || wt.Status != "clean" {
+		t.Errorf("got NoRemote %v, %d unpushed, status %q; want true, 0, clean", wt.NoRemote, wt.UnpushedCount, wt.Status)
+	}
+	if got := manager.CheckGitHubAvailability(); got != GitHubUnavailable {
+		t.Errorf("CheckGitHubAvailability() = %v, want GitHubUnavailable without a remote", got)
+	}
+
+	// Once there is somewhere to push, the same branch is unpushed
+	exec.Command("git", "-C", dir, "remote", "add", "origin", t.TempDir()).Run()
+	manager = NewWorktreeManager(manager.gitRepo, manager.configManager)
+	wt = WorktreeInfo{Path: path, Branch: "local-work"}
+	manager.EnrichStatus(&wt)
+	if wt.NoRemote || wt.Status != "unpushed" {
+		t.Errorf("with a remote got NoRemote %v, status %q; want false, unpushed", wt.NoRemote, wt.Status)
+	}
+}
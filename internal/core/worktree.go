@@ -3,14 +3,19 @@ package core
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"hash/fnv"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"sync/atomic"
+	"time"
+	"unicode"
 
 	"github.com/langtind/gren/internal/config"
 	"github.com/langtind/gren/internal/events"
@@ -26,11 +31,30 @@ type WorktreeManager struct {
 	// as it is parsed from the NDJSON stream. Stored via atomic.Value so
 	// Set/Get don't race with the consumer goroutine. Callback must not block.
 	eventObserver atomic.Value // func(events.Event)
+	// outputObserver is an optional callback invoked for each line a
+	// non-interactive hook prints, as it prints it. Same rules as
+	// eventObserver; it may be called from two goroutines (stdout, stderr).
+	outputObserver atomic.Value // func(string)
 	// forceInteractive, when set, makes every hook run with inherited stdio
 	// (a real TTY) regardless of its own `interactive` setting. Used by
 	// `gren hook-run --interactive` so a caller can run normal hooks in a pane.
 	forceInteractive atomic.Bool
-}
+	// githubConcurrency overrides the github_concurrency config when > 0.
+	githubConcurrency atomic.Int32
+	// githubStatus caches CheckGitHubAvailability (a GitHubStatus). gh won't
+	// be installed or logged in halfway through a command, so it is checked
+	// once per manager.
+	githubStatus atomic.Int32
+	// remoteState caches HasRemote: 0 unchecked, 1 yes, 2 no.
+	remoteState atomic.Int32
+	// defaultBranch caches configuredDefaultBranch, asked for every
+	// worktree in a listing; nil until the config is first read.
+	defaultBranch atomic.Pointer[string]
+}
+
+// DefaultGitHubConcurrency is how many per-branch gh calls run at once when
+// neither SetGitHubConcurrency nor github_concurrency says otherwise.
+const DefaultGitHubConcurrency = 8
 
 // NewWorktreeManager creates a new WorktreeManager
 func NewWorktreeManager(gitRepo git.Repository, configManager *config.Manager) *WorktreeManager {
@@ -40,6 +64,39 @@ func NewWorktreeManager(gitRepo git.Repository, configManager *config.Manager) *
 	}
 }
 
+// SetGitHubConcurrency caps how many per-branch gh calls run at once,
+// overriding the github_concurrency config. Zero restores the config value.
+func (wm *WorktreeManager) SetGitHubConcurrency(n int) {
+	wm.githubConcurrency.Store(int32(n))
+}
+
+// gitHubConcurrency returns the effective per-branch gh call limit.
+func (wm *WorktreeManager) gitHubConcurrency() int {
+	if n := wm.githubConcurrency.Load(); n > 0 {
+		return int(n)
+	}
+	if wm.configManager != nil {
+		if cfg, err := wm.configManager.Load(); err == nil && cfg.GitHubConcurrency > 0 {
+			return cfg.GitHubConcurrency
+		}
+	}
+	return DefaultGitHubConcurrency
+}
+
+// MarkerManager returns a MarkerManager using the marker_ttl config, or
+// DefaultMarkerTTL when it is unset or the config cannot be loaded.
+func (wm *WorktreeManager) MarkerManager() *MarkerManager {
+	mm := NewMarkerManager()
+	if wm.configManager != nil {
+		if cfg, err := wm.configManager.Load(); err == nil && cfg.MarkerTTL != "" {
+			if ttl, err := time.ParseDuration(cfg.MarkerTTL); err == nil {
+				mm.SetTTL(ttl)
+			}
+		}
+	}
+	return mm
+}
+
 // SetEventObserver registers a callback that fires for each hook phase event
 // (including the synthetic interrupted event on non-zero exit) as it is
 // parsed live. Pass nil to clear. The callback must not block the caller;
@@ -52,6 +109,28 @@ func (wm *WorktreeManager) SetEventObserver(fn func(events.Event)) {
 	wm.eventObserver.Store(v)
 }
 
+// SetHookOutputObserver registers a callback that fires for each line of
+// stdout or stderr a non-interactive hook prints, while it runs. Pass nil to
+// clear. Like the event observer it must not block for long: the hook waits
+// on its output until the callback returns.
+func (wm *WorktreeManager) SetHookOutputObserver(fn func(line string)) {
+	var v = fn
+	wm.outputObserver.Store(v)
+}
+
+// emitOutput forwards a line of hook output to the registered observer, if any.
+func (wm *WorktreeManager) emitOutput(line string) {
+	v := wm.outputObserver.Load()
+	if v == nil {
+		return
+	}
+	fn, ok := v.(func(string))
+	if !ok || fn == nil {
+		return
+	}
+	fn(line)
+}
+
 // SetForceInteractive toggles whether all hooks run with inherited stdio (a
 // real TTY) regardless of their own `interactive` setting. `gren hook-run
 // --interactive` sets it so normal, non-interactive hooks can run in a
@@ -80,6 +159,67 @@ type CreateWorktreeRequest struct {
 	BaseBranch  string // Base branch to create from (if creating new branch)
 	IsNewBranch bool   // Whether to create a new branch
 	WorktreeDir string // Base directory for worktrees
+	// PreferRemote forces creation from origin/<branch> even when a local
+	// branch exists and is ahead, bypassing the ahead/behind heuristic
+	// (`gren create --track-remote`). The local branch is reset to the
+	// remote ref, so unpushed local commits are not in the worktree.
+	PreferRemote bool
+	// Remote creates the branch from <Remote>/<branch> and makes it track
+	// that ref (`gren create --remote upstream`), e.g. for a fork's upstream
+	// repository. Empty means origin with the usual local/remote choice.
+	Remote string
+	// ExplicitPath places the worktree at exactly this path (`gren create
+	// --path`), bypassing WorktreeDir, worktree_dir and its templates. A
+	// relative path is relative to the working directory. It must not exist
+	// yet or lie inside another worktree.
+	ExplicitPath string
+	// Preset names a configured preset whose base branch (when BaseBranch
+	// is empty), copy_files and symlink_files apply to this worktree. Its
+	// post-create hook is run by the caller, with RunPostCreateHookWithPreset.
+	Preset string
+	// PostCreate, when set, runs the post-create hooks once the worktree is
+	// set up, e.g. RunPostCreateHookWithPreset with its approval prompt.
+	// Their failures are added to the returned warning and, with
+	// hooks_required, returned as ErrHooksRequired along with the path.
+	PostCreate func(worktreePath string) []HookResult
+}
+
+// StaleReasons lists every value WorktreeInfo.StaleReason can take.
+var StaleReasons = []string{"merged_locally", "no_unique_commits", "remote_gone", "pr_merged", "pr_closed", "branch_deleted"}
+
+// ParseStaleReasons parses a comma-separated list of stale reasons, e.g.
+// "pr_merged,remote_gone", into a set. Unknown reasons are an error.
+func ParseStaleReasons(list string) (map[string]bool, error) {
+	reasons := make(map[string]bool)
+	for _, r := range strings.Split(list, ",") {
+		r = strings.TrimSpace(r)
+		if r == "" {
+			continue
+		}
+		if !slices.Contains(StaleReasons, r) {
+			return nil, fmt.Errorf("unknown stale reason '%s' (must be one of: %s)", r, strings.Join(StaleReasons, ", "))
+		}
+		reasons[r] = true
+	}
+	if len(reasons) == 0 {
+		return nil, fmt.Errorf("no stale reasons given (must be one of: %s)", strings.Join(StaleReasons, ", "))
+	}
+	return reasons, nil
+}
+
+// HasUncommittedChanges reports whether wt has staged, modified or
+// untracked files.
+func (wt WorktreeInfo) HasUncommittedChanges() bool {
+	return wt.StagedCount > 0 || wt.ModifiedCount > 0 || wt.UntrackedCount > 0
+}
+
+// SafeToCleanUp reports whether the stale worktree wt can be deleted
+// without a second look, the ones cleanup picks for the user: its PR was
+// merged and it has no uncommitted changes, no git operation in progress
+// and a status git could read. A branch with no unique commits may just
+// have been started, so it is never picked for the user.
+func (wt WorktreeInfo) SafeToCleanUp() bool {
+	return wt.StaleReason == "pr_merged" && !wt.HasUncommittedChanges() && wt.Operation == "" && wt.StatusError == ""
 }
 
 // WorktreeInfo represents basic worktree information
@@ -89,18 +229,30 @@ type WorktreeInfo struct {
 	Branch         string
 	IsCurrent      bool
 	IsPrevious     bool   // True if this was the most recently active worktree (i.e. `gren switch -` target)
-	IsMain         bool   // True if this is the main worktree (where .git directory lives)
-	Status         string // "clean", "modified", "untracked", "mixed", "unpushed", "missing"
+	IsMain         bool   // True for the main worktree (the bare repository in a bare clone), which git lists first
+	IsBare         bool   // True for the repository itself in a bare clone: no checkout, never current, never deleted
+	Status         string // "clean", "modified", "untracked", "mixed", "unpushed", "missing", "unknown"
+	StatusError    string // Why git status failed when Status is "unknown" (e.g. a held index.lock); the file counts are then meaningless
 	LastCommit     string // Relative time of last commit (e.g., "2 hours ago")
 	StagedCount    int    // Number of staged files (ready to commit)
 	ModifiedCount  int    // Number of modified files (not staged)
 	UntrackedCount int    // Number of untracked files
 	UnpushedCount  int    // Number of unpushed commits
+	UpstreamName   string // Upstream the branch tracks (e.g. "origin/feat-x"), "" when none is configured
 	HasSubmodules  bool   // True if worktree contains .gitmodules (requires --force to delete)
+	SubmoduleState string // Submodules out of step with the commit, e.g. "1 modified, 2 uninitialized"; "" when all match
+	Operation      string // Git operation stopped halfway: "rebase", "merge", "cherry-pick", "revert" or "" (requires --force to delete)
+	BranchMismatch bool   // True when the directory is named for another branch than the one checked out (see HasBranchMismatch)
+	BrokenLink     bool   // True when the worktree and the repository no longer point at each other (see HasBrokenLink); fixed by RepairWorktrees
+	NoRemote       bool   // True when the repository has no remote, so there is nothing to push to and UnpushedCount means nothing
+
+	// When gren created the worktree, else its directory's mtime (worktrees
+	// from `git worktree add`); zero for the main worktree
+	Created time.Time
 
 	// Stale detection fields
 	BranchStatus string // "active", "stale", or "" if not yet checked
-	StaleReason  string // "merged_locally", "no_unique_commits", "remote_gone", "pr_merged", "pr_closed"
+	StaleReason  string // "merged_locally", "no_unique_commits", "remote_gone", "pr_merged", "pr_closed", "branch_deleted"
 
 	// GitHub PR fields (populated async, empty if gh unavailable or no PR)
 	PRNumber int    // PR number, 0 if no PR
@@ -108,9 +260,9 @@ type WorktreeInfo struct {
 	PRURL    string // Full URL to PR for "Open in browser"
 
 	// CI status fields (populated async via gh CLI)
-	CIStatus     string // "success", "failure", "pending", "error", "" if unknown
-	CIConclusion string // Detailed conclusion from GitHub Actions
-	ChecksURL    string // URL to checks page
+	CIStatus     string // "success", "failure", "pending", "none" (no CI), "unknown", "" if not checked
+	CIConclusion string // Summary of the checks, e.g. "1 of 4 checks failed"
+	CIURL        string // Failing or running check, else the PR's checks page
 
 	Marker MarkerType
 }
@@ -123,6 +275,17 @@ type MergeOptions struct {
 	Rebase bool
 	Yes    bool
 	Force  bool
+	// IntoCurrent merges Target into the current branch with git merge,
+	// instead of landing the current branch on Target. Squash, Remove,
+	// Rebase and hooks don't apply.
+	IntoCurrent bool
+	// AbortOnConflict aborts an IntoCurrent merge that stops on conflicts,
+	// leaving the worktree as it was. Without it the conflicts are left in
+	// place to resolve.
+	AbortOnConflict bool
+	// DryRun fills in the result and its Steps without running hooks or
+	// any git command that changes something.
+	DryRun bool
 }
 
 type MergeResult struct {
@@ -133,6 +296,39 @@ type MergeResult struct {
 	WorktreePath    string
 	Skipped         bool
 	SkipReason      string
+	Warnings        []string // Problems after the merge landed: failed hooks, a worktree that couldn't be removed
+	Conflicts       []string // Conflicted files when the merge or rebase stopped, relative to the worktree
+	DryRun          bool     // Nothing was done; CommitsSquashed and WorktreeRemoved say what would be
+	Steps           []string // With DryRun, what the merge would do, in order
+}
+
+// MergeConflictError is returned by Merge when a rebase or merge stops on
+// conflicts. Its message lists the files and what to do next.
+type MergeConflictError struct {
+	Op      string   // "rebase" or "merge"
+	Source  string   // Branch being rebased or merged in
+	Target  string   // Branch it was rebased onto or merged into
+	Files   []string // Conflicted files, relative to the worktree
+	Aborted bool     // The rebase or merge was aborted, so the worktree is as it was
+}
+
+func (e *MergeConflictError) Error() string {
+	var b strings.Builder
+	if e.Op == "rebase" {
+		fmt.Fprintf(&b, "rebasing %s onto %s", e.Source, e.Target)
+	} else {
+		fmt.Fprintf(&b, "merging %s into %s", e.Source, e.Target)
+	}
+	fmt.Fprintf(&b, " stopped on conflicts in %d file(s):", len(e.Files))
+	for _, f := range e.Files {
+		b.WriteString("\n  " + f)
+	}
+	if e.Aborted {
+		fmt.Fprintf(&b, "\nThe %s was aborted; nothing changed.", e.Op)
+	} else {
+		fmt.Fprintf(&b, "\nResolve them, git add them and run git %[1]s --continue, or run git %[1]s --abort to undo.", e.Op)
+	}
+	return b.String()
 }
 
 // ForEachOptions contains parameters for running a command in all worktrees
@@ -184,68 +380,98 @@ func (wm *WorktreeManager) CheckPrerequisites() error {
 // CreateWorktree creates a new worktree with the given parameters
 // Returns a warning message (if any) and an error
 func (wm *WorktreeManager) CreateWorktree(ctx context.Context, req CreateWorktreeRequest) (worktreePath string, warning string, err error) {
+	worktreePath, warning, cfg, err := wm.createWorktree(ctx, req)
+	if err != nil || req.PostCreate == nil {
+		return worktreePath, warning, err
+	}
+
+	// The hooks run after the repository lock is released; a dependency
+	// install can take minutes
+	hookWarning, err := PostCreateHookFailure(cfg, req.PostCreate(worktreePath), worktreePath)
+	if hookWarning != "" {
+		logging.Warn("CreateWorktree: %s", hookWarning)
+		warning = strings.TrimPrefix(warning+"\n"+hookWarning, "\n")
+	}
+	return worktreePath, warning, err
+}
+
+// createWorktree creates the worktree for CreateWorktree under the
+// repository lock and returns the configuration it used.
+func (wm *WorktreeManager) createWorktree(ctx context.Context, req CreateWorktreeRequest) (worktreePath string, warning string, cfg *config.Config, err error) {
 	logging.Info("CreateWorktree called: name=%s, branch=%s, base=%s, isNew=%v", req.Name, req.Branch, req.BaseBranch, req.IsNewBranch)
 
 	// Check prerequisites
 	if err := wm.CheckPrerequisites(); err != nil {
 		logging.Error("Prerequisites check failed: %v", err)
-		return "", "", err
+		return "", "", nil, err
 	}
 
-	// Fetch latest from origin to ensure we have up-to-date remote refs
+	// Fetch latest from origin to ensure we have up-to-date remote refs.
+	// This happens before taking the repository lock: a slow or retried
+	// fetch would otherwise keep every other gren process waiting.
 	wm.FetchOrigin()
 
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return "", "", nil, err
+	}
+	defer lock.Unlock()
+
 	// Load configuration
-	cfg, err := wm.configManager.Load()
+	cfg, err = wm.configManager.Load()
 	if err != nil {
 		logging.Error("Failed to load configuration: %v", err)
-		return "", "", fmt.Errorf("failed to load configuration: %w", err)
+		return "", "", nil, fmt.Errorf("failed to load configuration: %w", err)
 	}
 
-	// Determine worktree path
-	worktreeDir := req.WorktreeDir
-	if worktreeDir == "" {
-		worktreeDir = cfg.WorktreeDir
-	}
-	// An empty dir needs the repo name for the default; a templated dir needs it
-	// to expand (e.g. worktree_dir = "../{{ repo }}-worktrees").
-	if worktreeDir == "" || strings.Contains(worktreeDir, "{{") {
-		repoInfo, err := wm.gitRepo.GetRepoInfo(ctx)
+	// A preset's settings apply as if the project config had them
+	var symlinkFiles []string
+	if req.Preset != "" {
+		preset, err := cfg.Preset(req.Preset)
 		if err != nil {
-			logging.Error("Failed to get repo info: %v", err)
-			return "", "", fmt.Errorf("failed to get repo info: %w", err)
+			return "", "", nil, err
 		}
-		if worktreeDir == "" {
-			worktreeDir = fmt.Sprintf("../%s-worktrees", repoInfo.Name)
-			logging.Debug("Using default worktree_dir: %s", worktreeDir)
-		} else {
-			branchForTmpl := req.Branch
-			if branchForTmpl == "" {
-				branchForTmpl = req.Name
-			}
-			worktreeDir = expandTemplate(worktreeDir, TemplateContext{
-				Repo:            repoInfo.Name,
-				Branch:          branchForTmpl,
-				BranchSanitized: sanitizeBranch(branchForTmpl),
-			})
-			logging.Debug("Using worktree_dir from config (expanded): %s", worktreeDir)
+		if req.BaseBranch == "" {
+			req.BaseBranch = preset.BaseBranch
 		}
-	} else {
-		logging.Debug("Using worktree_dir from config: %s", worktreeDir)
+		if len(preset.CopyFiles) > 0 {
+			withPreset := *cfg
+			withPreset.CopyFiles = append(slices.Clone(cfg.CopyFiles), preset.CopyFiles...)
+			cfg = &withPreset
+		}
+		symlinkFiles = preset.SymlinkFiles
 	}
 
-	// Sanitize worktree name: replace / with - to avoid nested directories
-	worktreeName := strings.ReplaceAll(req.Name, "/", "-")
-	worktreePath = filepath.Join(worktreeDir, worktreeName)
+	// Determine worktree path
+	var worktreeDir string
+	if req.ExplicitPath != "" {
+		worktreePath, err = wm.checkExplicitPath(ctx, req.ExplicitPath)
+		if err != nil {
+			return "", "", nil, err
+		}
+		worktreeDir = filepath.Dir(worktreePath)
+	} else {
+		worktreePath, err = wm.worktreePath(ctx, cfg, req.WorktreeDir, req.Name, req.Branch)
+		if err != nil {
+			return "", "", nil, err
+		}
+		worktreeDir = filepath.Dir(worktreePath)
+	}
 	logging.Debug("Worktree path: %s", worktreePath)
 
+	if err := clearWorktreePath(worktreePath, true); err != nil {
+		logging.Error("Worktree path collision: %v", err)
+		return "", "", nil, err
+	}
+
 	// Create worktree directory if it doesn't exist
 	if _, err := os.Stat(worktreeDir); os.IsNotExist(err) {
 		logging.Debug("Creating worktree directory: %s", worktreeDir)
 		if err := os.MkdirAll(worktreeDir, 0755); err != nil {
 			logging.Error("Failed to create worktree directory: %v", err)
-			return "", "", fmt.Errorf("failed to create worktree directory: %w", err)
+			return "", "", nil, fmt.Errorf("failed to create worktree directory: %w", err)
 		}
+		wm.recordCreatedWorktreeDir(worktreeDir)
 	}
 
 	// Create the git worktree with smart branch detection
@@ -265,12 +491,54 @@ func (wm *WorktreeManager) CreateWorktree(ctx context.Context, req CreateWorktre
 		listOutput, _ := worktreeListCmd.Output()
 		if strings.Contains(string(listOutput), "["+branchName+"]") {
 			logging.Error("Branch already checked out in another worktree: %s", branchName)
-			return "", "", fmt.Errorf("branch '%s' is already checked out in another worktree", branchName)
+			return "", "", nil, fmt.Errorf("branch '%s' is already checked out in another worktree", branchName)
 		}
 	}
 
 	var gitCmd string
-	if syncStatus.LocalExists || syncStatus.RemoteExists {
+	if req.Remote != "" {
+		remoteRef, err := wm.resolveRemoteBranch(req.Remote, branchName)
+		if err != nil {
+			logging.Error("CreateWorktree: %v", err)
+			return "", "", nil, err
+		}
+		warning = ""
+		if syncStatus.LocalExists {
+			if !req.PreferRemote {
+				return "", "", nil, fmt.Errorf("branch '%s' already exists locally; add --track-remote to reset it to %s", branchName, remoteRef)
+			}
+			gitCmd = fmt.Sprintf("git worktree add --track -B %s %s %s", branchName, worktreePath, remoteRef)
+			logging.Info("Resetting local branch to %s", remoteRef)
+			cmd = exec.Command("git", "worktree", "add", "--track", "-B", branchName, worktreePath, remoteRef)
+		} else {
+			gitCmd = fmt.Sprintf("git worktree add --track -b %s %s %s", branchName, worktreePath, remoteRef)
+			logging.Info("Creating local branch from %s", remoteRef)
+			cmd = exec.Command("git", "worktree", "add", "--track", "-b", branchName, worktreePath, remoteRef)
+		}
+	} else if req.PreferRemote {
+		// --track-remote: always start from origin/<branch>, e.g. after a
+		// force-push made the local copy obsolete.
+		if !syncStatus.RemoteExists {
+			logging.Error("Branch not found on remote (--track-remote): %s", branchName)
+			return "", "", nil, fmt.Errorf("branch '%s' not found on origin; --track-remote requires a remote branch", branchName)
+		}
+		remoteRef := "origin/" + branchName
+		warning = ""
+		if syncStatus.Ahead > 0 {
+			warning = fmt.Sprintf("%s had %d unpushed commit(s) that are NOT in the worktree - branch reset to %s (recover them via git reflog)", branchName, syncStatus.Ahead, remoteRef)
+			logging.Warn("CreateWorktree: %s", warning)
+		}
+		if syncStatus.LocalExists {
+			// -B resets the existing local branch to the remote ref
+			gitCmd = fmt.Sprintf("git worktree add --track -B %s %s %s", branchName, worktreePath, remoteRef)
+			logging.Info("Resetting local branch to remote (--track-remote): %s", remoteRef)
+			cmd = exec.Command("git", "worktree", "add", "--track", "-B", branchName, worktreePath, remoteRef)
+		} else {
+			gitCmd = fmt.Sprintf("git worktree add --track -b %s %s %s", branchName, worktreePath, remoteRef)
+			logging.Info("Creating local branch from remote (--track-remote): %s", remoteRef)
+			cmd = exec.Command("git", "worktree", "add", "--track", "-b", branchName, worktreePath, remoteRef)
+		}
+	} else if syncStatus.LocalExists || syncStatus.RemoteExists {
 		// Branch exists - use the best source ref (local if ahead, remote otherwise)
 		sourceRef := syncStatus.SourceRef
 
@@ -308,10 +576,10 @@ func (wm *WorktreeManager) CreateWorktree(ctx context.Context, req CreateWorktre
 		baseBranch := req.BaseBranch
 		if baseBranch == "" {
 			// Get recommended base branch
-			baseBranch, err = wm.gitRepo.GetRecommendedBaseBranch(ctx)
+			baseBranch, err = wm.RecommendedBaseBranch(ctx)
 			if err != nil {
 				logging.Error("Failed to get recommended base branch: %v", err)
-				return "", "", fmt.Errorf("failed to get recommended base branch: %w", err)
+				return "", "", nil, fmt.Errorf("failed to get recommended base branch: %w", err)
 			}
 		}
 
@@ -331,7 +599,7 @@ func (wm *WorktreeManager) CreateWorktree(ctx context.Context, req CreateWorktre
 	} else {
 		// User explicitly wanted existing branch but it doesn't exist
 		logging.Error("Branch not found locally or on remote: %s", branchName)
-		return "", "", fmt.Errorf("branch '%s' not found locally or on remote", branchName)
+		return "", "", nil, fmt.Errorf("branch '%s' not found locally or on remote", branchName)
 	}
 
 	logging.Debug("Running: %s", gitCmd)
@@ -339,32 +607,79 @@ func (wm *WorktreeManager) CreateWorktree(ctx context.Context, req CreateWorktre
 	if err != nil {
 		logging.Error("git worktree add failed: %v, output: %s", err, string(output))
 		if len(output) == 0 {
-			return "", "", fmt.Errorf("git worktree add failed: %w", err)
+			return "", "", nil, fmt.Errorf("git worktree add failed: %w", err)
 		}
-		return "", "", fmt.Errorf("git worktree add failed: %s", string(output))
+		return "", "", nil, fmt.Errorf("git worktree add failed: %s", string(output))
 	}
+	recordWorktreeCreated(worktreePath, branchName, time.Now())
 
 	// Ensure the branch tracks the correct remote (origin/<branchName>)
-	// This fixes issues where branches inherit incorrect upstream from their parent branch
-	wm.setCorrectUpstream(worktreePath, branchName)
+	// This fixes issues where branches inherit incorrect upstream from their parent branch.
+	// With an explicit remote, --track already set the upstream.
+	if req.Remote == "" {
+		wm.setCorrectUpstream(worktreePath, branchName)
+	}
+
+	// A reused branch may have been left behind its base long ago
+	if syncStatus.LocalExists || syncStatus.RemoteExists {
+		if behindWarning := wm.behindBaseWarning(ctx, worktreePath, branchName, req.BaseBranch); behindWarning != "" {
+			warning = appendWarning(warning, behindWarning)
+		}
+	}
 
 	// Initialize submodules in the new worktree
-	if _, err := os.Stat(".gitmodules"); err == nil {
+	if _, err := os.Stat(filepath.Join(worktreePath, ".gitmodules")); err == nil {
 		submoduleCmd := exec.Command("git", "-C", worktreePath, "submodule", "update", "--init", "--recursive")
 		if err := submoduleCmd.Run(); err != nil {
 			logging.Warn("Failed to initialize submodules: %v", err)
 		}
 	}
 
+	// Copy copy_files, link a preset's symlink_files and render
+	// per-worktree env values before the post-create hook, which may depend
+	// on them. A failure leaves the worktree in place with a warning.
+	if _, err := wm.copyConfiguredFiles(cfg, worktreePath); err != nil {
+		logging.Warn("CreateWorktree: copy_files: %v", err)
+		warning = appendWarning(warning, fmt.Sprintf("copy_files incomplete: %v", err))
+	}
+	if _, err := wm.symlinkConfiguredFiles(symlinkFiles, worktreePath); err != nil {
+		logging.Warn("CreateWorktree: symlink_files: %v", err)
+		warning = appendWarning(warning, fmt.Sprintf("symlink_files incomplete: %v", err))
+	}
+	if _, err := wm.renderEnvTemplate(cfg, worktreePath, branchName); err != nil {
+		logging.Warn("CreateWorktree: env_template: %v", err)
+		warning = appendWarning(warning, fmt.Sprintf("env_template not rendered: %v", err))
+	}
+
 	// Note: Post-create hook is now run by caller with approval checking
 	// See CLI handleCreate() and TUI create flow
 
 	logging.Info("Created worktree '%s' at %s", req.Name, worktreePath)
-	return worktreePath, warning, nil
+	return worktreePath, warning, cfg, nil
 }
 
 // ListWorktrees returns a list of all worktrees with full status information
 func (wm *WorktreeManager) ListWorktrees(ctx context.Context) ([]WorktreeInfo, error) {
+	worktrees, err := wm.ListWorktreesBasic(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// Enrich worktrees with status information
+	for i := range worktrees {
+		wm.EnrichStatus(&worktrees[i])
+	}
+
+	wm.EnrichStaleStatus(worktrees)
+
+	return worktrees, nil
+}
+
+// ListWorktreesBasic returns the worktrees with only what is cheap to
+// determine: branch, path, main/current/previous flags, markers and last
+// commit time. File counts, Status and BranchStatus are left empty; fill
+// them in with EnrichStatus and EnrichStaleStatus.
+func (wm *WorktreeManager) ListWorktreesBasic(ctx context.Context) ([]WorktreeInfo, error) {
 	cmd := exec.Command("git", "worktree", "list", "--porcelain")
 	output, err := cmd.CombinedOutput()
 	if err != nil {
@@ -374,27 +689,30 @@ func (wm *WorktreeManager) ListWorktrees(ctx context.Context) ([]WorktreeInfo, e
 
 	worktrees := wm.parseWorktreeList(string(output))
 
-	// Detect main worktree dynamically by checking if .git is a directory (not a file)
-	// In main worktree: .git is a directory
-	// In linked worktrees: .git is a file containing "gitdir: /path/to/.git/worktrees/name"
-	for i := range worktrees {
-		gitPath := filepath.Join(worktrees[i].Path, ".git")
-		if info, err := os.Stat(gitPath); err == nil && info.IsDir() {
-			worktrees[i].IsMain = true
+	// git lists the main worktree first, but where its git dir is not
+	// <root>/.git (a submodule, --separate-git-dir, GIT_DIR) it reports the
+	// git dir as the main worktree's path.
+	if len(worktrees) > 0 && !worktrees[0].IsBare {
+		if root := config.MainWorktreeRoot(); root != "" && root != worktrees[0].Path {
+			worktrees[0].Path, worktrees[0].Name = root, filepath.Base(root)
+			worktrees[0].IsCurrent = samePath(root, currentWorktreeRoot())
 		}
 	}
 
-	// Enrich worktrees with status information
-	for i := range worktrees {
-		wm.enrichWorktreeStatus(&worktrees[i])
-	}
-
-	// Build stale cache once (runs git commands only once for all worktrees)
-	cache := wm.buildStaleCache()
-
-	// Enrich with stale status using cached data
+	// git always lists the main worktree first. Whether its .git is a
+	// directory says nothing reliable: a submodule's checkout has a .git
+	// file, and with GIT_DIR or core.worktree the git dir lives elsewhere.
+	// In a bare clone the bare repository itself takes the main worktree's
+	// place, so it gets the same protection.
 	for i := range worktrees {
-		wm.enrichStaleStatusCached(&worktrees[i], cache)
+		worktrees[i].IsMain = i == 0
+		worktrees[i].BranchMismatch = HasBranchMismatch(worktrees[i])
+		// Needed up front: the TUI sorts by it before status arrives
+		if worktrees[i].Status != "missing" {
+			worktrees[i].BrokenLink = !worktrees[i].IsMain && HasBrokenLink(worktrees[i].Path)
+			worktrees[i].LastCommit = getLastCommitTime(worktrees[i].Path)
+			worktrees[i].Created = worktreeCreated(worktrees[i].Path)
+		}
 	}
 
 	wm.enrichMarkers(ctx, worktrees)
@@ -422,23 +740,61 @@ func (wm *WorktreeManager) ListWorktrees(ctx context.Context) ([]WorktreeInfo, e
 	return worktrees, nil
 }
 
-// enrichWorktreeStatus adds detailed status information to a worktree
-func (wm *WorktreeManager) enrichWorktreeStatus(wt *WorktreeInfo) {
+// EnrichStaleStatus sets BranchStatus and StaleReason from git alone
+// (merged into the base branch, or remote gone). It lists merged and gone
+// branches once for all worktrees.
+func (wm *WorktreeManager) EnrichStaleStatus(worktrees []WorktreeInfo) {
+	// Build stale cache once (runs git commands only once for all worktrees)
+	cache := wm.buildStaleCache()
+
+	// Enrich with stale status using cached data
+	for i := range worktrees {
+		wm.enrichStaleStatusCached(&worktrees[i], cache)
+	}
+}
+
+// EnrichStatus fills in wt's file counts, upstream, unpushed count and Status. It
+// only runs git inside wt, so it is safe to call concurrently for different
+// worktrees.
+func (wm *WorktreeManager) EnrichStatus(wt *WorktreeInfo) {
 	// Skip if worktree is missing
 	if wt.Status == "missing" {
 		return
 	}
+	// A bare repository has no working tree to be dirty
+	if wt.IsBare {
+		wt.Status = "clean"
+		return
+	}
 
 	// Check for submodules (affects deletion - requires --force)
 	if _, err := os.Stat(filepath.Join(wt.Path, ".gitmodules")); err == nil {
 		wt.HasSubmodules = true
+		wt.SubmoduleState = getSubmoduleState(wt.Path)
 	}
 
+	wt.Operation = OperationInProgress(wt.Path)
+
 	// Get file counts
-	wt.StagedCount, wt.ModifiedCount, wt.UntrackedCount = getFileCounts(wt.Path, wt.IsCurrent)
+	var err error
+	wt.StagedCount, wt.ModifiedCount, wt.UntrackedCount, err = getFileCounts(wt.Path, wt.IsCurrent)
+
+	// Get unpushed count, against the upstream when one is configured.
+	// Without a remote every commit would count as unpushed, so none do.
+	wt.NoRemote = !wm.HasRemote()
+	if !wt.NoRemote {
+		wt.UpstreamName = GetUpstream(wt.Path)
+		wt.UnpushedCount = getUnpushedCount(wt.Path, wt.IsCurrent, wt.Branch, wt.UpstreamName)
+	}
 
-	// Get unpushed count
-	wt.UnpushedCount = getUnpushedCount(wt.Path, wt.IsCurrent)
+	// A failed git status (another process holding index.lock, a corrupt
+	// index) says nothing about the worktree, so it must not read as clean
+	if err != nil {
+		logging.Warn("EnrichStatus: git status failed in %s: %v", wt.Path, err)
+		wt.Status = "unknown"
+		wt.StatusError = err.Error()
+		return
+	}
 
 	// Determine status based on counts
 	hasModified := wt.StagedCount > 0 || wt.ModifiedCount > 0
@@ -450,17 +806,15 @@ func (wm *WorktreeManager) enrichWorktreeStatus(wt *WorktreeInfo) {
 		wt.Status = "modified"
 	} else if hasUntracked {
 		wt.Status = "untracked"
-	} else if wt.UnpushedCount > 0 || isNotPushedToRemote(wt.Path, wt.IsCurrent) {
+	} else if wt.UnpushedCount > 0 || (!wt.NoRemote && wt.UpstreamName == "" && isNotPushedToRemote(wt.Path, wt.IsCurrent)) {
 		wt.Status = "unpushed"
 	} else {
 		wt.Status = "clean"
 	}
-
-	wt.LastCommit = getLastCommitTime(wt.Path)
 }
 
 func (wm *WorktreeManager) enrichMarkers(ctx context.Context, worktrees []WorktreeInfo) {
-	mm := NewMarkerManager()
+	mm := wm.MarkerManager()
 	markers, err := mm.ListMarkers(ctx)
 	if err != nil {
 		logging.Warn("Failed to list markers: %v", err)
@@ -474,7 +828,55 @@ func (wm *WorktreeManager) enrichMarkers(ctx context.Context, worktrees []Worktr
 	}
 }
 
-func getFileCounts(worktreePath string, isCurrent bool) (staged, modified, untracked int) {
+// getSubmoduleState summarizes `git submodule status` in a worktree: how
+// many submodules are checked out at another commit than the superproject
+// records, not initialized, or in conflict. It returns "" when all match or
+// git fails, since the summary is only informational.
+func getSubmoduleState(worktreePath string) string {
+	output, err := exec.Command("git", "-C", worktreePath, "submodule", "status").Output()
+	if err != nil {
+		logging.Debug("getSubmoduleState: git submodule status failed in %s: %v", worktreePath, err)
+		return ""
+	}
+	return summarizeSubmoduleState(string(output))
+}
+
+// summarizeSubmoduleState turns `git submodule status` output, one
+// submodule per line prefixed by ' ' (in sync), '+' (other commit checked
+// out), '-' (not initialized) or 'U' (merge conflict), into a summary such
+// as "1 modified, 2 uninitialized".
+func summarizeSubmoduleState(output string) string {
+	var modified, uninitialized, conflicted int
+	for _, line := range strings.Split(output, "\n") {
+		if line == "" {
+			continue
+		}
+		switch line[0] {
+		case '+':
+			modified++
+		case '-':
+			uninitialized++
+		case 'U':
+			conflicted++
+		}
+	}
+
+	var parts []string
+	if modified > 0 {
+		parts = append(parts, fmt.Sprintf("%d modified", modified))
+	}
+	if uninitialized > 0 {
+		parts = append(parts, fmt.Sprintf("%d uninitialized", uninitialized))
+	}
+	if conflicted > 0 {
+		parts = append(parts, fmt.Sprintf("%d conflicted", conflicted))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// getFileCounts counts the worktree's staged, modified and untracked files.
+// It returns an error, rather than zero counts, when git status fails.
+func getFileCounts(worktreePath string, isCurrent bool) (staged, modified, untracked int, err error) {
 	var cmd *exec.Cmd
 	if isCurrent {
 		cmd = exec.Command("git", "status", "--porcelain")
@@ -484,7 +886,13 @@ func getFileCounts(worktreePath string, isCurrent bool) (staged, modified, untra
 
 	output, err := cmd.Output()
 	if err != nil {
-		return 0, 0, 0
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			if msg, _, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(string(exitErr.Stderr)), "fatal: "), "\n"); msg != "" {
+				return 0, 0, 0, fmt.Errorf("git status failed: %s", msg)
+			}
+		}
+		return 0, 0, 0, fmt.Errorf("git status failed: %w", err)
 	}
 
 	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
@@ -512,31 +920,40 @@ func getFileCounts(worktreePath string, isCurrent bool) (staged, modified, untra
 		}
 	}
 
-	return staged, modified, untracked
+	return staged, modified, untracked, nil
 }
 
-// getUnpushedCount returns the number of unpushed commits
-func getUnpushedCount(worktreePath string, isCurrent bool) int {
+// getUnpushedCount returns the number of commits on HEAD that upstream
+// lacks. A branch without an upstream is compared to origin/<branch>, in
+// case it was pushed without -u, and counts as 0 when that doesn't exist
+// either; isNotPushedToRemote covers that case.
+func getUnpushedCount(worktreePath string, isCurrent bool, branch, upstream string) int {
+	if upstream == "" {
+		if branch == "" || branch == "(detached)" || branch == "(bare)" {
+			return 0
+		}
+		upstream = "origin/" + branch
+	}
+
 	var cmd *exec.Cmd
 	if isCurrent {
-		cmd = exec.Command("git", "log", "@{u}..HEAD", "--oneline")
+		cmd = exec.Command("git", "rev-list", "--count", upstream+"..HEAD")
 	} else {
-		cmd = exec.Command("git", "-C", worktreePath, "log", "@{u}..HEAD", "--oneline")
+		cmd = exec.Command("git", "-C", worktreePath, "rev-list", "--count", upstream+"..HEAD")
 	}
 
 	output, err := cmd.Output()
 	if err != nil {
 		return 0
 	}
-
-	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
-	if len(lines) > 0 && lines[0] != "" {
-		return len(lines)
-	}
-	return 0
+	count, _ := strconv.Atoi(strings.TrimSpace(string(output)))
+	return count
 }
 
-// isNotPushedToRemote checks if branch doesn't exist on remote
+// isNotPushedToRemote checks if branch doesn't exist on remote. It only
+// means "never pushed" for a branch with no upstream configured: one that
+// tracks a differently named branch (git push -u origin other) has no
+// origin/<branch> but is pushed all the same.
 func isNotPushedToRemote(worktreePath string, isCurrent bool) bool {
 	// Get current branch
 	var branchCmd *exec.Cmd
@@ -599,6 +1016,49 @@ func getLastCommitTime(worktreePath string) string {
 	return result
 }
 
+// behindBaseWarnCommits is how many commits a reused branch may be behind
+// its base before CreateWorktree warns about it.
+const behindBaseWarnCommits = 10
+
+// behindBaseWarning returns a warning when the branch checked out at
+// worktreePath is at least behindBaseWarnCommits behind its base branch
+// (baseBranch, or the recommended base when empty), counted against the
+// freshest copy of the base as GetBranchSyncStatus picks it. It returns ""
+// when the branch is the base or the count cannot be made.
+func (wm *WorktreeManager) behindBaseWarning(ctx context.Context, worktreePath, branch, baseBranch string) string {
+	if baseBranch == "" {
+		var err error
+		if baseBranch, err = wm.RecommendedBaseBranch(ctx); err != nil {
+			return ""
+		}
+	}
+	if baseBranch == branch {
+		return ""
+	}
+	baseRef := wm.GetBranchSyncStatus(baseBranch).SourceRef
+	if baseRef == "" {
+		return ""
+	}
+	output, err := exec.Command("git", "-C", worktreePath, "rev-list", "--count", "HEAD.."+baseRef).Output()
+	if err != nil {
+		return ""
+	}
+	behind, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil || behind < behindBaseWarnCommits {
+		return ""
+	}
+	logging.Info("CreateWorktree: %s is %d commits behind %s", branch, behind, baseRef)
+	return fmt.Sprintf("%s is %d commits behind %s; consider rebasing", branch, behind, baseBranch)
+}
+
+// appendWarning adds extra to the "; "-separated warnings in warning.
+func appendWarning(warning, extra string) string {
+	if warning == "" {
+		return extra
+	}
+	return warning + "; " + extra
+}
+
 // BranchSyncStatus represents the sync status between local and remote branch
 type BranchSyncStatus struct {
 	LocalExists  bool
@@ -702,11 +1162,37 @@ func (wm *WorktreeManager) setCorrectUpstream(worktreePath, branchName string) {
 	}
 }
 
-// FetchOrigin runs git fetch origin to update remote tracking branches
+// resolveRemoteBranch fetches remote and returns its ref for branch, e.g.
+// "upstream/feature". It fails when the remote is not configured or has no
+// such branch.
+func (wm *WorktreeManager) resolveRemoteBranch(remote, branch string) (string, error) {
+	if err := exec.Command("git", "remote", "get-url", remote).Run(); err != nil {
+		return "", fmt.Errorf("remote '%s' not found (see 'git remote -v')", remote)
+	}
+	if remote != "origin" {
+		// origin was already fetched by CreateWorktree
+		if output, err := exec.Command("git", "fetch", remote).CombinedOutput(); err != nil {
+			logging.Warn("resolveRemoteBranch: git fetch %s failed: %v, output: %s", remote, err, string(output))
+		}
+	}
+	remoteRef := remote + "/" + branch
+	if err := exec.Command("git", "show-ref", "--verify", "--quiet", "refs/remotes/"+remoteRef).Run(); err != nil {
+		return "", fmt.Errorf("branch '%s' not found on remote '%s'", branch, remote)
+	}
+	return remoteRef, nil
+}
+
+// FetchOrigin runs git fetch origin to update remote tracking branches,
+// retrying transient network failures.
 func (wm *WorktreeManager) FetchOrigin() error {
+	if !wm.HasRemote() {
+		logging.Debug("FetchOrigin: no remote configured, skipping fetch")
+		return nil
+	}
 	logging.Debug("FetchOrigin: running git fetch origin")
-	cmd := exec.Command("git", "fetch", "origin")
-	output, err := cmd.CombinedOutput()
+	output, err := retryNetwork(context.Background(), wm.networkRetryPolicy(), "FetchOrigin", func() ([]byte, error) {
+		return exec.Command("git", "fetch", "origin").CombinedOutput()
+	})
 	if err != nil {
 		logging.Warn("FetchOrigin: git fetch origin failed: %v, output: %s", err, string(output))
 		// Don't fail - might be offline or no remote configured
@@ -716,11 +1202,77 @@ func (wm *WorktreeManager) FetchOrigin() error {
 	return nil
 }
 
+// FetchOriginPrune fetches origin and prunes remote-tracking branches that
+// were deleted upstream, so "remote_gone" stale detection is current. In a
+// repository without a remote it returns ErrNoRemote. Unlike FetchOrigin it
+// reports failure — `list --fetch` and `cleanup --fetch` warn
+// that status may be out of date — but callers should carry on either way.
+// It never prompts for credentials, retries transient network failures and
+// gives up after fetchTimeout, retries included, so being offline costs a
+// bounded delay rather than a hang.
+func (wm *WorktreeManager) FetchOriginPrune() error {
+	if !wm.HasRemote() {
+		logging.Debug("FetchOriginPrune: no remote configured, skipping fetch")
+		return ErrNoRemote
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	logging.Debug("FetchOriginPrune: running git fetch --prune origin")
+	output, err := retryNetwork(ctx, wm.networkRetryPolicy(), "FetchOriginPrune", func() ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "git", "fetch", "--prune", "origin")
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		return cmd.CombinedOutput()
+	})
+	if ctx.Err() == context.DeadlineExceeded {
+		logging.Warn("FetchOriginPrune: timed out after %s", fetchTimeout)
+		return fmt.Errorf("git fetch timed out after %s", fetchTimeout)
+	}
+	if err != nil {
+		logging.Warn("FetchOriginPrune: git fetch --prune origin failed: %v, output: %s", err, string(output))
+		return fmt.Errorf("git fetch failed: %s", strings.TrimSpace(string(output)))
+	}
+	logging.Debug("FetchOriginPrune: success")
+	return nil
+}
+
+// fetchTimeout bounds FetchOriginPrune.
+const fetchTimeout = 30 * time.Second
+
+// ErrNoRemote is returned by FetchOriginPrune in a repository without a
+// remote, where there is nothing to fetch.
+var ErrNoRemote = errors.New("no remote configured")
+
+// HasRemote reports whether the repository has any remote. Many
+// single-developer repositories have none, and then push state, fetching,
+// remote_gone and GitHub lookups don't apply. Remotes aren't added halfway
+// through a command, so it is checked once per manager.
+func (wm *WorktreeManager) HasRemote() bool {
+	switch wm.remoteState.Load() {
+	case 1:
+		return true
+	case 2:
+		return false
+	}
+	output, err := exec.Command("git", "remote").Output()
+	// If git can't say, assume a remote rather than hide push state
+	has := err != nil || strings.TrimSpace(string(output)) != ""
+	if has {
+		wm.remoteState.Store(1)
+	} else {
+		logging.Debug("HasRemote: no remote configured")
+		wm.remoteState.Store(2)
+	}
+	return has
+}
+
 // staleCache holds pre-fetched data for stale detection to avoid repeated git calls
 type staleCache struct {
 	mergedBranches map[string]bool // branches merged into main/master
 	goneBranches   map[string]bool // branches with deleted remote tracking
 	baseBranch     string          // which base branch was found (main or master)
+	hasRemote      bool            // false skips the remote_gone check: nothing can be gone
+	localBranches  map[string]bool // every local branch; nil when git couldn't list them
 }
 
 // buildStaleCache fetches stale-related git data once for all worktrees
@@ -728,10 +1280,23 @@ func (wm *WorktreeManager) buildStaleCache() *staleCache {
 	cache := &staleCache{
 		mergedBranches: make(map[string]bool),
 		goneBranches:   make(map[string]bool),
+		hasRemote:      wm.HasRemote(),
+	}
+
+	// List local branches, to catch worktrees whose branch was deleted
+	if output, err := exec.Command("git", "for-each-ref", "--format=%(refname:short)", "refs/heads/").Output(); err != nil {
+		logging.Debug("buildStaleCache: git for-each-ref refs/heads/ failed: %v", err)
+	} else {
+		cache.localBranches = make(map[string]bool)
+		for _, line := range strings.Split(string(output), "\n") {
+			if line = strings.TrimSpace(line); line != "" {
+				cache.localBranches[line] = true
+			}
+		}
 	}
 
-	// Get merged branches (try main first, then master)
-	for _, baseBranch := range []string{"main", "master"} {
+	// Get merged branches (the configured default branch, else main, then master)
+	for _, baseBranch := range wm.baseBranchRefs() {
 		cmd := exec.Command("git", "branch", "--merged", baseBranch)
 		output, err := cmd.Output()
 		if err != nil {
@@ -754,6 +1319,9 @@ func (wm *WorktreeManager) buildStaleCache() *staleCache {
 	}
 
 	// Get branches with gone remotes
+	if !cache.hasRemote {
+		return cache
+	}
 	cmd := exec.Command("git", "branch", "-vv")
 	output, err := cmd.Output()
 	if err != nil {
@@ -798,6 +1366,15 @@ func (wm *WorktreeManager) enrichStaleStatusCached(wt *WorktreeInfo, cache *stal
 		return
 	}
 
+	// Check 0: Was the branch deleted while checked out here? A branch
+	// without commits yet (an orphan) has no ref either
+	if cache.localBranches != nil && !cache.localBranches[wt.Branch] && headHadCommit(wt.Path) {
+		logging.Info("enrichStaleStatusCached: branch %q no longer exists", wt.Branch)
+		wt.BranchStatus = "stale"
+		wt.StaleReason = "branch_deleted"
+		return
+	}
+
 	// Check 1: Is branch merged into main/master?
 	if cache.mergedBranches[wt.Branch] {
 		wt.BranchStatus = "stale"
@@ -824,6 +1401,20 @@ func (wm *WorktreeManager) enrichStaleStatusCached(wt *WorktreeInfo, cache *stal
 	wt.BranchStatus = "active"
 }
 
+// headHadCommit reports whether HEAD of the worktree at worktreePath ever
+// pointed at a commit, going by its reflog. A branch made with `git worktree
+// add --orphan` has no ref until its first commit, like a deleted branch,
+// but no reflog either. The reflog is read directly: git log -g gives up
+// when HEAD doesn't resolve.
+func headHadCommit(worktreePath string) bool {
+	output, err := exec.Command("git", "-C", worktreePath, "rev-parse", "--path-format=absolute", "--git-path", "logs/HEAD").Output()
+	if err != nil {
+		return false
+	}
+	info, err := os.Stat(strings.TrimSpace(string(output)))
+	return err == nil && info.Size() > 0
+}
+
 // enrichStaleStatus checks if a worktree's branch is stale (merged or remote gone)
 // Deprecated: Use enrichStaleStatusCached with buildStaleCache for better performance
 func (wm *WorktreeManager) enrichStaleStatus(wt *WorktreeInfo) {
@@ -843,6 +1434,14 @@ func (wm *WorktreeManager) enrichStaleStatus(wt *WorktreeInfo) {
 		return
 	}
 
+	// Check 0: Was the branch deleted while checked out here?
+	if exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+wt.Branch).Run() != nil && headHadCommit(wt.Path) {
+		logging.Info("enrichStaleStatus: branch %q no longer exists", wt.Branch)
+		wt.BranchStatus = "stale"
+		wt.StaleReason = "branch_deleted"
+		return
+	}
+
 	// Check 1: Is branch merged into main/master?
 	merged, hasUniqueCommits := wm.isBranchMerged(wt.Branch)
 	if merged {
@@ -858,7 +1457,7 @@ func (wm *WorktreeManager) enrichStaleStatus(wt *WorktreeInfo) {
 	}
 
 	// Check 2: Is remote branch gone (deleted after merge)?
-	if wm.isRemoteBranchGone(wt.Branch) {
+	if wm.HasRemote() && wm.isRemoteBranchGone(wt.Branch) {
 		logging.Info("enrichStaleStatus: branch %q has gone remote", wt.Branch)
 		wt.BranchStatus = "stale"
 		wt.StaleReason = "remote_gone"
@@ -880,8 +1479,8 @@ func (wm *WorktreeManager) isBranchMerged(branch string) (merged bool, hasUnique
 	hasUniqueCommits = wm.branchHasUniqueCommits(branch)
 	logging.Debug("isBranchMerged: %q hasUniqueCommits=%v", branch, hasUniqueCommits)
 
-	// Try main first, then master
-	for _, baseBranch := range []string{"main", "master"} {
+	// The configured default branch, else main, then master
+	for _, baseBranch := range wm.baseBranchRefs() {
 		cmd := exec.Command("git", "branch", "--merged", baseBranch)
 		output, err := cmd.Output()
 		if err != nil {
@@ -910,8 +1509,8 @@ func (wm *WorktreeManager) isBranchMerged(branch string) (merged bool, hasUnique
 // branchHasUniqueCommits checks if a branch currently has commits not in main/master
 // Note: After a merge, this will return false even if the branch had commits before merging
 func (wm *WorktreeManager) branchHasUniqueCommits(branch string) bool {
-	// Try main first, then master
-	for _, baseBranch := range []string{"main", "master"} {
+	// The configured default branch, else main, then master
+	for _, baseBranch := range wm.baseBranchRefs() {
 		// Count commits in branch that are not in baseBranch
 		cmd := exec.Command("git", "rev-list", "--count", baseBranch+".."+branch)
 		output, err := cmd.Output()
@@ -966,8 +1565,18 @@ func (wm *WorktreeManager) isRemoteBranchGone(branch string) bool {
 	return false
 }
 
+// ErrDeleteBare is returned when asked to delete the bare repository of a
+// bare clone, which holds the git data of every worktree.
+var ErrDeleteBare = errors.New("cannot delete the bare repository: it holds the git data for every worktree")
+
 // DeleteWorktree deletes a worktree by name or path
 func (wm *WorktreeManager) DeleteWorktree(ctx context.Context, identifier string, force bool) error {
+	lock, err := wm.LockRepo(ctx)
+	if err != nil {
+		return err
+	}
+	defer lock.Unlock()
+
 	worktrees, err := wm.ListWorktrees(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to list worktrees: %w", err)
@@ -992,76 +1601,10 @@ func (wm *WorktreeManager) DeleteWorktree(ctx context.Context, identifier string
 		return fmt.Errorf("worktree '%s' not found", identifier)
 	}
 
-	if targetWorktree.IsCurrent {
-		return fmt.Errorf("cannot delete current worktree")
-	}
-
 	// Note: Pre-remove hooks are now run by the caller with approval checking.
 	// See CLI handleDelete() and TUI delete flow.
-
-	hasSubmodules := false
-	if _, err := os.Stat(filepath.Join(targetWorktree.Path, ".gitmodules")); err == nil {
-		hasSubmodules = true
-		deinitCmd := exec.Command("git", "-C", targetWorktree.Path, "submodule", "deinit", "--all", "--force")
-		output, err := deinitCmd.CombinedOutput()
-		if err != nil {
-			return fmt.Errorf("failed to deinit submodules in worktree '%s': %w\n\nThis can happen if submodules have uncommitted changes.\nTry running manually:\n  cd %s\n  git submodule deinit --all --force\n\nOutput: %s",
-				targetWorktree.Name, err, targetWorktree.Path, string(output))
-		}
-	}
-
-	// 2. Remove worktree using git. --force is required for submodules (even
-	// after deinit) or when the caller forces (to ignore uncommitted/leftover
-	// content).
-	forceRemove := hasSubmodules || force
-	var cmd *exec.Cmd
-	if forceRemove {
-		cmd = exec.Command("git", "worktree", "remove", "--force", targetWorktree.Path)
-		if hasSubmodules {
-			logging.Debug("DeleteWorktree: using --force flag (worktree has submodules)")
-		} else {
-			logging.Debug("DeleteWorktree: using --force flag (uncommitted changes will be ignored)")
-		}
-	} else {
-		cmd = exec.Command("git", "worktree", "remove", targetWorktree.Path)
-	}
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		outputStr := string(output)
-		// When force was requested, complete the removal even if git still
-		// refuses — e.g. leftover ignored content ("Directory not empty") or a
-		// worktree left half-removed by a prior non-force attempt. Remove the
-		// checkout directly and prune the now-stale registration.
-		if forceRemove {
-			logging.Warn("DeleteWorktree: 'git worktree remove --force' failed (%s); removing directory and pruning", strings.TrimSpace(outputStr))
-			if rmErr := os.RemoveAll(targetWorktree.Path); rmErr != nil {
-				return fmt.Errorf("failed to remove worktree '%s' directory: %w", targetWorktree.Name, rmErr)
-			}
-			pruneCmd := exec.Command("git", "worktree", "prune")
-			if repoRoot, rrErr := wm.getRepoRoot(); rrErr == nil {
-				pruneCmd.Dir = repoRoot
-			}
-			if pruneOut, pruneErr := pruneCmd.CombinedOutput(); pruneErr != nil {
-				logging.Warn("DeleteWorktree: 'git worktree prune' failed: %v (%s)", pruneErr, strings.TrimSpace(string(pruneOut)))
-			}
-			logging.Info("Deleted worktree '%s' via force fallback (branch '%s' is preserved)", targetWorktree.Name, targetWorktree.Branch)
-			return nil
-		}
-		var hint string
-		if strings.Contains(outputStr, "submodules") {
-			hint = "The worktree contains submodules. Try running:\n  git -C " + targetWorktree.Path + " submodule deinit --all --force\nThen try deleting again with force."
-		} else if strings.Contains(outputStr, "modified or untracked") {
-			hint = "The worktree has uncommitted changes. Commit or stash them first, or use force delete."
-		} else {
-			hint = "Use force delete to remove anyway."
-		}
-		return fmt.Errorf("failed to remove worktree '%s': %w\n\nOutput: %s\n\n%s",
-			targetWorktree.Name, err, outputStr, hint)
-	}
-
-	// Note: Branch is kept - user can delete manually if needed
-	logging.Info("Deleted worktree '%s' (branch '%s' is preserved)", targetWorktree.Name, targetWorktree.Branch)
-	return nil
+	_, err = wm.RemoveWorktree(ctx, *targetWorktree, force)
+	return err
 }
 
 // Helper functions
@@ -1090,8 +1633,12 @@ func (wm *WorktreeManager) parseWorktreeList(output string) []WorktreeInfo {
 			current.Branch = strings.TrimPrefix(branch, "refs/heads/")
 		} else if line == "bare" {
 			current.Branch = "(bare)"
+			current.IsBare = true
 		} else if line == "detached" {
 			current.Branch = "(detached)"
+		} else if line == "prunable" || strings.HasPrefix(line, "prunable ") {
+			// The directory is gone: deleted, or moved without git knowing
+			current.Status = "missing"
 		}
 	}
 
@@ -1100,35 +1647,68 @@ func (wm *WorktreeManager) parseWorktreeList(output string) []WorktreeInfo {
 		worktrees = append(worktrees, current)
 	}
 
-	// Mark current worktree
-	currentPath, _ := os.Getwd()
-	for i := range worktrees {
-		if worktrees[i].Path == currentPath {
-			worktrees[i].IsCurrent = true
-		}
-	}
-
+	markCurrentWorktree(worktrees, currentWorktreeRoot())
 	return worktrees
 }
 
-func (wm *WorktreeManager) getRepoRoot() (string, error) {
+// markCurrentWorktree sets IsCurrent on the worktree at currentPath, the
+// toplevel rather than the working directory, so gren run from a
+// subdirectory still knows which worktree it is in. Paths are compared with
+// symlinks resolved, since git records a worktree's path as it was given.
+// When currentPath is no worktree's root (inside a .git directory, where git
+// has no toplevel) the innermost worktree containing it is current. The bare
+// repository has no checkout to be in, even when gren runs from its
+// directory.
+func markCurrentWorktree(worktrees []WorktreeInfo, currentPath string) {
+	if currentPath == "" {
+		return
+	}
+	innermost := -1
+	for i := range worktrees {
+		if worktrees[i].IsBare {
+			continue
+		}
+		if samePath(worktrees[i].Path, currentPath) {
+			worktrees[i].IsCurrent = true
+			return
+		}
+		if pathWithin(currentPath, worktrees[i].Path) && (innermost == -1 || len(worktrees[i].Path) > len(worktrees[innermost].Path)) {
+			innermost = i
+		}
+	}
+	if innermost != -1 {
+		worktrees[innermost].IsCurrent = true
+	}
+}
+
+// pathWithin reports whether path lies inside dir, comparing them with
+// symlinks in their existing parents resolved.
+func pathWithin(path, dir string) bool {
+	rel, err := filepath.Rel(resolveParentSymlinks(filepath.Clean(dir)), resolveParentSymlinks(filepath.Clean(path)))
+	return err == nil && rel != "." && !leavesDir(rel) && !filepath.IsAbs(rel)
+}
+
+func (wm *WorktreeManager) getRepoRoot() (string, error) {
 	// Resolve the MAIN worktree's root, not the current linked worktree's
-	// toplevel. The main checkout is the parent of the shared git common dir,
-	// so this stays correct even when gren runs from inside a linked worktree
+	// toplevel. git reports the main checkout from any of its worktrees, so
+	// this stays correct even when gren runs from inside a linked worktree
 	// (e.g. `gren hook-run` invoked by a plugin from the worktree's own pane) —
 	// hook RepoRoot / repo_root / worktree_dir then resolve against the main
 	// checkout, where shared gitignored files (a .env) live. In a non-worktree
-	// repo the common dir is <repo>/.git, so this equals --show-toplevel.
-	cmd := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir")
-	if output, err := cmd.Output(); err == nil {
-		commonDir := strings.TrimSpace(string(output))
-		if commonDir != "" {
-			return filepath.Dir(filepath.Clean(commonDir)), nil
-		}
+	// repo this equals --show-toplevel.
+	//
+	// A bare repository has no main checkout, so the bare git dir itself is the
+	// root: relative worktree_dir values ("../<repo>-worktrees") then land next
+	// to it, and hooks run from there instead of from its unrelated parent.
+	if gitDir := bareGitDir(); gitDir != "" {
+		return gitDir, nil
+	}
+	if root := config.MainWorktreeRoot(); root != "" {
+		return root, nil
 	}
 
-	// Fallback for older git (< 2.31, no --path-format) or unexpected output.
-	cmd = exec.Command("git", "rev-parse", "--show-toplevel")
+	// Fallback for unexpected output
+	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
 	output, err := cmd.Output()
 	if err != nil {
 		return "", fmt.Errorf("failed to get repository root: %w", err)
@@ -1136,6 +1716,225 @@ func (wm *WorktreeManager) getRepoRoot() (string, error) {
 	return strings.TrimSpace(string(output)), nil
 }
 
+// resolveWorktreeDir returns the directory new worktrees go in: explicitDir
+// (create's --dir) when set, else the configured worktree_dir (which may
+// come from the user config's [defaults]), else a sibling <repo>-worktrees
+// directory. Templates are expanded with branch and a leading ~ with the
+// home directory. The
+// configured worktree_dir is relative to the main worktree (a bare
+// repository's git dir), not to wherever gren runs: from inside a linked
+// worktree or a subdirectory, "../<repo>-worktrees" would otherwise nest new
+// worktrees inside the current one. An explicit --dir stays relative to the
+// working directory, like any other path argument.
+func (wm *WorktreeManager) resolveWorktreeDir(ctx context.Context, cfg *config.Config, explicitDir, branch string) (string, error) {
+	worktreeDir := explicitDir
+	fromConfig := worktreeDir == ""
+	if fromConfig {
+		worktreeDir = cfg.WorktreeDir
+	}
+	repoRoot, rootErr := wm.getRepoRoot()
+	// An empty dir needs the repo name for the default; a templated dir needs it
+	// to expand (e.g. worktree_dir = "../{{ repo }}-worktrees").
+	if worktreeDir == "" || strings.Contains(worktreeDir, "{{") {
+		// A bare repo has no toplevel, so GetRepoInfo can't name it; derive
+		// the name from the git dir and default to a sibling directory. The
+		// main worktree names a regular repo, as the current one may be a
+		// linked worktree with its own directory name.
+		var repoName string
+		bareDir := bareGitDir()
+		if bareDir != "" {
+			repoName = bareRepoName(bareDir)
+		} else if rootErr == nil {
+			repoName = filepath.Base(repoRoot)
+		} else {
+			repoInfo, err := wm.gitRepo.GetRepoInfo(ctx)
+			if err != nil {
+				logging.Error("Failed to get repo info: %v", err)
+				return "", fmt.Errorf("failed to get repo info: %w", err)
+			}
+			repoName = repoInfo.Name
+		}
+		if worktreeDir == "" && bareDir != "" {
+			worktreeDir = bareWorktreeDir(bareDir, repoName)
+			logging.Debug("Using default worktree_dir for bare repo: %s", worktreeDir)
+		} else if worktreeDir == "" {
+			worktreeDir = fmt.Sprintf("../%s-worktrees", repoName)
+			logging.Debug("Using default worktree_dir: %s", worktreeDir)
+		} else {
+			worktreeDir = expandTemplate(worktreeDir, TemplateContext{
+				Repo:            repoName,
+				Branch:          branch,
+				BranchSanitized: cfg.Sanitize.Apply(branch),
+			})
+			logging.Debug("Using worktree_dir from config (expanded): %s", worktreeDir)
+		}
+	} else {
+		logging.Debug("Using worktree_dir from config: %s", worktreeDir)
+	}
+	if fromConfig {
+		worktreeDir = config.ExpandHome(worktreeDir)
+	}
+	if fromConfig && rootErr == nil && !filepath.IsAbs(worktreeDir) {
+		worktreeDir = filepath.Join(repoRoot, worktreeDir)
+		logging.Debug("Resolved worktree_dir against main worktree: %s", worktreeDir)
+	}
+
+	return worktreeDir, nil
+}
+
+// ErrWorktreePathExists is returned by CreateWorktree when something is
+// already at the worktree path, which git would otherwise refuse with a
+// less helpful message.
+var ErrWorktreePathExists = errors.New("worktree path already exists")
+
+// clearWorktreePath checks that nothing is at path. With allowEmpty, an
+// empty directory there (typically left by a failed create) is let through:
+// git worktree add checks out into it.
+func clearWorktreePath(path string, allowEmpty bool) error {
+	info, err := os.Lstat(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check worktree path %s: %w", path, err)
+	}
+	empty := false
+	if info.IsDir() {
+		entries, err := os.ReadDir(path)
+		empty = err == nil && len(entries) == 0
+	}
+	switch {
+	case empty && allowEmpty:
+		logging.Debug("Reusing empty directory at %s", path)
+		return nil
+	case empty:
+		return fmt.Errorf("%w: %s is an empty directory; use a different path or remove it", ErrWorktreePathExists, path)
+	case !info.IsDir():
+		return fmt.Errorf("%w: %s is a file; use a different name or remove it", ErrWorktreePathExists, path)
+	default:
+		return fmt.Errorf("%w: %s is not empty; use a different name or remove it", ErrWorktreePathExists, path)
+	}
+}
+
+// WorktreePath returns where CreateWorktree would place a worktree named
+// name on branch (empty: name) without an explicit --dir or --path. A
+// relative worktree_dir resolves against the main worktree, so the answer is
+// the same from any subdirectory or linked worktree.
+func (wm *WorktreeManager) WorktreePath(ctx context.Context, name, branch string) (string, error) {
+	cfg, err := wm.configManager.Load()
+	if err != nil {
+		return "", fmt.Errorf("failed to load configuration: %w", err)
+	}
+	return wm.worktreePath(ctx, cfg, "", name, branch)
+}
+
+// worktreePath joins the resolved worktree directory and the worktree name,
+// sanitized by the config's rules (/ becomes - to avoid nested directories).
+func (wm *WorktreeManager) worktreePath(ctx context.Context, cfg *config.Config, explicitDir, name, branch string) (string, error) {
+	if branch == "" {
+		branch = name
+	}
+	worktreeDir, err := wm.resolveWorktreeDir(ctx, cfg, explicitDir, branch)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(worktreeDir, cfg.Sanitize.Apply(name)), nil
+}
+
+// checkExplicitPath resolves a --path placement to an absolute path and
+// checks that it is not inside an existing worktree, where git would nest
+// one checkout in another.
+func (wm *WorktreeManager) checkExplicitPath(ctx context.Context, path string) (string, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve path %s: %w", path, err)
+	}
+	// git reports worktree paths with symlinks resolved (/tmp → /private/tmp
+	// on macOS), so compare against the resolved form too.
+	resolved := resolveParentSymlinks(abs)
+
+	worktrees, err := wm.ListWorktreesBasic(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to list worktrees: %w", err)
+	}
+	for _, wt := range worktrees {
+		for _, p := range []string{abs, resolved} {
+			if rel, err := filepath.Rel(wt.Path, p); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+				return "", fmt.Errorf("path %s is inside worktree '%s' (%s)", abs, wt.Name, wt.Path)
+			}
+		}
+	}
+	return abs, nil
+}
+
+// resolveParentSymlinks returns the absolute path abs with the symlinks in
+// its nearest existing parent resolved, so it compares equal to the paths
+// git reports even when abs itself does not exist yet.
+func resolveParentSymlinks(abs string) string {
+	for dir := filepath.Dir(abs); ; dir = filepath.Dir(dir) {
+		if real, err := filepath.EvalSymlinks(dir); err == nil {
+			rel, _ := filepath.Rel(dir, abs)
+			return filepath.Join(real, rel)
+		}
+		if dir == filepath.Dir(dir) {
+			return abs
+		}
+	}
+}
+
+// currentWorktreeRoot returns the toplevel of the worktree gren runs in, main
+// or linked, so commands started from a subdirectory act on the whole
+// worktree. Outside a working tree (a bare repo's git dir) it falls back to
+// the working directory.
+func currentWorktreeRoot() string {
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		if root := strings.TrimSpace(string(output)); root != "" {
+			return root
+		}
+	}
+	cwd, _ := os.Getwd()
+	return cwd
+}
+
+// bareGitDir returns the absolute git directory of a bare repository (e.g.
+// /src/project.git), or "" when the repository has a main working tree. The
+// check runs against the shared common dir, so it also holds from inside a
+// linked worktree of a bare repo, where --is-bare-repository alone says false.
+func bareGitDir() string {
+	output, err := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir").Output()
+	if err != nil {
+		return ""
+	}
+	commonDir := filepath.Clean(strings.TrimSpace(string(output)))
+	bare, err := exec.Command("git", "--git-dir", commonDir, "rev-parse", "--is-bare-repository").Output()
+	if err != nil || strings.TrimSpace(string(bare)) != "true" {
+		return ""
+	}
+	return commonDir
+}
+
+// bareRepoName derives the project name from a bare repository's directory:
+// "project.git" → "project", and for the "<project>/.bare" layout the parent
+// directory's name.
+func bareRepoName(gitDir string) string {
+	name := filepath.Base(gitDir)
+	if name == ".bare" || name == ".git" {
+		return filepath.Base(filepath.Dir(gitDir))
+	}
+	return strings.TrimSuffix(name, ".git")
+}
+
+// bareWorktreeDir is the default worktree directory of a bare repository.
+// In the "<project>/.bare" layout, where <project>/.git points at .bare,
+// worktrees go in <project> next to .bare; a "project.git" clone gets a
+// sibling "project-worktrees" directory.
+func bareWorktreeDir(gitDir, repoName string) string {
+	if name := filepath.Base(gitDir); name == ".bare" || name == ".git" {
+		return filepath.Dir(gitDir)
+	}
+	return filepath.Join(filepath.Dir(gitDir), repoName+"-worktrees")
+}
+
 // copyDir copies a directory recursively
 func copyDir(src, dst string) error {
 	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
@@ -1172,8 +1971,32 @@ const (
 	GitHubUnavailable
 )
 
-// CheckGitHubAvailability checks if gh CLI is installed and authenticated
+// CheckGitHubAvailability checks if gh CLI is installed and authenticated.
+// The result is cached for the manager's lifetime. With `github = false` in
+// the config, or in a repository without a remote, it reports
+// GitHubUnavailable without running gh at all.
 func (wm *WorktreeManager) CheckGitHubAvailability() GitHubStatus {
+	if status := GitHubStatus(wm.githubStatus.Load()); status != GitHubUnchecked {
+		return status
+	}
+	status := wm.checkGitHubAvailability()
+	wm.githubStatus.Store(int32(status))
+	return status
+}
+
+// checkGitHubAvailability does the uncached work of CheckGitHubAvailability.
+func (wm *WorktreeManager) checkGitHubAvailability() GitHubStatus {
+	if !wm.HasRemote() {
+		logging.Debug("CheckGitHubAvailability: no remote, so no PRs to look up")
+		return GitHubUnavailable
+	}
+	if wm.configManager != nil {
+		if cfg, err := wm.configManager.Load(); err == nil && !cfg.GitHubEnabled() {
+			logging.Debug("CheckGitHubAvailability: disabled by github = false")
+			return GitHubUnavailable
+		}
+	}
+
 	// Check if gh is installed
 	if _, err := exec.LookPath("gh"); err != nil {
 		logging.Debug("CheckGitHubAvailability: gh CLI not installed")
@@ -1228,6 +2051,11 @@ func (wm *WorktreeManager) FetchPRStatus(branch string) *PRInfo {
 func (wm *WorktreeManager) EnrichWithGitHubStatus(worktrees []WorktreeInfo) {
 	logging.Debug("EnrichWithGitHubStatus: enriching %d worktrees", len(worktrees))
 
+	prs := wm.FetchPRsByBranch()
+	if prs == nil {
+		return
+	}
+
 	for i := range worktrees {
 		wt := &worktrees[i]
 
@@ -1241,8 +2069,7 @@ func (wm *WorktreeManager) EnrichWithGitHubStatus(worktrees []WorktreeInfo) {
 			continue
 		}
 
-		pr := wm.FetchPRStatus(wt.Branch)
-		if pr != nil {
+		if pr, ok := prs[wt.Branch]; ok {
 			wt.PRNumber = pr.Number
 			wt.PRURL = pr.URL
 
@@ -1253,7 +2080,11 @@ func (wm *WorktreeManager) EnrichWithGitHubStatus(worktrees []WorktreeInfo) {
 				wt.PRState = pr.State
 			}
 
-			// Update stale status based on PR state
+			// Update stale status based on PR state. A deleted branch stays
+			// branch_deleted: the worktree is broken whatever became of the PR.
+			if wt.StaleReason == "branch_deleted" {
+				continue
+			}
 			if pr.State == "MERGED" {
 				wt.BranchStatus = "stale"
 				wt.StaleReason = "pr_merged"
@@ -1279,26 +2110,51 @@ func (wm *WorktreeManager) OpenPRInBrowser(branch string) error {
 	return nil
 }
 
+// CIInfo is the combined state of a branch's PR checks.
 type CIInfo struct {
-	Status     string
-	Conclusion string
-	ChecksURL  string
+	Status     string // "success", "failure", "pending", "none" (no checks), "unknown"
+	Conclusion string // Human-readable summary, e.g. "2 of 5 checks failed"
+	CIURL      string // Failing or running check's page, "" if none
 }
 
+// FetchCIStatus summarizes the checks on branch's PR from `gh pr checks`.
+// A PR without any checks has Status "none". It returns nil when gh fails,
+// e.g. because the branch has no PR.
 func (wm *WorktreeManager) FetchCIStatus(branch string) *CIInfo {
+	return wm.fetchCIStatus(context.Background(), branch)
+}
+
+// fetchCIStatus is FetchCIStatus with a context that kills gh when done.
+func (wm *WorktreeManager) fetchCIStatus(ctx context.Context, branch string) *CIInfo {
 	logging.Debug("FetchCIStatus: checking CI for branch %q", branch)
 
-	cmd := exec.Command("gh", "pr", "checks", branch, "--json", "state,name,conclusion")
-	output, err := cmd.Output()
+	output, err := retryNetwork(ctx, wm.networkRetryPolicy(), "FetchCIStatus", func() ([]byte, error) {
+		cmd := exec.CommandContext(ctx, "gh", "pr", "checks", branch, "--json", "name,state,bucket,link")
+		// Don't wait on children of gh that outlive it and hold its output open
+		cmd.WaitDelay = time.Second
+		output, err := cmd.Output()
+		// gh exits non-zero when checks fail (1) or are pending (8) but
+		// still prints them, so only treat it as failing (and maybe retry)
+		// when there is nothing to parse
+		if len(output) > 0 {
+			err = nil
+		}
+		return output, err
+	})
 	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && strings.Contains(string(exitErr.Stderr), "no checks reported") {
+			return &CIInfo{Status: "none", Conclusion: "No checks reported"}
+		}
 		logging.Debug("FetchCIStatus: no checks for branch %q: %v", branch, err)
 		return nil
 	}
 
 	var checks []struct {
-		State      string `json:"state"`
-		Name       string `json:"name"`
-		Conclusion string `json:"conclusion"`
+		Name   string `json:"name"`
+		State  string `json:"state"`
+		Bucket string `json:"bucket"` // pass, fail, pending, skipping, cancel
+		Link   string `json:"link"`
 	}
 	if err := json.Unmarshal(output, &checks); err != nil {
 		logging.Debug("FetchCIStatus: failed to parse checks: %v", err)
@@ -1306,65 +2162,130 @@ func (wm *WorktreeManager) FetchCIStatus(branch string) *CIInfo {
 	}
 
 	if len(checks) == 0 {
-		return nil
+		return &CIInfo{Status: "none", Conclusion: "No checks reported"}
 	}
 
 	info := &CIInfo{}
-	hasFailure := false
-	hasPending := false
-	allSuccess := true
-
+	var failed, pending, passed int
+	var failedURL, pendingURL string
 	for _, check := range checks {
-		switch check.State {
-		case "FAILURE", "ERROR":
-			hasFailure = true
-			allSuccess = false
-		case "PENDING", "QUEUED", "IN_PROGRESS":
-			hasPending = true
-			allSuccess = false
-		case "SUCCESS":
-		default:
-			allSuccess = false
+		switch check.Bucket {
+		case "fail", "cancel":
+			failed++
+			if failedURL == "" {
+				failedURL = check.Link
+			}
+		case "pending":
+			pending++
+			if pendingURL == "" {
+				pendingURL = check.Link
+			}
+		case "pass", "skipping":
+			passed++
 		}
 	}
 
-	if hasFailure {
+	switch {
+	case failed > 0:
 		info.Status = "failure"
-		info.Conclusion = "Some checks failed"
-	} else if hasPending {
+		info.Conclusion = fmt.Sprintf("%d of %d checks failed", failed, len(checks))
+		info.CIURL = failedURL
+	case pending > 0:
 		info.Status = "pending"
-		info.Conclusion = "Checks in progress"
-	} else if allSuccess {
+		info.Conclusion = fmt.Sprintf("%d of %d checks in progress", pending, len(checks))
+		info.CIURL = pendingURL
+	case passed == len(checks):
 		info.Status = "success"
 		info.Conclusion = "All checks passed"
-	} else {
+	default:
 		info.Status = "unknown"
 	}
 
+	logging.Debug("FetchCIStatus: branch %q is %s (%s)", branch, info.Status, info.Conclusion)
 	return info
 }
 
-func (wm *WorktreeManager) EnrichWithCIStatus(worktrees []WorktreeInfo) {
+// EnrichWithCIStatus fills in the CI fields of worktrees that have a PR, so
+// call it after EnrichWithGitHubStatus. A PR without checks counts as
+// "pending" when the worktree has GitHub Actions workflows, whose runs just
+// haven't started, and as "none" when there is no CI to wait for. Branches
+// are checked concurrently, github_concurrency at a time (default 8), and
+// cancelling ctx kills the gh calls still running.
+func (wm *WorktreeManager) EnrichWithCIStatus(ctx context.Context, worktrees []WorktreeInfo) {
 	logging.Debug("EnrichWithCIStatus: enriching %d worktrees", len(worktrees))
 
-	for i := range worktrees {
-		wt := &worktrees[i]
-
+	var pending []int
+	for i, wt := range worktrees {
 		if wt.IsMain || wt.Branch == "(detached)" || wt.Branch == "(bare)" {
 			continue
 		}
-
 		if wt.PRNumber == 0 {
 			continue
 		}
+		pending = append(pending, i)
+	}
+
+	// gh pr checks is one network round trip per branch, so run them side
+	// by side. Each call fills in only its own element of worktrees.
+	forEachBounded(ctx, wm.gitHubConcurrency(), len(pending), func(ctx context.Context, n int) {
+		wt := &worktrees[pending[n]]
+		ci := wm.fetchCIStatus(ctx, wt.Branch)
+		if ci == nil {
+			return
+		}
+		if ci.Status == "none" && hasCIWorkflows(wt.Path) {
+			ci.Status = "pending"
+			ci.Conclusion = "Waiting for checks to start"
+		}
+		wt.CIStatus = ci.Status
+		wt.CIConclusion = ci.Conclusion
+		wt.CIURL = ci.CIURL
+		if wt.CIURL == "" && wt.PRURL != "" && ci.Status != "none" {
+			wt.CIURL = wt.PRURL + "/checks"
+		}
+	})
+}
 
-		ci := wm.FetchCIStatus(wt.Branch)
-		if ci != nil {
-			wt.CIStatus = ci.Status
-			wt.CIConclusion = ci.Conclusion
-			wt.ChecksURL = ci.ChecksURL
+// forEachBounded calls fn for 0..n-1 with at most limit calls running at
+// once, and returns when all have finished. Once ctx is done, calls not yet
+// started are skipped; running ones see the cancelled ctx.
+func forEachBounded(ctx context.Context, limit, n int, fn func(ctx context.Context, i int)) {
+	if limit < 1 {
+		limit = 1
+	}
+	sem := make(chan struct{}, limit)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if ctx.Err() != nil {
+				return
+			}
+			fn(ctx, i)
+		}(i)
+	}
+	wg.Wait()
+}
+
+// hasCIWorkflows reports whether the worktree at path defines GitHub
+// Actions workflows.
+func hasCIWorkflows(path string) bool {
+	for _, pattern := range []string{"*.yml", "*.yaml"} {
+		if matches, _ := filepath.Glob(filepath.Join(path, ".github", "workflows", pattern)); len(matches) > 0 {
+			return true
 		}
 	}
+	return false
 }
 
 func (wm *WorktreeManager) Merge(ctx context.Context, opts MergeOptions) (*MergeResult, error) {
@@ -1372,10 +2293,7 @@ func (wm *WorktreeManager) Merge(ctx context.Context, opts MergeOptions) (*Merge
 
 	result := &MergeResult{}
 
-	currentPath, err := os.Getwd()
-	if err != nil {
-		return nil, fmt.Errorf("failed to get current directory: %w", err)
-	}
+	currentPath := currentWorktreeRoot()
 	result.WorktreePath = currentPath
 
 	currentBranch, err := wm.getCurrentBranch()
@@ -1399,6 +2317,18 @@ func (wm *WorktreeManager) Merge(ctx context.Context, opts MergeOptions) (*Merge
 		return result, nil
 	}
 
+	if opts.IntoCurrent {
+		if opts.DryRun {
+			result.DryRun = true
+			result.Steps = []string{fmt.Sprintf("merge %s into %s with git merge", targetBranch, currentBranch)}
+			if opts.AbortOnConflict {
+				result.Steps = append(result.Steps, "abort the merge if it hits conflicts")
+			}
+			return result, nil
+		}
+		return wm.mergeIntoCurrent(result, opts.AbortOnConflict)
+	}
+
 	worktrees, err := wm.ListWorktrees(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to list worktrees: %w", err)
@@ -1423,6 +2353,9 @@ func (wm *WorktreeManager) Merge(ctx context.Context, opts MergeOptions) (*Merge
 	}
 
 	hasChanges := currentWorktree.ModifiedCount > 0 || currentWorktree.UntrackedCount > 0 || currentWorktree.StagedCount > 0
+	if opts.DryRun {
+		return wm.planMerge(result, currentWorktree, hasChanges, opts), nil
+	}
 	if hasChanges && !opts.Force {
 		if err := wm.stageAndCommitChanges(currentBranch); err != nil {
 			return nil, fmt.Errorf("failed to commit changes: %w", err)
@@ -1442,7 +2375,12 @@ func (wm *WorktreeManager) Merge(ctx context.Context, opts MergeOptions) (*Merge
 	}
 
 	if opts.Rebase {
-		if err := wm.rebaseOnto(targetBranch); err != nil {
+		if err := wm.rebaseOnto(currentBranch, targetBranch); err != nil {
+			var conflict *MergeConflictError
+			if errors.As(err, &conflict) {
+				result.Conflicts = conflict.Files
+				return result, err
+			}
 			return nil, fmt.Errorf("rebase failed: %w", err)
 		}
 	}
@@ -1462,35 +2400,85 @@ func (wm *WorktreeManager) Merge(ctx context.Context, opts MergeOptions) (*Merge
 	if opts.Remove {
 		if opts.Verify {
 			// Run pre-remove hooks with approval (autoYes=true since remove already confirmed)
-			wm.RunPreRemoveHookWithApproval(currentPath, currentBranch, true)
+			result.Warnings = append(result.Warnings, hookWarnings(wm.RunPreRemoveHookWithApproval(currentPath, currentBranch, true))...)
 		}
 
+		// Leave the worktree before removing it: DeleteWorktree refuses the
+		// current worktree, and git can't remove the directory it runs in.
 		repoRoot, _ := wm.getRepoRoot()
-		if err := wm.DeleteWorktree(ctx, currentBranch, true); err != nil {
+		if repoRoot != "" {
+			if err := os.Chdir(repoRoot); err != nil {
+				logging.Warn("Merge: failed to change to repo root: %v", err)
+			}
+		}
+		if err := wm.DeleteWorktree(ctx, currentPath, true); err != nil {
 			logging.Warn("Merge: failed to remove worktree: %v", err)
+			result.Warnings = append(result.Warnings, fmt.Sprintf("could not remove worktree %s: %v", currentPath, err))
 		} else {
 			result.WorktreeRemoved = true
 			if opts.Verify {
 				// Run post-remove hooks (best-effort: failures are logged but don't affect outcome)
-				wm.RunPostRemoveHookWithApproval(currentPath, currentBranch, true)
-			}
-		}
-
-		if repoRoot != "" {
-			if err := os.Chdir(repoRoot); err != nil {
-				logging.Warn("Merge: failed to change to repo root: %v", err)
+				result.Warnings = append(result.Warnings, hookWarnings(wm.RunPostRemoveHookWithApproval(currentPath, currentBranch, true))...)
 			}
 		}
 	}
 
 	if opts.Verify {
 		// Run post-merge hooks with approval (autoYes=true)
-		wm.RunPostMergeHookWithApproval(currentPath, currentBranch, targetBranch, true)
+		result.Warnings = append(result.Warnings, hookWarnings(wm.RunPostMergeHookWithApproval(currentPath, currentBranch, targetBranch, true))...)
 	}
 
 	return result, nil
 }
 
+// planMerge fills in result with what Merge would do for opts, counting
+// the commits to squash but changing nothing.
+func (wm *WorktreeManager) planMerge(result *MergeResult, wt *WorktreeInfo, hasChanges bool, opts MergeOptions) *MergeResult {
+	source, target := result.SourceBranch, result.TargetBranch
+	result.DryRun = true
+
+	if hasChanges && !opts.Force {
+		changes := wt.StagedCount + wt.ModifiedCount + wt.UntrackedCount
+		result.Steps = append(result.Steps, fmt.Sprintf("commit %d uncommitted change(s) as \"WIP: changes on %s\"", changes, source))
+	}
+	if opts.Squash {
+		count, err := wm.getCommitsAhead(source, target)
+		if err != nil {
+			logging.Warn("Merge: could not count commits ahead: %v", err)
+			result.Steps = append(result.Steps, fmt.Sprintf("squash the commits ahead of %s into one (could not count them)", target))
+		} else {
+			if hasChanges && !opts.Force {
+				count++ // the WIP commit
+			}
+			if count > 1 {
+				result.CommitsSquashed = count
+				result.Steps = append(result.Steps, fmt.Sprintf("squash %d commits into one", count))
+			}
+		}
+	}
+	if opts.Rebase {
+		result.Steps = append(result.Steps, fmt.Sprintf("rebase %s onto %s", source, target))
+	}
+	if opts.Verify {
+		result.Steps = append(result.Steps, "run pre-merge hooks")
+	}
+	result.Steps = append(result.Steps, fmt.Sprintf("fast-forward %s to %s", target, source))
+	if opts.Remove {
+		result.WorktreeRemoved = true
+		if opts.Verify {
+			result.Steps = append(result.Steps, "run pre-remove hooks")
+		}
+		result.Steps = append(result.Steps, fmt.Sprintf("remove worktree %s", result.WorktreePath))
+		if opts.Verify {
+			result.Steps = append(result.Steps, "run post-remove hooks")
+		}
+	}
+	if opts.Verify {
+		result.Steps = append(result.Steps, "run post-merge hooks")
+	}
+	return result
+}
+
 func (wm *WorktreeManager) getCurrentBranch() (string, error) {
 	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
 	output, err := cmd.Output()
@@ -1501,6 +2489,10 @@ func (wm *WorktreeManager) getCurrentBranch() (string, error) {
 }
 
 func (wm *WorktreeManager) getDefaultBranch() (string, error) {
+	if branch := wm.configuredDefaultBranch(); branch != "" {
+		return branch, nil
+	}
+
 	for _, branch := range []string{"main", "master"} {
 		cmd := exec.Command("git", "rev-parse", "--verify", branch)
 		if err := cmd.Run(); err == nil {
@@ -1520,6 +2512,45 @@ func (wm *WorktreeManager) getDefaultBranch() (string, error) {
 	return "", fmt.Errorf("could not determine default branch")
 }
 
+// configuredDefaultBranch returns the default_branch config override, or ""
+// when none is set. The config is read once per manager.
+func (wm *WorktreeManager) configuredDefaultBranch() string {
+	if cached := wm.defaultBranch.Load(); cached != nil {
+		return *cached
+	}
+	var branch string
+	if wm.configManager != nil {
+		if cfg, err := wm.configManager.Load(); err == nil {
+			branch = cfg.DefaultBranch
+		}
+	}
+	wm.defaultBranch.Store(&branch)
+	return branch
+}
+
+// baseBranchRefs returns the refs stale detection compares branches against:
+// the configured default_branch (its origin ref when there is no local
+// branch), else main, then master.
+func (wm *WorktreeManager) baseBranchRefs() []string {
+	branch := wm.configuredDefaultBranch()
+	if branch == "" {
+		return []string{"main", "master"}
+	}
+	if exec.Command("git", "show-ref", "--verify", "--quiet", "refs/heads/"+branch).Run() != nil {
+		return []string{"origin/" + branch}
+	}
+	return []string{branch}
+}
+
+// RecommendedBaseBranch returns the base for a new worktree when none is
+// given: the configured default_branch, else the git layer's recommendation.
+func (wm *WorktreeManager) RecommendedBaseBranch(ctx context.Context) (string, error) {
+	if branch := wm.configuredDefaultBranch(); branch != "" {
+		return branch, nil
+	}
+	return wm.gitRepo.GetRecommendedBaseBranch(ctx)
+}
+
 func (wm *WorktreeManager) stageAndCommitChanges(branch string) error {
 	logging.Info("Merge: staging and committing changes")
 
@@ -1577,18 +2608,76 @@ func (wm *WorktreeManager) squashCommits(target string, count int) error {
 	return nil
 }
 
-func (wm *WorktreeManager) rebaseOnto(target string) error {
+// rebaseOnto rebases the current branch onto target. A rebase that stops
+// is always aborted, since the merge pipeline can't continue from it; on
+// conflicts the error is a *MergeConflictError naming the files.
+func (wm *WorktreeManager) rebaseOnto(branch, target string) error {
 	logging.Info("Merge: rebasing onto %s", target)
 
 	cmd := exec.Command("git", "rebase", target)
 	if output, err := cmd.CombinedOutput(); err != nil {
+		files := conflictedFiles()
 		exec.Command("git", "rebase", "--abort").Run()
-		return fmt.Errorf("rebase failed (conflicts?): %s", string(output))
+		if len(files) > 0 {
+			return &MergeConflictError{Op: "rebase", Source: branch, Target: target, Files: files, Aborted: true}
+		}
+		return fmt.Errorf("rebase failed: %s", strings.TrimSpace(string(output)))
 	}
 
 	return nil
 }
 
+// mergeIntoCurrent merges result.TargetBranch into the current branch. On
+// conflicts it returns result with Conflicts set and a *MergeConflictError.
+func (wm *WorktreeManager) mergeIntoCurrent(result *MergeResult, abortOnConflict bool) (*MergeResult, error) {
+	source, target := result.TargetBranch, result.SourceBranch
+	logging.Info("Merge: merging %s into current branch %s", source, target)
+
+	// git merge refuses to start over changes it would touch, and a merge
+	// over unrelated changes can't be aborted cleanly
+	status, err := exec.Command("git", "status", "--porcelain", "--untracked-files=no").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for changes: %w", err)
+	}
+	if len(strings.TrimSpace(string(status))) > 0 {
+		return nil, fmt.Errorf("%s has uncommitted changes; commit or stash them before merging %s into it", target, source)
+	}
+
+	output, err := exec.Command("git", "merge", "--no-edit", source).CombinedOutput()
+	if err == nil {
+		return result, nil
+	}
+
+	files := conflictedFiles()
+	if len(files) == 0 {
+		return nil, fmt.Errorf("git merge failed: %s", strings.TrimSpace(string(output)))
+	}
+	conflict := &MergeConflictError{Op: "merge", Source: source, Target: target, Files: files}
+	if abortOnConflict {
+		if out, err := exec.Command("git", "merge", "--abort").CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("git merge --abort failed after conflicts in %s: %s", strings.Join(files, ", "), strings.TrimSpace(string(out)))
+		}
+		conflict.Aborted = true
+	}
+	result.Conflicts = files
+	return result, conflict
+}
+
+// conflictedFiles lists the unmerged files in the current worktree.
+func conflictedFiles() []string {
+	out, err := exec.Command("git", "diff", "--name-only", "--diff-filter=U").Output()
+	if err != nil {
+		return nil
+	}
+	var files []string
+	for _, line := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if line != "" {
+			files = append(files, line)
+		}
+	}
+	return files
+}
+
 func (wm *WorktreeManager) fastForwardMerge(source, target string) error {
 	logging.Info("Merge: fast-forward merging %s into %s", source, target)
 
@@ -1632,18 +2721,8 @@ func (wm *WorktreeManager) ForEach(ctx context.Context, opts ForEachOptions) ([]
 			continue
 		}
 
-		tmplCtx := wm.buildTemplateContext(&wt, repoRoot, repoName, defaultBranch)
-		expandedCmd := wm.expandCommand(opts.Command, tmplCtx)
-
 		result := ForEachResult{Worktree: &wt}
-
-		var cmd *exec.Cmd
-		if len(expandedCmd) == 1 {
-			cmd = exec.CommandContext(ctx, "sh", "-c", expandedCmd[0])
-		} else {
-			cmd = exec.CommandContext(ctx, expandedCmd[0], expandedCmd[1:]...)
-		}
-		cmd.Dir = wt.Path
+		cmd := wm.worktreeCommand(ctx, &wt, opts.Command, repoRoot, repoName, defaultBranch)
 
 		output, err := cmd.CombinedOutput()
 		result.Output = string(output)
@@ -1667,6 +2746,32 @@ func (wm *WorktreeManager) ForEach(ctx context.Context, opts ForEachOptions) ([]
 	return results, nil
 }
 
+// WorktreeCommand prepares command to run in wt, as `gren exec` does: with
+// the for-each template variables expanded and wt's path as the working
+// directory. A single argument runs through `sh -c`, so it may use pipes
+// and &&; several run as they are. The caller wires up stdio and runs it.
+func (wm *WorktreeManager) WorktreeCommand(ctx context.Context, wt *WorktreeInfo, command []string) *exec.Cmd {
+	repoRoot, _ := wm.getRepoRoot()
+	defaultBranch, _ := wm.getDefaultBranch()
+	return wm.worktreeCommand(ctx, wt, command, repoRoot, filepath.Base(repoRoot), defaultBranch)
+}
+
+// worktreeCommand is WorktreeCommand with the repository details looked up
+// once by the caller, for ForEach.
+func (wm *WorktreeManager) worktreeCommand(ctx context.Context, wt *WorktreeInfo, command []string, repoRoot, repoName, defaultBranch string) *exec.Cmd {
+	tmplCtx := wm.buildTemplateContext(wt, repoRoot, repoName, defaultBranch)
+	expandedCmd := wm.expandCommand(command, tmplCtx)
+
+	var cmd *exec.Cmd
+	if len(expandedCmd) == 1 {
+		cmd = exec.CommandContext(ctx, "sh", "-c", expandedCmd[0])
+	} else {
+		cmd = exec.CommandContext(ctx, expandedCmd[0], expandedCmd[1:]...)
+	}
+	cmd.Dir = wt.Path
+	return cmd
+}
+
 // EvalTemplate expands a template string against the current worktree's
 // context and returns the result. It powers `gren step eval`, exposing the
 // same engine hooks and for-each use so scripts can derive per-worktree ports,
@@ -1677,14 +2782,11 @@ func (wm *WorktreeManager) EvalTemplate(template string) (string, error) {
 		return "", err
 	}
 
-	cwd, err := os.Getwd()
-	if err != nil {
-		return "", err
-	}
+	worktreePath := currentWorktreeRoot()
 
 	branch, _ := wm.getCurrentBranch()
 	defaultBranch, _ := wm.getDefaultBranch()
-	commit := wm.getCommitSHA(cwd)
+	commit := wm.getCommitSHA(worktreePath)
 	shortCommit := commit
 	if len(commit) > 7 {
 		shortCommit = commit[:7]
@@ -1692,9 +2794,9 @@ func (wm *WorktreeManager) EvalTemplate(template string) (string, error) {
 
 	ctx := TemplateContext{
 		Branch:          branch,
-		BranchSanitized: sanitizeBranch(branch),
-		Worktree:        cwd,
-		WorktreeName:    filepath.Base(cwd),
+		BranchSanitized: wm.sanitizeBranch(branch),
+		Worktree:        worktreePath,
+		WorktreeName:    filepath.Base(worktreePath),
 		Repo:            filepath.Base(repoRoot),
 		RepoRoot:        repoRoot,
 		Commit:          commit,
@@ -1714,7 +2816,7 @@ func (wm *WorktreeManager) buildTemplateContext(wt *WorktreeInfo, repoRoot, repo
 
 	return TemplateContext{
 		Branch:          wt.Branch,
-		BranchSanitized: sanitizeBranch(wt.Branch),
+		BranchSanitized: wm.sanitizeBranch(wt.Branch),
 		Worktree:        wt.Path,
 		WorktreeName:    wt.Name,
 		Repo:            repoName,
@@ -1735,10 +2837,57 @@ func (wm *WorktreeManager) getCommitSHA(worktreePath string) string {
 	return strings.TrimSpace(string(output))
 }
 
-func sanitizeBranch(branch string) string {
-	result := strings.ReplaceAll(branch, "/", "-")
-	result = strings.ReplaceAll(result, "\\", "-")
-	return result
+// HasBranchMismatch reports whether another branch was checked out in wt
+// since gren created it, leaving its directory named for a branch it no
+// longer has. Only worktrees gren created are checked: a directory chosen
+// with -n or --path needn't match its branch, and that of a worktree made
+// with `git worktree add` is anyone's guess. The main worktree, bare
+// repositories and detached worktrees are never mismatched, nor is a
+// directory that happens to be named after the new branch.
+func HasBranchMismatch(wt WorktreeInfo) bool {
+	if wt.IsMain || wt.IsBare || wt.Branch == "" || wt.Branch == "(detached)" {
+		return false
+	}
+	created := worktreeCreatedBranch(wt.Path)
+	return created != "" && created != wt.Branch && !namedFor(filepath.Base(wt.Path), wt.Branch)
+}
+
+// namedFor reports whether dir is named after branch, as gren names it.
+// Case and which characters became "-" are ignored, so directories named
+// under any sanitize rules, or older defaults, still match.
+func namedFor(dir, branch string) bool {
+	return looseName(dir) == looseName(branch)
+}
+
+// looseName lowercases name and turns everything but letters and digits
+// into "-", the most any sanitize rules can do to a name.
+func looseName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToLower(r)
+		}
+		return '-'
+	}, name)
+}
+
+// SanitizeBranch turns a branch name into the directory name gren gives its
+// worktree under the default sanitize rules (config.Sanitize). Code with the
+// project config at hand applies its rules instead, as sanitizeBranch does.
+func SanitizeBranch(branch string) string {
+	return config.Sanitize{}.Apply(branch)
+}
+
+// sanitizeBranch is SanitizeBranch with the project's sanitize rules, falling
+// back to the defaults when the config can't be loaded.
+func (wm *WorktreeManager) sanitizeBranch(branch string) string {
+	if wm.configManager == nil {
+		return SanitizeBranch(branch)
+	}
+	cfg, err := wm.configManager.Load()
+	if err != nil {
+		return SanitizeBranch(branch)
+	}
+	return cfg.Sanitize.Apply(branch)
 }
 
 // hashPort deterministically maps a string (typically a branch name) to a port
@@ -1750,26 +2899,6 @@ func hashPort(s string) int {
 	return 10000 + int(h.Sum32()%10000)
 }
 
-// sanitizeDB turns a string (typically a branch name) into a safe SQL/database
-// identifier: lowercased, every non-alphanumeric rune replaced with an
-// underscore, and a leading underscore prepended when the result would start
-// with a digit (many databases reject identifiers that start with a digit).
-func sanitizeDB(s string) string {
-	var b strings.Builder
-	for _, r := range strings.ToLower(s) {
-		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
-			b.WriteRune(r)
-		} else {
-			b.WriteRune('_')
-		}
-	}
-	result := b.String()
-	if result != "" && result[0] >= '0' && result[0] <= '9' {
-		result = "_" + result
-	}
-	return result
-}
-
 func (wm *WorktreeManager) expandCommand(command []string, ctx TemplateContext) []string {
 	expanded := make([]string, len(command))
 	for i, arg := range command {
@@ -1795,8 +2924,8 @@ func templateReplacements(ctx TemplateContext, transform func(string) string) ma
 		"{{branch|sanitize}}":        t(ctx.BranchSanitized),
 		"{{ branch | hash_port }}":   t(strconv.Itoa(hashPort(ctx.Branch))),
 		"{{branch|hash_port}}":       t(strconv.Itoa(hashPort(ctx.Branch))),
-		"{{ branch | sanitize_db }}": t(sanitizeDB(ctx.Branch)),
-		"{{branch|sanitize_db}}":     t(sanitizeDB(ctx.Branch)),
+		"{{ branch | sanitize_db }}": t(config.SanitizeDB(ctx.Branch)),
+		"{{branch|sanitize_db}}":     t(config.SanitizeDB(ctx.Branch)),
 		"{{ worktree }}":             t(ctx.Worktree),
 		"{{worktree}}":               t(ctx.Worktree),
 		"{{ worktree_name }}":        t(ctx.WorktreeName),
@@ -1844,6 +2973,16 @@ func shellQuote(s string) string {
 type StepCommitOptions struct {
 	Message string
 	UseLLM  bool
+	// Paths limits the commit to these files, relative to the current
+	// directory; anything else, staged or not, stays out of it. Empty
+	// commits every change.
+	Paths []string
+}
+
+// ChangedFile is a file with uncommitted changes, as listed by ChangedFiles.
+type ChangedFile struct {
+	Path   string // Relative to the current directory
+	Status string // git status --porcelain code, e.g. " M", "A ", "??"
 }
 
 type StepSquashOptions struct {
@@ -1853,27 +2992,37 @@ type StepSquashOptions struct {
 }
 
 func (wm *WorktreeManager) StepCommit(opts StepCommitOptions) error {
-	logging.Info("StepCommit: committing staged changes")
+	logging.Info("StepCommit: committing staged changes (paths=%v)", opts.Paths)
 
-	addCmd := exec.Command("git", "add", "-A")
+	// With Paths, only those are staged, and the pathspec keeps whatever
+	// else was already staged out of the diff and the commit
+	var pathspec []string
+	if len(opts.Paths) > 0 {
+		pathspec = append([]string{"--"}, opts.Paths...)
+	}
+
+	addCmd := exec.Command("git", append([]string{"add", "-A"}, pathspec...)...)
 	if output, err := addCmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("git add failed: %s", string(output))
 	}
 
-	statusCmd := exec.Command("git", "status", "--porcelain")
+	statusCmd := exec.Command("git", append([]string{"status", "--porcelain"}, pathspec...)...)
 	statusOutput, err := statusCmd.Output()
 	if err != nil {
 		return fmt.Errorf("git status failed: %w", err)
 	}
 	if len(strings.TrimSpace(string(statusOutput))) == 0 {
+		if len(opts.Paths) > 0 {
+			return fmt.Errorf("nothing to commit in %s", strings.Join(opts.Paths, ", "))
+		}
 		return fmt.Errorf("nothing to commit")
 	}
 
 	message := opts.Message
 	if opts.UseLLM && message == "" {
 		cfg, _ := wm.configManager.Load()
-		if cfg != nil && cfg.CommitGenerator.Command != "" {
-			generated, err := wm.generateCommitMessage(cfg.CommitGenerator.Command, cfg.CommitGenerator.Args)
+		if llm := LLMConfig(cfg); llm.Configured() {
+			generated, err := wm.generateCommitMessage(llm, opts.Paths...)
 			if err != nil {
 				logging.Warn("StepCommit: LLM generation failed: %v, using default message", err)
 			} else {
@@ -1887,7 +3036,7 @@ func (wm *WorktreeManager) StepCommit(opts StepCommitOptions) error {
 		message = fmt.Sprintf("WIP: changes on %s", branch)
 	}
 
-	commitCmd := exec.Command("git", "commit", "-m", message)
+	commitCmd := exec.Command("git", append([]string{"commit", "-m", message}, pathspec...)...)
 	if output, err := commitCmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("git commit failed: %s", string(output))
 	}
@@ -1895,6 +3044,40 @@ func (wm *WorktreeManager) StepCommit(opts StepCommitOptions) error {
 	return nil
 }
 
+// ChangedFiles lists the files with uncommitted changes in the current
+// worktree, staged or not, untracked ones included, for picking which to
+// pass as StepCommitOptions.Paths.
+func (wm *WorktreeManager) ChangedFiles() ([]ChangedFile, error) {
+	output, err := exec.Command("git", "status", "--porcelain", "-z", "--untracked-files=all").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git status failed: %w", err)
+	}
+	prefixOut, err := exec.Command("git", "rev-parse", "--show-prefix").Output()
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse failed: %w", err)
+	}
+	prefix := strings.TrimSpace(string(prefixOut))
+
+	var files []ChangedFile
+	entries := strings.Split(string(output), "\x00")
+	for i := 0; i < len(entries); i++ {
+		entry := entries[i]
+		if len(entry) < 4 {
+			continue
+		}
+		status, path := entry[:2], entry[3:]
+		if status[0] == 'R' || status[0] == 'C' {
+			i++ // the original path follows a rename or copy
+		}
+		// Porcelain paths are relative to the top level; Paths are not
+		if rel, err := filepath.Rel(filepath.FromSlash(prefix), filepath.FromSlash(path)); err == nil {
+			path = rel
+		}
+		files = append(files, ChangedFile{Path: path, Status: status})
+	}
+	return files, nil
+}
+
 func (wm *WorktreeManager) StepSquash(opts StepSquashOptions) error {
 	logging.Info("StepSquash: squashing commits to %s", opts.Target)
 
@@ -1931,8 +3114,8 @@ func (wm *WorktreeManager) StepSquash(opts StepSquashOptions) error {
 	message := opts.Message
 	if opts.UseLLM && message == "" {
 		cfg, _ := wm.configManager.Load()
-		if cfg != nil && cfg.CommitGenerator.Command != "" {
-			generated, err := wm.generateSquashMessage(cfg.CommitGenerator.Command, cfg.CommitGenerator.Args, target)
+		if llm := LLMConfig(cfg); llm.Configured() {
+			generated, err := wm.generateSquashMessage(llm, target)
 			if err != nil {
 				logging.Warn("StepSquash: LLM generation failed: %v, using default message", err)
 			} else {
@@ -1958,25 +3141,18 @@ func (wm *WorktreeManager) StepSquash(opts StepSquashOptions) error {
 	return nil
 }
 
-func (wm *WorktreeManager) generateCommitMessage(command string, args []string) (string, error) {
-	diff, err := wm.getStagedDiff()
+func (wm *WorktreeManager) generateCommitMessage(llm config.CommitGenerator, paths ...string) (string, error) {
+	diff, err := wm.getStagedDiff(paths...)
 	if err != nil {
 		return "", fmt.Errorf("failed to get diff: %w", err)
 	}
 
 	prompt := buildCommitPrompt(diff, "")
 
-	cmd := exec.Command(command, args...)
-	cmd.Stdin = strings.NewReader(prompt)
-	output, err := cmd.Output()
-	if err != nil {
-		return "", fmt.Errorf("LLM command failed: %w", err)
-	}
-
-	return strings.TrimSpace(string(output)), nil
+	return generateWith(llm, prompt)
 }
 
-func (wm *WorktreeManager) generateSquashMessage(command string, args []string, target string) (string, error) {
+func (wm *WorktreeManager) generateSquashMessage(llm config.CommitGenerator, target string) (string, error) {
 	branch, _ := wm.getCurrentBranch()
 
 	diffCmd := exec.Command("git", "diff", fmt.Sprintf("%s...HEAD", target))
@@ -1991,24 +3167,32 @@ func (wm *WorktreeManager) generateSquashMessage(command string, args []string,
 	context := fmt.Sprintf("Branch: %s\nCommits being squashed:\n%s", branch, string(logOutput))
 	prompt := buildCommitPrompt(string(diffOutput), context)
 
-	cmd := exec.Command(command, args...)
-	cmd.Stdin = strings.NewReader(prompt)
-	output, err := cmd.Output()
+	return generateWith(llm, prompt)
+}
+
+// generateWith sends prompt to the LLM llm selects.
+func generateWith(llm config.CommitGenerator, prompt string) (string, error) {
+	gen, err := NewCommitMessageGenerator(llm)
 	if err != nil {
-		return "", fmt.Errorf("LLM command failed: %w", err)
+		return "", err
 	}
-
-	return strings.TrimSpace(string(output)), nil
+	return gen.Generate(context.Background(), prompt)
 }
 
-func (wm *WorktreeManager) getStagedDiff() (string, error) {
-	cmd := exec.Command("git", "diff", "--cached")
+// getStagedDiff returns the staged diff, limited to paths when given, or
+// the unstaged one when nothing is staged.
+func (wm *WorktreeManager) getStagedDiff(paths ...string) (string, error) {
+	var pathspec []string
+	if len(paths) > 0 {
+		pathspec = append([]string{"--"}, paths...)
+	}
+	cmd := exec.Command("git", append([]string{"diff", "--cached"}, pathspec...)...)
 	output, err := cmd.Output()
 	if err != nil {
 		return "", err
 	}
 	if len(output) == 0 {
-		cmd = exec.Command("git", "diff")
+		cmd = exec.Command("git", append([]string{"diff"}, pathspec...)...)
 		output, err = cmd.Output()
 		if err != nil {
 			return "", err
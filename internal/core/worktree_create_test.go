@@ -2,12 +2,16 @@ package core
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
 	"testing"
+
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/git"
 )
 
 func TestCreateWorktreeWithCustomDir(t *testing.T) {
@@ -44,6 +48,94 @@ func TestCreateWorktreeWithCustomDir(t *testing.T) {
 	_ = dir // keep reference for cleanup
 }
 
+func TestCreateWorktreeWithExplicitPath(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	parent := t.TempDir()
+
+	t.Run("places the worktree at the exact path", func(t *testing.T) {
+		want := filepath.Join(parent, "custom", "location")
+		got, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{
+			Name:         "feat",
+			IsNewBranch:  true,
+			WorktreeDir:  filepath.Join(parent, "ignored"),
+			ExplicitPath: want,
+		})
+		if err != nil {
+			t.Fatalf("CreateWorktree() error: %v", err)
+		}
+		if got != want {
+			t.Errorf("worktree path = %q, want %q", got, want)
+		}
+		if _, err := os.Stat(filepath.Join(want, ".git")); err != nil {
+			t.Errorf("worktree not created at %s: %v", want, err)
+		}
+		if _, err := os.Stat(filepath.Join(parent, "ignored")); !os.IsNotExist(err) {
+			t.Errorf("WorktreeDir was used despite ExplicitPath: %v", err)
+		}
+	})
+
+	t.Run("refuses an existing path", func(t *testing.T) {
+		existing := filepath.Join(parent, "existing")
+		os.MkdirAll(existing, 0755)
+		os.WriteFile(filepath.Join(existing, "file.txt"), []byte("x"), 0644)
+		_, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "feat-2", IsNewBranch: true, ExplicitPath: existing})
+		if err == nil || !strings.Contains(err.Error(), "already exists") {
+			t.Errorf("CreateWorktree() error = %v, want already exists", err)
+		}
+	})
+
+	t.Run("refuses a path inside a worktree", func(t *testing.T) {
+		_, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "feat-3", IsNewBranch: true, ExplicitPath: filepath.Join(dir, "nested")})
+		if err == nil || !strings.Contains(err.Error(), "inside worktree") {
+			t.Errorf("CreateWorktree() error = %v, want inside worktree", err)
+		}
+	})
+}
+
+func TestCreateWorktreePathCollision(t *testing.T) {
+	_, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	worktreeDir := t.TempDir()
+
+	t.Run("non-empty directory is refused", func(t *testing.T) {
+		leftover := filepath.Join(worktreeDir, "leftover")
+		os.MkdirAll(leftover, 0755)
+		os.WriteFile(filepath.Join(leftover, "file.txt"), []byte("x"), 0644)
+		_, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "leftover", IsNewBranch: true, WorktreeDir: worktreeDir})
+		if !errors.Is(err, ErrWorktreePathExists) || !strings.Contains(err.Error(), "not empty") {
+			t.Errorf("CreateWorktree() error = %v, want ErrWorktreePathExists (not empty)", err)
+		}
+		if _, err := os.Stat(filepath.Join(leftover, "file.txt")); err != nil {
+			t.Errorf("leftover content was touched: %v", err)
+		}
+	})
+
+	t.Run("empty directory is reused", func(t *testing.T) {
+		empty := filepath.Join(worktreeDir, "empty")
+		os.MkdirAll(empty, 0755)
+		path, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "empty", IsNewBranch: true, WorktreeDir: worktreeDir})
+		if err != nil {
+			t.Fatalf("CreateWorktree() error = %v", err)
+		}
+		if _, err := os.Stat(filepath.Join(path, "README.md")); err != nil {
+			t.Errorf("worktree not checked out at %s: %v", path, err)
+		}
+	})
+
+	t.Run("file at the path is refused", func(t *testing.T) {
+		os.WriteFile(filepath.Join(worktreeDir, "file"), []byte("x"), 0644)
+		_, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "file", IsNewBranch: true, WorktreeDir: worktreeDir})
+		if !errors.Is(err, ErrWorktreePathExists) {
+			t.Errorf("CreateWorktree() error = %v, want ErrWorktreePathExists", err)
+		}
+	})
+}
+
 // TestCreateWorktreeWithoutGrenInit verifies that gren create works on a git
 // repository that was never `gren init`-ed: it falls back to default settings
 // (no hooks) and places the worktree under the default ../<repo>-worktrees
@@ -78,6 +170,168 @@ func TestCreateWorktreeWithoutGrenInit(t *testing.T) {
 	}
 }
 
+// TestCreateWorktreeInBareRepo verifies that a bare repository (no main
+// working tree, no --show-toplevel) gets a sibling <name>-worktrees default
+// and that hooks/templates see the bare dir as the repo root.
+func TestCreateWorktreeInBareRepo(t *testing.T) {
+	dir, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	parent, err := os.MkdirTemp("", "gren-bare-parent-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(parent)
+	parent, _ = filepath.EvalSymlinks(parent)
+
+	bareDir := filepath.Join(parent, "project.git")
+	runGit(t, dir, "clone", "--bare", dir, bareDir)
+	if err := os.Chdir(bareDir); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewWorktreeManager(git.NewLocalRepository(), config.NewManager())
+	worktreePath, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:        "feature-bare",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() in bare repo error: %v", err)
+	}
+
+	want := filepath.Join(parent, "project-worktrees", "feature-bare")
+	if worktreePath != want {
+		t.Errorf("worktree path = %q, want %q", worktreePath, want)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "README.md")); err != nil {
+		t.Errorf("worktree not checked out at %q: %v", worktreePath, err)
+	}
+
+	root, err := manager.getRepoRoot()
+	if err != nil || root != bareDir {
+		t.Errorf("getRepoRoot() = %q, %v, want bare dir %q", root, err, bareDir)
+	}
+
+	// From inside the linked worktree the repo is still recognized as bare.
+	if err := os.Chdir(worktreePath); err != nil {
+		t.Fatal(err)
+	}
+	if got := bareGitDir(); got != bareDir {
+		t.Errorf("bareGitDir() from linked worktree = %q, want %q", got, bareDir)
+	}
+}
+
+// TestBareCloneLayout covers the "<project>/.bare" layout: a bare repository
+// in .bare, <project>/.git pointing at it, and worktrees as its siblings.
+func TestBareCloneLayout(t *testing.T) {
+	dir, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	parent, err := os.MkdirTemp("", "gren-bare-layout-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(parent)
+	parent, _ = filepath.EvalSymlinks(parent)
+
+	project := filepath.Join(parent, "project")
+	bareDir := filepath.Join(project, ".bare")
+	runGit(t, parent, "init", "--bare", "-b", "main", bareDir)
+	runGit(t, parent, "--git-dir", bareDir, "fetch", dir, "main:main")
+	if err := os.WriteFile(filepath.Join(project, ".git"), []byte("gitdir: ./.bare\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+
+	manager := NewWorktreeManager(git.NewLocalRepository(), config.NewManager())
+	worktreePath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{
+		Name:        "feature",
+		BaseBranch:  "main",
+		IsNewBranch: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	if want := filepath.Join(project, "feature"); worktreePath != want {
+		t.Errorf("worktree path = %q, want %q next to .bare", worktreePath, want)
+	}
+
+	find := func(worktrees []WorktreeInfo, path string) WorktreeInfo {
+		t.Helper()
+		for _, wt := range worktrees {
+			if wt.Path == path {
+				return wt
+			}
+		}
+		t.Fatalf("no worktree at %q in %+v", path, worktrees)
+		return WorktreeInfo{}
+	}
+
+	// From the project dir and from .bare itself, the bare repo is listed
+	// as main but never current
+	for _, cwd := range []string{project, bareDir} {
+		if err := os.Chdir(cwd); err != nil {
+			t.Fatal(err)
+		}
+		worktrees, err := manager.ListWorktrees(ctx)
+		if err != nil {
+			t.Fatalf("ListWorktrees() from %s error: %v", cwd, err)
+		}
+		bare := find(worktrees, bareDir)
+		if !bare.IsBare || !bare.IsMain || bare.IsCurrent || bare.Status != "clean" {
+			t.Errorf("from %s: bare entry = %+v, want bare, main, not current, clean", cwd, bare)
+		}
+		if feature := find(worktrees, worktreePath); feature.IsBare || feature.IsMain || feature.Branch != "feature" {
+			t.Errorf("from %s: feature entry = %+v, want a linked worktree on feature", cwd, feature)
+		}
+	}
+
+	if err := manager.DeleteWorktree(ctx, ".bare", true); !errors.Is(err, ErrDeleteBare) {
+		t.Errorf("DeleteWorktree(.bare) error = %v, want ErrDeleteBare", err)
+	}
+	if _, err := os.Stat(filepath.Join(bareDir, "HEAD")); err != nil {
+		t.Fatalf("bare repository damaged: %v", err)
+	}
+
+	if err := os.Chdir(worktreePath); err != nil {
+		t.Fatal(err)
+	}
+	worktrees, err := manager.ListWorktrees(ctx)
+	if err != nil {
+		t.Fatalf("ListWorktrees() from the worktree error: %v", err)
+	}
+	if !find(worktrees, worktreePath).IsCurrent {
+		t.Error("the linked worktree gren runs in should be current")
+	}
+
+	if err := os.Chdir(project); err != nil {
+		t.Fatal(err)
+	}
+	if err := manager.DeleteWorktree(ctx, "feature", false); err != nil {
+		t.Fatalf("DeleteWorktree(feature) error: %v", err)
+	}
+	if _, err := os.Stat(worktreePath); !os.IsNotExist(err) {
+		t.Errorf("worktree still exists after delete: %v", err)
+	}
+}
+
+func TestBareRepoName(t *testing.T) {
+	tests := map[string]string{
+		"/src/project.git":   "project",
+		"/src/project":       "project",
+		"/src/project/.bare": "project",
+	}
+	for gitDir, want := range tests {
+		if got := bareRepoName(gitDir); got != want {
+			t.Errorf("bareRepoName(%q) = %q, want %q", gitDir, got, want)
+		}
+	}
+}
+
 // TestCreateWorktreeExpandsWorktreeDirTemplate verifies that a templated
 // worktree_dir (which gren's own config help advertises, e.g.
 // "../{{ repo }}-worktrees") is expanded, not used literally.
@@ -110,6 +364,32 @@ func TestCreateWorktreeExpandsWorktreeDirTemplate(t *testing.T) {
 	_ = dir
 }
 
+func TestWorktreePathUserDefault(t *testing.T) {
+	dir, _, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(home, ".config"))
+	t.Setenv("APPDATA", filepath.Join(home, "AppData"))
+	user := &config.UserConfig{Defaults: config.UserDefaults{WorktreeDir: "~/worktrees/{{ repo }}"}}
+	if err := config.NewUserConfigManager().Save(user); err != nil {
+		t.Fatalf("saving user config: %v", err)
+	}
+	// Uninitialized, so the project sets no worktree_dir
+	os.RemoveAll(filepath.Join(dir, ".gren"))
+	manager := NewWorktreeManager(git.NewLocalRepository(), config.NewManager())
+
+	path, err := manager.WorktreePath(context.Background(), "feat", "")
+	if err != nil {
+		t.Fatalf("WorktreePath: %v", err)
+	}
+	want := filepath.Join(home, "worktrees", filepath.Base(dir), "feat")
+	if path != want {
+		t.Errorf("WorktreePath = %q, want %q", path, want)
+	}
+}
+
 func TestCreateWorktreeWithBaseBranch(t *testing.T) {
 	dir, manager, cleanup := setupTestEnvironment(t)
 	defer cleanup()
@@ -147,6 +427,45 @@ func TestCreateWorktreeWithBaseBranch(t *testing.T) {
 	})
 }
 
+func TestCreateWorktreeWarnsWhenBehindBase(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	for _, branch := range []string{"far-behind", "slightly-behind"} {
+		if out, err := exec.Command("git", "-C", dir, "branch", branch).CombinedOutput(); err != nil {
+			t.Fatalf("git branch %s: %v\n%s", branch, err, out)
+		}
+		// far-behind misses every commit below, slightly-behind only the last
+		n := behindBaseWarnCommits
+		if branch == "slightly-behind" {
+			n = 1
+		}
+		for i := 0; i < n; i++ {
+			if out, err := exec.Command("git", "-C", dir, "commit", "--allow-empty", "-m", fmt.Sprintf("main %s %d", branch, i)).CombinedOutput(); err != nil {
+				t.Fatalf("git commit: %v\n%s", err, out)
+			}
+		}
+	}
+
+	_, warning, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "far-behind", BaseBranch: "main"})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	want := fmt.Sprintf("far-behind is %d commits behind main; consider rebasing", behindBaseWarnCommits+1)
+	if !strings.Contains(warning, want) {
+		t.Errorf("warning = %q, want %q", warning, want)
+	}
+
+	_, warning, err = manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "slightly-behind", BaseBranch: "main"})
+	if err != nil {
+		t.Fatalf("CreateWorktree() error: %v", err)
+	}
+	if strings.Contains(warning, "behind") {
+		t.Errorf("warning = %q for a branch 1 commit behind, want none", warning)
+	}
+}
+
 func TestCreateWorktreeWithGrenSymlink(t *testing.T) {
 	dir, manager, cleanup := setupTestEnvironment(t)
 	defer cleanup()
@@ -619,3 +938,162 @@ func TestCreateWorktreeWithExistingBranchBothLocalAndRemote(t *testing.T) {
 		}
 	})
 }
+
+// TestCreateWorktreePreferRemote verifies that PreferRemote (--track-remote)
+// creates the worktree from origin/<branch> even when the local branch is
+// ahead, and warns that the local commits were left out.
+func TestCreateWorktreePreferRemote(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	remoteDir, err := os.MkdirTemp("", "gren-remote-prefer-*")
+	if err != nil {
+		t.Fatalf("failed to create remote dir: %v", err)
+	}
+	defer os.RemoveAll(remoteDir)
+
+	exec.Command("git", "-C", remoteDir, "init", "--bare").Run()
+	exec.Command("git", "-C", dir, "remote", "add", "origin", remoteDir).Run()
+	exec.Command("git", "-C", dir, "push", "-u", "origin", "HEAD").Run()
+
+	// Branch pushed to origin, then one more local-only commit on top.
+	exec.Command("git", "-C", dir, "branch", "force-pushed").Run()
+	exec.Command("git", "-C", dir, "push", "-u", "origin", "force-pushed").Run()
+	remoteHead, _ := exec.Command("git", "-C", dir, "rev-parse", "origin/force-pushed").Output()
+	exec.Command("git", "-C", dir, "checkout", "force-pushed").Run()
+	os.WriteFile(filepath.Join(dir, "local-only.txt"), []byte("local"), 0644)
+	exec.Command("git", "-C", dir, "add", "local-only.txt").Run()
+	exec.Command("git", "-C", dir, "commit", "-m", "Local only").Run()
+	exec.Command("git", "-C", dir, "checkout", "main").Run()
+
+	worktreePath, warning, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+		Name:         "prefer-remote",
+		Branch:       "force-pushed",
+		PreferRemote: true,
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() with PreferRemote error: %v", err)
+	}
+
+	head, _ := exec.Command("git", "-C", worktreePath, "rev-parse", "HEAD").Output()
+	if strings.TrimSpace(string(head)) != strings.TrimSpace(string(remoteHead)) {
+		t.Errorf("worktree HEAD = %s, want origin/force-pushed %s", head, remoteHead)
+	}
+	if _, err := os.Stat(filepath.Join(worktreePath, "local-only.txt")); err == nil {
+		t.Error("local-only commit should not be in the worktree")
+	}
+	if !strings.Contains(warning, "unpushed") {
+		t.Errorf("warning = %q, want a note about unpushed commits", warning)
+	}
+
+	t.Run("errors when remote branch is missing", func(t *testing.T) {
+		exec.Command("git", "-C", dir, "branch", "local-only-branch").Run()
+		_, _, err := manager.CreateWorktree(context.Background(), CreateWorktreeRequest{
+			Name:         "local-only-wt",
+			Branch:       "local-only-branch",
+			PreferRemote: true,
+		})
+		if err == nil {
+			t.Fatal("CreateWorktree() with PreferRemote and no remote branch = nil error, want error")
+		}
+	})
+}
+
+// TestCreateWorktreeFromNamedRemote verifies that Remote (--remote) creates
+// the branch from <remote>/<branch> and leaves it tracking that ref instead
+// of origin.
+func TestCreateWorktreeFromNamedRemote(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	ctx := context.Background()
+
+	// A fork setup: origin has only main, upstream has the feature branch
+	origin, upstream := t.TempDir(), t.TempDir()
+	runGit(t, origin, "init", "--bare")
+	runGit(t, upstream, "init", "--bare")
+	runGit(t, dir, "remote", "add", "origin", origin)
+	runGit(t, dir, "remote", "add", "upstream", upstream)
+	runGit(t, dir, "push", "origin", "HEAD")
+	runGit(t, dir, "branch", "feature")
+	runGit(t, dir, "push", "upstream", "feature")
+	runGit(t, dir, "branch", "-D", "feature")
+
+	worktreePath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{
+		Name:   "fork-feature",
+		Branch: "feature",
+		Remote: "upstream",
+	})
+	if err != nil {
+		t.Fatalf("CreateWorktree() with Remote error: %v", err)
+	}
+	upstreamRef, _ := exec.Command("git", "-C", worktreePath, "rev-parse", "--abbrev-ref", "@{upstream}").Output()
+	if got := strings.TrimSpace(string(upstreamRef)); got != "upstream/feature" {
+		t.Errorf("upstream = %q, want upstream/feature", got)
+	}
+
+	t.Run("unknown remote", func(t *testing.T) {
+		_, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "x", Branch: "other", Remote: "nope"})
+		if err == nil || !strings.Contains(err.Error(), "remote 'nope' not found") {
+			t.Errorf("error = %v, want remote not found", err)
+		}
+	})
+
+	t.Run("branch missing on remote", func(t *testing.T) {
+		_, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "y", Branch: "missing", Remote: "upstream"})
+		if err == nil || !strings.Contains(err.Error(), "not found on remote 'upstream'") {
+			t.Errorf("error = %v, want branch not found on remote", err)
+		}
+	})
+}
+
+func TestCreateWorktreePreset(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	runGit(t, dir, "checkout", "-q", "-b", "web-base")
+	os.WriteFile(filepath.Join(dir, "web.txt"), []byte("web\n"), 0644)
+	runGit(t, dir, "add", "web.txt")
+	runGit(t, dir, "commit", "-q", "-m", "web base")
+	runGit(t, dir, "checkout", "-q", "-")
+	os.WriteFile(filepath.Join(dir, "dev.sqlite3"), []byte("db"), 0600)
+	os.MkdirAll(filepath.Join(dir, "node_modules", "react"), 0755)
+
+	cfg := `{
+		"worktree_dir": "` + filepath.Join(filepath.Dir(dir), "test-worktrees") + `",
+		"version": "1.0.0",
+		"presets": {
+			"frontend": {"base_branch": "web-base", "copy_files": ["dev.sqlite3"], "symlink_files": ["node_modules"], "post_create": "touch preset-ran"}
+		}
+	}`
+	if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	path, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "fe", IsNewBranch: true, Preset: "frontend"})
+	if err != nil {
+		t.Fatalf("CreateWorktree() with preset: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "web.txt")); err != nil {
+		t.Errorf("worktree not based on the preset's base branch: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(path, "dev.sqlite3")); err != nil {
+		t.Errorf("preset copy_files not copied: %v", err)
+	}
+	if target, err := os.Readlink(filepath.Join(path, "node_modules")); err != nil || target != filepath.Join(dir, "node_modules") {
+		t.Errorf("preset symlink_files: node_modules links to %q (%v), want %s", target, err, filepath.Join(dir, "node_modules"))
+	}
+
+	results := manager.RunPostCreateHookWithPreset(path, "fe", "web-base", "frontend", true)
+	if HooksFailed(results) {
+		t.Fatalf("preset post-create hook failed: %+v", results)
+	}
+	if _, err := os.Stat(filepath.Join(path, "preset-ran")); err != nil {
+		t.Errorf("preset post-create hook did not run: %v", err)
+	}
+
+	_, _, err = manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "be", IsNewBranch: true, Preset: "backend"})
+	if err == nil || !strings.Contains(err.Error(), "available: frontend") {
+		t.Errorf("CreateWorktree() with an unknown preset = %v, want an error listing frontend", err)
+	}
+}
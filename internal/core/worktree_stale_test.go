@@ -2,9 +2,11 @@ package core
 
 import (
 	"context"
+	"errors"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -305,3 +307,146 @@ func TestStaleStatusInListWorktrees(t *testing.T) {
 		}
 	})
 }
+
+func TestStaleStatusBranchDeleted(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	worktreeDir := filepath.Join(filepath.Dir(dir), "test-worktrees")
+	os.MkdirAll(worktreeDir, 0755)
+	for _, branch := range []string{"deleted-branch", "kept-branch"} {
+		worktreePath := filepath.Join(worktreeDir, branch)
+		exec.Command("git", "-C", dir, "worktree", "add", "-b", branch, worktreePath).Run()
+		os.WriteFile(filepath.Join(worktreePath, branch+".txt"), []byte(branch), 0644)
+		exec.Command("git", "-C", worktreePath, "add", ".").Run()
+		exec.Command("git", "-C", worktreePath, "commit", "-m", "Work on "+branch).Run()
+	}
+
+	// Current git refuses to delete a checked-out branch; older git, other
+	// clones sharing the repository and ref tools don't
+	if exec.Command("git", "-C", dir, "branch", "-D", "deleted-branch").Run() != nil {
+		runGit(t, dir, "update-ref", "-d", "refs/heads/deleted-branch")
+	}
+
+	worktrees, err := manager.ListWorktrees(context.Background())
+	if err != nil {
+		t.Fatalf("ListWorktrees() error: %v", err)
+	}
+	byBranch := make(map[string]WorktreeInfo)
+	for _, wt := range worktrees {
+		byBranch[wt.Branch] = wt
+	}
+
+	deleted, ok := byBranch["deleted-branch"]
+	if !ok {
+		t.Fatal("worktree of the deleted branch not listed")
+	}
+	if deleted.BranchStatus != "stale" || deleted.StaleReason != "branch_deleted" {
+		t.Errorf("deleted branch: BranchStatus = %q, StaleReason = %q, want stale, branch_deleted", deleted.BranchStatus, deleted.StaleReason)
+	}
+	if kept := byBranch["kept-branch"]; kept.BranchStatus != "active" {
+		t.Errorf("kept branch: BranchStatus = %q (%s), want active", kept.BranchStatus, kept.StaleReason)
+	}
+
+	wt := &WorktreeInfo{Name: "deleted-branch", Branch: "deleted-branch", Path: deleted.Path, Status: "clean"}
+	manager.enrichStaleStatus(wt)
+	if wt.StaleReason != "branch_deleted" {
+		t.Errorf("enrichStaleStatus() StaleReason = %q, want branch_deleted", wt.StaleReason)
+	}
+
+	if err := manager.DeleteStaleBranch(deleted, false); err != nil {
+		t.Errorf("DeleteStaleBranch() of a deleted branch = %v, want nil", err)
+	}
+}
+
+func TestStaleStatusOrphanBranch(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// A new orphan branch has no ref until its first commit. `git worktree
+	// add --orphan` needs git 2.42; older git gets the same state from a
+	// detached worktree switched to an orphan branch, minus its reflog.
+	orphanPath := filepath.Join(filepath.Dir(dir), "test-worktrees", "orphan")
+	if exec.Command("git", "-C", dir, "worktree", "add", "--orphan", "-b", "orphan", orphanPath).Run() != nil {
+		runGit(t, dir, "worktree", "add", "--detach", orphanPath)
+		runGit(t, orphanPath, "checkout", "--orphan", "orphan")
+		reflog, err := exec.Command("git", "-C", orphanPath, "rev-parse", "--path-format=absolute", "--git-path", "logs/HEAD").Output()
+		if err != nil {
+			t.Fatal(err)
+		}
+		os.Remove(strings.TrimSpace(string(reflog)))
+	}
+
+	worktrees, err := manager.ListWorktrees(context.Background())
+	if err != nil {
+		t.Fatalf("ListWorktrees() error: %v", err)
+	}
+	for _, wt := range worktrees {
+		if wt.Branch == "orphan" && wt.BranchStatus != "active" {
+			t.Errorf("orphan branch: BranchStatus = %q, StaleReason = %q, want active", wt.BranchStatus, wt.StaleReason)
+		}
+	}
+
+	wt := &WorktreeInfo{Name: "orphan", Branch: "orphan", Path: orphanPath, Status: "clean"}
+	manager.enrichStaleStatus(wt)
+	if wt.StaleReason == "branch_deleted" {
+		t.Error("enrichStaleStatus() flags the unborn orphan branch as deleted")
+	}
+}
+
+func TestFetchOriginPrune(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	t.Run("errors without a remote", func(t *testing.T) {
+		if err := manager.FetchOriginPrune(); !errors.Is(err, ErrNoRemote) {
+			t.Errorf("FetchOriginPrune() without a remote = %v, want ErrNoRemote", err)
+		}
+	})
+
+	t.Run("prunes branches deleted on the remote", func(t *testing.T) {
+		remoteDir, err := os.MkdirTemp("", "gren-remote-prune-*")
+		if err != nil {
+			t.Fatalf("failed to create remote dir: %v", err)
+		}
+		defer os.RemoveAll(remoteDir)
+
+		exec.Command("git", "-C", remoteDir, "init", "--bare").Run()
+		exec.Command("git", "-C", dir, "remote", "add", "origin", remoteDir).Run()
+		// The manager remembers there was no remote; a new one looks again
+		manager := NewWorktreeManager(manager.gitRepo, manager.configManager)
+		exec.Command("git", "-C", dir, "checkout", "-b", "pruned-branch").Run()
+		exec.Command("git", "-C", dir, "push", "-u", "origin", "pruned-branch").Run()
+		exec.Command("git", "-C", dir, "checkout", "-").Run()
+
+		// Deleted on the remote by someone else: our tracking ref is untouched.
+		exec.Command("git", "-C", remoteDir, "branch", "-D", "pruned-branch").Run()
+		if manager.isRemoteBranchGone("pruned-branch") {
+			t.Fatal("isRemoteBranchGone() before fetch = true, want false (ref still cached)")
+		}
+
+		if err := manager.FetchOriginPrune(); err != nil {
+			t.Fatalf("FetchOriginPrune() error: %v", err)
+		}
+		if !manager.isRemoteBranchGone("pruned-branch") {
+			t.Error("isRemoteBranchGone() after FetchOriginPrune = false, want true")
+		}
+	})
+}
+
+func TestParseStaleReasons(t *testing.T) {
+	reasons, err := ParseStaleReasons("pr_merged, remote_gone")
+	if err != nil {
+		t.Fatalf("ParseStaleReasons() error = %v", err)
+	}
+	if len(reasons) != 2 || !reasons["pr_merged"] || !reasons["remote_gone"] {
+		t.Errorf("ParseStaleReasons() = %v, want pr_merged and remote_gone", reasons)
+	}
+
+	if _, err := ParseStaleReasons("pr_merged,gone"); err == nil || !strings.Contains(err.Error(), "unknown stale reason 'gone'") {
+		t.Errorf("ParseStaleReasons() with unknown reason: error = %v", err)
+	}
+	if _, err := ParseStaleReasons(" , "); err == nil {
+		t.Error("ParseStaleReasons() with no reasons should error")
+	}
+}
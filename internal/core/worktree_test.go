@@ -5,6 +5,7 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/langtind/gren/internal/config"
@@ -144,6 +145,75 @@ func TestListWorktrees(t *testing.T) {
 	})
 }
 
+func TestListWorktreesCurrentFromSubdirectory(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	defer os.Chdir(dir)
+
+	ctx := context.Background()
+	worktreePath := filepath.Join(filepath.Dir(dir), "test-worktrees", "nested-current")
+	runGit(t, dir, "worktree", "add", "-b", "nested-current", worktreePath)
+	nested := filepath.Join(worktreePath, "deep", "er")
+	if err := os.MkdirAll(nested, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	current := func() string {
+		t.Helper()
+		worktrees, err := manager.ListWorktrees(ctx)
+		if err != nil {
+			t.Fatalf("ListWorktrees() error: %v", err)
+		}
+		var names []string
+		for _, wt := range worktrees {
+			if wt.IsCurrent {
+				names = append(names, wt.Name)
+			}
+		}
+		return strings.Join(names, ",")
+	}
+
+	os.Chdir(nested)
+	if got := current(); got != "nested-current" {
+		t.Errorf("current worktree from %s = %q, want nested-current", nested, got)
+	}
+	if err := manager.DeleteWorktree(ctx, "nested-current", true); err == nil || !strings.Contains(err.Error(), "current worktree") {
+		t.Errorf("DeleteWorktree() from a subdirectory = %v, want it refused as the current worktree", err)
+	}
+
+	// git has no toplevel inside .git; the worktree around it is current
+	os.Chdir(filepath.Join(dir, ".git", "refs"))
+	if got := current(); got != filepath.Base(dir) {
+		t.Errorf("current worktree from .git/refs = %q, want the main worktree %q", got, filepath.Base(dir))
+	}
+}
+
+func TestMarkCurrentWorktreeResolvesSymlinks(t *testing.T) {
+	base := t.TempDir()
+	real := filepath.Join(base, "real")
+	if err := os.MkdirAll(filepath.Join(real, "feat"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(real, filepath.Join(base, "link")); err != nil {
+		t.Skipf("symlinks unavailable: %v", err)
+	}
+
+	worktrees := []WorktreeInfo{
+		{Name: "main", Path: filepath.Join(base, "main")},
+		{Name: "feat", Path: filepath.Join(base, "link", "feat")}, // recorded through the link
+	}
+	markCurrentWorktree(worktrees, filepath.Join(real, "feat"))
+	if worktrees[0].IsCurrent || !worktrees[1].IsCurrent {
+		t.Errorf("IsCurrent = %v, %v; want only feat, recorded through a symlink", worktrees[0].IsCurrent, worktrees[1].IsCurrent)
+	}
+
+	bare := []WorktreeInfo{{Name: "project.git", Path: base, IsBare: true}}
+	markCurrentWorktree(bare, filepath.Join(base, "refs"))
+	if bare[0].IsCurrent {
+		t.Error("the bare repository should never be current")
+	}
+}
+
 func TestParseWorktreeList(t *testing.T) {
 	manager := &WorktreeManager{}
 
@@ -721,3 +791,203 @@ func TestCreateWorktreeRequestFields(t *testing.T) {
 		}
 	})
 }
+
+func TestWorktreeOperationsFromLinkedWorktree(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// A relative worktree_dir, as `gren init` writes it, must resolve against
+	// the main worktree rather than the linked one gren is run from.
+	worktreeDir := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-linked")
+	defer os.RemoveAll(worktreeDir)
+	configContent := `{
+		"worktree_dir": "../` + filepath.Base(dir) + `-linked",
+		"package_manager": "auto",
+		"version": "1.0.0"
+	}`
+	os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(configContent), 0644)
+
+	ctx := context.Background()
+	firstPath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "first", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree(first) error: %v", err)
+	}
+
+	subdir := filepath.Join(firstPath, "sub")
+	os.MkdirAll(subdir, 0755)
+	os.Chdir(subdir)
+
+	secondPath, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "second", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree(second) from linked worktree error: %v", err)
+	}
+	if want := filepath.Join(worktreeDir, "second"); secondPath != want {
+		t.Errorf("CreateWorktree(second) path = %s, want %s", secondPath, want)
+	}
+
+	worktrees, err := manager.ListWorktrees(ctx)
+	if err != nil {
+		t.Fatalf("ListWorktrees() error: %v", err)
+	}
+	if len(worktrees) != 3 {
+		t.Fatalf("ListWorktrees() returned %d worktrees, want 3", len(worktrees))
+	}
+	for _, wt := range worktrees {
+		if want := wt.Name == "first"; wt.IsCurrent != want {
+			t.Errorf("worktree %s IsCurrent = %v, want %v", wt.Name, wt.IsCurrent, want)
+		}
+	}
+
+	if err := manager.DeleteWorktree(ctx, "second", true); err != nil {
+		t.Fatalf("DeleteWorktree(second) from linked worktree error: %v", err)
+	}
+	if _, err := os.Stat(secondPath); !os.IsNotExist(err) {
+		t.Errorf("worktree %s still exists after delete", secondPath)
+	}
+	if err := manager.DeleteWorktree(ctx, "first", true); err == nil {
+		t.Error("DeleteWorktree(first) from inside it = nil error, want current worktree refused")
+	}
+}
+
+func TestCreateWorktreeFromSubdirectory(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// The default config gren init writes: relative to the main worktree
+	worktreeDir := filepath.Join(filepath.Dir(dir), filepath.Base(dir)+"-nested")
+	defer os.RemoveAll(worktreeDir)
+	configContent := `{
+		"worktree_dir": "../` + filepath.Base(dir) + `-nested",
+		"package_manager": "auto",
+		"version": "1.0.0"
+	}`
+	os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(configContent), 0644)
+
+	nested := filepath.Join(dir, "src", "pkg", "deep")
+	os.MkdirAll(nested, 0755)
+	os.Chdir(nested)
+
+	ctx := context.Background()
+	want := filepath.Join(worktreeDir, "feature-nested")
+	if got, err := manager.WorktreePath(ctx, "feature/nested", ""); err != nil || got != want {
+		t.Errorf("WorktreePath() = %q, %v; want %q", got, err, want)
+	}
+
+	got, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "feature/nested", IsNewBranch: true})
+	if err != nil {
+		t.Fatalf("CreateWorktree() from %s error: %v", nested, err)
+	}
+	if got != want {
+		t.Errorf("CreateWorktree() path = %s, want %s", got, want)
+	}
+	if _, err := os.Stat(filepath.Join(want, "README.md")); err != nil {
+		t.Errorf("worktree not checked out at %s: %v", want, err)
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(nested), filepath.Base(dir)+"-nested")); !os.IsNotExist(err) {
+		t.Errorf("worktree_dir resolved against the working directory: %v", err)
+	}
+}
+
+func TestListWorktreesBasic(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	ctx := context.Background()
+	if err := os.WriteFile(filepath.Join(dir, "untracked.txt"), []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	worktrees, err := manager.ListWorktreesBasic(ctx)
+	if err != nil {
+		t.Fatalf("ListWorktreesBasic() error: %v", err)
+	}
+	if len(worktrees) != 1 {
+		t.Fatalf("got %d worktrees, want 1", len(worktrees))
+	}
+	wt := worktrees[0]
+	if !wt.IsMain || wt.LastCommit == "" {
+		t.Errorf("basic listing = %+v, want the main worktree with its last commit time", wt)
+	}
+	if wt.Status != "" || wt.UntrackedCount != 0 || wt.BranchStatus != "" {
+		t.Errorf("basic listing should leave status empty, got status %q, untracked %d, branch status %q", wt.Status, wt.UntrackedCount, wt.BranchStatus)
+	}
+
+	manager.EnrichStatus(&worktrees[0])
+	manager.EnrichStaleStatus(worktrees)
+	if wt := worktrees[0]; wt.Status != "untracked" || wt.UntrackedCount == 0 || wt.BranchStatus != "active" {
+		t.Errorf("after enriching = status %q, untracked %d, branch status %q, want untracked files and active", wt.Status, wt.UntrackedCount, wt.BranchStatus)
+	}
+}
+
+func TestEnrichStatusUnreadable(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+	path := filepath.Join(filepath.Dir(dir), "test-worktrees", "unreadable-wt")
+	runGit(t, dir, "worktree", "add", "-b", "unreadable-branch", path)
+	os.WriteFile(filepath.Join(path, "work.txt"), []byte("work\n"), 0644)
+
+	// A corrupt index makes git status fail, as a held index.lock can
+	out, err := exec.Command("git", "-C", path, "rev-parse", "--path-format=absolute", "--git-path", "index").Output()
+	if err != nil {
+		t.Fatalf("failed to locate the worktree's index: %v", err)
+	}
+	if err := os.WriteFile(strings.TrimSpace(string(out)), []byte("not an index"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	wt := WorktreeInfo{Name: "unreadable-wt", Path: path, Branch: "unreadable-branch"}
+	manager.EnrichStatus(&wt)
+	if wt.Status != "unknown" || !strings.Contains(wt.StatusError, "git status failed") {
+		t.Fatalf("status = %q (%q), want unknown with the git status error", wt.Status, wt.StatusError)
+	}
+
+	risk := manager.AssessDeleteRisk(wt)
+	if summary := risk.Summary(); len(summary) == 0 || !strings.HasPrefix(summary[0], "uncommitted changes unknown") {
+		t.Errorf("risk summary = %q, want the unknown status first", summary)
+	}
+	if filter, err := ParseStatusFilter("unknown"); err != nil || !filter.Matches(wt) {
+		t.Error("status filter unknown doesn't match the worktree")
+	}
+}
+
+func TestEnrichStatusSubmodules(t *testing.T) {
+	dir, manager, cleanup := setupTestEnvironment(t)
+	defer cleanup()
+
+	// A gitlink plus .gitmodules records a submodule that a new worktree
+	// does not check out
+	head, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, ".gitmodules"), []byte("[submodule \"lib\"]\n\tpath = lib\n\turl = ./lib\n"), 0644)
+	runGit(t, dir, "update-index", "--add", "--cacheinfo", "160000,"+strings.TrimSpace(string(head))+",lib")
+	runGit(t, dir, "add", ".gitmodules")
+	runGit(t, dir, "commit", "-m", "add submodule")
+
+	path := filepath.Join(filepath.Dir(dir), "test-worktrees", "sub-wt")
+	runGit(t, dir, "worktree", "add", "-b", "sub-branch", path)
+
+	wt := WorktreeInfo{Name: "sub-wt", Path: path, Branch: "sub-branch"}
+	manager.EnrichStatus(&wt)
+	if !wt.HasSubmodules || wt.SubmoduleState != "1 uninitialized" {
+		t.Errorf("HasSubmodules = %v, SubmoduleState = %q; want 1 uninitialized", wt.HasSubmodules, wt.SubmoduleState)
+	}
+}
+
+func TestSummarizeSubmoduleState(t *testing.T) {
+	tests := []struct {
+		output string
+		want   string
+	}{
+		{"", ""},
+		{" 1f2e3d4c lib (v1.0)\n", ""},
+		{"+1f2e3d4c lib (v1.0-2-gabc)\n-5a6b7c8d vendor/x\n-9e8f7a6b vendor/y\n", "1 modified, 2 uninitialized"},
+		{"U0000000 lib\n 1f2e3d4c other\n", "1 conflicted"},
+	}
+	for _, tt := range tests {
+		if got := summarizeSubmoduleState(tt.output); got != tt.want {
+			t.Errorf("summarizeSubmoduleState(%q) = %q, want %q", tt.output, got, tt.want)
+		}
+	}
+}
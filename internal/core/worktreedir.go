@@ -0,0 +1,102 @@
+package core
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/langtind/gren/internal/logging"
+)
+
+// createdWorktreeDirConfigKey lists, one value per directory, the worktree
+// directories CreateWorktree had to create. Only these are ever pruned, so a
+// directory the user made (or filled with other files) is never removed.
+const createdWorktreeDirConfigKey = "gren.createdWorktreeDir"
+
+// recordCreatedWorktreeDir remembers that gren created dir to hold worktrees.
+// A failure is only logged: it just means the directory won't be pruned.
+func (wm *WorktreeManager) recordCreatedWorktreeDir(dir string) {
+	dir = filepath.Clean(dir)
+	if slices.Contains(wm.createdWorktreeDirs(), dir) {
+		return
+	}
+	cmd := exec.Command("git", "config", "--local", "--add", createdWorktreeDirConfigKey, dir)
+	if repoRoot, err := wm.getRepoRoot(); err == nil {
+		cmd.Dir = repoRoot
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logging.Warn("recordCreatedWorktreeDir: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+}
+
+// createdWorktreeDirs returns the directories recorded by
+// recordCreatedWorktreeDir.
+func (wm *WorktreeManager) createdWorktreeDirs() []string {
+	cmd := exec.Command("git", "config", "--local", "--get-all", createdWorktreeDirConfigKey)
+	if repoRoot, err := wm.getRepoRoot(); err == nil {
+		cmd.Dir = repoRoot
+	}
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	var dirs []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			dirs = append(dirs, line)
+		}
+	}
+	return dirs
+}
+
+// forgetCreatedWorktreeDir drops dir from the recorded worktree directories.
+func (wm *WorktreeManager) forgetCreatedWorktreeDir(dir string) {
+	pattern := "^" + regexp.QuoteMeta(filepath.Clean(dir)) + "$"
+	cmd := exec.Command("git", "config", "--local", "--unset-all", createdWorktreeDirConfigKey, pattern)
+	if repoRoot, err := wm.getRepoRoot(); err == nil {
+		cmd.Dir = repoRoot
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		logging.Warn("forgetCreatedWorktreeDir: %v (%s)", err, strings.TrimSpace(string(output)))
+	}
+}
+
+// LeftoverWorktreeDir returns the directory that held the deleted worktree
+// at worktreePath when gren created it and it is now empty, or "" otherwise.
+// DeleteWorktree removes such a directory itself when the project sets
+// prune_empty_worktree_dir, so the CLI uses this to suggest the setting.
+func (wm *WorktreeManager) LeftoverWorktreeDir(worktreePath string) string {
+	dir := filepath.Dir(filepath.Clean(worktreePath))
+	if !slices.Contains(wm.createdWorktreeDirs(), dir) {
+		return ""
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil || len(entries) > 0 {
+		return ""
+	}
+	return dir
+}
+
+// pruneEmptyWorktreeDir removes the directory that held the deleted worktree
+// at worktreePath when prune_empty_worktree_dir is set, gren created the
+// directory, and nothing is left in it. os.Remove refuses a non-empty
+// directory, so files that appear in the meantime are never lost.
+func (wm *WorktreeManager) pruneEmptyWorktreeDir(worktreePath string) {
+	cfg, err := wm.configManager.Load()
+	if err != nil || !cfg.PruneEmptyWorktreeDir {
+		return
+	}
+	dir := wm.LeftoverWorktreeDir(worktreePath)
+	if dir == "" {
+		return
+	}
+	if err := os.Remove(dir); err != nil {
+		logging.Warn("pruneEmptyWorktreeDir: %v", err)
+		return
+	}
+	wm.forgetCreatedWorktreeDir(dir)
+	logging.Info("Removed empty worktree directory %s", dir)
+}
@@ -0,0 +1,77 @@
+package core
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDeleteWorktreePrunesEmptyWorktreeDir(t *testing.T) {
+	tests := []struct {
+		name        string
+		prune       bool
+		preexisting bool   // The directory exists before gren creates a worktree in it
+		extraFile   string // An unrelated file left in the directory
+		wantPruned  bool
+	}{
+		{name: "empty and created by gren", prune: true, wantPruned: true},
+		{name: "unrelated file left behind", prune: true, extraFile: "notes.txt"},
+		{name: "created by the user", prune: true, preexisting: true},
+		{name: "setting off", prune: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir, manager, cleanup := setupTestEnvironment(t)
+			defer cleanup()
+
+			worktreeDir := filepath.Join(t.TempDir(), "worktrees")
+			if tt.preexisting {
+				os.Mkdir(worktreeDir, 0755)
+			}
+			prune := "false"
+			if tt.prune {
+				prune = "true"
+			}
+			cfg := `{
+				"worktree_dir": "` + worktreeDir + `",
+				"version": "1.0.0",
+				"prune_empty_worktree_dir": ` + prune + `
+			}`
+			if err := os.WriteFile(filepath.Join(dir, ".gren", "config.json"), []byte(cfg), 0644); err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := context.Background()
+			if _, _, err := manager.CreateWorktree(ctx, CreateWorktreeRequest{Name: "prune-test", IsNewBranch: true}); err != nil {
+				t.Fatalf("CreateWorktree() error = %v", err)
+			}
+			if tt.extraFile != "" {
+				os.WriteFile(filepath.Join(worktreeDir, tt.extraFile), []byte("keep me"), 0644)
+			}
+			if err := manager.DeleteWorktree(ctx, "prune-test", false); err != nil {
+				t.Fatalf("DeleteWorktree() error = %v", err)
+			}
+
+			_, err := os.Stat(worktreeDir)
+			if pruned := os.IsNotExist(err); pruned != tt.wantPruned {
+				t.Errorf("worktree_dir removed = %v, want %v", pruned, tt.wantPruned)
+			}
+			if tt.extraFile != "" {
+				if data, _ := os.ReadFile(filepath.Join(worktreeDir, tt.extraFile)); string(data) != "keep me" {
+					t.Errorf("%s = %q, want it untouched", tt.extraFile, data)
+				}
+			}
+
+			leftover := manager.LeftoverWorktreeDir(filepath.Join(worktreeDir, "prune-test"))
+			wantLeftover := ""
+			if !tt.prune && !tt.preexisting && tt.extraFile == "" {
+				wantLeftover = worktreeDir
+			}
+			if leftover != wantLeftover {
+				t.Errorf("LeftoverWorktreeDir() = %q, want %q", leftover, wantLeftover)
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@
 package directive
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -17,9 +20,95 @@ const (
 	// LegacyTempFile is the old fixed temp file path for backward compatibility.
 	// Used when GREN_DIRECTIVE_FILE is not set (old shell integration).
 	LegacyTempFile = "/tmp/gren_navigate"
+
+	// EnvDirectiveFormat selects the directive file format. Unset, it holds
+	// shell commands for the wrapper to source; FormatJSON writes one
+	// Directive per line as JSON, for integrations that want gren's intent
+	// rather than shell to eval.
+	EnvDirectiveFormat = "GREN_DIRECTIVE_FORMAT"
+
+	// FormatJSON is the EnvDirectiveFormat value for JSON lines.
+	FormatJSON = "json"
+)
+
+// Directive actions.
+const (
+	ActionCD   = "cd"   // Change to Path
+	ActionRun  = "run"  // Run Command in the shell, which stays
+	ActionExec = "exec" // Replace the shell with Command
 )
 
-// WriteDirective writes a shell directive to be executed after gren exits.
+// Directive is one thing for the shell to do after gren exits. In the JSON
+// format each is a line such as
+//
+//	{"action":"cd","path":"/home/me/app-worktrees/feat"}
+//	{"action":"run","command":"claude"}
+//
+// and the lines are carried out in order.
+type Directive struct {
+	Action  string `json:"action"`
+	Path    string `json:"path,omitempty"`
+	Command string `json:"command,omitempty"`
+}
+
+// Shell returns the shell command that carries out d.
+func (d Directive) Shell() string {
+	switch d.Action {
+	case ActionCD:
+		return fmt.Sprintf("cd %q", d.Path)
+	case ActionExec:
+		return "exec " + d.Command
+	default:
+		return d.Command
+	}
+}
+
+// Write writes directives in the format EnvDirectiveFormat selects.
+func Write(directives ...Directive) error {
+	jsonFormat := os.Getenv(EnvDirectiveFormat) == FormatJSON
+	lines := make([]string, len(directives))
+	for i, d := range directives {
+		if !jsonFormat {
+			lines[i] = d.Shell()
+			continue
+		}
+		data, err := json.Marshal(d)
+		if err != nil {
+			return err
+		}
+		lines[i] = string(data)
+	}
+	return WriteDirective(strings.Join(lines, "\n"))
+}
+
+// ParseJSON reads directives written in the JSON format, skipping blank
+// lines. An unknown action is an error, so a consumer never half-carries out
+// a newer gren's intent.
+func ParseJSON(r io.Reader) ([]Directive, error) {
+	var directives []Directive
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		var d Directive
+		if err := json.Unmarshal([]byte(line), &d); err != nil {
+			return nil, fmt.Errorf("invalid directive %q: %w", line, err)
+		}
+		switch d.Action {
+		case ActionCD, ActionRun, ActionExec:
+		default:
+			return nil, fmt.Errorf("unknown directive action %q", d.Action)
+		}
+		directives = append(directives, d)
+	}
+	return directives, scanner.Err()
+}
+
+// WriteDirective writes a raw directive to be executed after gren exits. It
+// is written as given whatever EnvDirectiveFormat says; use Write for
+// directives that honor it.
 // If GREN_DIRECTIVE_FILE is set, writes to that file.
 // Otherwise, falls back to the legacy fixed temp file for backward compatibility.
 func WriteDirective(directive string) error {
@@ -33,33 +122,25 @@ func WriteDirective(directive string) error {
 
 // WriteCD writes a cd directive to change directory after gren exits.
 func WriteCD(path string) error {
-	return WriteDirective(fmt.Sprintf("cd %q", path))
+	return Write(Directive{Action: ActionCD, Path: path})
 }
 
 // WriteExec writes an exec directive to run a command after cd.
 // The command replaces the current shell process.
 func WriteExec(command string) error {
-	return WriteDirective(fmt.Sprintf("exec %s", command))
+	return Write(Directive{Action: ActionExec, Command: command})
 }
 
 // WriteCDAndExec writes both cd and exec directives.
 // First changes to the directory, then executes the command.
 func WriteCDAndExec(path, command string) error {
-	directives := []string{
-		fmt.Sprintf("cd %q", path),
-		command,
-	}
-	return WriteDirective(strings.Join(directives, "\n"))
+	return Write(Directive{Action: ActionCD, Path: path}, Directive{Action: ActionRun, Command: command})
 }
 
 // WriteCDAndRun writes a cd directive followed by a command to run (not exec).
 // The command runs in a subshell, keeping the shell alive after.
 func WriteCDAndRun(path, command string) error {
-	directives := []string{
-		fmt.Sprintf("cd %q", path),
-		command,
-	}
-	return WriteDirective(strings.Join(directives, "\n"))
+	return Write(Directive{Action: ActionCD, Path: path}, Directive{Action: ActionRun, Command: command})
 }
 
 // IsShellIntegrationActive returns true if the shell wrapper is active.
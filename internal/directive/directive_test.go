@@ -2,6 +2,7 @@ package directive
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -243,3 +244,41 @@ func TestClear(t *testing.T) {
 		t.Errorf("Clear on non-existent file should not error: %v", err)
 	}
 }
+
+func TestWriteJSONFormat(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "directive")
+	t.Setenv(EnvDirectiveFile, file)
+	t.Setenv(EnvDirectiveFormat, FormatJSON)
+
+	if err := WriteCDAndRun("/path/with spaces", "claude"); err != nil {
+		t.Fatalf("WriteCDAndRun failed: %v", err)
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"action":"cd","path":"/path/with spaces"}` + "\n" + `{"action":"run","command":"claude"}` + "\n"
+	if string(content) != want {
+		t.Errorf("content = %q, want %q", content, want)
+	}
+
+	directives, err := ParseJSON(strings.NewReader(string(content)))
+	if err != nil {
+		t.Fatalf("ParseJSON failed: %v", err)
+	}
+	var shell []string
+	for _, d := range directives {
+		shell = append(shell, d.Shell())
+	}
+	if got := strings.Join(shell, "\n"); got != `cd "/path/with spaces"`+"\nclaude" {
+		t.Errorf("shell = %q, want what the shell format writes", got)
+	}
+}
+
+func TestParseJSONRejectsUnknownAction(t *testing.T) {
+	for _, input := range []string{`{"action":"teleport"}`, `cd "/tmp"`} {
+		if _, err := ParseJSON(strings.NewReader(input)); err == nil {
+			t.Errorf("ParseJSON(%q) should fail", input)
+		}
+	}
+}
@@ -141,10 +141,14 @@ func (r *LocalRepository) getWorkingDirectoryStatus(ctx context.Context) (uncomm
 	return uncommitted, untracked, nil
 }
 
-// getAheadBehindCount returns how many commits the branch is ahead/behind its upstream.
+// getAheadBehindCount returns how many commits the branch is ahead/behind its
+// upstream. A branch without an upstream is compared to origin/<branch>.
 func (r *LocalRepository) getAheadBehindCount(ctx context.Context, branch string) (ahead, behind int, err error) {
-	// Try to get the upstream branch
-	cmd := exec.CommandContext(ctx, "git", "rev-list", "--left-right", "--count", branch+"...origin/"+branch)
+	upstream := branch + "@{upstream}"
+	if exec.CommandContext(ctx, "git", "rev-parse", "--verify", "--quiet", upstream).Run() != nil {
+		upstream = "origin/" + branch
+	}
+	cmd := exec.CommandContext(ctx, "git", "rev-list", "--left-right", "--count", branch+"..."+upstream)
 	output, err := cmd.Output()
 	if err != nil {
 		// No upstream or other error, return 0,0
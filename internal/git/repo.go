@@ -175,13 +175,20 @@ func getCurrentDirectory() (string, error) {
 }
 
 // isInitialized checks if gren has been initialized in this repo.
+// The config normally lives in the main worktree, the parent of the shared git
+// dir, so a linked worktree without its own .gren still counts as initialized.
 func isInitialized() bool {
-	cmd := exec.Command("git", "rev-parse", "--show-toplevel")
-	output, err := cmd.Output()
-	if err != nil {
-		return false
+	var roots []string
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		roots = append(roots, strings.TrimSpace(string(output)))
+	}
+	if output, err := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-common-dir").Output(); err == nil {
+		roots = append(roots, filepath.Dir(filepath.Clean(strings.TrimSpace(string(output)))))
+	}
+	for _, root := range roots {
+		if _, err := os.Stat(filepath.Join(root, ".gren")); err == nil {
+			return true
+		}
 	}
-	repoRoot := strings.TrimSpace(string(output))
-	_, err = os.Stat(filepath.Join(repoRoot, ".gren"))
-	return err == nil
+	return false
 }
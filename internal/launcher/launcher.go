@@ -0,0 +1,158 @@
+// Package launcher picks the terminal and editor used to open worktrees and
+// files, from config first and the environment second. It is shared by the
+// TUI's "Open in..." actions and `gren open`.
+package launcher
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"github.com/langtind/gren/internal/config"
+)
+
+// pathPlaceholder matches {{path}} in a terminal_command template, with or
+// without inner spaces.
+var pathPlaceholder = regexp.MustCompile(`\{\{\s*path\s*\}\}`)
+
+// linuxTerminals are tried in order when no terminal is configured or
+// detected from the environment. Each entry knows how to start in a directory.
+var linuxTerminals = []struct {
+	name string
+	args func(path string) []string
+}{
+	{"gnome-terminal", func(p string) []string { return []string{"--working-directory=" + p} }},
+	{"konsole", func(p string) []string { return []string{"--workdir", p} }},
+	{"kitty", func(p string) []string { return []string{"--directory", p} }},
+	{"alacritty", func(p string) []string { return []string{"--working-directory", p} }},
+	{"wezterm", func(p string) []string { return []string{"start", "--cwd", p} }},
+	{"xfce4-terminal", func(p string) []string { return []string{"--working-directory=" + p} }},
+	{"foot", func(p string) []string { return []string{"--working-directory=" + p} }},
+}
+
+// terminalEditors run inside the terminal, so the caller has to hand the
+// terminal over while they are open.
+var terminalEditors = map[string]bool{
+	"vim": true, "nvim": true, "vi": true, "nano": true, "emacs": true, "helix": true, "hx": true,
+}
+
+// Settings returns the configured terminal command and editor. The project
+// config wins; the user config's defaults fill in what it leaves empty.
+func Settings(project *config.Config) (terminal, editor string) {
+	if project != nil {
+		terminal, editor = project.TerminalCommand, project.Editor
+	}
+	if terminal == "" || editor == "" {
+		if userCfg, err := config.NewUserConfigManager().Load(); err == nil {
+			if terminal == "" {
+				terminal = userCfg.Defaults.TerminalCommand
+			}
+			if editor == "" {
+				editor = userCfg.Defaults.Editor
+			}
+		}
+	}
+	return terminal, editor
+}
+
+// Terminal returns the command that opens a terminal in worktreePath.
+// A configured template is run through sh; "tmux" and "tmux-split" open a
+// tmux window or pane. With nothing configured the terminal is detected from
+// the environment, then from the common Linux terminals on PATH.
+func Terminal(template, worktreePath string) (string, []string) {
+	switch template {
+	case "":
+	case "tmux":
+		return "tmux", []string{"new-window", "-c", worktreePath}
+	case "tmux-split":
+		return "tmux", []string{"split-window", "-c", worktreePath}
+	default:
+		return "sh", []string{"-c", expandTemplate(template, worktreePath)}
+	}
+
+	switch os.Getenv("TERM_PROGRAM") {
+	case "WarpTerminal":
+		return "warp-cli", []string{"open", worktreePath}
+	case "iTerm.app":
+		return "osascript", []string{"-e", fmt.Sprintf(`tell application "iTerm"
+				create window with default profile
+				tell current session of current window
+					write text "cd %s"
+				end tell
+			end tell`, worktreePath)}
+	case "Apple_Terminal":
+		return "osascript", []string{"-e", fmt.Sprintf(`tell application "Terminal"
+				do script "cd %s"
+			end tell`, worktreePath)}
+	case "tmux":
+		return "tmux", []string{"new-window", "-c", worktreePath}
+	case "WezTerm":
+		return "wezterm", []string{"start", "--cwd", worktreePath}
+	}
+
+	if os.Getenv("KITTY_WINDOW_ID") != "" {
+		return "kitty", []string{"--directory", worktreePath}
+	}
+	if os.Getenv("ALACRITTY_WINDOW_ID") != "" {
+		return "alacritty", []string{"--working-directory", worktreePath}
+	}
+
+	if runtime.GOOS == "darwin" {
+		// Fallback: just open the directory
+		return "open", []string{worktreePath}
+	}
+	for _, t := range linuxTerminals {
+		if isCommandAvailable(t.name) {
+			return t.name, t.args(worktreePath)
+		}
+	}
+	return "xdg-open", []string{worktreePath}
+}
+
+// expandTemplate substitutes the quoted worktree path for {{path}}. The
+// command runs from the worktree, so templates that don't take a directory
+// argument still start there.
+func expandTemplate(template, worktreePath string) string {
+	quoted := shellQuote(worktreePath)
+	return "cd " + quoted + " && " + pathPlaceholder.ReplaceAllLiteralString(template, quoted)
+}
+
+// shellQuote wraps s in single quotes for sh.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// Editor picks the editor command: the configured editor, then $EDITOR and
+// $VISUAL, then the first common editor on PATH. The result is split into
+// fields so values like "code --wait" work. nil means none was found.
+func Editor(configured string) []string {
+	for _, editor := range []string{configured, os.Getenv("EDITOR"), os.Getenv("VISUAL")} {
+		if fields := strings.Fields(editor); len(fields) > 0 {
+			return fields
+		}
+	}
+
+	fallbackEditors := []string{"code", "zed", "vim", "nano"}
+	for _, e := range fallbackEditors {
+		if isCommandAvailable(e) {
+			return []string{e}
+		}
+	}
+	return nil
+}
+
+// IsTerminalEditor reports whether command (possibly a path) is an editor
+// that runs inside the terminal rather than opening its own window.
+func IsTerminalEditor(command string) bool {
+	return terminalEditors[filepath.Base(command)]
+}
+
+// isCommandAvailable checks if a command is available in PATH
+func isCommandAvailable(command string) bool {
+	_, err := exec.LookPath(command)
+	return err == nil
+}
@@ -0,0 +1,81 @@
+package launcher
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTerminalConfigured(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		wantCmd  string
+		wantArgs []string
+	}{
+		{
+			name:     "tmux window",
+			template: "tmux",
+			wantCmd:  "tmux",
+			wantArgs: []string{"new-window", "-c", "/tmp/wt"},
+		},
+		{
+			name:     "tmux pane",
+			template: "tmux-split",
+			wantCmd:  "tmux",
+			wantArgs: []string{"split-window", "-c", "/tmp/wt"},
+		},
+		{
+			name:     "template",
+			template: "kitty --directory {{path}}",
+			wantCmd:  "sh",
+			wantArgs: []string{"-c", "cd '/tmp/wt' && kitty --directory '/tmp/wt'"},
+		},
+		{
+			name:     "template with spaced placeholder",
+			template: "alacritty --working-directory {{ path }}",
+			wantCmd:  "sh",
+			wantArgs: []string{"-c", "cd '/tmp/wt' && alacritty --working-directory '/tmp/wt'"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd, args := Terminal(tt.template, "/tmp/wt")
+			if cmd != tt.wantCmd {
+				t.Errorf("command = %q, want %q", cmd, tt.wantCmd)
+			}
+			if !reflect.DeepEqual(args, tt.wantArgs) {
+				t.Errorf("args = %q, want %q", args, tt.wantArgs)
+			}
+		})
+	}
+}
+
+func TestTerminalDetectsTmux(t *testing.T) {
+	t.Setenv("TERM_PROGRAM", "tmux")
+
+	cmd, args := Terminal("", "/tmp/wt")
+	if cmd != "tmux" || !reflect.DeepEqual(args, []string{"new-window", "-c", "/tmp/wt"}) {
+		t.Errorf("got %q %q, want tmux new-window", cmd, args)
+	}
+}
+
+func TestExpandTemplateQuotesPath(t *testing.T) {
+	got := expandTemplate("wezterm start --cwd {{path}}", "/tmp/it's here")
+	want := `cd '/tmp/it'\''s here' && wezterm start --cwd '/tmp/it'\''s here'`
+	if got != want {
+		t.Errorf("expandTemplate() = %q, want %q", got, want)
+	}
+}
+
+func TestEditor(t *testing.T) {
+	t.Setenv("EDITOR", "nvim")
+	t.Setenv("VISUAL", "")
+
+	if got := Editor("code --wait"); !reflect.DeepEqual(got, []string{"code", "--wait"}) {
+		t.Errorf("configured editor: got %q, want [code --wait]", got)
+	}
+	if got := Editor(""); !reflect.DeepEqual(got, []string{"nvim"}) {
+		t.Errorf("EDITOR fallback: got %q, want [nvim]", got)
+	}
+}
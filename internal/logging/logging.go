@@ -15,8 +15,25 @@ var (
 	logFile *os.File
 	logPath string
 	enabled bool
+	level   = LevelDebug
 )
 
+// Level is the minimum severity a message needs to be written.
+type Level int
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+// SetLevel sets the minimum severity written to the log. Everything is
+// written by default; `gren --quiet` raises it to LevelError.
+func SetLevel(l Level) {
+	level = l
+}
+
 const (
 	maxLogBytes = 5 * 1024 * 1024 // rotate gren.log past 5 MiB
 	logBackups  = 3               // keep gren.log.1 .. .3
@@ -39,12 +56,17 @@ func rotateIfLarge(path string, maxBytes int64, backups int) {
 
 // Init initializes the logger with the default log path for the OS
 func Init() error {
-	logDir := getLogDir()
-	if err := os.MkdirAll(logDir, 0755); err != nil {
+	return InitPath(filepath.Join(getLogDir(), "gren.log"))
+}
+
+// InitPath initializes the logger to append to path, creating its directory
+// if needed. Used for `gren --log-file`.
+func InitPath(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
 		return fmt.Errorf("failed to create log directory: %w", err)
 	}
 
-	logPath = filepath.Join(logDir, "gren.log")
+	logPath = path
 	rotateIfLarge(logPath, maxLogBytes, logBackups)
 
 	// Open log file in append mode
@@ -56,9 +78,13 @@ func Init() error {
 
 	logger = log.New(logFile, "", 0)
 	enabled = true
+	envErr := configureFromEnv()
 
 	// Log startup
 	Info("gren started")
+	if envErr != nil {
+		Warn("%v", envErr)
+	}
 
 	return nil
 }
@@ -108,32 +134,45 @@ func formatMessage(level, format string, args ...interface{}) string {
 	return fmt.Sprintf("[%s] [%s] %s", timestamp, level, message)
 }
 
+// write logs a message at l, in the format EnvLogFormat selects, if l is
+// at least the SetLevel floor and the EnvLog level of the calling package.
+func write(l Level, format string, args ...interface{}) {
+	if !enabled || logger == nil || l < level {
+		return
+	}
+	// Finding the caller costs a stack walk, so only when it is used
+	var component, caller string
+	if jsonFormat || len(componentLevels) > 0 {
+		component, caller = callerInfo(2)
+		if !componentAllows(component, l) {
+			return
+		}
+	}
+	if jsonFormat {
+		logger.Println(formatJSON(l, component, caller, format, args...))
+		return
+	}
+	logger.Println(formatMessage(l.String(), format, args...))
+}
+
 // Debug logs a debug message
 func Debug(format string, args ...interface{}) {
-	if enabled && logger != nil {
-		logger.Println(formatMessage("DEBUG", format, args...))
-	}
+	write(LevelDebug, format, args...)
 }
 
 // Info logs an info message
 func Info(format string, args ...interface{}) {
-	if enabled && logger != nil {
-		logger.Println(formatMessage("INFO", format, args...))
-	}
+	write(LevelInfo, format, args...)
 }
 
 // Warn logs a warning message
 func Warn(format string, args ...interface{}) {
-	if enabled && logger != nil {
-		logger.Println(formatMessage("WARN", format, args...))
-	}
+	write(LevelWarn, format, args...)
 }
 
 // Error logs an error message
 func Error(format string, args ...interface{}) {
-	if enabled && logger != nil {
-		logger.Println(formatMessage("ERROR", format, args...))
-	}
+	write(LevelError, format, args...)
 }
 
 // LogPanic records a recovered panic (value + stack) to disk, best-effort.
@@ -2,6 +2,7 @@ package logging
 
 import (
 	"bytes"
+	"encoding/json"
 	"log"
 	"os"
 	"path/filepath"
@@ -370,3 +371,195 @@ func TestLogTerminationWritesSignalLine(t *testing.T) {
 		t.Errorf("log missing termination line, got: %s", data)
 	}
 }
+
+func TestSetLevelFiltersMessages(t *testing.T) {
+	origLogger, origLogFile, origEnabled, origLevel := logger, logFile, enabled, level
+	defer func() {
+		logger, logFile, enabled, level = origLogger, origLogFile, origEnabled, origLevel
+	}()
+
+	var buf bytes.Buffer
+	logger = log.New(&buf, "", 0)
+	enabled = true
+
+	SetLevel(LevelError)
+	Debug("debug message")
+	Info("info message")
+	Warn("warn message")
+	Error("error message")
+
+	got := buf.String()
+	for _, msg := range []string{"debug message", "info message", "warn message"} {
+		if strings.Contains(got, msg) {
+			t.Errorf("LevelError log contains %q: %s", msg, got)
+		}
+	}
+	if !strings.Contains(got, "error message") {
+		t.Errorf("LevelError log should contain the error, got: %s", got)
+	}
+}
+
+func TestInitPath(t *testing.T) {
+	origLogger, origLogFile, origLogPath, origEnabled := logger, logFile, logPath, enabled
+	defer func() {
+		logger, logFile, logPath, enabled = origLogger, origLogFile, origLogPath, origEnabled
+	}()
+
+	path := filepath.Join(t.TempDir(), "nested", "custom.log")
+	if err := InitPath(path); err != nil {
+		t.Fatalf("InitPath() error: %v", err)
+	}
+	Close()
+
+	if GetLogPath() != path {
+		t.Errorf("GetLogPath() = %q, want %q", GetLogPath(), path)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("log file not created: %v", err)
+	}
+	if !strings.Contains(string(data), "gren started") {
+		t.Errorf("log = %q, want startup line", data)
+	}
+}
+
+func TestParseLevelSpec(t *testing.T) {
+	levels, err := ParseLevelSpec("warn, core=debug,ui=INFO")
+	if err != nil {
+		t.Fatalf("ParseLevelSpec() error: %v", err)
+	}
+	want := map[string]Level{"": LevelWarn, "core": LevelDebug, "ui": LevelInfo}
+	if len(levels) != len(want) {
+		t.Fatalf("ParseLevelSpec() = %v, want %v", levels, want)
+	}
+	for component, l := range want {
+		if levels[component] != l {
+			t.Errorf("level of %q = %v, want %v", component, levels[component], l)
+		}
+	}
+
+	levels, err = ParseLevelSpec("core=loud,=debug,cli=error")
+	if err == nil || !strings.Contains(err.Error(), "core=loud") || !strings.Contains(err.Error(), "=debug") {
+		t.Errorf("ParseLevelSpec() error = %v, want both invalid entries named", err)
+	}
+	if len(levels) != 1 || levels["cli"] != LevelError {
+		t.Errorf("ParseLevelSpec() = %v, want the valid cli entry kept", levels)
+	}
+}
+
+func TestComponentLevelsFilterByPackage(t *testing.T) {
+	origLogger, origEnabled, origLevel, origLevels := logger, enabled, level, componentLevels
+	defer func() {
+		logger, enabled, level, componentLevels = origLogger, origEnabled, origLevel, origLevels
+	}()
+
+	var buf bytes.Buffer
+	logger = log.New(&buf, "", 0)
+	enabled = true
+	level = LevelDebug
+
+	// This test runs in package logging
+	componentLevels = map[string]Level{"": LevelError, "logging": LevelDebug}
+	Debug("own debug")
+	componentLevels = map[string]Level{"": LevelDebug, "logging": LevelWarn}
+	Info("own info")
+	Warn("own warn")
+
+	got := buf.String()
+	if !strings.Contains(got, "own debug") || !strings.Contains(got, "own warn") {
+		t.Errorf("log missing messages the component level allows: %s", got)
+	}
+	if strings.Contains(got, "own info") {
+		t.Errorf("log contains info below the component's warn level: %s", got)
+	}
+	if !strings.Contains(got, "[DEBUG] own debug") {
+		t.Errorf("component levels should keep the human format, got: %s", got)
+	}
+}
+
+func TestSetLevelFloorBeatsComponentLevel(t *testing.T) {
+	origLogger, origEnabled, origLevel, origLevels := logger, enabled, level, componentLevels
+	defer func() {
+		logger, enabled, level, componentLevels = origLogger, origEnabled, origLevel, origLevels
+	}()
+
+	var buf bytes.Buffer
+	logger = log.New(&buf, "", 0)
+	enabled = true
+	level = LevelError
+	componentLevels = map[string]Level{"logging": LevelDebug}
+
+	Debug("debug message")
+	if buf.Len() != 0 {
+		t.Errorf("SetLevel(LevelError) should win over a debug component level, got: %s", buf.String())
+	}
+}
+
+func TestJSONFormat(t *testing.T) {
+	origLogger, origEnabled, origLevel, origJSON := logger, enabled, level, jsonFormat
+	defer func() {
+		logger, enabled, level, jsonFormat = origLogger, origEnabled, origLevel, origJSON
+	}()
+
+	var buf bytes.Buffer
+	logger = log.New(&buf, "", 0)
+	enabled = true
+	level = LevelDebug
+	jsonFormat = true
+
+	Warn("disk %s", "full")
+
+	var line map[string]string
+	if err := json.Unmarshal(bytes.TrimSpace(buf.Bytes()), &line); err != nil {
+		t.Fatalf("log line is not JSON: %v: %s", err, buf.String())
+	}
+	if line["level"] != "warn" || line["msg"] != "disk full" || line["component"] != "logging" {
+		t.Errorf("JSON line = %v, want level warn, msg \"disk full\", component logging", line)
+	}
+	if !strings.HasPrefix(line["caller"], "logging_test.go:") {
+		t.Errorf("caller = %q, want this test file", line["caller"])
+	}
+	if len(line["time"]) < len("2006-01-02T15:04:05.000Z") || !strings.Contains(line["time"], "T") {
+		t.Errorf("time = %q, want RFC 3339 with milliseconds", line["time"])
+	}
+
+	level, msg, ok := ParseJSONLine(strings.TrimSpace(buf.String()))
+	if !ok || level != "warn" || msg != "disk full" {
+		t.Errorf("ParseJSONLine() = %q, %q, %v", level, msg, ok)
+	}
+	if _, _, ok := ParseJSONLine("[2026-07-07 10:00:00.000] [INFO] started"); ok {
+		t.Error("ParseJSONLine() accepted a human-format line")
+	}
+}
+
+func TestInitPathReadsEnv(t *testing.T) {
+	origLogger, origLogFile, origLogPath, origEnabled := logger, logFile, logPath, enabled
+	origJSON, origLevels := jsonFormat, componentLevels
+	defer func() {
+		logger, logFile, logPath, enabled = origLogger, origLogFile, origLogPath, origEnabled
+		jsonFormat, componentLevels = origJSON, origLevels
+	}()
+	t.Setenv(EnvLogFormat, "json")
+	t.Setenv(EnvLog, "core=debug,bogus")
+
+	path := filepath.Join(t.TempDir(), "gren.log")
+	if err := InitPath(path); err != nil {
+		t.Fatalf("InitPath() error: %v", err)
+	}
+	Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("log file not created: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) < 2 {
+		t.Fatalf("log = %q, want startup line and invalid-spec warning", data)
+	}
+	if _, msg, ok := ParseJSONLine(lines[0]); !ok || msg != "gren started" {
+		t.Errorf("first line = %q, want JSON startup line", lines[0])
+	}
+	if level, msg, ok := ParseJSONLine(lines[1]); !ok || level != "warn" || !strings.Contains(msg, "bogus") {
+		t.Errorf("second line = %q, want a warning naming the bad entry", lines[1])
+	}
+}
@@ -0,0 +1,157 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvLogFormat selects the log line format. Unset, lines are
+	// "[time] [LEVEL] message"; "json" writes one JSON object per line with
+	// time, level, component, caller and msg, for filtering with jq.
+	EnvLogFormat = "GREN_LOG_FORMAT"
+
+	// EnvLog sets log levels per component (the Go package that logged:
+	// core, cli, ui, git, config, ...), e.g. "core=debug,ui=info". A bare
+	// level such as "warn" applies to every component not named. SetLevel
+	// still sets a floor: with --quiet only errors are written.
+	EnvLog = "GREN_LOG"
+)
+
+var (
+	jsonFormat bool
+	// componentLevels holds the levels from EnvLog by component; the ""
+	// entry is the level for components not named.
+	componentLevels map[string]Level
+)
+
+// levelNames are the names ParseLevelSpec accepts and JSON lines use.
+var levelNames = map[string]Level{
+	"debug":   LevelDebug,
+	"info":    LevelInfo,
+	"warn":    LevelWarn,
+	"warning": LevelWarn,
+	"error":   LevelError,
+}
+
+// String returns the level's name in upper case, as the human format
+// writes it.
+func (l Level) String() string {
+	switch l {
+	case LevelDebug:
+		return "DEBUG"
+	case LevelInfo:
+		return "INFO"
+	case LevelWarn:
+		return "WARN"
+	default:
+		return "ERROR"
+	}
+}
+
+// ParseLevelSpec parses an EnvLog value such as "warn,core=debug,ui=info"
+// into levels by component, with "" for a bare level. Invalid entries are
+// skipped and reported together in the error; the valid ones are still
+// returned.
+func ParseLevelSpec(spec string) (map[string]Level, error) {
+	levels := make(map[string]Level)
+	var bad []string
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		component, name, found := strings.Cut(entry, "=")
+		if !found {
+			component, name = "", entry
+		}
+		l, ok := levelNames[strings.ToLower(strings.TrimSpace(name))]
+		if !ok || (found && strings.TrimSpace(component) == "") {
+			bad = append(bad, entry)
+			continue
+		}
+		levels[strings.TrimSpace(component)] = l
+	}
+	if len(bad) > 0 {
+		return levels, fmt.Errorf("invalid %s entries %q (want level or component=level; levels: debug, info, warn, error)", EnvLog, bad)
+	}
+	return levels, nil
+}
+
+// configureFromEnv reads EnvLogFormat and EnvLog. It returns the EnvLog
+// parse error, for Init to log once the log is open.
+func configureFromEnv() error {
+	jsonFormat = strings.EqualFold(strings.TrimSpace(os.Getenv(EnvLogFormat)), "json")
+	var err error
+	componentLevels, err = ParseLevelSpec(os.Getenv(EnvLog))
+	return err
+}
+
+// componentAllows reports whether a message at l from component passes the
+// EnvLog levels. Without an entry for it or a bare level, everything does.
+func componentAllows(component string, l Level) bool {
+	min, ok := componentLevels[component]
+	if !ok {
+		min, ok = componentLevels[""]
+	}
+	return !ok || l >= min
+}
+
+// callerInfo returns the component (package name) and file:line of the
+// function skip frames above its caller.
+func callerInfo(skip int) (component, caller string) {
+	pc, file, line, ok := runtime.Caller(skip + 1)
+	if !ok {
+		return "", ""
+	}
+	caller = fmt.Sprintf("%s:%d", filepath.Base(file), line)
+	if fn := runtime.FuncForPC(pc); fn != nil {
+		// github.com/langtind/gren/internal/core.(*WorktreeManager).List → core
+		name := fn.Name()
+		name = name[strings.LastIndex(name, "/")+1:]
+		component, _, _ = strings.Cut(name, ".")
+	}
+	return component, caller
+}
+
+// jsonLine is one log line in the JSON format.
+type jsonLine struct {
+	Time      string `json:"time"`
+	Level     string `json:"level"`
+	Component string `json:"component,omitempty"`
+	Caller    string `json:"caller,omitempty"`
+	Msg       string `json:"msg"`
+}
+
+// formatJSON formats a log message as a JSON line.
+func formatJSON(l Level, component, caller, format string, args ...interface{}) string {
+	data, err := json.Marshal(jsonLine{
+		Time:      time.Now().Format("2006-01-02T15:04:05.000Z07:00"),
+		Level:     strings.ToLower(l.String()),
+		Component: component,
+		Caller:    caller,
+		Msg:       fmt.Sprintf(format, args...),
+	})
+	if err != nil {
+		return formatMessage(l.String(), format, args...)
+	}
+	return string(data)
+}
+
+// ParseJSONLine decodes a line written in the JSON format, returning its
+// lower-case level and message; ok is false for any other line.
+func ParseJSONLine(line string) (level, msg string, ok bool) {
+	if !strings.HasPrefix(line, "{") {
+		return "", "", false
+	}
+	var l jsonLine
+	if json.Unmarshal([]byte(line), &l) != nil || l.Level == "" {
+		return "", "", false
+	}
+	return l.Level, l.Msg, true
+}
@@ -0,0 +1,102 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DiffKind classifies one line of unified diff output so the CLI and the TUI
+// compare view color diffs the same way, each with its own styles.
+type DiffKind int
+
+const (
+	DiffContext   DiffKind = iota // Unchanged line (or anything unrecognized)
+	DiffAdded                     // "+line"
+	DiffRemoved                   // "-line"
+	DiffHunk                      // "@@ -1,2 +1,3 @@"
+	DiffMeta                      // "diff --git", "index", "---"/"+++" file headers, "[binary]"
+	DiffNoNewline                 // "\ No newline at end of file"
+)
+
+// MaxDiffLines caps how many lines of a single file's diff are rendered. A
+// regenerated lockfile or bundled asset can produce tens of thousands of lines
+// that nobody reads and that make the view sluggish.
+const MaxDiffLines = 2000
+
+// BinaryDiffMarker replaces git's "Binary files a and b differ" notice.
+const BinaryDiffMarker = "[binary]"
+
+// ClassifyDiffLine reports what kind of diff line line is. File headers are
+// checked before added/removed lines because "+++"/"---" would otherwise be
+// mistaken for content; they are matched with their a/, b/ or /dev/null path
+// so a removed "-- comment" line is still shown as a removal.
+func ClassifyDiffLine(line string) DiffKind {
+	switch {
+	case strings.HasPrefix(line, "--- a/"), strings.HasPrefix(line, "+++ b/"),
+		strings.HasPrefix(line, "--- /dev/null"), strings.HasPrefix(line, "+++ /dev/null"),
+		strings.HasPrefix(line, "diff "), strings.HasPrefix(line, "index "),
+		strings.HasPrefix(line, "new file"), strings.HasPrefix(line, "deleted file"),
+		line == BinaryDiffMarker:
+		return DiffMeta
+	case strings.HasPrefix(line, "@@"):
+		return DiffHunk
+	case strings.HasPrefix(line, `\ `):
+		return DiffNoNewline
+	case strings.HasPrefix(line, "+"):
+		return DiffAdded
+	case strings.HasPrefix(line, "-"):
+		return DiffRemoved
+	default:
+		return DiffContext
+	}
+}
+
+// PrepareDiff splits raw `git diff` output into display lines. Git's binary
+// notice collapses to BinaryDiffMarker, and output longer than maxLines is cut
+// with a trailing notice saying how much was left out. maxLines <= 0 disables
+// truncation.
+func PrepareDiff(raw string, maxLines int) []string {
+	lines := strings.Split(strings.TrimRight(raw, "\n"), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "Binary files ") && strings.HasSuffix(line, " differ") {
+			lines[i] = BinaryDiffMarker
+		}
+	}
+	if maxLines > 0 && len(lines) > maxLines {
+		total := len(lines)
+		lines = append(lines[:maxLines:maxLines], fmt.Sprintf("… diff truncated: showing %d of %d lines", maxLines, total))
+	}
+	return lines
+}
+
+// IsBinary reports whether data looks like binary content, using git's own
+// heuristic: a NUL byte in the first 8000 bytes.
+func IsBinary(data []byte) bool {
+	if len(data) > 8000 {
+		data = data[:8000]
+	}
+	for _, b := range data {
+		if b == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// DiffLine colors one line of unified diff output for the terminal.
+func DiffLine(line string) string {
+	switch ClassifyDiffLine(line) {
+	case DiffAdded:
+		return greenStyle.Render(line)
+	case DiffRemoved:
+		return redStyle.Render(line)
+	case DiffHunk:
+		return cyanStyle.Render(line)
+	case DiffMeta:
+		return boldStyle.Render(line)
+	case DiffNoNewline:
+		return dimStyle.Render(line)
+	default:
+		return line
+	}
+}
@@ -0,0 +1,76 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestClassifyDiffLine(t *testing.T) {
+	tests := []struct {
+		line string
+		want DiffKind
+	}{
+		{"diff --git a/x b/x", DiffMeta},
+		{"index 123..456 100644", DiffMeta},
+		{"--- a/x", DiffMeta},
+		{"+++ b/x", DiffMeta},
+		{"+++ /dev/null", DiffMeta},
+		{"[binary]", DiffMeta},
+		{"@@ -1,2 +1,3 @@ func main()", DiffHunk},
+		{"+added", DiffAdded},
+		{"-removed", DiffRemoved},
+		{"--- removed sql comment", DiffRemoved},
+		{`\ No newline at end of file`, DiffNoNewline},
+		{" context", DiffContext},
+		{"", DiffContext},
+	}
+	for _, tt := range tests {
+		if got := ClassifyDiffLine(tt.line); got != tt.want {
+			t.Errorf("ClassifyDiffLine(%q) = %v, want %v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestPrepareDiff(t *testing.T) {
+	t.Run("collapses binary notice", func(t *testing.T) {
+		lines := PrepareDiff("diff --git a/img.png b/img.png\nBinary files a/img.png and b/img.png differ\n", 0)
+		if lines[len(lines)-1] != BinaryDiffMarker {
+			t.Errorf("last line = %q, want %q", lines[len(lines)-1], BinaryDiffMarker)
+		}
+	})
+
+	t.Run("truncates long diffs with a notice", func(t *testing.T) {
+		raw := strings.Repeat("+line\n", 50)
+		lines := PrepareDiff(raw, 10)
+		if len(lines) != 11 {
+			t.Fatalf("len(lines) = %d, want 11 (10 + notice)", len(lines))
+		}
+		if !strings.Contains(lines[10], "10 of 50") {
+			t.Errorf("truncation notice = %q, want it to mention 10 of 50", lines[10])
+		}
+	})
+
+	t.Run("leaves short diffs alone", func(t *testing.T) {
+		lines := PrepareDiff("+a\n-b\n", 10)
+		if len(lines) != 2 {
+			t.Errorf("len(lines) = %d, want 2", len(lines))
+		}
+	})
+}
+
+func TestIsBinary(t *testing.T) {
+	if IsBinary([]byte("plain text\n")) {
+		t.Error("IsBinary(text) = true, want false")
+	}
+	if !IsBinary([]byte{'P', 'N', 'G', 0, 1}) {
+		t.Error("IsBinary(data with NUL) = false, want true")
+	}
+}
+
+func TestDiffLineKeepsContent(t *testing.T) {
+	for _, line := range []string{"+a", "-b", "@@ -1 +1 @@", " c", `\ No newline at end of file`} {
+		if !strings.Contains(DiffLine(line), line) {
+			t.Errorf("DiffLine(%q) lost the line content", line)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
 )
 
 // stdout is where human-facing output goes. Every function in this package
@@ -252,10 +253,15 @@ type WorktreeListItem struct {
 	Path      string
 	IsCurrent bool
 	IsMain    bool
+	IsBare    bool // The bare repository of a bare clone, which has no branch checked out
 	StaleInfo string
 	PRInfo    string
+	PRState   string // OPEN, DRAFT, MERGED or CLOSED; colors PRInfo in the simple list
 	CIStatus  string
 	Status    string
+	Size      string // Disk usage, e.g. "1.2 GB"; "" when not measured
+	Created   string // Creation date, e.g. "2026-03-14"; "" when not shown
+	NoRemote  bool   // The repository has no remote; shown as "local only" instead of a push state
 }
 
 // PrintWorktreeList prints a nicely formatted worktree list
@@ -270,24 +276,39 @@ func PrintWorktreeList(items []WorktreeListItem, repoName string) {
 
 		// Build the main line
 		name := item.Name
-		if item.IsMain {
+		if item.IsBare {
+			name = boldStyle.Render(name) + dimStyle.Render(" (bare repository)")
+		} else if item.IsMain {
 			name = boldStyle.Render(name) + dimStyle.Render(" (main)")
 		} else {
 			name = boldStyle.Render(name)
 		}
 
 		// Add branch if different from name
-		if item.Branch != item.Name && item.Branch != "" {
+		if item.Branch != item.Name && item.Branch != "" && !item.IsBare {
 			name += " " + dimStyle.Render("on") + " " + cyanStyle.Render(item.Branch)
 		}
 
+		if item.Size != "" {
+			name += " " + dimStyle.Render(item.Size)
+		}
+		if item.Created != "" {
+			name += " " + dimStyle.Render("created "+item.Created)
+		}
+
 		// Add status indicators
 		var indicators []string
 
-		if item.Status != "" && item.Status != "clean" {
+		if item.Status == "unknown" {
+			indicators = append(indicators, yellowStyle.Render("status unknown"))
+		} else if item.Status != "" && item.Status != "clean" {
 			indicators = append(indicators, yellowStyle.Render(item.Status))
 		}
 
+		if item.NoRemote && !item.IsBare {
+			indicators = append(indicators, dimStyle.Render("local only"))
+		}
+
 		if item.StaleInfo != "" {
 			indicators = append(indicators, dimStyle.Render("stale: "+item.StaleInfo))
 		}
@@ -296,19 +317,8 @@ func PrintWorktreeList(items []WorktreeListItem, repoName string) {
 			indicators = append(indicators, cyanStyle.Render(item.PRInfo))
 		}
 
-		if item.CIStatus != "" {
-			ciIcon := ""
-			switch item.CIStatus {
-			case "success":
-				ciIcon = greenStyle.Render("●")
-			case "failure":
-				ciIcon = redStyle.Render("●")
-			case "pending":
-				ciIcon = yellowStyle.Render("●")
-			}
-			if ciIcon != "" {
-				indicators = append(indicators, ciIcon)
-			}
+		if badge := ciBadge(item.CIStatus); badge != "" {
+			indicators = append(indicators, badge)
 		}
 
 		indicatorStr := ""
@@ -325,8 +335,31 @@ func PrintWorktreeList(items []WorktreeListItem, repoName string) {
 	}
 }
 
-// PrintSimpleWorktreeList prints a simple worktree list (for non-verbose output)
+// ciBadge renders a CI status as a colored label for the verbose list, or
+// "" when the status is unknown.
+func ciBadge(status string) string {
+	switch status {
+	case "success":
+		return greenStyle.Render("● CI passing")
+	case "failure":
+		return redStyle.Render("● CI failing")
+	case "pending":
+		return yellowStyle.Render("● CI running")
+	case "none":
+		return dimStyle.Render("○ no CI")
+	}
+	return ""
+}
+
+// PrintSimpleWorktreeList prints a simple worktree list (for non-verbose
+// output). On a terminal the names, statuses and PRs line up in columns,
+// colored like the dashboard; piped, it keeps the plain one-per-line form
+// scripts already parse.
 func PrintSimpleWorktreeList(items []WorktreeListItem) {
+	if isTerminal(stdout()) {
+		printWorktreeColumns(stdout(), items)
+		return
+	}
 	for _, item := range items {
 		prefix := "  "
 		if item.IsCurrent {
@@ -334,6 +367,12 @@ func PrintSimpleWorktreeList(items []WorktreeListItem) {
 		}
 
 		name := item.Name
+		if item.Size != "" {
+			name += " " + dimStyle.Render(item.Size)
+		}
+		if item.Created != "" {
+			name += " " + dimStyle.Render(item.Created)
+		}
 
 		// Add stale info
 		staleInfo := ""
@@ -344,19 +383,86 @@ func PrintSimpleWorktreeList(items []WorktreeListItem) {
 		// Add CI status
 		ciIcon := ""
 		switch item.CIStatus {
-		case "success":
-			ciIcon = " " + greenStyle.Render("●")
-		case "failure":
-			ciIcon = " " + redStyle.Render("●")
-		case "pending":
-			ciIcon = " " + yellowStyle.Render("●")
+		case "success", "failure", "pending":
+			ciIcon = " " + lipgloss.NewStyle().Foreground(CIStatusColor(item.CIStatus)).Render("●")
 		}
 
 		fmt.Fprintf(stdout(), "%s%s%s%s\n", prefix, name, staleInfo, ciIcon)
 	}
 }
 
+// isTerminal reports whether w is a terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+// printWorktreeColumns prints items as aligned columns: name, status, PR,
+// CI, size, creation date and stale reason. Columns no item has a value for are left out.
+func printWorktreeColumns(w io.Writer, items []WorktreeListItem) {
+	if len(items) == 0 {
+		return
+	}
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		status := ""
+		if item.Status != "" {
+			status = lipgloss.NewStyle().Foreground(StatusColor(item.Status)).Render(item.Status)
+		}
+		pr := ""
+		if item.PRInfo != "" {
+			pr = lipgloss.NewStyle().Foreground(PRStateColor(item.PRState)).Render(item.PRInfo)
+		}
+		ci := ""
+		switch item.CIStatus {
+		case "success", "failure", "pending":
+			ci = lipgloss.NewStyle().Foreground(CIStatusColor(item.CIStatus)).Render("●")
+		}
+		stale := ""
+		if item.StaleInfo != "" {
+			stale = lipgloss.NewStyle().Foreground(StatusColor("stale")).Render("stale: " + item.StaleInfo)
+		}
+		rows[i] = []string{boldStyle.Render(item.Name), status, pr, ci, dimStyle.Render(item.Size), dimStyle.Render(item.Created), stale}
+	}
+
+	widths := make([]int, len(rows[0]))
+	for _, row := range rows {
+		for col, cell := range row {
+			widths[col] = max(widths[col], lipgloss.Width(cell))
+		}
+	}
+
+	for i, item := range items {
+		prefix := "  "
+		if item.IsCurrent {
+			prefix = greenStyle.Render("▸ ")
+		}
+		var cells []string
+		for col, cell := range rows[i] {
+			if widths[col] == 0 {
+				continue
+			}
+			cells = append(cells, cell+strings.Repeat(" ", widths[col]-lipgloss.Width(cell)))
+		}
+		fmt.Fprintln(w, prefix+strings.TrimRight(strings.Join(cells, "  "), " "))
+	}
+}
+
 // RepoName extracts the repo name from a path
 func RepoName(repoPath string) string {
 	return filepath.Base(repoPath)
 }
+
+// FormatSize renders a byte count in binary units, e.g. "1.4 GB".
+func FormatSize(bytes int64) string {
+	const unit = 1024
+	if bytes < unit {
+		return fmt.Sprintf("%d B", bytes)
+	}
+	div, exp := int64(unit), 0
+	for n := bytes / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(bytes)/float64(div), "KMGTPE"[exp])
+}
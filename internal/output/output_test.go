@@ -6,6 +6,8 @@ import (
 	"os"
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/lipgloss"
 )
 
 // captureStdout captures stdout during function execution
@@ -343,6 +345,16 @@ func TestPrintWorktreeList(t *testing.T) {
 			IsMain:    false,
 			Status:    "modified",
 		},
+		{
+			Name:     "ci-fail",
+			Branch:   "ci-fail",
+			CIStatus: "failure",
+		},
+		{
+			Name:     "no-ci",
+			Branch:   "no-ci",
+			CIStatus: "none",
+		},
 	}
 
 	output := captureStdout(func() {
@@ -355,6 +367,9 @@ func TestPrintWorktreeList(t *testing.T) {
 	if !strings.Contains(output, "feature-test") {
 		t.Errorf("PrintWorktreeList() should contain feature worktree, got: %s", output)
 	}
+	if !strings.Contains(output, "CI failing") || !strings.Contains(output, "no CI") {
+		t.Errorf("PrintWorktreeList() should label CI status, got: %s", output)
+	}
 }
 
 func TestPrintSimpleWorktreeList(t *testing.T) {
@@ -400,6 +415,49 @@ func TestPrintSimpleWorktreeList(t *testing.T) {
 	}
 }
 
+func TestPrintWorktreeColumns(t *testing.T) {
+	items := []WorktreeListItem{
+		{Name: "main", IsCurrent: true, Status: "clean"},
+		{Name: "feature-long", Status: "modified", PRInfo: "#12", PRState: "OPEN"},
+		{Name: "old", Status: "clean", StaleInfo: "pr_merged"},
+	}
+
+	var buf bytes.Buffer
+	printWorktreeColumns(&buf, items)
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 3:\n%s", len(lines), buf.String())
+	}
+
+	// Statuses start in one column, past the longest name
+	column := func(line, s string) int { return lipgloss.Width(line[:strings.Index(line, s)]) }
+	col := column(lines[1], "modified")
+	if col <= len("  feature-long") {
+		t.Fatalf("status not after the name: %q", lines[1])
+	}
+	for _, line := range []string{lines[0], lines[2]} {
+		if got := column(line, "clean"); got != col {
+			t.Errorf("status in %q at %d, want %d", line, got, col)
+		}
+	}
+	// No item has CI or a size, so the stale reason follows the PR column
+	if got, want := column(lines[2], "stale: pr_merged"), column(lines[1], "#12")+len("#12  "); got != want {
+		t.Errorf("stale reason at %d, want %d:\n%s", got, want, buf.String())
+	}
+}
+
+func TestStatusColors(t *testing.T) {
+	if StatusColor("clean") != StatusColorSuccess || StatusColor("mixed") != StatusColorWarning || StatusColor("stale") != StatusColorMuted {
+		t.Error("StatusColor maps clean, mixed or stale to the wrong color")
+	}
+	if PRStateColor("MERGED") != StatusColorInfo || PRStateColor("CLOSED") != StatusColorError || PRStateColor("DRAFT") != StatusColorMuted {
+		t.Error("PRStateColor maps MERGED, CLOSED or DRAFT to the wrong color")
+	}
+	if CIStatusColor("failure") != StatusColorError || CIStatusColor("none") != StatusColorMuted {
+		t.Error("CIStatusColor maps failure or none to the wrong color")
+	}
+}
+
 func TestRepoName(t *testing.T) {
 	tests := []struct {
 		path     string
@@ -464,3 +522,22 @@ func TestStdoutFollowsReassignedOsStdout(t *testing.T) {
 		t.Errorf("output did not follow the reassigned os.Stdout, got %q", captured)
 	}
 }
+
+func TestFormatSize(t *testing.T) {
+	tests := []struct {
+		bytes int64
+		want  string
+	}{
+		{0, "0 B"},
+		{1023, "1023 B"},
+		{1024, "1.0 KB"},
+		{1536, "1.5 KB"},
+		{5 * 1024 * 1024, "5.0 MB"},
+		{3 * 1024 * 1024 * 1024 / 2, "1.5 GB"},
+	}
+	for _, tt := range tests {
+		if got := FormatSize(tt.bytes); got != tt.want {
+			t.Errorf("FormatSize(%d) = %q, want %q", tt.bytes, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package output
+
+import "github.com/charmbracelet/lipgloss"
+
+// Status colors shared by the CLI list and the TUI dashboard, so a worktree
+// reads the same in both. The TUI builds its palette on top of these.
+var (
+	StatusColorSuccess = lipgloss.AdaptiveColor{Light: "#16a34a", Dark: "#4ade80"} // Green - clean, open, passing
+	StatusColorWarning = lipgloss.AdaptiveColor{Light: "#ea580c", Dark: "#fb923c"} // Orange - changes, running
+	StatusColorError   = lipgloss.AdaptiveColor{Light: "#dc2626", Dark: "#f87171"} // Red - missing, closed, failing
+	StatusColorInfo    = lipgloss.AdaptiveColor{Light: "#6366f1", Dark: "#818cf8"} // Indigo - unpushed, merged
+	StatusColorMuted   = lipgloss.AdaptiveColor{Light: "#6b7280", Dark: "#9ca3af"} // Gray - stale, draft, no CI
+)
+
+// StatusColor returns the color for a worktree status (WorktreeInfo.Status)
+// or the "stale" branch status. Unknown statuses get the muted color.
+func StatusColor(status string) lipgloss.AdaptiveColor {
+	switch status {
+	case "clean":
+		return StatusColorSuccess
+	case "modified", "untracked", "mixed", "unknown":
+		return StatusColorWarning
+	case "unpushed":
+		return StatusColorInfo
+	case "missing":
+		return StatusColorError
+	default:
+		return StatusColorMuted
+	}
+}
+
+// PRStateColor returns the color for a PR state: OPEN, DRAFT, MERGED or
+// CLOSED. An unknown state is treated as open.
+func PRStateColor(state string) lipgloss.AdaptiveColor {
+	switch state {
+	case "DRAFT":
+		return StatusColorMuted
+	case "MERGED":
+		return StatusColorInfo
+	case "CLOSED":
+		return StatusColorError
+	default:
+		return StatusColorSuccess
+	}
+}
+
+// CIStatusColor returns the color for a CI status: success, failure,
+// pending, or anything else for no CI.
+func CIStatusColor(status string) lipgloss.AdaptiveColor {
+	switch status {
+	case "success":
+		return StatusColorSuccess
+	case "failure":
+		return StatusColorError
+	case "pending":
+		return StatusColorWarning
+	default:
+		return StatusColorMuted
+	}
+}
@@ -0,0 +1,58 @@
+package output
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Summary is the outcome of a mutating command (create, delete, cleanup,
+// merge), printed as its last line so users and scripts read every result the
+// same way:
+//
+//	✓ 1 created, 0 failed, 0 warnings
+//	✗ 2 deleted, 1 failed, 0 warnings
+//
+// Skipped items are only mentioned when there are some.
+type Summary struct {
+	Verb     string // Past tense of the action: "created", "deleted", "merged"
+	Done     int
+	Skipped  int
+	Failed   int
+	Warnings int
+}
+
+// String renders the summary without color, leading with ✓ when nothing
+// failed and ✗ otherwise.
+func (s Summary) String() string {
+	symbol := SymbolSuccess
+	if s.Failed > 0 {
+		symbol = SymbolError
+	}
+	parts := []string{fmt.Sprintf("%d %s", s.Done, s.Verb)}
+	if s.Skipped > 0 {
+		parts = append(parts, fmt.Sprintf("%d skipped", s.Skipped))
+	}
+	parts = append(parts,
+		fmt.Sprintf("%d failed", s.Failed),
+		fmt.Sprintf("%d %s", s.Warnings, plural(s.Warnings, "warning", "warnings")),
+	)
+	return symbol + " " + strings.Join(parts, ", ")
+}
+
+// PrintSummary prints s, with its symbol colored.
+func PrintSummary(s Summary) {
+	line := s.String()
+	symbol, rest, _ := strings.Cut(line, " ")
+	style := successStyle
+	if s.Failed > 0 {
+		style = errorStyle
+	}
+	fmt.Fprintln(stdout(), style.Render(symbol)+" "+rest)
+}
+
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
+}
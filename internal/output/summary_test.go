@@ -0,0 +1,30 @@
+package output
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSummary(t *testing.T) {
+	tests := []struct {
+		summary Summary
+		want    string
+	}{
+		{Summary{Verb: "created", Done: 1}, "✓ 1 created, 0 failed, 0 warnings"},
+		{Summary{Verb: "created", Done: 1, Warnings: 1}, "✓ 1 created, 0 failed, 1 warning"},
+		{Summary{Verb: "deleted", Done: 3, Failed: 1}, "✗ 3 deleted, 1 failed, 0 warnings"},
+		{Summary{Verb: "merged", Skipped: 1}, "✓ 0 merged, 1 skipped, 0 failed, 0 warnings"},
+	}
+	for _, tt := range tests {
+		if got := tt.summary.String(); got != tt.want {
+			t.Errorf("%+v.String() = %q, want %q", tt.summary, got, tt.want)
+		}
+	}
+
+	out := captureStdout(func() {
+		PrintSummary(Summary{Verb: "deleted", Done: 2})
+	})
+	if !strings.Contains(out, "✓") || !strings.Contains(out, "2 deleted, 0 failed, 0 warnings") {
+		t.Errorf("PrintSummary() output = %q", out)
+	}
+}
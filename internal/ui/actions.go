@@ -1,11 +1,11 @@
 package ui
 
 import (
-	"fmt"
-	"os"
 	"os/exec"
 	"strings"
 
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/launcher"
 	"github.com/langtind/gren/internal/logging"
 )
 
@@ -21,6 +21,8 @@ func (m Model) getAvailableActions() []PostCreateAction {
 
 // getActionsForPath returns available actions for a given path
 func (m Model) getActionsForPath(worktreePath, backActionName string) []PostCreateAction {
+	terminal, _ := m.launcherSettings()
+	terminalCommand, terminalArgs := launcher.Terminal(terminal, worktreePath)
 
 	allActions := []PostCreateAction{
 		{
@@ -34,8 +36,8 @@ func (m Model) getActionsForPath(worktreePath, backActionName string) []PostCrea
 		{
 			Name:        "Open in Terminal",
 			Icon:        "🖥️",
-			Command:     m.getTerminalCommand(),
-			Args:        m.getTerminalArgs(worktreePath),
+			Command:     terminalCommand,
+			Args:        terminalArgs,
 			Description: "Open worktree directory in new terminal",
 		},
 		{
@@ -98,52 +100,6 @@ func (m Model) getActionsForPath(worktreePath, backActionName string) []PostCrea
 	return availableActions
 }
 
-// getTerminalCommand determines the best way to open the current terminal
-func (m Model) getTerminalCommand() string {
-	// Check what terminal we're running in by looking at environment variables
-	if term := os.Getenv("TERM_PROGRAM"); term != "" {
-		switch term {
-		case "WarpTerminal":
-			return "warp-cli"
-		case "iTerm.app":
-			return "osascript"
-		case "Apple_Terminal":
-			return "osascript"
-		}
-	}
-
-	// Fallback to generic open command
-	return "open"
-}
-
-// getTerminalArgs returns the appropriate arguments for opening a terminal
-func (m Model) getTerminalArgs(worktreePath string) []string {
-	termProgram := os.Getenv("TERM_PROGRAM")
-	switch termProgram {
-	case "WarpTerminal":
-		return []string{"open", worktreePath}
-	case "iTerm.app":
-		return []string{
-			"-e",
-			fmt.Sprintf(`tell application "iTerm"
-				create window with default profile
-				tell current session of current window
-					write text "cd %s"
-				end tell
-			end tell`, worktreePath),
-		}
-	case "Apple_Terminal":
-		return []string{
-			"-e",
-			fmt.Sprintf(`tell application "Terminal"
-				do script "cd %s"
-			end tell`, worktreePath),
-		}
-	default:
-		return []string{worktreePath}
-	}
-}
-
 // isCommandAvailable checks if a command is available in PATH
 func isCommandAvailable(command string) bool {
 	if command == "" {
@@ -178,9 +134,12 @@ func (m Model) executeAction(action PostCreateAction, worktreePath string) error
 }
 
 // sanitizeBranchForPath converts a branch name to a valid directory name
-// e.g., "feature/testing" -> "feature-testing"
-func sanitizeBranchForPath(branchName string) string {
-	return strings.ReplaceAll(branchName, "/", "-")
+// with the project's sanitize rules, e.g. "feature/testing" -> "feature-testing"
+func (m Model) sanitizeBranchForPath(branchName string) string {
+	if m.config != nil {
+		return m.config.Sanitize.Apply(branchName)
+	}
+	return config.Sanitize{}.Apply(branchName)
 }
 
 // isValidBranchName validates git branch names
@@ -631,6 +631,34 @@ func TestCleanupPreSelectionSafety(t *testing.T) {
 		}
 	})
 
+	t.Run("worktree with pr_merged but a rebase in progress should NOT be pre-selected", func(t *testing.T) {
+		m := Model{
+			currentView: ToolsView,
+			worktrees: []Worktree{
+				{Branch: "main", IsMain: true, BranchStatus: "active"},
+				{
+					Branch:       "feature/mid-rebase",
+					BranchStatus: "stale",
+					StaleReason:  "pr_merged",
+					Operation:    "rebase",
+				},
+			},
+		}
+
+		msg := tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}}
+		newModel, _ := m.handleToolsKeys(msg)
+
+		if newModel.cleanupState == nil {
+			t.Fatal("cleanupState should be initialized")
+		}
+		if newModel.cleanupState.selectedIndices[0] {
+			t.Error("Worktree with a rebase in progress should NOT be pre-selected")
+		}
+		if view := newModel.renderCleanupConfirmation(); !strings.Contains(view, "rebase in progress") {
+			t.Error("cleanup confirmation should flag the rebase in progress")
+		}
+	})
+
 	t.Run("worktree with pr_merged and clean SHOULD be pre-selected", func(t *testing.T) {
 		m := Model{
 			currentView: ToolsView,
@@ -877,3 +905,54 @@ func TestCleanupProgressShowsOnlySelectedWorktrees(t *testing.T) {
 		}
 	})
 }
+
+func TestRenderCleanupConfirmationSizes(t *testing.T) {
+	m := Model{
+		cleanupState: &CleanupState{
+			staleWorktrees: []Worktree{
+				{Branch: "feature/stale1", Path: "/wt/stale1", BranchStatus: "stale", StaleReason: "pr_merged"},
+				{Branch: "feature/stale2", Path: "/wt/stale2", BranchStatus: "stale", StaleReason: "no_unique_commits"},
+			},
+			selectedIndices: map[int]bool{0: true},
+			cursorIndex:     0,
+		},
+	}
+
+	if result := m.renderCleanupConfirmation(); !strings.Contains(result, "Calculating disk usage") {
+		t.Error("Should say disk usage is being calculated before sizes arrive")
+	}
+
+	updated, _ := m.Update(cleanupSizesMsg{sizes: map[string]int64{"/wt/stale1": 2048, "/wt/stale2": 1024 * 1024}})
+	result := updated.(Model).renderCleanupConfirmation()
+
+	if !strings.Contains(result, "Frees ~2.0 KB across 1 worktree(s)") {
+		t.Error("Summary should total only the selected worktrees")
+	}
+	if !strings.Contains(result, "1.0 MB") {
+		t.Error("Each worktree should show its own size")
+	}
+}
+
+func TestCleanupStateSkipsUnknownStatus(t *testing.T) {
+	m := Model{
+		currentView: ToolsView,
+		worktrees: []Worktree{
+			{Branch: "feature/merged", BranchStatus: "stale", StaleReason: "pr_merged"},
+			{Branch: "feature/locked", Status: "unknown", StatusError: "git status failed: index file corrupt", BranchStatus: "stale", StaleReason: "pr_merged"},
+		},
+	}
+
+	newModel, _ := m.handleToolsKeys(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'c'}})
+	if newModel.cleanupState == nil {
+		t.Fatal("cleanupState should be initialized")
+	}
+	if !newModel.cleanupState.selectedIndices[0] {
+		t.Error("clean pr_merged worktree should be pre-selected")
+	}
+	if newModel.cleanupState.selectedIndices[1] {
+		t.Error("worktree whose status could not be read should NOT be pre-selected")
+	}
+	if view := newModel.renderCleanupConfirmation(); !strings.Contains(view, "status unknown") {
+		t.Error("cleanup list should mark the worktree's status as unknown")
+	}
+}
@@ -3,6 +3,7 @@ package ui
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -16,7 +17,9 @@ import (
 	"github.com/langtind/gren/internal/config"
 	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/directive"
+	"github.com/langtind/gren/internal/launcher"
 	"github.com/langtind/gren/internal/logging"
+	"github.com/langtind/gren/internal/output"
 	"github.com/langtind/gren/internal/skills"
 )
 
@@ -89,9 +92,21 @@ func (m Model) initializeCreateStateWithBase(suggestedBase string) tea.Cmd {
 			recommendedBase = branchStatuses[0].Name
 		}
 
+		// Presets are offered when the config loads; without them the
+		// wizard starts at the branch step as before
+		var presets map[string]config.Preset
+		var presetNames []string
+		if m.configManager != nil {
+			if cfg, err := m.configManager.Load(); err == nil {
+				presets, presetNames = cfg.Presets, cfg.PresetNames()
+			}
+		}
+
 		return createInitMsg{
 			branchStatuses:  branchStatuses,
 			recommendedBase: recommendedBase,
+			presets:         presets,
+			presetNames:     presetNames,
 		}
 	}
 }
@@ -103,13 +118,56 @@ func (m Model) initializeDeleteState() tea.Cmd {
 	}
 }
 
-// initializeDeleteStateForWorktree initializes delete state for a specific worktree
+// initializeDeleteStateForWorktree initializes delete state for a specific
+// worktree, with the issues the confirmation shows
 func (m Model) initializeDeleteStateForWorktree(worktree Worktree) tea.Cmd {
 	return func() tea.Msg {
-		return deleteInitMsg{selectedWorktree: &worktree}
+		issues, err := m.precheckDelete([]Worktree{worktree})
+		if err != nil {
+			return deleteInitMsg{err: err}
+		}
+		return deleteInitMsg{selectedWorktree: &worktree, issues: issues}
+	}
+}
+
+// precheckSelectedWorktrees runs the precheck over the worktrees picked for
+// a multi-select delete, before the confirmation shows its issues
+func (m Model) precheckSelectedWorktrees() tea.Cmd {
+	var worktrees []Worktree
+	for _, idx := range m.deleteState.selectedWorktrees {
+		if idx < len(m.worktrees) {
+			worktrees = append(worktrees, m.worktrees[idx])
+		}
+	}
+	return func() tea.Msg {
+		issues, err := m.precheckDelete(worktrees)
+		return deletePrecheckMsg{issues: issues, err: err}
 	}
 }
 
+// precheckDelete runs PrecheckDelete over the worktrees about to be deleted,
+// as `gren delete` does. With more than one, each issue names its worktree.
+func (m Model) precheckDelete(worktrees []Worktree) ([]string, error) {
+	worktreeManager := core.NewWorktreeManager(m.gitRepo, m.configManager)
+	var all []string
+	for _, wt := range worktrees {
+		issues, _, err := worktreeManager.PrecheckDelete(convertUIWorktreeToCore(wt))
+		if err != nil {
+			if len(worktrees) > 1 {
+				return nil, fmt.Errorf("%s: %w", wt.Name, err)
+			}
+			return nil, err
+		}
+		for _, issue := range issues {
+			if len(worktrees) > 1 {
+				issue = wt.Name + ": " + issue
+			}
+			all = append(all, issue)
+		}
+	}
+	return all, nil
+}
+
 // loadAvailableBranches loads branches available for existing worktree creation
 func (m Model) loadAvailableBranches() tea.Cmd {
 	return func() tea.Msg {
@@ -428,7 +486,7 @@ func (m Model) createWorktree() tea.Cmd {
 		baseBranch := m.createState.baseBranch
 		isNewBranch := m.createState.createMode == CreateModeNewBranch
 
-		logging.Info("Creating worktree: branch=%s, base=%s, isNew=%v", branchName, baseBranch, isNewBranch)
+		logging.Info("Creating worktree: branch=%s, base=%s, isNew=%v, preset=%s", branchName, baseBranch, isNewBranch, m.createState.preset)
 
 		// Use WorktreeManager to create worktree (same logic as CLI)
 		req := core.CreateWorktreeRequest{
@@ -437,11 +495,12 @@ func (m Model) createWorktree() tea.Cmd {
 			BaseBranch:  baseBranch,
 			IsNewBranch: isNewBranch,
 			WorktreeDir: "", // Let WorktreeManager determine from config
+			Preset:      m.createState.preset,
 		}
 
 		ctx := context.Background()
 		worktreeManager := core.NewWorktreeManager(m.gitRepo, m.configManager)
-		_, warning, err := worktreeManager.CreateWorktree(ctx, req)
+		worktreePath, warning, err := worktreeManager.CreateWorktree(ctx, req)
 		if err != nil {
 			logging.Error("Create worktree failed: %v", err)
 			return worktreeCreatedMsg{err: err}
@@ -451,46 +510,33 @@ func (m Model) createWorktree() tea.Cmd {
 			logging.Info("Create worktree warning: %s", warning)
 		}
 		logging.Info("Successfully created worktree: %s", branchName)
-		return worktreeCreatedMsg{branchName: branchName, warning: warning}
+		return worktreeCreatedMsg{branchName: branchName, path: worktreePath, warning: warning}
 	}
 }
 
-// switchToWorktree opens a new terminal in the worktree directory
-func (m Model) switchToWorktree(worktreePath string) tea.Cmd {
+// switchToWorktree takes the user to the worktree. With shell integration
+// active it writes a cd directive and quits, so the shell gren was started
+// from changes directory (see navigateToWorktree). Without it nothing reads
+// the directive, so a new terminal is opened in the worktree instead and the
+// TUI stays open.
+func (m Model) switchToWorktree(worktreeName, worktreePath string) tea.Cmd {
+	if directive.IsShellIntegrationActive() {
+		return m.navigateToWorktree(worktreeName, worktreePath)
+	}
+	terminal, _ := m.launcherSettings()
 	return func() tea.Msg {
-		// Detect terminal and open new window/tab in worktree directory
-		termProgram := os.Getenv("TERM_PROGRAM")
-		var cmd *exec.Cmd
-
-		switch termProgram {
-		case "WarpTerminal":
-			// Warp terminal
-			cmd = exec.Command("warp-cli", "open", worktreePath)
-		case "iTerm.app":
-			// iTerm2
-			script := fmt.Sprintf(`tell application "iTerm"
-				create window with default profile
-				tell current session of current window
-					write text "cd %s"
-				end tell
-			end tell`, worktreePath)
-			cmd = exec.Command("osascript", "-e", script)
-		case "Apple_Terminal":
-			// macOS Terminal
-			script := fmt.Sprintf(`tell application "Terminal"
-				do script "cd %s"
-			end tell`, worktreePath)
-			cmd = exec.Command("osascript", "-e", script)
-		default:
-			// Fallback: just open the directory
-			cmd = exec.Command("open", worktreePath)
-		}
-
+		logging.Info("switchToWorktree: no shell integration, opening a terminal in %s", worktreePath)
+		name, args := launcher.Terminal(terminal, worktreePath)
+		cmd := exec.Command(name, args...)
 		if err := cmd.Start(); err != nil {
-			return fmt.Errorf("failed to open terminal: %w", err)
+			logging.Error("switchToWorktree: failed to open terminal: %v", err)
+			return navigateCompleteMsg{err: fmt.Errorf("failed to open terminal: %w (set up shell integration with gren shell-init to switch in place)", err)}
+		}
+		return navigateCompleteMsg{
+			worktreeName: worktreeName,
+			worktreePath: worktreePath,
+			newTerminal:  true,
 		}
-
-		return nil
 	}
 }
 
@@ -502,73 +548,33 @@ func (m Model) deleteSelectedWorktrees() tea.Cmd {
 		}
 
 		deletedCount := 0
+		danglingRemoved := 0
+		worktreeManager := core.NewWorktreeManager(m.gitRepo, m.configManager)
+
+		// Hold the repo lock so a concurrent gren can't change the worktree
+		// list under us
+		lock, err := worktreeManager.LockRepo(context.Background())
+		if err != nil {
+			return worktreeDeletedMsg{err: err}
+		}
+		defer lock.Unlock()
 
 		// Helper function to delete a single worktree
 		deleteWorktree := func(worktree Worktree) error {
 			logging.Info("Deleting worktree: %s (path: %s)", worktree.Name, worktree.Path)
 
-			// 1. Remove symlinks that point outside the worktree (they cause issues with git worktree remove)
-			// This includes .gren, .env files, etc. that may have been symlinked by post-create hooks
-			entries, err := os.ReadDir(worktree.Path)
-			if err == nil {
-				for _, entry := range entries {
-					entryPath := filepath.Join(worktree.Path, entry.Name())
-					if info, err := os.Lstat(entryPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
-						// It's a symlink - check if it points outside the worktree
-						target, err := os.Readlink(entryPath)
-						if err == nil {
-							// Resolve to absolute path
-							if !filepath.IsAbs(target) {
-								target = filepath.Join(worktree.Path, target)
-							}
-							absTarget, _ := filepath.Abs(target)
-							absWorktree, _ := filepath.Abs(worktree.Path)
-							// If symlink points outside worktree, remove it
-							if !strings.HasPrefix(absTarget, absWorktree) {
-								logging.Debug("Removing external symlink: %s -> %s", entryPath, absTarget)
-								if err := os.Remove(entryPath); err != nil {
-									logging.Warn("Failed to remove symlink %s: %v", entryPath, err)
-								}
-							}
-						}
-					}
-				}
-			}
-
-			// 2. Deinit submodules and track if worktree has submodules
-			hasSubmodules := false
-			if _, err := os.Stat(filepath.Join(worktree.Path, ".gitmodules")); err == nil {
-				hasSubmodules = true
-				logging.Debug("Worktree has submodules, running deinit")
-				cmd := exec.Command("git", "-C", worktree.Path, "submodule", "deinit", "--all", "--force")
-				output, err := cmd.CombinedOutput()
-				if err != nil {
-					logging.Error("Failed to deinit submodules: %v, output: %s", err, string(output))
-					return fmt.Errorf("failed to deinit submodules in worktree '%s': %w\n\nThis can happen if submodules have uncommitted changes.\nTry running manually:\n  cd %s\n  git submodule deinit --all --force\n\nOutput: %s",
-						worktree.Name, err, worktree.Path, string(output))
-				}
-				logging.Debug("Submodules deinited successfully")
+			// Confirming the delete forces past uncommitted changes, which a
+			// conflicted rebase or merge always has; refuse it here instead of
+			// discarding the operation. `gren delete -f` is the explicit way.
+			if worktree.Operation != "" {
+				return core.OperationError(worktree.Name, worktree.Path, worktree.Operation)
 			}
 
-			// 3. Remove worktree using git
-			// Note: --force is required for worktrees with submodules (even after deinit)
-			// or when user confirmed deletion of worktree with uncommitted changes
-			useForce := hasSubmodules || m.deleteState.forceDelete
-			var cmd *exec.Cmd
-			if useForce {
-				logging.Debug("Running: git worktree remove --force %s", worktree.Path)
-				cmd = exec.Command("git", "worktree", "remove", "--force", worktree.Path)
-			} else {
-				logging.Debug("Running: git worktree remove %s", worktree.Path)
-				cmd = exec.Command("git", "worktree", "remove", worktree.Path)
-			}
-			output, err := cmd.CombinedOutput()
+			result, err := worktreeManager.RemoveWorktree(context.Background(), convertUIWorktreeToCore(worktree), m.deleteState.forceDelete)
 			if err != nil {
-				logging.Error("Failed to remove worktree: %v, output: %s", err, string(output))
-				return fmt.Errorf("failed to remove worktree '%s': %w\n\nOutput: %s\n\nIf the worktree has uncommitted changes, commit or stash them first.",
-					worktree.Name, err, string(output))
+				return err
 			}
-
+			danglingRemoved += len(result.DanglingSymlinks)
 			logging.Info("Successfully deleted worktree: %s", worktree.Name)
 			return nil
 		}
@@ -597,7 +603,7 @@ func (m Model) deleteSelectedWorktrees() tea.Cmd {
 			}
 		}
 
-		return worktreeDeletedMsg{deletedCount: deletedCount}
+		return worktreeDeletedMsg{deletedCount: deletedCount, danglingRemoved: danglingRemoved}
 	}
 }
 
@@ -619,51 +625,17 @@ func (m Model) initializeOpenInState(worktreeName, worktreePath string) tea.Cmd
 func (m Model) openPostCreateScript() tea.Cmd {
 	scriptPath := ".gren/post-create.sh"
 
-	// First check EDITOR environment variable
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-
-	// If no env var set, try common editors
-	if editor == "" {
-		fallbackEditors := []string{"code", "zed", "vim", "nano"}
-		for _, e := range fallbackEditors {
-			if isCommandAvailable(e) {
-				editor = e
-				break
-			}
-		}
-	}
-
-	if editor == "" {
+	_, configured := m.launcherSettings()
+	editor := launcher.Editor(configured)
+	if editor == nil {
 		return func() tea.Msg {
-			return scriptEditCompleteMsg{err: fmt.Errorf("no editor found. Set EDITOR environment variable")}
+			return scriptEditCompleteMsg{err: fmt.Errorf("no editor found. Set editor in config or the EDITOR environment variable")}
 		}
 	}
 
-	// Check if it's a terminal editor (vim, nvim, nano, etc.)
-	terminalEditors := map[string]bool{
-		"vim": true, "nvim": true, "vi": true, "nano": true, "emacs": true, "helix": true, "hx": true,
-	}
-
-	// Get the base command name (in case EDITOR contains a path)
-	editorBase := filepath.Base(editor)
-
-	if terminalEditors[editorBase] {
-		// Use tea.ExecProcess for terminal editors - this suspends the TUI
-		cmd := exec.Command(editor, scriptPath)
-		return tea.ExecProcess(cmd, func(err error) tea.Msg {
-			return scriptEditCompleteMsg{err: err}
-		})
-	}
-
-	// For GUI editors, just start them in background
-	return func() tea.Msg {
-		cmd := exec.Command(editor, scriptPath)
-		err := cmd.Start()
+	return openInEditor(editor, scriptPath, func(err error) tea.Msg {
 		return scriptEditCompleteMsg{err: err}
-	}
+	})
 }
 
 // commitConfiguration commits the .gren configuration to git
@@ -764,17 +736,13 @@ func initRecommendationOptions(claudeAvailable bool) (labels []string, modes []i
 func (m Model) analyzeProject() []DetectedFile {
 	var files []DetectedFile
 
-	// Detect all .env files using glob pattern
-	envFiles, err := filepath.Glob(".env*")
-	if err == nil {
-		for _, envFile := range envFiles {
-			files = append(files, DetectedFile{
-				Path:         envFile,
-				Type:         "env",
-				IsGitIgnored: m.isGitIgnored(envFile),
-				Description:  getFileDescription(envFile, "env"),
-			})
-		}
+	// Detect all .env files, including ones in subdirectories (monorepos)
+	for _, envFile := range config.FindEnvFiles(".env*") {
+		files = append(files, DetectedFile{
+			Path:        envFile,
+			Type:        "env",
+			Description: getFileDescription(envFile, "env"),
+		})
 	}
 
 	// Track already-added paths to avoid duplicates
@@ -799,14 +767,23 @@ func (m Model) analyzeProject() []DetectedFile {
 		}
 		if _, err := os.Stat(pattern); err == nil {
 			files = append(files, DetectedFile{
-				Path:         pattern,
-				Type:         fileType,
-				IsGitIgnored: m.isGitIgnored(pattern),
-				Description:  getFileDescription(pattern, fileType),
+				Path:        pattern,
+				Type:        fileType,
+				Description: getFileDescription(pattern, fileType),
 			})
 		}
 	}
 
+	// Ask git about every file in one go
+	paths := make([]string, len(files))
+	for i, f := range files {
+		paths[i] = f.Path
+	}
+	ignored := config.CheckIgnored(paths)
+	for i := range files {
+		files[i].IsGitIgnored = ignored[files[i].Path]
+	}
+
 	// Sort by type and name
 	sort.Slice(files, func(i, j int) bool {
 		if files[i].Type != files[j].Type {
@@ -820,7 +797,7 @@ func (m Model) analyzeProject() []DetectedFile {
 
 // getFileDescription returns a human-readable description for a file
 func getFileDescription(path, fileType string) string {
-	switch path {
+	switch filepath.Base(path) {
 	case ".env":
 		return "Environment variables"
 	case ".env.local":
@@ -851,13 +828,6 @@ func getFileDescription(path, fileType string) string {
 	}
 }
 
-// isGitIgnored checks if a file is git ignored
-func (m Model) isGitIgnored(filename string) bool {
-	cmd := exec.Command("git", "check-ignore", filename)
-	err := cmd.Run()
-	return err == nil // If command succeeds, file is ignored
-}
-
 // parseGitIgnore parses .gitignore file and returns patterns
 func (m Model) parseGitIgnore() []string {
 	file, err := os.Open(".gitignore")
@@ -936,83 +906,35 @@ func (m Model) openConfigFile(filePath string) tea.Cmd {
 		}
 	}
 
-	// First check EDITOR environment variable
-	editor := os.Getenv("EDITOR")
-	if editor == "" {
-		editor = os.Getenv("VISUAL")
-	}
-
-	// If no env var set, try common editors
-	if editor == "" {
-		fallbackEditors := []string{"code", "zed", "vim", "nano"}
-		for _, e := range fallbackEditors {
-			if _, err := exec.LookPath(e); err == nil {
-				editor = e
-				break
-			}
-		}
-	}
-
-	if editor == "" {
+	_, configured := m.launcherSettings()
+	editor := launcher.Editor(configured)
+	if editor == nil {
 		return func() tea.Msg {
-			return configFileOpenedMsg{err: fmt.Errorf("no editor found. Set EDITOR environment variable or install code/vim/nano")}
+			return configFileOpenedMsg{err: fmt.Errorf("no editor found. Set editor in config, the EDITOR environment variable, or install code/vim/nano")}
 		}
 	}
 
-	// Check if it's a terminal editor (vim, nvim, nano, etc.)
-	terminalEditors := map[string]bool{
-		"vim": true, "nvim": true, "vi": true, "nano": true, "emacs": true, "helix": true, "hx": true,
-	}
-
-	editorBase := filepath.Base(editor)
-
-	if terminalEditors[editorBase] {
-		// Use tea.ExecProcess for terminal editors - this suspends the TUI
-		cmd := exec.Command(editor, filePath)
-		return tea.ExecProcess(cmd, func(err error) tea.Msg {
-			return configFileOpenedMsg{err: err}
-		})
-	}
-
-	// For GUI editors, just start them in background
-	return func() tea.Msg {
-		cmd := exec.Command(editor, filePath)
-		if err := cmd.Start(); err != nil {
-			return configFileOpenedMsg{err: fmt.Errorf("failed to open %s: %w", editor, err)}
-		}
-		return configFileOpenedMsg{err: nil}
-	}
+	return openInEditor(editor, filePath, func(err error) tea.Msg {
+		return configFileOpenedMsg{err: err}
+	})
 }
 
 // pruneWorktrees removes missing/prunable worktrees from git tracking
 func (m Model) pruneWorktrees() tea.Cmd {
+	gitRepo := m.gitRepo
+	configManager := m.configManager
+
 	return func() tea.Msg {
-		// Run git worktree prune to remove missing worktrees
-		cmd := exec.Command("git", "worktree", "prune", "--verbose")
-		output, err := cmd.CombinedOutput()
+		worktreeManager := core.NewWorktreeManager(gitRepo, configManager)
+		pruned, err := worktreeManager.PruneWorktrees(context.Background(), core.PruneOptions{})
 		if err != nil {
 			return pruneCompleteMsg{err: fmt.Errorf("failed to prune worktrees: %w", err)}
 		}
 
-		// Parse the output to count and list pruned worktrees
-		var prunedPaths []string
-		outputStr := string(output)
-		lines := strings.Split(strings.TrimSpace(outputStr), "\n")
-
-		for _, line := range lines {
-			line = strings.TrimSpace(line)
-			if line != "" && strings.Contains(line, "Removing") {
-				// Extract path from "Removing worktrees/path: gitdir file points to non-existent location"
-				parts := strings.SplitN(line, ":", 2)
-				if len(parts) > 0 {
-					path := strings.TrimSpace(strings.TrimPrefix(parts[0], "Removing"))
-					if path != "" {
-						prunedPaths = append(prunedPaths, path)
-					}
-				}
-			}
+		prunedPaths := make([]string, len(pruned))
+		for i, p := range pruned {
+			prunedPaths[i] = p.Name
 		}
-
 		return pruneCompleteMsg{
 			err:         nil,
 			prunedCount: len(prunedPaths),
@@ -1021,6 +943,107 @@ func (m Model) pruneWorktrees() tea.Cmd {
 	}
 }
 
+// measureCleanupSizes measures the disk usage of the stale worktrees in the
+// background, so the confirmation can show what cleanup frees without
+// waiting on the walk.
+func (m Model) measureCleanupSizes(worktrees []Worktree) tea.Cmd {
+	paths := make([]string, len(worktrees))
+	for i, wt := range worktrees {
+		paths[i] = wt.Path
+	}
+	return func() tea.Msg {
+		return cleanupSizesMsg{sizes: core.WorktreeDiskUsages(paths)}
+	}
+}
+
+// measureSelectedDiskUsage starts measuring the selected worktree for the
+// preview when its size isn't cached or already being measured, and
+// returns nil otherwise. The walk is slow on large worktrees, so only the
+// worktrees actually looked at are measured.
+func (m *Model) measureSelectedDiskUsage() tea.Cmd {
+	if m.currentView != DashboardView {
+		return nil
+	}
+	wt := m.getSelectedWorktree()
+	if wt == nil {
+		return nil
+	}
+	if _, ok := m.diskUsage[wt.Path]; ok || m.diskUsageLoading[wt.Path] {
+		return nil
+	}
+	if m.diskUsageLoading == nil {
+		m.diskUsageLoading = make(map[string]bool)
+	}
+	m.diskUsageLoading[wt.Path] = true
+	path := wt.Path
+	// Worktrees nested inside this one are not part of its size
+	others := make([]string, len(m.worktrees))
+	for i, other := range m.worktrees {
+		others[i] = other.Path
+	}
+	return tea.Batch(m.githubSpinner.Tick, func() tea.Msg {
+		size, err := core.WorktreeDiskUsage(path, others...)
+		return diskUsageMsg{path: path, size: size, err: err}
+	})
+}
+
+// loadSelectedDiffStat starts loading the changed files of the selected
+// worktree for the preview when it is dirty and they aren't cached or
+// already loading, and returns nil otherwise. Moving the selection back
+// and forth reuses the cached result.
+func (m *Model) loadSelectedDiffStat() tea.Cmd {
+	if m.currentView != DashboardView {
+		return nil
+	}
+	wt := m.getSelectedWorktree()
+	if wt == nil || wt.Loading || wt.StagedCount+wt.ModifiedCount+wt.UntrackedCount == 0 {
+		return nil
+	}
+	if _, ok := m.diffStats[wt.Path]; ok || m.diffStatLoading[wt.Path] {
+		return nil
+	}
+	if m.diffStatLoading == nil {
+		m.diffStatLoading = make(map[string]bool)
+	}
+	m.diffStatLoading[wt.Path] = true
+	path := wt.Path
+	return func() tea.Msg {
+		files, err := core.DiffStat(path)
+		return diffStatMsg{path: path, files: files, err: err}
+	}
+}
+
+// markerRefreshInterval is how often the dashboard rereads the markers.
+// Reading them is a single git config call.
+const markerRefreshInterval = 3 * time.Second
+
+// loadMarkers reads the Claude activity markers in the background. The
+// marker_ttl expiry applies, so a crashed session shows as idle; the config
+// is only loaded for it on the first call.
+func (m Model) loadMarkers() tea.Cmd {
+	gitRepo := m.gitRepo
+	configManager := m.configManager
+	mm := m.markerManager
+	return func() tea.Msg {
+		if mm == nil {
+			mm = core.NewWorktreeManager(gitRepo, configManager).MarkerManager()
+		}
+		markers, err := mm.ListMarkers(context.Background())
+		if err != nil {
+			logging.Debug("loadMarkers: %v", err)
+			return markersLoadedMsg{markerManager: mm}
+		}
+		return markersLoadedMsg{markers: markers, markerManager: mm}
+	}
+}
+
+// scheduleMarkerRefresh asks for the next marker reload.
+func scheduleMarkerRefresh() tea.Cmd {
+	return tea.Tick(markerRefreshInterval, func(time.Time) tea.Msg {
+		return markerRefreshMsg{}
+	})
+}
+
 // cleanupStaleWorktrees initiates the cleanup and sends start message
 func (m Model) cleanupStaleWorktrees() tea.Cmd {
 	return func() tea.Msg {
@@ -1057,83 +1080,39 @@ func (m Model) deleteNextWorktree(index int) tea.Cmd {
 		wt := m.cleanupState.staleWorktrees[index]
 		logging.Debug("deleteNextWorktree: deleting index %d: %s (%s)", index, wt.Name, wt.Path)
 
-		// 1. Remove symlinks that point outside the worktree (they cause issues with git worktree remove)
-		entries, err := os.ReadDir(wt.Path)
-		if err == nil {
-			for _, entry := range entries {
-				entryPath := filepath.Join(wt.Path, entry.Name())
-				if info, err := os.Lstat(entryPath); err == nil && info.Mode()&os.ModeSymlink != 0 {
-					target, err := os.Readlink(entryPath)
-					if err == nil {
-						if !filepath.IsAbs(target) {
-							target = filepath.Join(wt.Path, target)
-						}
-						absTarget, _ := filepath.Abs(target)
-						absWorktree, _ := filepath.Abs(wt.Path)
-						if !strings.HasPrefix(absTarget, absWorktree) {
-							logging.Debug("deleteNextWorktree: removing external symlink: %s -> %s", entryPath, absTarget)
-							os.Remove(entryPath)
-						}
-					}
-				}
-			}
-		}
-
-		// 2. Deinit submodules if present (required before git worktree remove can succeed)
-		hasSubmodules := wt.HasSubmodules
-		if !hasSubmodules {
-			// Double-check in case it wasn't detected earlier
-			if _, err := os.Stat(filepath.Join(wt.Path, ".gitmodules")); err == nil {
-				hasSubmodules = true
+		// A rebase or merge in progress is only discarded with force delete
+		if wt.Operation != "" && !m.cleanupState.forceDelete {
+			logging.Info("deleteNextWorktree: skipping %s, %s in progress", wt.Name, wt.Operation)
+			return cleanupItemCompleteMsg{
+				worktreeIndex: index,
+				worktreeName:  wt.Branch,
+				success:       false,
+				errorMsg:      wt.Operation + " in progress (finish or abort it, or force delete)",
 			}
 		}
 
-		if hasSubmodules {
-			logging.Debug("deleteNextWorktree: worktree has submodules, running deinit")
-			deinitCmd := exec.Command("git", "-C", wt.Path, "submodule", "deinit", "--all", "--force")
-			deinitOutput, err := deinitCmd.CombinedOutput()
-			if err != nil {
-				logging.Error("deleteNextWorktree: failed to deinit submodules: %v, output: %s", err, string(deinitOutput))
-				return cleanupItemCompleteMsg{
-					worktreeIndex: index,
-					worktreeName:  wt.Branch,
-					success:       false,
-					errorMsg:      "submodule deinit failed",
-				}
+		// Nor is a worktree whose status git couldn't read: it may hold work
+		if wt.StatusError != "" && !m.cleanupState.forceDelete {
+			logging.Info("deleteNextWorktree: skipping %s, status unknown: %s", wt.Name, wt.StatusError)
+			return cleanupItemCompleteMsg{
+				worktreeIndex: index,
+				worktreeName:  wt.Branch,
+				success:       false,
+				errorMsg:      "status unknown (retry, or force delete)",
 			}
-			logging.Debug("deleteNextWorktree: submodules deinited successfully")
-		}
-
-		// 3. Perform deletion using git worktree remove
-		// Note: --force is required for worktrees with submodules (even after deinit)
-		useForce := hasSubmodules || m.cleanupState.forceDelete
-		args := []string{"worktree", "remove"}
-		if useForce {
-			args = append(args, "--force")
-			logging.Debug("deleteNextWorktree: using --force flag (hasSubmodules=%v, forceDelete=%v)",
-				hasSubmodules, m.cleanupState.forceDelete)
 		}
-		args = append(args, wt.Path)
-		cmd := exec.Command("git", args...)
-		output, err := cmd.CombinedOutput()
 
-		// Build completion message
-		if err != nil {
-			logging.Error("deleteNextWorktree: failed to delete %s: %v (output: %s)", wt.Name, err, string(output))
-
-			// Parse error reason for user-friendly message
-			outputStr := string(output)
-			var reason string
-			if strings.Contains(outputStr, "submodules") {
-				reason = "has submodules (try force delete)"
-			} else if strings.Contains(outputStr, "modified or untracked files") {
-				reason = "has uncommitted changes"
-			} else if strings.Contains(outputStr, "is not a working tree") {
-				reason = "not a valid worktree"
-			} else {
-				reason = "deletion failed"
+		worktreeManager := core.NewWorktreeManager(m.gitRepo, m.configManager)
+		if _, err := worktreeManager.RemoveWorktree(context.Background(), convertUIWorktreeToCore(wt), m.cleanupState.forceDelete); err != nil {
+			logging.Error("deleteNextWorktree: failed to delete %s: %v", wt.Name, err)
+			// Lists show the short reason; refusals (main, current) say it all
+			reason := err.Error()
+			var removeErr *core.RemoveError
+			if errors.As(err, &removeErr) {
+				reason = removeErr.Reason
+			} else if errors.Is(err, core.ErrRepoLocked) {
+				reason = "another gren process is changing the repository"
 			}
-
 			return cleanupItemCompleteMsg{
 				worktreeIndex: index,
 				worktreeName:  wt.Branch,
@@ -1193,28 +1172,17 @@ func (m Model) generateAISetupScript() tea.Cmd {
 	return func() tea.Msg {
 		logging.Info("Starting AI setup script generation")
 
-		// Check if Claude CLI is available
-		claudePath, err := exec.LookPath("claude")
+		// Claude Code is preferred; otherwise the [commit-generation] LLM
+		var cfg *config.Config
+		if m.configManager != nil {
+			cfg, _ = m.configManager.Load()
+		}
+		cwd, _ := os.Getwd()
+		generator, err := core.NewSetupScriptGenerator(cfg, cwd)
 		if err != nil {
-			// Try common locations
-			possiblePaths := []string{
-				"/usr/local/bin/claude",
-				os.ExpandEnv("$HOME/.local/bin/claude"),
-				"/opt/homebrew/bin/claude",
-			}
-			for _, p := range possiblePaths {
-				if _, err := os.Stat(p); err == nil {
-					claudePath = p
-					break
-				}
-			}
-			if claudePath == "" {
-				return aiScriptGeneratedMsg{err: fmt.Errorf("Claude Code CLI not found.\n\nInstall it from: https://claude.ai/code\n\nAlternatively, choose 'Customize settings' to manually create a setup script.")}
-			}
+			return aiScriptGeneratedMsg{err: err}
 		}
 
-		logging.Debug("Found Claude CLI at: %s", claudePath)
-
 		// Build context header with TUI-detected info
 		var contextHeader strings.Builder
 		contextHeader.WriteString("# Project context (pre-detected by gren TUI)\n\n")
@@ -1247,50 +1215,18 @@ func (m Model) generateAISetupScript() tea.Cmd {
 		// Combine context header with skill prompt
 		prompt := contextHeader.String() + skills.GetGrenSetupPrompt()
 
-		// Run Claude CLI with prompt via stdin (avoids CLI argument length limits)
-		// Scope Bash to read-only operations to prevent Claude from writing files
-		cmd := exec.Command(claudePath, "-p",
-			"--allowedTools", "Read", "Glob", "Grep",
-			"Bash(git check-ignore:*)", "Bash(git ls-files:*)", "Bash(ls:*)", "Bash(cat:*)",
-		)
-		cmd.Dir, _ = os.Getwd()
-		cmd.Stdin = strings.NewReader(prompt)
-
-		output, err := cmd.Output()
+		// The prompt goes via stdin (avoids CLI argument length limits), and
+		// Claude is scoped to read-only tools so it can't write files
+		output, err := generator.Generate(context.Background(), prompt)
 		if err != nil {
-			if exitErr, ok := err.(*exec.ExitError); ok {
-				logging.Error("Claude CLI failed: %v, stderr: %s", err, string(exitErr.Stderr))
-				return aiScriptGeneratedMsg{err: fmt.Errorf("Claude CLI failed: %s", string(exitErr.Stderr))}
-			}
-			logging.Error("Claude CLI failed: %v", err)
-			return aiScriptGeneratedMsg{err: fmt.Errorf("Claude CLI failed: %v", err)}
+			logging.Error("AI setup script generation failed: %v", err)
+			return aiScriptGeneratedMsg{err: err}
 		}
 
-		script := strings.TrimSpace(string(output))
-
-		// Basic validation - should start with shebang
-		if !strings.HasPrefix(script, "#!/") {
-			// Try to extract script from response if Claude added explanation
-			lines := strings.Split(script, "\n")
-			inScript := false
-			var scriptLines []string
-			for _, line := range lines {
-				if strings.HasPrefix(line, "#!/") {
-					inScript = true
-				}
-				if inScript {
-					scriptLines = append(scriptLines, line)
-				}
-			}
-			if len(scriptLines) > 0 {
-				// Strip trailing markdown fences if present
-				for len(scriptLines) > 0 && strings.TrimSpace(scriptLines[len(scriptLines)-1]) == "```" {
-					scriptLines = scriptLines[:len(scriptLines)-1]
-				}
-				script = strings.TrimRight(strings.Join(scriptLines, "\n"), "\n")
-			} else {
-				script = "#!/bin/bash\n\n# AI-generated script\n" + script
-			}
+		script, warning := extractSetupScript(output)
+		if warning != "" {
+			logging.Warn("AI setup script failed validation: %s", warning)
+			return aiScriptGeneratedMsg{script: script, warning: warning}
 		}
 
 		logging.Info("AI script generated successfully")
@@ -1323,7 +1259,7 @@ func (m Model) refreshAllStatus() tea.Cmd {
 		if ghStatus == core.GitHubAvailable {
 			logging.Info("refreshAllStatus: GitHub CLI available, fetching PR status")
 			worktreeManager.EnrichWithGitHubStatus(worktrees)
-			worktreeManager.EnrichWithCIStatus(worktrees)
+			worktreeManager.EnrichWithCIStatus(ctx, worktrees)
 		} else {
 			logging.Debug("refreshAllStatus: GitHub CLI not available, skipping PR status")
 		}
@@ -1359,7 +1295,7 @@ func (m Model) startGitHubCheck() tea.Cmd {
 		ghStatus := worktreeManager.CheckGitHubAvailability()
 		if ghStatus != core.GitHubAvailable {
 			logging.Debug("startGitHubCheck: GitHub CLI not available, skipping")
-			return githubRefreshCompleteMsg{worktrees: currentWorktrees, ghStatus: ghStatus}
+			return githubRefreshCompleteMsg{worktrees: currentWorktrees, ghStatus: ghStatus, prOnly: true}
 		}
 
 		logging.Info("startGitHubCheck: GitHub CLI available, fetching PR status")
@@ -1367,27 +1303,12 @@ func (m Model) startGitHubCheck() tea.Cmd {
 		// Convert UI worktrees to core worktrees for enrichment
 		coreWorktrees := make([]core.WorktreeInfo, len(currentWorktrees))
 		for i, wt := range currentWorktrees {
-			coreWorktrees[i] = core.WorktreeInfo{
-				Name:           wt.Name,
-				Path:           wt.Path,
-				Branch:         wt.Branch,
-				Status:         wt.Status,
-				IsCurrent:      wt.IsCurrent,
-				IsMain:         wt.IsMain,
-				LastCommit:     wt.LastCommit,
-				StagedCount:    wt.StagedCount,
-				ModifiedCount:  wt.ModifiedCount,
-				UntrackedCount: wt.UntrackedCount,
-				UnpushedCount:  wt.UnpushedCount,
-				HasSubmodules:  wt.HasSubmodules,
-				BranchStatus:   wt.BranchStatus,
-				StaleReason:    wt.StaleReason,
-			}
+			coreWorktrees[i] = convertUIWorktreeToCore(wt)
 		}
 
 		// Enrich with GitHub status
 		worktreeManager.EnrichWithGitHubStatus(coreWorktrees)
-		worktreeManager.EnrichWithCIStatus(coreWorktrees)
+		worktreeManager.EnrichWithCIStatus(context.Background(), coreWorktrees)
 
 		// Convert back to UI worktrees
 		uiWorktrees := make([]Worktree, len(coreWorktrees))
@@ -1395,10 +1316,52 @@ func (m Model) startGitHubCheck() tea.Cmd {
 			uiWorktrees[i] = convertCoreWorktreeToUI(wt)
 		}
 
-		return githubRefreshCompleteMsg{worktrees: uiWorktrees, ghStatus: ghStatus}
+		return githubRefreshCompleteMsg{worktrees: uiWorktrees, ghStatus: ghStatus, prOnly: true}
 	}
 }
 
+// loadWorktreeDetails loads the status of each worktree listed by
+// loadWorktreesBasic, one command per worktree so rows fill in as soon as
+// their own git calls finish, plus one for the stale check of all of them.
+func (m Model) loadWorktreeDetails() tea.Cmd {
+	gitRepo := m.gitRepo
+	configManager := m.configManager
+	generation := m.loadGeneration
+
+	coreWorktrees := make([]core.WorktreeInfo, len(m.worktrees))
+	for i, wt := range m.worktrees {
+		coreWorktrees[i] = core.WorktreeInfo{
+			Name:      wt.Name,
+			Path:      wt.Path,
+			Branch:    wt.Branch,
+			Status:    wt.Status,
+			IsCurrent: wt.IsCurrent,
+			IsMain:    wt.IsMain,
+			IsBare:    wt.IsBare,
+		}
+	}
+
+	var cmds []tea.Cmd
+	for _, wt := range coreWorktrees {
+		if wt.Status == "missing" {
+			continue
+		}
+		cmds = append(cmds, func() tea.Msg {
+			core.NewWorktreeManager(gitRepo, configManager).EnrichStatus(&wt)
+			return worktreeStatusMsg{generation: generation, worktree: wt}
+		})
+	}
+	cmds = append(cmds, func() tea.Msg {
+		core.NewWorktreeManager(gitRepo, configManager).EnrichStaleStatus(coreWorktrees)
+		stale := make(map[string]core.WorktreeInfo, len(coreWorktrees))
+		for _, wt := range coreWorktrees {
+			stale[wt.Path] = wt
+		}
+		return worktreeStaleMsg{generation: generation, stale: stale}
+	})
+	return tea.Batch(cmds...)
+}
+
 // openPRInBrowser opens the PR for a branch in the default browser
 func (m Model) openPRInBrowser(branch string) tea.Cmd {
 	// Capture dependencies for the closure
@@ -1431,20 +1394,6 @@ func (m Model) initializeCompareState(sourceWorktree string) tea.Cmd {
 		worktreeManager := core.NewWorktreeManager(gitRepo, configManager)
 		ctx := context.Background()
 
-		// Get source worktree path
-		worktrees, err := worktreeManager.ListWorktrees(ctx)
-		if err != nil {
-			return compareInitMsg{err: err}
-		}
-
-		var sourcePath string
-		for _, wt := range worktrees {
-			if wt.Name == sourceWorktree || wt.Branch == sourceWorktree {
-				sourcePath = wt.Path
-				break
-			}
-		}
-
 		result, err := worktreeManager.CompareWorktrees(ctx, sourceWorktree)
 		if err != nil {
 			logging.Error("initializeCompareState: failed: %v", err)
@@ -1464,7 +1413,8 @@ func (m Model) initializeCompareState(sourceWorktree string) tea.Cmd {
 
 		return compareInitMsg{
 			sourceWorktree: sourceWorktree,
-			sourcePath:     sourcePath,
+			sourcePath:     result.SourcePath,
+			targetPath:     result.TargetPath,
 			files:          files,
 		}
 	}
@@ -1481,10 +1431,20 @@ func (m Model) applyCompareChanges() tea.Cmd {
 
 	sourceWorktree := m.compareState.sourceWorktree
 	selectedFiles := make([]CompareFileItem, 0)
+	// Files with only some hunks selected are applied as a patch of those
+	// hunks instead of being copied whole.
+	var partialHunks []core.Hunk
+	partialFiles := make(map[string]bool)
 	for _, f := range m.compareState.files {
-		if f.Selected {
-			selectedFiles = append(selectedFiles, f)
+		if !f.Selected {
+			continue
+		}
+		if hunks := m.compareState.selectedHunks(f.Path); hunks != nil {
+			partialHunks = append(partialHunks, hunks...)
+			partialFiles[f.Path] = true
+			continue
 		}
+		selectedFiles = append(selectedFiles, f)
 	}
 
 	// Capture dependencies
@@ -1492,11 +1452,11 @@ func (m Model) applyCompareChanges() tea.Cmd {
 	configManager := m.configManager
 
 	return func() tea.Msg {
-		if len(selectedFiles) == 0 {
+		if len(selectedFiles) == 0 && len(partialHunks) == 0 {
 			return compareApplyCompleteMsg{appliedCount: 0}
 		}
 
-		logging.Info("applyCompareChanges: applying %d files from %s", len(selectedFiles), sourceWorktree)
+		logging.Info("applyCompareChanges: applying %d files and %d hunks from %s", len(selectedFiles), len(partialHunks), sourceWorktree)
 
 		worktreeManager := core.NewWorktreeManager(gitRepo, configManager)
 		ctx := context.Background()
@@ -1529,7 +1489,12 @@ func (m Model) applyCompareChanges() tea.Cmd {
 			return compareApplyCompleteMsg{err: err}
 		}
 
-		return compareApplyCompleteMsg{appliedCount: len(coreFiles)}
+		if err := worktreeManager.ApplyHunks(ctx, sourceWorktree, partialHunks); err != nil {
+			logging.Error("applyCompareChanges: applying hunks failed: %v", err)
+			return compareApplyCompleteMsg{err: err}
+		}
+
+		return compareApplyCompleteMsg{appliedCount: len(coreFiles) + len(partialFiles)}
 	}
 }
 
@@ -1629,6 +1594,10 @@ func (m Model) generateLLMMessage() tea.Cmd {
 	}
 
 	configManager := m.configManager
+	var pathspec []string
+	if paths := m.stepCommitState.selectedPaths(); len(paths) > 0 {
+		pathspec = append([]string{"--"}, paths...)
+	}
 
 	return func() tea.Msg {
 		logging.Info("generateLLMMessage: generating commit message with LLM")
@@ -1639,20 +1608,24 @@ func (m Model) generateLLMMessage() tea.Cmd {
 			return llmMessageGeneratedMsg{err: fmt.Errorf("failed to load config: %w", err)}
 		}
 
-		// Check if LLM is configured
-		if cfg.CommitGenerator.Command == "" {
-			return llmMessageGeneratedMsg{err: fmt.Errorf("LLM not configured. Set [commit-generation] command in config")}
+		// Check if LLM is configured, by the project or the user config
+		llm := core.LLMConfig(cfg)
+		if !llm.Configured() {
+			return llmMessageGeneratedMsg{err: core.ErrLLMNotConfigured}
+		}
+		if _, err := core.NewCommitMessageGenerator(llm); err != nil {
+			return llmMessageGeneratedMsg{err: err}
 		}
 
 		// Get the staged diff
-		diff, err := exec.Command("git", "diff", "--cached").Output()
+		diff, err := exec.Command("git", append([]string{"diff", "--cached"}, pathspec...)...).Output()
 		if err != nil {
 			return llmMessageGeneratedMsg{err: fmt.Errorf("failed to get staged diff: %w", err)}
 		}
 
 		if len(diff) == 0 {
 			// Try unstaged diff if nothing staged
-			diff, err = exec.Command("git", "diff").Output()
+			diff, err = exec.Command("git", append([]string{"diff"}, pathspec...)...).Output()
 			if err != nil {
 				return llmMessageGeneratedMsg{err: fmt.Errorf("failed to get diff: %w", err)}
 			}
@@ -1663,7 +1636,9 @@ func (m Model) generateLLMMessage() tea.Cmd {
 		}
 
 		// Create LLM generator
-		generator := core.NewLLMGenerator(cfg)
+		llmCfg := *cfg
+		llmCfg.CommitGenerator = llm
+		generator := core.NewLLMGenerator(&llmCfg)
 
 		// Generate the commit message
 		message, err := generator.GenerateCommitMessage(string(diff), "")
@@ -1675,6 +1650,16 @@ func (m Model) generateLLMMessage() tea.Cmd {
 	}
 }
 
+// loadChangedFiles lists the files step commit can be limited to.
+func (m Model) loadChangedFiles() tea.Cmd {
+	gitRepo := m.gitRepo
+	configManager := m.configManager
+	return func() tea.Msg {
+		files, err := core.NewWorktreeManager(gitRepo, configManager).ChangedFiles()
+		return changedFilesLoadedMsg{files: files, err: err}
+	}
+}
+
 func (m Model) executeStepCommit() tea.Cmd {
 	if m.stepCommitState == nil {
 		return func() tea.Msg {
@@ -1685,15 +1670,17 @@ func (m Model) executeStepCommit() tea.Cmd {
 	gitRepo := m.gitRepo
 	configManager := m.configManager
 	message := m.stepCommitState.message
+	paths := m.stepCommitState.selectedPaths()
 
 	return func() tea.Msg {
-		logging.Info("executeStepCommit: committing changes")
+		logging.Info("executeStepCommit: committing changes (paths=%v)", paths)
 
 		worktreeManager := core.NewWorktreeManager(gitRepo, configManager)
 
 		opts := core.StepCommitOptions{
 			Message: message,
 			UseLLM:  false, // Message is already set, no need for LLM
+			Paths:   paths,
 		}
 
 		err := worktreeManager.StepCommit(opts)
@@ -1705,22 +1692,20 @@ func (m Model) executeStepCommit() tea.Cmd {
 	}
 }
 
-func (m Model) loadCompareDiff(sourcePath string, filePath string) tea.Cmd {
+func (m Model) loadCompareDiff(sourcePath, targetPath, filePath string) tea.Cmd {
 	return func() tea.Msg {
 		logging.Info("loadCompareDiff: loading diff for %s from %s", filePath, sourcePath)
 
 		if sourcePath == "" {
-			return compareDiffLoadedMsg{content: "(Source path not available)", err: nil}
+			return compareDiffLoadedMsg{path: filePath, content: "(Source path not available)", err: nil}
 		}
 
-		// Get current working directory (current worktree)
-		cwd, err := os.Getwd()
-		if err != nil {
-			return compareDiffLoadedMsg{content: "(Could not get current directory)", err: nil}
+		if targetPath == "" {
+			return compareDiffLoadedMsg{path: filePath, content: "(Target path not available)", err: nil}
 		}
 
 		sourceFile := filepath.Join(sourcePath, filePath)
-		currentFile := filepath.Join(cwd, filePath)
+		currentFile := filepath.Join(targetPath, filePath)
 
 		// Check if source file exists
 		sourceExists := true
@@ -1745,18 +1730,14 @@ func (m Model) loadCompareDiff(sourcePath string, filePath string) tea.Cmd {
 			data, err := os.ReadFile(sourceFile)
 			if err != nil {
 				content = fmt.Sprintf("(Could not read file: %v)", err)
+			} else if output.IsBinary(data) {
+				content = strings.Join([]string{
+					fmt.Sprintf("diff --git a/%s b/%s", filePath, filePath),
+					"new file",
+					output.BinaryDiffMarker,
+				}, "\n")
 			} else {
-				// Format as new file diff
-				lines := strings.Split(string(data), "\n")
-				var diffLines []string
-				diffLines = append(diffLines, fmt.Sprintf("diff --git a/%s b/%s", filePath, filePath))
-				diffLines = append(diffLines, "new file")
-				diffLines = append(diffLines, fmt.Sprintf("+++ b/%s", filePath))
-				diffLines = append(diffLines, "@@ -0,0 +1,"+fmt.Sprintf("%d", len(lines))+" @@")
-				for _, line := range lines {
-					diffLines = append(diffLines, "+"+line)
-				}
-				content = strings.Join(diffLines, "\n")
+				content = newFileDiff(filePath, string(data))
 			}
 		} else {
 			// Both files exist - use git diff for cross-platform compatibility
@@ -1769,10 +1750,42 @@ func (m Model) loadCompareDiff(sourcePath string, filePath string) tea.Cmd {
 			}
 		}
 
-		return compareDiffLoadedMsg{content: content, err: nil}
+		// Hunks drive per-hunk selection; without them (binary, deleted,
+		// unreadable) the file can still be applied whole.
+		var hunks []core.Hunk
+		if sourceExists {
+			var err error
+			if hunks, err = core.DiffHunks(targetPath, sourcePath, filePath); err != nil {
+				logging.Debug("loadCompareDiff: no hunks for %s: %v", filePath, err)
+			}
+		}
+
+		return compareDiffLoadedMsg{path: filePath, content: content, hunks: hunks, err: nil}
 	}
 }
 
+// newFileDiff formats the contents of a file that only exists in the source
+// worktree as a unified diff adding every line, including git's marker when
+// the file has no trailing newline.
+func newFileDiff(filePath, data string) string {
+	lines := strings.Split(strings.TrimSuffix(data, "\n"), "\n")
+	if data == "" {
+		lines = nil
+	}
+	var diffLines []string
+	diffLines = append(diffLines, fmt.Sprintf("diff --git a/%s b/%s", filePath, filePath))
+	diffLines = append(diffLines, "new file")
+	diffLines = append(diffLines, fmt.Sprintf("+++ b/%s", filePath))
+	diffLines = append(diffLines, fmt.Sprintf("@@ -0,0 +1,%d @@", len(lines)))
+	for _, line := range lines {
+		diffLines = append(diffLines, "+"+line)
+	}
+	if data != "" && !strings.HasSuffix(data, "\n") {
+		diffLines = append(diffLines, `\ No newline at end of file`)
+	}
+	return strings.Join(diffLines, "\n")
+}
+
 // clearStatusAfter returns a command that clears the status message after a delay
 func clearStatusAfter(duration time.Duration) tea.Cmd {
 	return tea.Tick(duration, func(t time.Time) tea.Msg {
@@ -4,7 +4,11 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/langtind/gren/internal/config"
 )
 
 func TestGetAvailableBranchesForWorktree_RemoteBranches(t *testing.T) {
@@ -283,3 +287,51 @@ func TestGetAvailableBranchesForWorktree_EmptyRepo(t *testing.T) {
 		t.Logf("Empty repo returns error (expected): %v", err)
 	}
 }
+
+func TestSwitchToWorktreeWritesDirective(t *testing.T) {
+	directiveFile := filepath.Join(t.TempDir(), "directive")
+	t.Setenv("GREN_DIRECTIVE_FILE", directiveFile)
+	worktreePath := t.TempDir()
+
+	msg := Model{}.switchToWorktree("feature", worktreePath)()
+	nav, ok := msg.(navigateCompleteMsg)
+	if !ok || nav.err != nil || nav.newTerminal {
+		t.Fatalf("switchToWorktree with shell integration = %#v, want a navigate message", msg)
+	}
+	data, err := os.ReadFile(directiveFile)
+	if err != nil || !strings.Contains(string(data), "cd \""+worktreePath+"\"") {
+		t.Errorf("directive = %q (%v), want a cd into %s", data, err, worktreePath)
+	}
+}
+
+func TestSwitchToWorktreeOpensTerminalWithoutIntegration(t *testing.T) {
+	t.Setenv("GREN_DIRECTIVE_FILE", "")
+	worktreePath := t.TempDir()
+
+	m := Model{currentView: OpenInView, config: &config.Config{TerminalCommand: "touch opened"}}
+	msg := m.switchToWorktree("feature", worktreePath)()
+	nav, ok := msg.(navigateCompleteMsg)
+	if !ok || nav.err != nil || !nav.newTerminal {
+		t.Fatalf("switchToWorktree without shell integration = %#v, want a new terminal", msg)
+	}
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		if _, err := os.Stat(filepath.Join(worktreePath, "opened")); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("terminal command did not run in the worktree")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// The TUI stays open, back on the dashboard
+	updated, cmd := m.Update(nav)
+	um := updated.(Model)
+	if um.currentView != DashboardView || !strings.Contains(um.statusMessage, "new terminal") || cmd == nil {
+		t.Errorf("after opening a terminal: view %v, status %q, want the dashboard and a note", um.currentView, um.statusMessage)
+	}
+	if um.ExitMessage != "" {
+		t.Errorf("ExitMessage = %q, want the TUI to stay open", um.ExitMessage)
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/langtind/gren/internal/core"
+	"github.com/langtind/gren/internal/output"
 )
 
 // renderCompareView renders the compare worktrees view with split layout
@@ -42,16 +44,6 @@ func (m Model) renderCompareView() string {
 	deletedStyle := lipgloss.NewStyle().
 		Foreground(ColorError)
 
-	diffAddStyle := lipgloss.NewStyle().
-		Foreground(ColorSuccess)
-
-	diffDelStyle := lipgloss.NewStyle().
-		Foreground(ColorError)
-
-	diffHeaderStyle := lipgloss.NewStyle().
-		Foreground(ColorSecondary).
-		Bold(true)
-
 	dimStyle := lipgloss.NewStyle().
 		Foreground(ColorTextMuted)
 
@@ -83,29 +75,7 @@ func (m Model) renderCompareView() string {
 		return b.String()
 	}
 
-	// Calculate dimensions - fixed layout
-	totalWidth := m.width
-	if totalWidth < 80 {
-		totalWidth = 80
-	}
-	// Reserve: 1 title, 1 blank, panels, 1 selection count, 1 footer = 4 lines
-	panelHeight := m.height - 4
-	if panelHeight < 10 {
-		panelHeight = 10
-	}
-
-	// Left panel (file list) gets 35% width, right panel (diff) gets 65%
-	leftWidth := totalWidth * 35 / 100
-	if leftWidth < 30 {
-		leftWidth = 30
-	}
-	rightWidth := totalWidth - leftWidth - 4 // Account for borders and spacing
-
-	// Content height inside panels (subtract border and header)
-	contentHeight := panelHeight - 4
-	if contentHeight < 3 {
-		contentHeight = 3
-	}
+	leftWidth, rightWidth, panelHeight, contentHeight := compareLayout(m.width, m.height)
 
 	// Build left panel (file list)
 	var leftLines []string
@@ -134,6 +104,9 @@ func (m Model) renderCompareView() string {
 		checkbox := "○"
 		if file.Selected {
 			checkbox = "●"
+			if state.selectedHunks(file.Path) != nil {
+				checkbox = "◐" // only some hunks
+			}
 		}
 
 		// Status icon and color
@@ -200,57 +173,13 @@ func (m Model) renderCompareView() string {
 	rightLines = append(rightLines, dimStyle.Render(diffLabel))
 	rightLines = append(rightLines, strings.Repeat("─", rightWidth-4))
 
-	visibleDiffLines := contentHeight - 2 // Subtract header lines
-	if visibleDiffLines < 1 {
-		visibleDiffLines = 1
-	}
-
 	if state.diffContent == "" {
 		rightLines = append(rightLines, dimStyle.Render("Loading diff..."))
 	} else {
-		// Render diff with syntax highlighting
-		diffLines := strings.Split(state.diffContent, "\n")
-
-		startLine := state.diffScrollOffset
-		endLine := startLine + visibleDiffLines
-		if endLine > len(diffLines) {
-			endLine = len(diffLines)
-		}
-
-		for i := startLine; i < endLine; i++ {
-			line := diffLines[i]
-			// Truncate long lines using rune-aware width calculation
-			maxWidth := rightWidth - 4
-			if lipgloss.Width(line) > maxWidth {
-				// Truncate by runes, not bytes, to avoid splitting UTF-8 characters
-				runes := []rune(line)
-				if len(runes) > maxWidth-3 {
-					line = string(runes[:maxWidth-3]) + "..."
-				}
-			}
-
-			// Color based on diff type
-			if strings.HasPrefix(line, "+") && !strings.HasPrefix(line, "+++") {
-				rightLines = append(rightLines, diffAddStyle.Render(line))
-			} else if strings.HasPrefix(line, "-") && !strings.HasPrefix(line, "---") {
-				rightLines = append(rightLines, diffDelStyle.Render(line))
-			} else if strings.HasPrefix(line, "@@") {
-				rightLines = append(rightLines, diffHeaderStyle.Render(line))
-			} else if strings.HasPrefix(line, "diff ") || strings.HasPrefix(line, "index ") ||
-				strings.HasPrefix(line, "---") || strings.HasPrefix(line, "+++") {
-				rightLines = append(rightLines, diffHeaderStyle.Render(line))
-			} else {
-				rightLines = append(rightLines, line)
-			}
-		}
-
-		// Show scroll indicator for diff
-		if len(diffLines) > visibleDiffLines {
-			scrollInfo := fmt.Sprintf("[line %d/%d]", startLine+1, len(diffLines))
-			// Pad and add scroll info
-			for len(rightLines) < contentHeight-1 {
-				rightLines = append(rightLines, "")
-			}
+		rightLines = append(rightLines, state.diffViewport.View())
+		vp := state.diffViewport
+		if vp.TotalLineCount() > vp.Height {
+			scrollInfo := fmt.Sprintf("[line %d/%d]", vp.YOffset+1, vp.TotalLineCount())
 			rightLines = append(rightLines, dimStyle.Render(scrollInfo))
 		}
 	}
@@ -301,9 +230,10 @@ func (m Model) renderCompareView() string {
 
 	var footer string
 	if state.diffFocused {
-		nav := HelpItem("↑↓jk", "scroll")
+		nav := HelpItem("↑↓jk", "scroll") + " " + HelpItem("pgup/pgdn", "page")
+		hunks := HelpItem("n/p", "hunk") + " " + HelpItem("space", "toggle hunk")
 		other := HelpItem("←h", "back") + " " + HelpItem("?", "help") + " " + HelpItem("esc", "exit")
-		footer = nav + sep + other
+		footer = nav + sep + hunks + sep + other
 	} else {
 		nav := HelpItem("↑↓jk", "nav")
 		selection := HelpItem("space", "toggle") + " " + HelpItem("a", "all")
@@ -315,3 +245,177 @@ func (m Model) renderCompareView() string {
 
 	return b.String()
 }
+
+// compareLayout returns the panel dimensions used by renderCompareView. The
+// diff viewport is sized from the same numbers so it always fits its panel.
+func compareLayout(width, height int) (leftWidth, rightWidth, panelHeight, contentHeight int) {
+	totalWidth := width
+	if totalWidth < 80 {
+		totalWidth = 80
+	}
+	// Reserve: 1 title, 1 blank, panels, 1 selection count, 1 footer = 4 lines
+	panelHeight = height - 4
+	if panelHeight < 10 {
+		panelHeight = 10
+	}
+
+	// Left panel (file list) gets 35% width, right panel (diff) gets 65%
+	leftWidth = totalWidth * 35 / 100
+	if leftWidth < 30 {
+		leftWidth = 30
+	}
+	rightWidth = totalWidth - leftWidth - 4 // Account for borders and spacing
+
+	// Content height inside panels (subtract border and header)
+	contentHeight = panelHeight - 4
+	if contentHeight < 3 {
+		contentHeight = 3
+	}
+	return leftWidth, rightWidth, panelHeight, contentHeight
+}
+
+// syncCompareDiff sizes the diff viewport to the current window and loads the
+// colored diff into it. Lines are truncated to the panel width, so this runs
+// again on resize; the scroll position is kept unless resetScroll is set.
+func (m Model) syncCompareDiff(resetScroll bool) {
+	if m.compareState == nil {
+		return
+	}
+	_, rightWidth, _, contentHeight := compareLayout(m.width, m.height)
+
+	vp := &m.compareState.diffViewport
+	vp.Width = rightWidth - 4
+	vp.Height = contentHeight - 3 // label, separator and line indicator
+	if vp.Height < 1 {
+		vp.Height = 1
+	}
+	state := m.compareState
+	vp.SetContent(renderDiffLines(state.diffContent, vp.Width, state.fileHunks[state.diffPath], state.hunkIndex, state.diffFocused))
+	if resetScroll {
+		vp.GotoTop()
+	}
+}
+
+// jumpToHunk makes hunk i of the displayed file current and scrolls it to the
+// top of the diff panel. Hunks cut off by diff truncation are not reachable.
+func (m Model) jumpToHunk(i int) {
+	state := m.compareState
+	offsets := hunkLineOffsets(state.diffContent)
+	if n := len(state.fileHunks[state.diffPath]); n < len(offsets) {
+		offsets = offsets[:n]
+	}
+	if i < 0 || i >= len(offsets) {
+		return
+	}
+	state.hunkIndex = i
+	m.syncCompareDiff(false)
+	state.diffViewport.SetYOffset(offsets[i])
+}
+
+// hunkLineOffsets returns the display line of each "@@" header in a diff.
+func hunkLineOffsets(content string) []int {
+	var offsets []int
+	for i, line := range output.PrepareDiff(content, output.MaxDiffLines) {
+		if output.ClassifyDiffLine(line) == output.DiffHunk {
+			offsets = append(offsets, i)
+		}
+	}
+	return offsets
+}
+
+// selectedHunks returns the selected hunks of path when only some of them are
+// selected, and nil when the file should be applied whole.
+func (s *CompareState) selectedHunks(path string) []core.Hunk {
+	var selected []core.Hunk
+	for _, h := range s.fileHunks[path] {
+		if h.selected {
+			selected = append(selected, h.hunk)
+		}
+	}
+	if len(selected) == 0 || len(selected) == len(s.fileHunks[path]) {
+		return nil
+	}
+	return selected
+}
+
+// setAllHunks selects or deselects every hunk of path, used when the whole
+// file is toggled from the file list.
+func (s *CompareState) setAllHunks(path string, selected bool) {
+	for i := range s.fileHunks[path] {
+		s.fileHunks[path][i].selected = selected
+	}
+}
+
+// toggleHunk flips the current hunk of the displayed file. The file stays
+// selected while any of its hunks is, so the checkbox and apply agree.
+func (s *CompareState) toggleHunk() {
+	hunks := s.fileHunks[s.diffPath]
+	if s.hunkIndex >= len(hunks) {
+		return
+	}
+	hunks[s.hunkIndex].selected = !hunks[s.hunkIndex].selected
+
+	anySelected := false
+	for _, h := range hunks {
+		anySelected = anySelected || h.selected
+	}
+	for i := range s.files {
+		if s.files[i].Path == s.diffPath {
+			s.files[i].Selected = anySelected
+		}
+	}
+}
+
+// renderDiffLines colors a unified diff for the diff panel, truncating each
+// line to maxWidth. Binary notices and oversized diffs are handled by
+// output.PrepareDiff so the CLI and TUI agree on what is shown. When hunks are
+// given, each "@@" header gets its selection checkbox and the current hunk is
+// highlighted while the panel is focused.
+func renderDiffLines(content string, maxWidth int, hunks []compareHunk, current int, focused bool) string {
+	addStyle := lipgloss.NewStyle().Foreground(ColorSuccess)
+	delStyle := lipgloss.NewStyle().Foreground(ColorError)
+	headerStyle := lipgloss.NewStyle().Foreground(ColorSecondary).Bold(true)
+	dimStyle := lipgloss.NewStyle().Foreground(ColorTextMuted)
+	currentHunkStyle := lipgloss.NewStyle().Background(ColorBgSelected).Foreground(ColorTextPrimary)
+
+	hunk := 0
+	lines := output.PrepareDiff(content, output.MaxDiffLines)
+	for i, line := range lines {
+		kind := output.ClassifyDiffLine(line)
+		if kind == output.DiffHunk && hunk < len(hunks) {
+			checkbox := "○ "
+			if hunks[hunk].selected {
+				checkbox = "● "
+			}
+			line = checkbox + line
+		}
+
+		// Truncate by runes, not bytes, to avoid splitting UTF-8 characters
+		if maxWidth > 3 && lipgloss.Width(line) > maxWidth {
+			runes := []rune(line)
+			if len(runes) > maxWidth-3 {
+				line = string(runes[:maxWidth-3]) + "..."
+			}
+		}
+
+		switch kind {
+		case output.DiffHunk:
+			if hunk < len(hunks) && hunk == current && focused {
+				line = currentHunkStyle.Render(line)
+			} else {
+				line = headerStyle.Render(line)
+			}
+			hunk++
+		case output.DiffAdded:
+			line = addStyle.Render(line)
+		case output.DiffRemoved:
+			line = delStyle.Render(line)
+		case output.DiffMeta:
+			line = headerStyle.Render(line)
+		case output.DiffNoNewline:
+			line = dimStyle.Render(line)
+		}
+		lines[i] = line
+	}
+	return strings.Join(lines, "\n")
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/langtind/gren/internal/config"
 )
 
 // createView renders the create worktree wizard
@@ -14,6 +15,8 @@ func (m Model) createView() string {
 	}
 
 	switch m.createState.currentStep {
+	case CreateStepPreset:
+		return m.renderPresetStep()
 	case CreateStepBranchMode:
 		return m.renderBranchModeStep()
 	case CreateStepBranchName:
@@ -33,6 +36,82 @@ func (m Model) createView() string {
 	}
 }
 
+// ═══════════════════════════════════════════════════════════════════════════
+// Step 0: Preset Selection (only when presets are configured)
+// ═══════════════════════════════════════════════════════════════════════════
+
+func (m Model) renderPresetStep() string {
+	// Build header
+	header := m.renderWizardHeader("New Worktree")
+
+	// Build content
+	var content strings.Builder
+
+	// Error display
+	if m.err != nil {
+		content.WriteString(ErrorStyle.Render("Error: " + m.err.Error()))
+		content.WriteString("\n\n")
+	}
+
+	content.WriteString(WizardSubtitleStyle.Render("Choose a preset"))
+	content.WriteString("\n\n")
+
+	options := append([]string{"No preset"}, m.createState.presetNames...)
+	for i, name := range options {
+		selected := i == m.createState.selectedPreset
+		content.WriteString(WizardOption(name, selected))
+		content.WriteString("\n")
+		if !selected {
+			continue
+		}
+		desc := "Use the project's settings only"
+		if i > 0 {
+			desc = describePreset(m.createState.presets[name])
+		}
+		content.WriteString(WizardDescStyle.Render("   " + desc))
+		content.WriteString("\n")
+	}
+
+	// Build footer
+	footer := m.renderWizardFooter("↑↓", "select", "enter", "confirm", "esc", "cancel")
+
+	// Calculate content height to fill available space
+	contentHeight := m.height - 4 - FooterHeight // header lines + footer
+	if contentHeight < 5 {
+		contentHeight = 5
+	}
+
+	// Style content to fill space
+	contentStyled := lipgloss.NewStyle().
+		Width(m.width-4).
+		Height(contentHeight).
+		Padding(1, 2).
+		Render(content.String())
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, contentStyled, footer)
+}
+
+// describePreset sums up what a preset adds, for the preset step
+func describePreset(p config.Preset) string {
+	var parts []string
+	if p.BaseBranch != "" {
+		parts = append(parts, "based on "+p.BaseBranch)
+	}
+	if len(p.CopyFiles) > 0 {
+		parts = append(parts, "copies "+strings.Join(p.CopyFiles, ", "))
+	}
+	if len(p.SymlinkFiles) > 0 {
+		parts = append(parts, "links "+strings.Join(p.SymlinkFiles, ", "))
+	}
+	if p.PostCreate != "" {
+		parts = append(parts, "runs "+p.PostCreate)
+	}
+	if len(parts) == 0 {
+		return "Adds nothing to the project's settings"
+	}
+	return strings.Join(parts, "; ")
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Step 1: Branch Mode Selection
 // ═══════════════════════════════════════════════════════════════════════════
@@ -358,7 +437,7 @@ func (m Model) renderConfirmStep() string {
 	var content strings.Builder
 
 	// Summary box
-	sanitizedName := sanitizeBranchForPath(m.createState.branchName)
+	sanitizedName := m.sanitizeBranchForPath(m.createState.branchName)
 	worktreePath := m.getWorktreePath(m.createState.branchName)
 
 	summaryStyle := lipgloss.NewStyle().
@@ -374,6 +453,9 @@ func (m Model) renderConfirmStep() string {
 	} else {
 		summary.WriteString(WizardSubtitleStyle.Render("Branch:   ") + WorktreeBranchStyle.Render(m.createState.branchName) + "\n")
 	}
+	if m.createState.preset != "" {
+		summary.WriteString(WizardSubtitleStyle.Render("Preset:   ") + m.createState.preset + "\n")
+	}
 	summary.WriteString(WizardSubtitleStyle.Render("Path:     ") + WizardDescStyle.Render(worktreePath))
 
 	content.WriteString(summaryStyle.Render(summary.String()))
@@ -526,10 +608,23 @@ func (m Model) getWizardStepInfo() (current int, total int) {
 		return 0, 0
 	}
 
+	current, total = m.branchStepInfo()
+	if current == 0 || len(m.createState.presetNames) == 0 {
+		return current, total
+	}
+	// The preset step comes first
+	if m.createState.currentStep == CreateStepPreset {
+		return 1, total + 1
+	}
+	return current + 1, total + 1
+}
+
+// branchStepInfo is getWizardStepInfo without the preset step
+func (m Model) branchStepInfo() (current int, total int) {
 	isNewBranch := m.createState.createMode == CreateModeNewBranch
 
 	switch m.createState.currentStep {
-	case CreateStepBranchMode:
+	case CreateStepPreset, CreateStepBranchMode:
 		// At step 1, we don't know the flow yet, so show 1 of ?
 		// Actually, we show based on selected mode
 		if m.createState.selectedMode == 0 { // New branch selected
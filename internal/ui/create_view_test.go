@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/langtind/gren/internal/config"
+)
+
+func TestCreatePresetStep(t *testing.T) {
+	m := Model{currentView: CreateView, keys: DefaultKeyMap(), width: 100, height: 30}
+	m.setupCreateState(createInitMsg{
+		branchStatuses:  []BranchStatus{{Name: "main", IsClean: true}, {Name: "develop", IsClean: true}},
+		recommendedBase: "main",
+		presets: map[string]config.Preset{
+			"backend":  {CopyFiles: []string{"dev.sqlite3"}},
+			"frontend": {BaseBranch: "develop", SymlinkFiles: []string{"node_modules"}},
+		},
+		presetNames: []string{"backend", "frontend"},
+	})
+
+	if m.createState.currentStep != CreateStepPreset {
+		t.Fatalf("step = %d, want the preset step when presets are configured", m.createState.currentStep)
+	}
+	view := m.createView()
+	for _, want := range []string{"No preset", "backend", "frontend"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preset step does not offer %q", want)
+		}
+	}
+
+	// Pick frontend, the second preset
+	for range 2 {
+		model, _ := m.handleCreateKeys(tea.KeyMsg{Type: tea.KeyDown})
+		m = model.(Model)
+	}
+	model, _ := m.handleCreateKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	m = model.(Model)
+
+	if m.createState.preset != "frontend" {
+		t.Errorf("preset = %q, want frontend", m.createState.preset)
+	}
+	if m.createState.currentStep != CreateStepBranchMode {
+		t.Errorf("step = %d after picking a preset, want the branch mode step", m.createState.currentStep)
+	}
+	if m.createState.baseBranch != "develop" || m.createState.branchStatuses[m.createState.selectedBranch].Name != "develop" {
+		t.Errorf("base branch = %q, want the preset's develop", m.createState.baseBranch)
+	}
+
+	// Esc goes back to the preset step rather than leaving the wizard
+	model, _ = m.handleCreateKeys(tea.KeyMsg{Type: tea.KeyEscape})
+	m = model.(Model)
+	if m.currentView != CreateView || m.createState.currentStep != CreateStepPreset {
+		t.Errorf("esc from branch mode: view %v, step %d, want the preset step", m.currentView, m.createState.currentStep)
+	}
+}
+
+func TestCreateWithoutPresetsSkipsPresetStep(t *testing.T) {
+	m := Model{currentView: CreateView, keys: DefaultKeyMap()}
+	m.setupCreateState(createInitMsg{
+		branchStatuses:  []BranchStatus{{Name: "main", IsClean: true}},
+		recommendedBase: "main",
+	})
+	if m.createState.currentStep != CreateStepBranchMode {
+		t.Errorf("step = %d, want the branch mode step without presets", m.createState.currentStep)
+	}
+}
@@ -2,12 +2,14 @@ package ui
 
 import (
 	"fmt"
-	"os"
 	"os/exec"
-	"sort"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/core"
+	"github.com/langtind/gren/internal/output"
 )
 
 // Layout breakpoints
@@ -350,24 +352,34 @@ func (m Model) buildPreviewWithHeader(wt *Worktree, width, height int) string {
 		Render(content)
 }
 
-// getSortedWorktrees returns worktrees sorted: current first, then by recency
+// getSortedWorktrees returns worktrees in the dashboard's sort order (current
+// first, then by recency unless changed with "s"/"S"), narrowed by the filter
 func (m Model) getSortedWorktrees() []Worktree {
 	sorted := make([]Worktree, len(m.worktrees))
 	copy(sorted, m.worktrees)
 
-	sort.SliceStable(sorted, func(i, j int) bool {
-		// Current worktree always first
-		if sorted[i].IsCurrent && !sorted[j].IsCurrent {
-			return true
+	core.SortWorktrees(sorted, m.currentSortMode(), !m.unpinCurrent, func(wt Worktree) core.SortKey {
+		return core.SortKey{
+			Name:         wt.Name,
+			Branch:       wt.Branch,
+			Status:       wt.Status,
+			BranchStatus: wt.BranchStatus,
+			LastCommit:   wt.LastCommit,
+			IsCurrent:    wt.IsCurrent,
 		}
-		if !sorted[i].IsCurrent && sorted[j].IsCurrent {
-			return false
-		}
-		// Then sort by commit recency (this is approximate based on string)
-		return commitTimeScore(sorted[i].LastCommit) > commitTimeScore(sorted[j].LastCommit)
 	})
 
-	return sorted
+	if m.filterQuery == "" {
+		return sorted
+	}
+	query := strings.ToLower(m.filterQuery)
+	filtered := sorted[:0]
+	for _, wt := range sorted {
+		if strings.Contains(strings.ToLower(wt.Branch), query) || strings.Contains(strings.ToLower(wt.Path), query) {
+			filtered = append(filtered, wt)
+		}
+	}
+	return filtered
 }
 
 // getSelectedWorktree returns the worktree at the current selection index from the sorted list.
@@ -381,34 +393,22 @@ func (m Model) getSelectedWorktree() *Worktree {
 	return &sorted[m.selected]
 }
 
-// commitTimeScore returns a rough score for sorting (higher = more recent)
-func commitTimeScore(timeStr string) int {
-	if timeStr == "" {
-		return 0
-	}
-	// Parse the shortened time format and return approximate seconds ago
-	if strings.Contains(timeStr, "s ago") {
-		return 1000000
-	}
-	if strings.Contains(timeStr, "m ago") {
-		return 100000
-	}
-	if strings.Contains(timeStr, "h ago") {
-		return 10000
-	}
-	if strings.Contains(timeStr, "d ago") {
-		return 1000
+// sortLabel describes the sort order for the footer, e.g. "recent" or
+// "name (unpinned)"
+func (m Model) sortLabel() string {
+	label := string(m.currentSortMode())
+	if m.unpinCurrent {
+		label += " (unpinned)"
 	}
-	if strings.Contains(timeStr, "w ago") {
-		return 100
-	}
-	if strings.Contains(timeStr, "mo ago") {
-		return 10
-	}
-	if strings.Contains(timeStr, "y ago") {
-		return 1
+	return label
+}
+
+// currentSortMode returns the dashboard sort mode, defaulting to recent
+func (m Model) currentSortMode() core.SortMode {
+	if m.sortMode == "" {
+		return core.SortRecent
 	}
-	return 0
+	return m.sortMode
 }
 
 func (m Model) renderTableHeader(width int) string {
@@ -443,6 +443,12 @@ func (m Model) renderWorktreeRow(wt Worktree, selected bool, width int) string {
 	if wt.IsPrevious {
 		branch = branch + " ←"
 	}
+	if wt.BranchMismatch {
+		branch = branch + " ≠"
+	}
+	if wt.SubmoduleState != "" {
+		branch = branch + " 📦"
+	}
 	if wt.IsCurrent {
 		branch = "● " + branch
 	} else {
@@ -452,7 +458,9 @@ func (m Model) renderWorktreeRow(wt Worktree, selected bool, width int) string {
 	// Shorten path (use ~ for home directory)
 	// Add [main] suffix for main worktree
 	mainTag := ""
-	if wt.IsMain {
+	if wt.IsBare {
+		mainTag = " [bare]"
+	} else if wt.IsMain {
 		mainTag = " [main]"
 	}
 	path := shortenPath(wt.Path, pathWidth-2-len(mainTag))
@@ -476,6 +484,10 @@ func (m Model) renderWorktreeRow(wt Worktree, selected bool, width int) string {
 
 	// Status badge with details - pass background color for consistent styling
 	status := StatusBadgeDetailed(wt.Status, wt.BranchStatus, wt.StagedCount, wt.ModifiedCount, wt.UntrackedCount, wt.UnpushedCount, wt.PRNumber, wt.PRState, bgColor)
+	if wt.Loading {
+		// Status is still being loaded in the background
+		status = m.githubSpinner.View()
+	}
 
 	// Use Dashboard-specific styles for consistent coloring
 	var branchStyle lipgloss.Style
@@ -499,9 +511,10 @@ func (m Model) renderWorktreeRow(wt Worktree, selected bool, width int) string {
 
 // shortenPath replaces home directory with ~ and truncates if needed
 func shortenPath(path string, maxLen int) string {
-	// Replace home directory with ~
-	if home, err := os.UserHomeDir(); err == nil {
-		if strings.HasPrefix(path, home) {
+	// Replace home directory with ~ (falls back to the user database when
+	// HOME is unset, as in some containers)
+	if home, err := config.HomeDir(); err == nil && home != "/" {
+		if path == home || strings.HasPrefix(path, home+string(filepath.Separator)) {
 			path = "~" + strings.TrimPrefix(path, home)
 		}
 	}
@@ -530,13 +543,29 @@ func (m Model) renderFooter() string {
 		return FooterBarStyle.Width(width).Render(items)
 	}
 
+	sep := HelpSeparatorStyle.Render(" │ ")
+
+	// While filtering, the footer is the filter input
+	if m.filtering || m.filterQuery != "" {
+		count := HelpTextStyle.Render(fmt.Sprintf(" %d of %d", len(m.getSortedWorktrees()), len(m.worktrees)))
+		if m.filtering {
+			input := HelpKeyStyle.Render("/ " + m.filterQuery + "▮")
+			items := input + count + sep + HelpItem("↑↓", "nav") + " " + HelpItem("enter", "done") + " " + HelpItem("esc", "clear")
+			return FooterBarStyle.Width(width).Render(items)
+		}
+		filter := HelpKeyStyle.Render("filter: "+m.filterQuery) + count + " " + HelpItem("esc", "clear")
+		nav := HelpItem("↑↓", "nav") + " " + HelpItem("/", "edit") + " " + HelpItem("s", "sort:"+m.sortLabel())
+		actions := HelpItem("n", "new") + " " + HelpItem("d", "del") + " " + HelpItem("t", "tools")
+		open := HelpItem("enter", "open") + " " + HelpItem("g", "goto")
+		return FooterBarStyle.Width(width).Render(filter + sep + nav + sep + actions + sep + open)
+	}
+
 	// Group shortcuts logically with separators
-	nav := HelpItem("↑↓", "nav")
+	nav := HelpItem("↑↓", "nav") + " " + HelpItem("/", "filter") + " " + HelpItem("s", "sort:"+m.sortLabel())
 	actions := HelpItem("n", "new") + " " + HelpItem("d", "del") + " " + HelpItem("t", "tools")
 	open := HelpItem("enter", "open") + " " + HelpItem("g", "goto")
 	other := HelpItem("c", "cfg") + " " + HelpItem("?", "help") + " " + HelpItem("q", "quit")
 
-	sep := HelpSeparatorStyle.Render(" │ ")
 	helpText := nav + sep + actions + sep + open + sep + other
 
 	return FooterBarStyle.Width(width).Render(helpText)
@@ -686,6 +715,49 @@ func getStatusDisplay(status string) (string, lipgloss.Style) {
 // Preview Panel
 // ═══════════════════════════════════════════════════════════════════════════
 
+// previewDiffFiles is how many changed files the preview lists before
+// summing up the rest.
+const previewDiffFiles = 5
+
+// renderDiffStat lists the largest uncommitted changes of wt for the
+// preview, like `git diff --stat`: each file with its added and deleted
+// line counts. It shows a spinner while they load and nothing for a clean
+// worktree.
+func (m Model) renderDiffStat(wt *Worktree, width int) []string {
+	if wt.StagedCount+wt.ModifiedCount+wt.UntrackedCount == 0 {
+		return nil
+	}
+	files, ok := m.diffStats[wt.Path]
+	if !ok {
+		if m.diffStatLoading[wt.Path] {
+			return []string{"", "  " + m.githubSpinner.View() + " " + DashboardPathStyle.Render("Loading changes...")}
+		}
+		return nil
+	}
+	if len(files) == 0 {
+		return nil
+	}
+
+	lines := []string{""}
+	for _, f := range files[:min(len(files), previewDiffFiles)] {
+		var counts string
+		switch {
+		case f.Binary:
+			counts = DashboardPathStyle.Render("binary")
+		case f.Untracked:
+			counts = StatusCleanStyle.Render(fmt.Sprintf("+%d", f.Added)) + " " + DashboardPathStyle.Render("new")
+		default:
+			counts = StatusCleanStyle.Render(fmt.Sprintf("+%d", f.Added)) + " " + StatusMissingStyle.Render(fmt.Sprintf("-%d", f.Deleted))
+		}
+		name := shortenPath(f.Path, max(width-4-lipgloss.Width(counts)-1, 8))
+		lines = append(lines, "  "+DashboardCommitStyle.Render(name)+" "+counts)
+	}
+	if rest := len(files) - previewDiffFiles; rest > 0 {
+		lines = append(lines, "  "+DashboardPathStyle.Render(fmt.Sprintf("… and %d more", rest)))
+	}
+	return lines
+}
+
 // renderPreviewPanel renders the right-side preview panel with worktree details
 func (m Model) renderPreviewPanel(wt *Worktree, width, height int) string {
 	if wt == nil {
@@ -711,6 +783,42 @@ func (m Model) renderPreviewPanel(wt *Worktree, width, height int) string {
 	lines = append(lines, labelStyle.Render("Path"))
 	shortPath := shortenPath(wt.Path, width-4)
 	lines = append(lines, "  "+DashboardPathStyle.Render(shortPath))
+	if wt.BranchMismatch {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorWarning).Render("≠ directory named for another branch (gren relocate)"))
+	}
+	if wt.BrokenLink {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorWarning).Render("⚠ link to the repository is broken (gren repair)"))
+	}
+	lines = append(lines, "")
+
+	// Submodules, flagged when one is not at the commit the branch records
+	if wt.HasSubmodules && !wt.Loading {
+		lines = append(lines, labelStyle.Render("Submodules"))
+		if wt.SubmoduleState != "" {
+			lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorWarning).Render("📦 "+wt.SubmoduleState))
+			lines = append(lines, "  "+DashboardPathStyle.Render("git submodule update --init to sync"))
+		} else {
+			lines = append(lines, "  "+StatusCleanStyle.Render("✓ In sync"))
+		}
+		lines = append(lines, "")
+	}
+
+	// Claude activity, when a session has left a marker
+	if wt.Marker != "" {
+		lines = append(lines, labelStyle.Render("Claude"))
+		lines = append(lines, "  "+DashboardCommitStyle.Render(markerLabel(wt.Marker)))
+		lines = append(lines, "")
+	}
+
+	// Disk usage
+	lines = append(lines, labelStyle.Render("Size"))
+	if size, ok := m.diskUsage[wt.Path]; ok {
+		lines = append(lines, "  "+DashboardCommitStyle.Render(output.FormatSize(size)))
+	} else if m.diskUsageLoading[wt.Path] {
+		lines = append(lines, "  "+m.githubSpinner.View()+" "+DashboardPathStyle.Render("Measuring..."))
+	} else {
+		lines = append(lines, "  "+DashboardPathStyle.Render("unknown"))
+	}
 	lines = append(lines, "")
 
 	// Last commit
@@ -722,9 +830,21 @@ func (m Model) renderPreviewPanel(wt *Worktree, width, height int) string {
 	}
 	lines = append(lines, "")
 
+	// Creation date (main worktree has none)
+	if !wt.Created.IsZero() {
+		lines = append(lines, labelStyle.Render("Created"))
+		lines = append(lines, "  "+DashboardCommitStyle.Render(wt.Created.Local().Format("2006-01-02 15:04")))
+		lines = append(lines, "")
+	}
+
 	// Status details
 	lines = append(lines, labelStyle.Render("Status"))
-	if wt.BranchStatus == "stale" {
+	if wt.Loading {
+		lines = append(lines, "  "+m.githubSpinner.View()+" "+DashboardPathStyle.Render("Loading..."))
+	} else if wt.StatusError != "" {
+		lines = append(lines, "  "+StatusModifiedStyle.Render("⚠ Status unknown"))
+		lines = append(lines, "  "+DashboardPathStyle.Render(truncate(wt.StatusError, width-4)))
+	} else if wt.BranchStatus == "stale" {
 		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorTextMuted).Render("💤 Stale"))
 	} else if wt.StagedCount == 0 && wt.ModifiedCount == 0 && wt.UntrackedCount == 0 && wt.UnpushedCount == 0 {
 		lines = append(lines, "  "+StatusCleanStyle.Render("✓ Clean"))
@@ -741,6 +861,10 @@ func (m Model) renderPreviewPanel(wt *Worktree, width, height int) string {
 		if wt.UnpushedCount > 0 {
 			lines = append(lines, "  "+StatusUnpushedStyle.Render(fmt.Sprintf("↑%d unpushed", wt.UnpushedCount)))
 		}
+		lines = append(lines, m.renderDiffStat(wt, width)...)
+	}
+	if wt.NoRemote && !wt.Loading && !wt.IsBare {
+		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorTextMuted).Render("○ Local only (no remote)"))
 	}
 
 	// Show PR info if available
@@ -750,19 +874,15 @@ func (m Model) renderPreviewPanel(wt *Worktree, width, height int) string {
 		lines = append(lines, prHeaderStyle.Render("Pull Request"))
 
 		prStyle := lipgloss.NewStyle().Foreground(ColorText)
-		stateStyle := lipgloss.NewStyle()
-		switch wt.PRState {
-		case "OPEN":
-			stateStyle = stateStyle.Foreground(ColorSuccess)
-		case "DRAFT":
-			stateStyle = stateStyle.Foreground(ColorTextMuted)
-		case "MERGED":
-			stateStyle = stateStyle.Foreground(ColorPrimary)
-		case "CLOSED":
-			stateStyle = stateStyle.Foreground(ColorError)
-		}
+		stateStyle := lipgloss.NewStyle().Foreground(output.PRStateColor(wt.PRState))
 
 		lines = append(lines, "  "+prStyle.Render(fmt.Sprintf("#%d", wt.PRNumber))+" "+stateStyle.Render(wt.PRState))
+		if wt.CIStatus != "" {
+			lines = append(lines, "  "+CIStatusBadge(wt.CIStatus, lipgloss.AdaptiveColor{})+" "+prStyle.Render(ciStatusLabel(wt)))
+			if wt.CIURL != "" {
+				lines = append(lines, "  "+DashboardPathStyle.Render(truncate(wt.CIURL, width-4)))
+			}
+		}
 		lines = append(lines, "  "+lipgloss.NewStyle().Foreground(ColorTextMuted).Render("Press 't' → 'p' to open in browser"))
 	}
 
@@ -791,6 +911,9 @@ func (m Model) renderPreviewPanel(wt *Worktree, width, height int) string {
 		case "pr_closed":
 			explanation = "Pull request was closed without merging."
 			suggestion = "Review if work should be preserved."
+		case "branch_deleted":
+			explanation = "Branch was deleted; git sees every file as staged."
+			suggestion = "Recreate the branch to keep the work, or 'd' to delete."
 		default:
 			explanation = "Branch appears to be stale."
 			suggestion = "Consider cleaning up this worktree."
@@ -976,3 +1099,18 @@ func (m Model) renderOpenInModal() string {
 
 	return content.String()
 }
+
+// markerLabel describes a Claude activity marker for the preview panel.
+// Custom markers are shown as set.
+func markerLabel(marker string) string {
+	switch core.MarkerType(marker) {
+	case core.MarkerWorking:
+		return marker + " Working"
+	case core.MarkerWaiting:
+		return marker + " Waiting for input"
+	case core.MarkerIdle:
+		return marker + " Idle"
+	default:
+		return marker
+	}
+}
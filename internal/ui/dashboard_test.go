@@ -1,11 +1,13 @@
 package ui
 
 import (
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/git"
 )
 
@@ -213,3 +215,401 @@ func init() {
 	// Suppress unused import error for key package
 	_ = key.NewBinding()
 }
+
+func TestPreviewPanelShowsDiskUsage(t *testing.T) {
+	m := Model{
+		keys:        DefaultKeyMap(),
+		currentView: DashboardView,
+		repoInfo:    &git.RepoInfo{IsGitRepo: true, IsInitialized: true},
+		worktrees: []Worktree{
+			{Name: "main", Branch: "main", Path: "/repo", IsCurrent: true},
+			{Name: "feature", Branch: "feature", Path: "/wt/feature"},
+		},
+	}
+	wt := &m.worktrees[1]
+
+	// Only the selected worktree is measured
+	m.selected = 1
+	if cmd := m.measureSelectedDiskUsage(); cmd == nil {
+		t.Fatal("selecting an unmeasured worktree should measure it")
+	}
+	if !m.diskUsageLoading["/wt/feature"] || m.diskUsageLoading["/repo"] {
+		t.Errorf("diskUsageLoading = %v, want only the selected worktree", m.diskUsageLoading)
+	}
+	if cmd := m.measureSelectedDiskUsage(); cmd != nil {
+		t.Error("a worktree already being measured should not be measured twice")
+	}
+	if preview := m.renderPreviewPanel(wt, 60, 30); !strings.Contains(preview, "Measuring...") {
+		t.Error("Preview should say the size is being measured before it arrives")
+	}
+
+	updated, _ := m.Update(diskUsageMsg{path: "/wt/feature", size: 3 * 1024 * 1024})
+	m = updated.(Model)
+	if len(m.diskUsageLoading) != 0 {
+		t.Error("diskUsageLoading should be cleared once the size arrives")
+	}
+	if preview := m.renderPreviewPanel(wt, 60, 30); !strings.Contains(preview, "3.0 MB") {
+		t.Errorf("Preview should show the worktree size, got:\n%s", preview)
+	}
+	if cmd := m.measureSelectedDiskUsage(); cmd != nil {
+		t.Error("a measured worktree should reuse its size")
+	}
+}
+
+func TestPreviewPanelDiffStat(t *testing.T) {
+	m := Model{
+		keys:        DefaultKeyMap(),
+		currentView: DashboardView,
+		repoInfo:    &git.RepoInfo{IsGitRepo: true, IsInitialized: true},
+		worktrees: []Worktree{
+			{Name: "main", Branch: "main", Path: "/repo", IsCurrent: true},
+			{Name: "feature", Branch: "feature", Path: "/wt/feature", Loading: true},
+		},
+		// Sizes already measured, so selecting only loads diff stats
+		diskUsage: map[string]int64{"/repo": 1, "/wt/feature": 1},
+	}
+	update := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(Model)
+		return cmd
+	}
+	down, up := tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyUp}
+	wt := &m.worktrees[1]
+
+	// Selecting the worktree before its status is in loads nothing
+	update(down)
+	if m.diffStatLoading["/wt/feature"] {
+		t.Fatal("diff stat should wait for the worktree's status")
+	}
+
+	// Once it turns out dirty, its changes load in the background
+	if cmd := update(worktreeStatusMsg{worktree: core.WorktreeInfo{Path: "/wt/feature", Status: "mixed", ModifiedCount: 6}}); cmd == nil || !m.diffStatLoading["/wt/feature"] {
+		t.Fatal("status showing changes should start loading the diff stat")
+	}
+	if preview := m.renderPreviewPanel(wt, 60, 40); !strings.Contains(preview, "Loading changes...") {
+		t.Errorf("Preview should say changes are loading, got:\n%s", preview)
+	}
+	if cmd := update(up); cmd != nil {
+		t.Error("a load already in flight should not be started again")
+	}
+
+	files := []core.FileStat{
+		{Path: "api.go", Added: 30, Deleted: 4},
+		{Path: "notes.md", Added: 12, Untracked: true},
+		{Path: "logo.png", Binary: true},
+		{Path: "a.go", Added: 1}, {Path: "b.go", Added: 1}, {Path: "c.go", Added: 1},
+	}
+	update(diffStatMsg{path: "/wt/feature", files: files})
+	if m.diffStatLoading["/wt/feature"] {
+		t.Error("loading flag should be cleared once the diff stat arrives")
+	}
+	preview := m.renderPreviewPanel(wt, 60, 40)
+	for _, want := range []string{"api.go", "+30", "-4", "notes.md", "new", "logo.png", "binary", "… and 1 more"} {
+		if !strings.Contains(preview, want) {
+			t.Errorf("Preview should contain %q, got:\n%s", want, preview)
+		}
+	}
+	if strings.Contains(preview, "c.go") {
+		t.Errorf("Preview should list only the first %d files, got:\n%s", previewDiffFiles, preview)
+	}
+
+	// Moving away and back reuses the cache; a status reload drops it
+	update(up)
+	if cmd := update(down); cmd != nil {
+		t.Error("a cached diff stat should not be loaded again")
+	}
+	if cmd := update(worktreeStatusMsg{worktree: core.WorktreeInfo{Path: "/wt/feature", Status: "modified", ModifiedCount: 1}}); cmd == nil {
+		t.Error("a status reload should load the diff stat again")
+	}
+}
+
+func TestMarkersRefreshInDashboard(t *testing.T) {
+	m := Model{
+		worktrees: []Worktree{
+			{Name: "feat", Branch: "feat", Path: "/wt/feat", Marker: string(core.MarkerWorking)},
+			{Name: "fix", Branch: "fix", Path: "/wt/fix"},
+		},
+	}
+
+	mm := core.NewMarkerManager()
+	updated, cmd := m.Update(markersLoadedMsg{markers: map[string]core.MarkerType{"fix": core.MarkerWaiting}, markerManager: mm})
+	m = updated.(Model)
+	if cmd == nil {
+		t.Error("markersLoadedMsg should schedule the next refresh")
+	}
+	if m.markerManager != mm {
+		t.Error("markersLoadedMsg should keep its MarkerManager for the next poll")
+	}
+	if m.worktrees[0].Marker != "" {
+		t.Errorf("feat marker = %q, want it cleared once gone from the markers", m.worktrees[0].Marker)
+	}
+	if m.worktrees[1].Marker != string(core.MarkerWaiting) {
+		t.Errorf("fix marker = %q, want %q", m.worktrees[1].Marker, core.MarkerWaiting)
+	}
+
+	// A failed read keeps the markers already shown
+	updated, _ = m.Update(markersLoadedMsg{})
+	m = updated.(Model)
+	if m.worktrees[1].Marker != string(core.MarkerWaiting) {
+		t.Errorf("fix marker after failed read = %q, want %q", m.worktrees[1].Marker, core.MarkerWaiting)
+	}
+
+	if preview := m.renderPreviewPanel(&m.worktrees[1], 60, 30); !strings.Contains(preview, "Waiting for input") {
+		t.Errorf("Preview should describe the marker, got:\n%s", preview)
+	}
+	if preview := m.renderPreviewPanel(&m.worktrees[0], 60, 30); strings.Contains(preview, "Claude") {
+		t.Errorf("Preview should leave out Claude without a marker, got:\n%s", preview)
+	}
+}
+
+func TestProgressiveWorktreeStatus(t *testing.T) {
+	m := Model{
+		loadGeneration: 2,
+		staleLoading:   true,
+		worktrees: []Worktree{
+			{Name: "main", Branch: "main", Path: "/wt/main", IsMain: true, Loading: true},
+			{Name: "feature", Branch: "feature", Path: "/wt/feature", Loading: true},
+		},
+	}
+
+	// The PR check may finish first; it must not clobber loading rows
+	updated, _ := m.Update(githubRefreshCompleteMsg{prOnly: true, worktrees: []Worktree{
+		{Path: "/wt/main"},
+		{Path: "/wt/feature", PRNumber: 7, PRState: "MERGED", BranchStatus: "stale", StaleReason: "pr_merged"},
+	}})
+	m = updated.(Model)
+	if !m.worktrees[1].Loading || m.worktrees[1].PRNumber != 7 {
+		t.Errorf("feature = %+v, want PR #7 merged in while still loading", m.worktrees[1])
+	}
+
+	// Results from a superseded load are dropped
+	updated, _ = m.Update(worktreeStatusMsg{generation: 1, worktree: core.WorktreeInfo{Path: "/wt/feature", Status: "clean"}})
+	m = updated.(Model)
+	if !m.worktrees[1].Loading {
+		t.Error("status from an old load generation should be ignored")
+	}
+
+	updated, _ = m.Update(worktreeStatusMsg{generation: 2, worktree: core.WorktreeInfo{Path: "/wt/feature", Status: "modified", ModifiedCount: 3}})
+	m = updated.(Model)
+	if wt := m.worktrees[1]; wt.Loading || wt.Status != "modified" || wt.ModifiedCount != 3 {
+		t.Errorf("feature = %+v, want modified with 3 files and no longer loading", wt)
+	}
+	if !m.worktrees[0].Loading || !m.statusLoading() {
+		t.Error("main should still be loading")
+	}
+
+	updated, _ = m.Update(worktreeStaleMsg{generation: 2, stale: map[string]core.WorktreeInfo{
+		"/wt/main":    {BranchStatus: "active"},
+		"/wt/feature": {BranchStatus: "active"},
+	}})
+	m = updated.(Model)
+	if wt := m.worktrees[1]; wt.BranchStatus != "stale" || wt.StaleReason != "pr_merged" {
+		t.Errorf("feature stale = (%q, %q), want the PR-derived reason kept", wt.BranchStatus, wt.StaleReason)
+	}
+	if m.worktrees[0].BranchStatus != "active" || m.staleLoading {
+		t.Error("stale status should be applied and stale loading cleared")
+	}
+}
+
+func TestDashboardFilter(t *testing.T) {
+	m := Model{
+		keys:        DefaultKeyMap(),
+		currentView: DashboardView,
+		repoInfo:    &git.RepoInfo{IsGitRepo: true, IsInitialized: true},
+		worktrees: []Worktree{
+			{Name: "main", Branch: "main", Path: "/repo", IsCurrent: true},
+			{Name: "api-auth", Branch: "feat/api-auth", Path: "/wt/api-auth", LastCommit: "1h ago"},
+			{Name: "web", Branch: "feat/web", Path: "/wt/web", LastCommit: "2d ago"},
+			{Name: "api-cache", Branch: "fix/cache", Path: "/wt/api-cache", LastCommit: "3d ago"},
+		},
+	}
+	press := func(msgs ...tea.KeyMsg) {
+		for _, msg := range msgs {
+			updated, _ := m.Update(msg)
+			m = updated.(Model)
+		}
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	// Select feat/web, then filter to something it doesn't match
+	press(tea.KeyMsg{Type: tea.KeyDown}, tea.KeyMsg{Type: tea.KeyDown}, runes("/"))
+	if !m.filtering {
+		t.Fatal("/ should open the filter input")
+	}
+	press(runes("a"), runes("p"), runes("i"))
+
+	var branches []string
+	for _, wt := range m.getSortedWorktrees() {
+		branches = append(branches, wt.Branch)
+	}
+	if got := strings.Join(branches, ","); got != "feat/api-auth,fix/cache" {
+		t.Errorf("filtered worktrees = %s, want branch and path matches in sorted order", got)
+	}
+	if wt := m.getSelectedWorktree(); wt == nil || wt.Branch != "feat/api-auth" {
+		t.Errorf("selected = %v, want the first match once the selection is filtered out", wt)
+	}
+
+	// "?" is filter text here, not help
+	press(runes("?"))
+	if m.helpVisible || m.filterQuery != "api?" {
+		t.Errorf("filter = %q, help visible = %v; want ? typed into the filter", m.filterQuery, m.helpVisible)
+	}
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyDown})
+	if wt := m.getSelectedWorktree(); wt == nil || wt.Branch != "fix/cache" {
+		t.Errorf("selected = %v, want down to move within the matches", wt)
+	}
+
+	// Widening the filter keeps the selected worktree
+	press(tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace}, tea.KeyMsg{Type: tea.KeyBackspace})
+	if wt := m.getSelectedWorktree(); wt == nil || wt.Branch != "fix/cache" {
+		t.Errorf("selected = %v after clearing the text, want fix/cache kept", wt)
+	}
+
+	press(runes("w"), runes("e"), runes("b"), tea.KeyMsg{Type: tea.KeyEnter})
+	if m.filtering || m.filterQuery != "web" {
+		t.Errorf("enter should keep the filter and leave the input, got filtering=%v query=%q", m.filtering, m.filterQuery)
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "filter: web") || !strings.Contains(footer, "1 of 4") {
+		t.Errorf("footer should show the active filter and match count, got %q", footer)
+	}
+
+	press(tea.KeyMsg{Type: tea.KeyEscape})
+	if m.filterQuery != "" || len(m.getSortedWorktrees()) != 4 {
+		t.Errorf("esc should clear the filter, got %q", m.filterQuery)
+	}
+	if wt := m.getSelectedWorktree(); wt == nil || wt.Branch != "feat/web" {
+		t.Errorf("selected = %v after clearing, want feat/web kept", wt)
+	}
+}
+
+func TestDashboardSortCycle(t *testing.T) {
+	m := Model{
+		currentView: DashboardView,
+		keys:        DefaultKeyMap(),
+		repoInfo:    &git.RepoInfo{IsGitRepo: true, IsInitialized: true},
+		worktrees: []Worktree{
+			{Name: "main", Branch: "main", Path: "/repo", IsCurrent: true, Status: "clean", LastCommit: "1h ago"},
+			{Name: "web", Branch: "feat/web", Path: "/wt/web", Status: "modified", LastCommit: "3d ago"},
+			{Name: "api", Branch: "feat/api", Path: "/wt/api", Status: "clean", LastCommit: "5m ago", BranchStatus: "stale"},
+		},
+	}
+	press := func(s string) {
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+		m = updated.(Model)
+	}
+	order := func() string {
+		var names []string
+		for _, wt := range m.getSortedWorktrees() {
+			names = append(names, wt.Name)
+		}
+		return strings.Join(names, ",")
+	}
+
+	if got := order(); got != "main,api,web" {
+		t.Errorf("default order = %s, want current first, then recent", got)
+	}
+
+	// Select web, which keeps its selection as the order changes
+	m.selected = 2
+	want := map[core.SortMode]string{
+		core.SortName:   "main,api,web",
+		core.SortBranch: "main,api,web",
+		core.SortStatus: "main,web,api",
+		core.SortStale:  "main,api,web",
+		core.SortRecent: "main,api,web",
+	}
+	for _, mode := range []core.SortMode{core.SortName, core.SortBranch, core.SortStatus, core.SortStale, core.SortRecent} {
+		press("s")
+		if m.currentSortMode() != mode {
+			t.Fatalf("s should cycle to %q, got %q", mode, m.currentSortMode())
+		}
+		if got := order(); got != want[mode] {
+			t.Errorf("%s order = %s, want %s", mode, got, want[mode])
+		}
+		if wt := m.getSelectedWorktree(); wt == nil || wt.Name != "web" {
+			t.Errorf("%s: selected = %v, want web kept", mode, wt)
+		}
+		if footer := m.renderFooter(); !strings.Contains(footer, "sort:"+string(mode)) {
+			t.Errorf("footer should show sort mode %q, got %q", mode, footer)
+		}
+	}
+
+	press("s")
+	press("S")
+	if got := order(); got != "api,main,web" {
+		t.Errorf("unpinned name order = %s, want main sorted in place", got)
+	}
+	if footer := m.renderFooter(); !strings.Contains(footer, "name (unpinned)") {
+		t.Errorf("footer should say the current worktree is unpinned, got %q", footer)
+	}
+}
+
+func TestPreviewPanelNoRemote(t *testing.T) {
+	m := Model{keys: DefaultKeyMap(), currentView: DashboardView}
+	wt := Worktree{Name: "feature", Branch: "feature", Path: "/wt/feature", Status: "clean", NoRemote: true}
+	preview := m.renderPreviewPanel(&wt, 60, 40)
+	if !strings.Contains(preview, "Local only (no remote)") {
+		t.Errorf("Preview should say the repository has no remote, got:\n%s", preview)
+	}
+	if strings.Contains(preview, "unpushed") {
+		t.Errorf("Preview should not show a push state without a remote, got:\n%s", preview)
+	}
+
+	wt.NoRemote = false
+	if preview := m.renderPreviewPanel(&wt, 60, 40); strings.Contains(preview, "Local only") {
+		t.Errorf("Preview with a remote should not say local only, got:\n%s", preview)
+	}
+}
+
+func TestPreviewPanelBranchDeleted(t *testing.T) {
+	m := Model{keys: DefaultKeyMap(), currentView: DashboardView}
+	wt := Worktree{Name: "feature", Branch: "feature", Path: "/wt/feature", Status: "clean", BranchStatus: "stale", StaleReason: "branch_deleted"}
+	preview := m.renderPreviewPanel(&wt, 80, 40)
+	if !strings.Contains(preview, "Branch was deleted") {
+		t.Errorf("Preview should say the branch was deleted, got:\n%s", preview)
+	}
+}
+
+func TestApplyStaleStatusBranchDeletedBeatsPR(t *testing.T) {
+	m := Model{worktrees: []Worktree{
+		{Path: "/wt/gone", BranchStatus: "stale", StaleReason: "pr_merged"},
+		{Path: "/wt/merged", BranchStatus: "stale", StaleReason: "pr_merged"},
+	}}
+	m.applyStaleStatus(map[string]core.WorktreeInfo{
+		"/wt/gone":   {BranchStatus: "stale", StaleReason: "branch_deleted"},
+		"/wt/merged": {BranchStatus: "active"},
+	})
+	if got := m.worktrees[0].StaleReason; got != "branch_deleted" {
+		t.Errorf("StaleReason = %q, want branch_deleted over pr_merged", got)
+	}
+	if got := m.worktrees[1].StaleReason; got != "pr_merged" {
+		t.Errorf("StaleReason = %q, want pr_merged kept", got)
+	}
+
+	m.mergePRStatus([]Worktree{{Path: "/wt/gone", BranchStatus: "stale", StaleReason: "pr_closed"}})
+	if got := m.worktrees[0].StaleReason; got != "branch_deleted" {
+		t.Errorf("after mergePRStatus StaleReason = %q, want branch_deleted", got)
+	}
+}
+
+func TestDeleteMultiSelectShowsPrecheckIssues(t *testing.T) {
+	model := Model{
+		currentView: DeleteView,
+		worktrees: []Worktree{
+			{Name: "main", Path: "/path/main", IsCurrent: true},
+			{Name: "feature", Path: "/path/feature", Branch: "feature"},
+		},
+		keys:        DefaultKeyMap(),
+		deleteState: &DeleteState{currentStep: DeleteStepSelection, selectedWorktrees: []int{1}},
+	}
+
+	updated, _ := model.Update(deletePrecheckMsg{issues: []string{"feature: 2 untracked file(s)"}})
+	m := updated.(Model)
+	if m.deleteState.currentStep != DeleteStepConfirm {
+		t.Fatalf("step = %d, want the confirmation once the precheck is in", m.deleteState.currentStep)
+	}
+	if view := m.renderDeleteConfirmModal(); !strings.Contains(view, "feature: 2 untracked file(s)") {
+		t.Errorf("confirmation should list the precheck issues:\n%s", view)
+	}
+}
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -57,27 +58,16 @@ func (m Model) renderDeleteConfirmModal() string {
 	b.WriteString(lipgloss.NewStyle().Foreground(ColorTextMuted).Render("    " + shortenPath(wt.Path, 50)))
 	b.WriteString("\n")
 
-	// Status warnings
-	hasWarning := false
-	var warnings []string
-	if wt.StagedCount > 0 || wt.ModifiedCount > 0 {
-		warnings = append(warnings, "uncommitted changes")
-		hasWarning = true
-	}
-	if wt.UntrackedCount > 0 {
-		warnings = append(warnings, "untracked files")
-		hasWarning = true
-	}
-	if wt.UnpushedCount > 0 {
-		warnings = append(warnings, "unpushed commits")
-		hasWarning = true
-	}
-
-	if hasWarning {
+	// What PrecheckDelete found, as `gren delete` lists it
+	if warnings := m.deleteState.warnings; len(warnings) > 0 {
 		b.WriteString("\n")
 		warningStyle := lipgloss.NewStyle().Foreground(ColorError).Bold(true)
-		b.WriteString(warningStyle.Render("⚠ Has " + strings.Join(warnings, ", ")))
+		b.WriteString(warningStyle.Render("⚠ Has:"))
 		b.WriteString("\n")
+		for _, w := range warnings {
+			b.WriteString(warningStyle.Render("  - " + w))
+			b.WriteString("\n")
+		}
 		b.WriteString(lipgloss.NewStyle().Foreground(ColorTextSecondary).Render("  Changes will be permanently lost!"))
 		b.WriteString("\n")
 	}
@@ -89,6 +79,18 @@ func (m Model) renderDeleteConfirmModal() string {
 	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSuccess)
 	cancelStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorError)
 
+	// A rebase or merge in progress blocks the delete outright
+	if wt.Operation != "" {
+		warningStyle := lipgloss.NewStyle().Foreground(ColorError).Bold(true)
+		b.WriteString(warningStyle.Render(fmt.Sprintf("⚠ %s in progress", wt.Operation)))
+		b.WriteString("\n")
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorTextSecondary).Render(
+			fmt.Sprintf("  Finish it or run 'git %s --abort' first,\n  or use 'gren delete -f %s' to discard it.", wt.Operation, wt.Name)))
+		b.WriteString("\n\n")
+		b.WriteString(promptStyle.Render("Press ") + cancelStyle.Render("esc") + promptStyle.Render(" to go back"))
+		return b.String()
+	}
+
 	prompt := promptStyle.Render("Press ") +
 		keyStyle.Render("y") +
 		promptStyle.Render(" to confirm, ") +
@@ -168,6 +170,12 @@ func (m Model) renderDeleteCompleteModal() string {
 		b.WriteString("\n\n")
 	}
 
+	if m.deleteState.danglingRemoved > 0 {
+		note := fmt.Sprintf("Removed %d dangling symlink(s) whose targets no longer exist", m.deleteState.danglingRemoved)
+		b.WriteString(lipgloss.NewStyle().Foreground(ColorTextMuted).Render(note))
+		b.WriteString("\n\n")
+	}
+
 	// Help
 	promptStyle := lipgloss.NewStyle().Foreground(ColorTextSecondary)
 	keyStyle := lipgloss.NewStyle().Bold(true).Foreground(ColorSuccess)
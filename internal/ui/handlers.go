@@ -2,11 +2,13 @@ package ui
 
 import (
 	"fmt"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/logging"
 )
 
@@ -292,6 +294,72 @@ func (m Model) handleCustomizationKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	}
 }
 
+// handleFilterKeys handles keyboard input while the dashboard filter input
+// has focus. Typing narrows the list live; enter keeps the filter, esc
+// clears it.
+func (m Model) handleFilterKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.Type {
+	case tea.KeyCtrlC:
+		logging.Info("User quit from dashboard filter")
+		return m, tea.Quit
+	case tea.KeyEscape:
+		logging.Debug("Dashboard: filter cleared (escape)")
+		m.filtering = false
+		m.setFilter("")
+	case tea.KeyEnter:
+		logging.Debug("Dashboard: filter kept: %q", m.filterQuery)
+		m.filtering = false
+	case tea.KeyBackspace:
+		if query := []rune(m.filterQuery); len(query) > 0 {
+			m.setFilter(string(query[:len(query)-1]))
+		}
+	case tea.KeyRunes, tea.KeySpace:
+		m.setFilter(m.filterQuery + string(msg.Runes))
+	case tea.KeyUp:
+		if m.selected > 0 {
+			m.selected--
+		}
+	case tea.KeyDown:
+		if m.selected < len(m.getSortedWorktrees())-1 {
+			m.selected++
+		}
+	}
+	return m, nil
+}
+
+// setFilter changes the dashboard filter, keeping the selected worktree
+// selected when it still matches and otherwise selecting the first match.
+func (m *Model) setFilter(query string) {
+	m.reorder(func() { m.filterQuery = query })
+}
+
+// setSort changes the dashboard sort order, keeping the selected worktree
+// selected.
+func (m *Model) setSort(mode core.SortMode, unpinCurrent bool) {
+	m.reorder(func() {
+		m.sortMode = mode
+		m.unpinCurrent = unpinCurrent
+	})
+}
+
+// reorder applies a change to the dashboard order and moves the selection
+// to wherever the selected worktree ends up, or the top if it's gone.
+func (m *Model) reorder(change func()) {
+	var selectedPath string
+	if wt := m.getSelectedWorktree(); wt != nil {
+		selectedPath = wt.Path
+	}
+
+	change()
+	m.selected = 0
+	for i, wt := range m.getSortedWorktrees() {
+		if wt.Path == selectedPath {
+			m.selected = i
+			break
+		}
+	}
+}
+
 // handleCreateKeys handles keyboard input for the create view
 func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.createState == nil {
@@ -394,7 +462,16 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	case key.Matches(msg, m.keys.Back):
 		// Go back one step (CreateStepBranchName is handled in text input block above)
 		switch m.createState.currentStep {
+		case CreateStepPreset:
+			logging.Info("CreateView: back to Dashboard from Preset")
+			m.currentView = DashboardView
+			return m, m.loadProjectInfo()
 		case CreateStepBranchMode:
+			if len(m.createState.presetNames) > 0 {
+				logging.Debug("CreateView: back to Preset from BranchMode")
+				m.createState.currentStep = CreateStepPreset
+				return m, nil
+			}
 			logging.Info("CreateView: back to Dashboard from BranchMode")
 			m.currentView = DashboardView
 			return m, m.loadProjectInfo()
@@ -426,6 +503,10 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Clear any errors when navigating
 		m.err = nil
 		switch m.createState.currentStep {
+		case CreateStepPreset:
+			if m.createState.selectedPreset > 0 {
+				m.createState.selectedPreset--
+			}
 		case CreateStepBranchMode:
 			if m.createState.selectedMode > 0 {
 				m.createState.selectedMode--
@@ -458,6 +539,10 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Clear any errors when navigating
 		m.err = nil
 		switch m.createState.currentStep {
+		case CreateStepPreset:
+			if m.createState.selectedPreset < len(m.createState.presetNames) { // 0="No preset"
+				m.createState.selectedPreset++
+			}
 		case CreateStepBranchMode:
 			if m.createState.selectedMode < 1 { // 0="Create new", 1="Use existing"
 				m.createState.selectedMode++
@@ -513,6 +598,11 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Clear any errors when making selections
 		m.err = nil
 		switch m.createState.currentStep {
+		case CreateStepPreset:
+			m.choosePreset()
+			logging.Info("CreateView: selected preset %q", m.createState.preset)
+			m.createState.currentStep = CreateStepBranchMode
+			return m, nil
 		case CreateStepBranchMode:
 			// Set mode and advance to appropriate step
 			if m.createState.selectedMode == 0 {
@@ -545,6 +635,7 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 
 				m.createState.branchName = branchName
+				m.createState.worktreePath = m.resolveWorktreePath(branchName)
 				m.createState.currentStep = CreateStepConfirm
 			}
 			return m, nil
@@ -563,6 +654,7 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 				}
 				logging.Info("CreateView: selected base branch: %s (clean: %v)", selectedStatus.Name, selectedStatus.IsClean)
 				m.createState.baseBranch = selectedStatus.Name
+				m.createState.worktreePath = m.resolveWorktreePath(m.createState.branchName)
 				m.createState.currentStep = CreateStepConfirm
 			}
 			return m, nil
@@ -581,11 +673,11 @@ func (m Model) handleCreateKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			if m.createState.selectedAction < len(actions) {
 				action := actions[m.createState.selectedAction]
 				logging.Info("CreateView: executing action: %s", action.Name)
-				// Handle navigate specially - it needs to quit TUI and write to temp file
+				// Handle navigate specially - it quits the TUI for the shell to cd
 				if action.Command == "navigate" {
 					worktreePath := m.getWorktreePath(m.createState.branchName)
 					logging.Info("CreateView: navigating to worktree: %s", worktreePath)
-					return m, m.navigateToWorktree(m.createState.branchName, worktreePath)
+					return m, m.switchToWorktree(m.createState.branchName, worktreePath)
 				}
 				// Handle claude specially - quit TUI and launch claude in the worktree
 				if action.Command == "claude" {
@@ -700,8 +792,8 @@ func (m Model) handleDeleteSelectionKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		return m, nil
 	case key.Matches(msg, m.keys.Enter):
 		if len(m.deleteState.selectedWorktrees) > 0 {
-			logging.Info("DeleteView: proceeding to confirm with %d worktrees selected", len(m.deleteState.selectedWorktrees))
-			m.deleteState.currentStep = DeleteStepConfirm
+			logging.Info("DeleteView: prechecking %d selected worktrees", len(m.deleteState.selectedWorktrees))
+			return m, m.precheckSelectedWorktrees()
 		}
 		return m, nil
 	case msg.String() == " ": // Space key for toggling selection
@@ -762,6 +854,10 @@ func (m Model) handleDeleteConfirmKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 	case msg.String() == "y" || msg.String() == "Y":
+		if wt := m.deleteState.targetWorktree; wt != nil && wt.Operation != "" {
+			logging.Info("DeleteView: refusing to delete %s, %s in progress", wt.Name, wt.Operation)
+			return m, nil
+		}
 		// Proceed with deletion
 		logging.Info("DeleteView: user confirmed deletion")
 		m.deleteState.currentStep = DeleteStepDeleting
@@ -820,10 +916,10 @@ func (m Model) handleOpenInKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		if m.openInState.selectedIndex < len(m.openInState.actions) {
 			action := m.openInState.actions[m.openInState.selectedIndex]
 			logging.Info("OpenInView: executing action: %s on %s", action.Name, m.openInState.worktreePath)
-			// Handle navigate specially - it needs to quit TUI and write to temp file
+			// Handle navigate specially - it quits the TUI for the shell to cd
 			if action.Command == "navigate" {
 				logging.Info("OpenInView: navigating to worktree: %s", m.openInState.worktreePath)
-				return m, m.navigateToWorktree(m.openInState.worktreeName, m.openInState.worktreePath)
+				return m, m.switchToWorktree(m.openInState.worktreeName, m.openInState.worktreePath)
 			}
 			// Handle claude specially - quit TUI and launch claude in the worktree
 			if action.Command == "claude" {
@@ -1118,6 +1214,12 @@ func (m Model) handleStepCommitKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			// Toggle LLM option with tab (consistent with for-each view)
 			m.stepCommitState.useLLM = !m.stepCommitState.useLLM
 			return m, nil
+		case msg.String() == "f":
+			m.stepCommitState.currentStep = StepCommitStepFiles
+			if !m.stepCommitState.filesLoaded {
+				return m, m.loadChangedFiles()
+			}
+			return m, nil
 		case key.Matches(msg, m.keys.Enter):
 			if m.stepCommitState.useLLM {
 				// Generate LLM message first, then show it for review
@@ -1128,6 +1230,38 @@ func (m Model) handleStepCommitKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.stepCommitState.currentStep = StepCommitStepMessage
 			return m, nil
 		}
+	case StepCommitStepFiles:
+		state := m.stepCommitState
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, tea.Quit
+		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Enter):
+			state.currentStep = StepCommitStepOptions
+			return m, nil
+		case key.Matches(msg, m.keys.Up):
+			if state.fileCursor > 0 {
+				state.fileCursor--
+			}
+			return m, nil
+		case key.Matches(msg, m.keys.Down):
+			if state.fileCursor < len(state.files)-1 {
+				state.fileCursor++
+			}
+			return m, nil
+		case msg.String() == " ":
+			if state.fileCursor < len(state.picked) {
+				state.picked[state.fileCursor] = !state.picked[state.fileCursor]
+			}
+			return m, nil
+		case msg.String() == "a":
+			// Pick all, or clear the picks when everything is already picked
+			all := !slices.Contains(state.picked, false)
+			for i := range state.picked {
+				state.picked[i] = !all
+			}
+			return m, nil
+		}
+		return m, nil
 	case StepCommitStepGenerating:
 		// While generating, only allow quit or cancel
 		switch {
@@ -1201,23 +1335,24 @@ func (m Model) handleCompareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Back), key.Matches(msg, m.keys.Left), msg.String() == "h" || msg.String() == "H":
 			// Exit diff focus mode
 			m.compareState.diffFocused = false
+			m.syncCompareDiff(false)
 			return m, nil
-		case key.Matches(msg, m.keys.Up), msg.String() == "k" || msg.String() == "K":
-			// Scroll diff up
-			if m.compareState.diffScrollOffset > 0 {
-				m.compareState.diffScrollOffset--
-			}
+		case msg.String() == " ":
+			// Toggle the current hunk
+			m.compareState.toggleHunk()
+			m.syncCompareDiff(false)
 			return m, nil
-		case key.Matches(msg, m.keys.Down), msg.String() == "j" || msg.String() == "J":
-			// Scroll diff down
-			diffLines := len(strings.Split(m.compareState.diffContent, "\n"))
-			visibleLines := m.height - 10
-			if m.compareState.diffScrollOffset < diffLines-visibleLines {
-				m.compareState.diffScrollOffset++
-			}
+		case msg.String() == "n":
+			m.jumpToHunk(m.compareState.hunkIndex + 1)
+			return m, nil
+		case msg.String() == "p" || msg.String() == "N":
+			m.jumpToHunk(m.compareState.hunkIndex - 1)
 			return m, nil
 		}
-		return m, nil
+		// Everything else (j/k, arrows, pgup/pgdn, u/d) scrolls the viewport
+		var cmd tea.Cmd
+		m.compareState.diffViewport, cmd = m.compareState.diffViewport.Update(msg)
+		return m, cmd
 	}
 
 	// Normal mode (file list focused)
@@ -1240,21 +1375,19 @@ func (m Model) handleCompareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		// Navigate up in the file list
 		if m.compareState.selectedIndex > 0 {
 			m.compareState.selectedIndex--
-			m.compareState.diffScrollOffset = 0 // Reset diff scroll
 			// Adjust scroll offset if needed
 			if m.compareState.selectedIndex < m.compareState.scrollOffset {
 				m.compareState.scrollOffset = m.compareState.selectedIndex
 			}
 			// Load diff for new selection
 			file := m.compareState.files[m.compareState.selectedIndex]
-			return m, m.loadCompareDiff(m.compareState.sourcePath, file.Path)
+			return m, m.loadCompareDiff(m.compareState.sourcePath, m.compareState.targetPath, file.Path)
 		}
 		return m, nil
 	case key.Matches(msg, m.keys.Down), msg.String() == "j" || msg.String() == "J":
 		// Navigate down in the file list
 		if m.compareState.selectedIndex < len(m.compareState.files)-1 {
 			m.compareState.selectedIndex++
-			m.compareState.diffScrollOffset = 0 // Reset diff scroll
 			// Adjust scroll offset if needed
 			visibleLines := m.height - 10
 			if visibleLines < 5 {
@@ -1265,17 +1398,20 @@ func (m Model) handleCompareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			}
 			// Load diff for new selection
 			file := m.compareState.files[m.compareState.selectedIndex]
-			return m, m.loadCompareDiff(m.compareState.sourcePath, file.Path)
+			return m, m.loadCompareDiff(m.compareState.sourcePath, m.compareState.targetPath, file.Path)
 		}
 		return m, nil
 	case key.Matches(msg, m.keys.Enter), key.Matches(msg, m.keys.Right), msg.String() == "l" || msg.String() == "L":
-		// Enter diff focus mode (for scrolling)
+		// Enter diff focus mode (for scrolling and hunk selection)
 		m.compareState.diffFocused = true
+		m.syncCompareDiff(false)
 		return m, nil
 	case msg.String() == " ": // Space key
 		// Toggle selection for current file
 		if m.compareState.selectedIndex < len(m.compareState.files) {
 			m.compareState.files[m.compareState.selectedIndex].Selected = !m.compareState.files[m.compareState.selectedIndex].Selected
+			m.compareState.setAllHunks(m.compareState.files[m.compareState.selectedIndex].Path,
+				m.compareState.files[m.compareState.selectedIndex].Selected)
 			logging.Debug("CompareView: toggled selection for %s: %v",
 				m.compareState.files[m.compareState.selectedIndex].Path,
 				m.compareState.files[m.compareState.selectedIndex].Selected)
@@ -1294,6 +1430,7 @@ func (m Model) handleCompareKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 		newState := !allSelected
 		for i := range m.compareState.files {
 			m.compareState.files[i].Selected = newState
+			m.compareState.setAllHunks(m.compareState.files[i].Path, newState)
 		}
 		m.compareState.selectAll = newState
 		logging.Debug("CompareView: toggled all files to: %v", newState)
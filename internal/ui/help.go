@@ -41,6 +41,9 @@ func (m Model) renderHelpContent() string {
 				{"↓/j", "Move down"},
 				{"enter", "Open in... menu"},
 				{"g", "Go to worktree directory"},
+				{"/", "Filter by branch or path (esc clears)"},
+				{"s", "Cycle sort: recent, name, branch, status, stale"},
+				{"S", "Pin/unpin current worktree at the top"},
 			},
 		},
 		{
@@ -194,7 +197,7 @@ func (m Model) renderCompareHelpContent() string {
 	b.WriteString(sectionStyle.Render("Why not use a PR?"))
 	b.WriteString("\n")
 	whyNot := []string{
-		"• PRs merge entire branches - this picks individual files",
+		"• PRs merge entire branches - this picks files or hunks",
 		"• Works with uncommitted changes (no commit needed)",
 		"• Faster for quick experiments and prototypes",
 		"• No git history pollution for throwaway changes",
@@ -220,9 +223,10 @@ func (m Model) renderCompareHelpContent() string {
 		{"↑/↓/j/k", "Navigate files / Scroll diff"},
 		{"→/l", "Focus diff panel"},
 		{"←/h", "Back to file list"},
-		{"space", "Toggle file selection"},
+		{"space", "Toggle file / hunk selection"},
+		{"n/p", "Next / previous hunk (diff panel)"},
 		{"a", "Select/deselect all"},
-		{"y", "Apply selected files"},
+		{"y", "Apply selected files and hunks"},
 		{"esc", "Back"},
 	}
 
@@ -183,10 +183,10 @@ func (m Model) centerOverlay(baseView, modal string) string {
 }
 
 // runHookByType dispatches to the correct hook runner based on hook type
-func runHookByType(wm *core.WorktreeManager, hookType config.HookType, worktreePath, branchName, baseBranch string, autoYes bool) {
+func runHookByType(wm *core.WorktreeManager, hookType config.HookType, worktreePath, branchName, baseBranch, preset string, autoYes bool) {
 	switch hookType {
 	case config.HookPostCreate:
-		wm.RunPostCreateHookWithApproval(worktreePath, branchName, baseBranch, autoYes)
+		wm.RunPostCreateHookWithPreset(worktreePath, branchName, baseBranch, preset, autoYes)
 	case config.HookPreRemove:
 		wm.RunPreRemoveHookWithApproval(worktreePath, branchName, autoYes)
 	case config.HookPreMerge:
@@ -209,9 +209,12 @@ func runHookByType(wm *core.WorktreeManager, hookType config.HookType, worktreeP
 // Interactive hooks in the pre-approved path are not streamed (they need a
 // real terminal and are handled via tea.ExecProcess in approveAndRunHooks);
 // here we fall back to the old synchronous runHookByType for them.
-func (m *Model) showHookApproval(hookType config.HookType, worktreePath, branchName, baseBranch string) tea.Cmd {
+//
+// preset names the preset a worktree was created with, whose post-create
+// hook runs after the configured ones; it is "" for other hooks.
+func (m *Model) showHookApproval(hookType config.HookType, worktreePath, branchName, baseBranch, preset string) tea.Cmd {
 	wm := core.NewWorktreeManager(m.gitRepo, m.configManager)
-	unapproved := wm.GetUnapprovedHooks(hookType)
+	unapproved := wm.GetUnapprovedHooksWithPreset(hookType, preset)
 	hasInteractive := wm.HasInteractiveHooks(hookType)
 
 	if len(unapproved) == 0 {
@@ -219,7 +222,7 @@ func (m *Model) showHookApproval(hookType config.HookType, worktreePath, branchN
 			// Interactive + pre-approved: no TUI suspension machinery here,
 			// fall back to synchronous run. Rare path — user has previously
 			// approved an interactive hook, so terminal control is expected.
-			runHookByType(wm, hookType, worktreePath, branchName, baseBranch, true)
+			runHookByType(wm, hookType, worktreePath, branchName, baseBranch, preset, true)
 			return nil
 		}
 		// Non-interactive + pre-approved: run via the async pipeline so the
@@ -229,6 +232,7 @@ func (m *Model) showHookApproval(hookType config.HookType, worktreePath, branchN
 			BranchName:   branchName,
 			BaseBranch:   baseBranch,
 			RepoRoot:     m.repoRoot(),
+			Preset:       preset,
 		}
 		stream, firstCmd := startHookRun(wm, hookType, hookCtx, true)
 		m.hookRunningState = &HookRunningState{
@@ -250,6 +254,7 @@ func (m *Model) showHookApproval(hookType config.HookType, worktreePath, branchN
 		baseBranch:     baseBranch,
 		selectedIndex:  0, // Default to "Approve"
 		hasInteractive: hasInteractive,
+		preset:         preset,
 	}
 	return nil
 }
@@ -294,6 +299,7 @@ func (m Model) approveAndRunHooks() (tea.Model, tea.Cmd) {
 		BranchName:   state.branchName,
 		BaseBranch:   state.baseBranch,
 		RepoRoot:     m.repoRoot(),
+		Preset:       state.preset,
 	}
 	stream, firstCmd := startHookRun(wm, config.HookType(state.hookType), hookCtx, true)
 	m.hookRunningState = &HookRunningState{
@@ -327,12 +333,16 @@ func createHookRunnerCmd(wm *core.WorktreeManager, state *HookApprovalState) *ex
 	// We'll use the gren binary itself to run hooks
 	// This is a bit of a hack, but it works
 	grenPath, _ := os.Executable()
-	cmd := exec.Command(grenPath, "hook-run",
+	args := []string{"hook-run",
 		"--type", state.hookType,
 		"--path", state.worktreePath,
 		"--branch", state.branchName,
 		"--base", state.baseBranch,
-	)
+	}
+	if state.preset != "" {
+		args = append(args, "--preset", state.preset)
+	}
+	cmd := exec.Command(grenPath, args...)
 	return cmd
 }
 
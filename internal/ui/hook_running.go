@@ -14,21 +14,57 @@ import (
 
 // HookRunningState holds state for the live hook-execution modal.
 // Shown while a non-interactive hook runs; phases update in place as events
-// arrive. On failure the modal persists until a keypress so the user can read
-// which phase failed and find the events file for post-mortem.
+// arrive, and the hook's output scrolls by below them. On failure the modal
+// persists until a keypress so the user can read which phase failed and find
+// the events file for post-mortem.
 type HookRunningState struct {
 	visible  bool
 	hookType config.HookType
-	// stream carries both hookPhaseEventMsg and hookExecutionDoneMsg; closed
-	// when the background goroutine returns.
+	// stream carries hookPhaseEventMsg, hookOutputLineMsg and
+	// hookExecutionDoneMsg; closed when the background goroutine returns.
 	stream  <-chan tea.Msg
 	events  []events.Event
+	output  []string // Last hookOutputKeep lines the hook printed
+	scroll  int      // Output lines scrolled back from the newest; 0 follows the output
 	done    bool
 	results []core.HookResult
 	hookErr error
 	started time.Time
 }
 
+const (
+	hookOutputKeep    = 1000 // output lines kept for scrolling back
+	hookOutputVisible = 8    // output lines shown at once
+)
+
+// hookOutputLineMsg is dispatched for each line of output the hook prints.
+type hookOutputLineMsg struct {
+	line string
+}
+
+// appendOutput adds a line of hook output, keeping the view where it is when
+// scrolled back and dropping the oldest line beyond hookOutputKeep.
+func (st *HookRunningState) appendOutput(line string) {
+	st.output = append(st.output, line)
+	if len(st.output) > hookOutputKeep {
+		st.output = st.output[len(st.output)-hookOutputKeep:]
+	}
+	if st.scroll > 0 {
+		st.scroll = min(st.scroll+1, st.maxScroll())
+	}
+}
+
+// maxScroll is how far back the output can be scrolled.
+func (st *HookRunningState) maxScroll() int {
+	return max(len(st.output)-hookOutputVisible, 0)
+}
+
+// visibleOutput returns the output lines in view.
+func (st *HookRunningState) visibleOutput() []string {
+	end := len(st.output) - st.scroll
+	return st.output[max(end-hookOutputVisible, 0):end]
+}
+
 // hookPhaseEventMsg is dispatched each time the observer sees a new event.
 type hookPhaseEventMsg struct {
 	ev events.Event
@@ -70,6 +106,10 @@ func startHookRun(wm *core.WorktreeManager, hookType config.HookType, ctx core.H
 			ch <- hookPhaseEventMsg{ev: e}
 		})
 		defer wm.SetEventObserver(nil)
+		wm.SetHookOutputObserver(func(line string) {
+			ch <- hookOutputLineMsg{line: line}
+		})
+		defer wm.SetHookOutputObserver(nil)
 
 		// Guarantee a Done msg lands in the channel even if a hook runner
 		// panics. Without this the channel closes silently, waitForHookStream
@@ -91,7 +131,7 @@ func startHookRun(wm *core.WorktreeManager, hookType config.HookType, ctx core.H
 
 		switch hookType {
 		case config.HookPostCreate:
-			results = wm.RunPostCreateHookWithApproval(ctx.WorktreePath, ctx.BranchName, ctx.BaseBranch, autoYes)
+			results = wm.RunPostCreateHookWithPreset(ctx.WorktreePath, ctx.BranchName, ctx.BaseBranch, ctx.Preset, autoYes)
 		case config.HookPreRemove:
 			results = wm.RunPreRemoveHookWithApproval(ctx.WorktreePath, ctx.BranchName, autoYes)
 		case config.HookPostRemove:
@@ -164,6 +204,23 @@ func firstFailedErr(results []core.HookResult) error {
 	return nil
 }
 
+// notePostCreateHookFailure adds the post-create hooks that failed in
+// results to the warning the create view shows, as gren create does, and
+// reports a hooks_required failure as an error. The worktree stays either
+// way.
+func (m *Model) notePostCreateHookFailure(results []core.HookResult) {
+	if m.createState == nil {
+		return
+	}
+	warning, err := core.PostCreateHookFailure(m.config, results, m.createState.worktreePath)
+	if warning != "" {
+		m.createState.createWarning = strings.TrimPrefix(m.createState.createWarning+"\n"+warning, "\n")
+	}
+	if err != nil {
+		m.err = err
+	}
+}
+
 func eventsFileFromResults(results []core.HookResult) string {
 	for _, r := range results {
 		if r.EventsFile != "" {
@@ -208,13 +265,23 @@ func lastFailedOutput(results []core.HookResult, tailN int) string {
 	return strings.Join(parts, "\n")
 }
 
-// handleHookRunningKeys dismisses the modal on any key once the hook is done.
-// While the hook is still running, keys are swallowed (no escape hatch — the
-// hook has side effects and abandoning mid-run would leave partial state).
+// handleHookRunningKeys scrolls the hook output with up/down (k/j) and
+// dismisses the modal on any other key once the hook is done. While the hook
+// is still running, other keys are swallowed (no escape hatch — the hook has
+// side effects and abandoning mid-run would leave partial state; a hung hook
+// is killed after hook_timeout).
 func (m Model) handleHookRunningKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 	if m.hookRunningState == nil || !m.hookRunningState.visible {
 		return m, nil
 	}
+	switch msg.String() {
+	case "up", "k":
+		m.hookRunningState.scroll = min(m.hookRunningState.scroll+1, m.hookRunningState.maxScroll())
+		return m, nil
+	case "down", "j":
+		m.hookRunningState.scroll = max(m.hookRunningState.scroll-1, 0)
+		return m, nil
+	}
 	if !m.hookRunningState.done {
 		// Silently ignore; modal cannot be cancelled mid-run (hooks have
 		// side effects and abandoning mid-run leaves partial state).
@@ -274,6 +341,22 @@ func (m Model) renderHookRunningOverlay(baseView string) string {
 		}
 	}
 
+	if len(st.output) > 0 && !(st.done && st.hookErr != nil) {
+		mutedStyle := lipgloss.NewStyle().Foreground(ColorTextMuted)
+		content.WriteString("\n")
+		header := "Output:"
+		if st.maxScroll() > 0 {
+			header = fmt.Sprintf("Output (↑↓ to scroll, %d/%d):", len(st.output)-st.scroll, len(st.output))
+		}
+		content.WriteString(mutedStyle.Render(header))
+		content.WriteString("\n")
+		for _, line := range st.visibleOutput() {
+			content.WriteString("  ")
+			content.WriteString(mutedStyle.Render(line))
+			content.WriteString("\n")
+		}
+	}
+
 	if st.done && st.hookErr != nil {
 		errStyle := lipgloss.NewStyle().Foreground(ColorError)
 		mutedStyle := lipgloss.NewStyle().Foreground(ColorTextMuted)
@@ -2,6 +2,7 @@ package ui
 
 import (
 	"errors"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -299,3 +300,77 @@ func TestRenderHookRunningOverlay_HiddenWhenStateNil(t *testing.T) {
 		t.Errorf("expected base view returned unchanged, got %q", out)
 	}
 }
+
+func TestHookOutputLineMsg_AppendsAndReissuesStreamCmd(t *testing.T) {
+	ch := make(chan tea.Msg, 1)
+	m := Model{
+		hookRunningState: &HookRunningState{
+			visible: true, stream: ch, hookType: config.HookPostCreate,
+		},
+	}
+	updated, cmd := m.Update(hookOutputLineMsg{line: "installing…"})
+	m2 := updated.(Model)
+	if got := m2.hookRunningState.output; len(got) != 1 || got[0] != "installing…" {
+		t.Fatalf("output = %q, want the line appended", got)
+	}
+	if cmd == nil {
+		t.Fatal("expected non-nil cmd to keep the stream draining")
+	}
+	if view := m2.renderHookRunningOverlay(bigBaseView(40, 120)); !strings.Contains(view, "installing…") {
+		t.Errorf("overlay does not show the output line:\n%s", view)
+	}
+}
+
+func TestHookRunningOutputScrolls(t *testing.T) {
+	st := &HookRunningState{visible: true, hookType: config.HookPostCreate}
+	for i := 1; i <= 20; i++ {
+		st.appendOutput(fmt.Sprintf("line %d", i))
+	}
+	if got := st.visibleOutput(); got[len(got)-1] != "line 20" {
+		t.Fatalf("visible output ends with %q, want the newest line", got[len(got)-1])
+	}
+
+	m := Model{hookRunningState: st}
+	updated, _ := m.handleHookRunningKeys(tea.KeyMsg{Type: tea.KeyUp})
+	m = updated.(Model)
+	if got := m.hookRunningState.visibleOutput(); got[len(got)-1] != "line 19" {
+		t.Errorf("after up, visible output ends with %q, want line 19", got[len(got)-1])
+	}
+
+	// New output keeps a scrolled-back view where it is
+	m.hookRunningState.appendOutput("line 21")
+	if got := m.hookRunningState.visibleOutput(); got[len(got)-1] != "line 19" {
+		t.Errorf("after new output, visible output ends with %q, want line 19", got[len(got)-1])
+	}
+
+	// Scrolling keys don't dismiss a finished run
+	m.hookRunningState.done = true
+	updated, _ = m.handleHookRunningKeys(tea.KeyMsg{Type: tea.KeyDown})
+	m = updated.(Model)
+	if m.hookRunningState == nil {
+		t.Fatal("down dismissed the modal, want it to scroll")
+	}
+	updated, _ = m.handleHookRunningKeys(tea.KeyMsg{Type: tea.KeyEnter})
+	if updated.(Model).hookRunningState != nil {
+		t.Error("enter did not dismiss the finished modal")
+	}
+}
+
+func TestHookExecutionDone_PostCreateFailureWarns(t *testing.T) {
+	results := []core.HookResult{{Ran: true, Command: "setup.sh", Err: errors.New("exit status 3"), Stderr: "missing-dependency\n"}}
+	for _, required := range []bool{false, true} {
+		m := Model{
+			config:           &config.Config{HooksRequired: required},
+			createState:      &CreateState{createWarning: "base is behind", worktreePath: "/wt"},
+			hookRunningState: &HookRunningState{visible: true, hookType: config.HookPostCreate},
+		}
+		updated, _ := m.Update(hookExecutionDoneMsg{results: results})
+		got := updated.(Model)
+		if w := got.createState.createWarning; !strings.HasPrefix(w, "base is behind\n") || !strings.Contains(w, "missing-dependency") {
+			t.Errorf("createWarning = %q, want the create warning and the hook output", w)
+		}
+		if required != errors.Is(got.err, core.ErrHooksRequired) {
+			t.Errorf("hooks_required=%v: err = %v", required, got.err)
+		}
+	}
+}
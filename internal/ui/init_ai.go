@@ -2,6 +2,8 @@ package ui
 
 import (
 	"fmt"
+	"os/exec"
+	"regexp"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -26,6 +28,96 @@ func aiScriptScrollParams(termHeight, totalLines int) (visibleLines, maxOffset i
 	return visibleLines, maxOffset
 }
 
+// fencedBlockRe matches a markdown code fence and captures its info string
+// and body.
+var fencedBlockRe = regexp.MustCompile("(?ms)^[ \t]*```[ \t]*([A-Za-z0-9_+-]*)[^\n]*\n(.*?)^[ \t]*```[ \t]*$")
+
+// shellFenceLangs are the fence info strings taken to hold a shell script.
+var shellFenceLangs = map[string]bool{"bash": true, "sh": true, "shell": true, "zsh": true}
+
+// extractSetupScript pulls the setup script out of an LLM response. A
+// fenced shell block wins (a fence holding a shebang counts as one), then
+// everything from the first shebang line on. The result must pass
+// `bash -n`; if there is no script or it doesn't pass, the raw response
+// comes back with a warning so nothing unchecked is passed off as one.
+func extractSetupScript(output string) (script, warning string) {
+	raw := strings.TrimSpace(output)
+
+	script, found := fencedShellBlock(raw)
+	if !found {
+		script, found = shebangScript(raw)
+	}
+	if !found || script == "" {
+		return raw, "The response contained no script. Review it before using it."
+	}
+	if !strings.HasPrefix(script, "#!") {
+		script = "#!/bin/bash\n\n" + script
+	}
+
+	if err := checkShellSyntax(script); err != nil {
+		return raw, fmt.Sprintf("The response doesn't look like a valid script (%v). Review it before using it.", err)
+	}
+	return script, ""
+}
+
+// fencedShellBlock returns the first fenced block tagged as shell or
+// starting with a shebang, or failing that the only untagged block.
+func fencedShellBlock(text string) (string, bool) {
+	var untagged []string
+	for _, m := range fencedBlockRe.FindAllStringSubmatch(text, -1) {
+		lang, body := strings.ToLower(m[1]), strings.TrimSpace(m[2])
+		if shellFenceLangs[lang] || strings.HasPrefix(body, "#!") {
+			return body, true
+		}
+		if lang == "" {
+			untagged = append(untagged, body)
+		}
+	}
+	if len(untagged) == 1 {
+		return untagged[0], true
+	}
+	return "", false
+}
+
+// shebangScript returns everything from the first "#!/" line on, without a
+// closing fence.
+func shebangScript(text string) (string, bool) {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "#!/") {
+			continue
+		}
+		rest := lines[i:]
+		for j, l := range rest {
+			if strings.HasPrefix(strings.TrimSpace(l), "```") {
+				rest = rest[:j]
+				break
+			}
+		}
+		return strings.TrimSpace(strings.Join(rest, "\n")), true
+	}
+	return "", false
+}
+
+// checkShellSyntax runs `bash -n` over script. Without bash there is nothing
+// to check with, so the script passes.
+func checkShellSyntax(script string) error {
+	bash, err := exec.LookPath("bash")
+	if err != nil {
+		return nil
+	}
+	cmd := exec.Command(bash, "-n")
+	cmd.Stdin = strings.NewReader(script)
+	if out, err := cmd.CombinedOutput(); err != nil {
+		msg := strings.TrimSpace(string(out))
+		if msg == "" {
+			return err
+		}
+		return fmt.Errorf("%s", strings.TrimPrefix(strings.SplitN(msg, "\n", 2)[0], "bash: "))
+	}
+	return nil
+}
+
 // renderAIGeneratingStep shows the AI script generation in progress
 func (m Model) renderAIGeneratingStep() string {
 	var b strings.Builder
@@ -66,7 +158,11 @@ func (m Model) renderAIResultStep() string {
 	b.WriteString(WizardHeader("Generated Script"))
 	b.WriteString("\n\n")
 
-	b.WriteString(WizardSuccessStyle.Render(fmt.Sprintf("Script generated (%d lines)", totalLines)))
+	if m.initState.aiWarning != "" {
+		b.WriteString(WizardWarningStyle.Render(m.initState.aiWarning))
+	} else {
+		b.WriteString(WizardSuccessStyle.Render(fmt.Sprintf("Script generated (%d lines)", totalLines)))
+	}
 	b.WriteString("\n\n")
 
 	// Calculate visible area for script preview
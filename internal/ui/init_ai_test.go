@@ -0,0 +1,108 @@
+package ui
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestExtractSetupScript(t *testing.T) {
+	if _, err := exec.LookPath("bash"); err != nil {
+		t.Skip("bash not installed")
+	}
+
+	tests := []struct {
+		name        string
+		output      string
+		want        string
+		wantWarning bool
+	}{
+		{
+			name:   "script only",
+			output: "#!/bin/bash\nset -e\nnpm install\n",
+			want:   "#!/bin/bash\nset -e\nnpm install",
+		},
+		{
+			name: "fenced with prose",
+			output: "Here's the setup script for your project:\n\n" +
+				"```bash\n#!/bin/bash\nset -e\nbun install\n```\n\n" +
+				"It installs dependencies with bun.",
+			want: "#!/bin/bash\nset -e\nbun install",
+		},
+		{
+			name:   "fenced without shebang",
+			output: "```sh\nnpm ci\n```",
+			want:   "#!/bin/bash\n\nnpm ci",
+		},
+		{
+			name: "shell fence preferred over others",
+			output: "Create `.env` like this:\n\n```ini\nPORT=3000\n```\n\n" +
+				"Then run:\n\n```bash\ncp .env.example .env\n```",
+			want: "#!/bin/bash\n\ncp .env.example .env",
+		},
+		{
+			name: "prose then script",
+			output: "I looked at package.json and the lockfile.\n\n" +
+				"#!/bin/bash\nset -e\npnpm install\n```",
+			want: "#!/bin/bash\nset -e\npnpm install",
+		},
+		{
+			name:   "multi-line constructs survive",
+			output: "```bash\n#!/bin/bash\nif [ -f .env ]; then\n  echo ok\nfi\n```",
+			want:   "#!/bin/bash\nif [ -f .env ]; then\n  echo ok\nfi",
+		},
+		{
+			name:        "prose only",
+			output:      "I couldn't determine how to set up this project.",
+			want:        "I couldn't determine how to set up this project.",
+			wantWarning: true,
+		},
+		{
+			name:        "broken script",
+			output:      "```bash\n#!/bin/bash\nif [ -f .env ]; then\n  echo ok\n```",
+			want:        "```bash\n#!/bin/bash\nif [ -f .env ]; then\n  echo ok\n```",
+			wantWarning: true,
+		},
+		{
+			name:        "empty",
+			output:      "  \n",
+			want:        "",
+			wantWarning: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, warning := extractSetupScript(tt.output)
+			if got != tt.want {
+				t.Errorf("script = %q, want %q", got, tt.want)
+			}
+			if (warning != "") != tt.wantWarning {
+				t.Errorf("warning = %q, wantWarning %v", warning, tt.wantWarning)
+			}
+		})
+	}
+}
+
+func TestAIScriptWarningShown(t *testing.T) {
+	m := Model{keys: DefaultKeyMap(), height: 40, width: 100, initState: &InitState{currentStep: InitStepAIGenerating}}
+
+	updated, _ := m.Update(aiScriptGeneratedMsg{script: "not a script", warning: "The response doesn't look like a valid script"})
+	m = updated.(Model)
+	view := m.renderAIResultStep()
+	if !strings.Contains(view, "doesn't look like a valid script") {
+		t.Errorf("result step should show the warning, got:\n%s", view)
+	}
+	if strings.Contains(view, "Script generated") {
+		t.Errorf("result step should not claim success when validation failed")
+	}
+	if m.initState.selected != 2 {
+		t.Errorf("selected = %d, want \"Edit manually instead\" as the default", m.initState.selected)
+	}
+
+	updated, _ = m.Update(aiScriptGeneratedMsg{script: "#!/bin/bash\necho ok"})
+	m = updated.(Model)
+	if m.initState.selected != 0 {
+		t.Errorf("selected = %d, want \"Use this script\" as the default for a valid script", m.initState.selected)
+	}
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/langtind/gren/internal/launcher"
+)
+
+// launcherSettings returns the configured terminal command and editor.
+func (m Model) launcherSettings() (terminal, editor string) {
+	return launcher.Settings(m.config)
+}
+
+// openInEditor opens filePath in editor. Terminal editors suspend the TUI via
+// tea.ExecProcess; GUI editors are started in the background. done turns the
+// outcome into the caller's message.
+func openInEditor(editor []string, filePath string, done func(error) tea.Msg) tea.Cmd {
+	args := append(append([]string{}, editor[1:]...), filePath)
+
+	if launcher.IsTerminalEditor(editor[0]) {
+		cmd := exec.Command(editor[0], args...)
+		return tea.ExecProcess(cmd, done)
+	}
+
+	return func() tea.Msg {
+		cmd := exec.Command(editor[0], args...)
+		if err := cmd.Start(); err != nil {
+			return done(fmt.Errorf("failed to open %s: %w", editor[0], err))
+		}
+		return done(nil)
+	}
+}
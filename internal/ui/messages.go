@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"github.com/langtind/gren/internal/config"
 	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/git"
 )
@@ -19,14 +20,24 @@ type initializeMsg struct {
 type createInitMsg struct {
 	branchStatuses  []BranchStatus
 	recommendedBase string
+	presets         map[string]config.Preset
+	presetNames     []string
 	err             error
 }
 
 type deleteInitMsg struct {
 	selectedWorktree *Worktree // Specific worktree to delete, nil for multi-select
+	issues           []string  // What PrecheckDelete found for selectedWorktree
 	err              error
 }
 
+// deletePrecheckMsg carries what PrecheckDelete found for the worktrees
+// picked in a multi-select delete
+type deletePrecheckMsg struct {
+	issues []string
+	err    error
+}
+
 type projectAnalysisCompleteMsg struct{}
 
 type initExecutionCompleteMsg struct {
@@ -39,13 +50,15 @@ type initExecutionCompleteMsg struct {
 
 type worktreeCreatedMsg struct {
 	branchName string
+	path       string
 	warning    string // Warning message (e.g., "main has 2 unpushed commits")
 	err        error
 }
 
 type worktreeDeletedMsg struct {
-	deletedCount int
-	err          error
+	deletedCount    int
+	danglingRemoved int // Dangling symlinks removed so the delete could go through
+	err             error
 }
 
 type openInInitializedMsg struct {
@@ -107,14 +120,64 @@ type cleanupFinishedMsg struct {
 	totalFailed  int
 }
 
+// cleanupSizesMsg carries the disk usage of the stale worktrees, measured
+// after the cleanup confirmation is shown.
+type cleanupSizesMsg struct {
+	sizes map[string]int64 // Worktree path → bytes
+}
+
+// diskUsageMsg carries the disk usage of one worktree, measured in the
+// background when it is selected.
+type diskUsageMsg struct {
+	path string
+	size int64
+	err  error
+}
+
+// diffStatMsg carries the uncommitted changes of one worktree, loaded in the
+// background when it is selected.
+type diffStatMsg struct {
+	path  string
+	files []core.FileStat
+	err   error
+}
+
 type aiScriptGeneratedMsg struct {
-	script string
-	err    error
+	script  string
+	warning string // Set when script is the raw response, not a checked script
+	err     error
 }
 
 type githubRefreshCompleteMsg struct {
 	worktrees []Worktree
 	ghStatus  core.GitHubStatus
+	// prOnly means only the PR and CI fields of worktrees are current, so
+	// they are merged into the list instead of replacing it
+	prOnly bool
+}
+
+// markersLoadedMsg carries the Claude activity markers by branch, reloaded
+// every markerRefreshInterval while the TUI runs
+type markersLoadedMsg struct {
+	markers       map[string]core.MarkerType
+	markerManager *core.MarkerManager // What read them, for the next poll
+}
+
+// markerRefreshMsg asks for the markers to be reloaded
+type markerRefreshMsg struct{}
+
+// worktreeStatusMsg carries the file counts and status of one worktree,
+// loaded in the background after the dashboard first renders
+type worktreeStatusMsg struct {
+	generation int
+	worktree   core.WorktreeInfo
+}
+
+// worktreeStaleMsg carries the git-based stale status of the worktrees,
+// keyed by path
+type worktreeStaleMsg struct {
+	generation int
+	stale      map[string]core.WorktreeInfo
 }
 
 type openPRCompleteMsg struct {
@@ -124,6 +187,7 @@ type openPRCompleteMsg struct {
 type compareInitMsg struct {
 	sourceWorktree string
 	sourcePath     string
+	targetPath     string
 	files          []CompareFileItem
 	err            error
 }
@@ -138,7 +202,9 @@ type compareDiffViewedMsg struct {
 }
 
 type compareDiffLoadedMsg struct {
+	path    string
 	content string
+	hunks   []core.Hunk
 	err     error
 }
 
@@ -159,6 +225,12 @@ type forEachItemCompleteMsg struct {
 
 type forEachCompleteMsg struct{}
 
+// changedFilesLoadedMsg carries the files step commit can pick from
+type changedFilesLoadedMsg struct {
+	files []core.ChangedFile
+	err   error
+}
+
 type stepCommitCompleteMsg struct {
 	result string
 	err    error
@@ -172,6 +244,7 @@ type llmMessageGeneratedMsg struct {
 type navigateCompleteMsg struct {
 	worktreeName string
 	worktreePath string
+	newTerminal  bool // Opened in a new terminal (no shell integration), so the TUI stays open
 	err          error
 }
 
@@ -1,6 +1,7 @@
 package ui
 
 import (
+	"context"
 	"fmt"
 	"os"
 	"path/filepath"
@@ -8,26 +9,91 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/logging"
 )
 
-// Update handles all incoming messages and updates the model state
+// Update handles all incoming messages and updates the model state. After
+// a key press or a (re)load of the worktrees it loads the selected
+// worktree's changed files and size for the preview, if needed.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	updated, cmd := m.update(msg)
+	switch msg.(type) {
+	case tea.KeyMsg, worktreeStatusMsg, projectInfoMsg:
+		if um, ok := updated.(Model); ok {
+			load := um.loadSelectedDiffStat()
+			measure := um.measureSelectedDiskUsage()
+			if load != nil || measure != nil {
+				return um, tea.Batch(cmd, load, measure)
+			}
+		}
+	}
+	return updated, cmd
+}
+
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
+		m.syncCompareDiff(false)
 		return m, nil
 
 	case projectInfoMsg:
 		m = m.updateProjectInfo(msg.info, msg.err)
-		// Start async GitHub check if we have worktrees
+		// Sizes are measured again as worktrees are selected
+		m.diskUsage = nil
+		// Start async status and GitHub check if we have worktrees
 		if len(m.worktrees) > 0 {
 			m.githubLoading = true
-			return m, tea.Batch(m.githubSpinner.Tick, m.startGitHubCheck())
+			cmds := []tea.Cmd{m.githubSpinner.Tick, m.loadWorktreeDetails(), m.startGitHubCheck()}
+			if !m.markerPolling {
+				m.markerPolling = true
+				cmds = append(cmds, m.loadMarkers())
+			}
+			return m, tea.Batch(cmds...)
+		}
+		return m, nil
+
+	case markersLoadedMsg:
+		if msg.markerManager != nil {
+			m.markerManager = msg.markerManager
+		}
+		// nil means the read failed; keep what is shown and try again
+		if msg.markers != nil {
+			m.applyMarkers(msg.markers)
+		}
+		return m, scheduleMarkerRefresh()
+
+	case markerRefreshMsg:
+		return m, m.loadMarkers()
+
+	case worktreeStatusMsg:
+		if msg.generation == m.loadGeneration {
+			m.applyWorktreeStatus(msg.worktree)
+			delete(m.diffStats, msg.worktree.Path)
+		}
+		return m, nil
+
+	case diffStatMsg:
+		delete(m.diffStatLoading, msg.path)
+		if msg.err != nil {
+			// Cached empty, so navigation doesn't rerun a failing git
+			logging.Warn("Dashboard: diff stat for %s: %v", msg.path, msg.err)
+		}
+		if m.diffStats == nil {
+			m.diffStats = make(map[string][]core.FileStat)
+		}
+		m.diffStats[msg.path] = msg.files
+		return m, nil
+
+	case worktreeStaleMsg:
+		if msg.generation == m.loadGeneration {
+			m.applyStaleStatus(msg.stale)
 		}
 		return m, nil
 
@@ -52,13 +118,27 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		if msg.selectedWorktree != nil {
 			// Delete specific worktree
-			m.setupDeleteStateForWorktree(*msg.selectedWorktree)
+			m.setupDeleteStateForWorktree(*msg.selectedWorktree, msg.issues)
 		} else {
 			// Multi-select delete
 			m.setupDeleteState()
 		}
 		return m, nil
 
+	case deletePrecheckMsg:
+		if m.deleteState == nil || m.deleteState.currentStep != DeleteStepSelection {
+			return m, nil
+		}
+		if msg.err != nil {
+			// Stay on the selection so the worktree can be unpicked
+			m.err = msg.err
+			return m, nil
+		}
+		logging.Info("DeleteView: proceeding to confirm with %d worktrees selected", len(m.deleteState.selectedWorktrees))
+		m.deleteState.warnings = msg.issues
+		m.deleteState.currentStep = DeleteStepConfirm
+		return m, nil
+
 	case projectAnalysisCompleteMsg:
 		if m.initState != nil {
 			m.initState.currentStep = InitStepRecommendations
@@ -179,6 +259,26 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case cleanupSizesMsg:
+		if m.cleanupState != nil {
+			m.cleanupState.sizes = msg.sizes
+		}
+		return m, nil
+
+	case diskUsageMsg:
+		delete(m.diskUsageLoading, msg.path)
+		if msg.err != nil {
+			// Left unknown rather than cached, and measured again on the
+			// next selection
+			logging.Warn("Dashboard: disk usage of %s: %v", msg.path, msg.err)
+			return m, nil
+		}
+		if m.diskUsage == nil {
+			m.diskUsage = make(map[string]int64)
+		}
+		m.diskUsage[msg.path] = msg.size
+		return m, nil
+
 	case cleanupItemStartMsg:
 		// Mark worktree as currently being deleted (triggers spinner display)
 		if m.cleanupState != nil {
@@ -274,7 +374,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case githubRefreshCompleteMsg:
 		// GitHub refresh complete - update worktrees with PR info
 		logging.Info("GitHub refresh complete: %d worktrees updated", len(msg.worktrees))
-		m.worktrees = msg.worktrees
+		if msg.prOnly {
+			m.mergePRStatus(msg.worktrees)
+		} else {
+			m.worktrees = msg.worktrees
+		}
 		m.githubLoading = false
 		m.err = nil
 		return m, nil
@@ -296,15 +400,18 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.compareState = &CompareState{
 			sourceWorktree: msg.sourceWorktree,
 			sourcePath:     msg.sourcePath,
+			targetPath:     msg.targetPath,
 			files:          msg.files,
 			selectedIndex:  0,
 			scrollOffset:   0,
 			selectAll:      true, // All selected by default
 			diffContent:    "",   // Will be loaded below
+			diffViewport:   viewport.New(0, 0),
+			fileHunks:      make(map[string][]compareHunk),
 		}
 		// Load diff for first file
 		if len(msg.files) > 0 {
-			return m, m.loadCompareDiff(msg.sourcePath, msg.files[0].Path)
+			return m, m.loadCompareDiff(msg.sourcePath, msg.targetPath, msg.files[0].Path)
 		}
 		return m, nil
 
@@ -333,8 +440,24 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.compareState.diffContent = fmt.Sprintf("Error loading diff: %v", msg.err)
 			} else {
 				m.compareState.diffContent = msg.content
-				m.compareState.diffScrollOffset = 0
 			}
+			m.compareState.diffPath = msg.path
+			m.compareState.hunkIndex = 0
+			// Keep earlier hunk choices for a file unless its diff changed shape.
+			if len(m.compareState.fileHunks[msg.path]) != len(msg.hunks) {
+				selected := false
+				for _, f := range m.compareState.files {
+					if f.Path == msg.path {
+						selected = f.Selected
+					}
+				}
+				hunks := make([]compareHunk, len(msg.hunks))
+				for i, h := range msg.hunks {
+					hunks[i] = compareHunk{hunk: h, selected: selected}
+				}
+				m.compareState.fileHunks[msg.path] = hunks
+			}
+			m.syncCompareDiff(true)
 		}
 		return m, nil
 
@@ -372,6 +495,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case changedFilesLoadedMsg:
+		if m.stepCommitState != nil {
+			if msg.err != nil {
+				m.stepCommitState.currentStep = StepCommitStepComplete
+				m.stepCommitState.err = msg.err
+				return m, nil
+			}
+			m.stepCommitState.files = msg.files
+			m.stepCommitState.filesLoaded = true
+			m.stepCommitState.picked = make([]bool, len(msg.files))
+			m.stepCommitState.fileCursor = 0
+		}
+		return m, nil
+
 	case llmMessageGeneratedMsg:
 		if m.stepCommitState != nil {
 			if msg.err != nil {
@@ -412,6 +549,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case hookOutputLineMsg:
+		// A line of output from a running non-interactive hook.
+		if m.hookRunningState != nil {
+			m.hookRunningState.appendOutput(msg.line)
+			return m, waitForHookStream(m.hookRunningState.stream)
+		}
+		return m, nil
+
 	case hookExecutionDoneMsg:
 		// Non-interactive hook finished. Mark done, record results, and
 		// schedule auto-dismiss on success. Failure persists until keypress.
@@ -419,6 +564,9 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.hookRunningState.done = true
 			m.hookRunningState.results = msg.results
 			m.hookRunningState.hookErr = firstFailedErr(msg.results)
+			if m.hookRunningState.hookType == config.HookPostCreate {
+				m.notePostCreateHookFailure(msg.results)
+			}
 		}
 		m.refreshWorktrees()
 		if m.hookRunningState != nil && m.hookRunningState.hookErr == nil {
@@ -442,17 +590,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Refresh project info to check if .gren was deleted/created
 				return m, m.loadProjectInfo()
 			} else {
-				// Refresh worktrees list after successful creation
+				// Refresh worktrees list after successful creation; a size
+				// kept for a worktree once at this path is stale
 				m.refreshWorktrees()
+				delete(m.diskUsage, msg.path)
 				m.createState.currentStep = CreateStepComplete
 				m.createState.createWarning = msg.warning // Store warning for display
+				m.createState.worktreePath = msg.path
 				m.initializeActionsList()
 
 				// Check for unapproved post-create hooks. Pre-approved hooks
 				// start running immediately (non-interactive) — the returned
 				// Cmd is the stream-wait that feeds live events into the UI.
 				worktreePath := m.getWorktreePath(m.createState.branchName)
-				cmd := m.showHookApproval(config.HookPostCreate, worktreePath, m.createState.branchName, m.createState.baseBranch)
+				cmd := m.showHookApproval(config.HookPostCreate, worktreePath, m.createState.branchName, m.createState.baseBranch, m.createState.preset)
 				return m, cmd
 			}
 		}
@@ -468,6 +619,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				// Refresh worktrees list after successful deletion
 				m.refreshWorktrees()
+				m.deleteState.danglingRemoved = msg.danglingRemoved
 				m.deleteState.currentStep = DeleteStepComplete
 			}
 		}
@@ -483,6 +635,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.err = fmt.Errorf("navigation failed: %w", msg.err)
 			return m, nil
 		}
+		if msg.newTerminal {
+			// Back to the dashboard, as after any other "Open in..." action
+			if m.createState != nil {
+				m.refreshWorktrees()
+			}
+			m.currentView = DashboardView
+			m.createState = nil
+			m.openInState = nil
+			m.statusMessage = fmt.Sprintf("Opened %s in a new terminal (set up gren shell-init to switch in place)", msg.worktreeName)
+			return m, clearStatusAfter(5 * time.Second)
+		}
 		// Set exit message - just show worktree name, shell wrapper shows path
 		m.ExitMessage = fmt.Sprintf("✅ Navigating to %s", msg.worktreeName)
 		logging.Info("navigateCompleteMsg: ExitMessage set, quitting")
@@ -530,10 +693,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.initState.aiError = msg.err.Error()
 			} else {
 				m.initState.aiGeneratedScript = msg.script
+				m.initState.aiWarning = msg.warning
 				m.initState.aiError = ""
 			}
 			m.initState.currentStep = InitStepAIResult
 			m.initState.selected = 0
+			if m.initState.aiWarning != "" {
+				// Don't default to using output that failed validation
+				m.initState.selected = 2
+			}
 		}
 		return m, nil
 
@@ -544,8 +712,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case spinner.TickMsg:
 		var cmds []tea.Cmd
 
-		// Handle GitHub loading spinner
-		if m.githubLoading {
+		// Handle GitHub / disk usage / worktree status loading spinner
+		if m.githubLoading || len(m.diskUsageLoading) > 0 || m.statusLoading() {
 			var cmd tea.Cmd
 			m.githubSpinner, cmd = m.githubSpinner.Update(msg)
 			cmds = append(cmds, cmd)
@@ -594,8 +762,8 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Handle keyboard input based on current view
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		// Handle help toggle globally on dashboard
-		if m.currentView == DashboardView && key.Matches(keyMsg, m.keys.Help) {
+		// Handle help toggle globally on dashboard ("?" is just text while filtering)
+		if m.currentView == DashboardView && !m.filtering && key.Matches(keyMsg, m.keys.Help) {
 			m.helpVisible = !m.helpVisible
 			return m, nil
 		}
@@ -659,6 +827,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Dashboard keys
 		logging.Debug("Dashboard key: %q", keyMsg.String())
 
+		// The filter input captures all typing while it has focus
+		if m.filtering {
+			return m.handleFilterKeys(keyMsg)
+		}
+
 		// Global keys
 		switch {
 		case key.Matches(keyMsg, m.keys.Quit):
@@ -669,10 +842,30 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selected--
 			}
 		case key.Matches(keyMsg, m.keys.Down):
-			if m.selected < len(m.worktrees)-1 {
+			if m.selected < len(m.getSortedWorktrees())-1 {
 				m.selected++
 			}
 
+		case key.Matches(keyMsg, m.keys.Filter):
+			logging.Debug("Dashboard: filter input opened")
+			m.filtering = true
+			return m, nil
+
+		case key.Matches(keyMsg, m.keys.Sort):
+			m.setSort(m.currentSortMode().Next(), m.unpinCurrent)
+			logging.Debug("Dashboard: sort mode %s", m.sortMode)
+			return m, nil
+
+		case key.Matches(keyMsg, m.keys.PinSort):
+			m.setSort(m.currentSortMode(), !m.unpinCurrent)
+			logging.Debug("Dashboard: current worktree pinned: %v", !m.unpinCurrent)
+			return m, nil
+
+		case m.filterQuery != "" && key.Matches(keyMsg, m.keys.Back):
+			logging.Debug("Dashboard: filter cleared")
+			m.setFilter("")
+			return m, nil
+
 		case key.Matches(keyMsg, m.keys.Enter):
 			// Show "Open in..." menu for selected worktree
 			if selectedWorktree := m.getSelectedWorktree(); selectedWorktree != nil {
@@ -705,6 +898,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.statusMessage = "⚠️ Cannot delete current worktree"
 					return m, clearStatusAfter(3 * time.Second)
 				}
+				if selectedWorktree.IsBare {
+					logging.Debug("Dashboard: cannot delete bare repository: %s", selectedWorktree.Name)
+					m.statusMessage = "⚠️ Cannot delete the bare repository"
+					return m, clearStatusAfter(3 * time.Second)
+				}
 				logging.Info("Dashboard: entering DeleteView for worktree: %s (shortcut 'd')", selectedWorktree.Name)
 				m.currentView = DeleteView
 				return m, m.initializeDeleteStateForWorktree(*selectedWorktree)
@@ -748,7 +946,7 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			// Navigate to selected worktree directory
 			if selectedWorktree := m.getSelectedWorktree(); selectedWorktree != nil {
 				logging.Info("Dashboard: navigating to worktree: %s (shortcut 'g')", selectedWorktree.Name)
-				return m, m.navigateToWorktree(selectedWorktree.Name, selectedWorktree.Path)
+				return m, m.switchToWorktree(selectedWorktree.Name, selectedWorktree.Path)
 			}
 			return m, nil
 		case key.Matches(keyMsg, m.keys.Tools):
@@ -867,8 +1065,14 @@ func (m Model) buildBaseView() string {
 	return baseView
 }
 
-// generateDefaultWorktreeDir creates a default worktree directory name based on current working directory
+// generateDefaultWorktreeDir returns the user config's default worktree
+// directory, which init writes when set, or one named after the current
+// working directory
 func (m Model) generateDefaultWorktreeDir() string {
+	if user, err := config.NewUserConfigManager().Load(); err == nil && user.Defaults.WorktreeDir != "" {
+		return user.Defaults.WorktreeDir
+	}
+
 	// Get current working directory
 	cwd, err := os.Getwd()
 	if err != nil {
@@ -895,7 +1099,29 @@ func (m Model) getWorktreeDir() string {
 	return m.generateDefaultWorktreeDir()
 }
 
-// getWorktreePath returns the full path for a worktree given a branch name
+// getWorktreePath returns the full path for a worktree given a branch name:
+// the path the create wizard resolved or created, else the configured
+// worktree directory joined with the sanitized branch.
 func (m Model) getWorktreePath(branchName string) string {
-	return fmt.Sprintf("%s/%s", m.getWorktreeDir(), sanitizeBranchForPath(branchName))
+	if m.createState != nil && m.createState.branchName == branchName && m.createState.worktreePath != "" {
+		return m.createState.worktreePath
+	}
+	return fmt.Sprintf("%s/%s", m.getWorktreeDir(), m.sanitizeBranchForPath(branchName))
+}
+
+// resolveWorktreePath asks core where a worktree for branchName will go, so
+// the wizard shows, and hooks and actions get, the same path `gren create`
+// uses: a relative worktree_dir resolves against the main worktree, not the
+// directory the TUI was started in. It is "" when core cannot tell.
+func (m Model) resolveWorktreePath(branchName string) string {
+	if m.configManager == nil {
+		return ""
+	}
+	wm := core.NewWorktreeManager(m.gitRepo, m.configManager)
+	path, err := wm.WorktreePath(context.Background(), branchName, branchName)
+	if err != nil {
+		logging.Warn("resolveWorktreePath: %v", err)
+		return ""
+	}
+	return path
 }
@@ -53,20 +53,138 @@ func (m Model) updateProjectInfo(info *git.RepoInfo, err error) Model {
 		if m.configManager != nil {
 			if cfg, err := m.configManager.Load(); err == nil {
 				m.config = cfg
+				if len(cfg.Warnings) > 0 {
+					m.statusMessage = "⚠️ " + cfg.Warnings[0]
+					if len(cfg.Warnings) > 1 {
+						m.statusMessage += fmt.Sprintf(" (+%d more)", len(cfg.Warnings)-1)
+					}
+				}
 			}
 		}
 
-		// Refresh worktrees list
-		if err := m.refreshWorktrees(); err != nil {
-			m.err = err
-		}
+		// Show the worktrees right away; status follows via loadWorktreeDetails
+		m.loadWorktreesBasic()
 	}
 
 	return m
 }
 
+// loadWorktreesBasic lists the worktrees without their status, marking each
+// row as loading. It starts a new load generation, so loadWorktreeDetails
+// must be called afterwards to fill the rows in.
+func (m *Model) loadWorktreesBasic() {
+	m.loadGeneration++
+	m.staleLoading = false
+	if m.repoInfo == nil || !m.repoInfo.IsGitRepo {
+		m.worktrees = nil
+		return
+	}
+
+	worktreeManager := core.NewWorktreeManager(m.gitRepo, m.configManager)
+	coreWorktrees, err := worktreeManager.ListWorktreesBasic(context.Background())
+	if err != nil {
+		m.worktrees = nil
+		return
+	}
+
+	m.worktrees = make([]Worktree, len(coreWorktrees))
+	for i, wt := range coreWorktrees {
+		m.worktrees[i] = convertCoreWorktreeToUI(wt)
+		m.worktrees[i].Loading = wt.Status != "missing"
+	}
+	m.staleLoading = len(m.worktrees) > 0
+}
+
+// statusLoading reports whether any worktree's status is still loading.
+func (m Model) statusLoading() bool {
+	if m.staleLoading {
+		return true
+	}
+	for _, wt := range m.worktrees {
+		if wt.Loading {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMarkers sets each worktree's marker from markers, keyed by branch,
+// clearing the markers of branches that no longer have one.
+func (m *Model) applyMarkers(markers map[string]core.MarkerType) {
+	for i := range m.worktrees {
+		m.worktrees[i].Marker = string(markers[m.worktrees[i].Branch])
+	}
+}
+
+// applyWorktreeStatus fills in the status of the worktree at wt.Path.
+func (m *Model) applyWorktreeStatus(wt core.WorktreeInfo) {
+	for i := range m.worktrees {
+		if m.worktrees[i].Path != wt.Path {
+			continue
+		}
+		row := &m.worktrees[i]
+		row.Status = wt.Status
+		row.StatusError = wt.StatusError
+		row.StagedCount = wt.StagedCount
+		row.ModifiedCount = wt.ModifiedCount
+		row.UntrackedCount = wt.UntrackedCount
+		row.UnpushedCount = wt.UnpushedCount
+		row.NoRemote = wt.NoRemote
+		row.HasSubmodules = wt.HasSubmodules
+		row.SubmoduleState = wt.SubmoduleState
+		row.Operation = wt.Operation
+		row.Loading = false
+		return
+	}
+}
+
+// applyStaleStatus fills in the git-based stale status of each worktree.
+// A stale reason derived from the PR wins, since it may have arrived first,
+// except over a deleted branch, which leaves the worktree broken either way.
+func (m *Model) applyStaleStatus(stale map[string]core.WorktreeInfo) {
+	for i := range m.worktrees {
+		row := &m.worktrees[i]
+		wt, ok := stale[row.Path]
+		if !ok || (row.StaleReason == "pr_merged" || row.StaleReason == "pr_closed") && wt.StaleReason != "branch_deleted" {
+			continue
+		}
+		row.BranchStatus = wt.BranchStatus
+		row.StaleReason = wt.StaleReason
+	}
+	m.staleLoading = false
+}
+
+// mergePRStatus copies the PR and CI fields of worktrees into the rows with
+// the same path, leaving the status loaded in the meantime alone.
+func (m *Model) mergePRStatus(worktrees []Worktree) {
+	byPath := make(map[string]Worktree, len(worktrees))
+	for _, wt := range worktrees {
+		byPath[wt.Path] = wt
+	}
+	for i := range m.worktrees {
+		row := &m.worktrees[i]
+		wt, ok := byPath[row.Path]
+		if !ok {
+			continue
+		}
+		row.PRNumber = wt.PRNumber
+		row.PRState = wt.PRState
+		row.PRURL = wt.PRURL
+		row.CIStatus = wt.CIStatus
+		row.CIConclusion = wt.CIConclusion
+		row.CIURL = wt.CIURL
+		if (wt.StaleReason == "pr_merged" || wt.StaleReason == "pr_closed") && row.StaleReason != "branch_deleted" {
+			row.BranchStatus = wt.BranchStatus
+			row.StaleReason = wt.StaleReason
+		}
+	}
+}
+
 // refreshWorktrees refreshes the list of worktrees
 func (m *Model) refreshWorktrees() error {
+	// Results of a progressive load still in flight are now outdated
+	m.loadGeneration++
+	m.staleLoading = false
 	if m.repoInfo == nil || !m.repoInfo.IsGitRepo {
 		m.worktrees = nil
 		return nil
@@ -96,15 +214,23 @@ func convertCoreWorktreeToUI(wt core.WorktreeInfo) Worktree {
 		Path:           wt.Path,
 		Branch:         wt.Branch,
 		Status:         wt.Status,
+		StatusError:    wt.StatusError,
 		IsCurrent:      wt.IsCurrent,
 		IsPrevious:     wt.IsPrevious,
 		IsMain:         wt.IsMain,
+		IsBare:         wt.IsBare,
 		LastCommit:     wt.LastCommit,
 		StagedCount:    wt.StagedCount,
 		ModifiedCount:  wt.ModifiedCount,
 		UntrackedCount: wt.UntrackedCount,
 		UnpushedCount:  wt.UnpushedCount,
 		HasSubmodules:  wt.HasSubmodules,
+		SubmoduleState: wt.SubmoduleState,
+		Operation:      wt.Operation,
+		BranchMismatch: wt.BranchMismatch,
+		BrokenLink:     wt.BrokenLink,
+		NoRemote:       wt.NoRemote,
+		Created:        wt.Created,
 		BranchStatus:   wt.BranchStatus,
 		StaleReason:    wt.StaleReason,
 		PRNumber:       wt.PRNumber,
@@ -112,10 +238,41 @@ func convertCoreWorktreeToUI(wt core.WorktreeInfo) Worktree {
 		PRURL:          wt.PRURL,
 		CIStatus:       wt.CIStatus,
 		CIConclusion:   wt.CIConclusion,
+		CIURL:          wt.CIURL,
 		Marker:         string(wt.Marker),
 	}
 }
 
+// convertUIWorktreeToCore converts a dashboard row back to the core type,
+// with the git-derived fields core functions read (not PR, CI or marker
+// data).
+func convertUIWorktreeToCore(wt Worktree) core.WorktreeInfo {
+	return core.WorktreeInfo{
+		Name:           wt.Name,
+		Path:           wt.Path,
+		Branch:         wt.Branch,
+		Status:         wt.Status,
+		StatusError:    wt.StatusError,
+		IsCurrent:      wt.IsCurrent,
+		IsMain:         wt.IsMain,
+		IsBare:         wt.IsBare,
+		LastCommit:     wt.LastCommit,
+		StagedCount:    wt.StagedCount,
+		ModifiedCount:  wt.ModifiedCount,
+		UntrackedCount: wt.UntrackedCount,
+		UnpushedCount:  wt.UnpushedCount,
+		HasSubmodules:  wt.HasSubmodules,
+		SubmoduleState: wt.SubmoduleState,
+		Operation:      wt.Operation,
+		BranchMismatch: wt.BranchMismatch,
+		BrokenLink:     wt.BrokenLink,
+		NoRemote:       wt.NoRemote,
+		Created:        wt.Created,
+		BranchStatus:   wt.BranchStatus,
+		StaleReason:    wt.StaleReason,
+	}
+}
+
 // setupCreateState initializes create state from message
 func (m *Model) setupCreateState(msg createInitMsg) {
 	m.createState = &CreateState{
@@ -133,6 +290,11 @@ func (m *Model) setupCreateState(msg createInitMsg) {
 		showWarning:       false,
 		warningAccepted:   false,
 		selectedAction:    0,
+		presets:           msg.presets,
+		presetNames:       msg.presetNames,
+	}
+	if len(msg.presetNames) > 0 {
+		m.createState.currentStep = CreateStepPreset
 	}
 
 	// Find the index of recommended base branch
@@ -147,6 +309,27 @@ func (m *Model) setupCreateState(msg createInitMsg) {
 	m.centerScrollOnSelectedBranch()
 }
 
+// choosePreset applies the preset picked in the create wizard: its base
+// branch becomes the suggested one in the base branch step.
+func (m *Model) choosePreset() {
+	m.createState.preset = ""
+	if i := m.createState.selectedPreset; i > 0 && i <= len(m.createState.presetNames) {
+		m.createState.preset = m.createState.presetNames[i-1]
+	}
+	base := m.createState.presets[m.createState.preset].BaseBranch
+	if base == "" {
+		return
+	}
+	for i, status := range m.createState.branchStatuses {
+		if status.Name == base {
+			m.createState.baseBranch = base
+			m.createState.selectedBranch = i
+			m.centerScrollOnSelectedBranch()
+			return
+		}
+	}
+}
+
 // filterBranches filters the branch list based on the search query (fzf-like)
 func (m *Model) filterBranches() {
 	if m.createState == nil {
@@ -251,12 +434,13 @@ func (m *Model) setupDeleteState() {
 	}
 }
 
-// setupDeleteStateForWorktree initializes delete state for specific worktree
-func (m *Model) setupDeleteStateForWorktree(worktree Worktree) {
+// setupDeleteStateForWorktree initializes delete state for specific
+// worktree, with the issues precheckDelete found as its warnings
+func (m *Model) setupDeleteStateForWorktree(worktree Worktree, issues []string) {
 	m.deleteState = &DeleteState{
 		currentStep:       DeleteStepConfirm,
 		selectedWorktrees: []int{}, // Will be handled differently for single worktree
-		warnings:          make([]string, 0),
+		warnings:          issues,
 		targetWorktree:    &worktree, // Store the specific worktree
 	}
 }
@@ -397,9 +581,9 @@ trap 'emit_event "${GREN_CURRENT_PHASE:-unknown}" error "trapped signal"; exit 1
 	if m.initState != nil && len(m.initState.detectedFiles) > 0 {
 		script.WriteString("GREN_CURRENT_PHASE=symlink\n")
 		script.WriteString("emit_event symlink start\n")
-		script.WriteString("# Symlink configuration files\n")
+		script.WriteString("# Symlink configuration files (skipped when missing, e.g. REPO_ROOT is a bare repo)\n")
 		for _, file := range m.initState.detectedFiles {
-			script.WriteString(fmt.Sprintf("ln -sf \"$REPO_ROOT/%s\" . 2>/dev/null || true\n", file.Path))
+			script.WriteString(fmt.Sprintf("[ -e \"$REPO_ROOT/%s\" ] && ln -sf \"$REPO_ROOT/%s\" . 2>/dev/null || true\n", file.Path, file.Path))
 		}
 		script.WriteString("emit_event symlink ok\n\n")
 	}
@@ -449,7 +633,7 @@ func (m Model) createAndOpenScript() tea.Cmd {
 
 		// Write config file
 		configPath := ".gren/config.json"
-		if err := os.WriteFile(configPath, []byte(configFile), 0644); err != nil {
+		if err := config.WriteFileAtomic(configPath, []byte(configFile), 0644); err != nil {
 			return scriptCreateCompleteMsg{err: err}
 		}
 
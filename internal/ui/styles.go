@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/langtind/gren/internal/output"
 )
 
 // ═══════════════════════════════════════════════════════════════════════════
@@ -17,10 +18,10 @@ var (
 	ColorSecondary = lipgloss.AdaptiveColor{Light: "#6366f1", Dark: "#818cf8"} // Indigo - accent color
 	ColorAccent    = lipgloss.AdaptiveColor{Light: "#f59e0b", Dark: "#fbbf24"} // Amber - highlights
 
-	// Semantic colors
-	ColorSuccess = lipgloss.AdaptiveColor{Light: "#16a34a", Dark: "#4ade80"} // Green - positive status
-	ColorWarning = lipgloss.AdaptiveColor{Light: "#ea580c", Dark: "#fb923c"} // Orange - warnings
-	ColorError   = lipgloss.AdaptiveColor{Light: "#dc2626", Dark: "#f87171"} // Red - errors
+	// Semantic colors, shared with the CLI (see output.StatusColor)
+	ColorSuccess = output.StatusColorSuccess // Green - positive status
+	ColorWarning = output.StatusColorWarning // Orange - warnings
+	ColorError   = output.StatusColorError   // Red - errors
 
 	// Text hierarchy (light to dark on dark theme)
 	ColorTextPrimary   = lipgloss.AdaptiveColor{Light: "#1f2937", Dark: "#f9fafb"} // Brightest - names, important
@@ -165,16 +166,16 @@ var (
 
 var (
 	StatusCleanStyle = lipgloss.NewStyle().
-				Foreground(ColorSuccess)
+				Foreground(output.StatusColor("clean"))
 
 	StatusModifiedStyle = lipgloss.NewStyle().
-				Foreground(ColorWarning)
+				Foreground(output.StatusColor("modified"))
 
 	StatusUnpushedStyle = lipgloss.NewStyle().
-				Foreground(ColorSecondary)
+				Foreground(output.StatusColor("unpushed"))
 
 	StatusMissingStyle = lipgloss.NewStyle().
-				Foreground(ColorError)
+				Foreground(output.StatusColor("missing"))
 
 	StatusBuildingStyle = lipgloss.NewStyle().
 				Foreground(ColorAccent)
@@ -384,6 +385,8 @@ func StatusBadge(status string) string {
 		return StatusUnpushedStyle.Render("↑")
 	case "missing":
 		return StatusMissingStyle.Render("✗")
+	case "unknown":
+		return StatusModifiedStyle.Render("⚠")
 	default:
 		return StatusCleanStyle.Render("✓")
 	}
@@ -399,21 +402,15 @@ func StatusBadgeDetailed(status, branchStatus string, staged, modified, untracke
 	cleanStyle := StatusCleanStyle
 	modifiedStyle := StatusModifiedStyle
 	unpushedStyle := StatusUnpushedStyle
-	staleStyle := lipgloss.NewStyle().Foreground(ColorTextMuted)
-
-	// PR state colors
-	prOpenStyle := lipgloss.NewStyle().Foreground(ColorSuccess)
-	prMergedStyle := lipgloss.NewStyle().Foreground(ColorSecondary) // Indigo/purple for merged
-	prClosedStyle := lipgloss.NewStyle().Foreground(ColorError)
+	staleStyle := lipgloss.NewStyle().Foreground(output.StatusColor("stale"))
+	prStyle := lipgloss.NewStyle().Foreground(output.PRStateColor(prState))
 
 	if bgColor.Dark != "" || bgColor.Light != "" {
 		cleanStyle = cleanStyle.Background(bgColor)
 		modifiedStyle = modifiedStyle.Background(bgColor)
 		unpushedStyle = unpushedStyle.Background(bgColor)
 		staleStyle = staleStyle.Background(bgColor)
-		prOpenStyle = prOpenStyle.Background(bgColor)
-		prMergedStyle = prMergedStyle.Background(bgColor)
-		prClosedStyle = prClosedStyle.Background(bgColor)
+		prStyle = prStyle.Background(bgColor)
 	}
 
 	// Stale branch indicator (merged or remote gone)
@@ -421,6 +418,16 @@ func StatusBadgeDetailed(status, branchStatus string, staged, modified, untracke
 		parts = append(parts, staleStyle.Render("💤"))
 	}
 
+	// Status unknown (git status failed) - the counts are all zero, but the
+	// worktree must not look clean
+	if status == "unknown" {
+		prefix := ""
+		if len(parts) > 0 {
+			prefix = " "
+		}
+		parts = append(parts, modifiedStyle.Render(prefix+"⚠"))
+	}
+
 	// Git status indicators (always show, even for stale branches)
 	// Staged files (ready to commit) - green with +
 	if staged > 0 {
@@ -462,18 +469,7 @@ func StatusBadgeDetailed(status, branchStatus string, staged, modified, untracke
 
 	// Add PR badge if PR exists
 	if prNumber > 0 {
-		prefix := " "
-		prBadge := fmt.Sprintf("#%d", prNumber)
-		switch prState {
-		case "OPEN":
-			parts = append(parts, prOpenStyle.Render(prefix+prBadge))
-		case "MERGED":
-			parts = append(parts, prMergedStyle.Render(prefix+prBadge))
-		case "CLOSED":
-			parts = append(parts, prClosedStyle.Render(prefix+prBadge))
-		default:
-			parts = append(parts, prOpenStyle.Render(prefix+prBadge))
-		}
+		parts = append(parts, prStyle.Render(fmt.Sprintf(" #%d", prNumber)))
 	}
 
 	// Join without separator - spacing is included in each part
@@ -485,22 +481,12 @@ func CIStatusBadge(ciStatus string, bgColor lipgloss.AdaptiveColor) string {
 		return ""
 	}
 
-	var style lipgloss.Style
-	var symbol string
-
+	style := lipgloss.NewStyle().Foreground(output.CIStatusColor(ciStatus))
+	symbol := "●"
 	switch ciStatus {
-	case "success":
-		symbol = "●"
-		style = lipgloss.NewStyle().Foreground(ColorSuccess)
-	case "failure":
-		symbol = "●"
-		style = lipgloss.NewStyle().Foreground(ColorError)
-	case "pending":
-		symbol = "●"
-		style = lipgloss.NewStyle().Foreground(ColorWarning)
+	case "success", "failure", "pending":
 	default:
 		symbol = "○"
-		style = lipgloss.NewStyle().Foreground(ColorTextMuted)
 	}
 
 	if bgColor.Dark != "" || bgColor.Light != "" {
@@ -510,6 +496,26 @@ func CIStatusBadge(ciStatus string, bgColor lipgloss.AdaptiveColor) string {
 	return style.Render(symbol)
 }
 
+// ciStatusLabel describes a worktree's CI state for the preview panel,
+// preferring the summary of the checks when there is one.
+func ciStatusLabel(wt *Worktree) string {
+	if wt.CIConclusion != "" {
+		return wt.CIConclusion
+	}
+	switch wt.CIStatus {
+	case "success":
+		return "Checks passing"
+	case "failure":
+		return "Checks failing"
+	case "pending":
+		return "Checks running"
+	case "none":
+		return "No CI"
+	default:
+		return "CI status unknown"
+	}
+}
+
 // ═══════════════════════════════════════════════════════════════════════════
 // Wizard / View Styles
 // ═══════════════════════════════════════════════════════════════════════════
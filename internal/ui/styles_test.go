@@ -132,8 +132,13 @@ func TestStatusBadge(t *testing.T) {
 			want:   "✗",
 		},
 		{
-			name:   "unknown status defaults to clean",
+			name:   "unknown status warns",
 			status: "unknown",
+			want:   "⚠",
+		},
+		{
+			name:   "unrecognized status defaults to clean",
+			status: "building",
 			want:   "✓",
 		},
 	}
@@ -9,6 +9,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/langtind/gren/internal/logging"
+	"github.com/langtind/gren/internal/output"
 )
 
 // ToolAction represents an action in the Tools menu
@@ -63,9 +64,9 @@ func (m Model) renderToolsMenu() string {
 
 	hasPR := false
 	hasSelectedWorktree := false
-	if m.selected >= 0 && m.selected < len(m.worktrees) {
-		hasPR = m.worktrees[m.selected].PRNumber > 0
-		hasSelectedWorktree = !m.worktrees[m.selected].IsCurrent && !m.worktrees[m.selected].IsMain
+	if wt := m.getSelectedWorktree(); wt != nil {
+		hasPR = wt.PRNumber > 0
+		hasSelectedWorktree = !wt.IsCurrent && !wt.IsMain
 	}
 
 	actions := getToolActions(hasPR, hasSelectedWorktree)
@@ -157,10 +158,12 @@ func (m Model) handleToolsKeys(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
 		// Do NOT pre-select:
 		// - Worktrees with uncommitted changes (regardless of stale reason)
 		// - Worktrees with "no_unique_commits" (could be new branch user just started)
+		// - Worktrees with a rebase/merge in progress
+		// - Worktrees whose status git couldn't read (changes unknown)
 		selectedIndices := make(map[int]bool)
 		for i, wt := range staleWorktrees {
 			hasUncommittedChanges := wt.ModifiedCount > 0 || wt.StagedCount > 0 || wt.UntrackedCount > 0
-			isSafeToDelete := wt.StaleReason == "pr_merged" && !hasUncommittedChanges
+			isSafeToDelete := wt.StaleReason == "pr_merged" && !hasUncommittedChanges && wt.Operation == "" && wt.StatusError == ""
 
 			if isSafeToDelete {
 				selectedIndices[i] = true
@@ -181,7 +184,7 @@ func (m Model) handleToolsKeys(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
 			cleanupSpinner:  s,
 		}
 		m.currentView = CleanupView
-		return m, nil
+		return m, m.measureCleanupSizes(staleWorktrees)
 
 	case "x":
 		// Prune missing worktrees
@@ -190,8 +193,7 @@ func (m Model) handleToolsKeys(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, m.pruneWorktrees()
 
 	case "p":
-		if m.selected >= 0 && m.selected < len(m.worktrees) {
-			wt := m.worktrees[m.selected]
+		if wt := m.getSelectedWorktree(); wt != nil {
 			if wt.PRNumber > 0 {
 				logging.Info("Tools menu: opening PR #%d for %s", wt.PRNumber, wt.Branch)
 				m.currentView = DashboardView
@@ -224,8 +226,8 @@ func (m Model) handleToolsKeys(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
 		return m, nil
 
 	case "M":
-		if m.selected >= 0 && m.selected < len(m.worktrees) {
-			wt := m.worktrees[m.selected]
+		if selected := m.getSelectedWorktree(); selected != nil {
+			wt := *selected
 			if !wt.IsCurrent && !wt.IsMain {
 				logging.Info("Tools menu: opening merge for %s", wt.Branch)
 				m.mergeState = &MergeState{
@@ -247,6 +249,9 @@ func (m Model) handleToolsKeys(keyMsg tea.KeyMsg) (Model, tea.Cmd) {
 }
 
 func (m Model) getDefaultBranch() string {
+	if m.config != nil && m.config.DefaultBranch != "" {
+		return m.config.DefaultBranch
+	}
 	for _, wt := range m.worktrees {
 		if wt.Branch == "main" || wt.Branch == "master" {
 			return wt.Branch
@@ -304,6 +309,8 @@ func (m Model) renderCleanupConfirmationInitial() string {
 	totalCount := len(m.cleanupState.staleWorktrees)
 	descStyle := lipgloss.NewStyle().Foreground(ColorTextSecondary)
 	b.WriteString(descStyle.Render(fmt.Sprintf("Select worktrees to delete (%d/%d selected):", selectedCount, totalCount)))
+	b.WriteString("\n")
+	b.WriteString(lipgloss.NewStyle().Foreground(ColorTextMuted).Render(m.cleanupState.freedSummary()))
 	b.WriteString("\n\n")
 
 	// Force delete option (cursor index -1)
@@ -365,7 +372,11 @@ func (m Model) renderCleanupConfirmationInitial() string {
 		}
 		// Add uncommitted changes indicator
 		hasUncommittedChanges := wt.ModifiedCount > 0 || wt.StagedCount > 0 || wt.UntrackedCount > 0
-		if hasUncommittedChanges {
+		if wt.Operation != "" {
+			reason += " ⚠ " + wt.Operation + " in progress, requires force"
+		} else if wt.StatusError != "" {
+			reason += " ⚠ status unknown, requires force"
+		} else if hasUncommittedChanges {
 			reason += " ⚠ requires force"
 		}
 
@@ -376,6 +387,10 @@ func (m Model) renderCleanupConfirmationInitial() string {
 		b.WriteString(lineStyle.Render(wt.Branch))
 		b.WriteString(" ")
 		b.WriteString(lipgloss.NewStyle().Foreground(ColorTextMuted).Render("[" + reason + "]"))
+		if size, ok := m.cleanupState.sizes[wt.Path]; ok {
+			b.WriteString(" ")
+			b.WriteString(lipgloss.NewStyle().Foreground(ColorTextMuted).Render(output.FormatSize(size)))
+		}
 		b.WriteString("\n")
 	}
 
@@ -411,6 +426,19 @@ func (m Model) renderCleanupConfirmationInitial() string {
 	return b.String()
 }
 
+// freedSummary describes the disk space the selected worktrees take up, or
+// that it is still being measured.
+func (cs *CleanupState) freedSummary() string {
+	if cs.sizes == nil {
+		return "Calculating disk usage…"
+	}
+	var total int64
+	for i := range cs.selectedIndices {
+		total += cs.sizes[cs.staleWorktrees[i].Path]
+	}
+	return fmt.Sprintf("Frees ~%s across %d worktree(s)", output.FormatSize(total), len(cs.selectedIndices))
+}
+
 // renderCleanupProgress renders live progress during cleanup
 func (m Model) renderCleanupProgress() string {
 	if m.cleanupState == nil {
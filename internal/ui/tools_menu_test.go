@@ -5,6 +5,7 @@ import (
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/langtind/gren/internal/core"
 )
 
 func TestRenderToolsMenu(t *testing.T) {
@@ -182,3 +183,73 @@ func TestGetToolActions(t *testing.T) {
 		}
 	})
 }
+
+func TestStepCommitFilePicker(t *testing.T) {
+	m := Model{
+		currentView:     StepCommitView,
+		keys:            DefaultKeyMap(),
+		stepCommitState: &StepCommitState{currentStep: StepCommitStepOptions},
+	}
+
+	press := func(k tea.KeyMsg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.handleStepCommitKeys(k)
+		m = updated.(Model)
+		return cmd
+	}
+	runes := func(s string) tea.KeyMsg { return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)} }
+
+	if cmd := press(runes("f")); cmd == nil || m.stepCommitState.currentStep != StepCommitStepFiles {
+		t.Fatal("f should open the file picker and load the changed files")
+	}
+	updated, _ := m.Update(changedFilesLoadedMsg{files: []core.ChangedFile{
+		{Path: "a.go", Status: " M"}, {Path: "b.go", Status: "??"}, {Path: "c.go", Status: "A "},
+	}})
+	m = updated.(Model)
+
+	press(tea.KeyMsg{Type: tea.KeyDown})
+	press(runes(" "))
+	if got := m.stepCommitState.selectedPaths(); len(got) != 1 || got[0] != "b.go" {
+		t.Errorf("selectedPaths() = %v, want [b.go]", got)
+	}
+	if !strings.Contains(m.renderStepCommitView(), "[✓] ?? b.go") {
+		t.Errorf("picker should show b.go as picked:\n%s", m.renderStepCommitView())
+	}
+
+	press(runes("a"))
+	if got := m.stepCommitState.selectedPaths(); len(got) != 3 {
+		t.Errorf("a should pick every file, got %v", got)
+	}
+	press(runes("a"))
+	if got := m.stepCommitState.selectedPaths(); got != nil {
+		t.Errorf("a with everything picked should clear the picks, got %v", got)
+	}
+
+	press(runes(" "))
+	press(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.stepCommitState.currentStep != StepCommitStepOptions {
+		t.Error("enter should return to the options")
+	}
+	if view := m.renderStepCommitView(); !strings.Contains(view, "1 of 3 changed files") {
+		t.Errorf("options should say how many files will be committed:\n%s", view)
+	}
+}
+
+func TestStepCommitFilePickerNoChanges(t *testing.T) {
+	m := Model{
+		currentView:     StepCommitView,
+		keys:            DefaultKeyMap(),
+		stepCommitState: &StepCommitState{currentStep: StepCommitStepFiles},
+	}
+	if view := m.renderStepCommitView(); !strings.Contains(view, "Loading changed files") {
+		t.Errorf("picker should show loading before the files arrive:\n%s", view)
+	}
+
+	// A clean worktree has no changed files, which comes back as nil
+	updated, _ := m.Update(changedFilesLoadedMsg{})
+	m = updated.(Model)
+	view := m.renderStepCommitView()
+	if strings.Contains(view, "Loading") || !strings.Contains(view, "No changes to commit") {
+		t.Errorf("picker should show the no-changes state once loaded:\n%s", view)
+	}
+}
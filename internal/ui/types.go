@@ -2,11 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/langtind/gren/internal/config"
+	"github.com/langtind/gren/internal/core"
 	"github.com/langtind/gren/internal/git"
 )
 
@@ -35,19 +38,30 @@ type Worktree struct {
 	Path           string
 	Branch         string
 	Status         string // "clean", "modified", "building", etc.
+	StatusError    string // why git status failed when Status is "unknown"; the file counts are then meaningless
 	IsCurrent      bool   // true if this is the current worktree
 	IsPrevious     bool   // true if this was the most recently active worktree (`gren switch -` target)
 	IsMain         bool   // true if this is the main worktree (where .git directory lives)
+	IsBare         bool   // true for the repository itself in a bare clone (never current or deletable)
 	LastCommit     string // Relative time of last commit (e.g., "2h ago")
 	StagedCount    int    // Number of staged files (ready to commit)
 	ModifiedCount  int    // Number of modified files (not staged)
 	UntrackedCount int    // Number of untracked files
 	UnpushedCount  int    // Number of unpushed commits
 	HasSubmodules  bool   // true if worktree has submodules (requires --force to delete)
+	SubmoduleState string // submodules out of step with the commit, e.g. "1 modified"; "" when all match
+	Operation      string // git operation stopped halfway ("rebase", "merge", ...); blocks deletion
+	BranchMismatch bool   // directory named for another branch than the one checked out
+	BrokenLink     bool   // worktree and repository no longer point at each other (gren repair)
+	NoRemote       bool   // the repository has no remote, so there is no push state to show
+
+	// When the worktree was created (its directory's mtime if gren didn't
+	// create it); zero for the main worktree
+	Created time.Time
 
 	// Stale detection fields
 	BranchStatus string // "active", "stale", or "" if not yet checked
-	StaleReason  string // "merged_locally", "no_unique_commits", "remote_gone", "pr_merged", "pr_closed"
+	StaleReason  string // "merged_locally", "no_unique_commits", "remote_gone", "pr_merged", "pr_closed", "branch_deleted"
 
 	// GitHub PR fields (populated async, empty if gh unavailable or no PR)
 	PRNumber int    // PR number, 0 if no PR
@@ -55,10 +69,15 @@ type Worktree struct {
 	PRURL    string // Full URL to PR for "Open in browser"
 
 	// CI status fields
-	CIStatus     string // "success", "failure", "pending", "" if unknown
+	CIStatus     string // "success", "failure", "pending", "none" (no CI), "" if unknown
 	CIConclusion string
+	CIURL        string
 
 	Marker string
+
+	// Loading is true while Status and the file counts are still being
+	// loaded in the background (see worktreeStatusMsg)
+	Loading bool
 }
 
 // InitStep represents the current step in initialization
@@ -101,6 +120,7 @@ type InitState struct {
 	postCreateCmd      string // detected post-create command
 	aiGeneratedScript  string // AI-generated setup script content
 	aiError            string // Error message from AI generation
+	aiWarning          string // Set when the generated script failed validation
 	trackGrenInGit     bool   // whether to track .gren/ in git or add to .gitignore
 	recommendationMode int    // RecommendAccept, RecommendCustomize, or RecommendAI
 	claudeAvailable    bool   // whether Claude Code CLI is installed
@@ -158,6 +178,7 @@ const (
 	CreateStepConfirm
 	CreateStepCreating
 	CreateStepComplete
+	CreateStepPreset // Before CreateStepBranchMode, when presets are configured
 )
 
 // BranchStatus represents the status of a git branch (copied from git package to avoid circular imports).
@@ -194,6 +215,11 @@ type CreateState struct {
 	actionsList               list.Model    // Dropdown menu for post-create actions
 	spinner                   spinner.Model // Spinner for creating step
 	createWarning             string        // Warning from worktree creation (e.g., unpushed commits)
+	worktreePath              string        // Where the worktree goes, resolved by core; the created path once done
+	presets                   map[string]config.Preset
+	presetNames               []string // Sorted names of presets, offered after "No preset"
+	selectedPreset            int      // 0 = no preset, i = presetNames[i-1]
+	preset                    string   // The chosen preset, "" for none
 }
 
 // DeleteStep represents the current step in worktree deletion
@@ -230,19 +256,31 @@ type ConfigState struct {
 
 // CompareState holds the state for the compare view
 type CompareState struct {
-	sourceWorktree   string            // Name of the source worktree being compared
-	sourcePath       string            // Path to source worktree
-	files            []CompareFileItem // List of files with selection state
-	selectedIndex    int               // Currently selected file index
-	scrollOffset     int               // For scrolling long file lists
-	selectAll        bool              // Whether all files are selected
-	applyInProgress  bool              // Whether apply operation is running
-	applyComplete    bool              // Whether apply operation completed
-	applyError       string            // Error message from apply operation
-	appliedCount     int               // Number of files successfully applied
-	diffContent      string            // Diff content for currently selected file
-	diffScrollOffset int               // Scroll offset for diff viewer
-	diffFocused      bool              // Whether diff panel is focused (for scrolling)
+	sourceWorktree  string                   // Name of the source worktree being compared
+	sourcePath      string                   // Path to source worktree
+	targetPath      string                   // Path to target worktree (the current one)
+	files           []CompareFileItem        // List of files with selection state
+	selectedIndex   int                      // Currently selected file index
+	scrollOffset    int                      // For scrolling long file lists
+	selectAll       bool                     // Whether all files are selected
+	applyInProgress bool                     // Whether apply operation is running
+	applyComplete   bool                     // Whether apply operation completed
+	applyError      string                   // Error message from apply operation
+	appliedCount    int                      // Number of files successfully applied
+	diffContent     string                   // Diff content for currently selected file
+	diffViewport    viewport.Model           // Scrollable diff panel
+	diffFocused     bool                     // Whether diff panel is focused (for scrolling)
+	diffPath        string                   // File the loaded diff belongs to
+	fileHunks       map[string][]compareHunk // Hunks per file path, loaded with its diff
+	hunkIndex       int                      // Current hunk in the diff panel
+}
+
+// compareHunk is a hunk in the compare view with its selection state. A
+// selected file whose hunks are all selected is applied whole; otherwise only
+// its selected hunks are applied.
+type compareHunk struct {
+	hunk     core.Hunk
+	selected bool
 }
 
 // CompareFileItem represents a file in the compare view with selection state
@@ -297,6 +335,7 @@ const (
 	StepCommitStepMessage
 	StepCommitStepInProgress
 	StepCommitStepComplete
+	StepCommitStepFiles // Picking which changed files to commit
 )
 
 type StepCommitState struct {
@@ -305,6 +344,24 @@ type StepCommitState struct {
 	message     string
 	result      string
 	err         error
+
+	// Changed files to pick from, loaded on entering StepCommitStepFiles.
+	// With none of picked set, every change is committed.
+	files       []core.ChangedFile
+	filesLoaded bool
+	picked      []bool
+	fileCursor  int
+}
+
+// selectedPaths returns the picked files, or nil to commit everything.
+func (s *StepCommitState) selectedPaths() []string {
+	var paths []string
+	for i, picked := range s.picked {
+		if picked {
+			paths = append(paths, s.files[i].Path)
+		}
+	}
+	return paths
 }
 
 // DeleteState holds the state for worktree deletion
@@ -314,23 +371,25 @@ type DeleteState struct {
 	warnings          []string
 	targetWorktree    *Worktree // Specific worktree to delete (for single deletion)
 	forceDelete       bool      // Use --force flag (when user confirms deletion of dirty worktree)
+	danglingRemoved   int       // Dangling symlinks removed during the delete
 }
 
 // CleanupState holds the state for bulk stale worktree cleanup with live progress
 type CleanupState struct {
-	staleWorktrees      []Worktree     // Worktrees to be cleaned up
-	confirmed           bool           // Whether user confirmed the action
-	selectedIndices     map[int]bool   // Which worktrees are selected for deletion
-	selectedIndicesList []int          // Sorted list of selected indices (built when cleanup starts)
-	cursorIndex         int            // Current cursor position in selection list
-	forceDelete         bool           // Force delete even with uncommitted changes
-	inProgress          bool           // Cleanup currently running
-	currentIndex        int            // Index being deleted (-1 = none)
-	deletedIndices      map[int]bool   // Successfully deleted indices
-	failedWorktrees     map[int]string // Failed index → error message
-	totalCleaned        int            // Success count
-	totalFailed         int            // Failure count
-	cleanupSpinner      spinner.Model  // Spinner for current deletion
+	staleWorktrees      []Worktree       // Worktrees to be cleaned up
+	confirmed           bool             // Whether user confirmed the action
+	selectedIndices     map[int]bool     // Which worktrees are selected for deletion
+	selectedIndicesList []int            // Sorted list of selected indices (built when cleanup starts)
+	cursorIndex         int              // Current cursor position in selection list
+	forceDelete         bool             // Force delete even with uncommitted changes
+	inProgress          bool             // Cleanup currently running
+	currentIndex        int              // Index being deleted (-1 = none)
+	deletedIndices      map[int]bool     // Successfully deleted indices
+	failedWorktrees     map[int]string   // Failed index → error message
+	totalCleaned        int              // Success count
+	totalFailed         int              // Failure count
+	cleanupSpinner      spinner.Model    // Spinner for current deletion
+	sizes               map[string]int64 // Worktree path → disk usage; nil until measured
 }
 
 // Model holds the entire application state
@@ -372,10 +431,49 @@ type Model struct {
 	// Help overlay
 	helpVisible bool
 
-	// GitHub loading state
+	// GitHub loading state. The spinner also animates while disk usage is
+	// being measured.
 	githubLoading bool
 	githubSpinner spinner.Model
 
+	// Disk usage per worktree path for the preview panel, measured in the
+	// background when a worktree is first selected and kept until the
+	// worktree list is reloaded or a worktree is created at that path
+	diskUsage        map[string]int64
+	diskUsageLoading map[string]bool
+
+	// Uncommitted changes per worktree path for the preview panel, loaded
+	// when a dirty worktree is selected and dropped when its status reloads
+	diffStats       map[string][]core.FileStat
+	diffStatLoading map[string]bool
+
+	// The dashboard first shows the worktree list without status, then fills
+	// each row in as worktreeStatusMsg and worktreeStaleMsg arrive. Results
+	// tagged with an older loadGeneration belong to a superseded load and are
+	// dropped.
+	loadGeneration int
+	staleLoading   bool
+
+	// Claude activity markers are polled in the background so the dashboard
+	// follows sessions as they start, wait and finish; markerPolling keeps
+	// reloads of the worktree list from starting a second poll.
+	// markerManager, with the marker_ttl from the config, is built by the
+	// first poll and reused, so a poll only reads the markers.
+	markerPolling bool
+	markerManager *core.MarkerManager
+
+	// Dashboard filter. filtering is true while the "/" input has focus;
+	// filterQuery narrows getSortedWorktrees to branches and paths that
+	// contain it, and stays applied after enter until esc clears it.
+	filtering   bool
+	filterQuery string
+
+	// Dashboard order. sortMode is cycled with "s" (empty means recent);
+	// the current worktree is pinned to the top unless unpinCurrent is set
+	// with "S".
+	sortMode     core.SortMode
+	unpinCurrent bool
+
 	// Delete operation spinner
 	deleteSpinner spinner.Model
 
@@ -407,6 +505,9 @@ type KeyMap struct {
 	Help     key.Binding
 	Tools    key.Binding
 	Compare  key.Binding
+	Filter   key.Binding
+	Sort     key.Binding
+	PinSort  key.Binding
 }
 
 // HelpState holds the state for the help overlay
@@ -424,6 +525,7 @@ type HookApprovalState struct {
 	baseBranch     string   // Base branch
 	selectedIndex  int      // 0=Approve, 1=Skip
 	hasInteractive bool     // True if any hook is interactive (needs terminal)
+	preset         string   // Preset whose post-create hook also runs
 }
 
 // DefaultKeyMap returns default key bindings
@@ -493,5 +595,17 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("m"),
 			key.WithHelp("m", "compare/merge"),
 		),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter worktrees"),
+		),
+		Sort: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "cycle sort order"),
+		),
+		PinSort: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "pin current worktree"),
+		),
 	}
 }
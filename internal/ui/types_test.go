@@ -5,6 +5,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/langtind/gren/internal/core"
 )
 
 func TestDefaultKeyMap(t *testing.T) {
@@ -152,6 +153,7 @@ func TestCreateStepConstants(t *testing.T) {
 		CreateStepConfirm,
 		CreateStepCreating,
 		CreateStepComplete,
+		CreateStepPreset,
 	}
 
 	seen := make(map[CreateStep]bool)
@@ -276,3 +278,37 @@ func TestBranchStatusStruct(t *testing.T) {
 		t.Errorf("AheadCount = %d, want 3", bs.AheadCount)
 	}
 }
+
+func TestCompareStateHunkSelection(t *testing.T) {
+	h1 := core.Hunk{Path: "a.go", Header: "@@ -1 +1 @@"}
+	h2 := core.Hunk{Path: "a.go", Header: "@@ -9 +9 @@"}
+	state := &CompareState{
+		files:     []CompareFileItem{{Path: "a.go", Status: "modified", Selected: true}},
+		diffPath:  "a.go",
+		fileHunks: map[string][]compareHunk{"a.go": {{hunk: h1, selected: true}, {hunk: h2, selected: true}}},
+	}
+
+	if got := state.selectedHunks("a.go"); got != nil {
+		t.Errorf("selectedHunks() with all selected = %v, want nil (apply whole file)", got)
+	}
+
+	state.toggleHunk() // deselect hunk 0
+	got := state.selectedHunks("a.go")
+	if len(got) != 1 || got[0].Header != h2.Header {
+		t.Errorf("selectedHunks() after deselecting first = %v, want only second hunk", got)
+	}
+	if !state.files[0].Selected {
+		t.Error("file should stay selected while a hunk is selected")
+	}
+
+	state.hunkIndex = 1
+	state.toggleHunk() // deselect hunk 1
+	if state.files[0].Selected {
+		t.Error("file should be deselected once no hunks are selected")
+	}
+
+	state.setAllHunks("a.go", true)
+	if got := state.selectedHunks("a.go"); got != nil {
+		t.Errorf("selectedHunks() after setAllHunks(true) = %v, want nil", got)
+	}
+}
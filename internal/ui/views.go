@@ -1,10 +1,12 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
+	"github.com/langtind/gren/internal/core"
 )
 
 func (m Model) renderMergeView() string {
@@ -67,7 +69,12 @@ func (m Model) renderMergeView() string {
 		}
 
 	case MergeStepComplete:
-		if m.mergeState.err != nil {
+		var conflict *core.MergeConflictError
+		if errors.As(m.mergeState.err, &conflict) {
+			b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorError).Render("✗ Merge Stopped on Conflicts"))
+			b.WriteString("\n\n")
+			b.WriteString(m.mergeState.err.Error())
+		} else if m.mergeState.err != nil {
 			b.WriteString(lipgloss.NewStyle().Bold(true).Foreground(ColorError).Render("✗ Merge Failed"))
 			b.WriteString("\n\n")
 			b.WriteString(m.mergeState.err.Error())
@@ -171,7 +178,11 @@ func (m Model) renderStepCommitView() string {
 	case StepCommitStepOptions:
 		b.WriteString(titleStyle.Render("Commit Changes"))
 		b.WriteString("\n\n")
-		b.WriteString(labelStyle.Render("Stage all changes and create a commit."))
+		if paths := m.stepCommitState.selectedPaths(); len(paths) > 0 {
+			b.WriteString(labelStyle.Render(fmt.Sprintf("Stage and commit %d of %d changed files.", len(paths), len(m.stepCommitState.files))))
+		} else {
+			b.WriteString(labelStyle.Render("Stage all changes and create a commit."))
+		}
 		b.WriteString("\n\n")
 
 		llmCheck := "[ ]"
@@ -179,10 +190,37 @@ func (m Model) renderStepCommitView() string {
 			llmCheck = "[✓]"
 		}
 		b.WriteString(fmt.Sprintf("  %s Generate message with AI (tab to toggle)\n", llmCheck))
+		b.WriteString("      Choose files to commit (f)\n")
 
 		b.WriteString("\n")
 		b.WriteString(mutedStyle.Render("Press enter to continue • esc to cancel"))
 
+	case StepCommitStepFiles:
+		b.WriteString(titleStyle.Render("Choose Files"))
+		b.WriteString("\n\n")
+		switch {
+		case !m.stepCommitState.filesLoaded:
+			b.WriteString("Loading changed files...")
+		case len(m.stepCommitState.files) == 0:
+			b.WriteString(labelStyle.Render("No changes to commit."))
+		default:
+			for i, f := range m.stepCommitState.files {
+				check := "[ ]"
+				if m.stepCommitState.picked[i] {
+					check = "[✓]"
+				}
+				line := fmt.Sprintf("%s %s %s", check, f.Status, f.Path)
+				if i == m.stepCommitState.fileCursor {
+					b.WriteString(inputStyle.Render("› " + line))
+				} else {
+					b.WriteString("  " + line)
+				}
+				b.WriteString("\n")
+			}
+		}
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("space to pick • a to pick all • enter when done • none picked commits everything"))
+
 	case StepCommitStepGenerating:
 		b.WriteString(titleStyle.Render("Generating Commit Message..."))
 		b.WriteString("\n\n")
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"flag"
 	"fmt"
 	"os"
@@ -27,9 +28,34 @@ var (
 )
 
 func main() {
+	// Parse command line flags. Global flags go before the command
+	// (gren --verbose create ...); everything from the command on is left
+	// for the CLI to parse.
+	var showHelp = flag.Bool("help", false, "Show help message")
+	var showVersion = flag.Bool("version", false, "Show version information")
+	var quiet = flag.Bool("quiet", false, "Only write errors to the log")
+	var verbose = flag.Bool("verbose", false, "Write debug logs, and mirror them to stderr for CLI commands")
+	flag.BoolVar(verbose, "v", false, "Shorthand for --verbose")
+	var logFile = flag.String("log-file", "", "Write the log to this file instead of the default location")
+	flag.Parse()
+
+	if *quiet && *verbose {
+		fmt.Fprintln(os.Stderr, "Error: --quiet and --verbose are mutually exclusive")
+		os.Exit(2)
+	}
+	if *quiet {
+		logging.SetLevel(logging.LevelError)
+	}
+
 	// Initialize logging
-	if err := logging.Init(); err != nil {
-		fmt.Fprintf(os.Stderr, "Warning: failed to initialize logging: %v\n", err)
+	var logErr error
+	if *logFile != "" {
+		logErr = logging.InitPath(*logFile)
+	} else {
+		logErr = logging.Init()
+	}
+	if logErr != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to initialize logging: %v\n", logErr)
 	}
 	defer logging.Close()
 
@@ -59,11 +85,6 @@ func main() {
 	// Set up embedded skill files for install-skill command
 	cli.SetSkillFS(skillFS, "skills/gren", "gren")
 
-	// Parse command line flags
-	var showHelp = flag.Bool("help", false, "Show help message")
-	var showVersion = flag.Bool("version", false, "Show version information")
-	flag.Parse()
-
 	logging.Info("gren %s started, args: %v", version, os.Args)
 
 	if *showVersion {
@@ -81,24 +102,31 @@ func main() {
 	gitRepo := git.NewLocalRepository()
 	configManager := config.NewManager()
 
-	// Check if we have CLI commands (anything beyond flags)
-	args := os.Args
-	cliArgs := []string{}
-
-	// Filter out flag arguments to get command arguments
-	for i := 1; i < len(args); i++ {
-		arg := args[i]
-		if !(*showHelp) && !(*showVersion) && !strings.HasPrefix(arg, "-") {
-			cliArgs = append(cliArgs, args[i:]...)
-			break
-		}
+	// Check if we have CLI commands (anything after the global flags).
+	// flag.Args() rather than the first non-dash argument, so a flag value
+	// like --log-file's is never mistaken for the command.
+	var cliArgs []string
+	if !*showHelp {
+		cliArgs = flag.Args()
 	}
 
 	// If we have CLI commands, use CLI mode
 	if len(cliArgs) > 0 {
+		// Mirroring to stderr would draw over the TUI, so only CLI commands
+		// get it.
+		if *verbose {
+			logging.SetOutput(os.Stderr)
+		}
 		cliHandler := cli.NewCLI(gitRepo, configManager)
 		if err := cliHandler.ParseAndExecute(append([]string{"gren"}, cliArgs...)); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			// Some failures have already been reported, e.g. as JSON
+			if msg := err.Error(); msg != "" {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", msg)
+			}
+			var exitErr *cli.ExitCodeError
+			if errors.As(err, &exitErr) {
+				os.Exit(exitErr.Code)
+			}
 			os.Exit(1)
 		}
 		return